@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/cometbft/cometbft/crypto"
 	cometprivval "github.com/cometbft/cometbft/privval"
@@ -18,94 +21,153 @@ type AddressCmdOutput struct {
 	PubKey            string
 	ValConsAddress    string
 	ValConsPubAddress string
+	Base64Address     string
+	HexPubKey         string
+	Base64PubKey      string
+	Bech32Prefix      string `json:",omitempty"`
 }
 
 func addressCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:          "address chain-id [bech32]",
-		Short:        "Get public key hex address and valcons address",
-		Example:      `horcrux cosigner address cosmos`,
+		Use:   "address chain-id [bech32]",
+		Short: "Get the consensus public key and address of the validator",
+		Long: `Get the consensus public key and address of the validator of the chain from its key shard,
+or its key in single sign mode, in hex and base64, and with a base bech32 prefix as valcons
+address and valconspub public key, to verify them against the validator registered on-chain.
+The prefix is the bech32 argument, or the bech32Prefix of the chain in the config.`,
+		Example: `horcrux address cosmoshub-4 cosmos
+horcrux address osmosis-1 --output table`,
 		SilenceUsage: true,
 		Args:         cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-
-			var pubKey crypto.PubKey
-
-			chainID := args[0]
-
-			switch config.Config.SignMode {
-			case signer.SignModeThreshold:
-				err := config.Config.ValidateThresholdModeConfig()
-				if err != nil {
-					return err
-				}
-
-				keyFile, err := config.KeyFileExistsCosigner(chainID)
-				if err != nil {
-					return err
-				}
-
-				key, err := signer.LoadCosignerEd25519Key(keyFile)
-				if err != nil {
-					return fmt.Errorf("error reading cosigner key: %w, check that key is present for chain ID: %s", err, chainID)
-				}
-
-				pubKey = key.PubKey
-			case signer.SignModeSingle:
-				err := config.Config.ValidateSingleSignerConfig()
-				if err != nil {
-					return err
-				}
-				keyFile, err := config.KeyFileExistsSingleSigner(chainID)
-				if err != nil {
-					return fmt.Errorf("error reading priv-validator key: %w, check that key is present for chain ID: %s", err, chainID)
-				}
-
-				filePV := cometprivval.LoadFilePVEmptyState(keyFile, "")
-				pubKey = filePV.Key.PubKey
-			default:
-				panic(fmt.Errorf("unexpected sign mode: %s", config.Config.SignMode))
+			output, _ := cmd.Flags().GetString(flagOutput)
+			if output != "table" && output != "json" {
+				return fmt.Errorf("--%s must be table or json", flagOutput)
 			}
 
-			pubKeyAddress := pubKey.Address()
+			chainID := args[0]
 
-			pubKeyJSON, err := signer.PubKey("", pubKey)
+			pubKey, err := consensusPubKey(chainID)
 			if err != nil {
 				return err
 			}
 
-			output := AddressCmdOutput{
-				HexAddress: strings.ToUpper(hex.EncodeToString(pubKeyAddress)),
-				PubKey:     pubKeyJSON,
-			}
-
+			prefix := config.Config.Chains.Bech32Prefix(chainID)
 			if len(args) == 2 {
-				bech32ValConsAddress, err := bech32.ConvertAndEncode(args[1]+"valcons", pubKeyAddress)
-				if err != nil {
-					return err
-				}
-				output.ValConsAddress = bech32ValConsAddress
-				pubKeyBech32, err := signer.PubKey(args[1], pubKey)
-				if err != nil {
-					return err
-				}
-				output.ValConsPubAddress = pubKeyBech32
-			} else {
-				bech32Hint := "Pass bech32 base prefix as argument to generate (e.g. cosmos)"
-				output.ValConsAddress = bech32Hint
-				output.ValConsPubAddress = bech32Hint
+				prefix = args[1]
 			}
 
-			jsonOut, err := json.Marshal(output)
+			addr, err := newAddressCmdOutput(pubKey, prefix)
 			if err != nil {
 				return err
 			}
 
-			fmt.Println(string(jsonOut))
-
+			if output == "table" {
+				return printAddress(cmd.OutOrStdout(), addr)
+			}
+			jsonOut, err := json.Marshal(addr)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(jsonOut))
 			return nil
 		},
 	}
 
+	cmd.Flags().StringP(flagOutput, "o", "json", "output format, table or json")
+
 	return cmd
 }
+
+// consensusPubKey returns the consensus public key of the validator of the chain, from its key
+// shard, or its key in single sign mode.
+func consensusPubKey(chainID string) (crypto.PubKey, error) {
+	switch config.Config.SignMode {
+	case signer.SignModeThreshold:
+		err := config.Config.ValidateThresholdModeConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		keyFile, err := config.KeyFileExistsCosigner(chainID)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := signer.LoadCosignerEd25519Key(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading cosigner key: %w, check that key is present for chain ID: %s", err, chainID)
+		}
+
+		return key.PubKey, nil
+	case signer.SignModeSingle:
+		err := config.Config.ValidateSingleSignerConfig()
+		if err != nil {
+			return nil, err
+		}
+		keyFile, err := config.KeyFileExistsSingleSigner(chainID)
+		if err != nil {
+			return nil, fmt.Errorf("error reading priv-validator key: %w, check that key is present for chain ID: %s", err, chainID)
+		}
+
+		filePV := cometprivval.LoadFilePVEmptyState(keyFile, "")
+		return filePV.Key.PubKey, nil
+	default:
+		panic(fmt.Errorf("unexpected sign mode: %s", config.Config.SignMode))
+	}
+}
+
+// newAddressCmdOutput returns the consensus public key and address of the public key, in bech32 too if
+// the bech32 base prefix is set.
+func newAddressCmdOutput(pubKey crypto.PubKey, prefix string) (AddressCmdOutput, error) {
+	pubKeyAddress := pubKey.Address()
+
+	pubKeyJSON, err := signer.PubKey("", pubKey)
+	if err != nil {
+		return AddressCmdOutput{}, err
+	}
+
+	output := AddressCmdOutput{
+		HexAddress:    strings.ToUpper(hex.EncodeToString(pubKeyAddress)),
+		PubKey:        pubKeyJSON,
+		Base64Address: base64.StdEncoding.EncodeToString(pubKeyAddress),
+		HexPubKey:     strings.ToUpper(hex.EncodeToString(pubKey.Bytes())),
+		Base64PubKey:  base64.StdEncoding.EncodeToString(pubKey.Bytes()),
+		Bech32Prefix:  prefix,
+	}
+
+	if prefix != "" {
+		bech32ValConsAddress, err := bech32.ConvertAndEncode(prefix+"valcons", pubKeyAddress)
+		if err != nil {
+			return AddressCmdOutput{}, err
+		}
+		output.ValConsAddress = bech32ValConsAddress
+		pubKeyBech32, err := signer.PubKey(prefix, pubKey)
+		if err != nil {
+			return AddressCmdOutput{}, err
+		}
+		output.ValConsPubAddress = pubKeyBech32
+	} else {
+		bech32Hint := "Pass bech32 base prefix as argument or set bech32Prefix of the chain to generate (e.g. cosmos)"
+		output.ValConsAddress = bech32Hint
+		output.ValConsPubAddress = bech32Hint
+	}
+
+	return output, nil
+}
+
+// printAddress prints the consensus public key and address as a table.
+func printAddress(out io.Writer, addr AddressCmdOutput) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Address (hex):\t%s\n", addr.HexAddress)
+	fmt.Fprintf(w, "Address (base64):\t%s\n", addr.Base64Address)
+	fmt.Fprintf(w, "Public key (hex):\t%s\n", addr.HexPubKey)
+	fmt.Fprintf(w, "Public key (base64):\t%s\n", addr.Base64PubKey)
+	fmt.Fprintf(w, "Public key (JSON):\t%s\n", addr.PubKey)
+	if addr.Bech32Prefix != "" {
+		fmt.Fprintf(w, "Bech32 prefix:\t%s\n", addr.Bech32Prefix)
+		fmt.Fprintf(w, "Valcons address:\t%s\n", addr.ValConsAddress)
+		fmt.Fprintf(w, "Valconspub public key:\t%s\n", addr.ValConsPubAddress)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressCmdOutput(t *testing.T) {
+	pubKey := cometcryptoed25519.GenPrivKeyFromSecret([]byte("horcrux")).PubKey()
+
+	addr, err := newAddressCmdOutput(pubKey, "")
+	require.NoError(t, err)
+	require.Equal(t, pubKey.Address().String(), addr.HexAddress)
+	require.Equal(t, base64.StdEncoding.EncodeToString(pubKey.Address()), addr.Base64Address)
+	require.Equal(t, base64.StdEncoding.EncodeToString(pubKey.Bytes()), addr.Base64PubKey)
+	require.Len(t, addr.HexPubKey, 64)
+	require.Contains(t, addr.PubKey, addr.Base64PubKey)
+	require.Contains(t, addr.ValConsAddress, "Pass bech32 base prefix")
+
+	addr, err = newAddressCmdOutput(pubKey, "cosmos")
+	require.NoError(t, err)
+	require.Equal(t, "cosmos", addr.Bech32Prefix)
+	hrp, bz, err := bech32.DecodeAndConvert(addr.ValConsAddress)
+	require.NoError(t, err)
+	require.Equal(t, "cosmosvalcons", hrp)
+	require.Equal(t, []byte(pubKey.Address()), bz)
+	require.True(t, strings.HasPrefix(addr.ValConsPubAddress, "cosmosvalconspub1"))
+
+	var out bytes.Buffer
+	require.NoError(t, printAddress(&out, addr))
+	require.Contains(t, out.String(), "Address (hex):          "+addr.HexAddress+"\n")
+	require.Contains(t, out.String(), "Valcons address:        "+addr.ValConsAddress+"\n")
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/strangelove-ventures/horcrux/signer"
+)
+
+const (
+	flagKey      = "key"
+	flagOperator = "operator"
+	flagExpires  = "expires"
+)
+
+func approvalCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "approval",
+		Short: "Commands to approve the destructive operations of the admin API",
+	}
+
+	cmd.AddCommand(approvalKeygenCmd())
+	cmd.AddCommand(approvalSignCmd())
+
+	return cmd
+}
+
+func approvalKeygenCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "keygen file",
+		Short: "Create the ed25519 key an operator approves admin operations with",
+		Long: "Create an ed25519 key in the file, which must not exist, and print its public key, the\n" +
+			"pubKey of the operator in the approvals of the admin config. Each operator keeps their own key.",
+		Args:         cobra.ExactArgs(1),
+		Example:      `horcrux approval keygen ~/.horcrux-approval.key`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pub, priv, err := ed25519.GenerateKey(rand.Reader)
+			if err != nil {
+				return err
+			}
+			f, err := os.OpenFile(args[0], os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+			if err != nil {
+				return err
+			}
+			if _, err := f.WriteString(base64.StdEncoding.EncodeToString(priv) + "\n"); err != nil {
+				f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), base64.StdEncoding.EncodeToString(pub))
+			return nil
+		},
+	}
+}
+
+func approvalSignCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sign method target",
+		Short: "Approve an admin request",
+		Long: "Approve the admin request of the method and target, the path with its query, with the key\n" +
+			"of the operator, and print the approval. The request is sent with the approvals of the\n" +
+			"required number of distinct operators, each in an X-Horcrux-Approval header. An approval\n" +
+			"is only valid for the request, until it expires, and for a single use.",
+		Args: cobra.ExactArgs(2),
+		Example: `horcrux approval sign POST /v1/cluster/kill_switch/rearm --operator alice --key alice.key
+horcrux approval sign DELETE '/v1/chains?chain_id=cosmoshub-4' --operator bob --key bob.key --expires 5m`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			operator, _ := cmd.Flags().GetString(flagOperator)
+			keyFile, _ := cmd.Flags().GetString(flagKey)
+			expires, _ := cmd.Flags().GetDuration(flagExpires)
+			if operator == "" || keyFile == "" {
+				cmd.SilenceUsage = false
+				return fmt.Errorf("--%s and --%s are required", flagOperator, flagKey)
+			}
+			if expires <= 0 {
+				return fmt.Errorf("--%s must be positive", flagExpires)
+			}
+
+			bz, err := os.ReadFile(keyFile)
+			if err != nil {
+				return err
+			}
+			key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(bz)))
+			if err != nil || len(key) != ed25519.PrivateKeySize {
+				return fmt.Errorf("%s is not an approval key of horcrux approval keygen", keyFile)
+			}
+
+			request, err := signer.AdminApprovalRequest(args[0], args[1])
+			if err != nil {
+				return err
+			}
+			approval := signer.NewAdminApproval(operator, key, request, time.Now().Add(expires))
+			fmt.Fprintln(cmd.OutOrStdout(), approval)
+			return nil
+		},
+	}
+	cmd.Flags().String(flagOperator, "", "name of the operator in the approvals of the admin config")
+	cmd.Flags().String(flagKey, "", "file of the key of the operator")
+	cmd.Flags().Duration(flagExpires, 10*time.Minute, "how long the approval is valid")
+	return cmd
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/strangelove-ventures/horcrux/signer"
+)
+
+func auditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Commands to inspect the horcrux signer's audit log",
+	}
+
+	cmd.AddCommand(verifyAuditLogCmd())
+
+	return cmd
+}
+
+func verifyAuditLogCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify [file]",
+		Short: "Verify the hash chain of the audit log",
+		Long: "Verify that every entry of the audit log commits to the previous one and that the log ends\n" +
+			"at the entry recorded in its head file. Exits with an error if an entry was modified,\n" +
+			"removed or reordered, or the log was truncated. Defaults to the audit log in the config.",
+		Args:         cobra.MaximumNArgs(1),
+		Example:      `horcrux audit verify`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := config.AuditLogFile()
+			if len(args) == 1 {
+				path = args[0]
+			}
+
+			head, err := signer.VerifyAuditLog(path)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Audit log %s verified: %d entries, head hash %s\n", path, head.Seq, head.Hash)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"text/tabwriter"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/spf13/cobra"
+	"github.com/strangelove-ventures/horcrux/signer"
+)
+
+const (
+	flagSigns       = "signs"
+	flagConcurrency = "concurrency"
+	flagEncryption  = "encryption"
+)
+
+func benchmarkCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "benchmark",
+		Short: "Benchmark threshold signing on this machine",
+		Long: `Run the threshold sign pipeline of a local cluster of cosigners, from the nonce
+generation over the signs with the shards to the combined signature, against synthetic
+blocks, and print the latency percentiles of each stage and the sign rate.
+
+The blocks are signed with a new key, so no key or sign state of the config is used or
+changed. The cluster size defaults to the threshold and cosigners of the config. The
+network between the cosigners is not part of the benchmark, so add the round trip times
+between them to the latencies.`,
+		Args: cobra.NoArgs,
+		Example: `horcrux benchmark
+horcrux benchmark --threshold 3 --shards 5 --signs 1000
+horcrux benchmark --output json`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, _ := cmd.Flags().GetString(flagOutput)
+			if output != "table" && output != "json" {
+				return fmt.Errorf("--%s must be table or json", flagOutput)
+			}
+
+			cfg := signer.SignBenchmarkConfig{Threshold: 2, Shards: 3}
+			if tm := config.Config.ThresholdModeConfig; tm != nil && tm.Threshold > 0 {
+				cfg.Threshold, cfg.Shards = tm.Threshold, len(tm.Cosigners)
+			}
+			if cmd.Flags().Changed(flagThreshold) {
+				cfg.Threshold, _ = cmd.Flags().GetInt(flagThreshold)
+			}
+			if cmd.Flags().Changed(flagShards) {
+				cfg.Shards, _ = cmd.Flags().GetInt(flagShards)
+			}
+			cfg.Encryption, _ = cmd.Flags().GetString(flagEncryption)
+			cfg.Signs, _ = cmd.Flags().GetInt(flagSigns)
+			cfg.Concurrency, _ = cmd.Flags().GetInt(flagConcurrency)
+
+			if output == "table" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Signing %d blocks with %d of %d cosigners...\n",
+					cfg.Signs, cfg.Threshold, cfg.Shards)
+			}
+
+			res, err := signer.RunSignBenchmark(cmd.Context(), cometlog.NewNopLogger(), cfg)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if output == "json" {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(res)
+			}
+			return printSignBenchmark(out, res)
+		},
+	}
+
+	cmd.Flags().Int(flagThreshold, 0, "cosigners that sign each block, defaults to the threshold of the config or 2")
+	cmd.Flags().Int(flagShards, 0, "cosigners of the cluster, defaults to the cosigners of the config or 3")
+	cmd.Flags().String(flagEncryption, signer.BenchmarkEncryptionECIES, "encryption of the nonces, ecies or rsa")
+	cmd.Flags().Int(flagSigns, 500, "number of blocks to sign")
+	cmd.Flags().Int(flagConcurrency, runtime.NumCPU(), "number of chains signed concurrently to measure the sign rate")
+	cmd.Flags().StringP(flagOutput, "o", "table", "output format, table or json")
+
+	return cmd
+}
+
+// printSignBenchmark prints the latency percentiles of the stages of the sign pipeline and the sign rate.
+func printSignBenchmark(out io.Writer, res *signer.SignBenchmarkResult) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Cluster:\t%d of %d cosigners, %s\n", res.Threshold, res.Shards, res.Encryption)
+	fmt.Fprintf(w, "Signs:\t%d\n", res.Signs)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "\nLatency:")
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STAGE\tP50\tP90\tP99\tMAX")
+	for _, stage := range []struct {
+		name string
+		lat  signer.LatencyPercentiles
+	}{
+		{"nonces", res.Nonces},
+		{"sign", res.Sign},
+		{"combine", res.Combine},
+		{"total", res.Total},
+	} {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", stage.name, formatLatency(stage.lat.P50), formatLatency(stage.lat.P90),
+			formatLatency(stage.lat.P99), formatLatency(stage.lat.Max))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nMax sign rate: %.1f signs/s (concurrency %d)\n",
+		res.SignsPerSecond, res.Concurrency)
+	return nil
+}
+
+func formatLatency(d time.Duration) string {
+	return fmt.Sprintf("%.2fms", float64(d)/float64(time.Millisecond))
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/strangelove-ventures/horcrux/signer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintSignBenchmark(t *testing.T) {
+	ms := func(f float64) time.Duration { return time.Duration(f * float64(time.Millisecond)) }
+	lat := signer.LatencyPercentiles{P50: ms(1), P90: ms(1.5), P99: ms(2.25), Max: ms(3)}
+
+	var out bytes.Buffer
+	require.NoError(t, printSignBenchmark(&out, &signer.SignBenchmarkResult{
+		Threshold:      2,
+		Shards:         3,
+		Encryption:     signer.BenchmarkEncryptionECIES,
+		Signs:          500,
+		Concurrency:    4,
+		Nonces:         lat,
+		Sign:           lat,
+		Combine:        signer.LatencyPercentiles{P50: ms(0.1), P90: ms(0.1), P99: ms(0.2), Max: ms(0.3)},
+		Total:          signer.LatencyPercentiles{P50: ms(2.1), P90: ms(3.1), P99: ms(4.7), Max: ms(6.3)},
+		SignsPerSecond: 812.34,
+	}))
+	require.Equal(t, `Cluster:  2 of 3 cosigners, ecies
+Signs:    500
+
+Latency:
+STAGE    P50     P90     P99     MAX
+nonces   1.00ms  1.50ms  2.25ms  3.00ms
+sign     1.00ms  1.50ms  2.25ms  3.00ms
+combine  0.10ms  0.10ms  0.20ms  0.30ms
+total    2.10ms  3.10ms  4.70ms  6.30ms
+
+Max sign rate: 812.3 signs/s (concurrency 4)
+`, out.String())
+}
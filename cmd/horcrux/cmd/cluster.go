@@ -0,0 +1,369 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/spf13/cobra"
+	"github.com/strangelove-ventures/horcrux/signer"
+	"github.com/strangelove-ventures/horcrux/signer/proto"
+)
+
+const flagOutput = "output"
+
+func clusterCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cluster",
+		Short: "Commands to inspect the cosigner cluster",
+	}
+
+	cmd.AddCommand(clusterStatusCmd())
+	cmd.AddCommand(clusterPromoteCmd())
+	cmd.AddCommand(clusterTransferLeaderCmd())
+	cmd.AddCommand(clusterRollingRestartCmd())
+
+	return cmd
+}
+
+// cosignerStatus is the status reported by a cosigner, or the error querying it.
+type cosignerStatus struct {
+	ShardID int                      `json:"shardID"`
+	Address string                   `json:"address"`
+	Error   string                   `json:"error,omitempty"`
+	Status  *proto.GetStatusResponse `json:"status,omitempty"`
+}
+
+// getClusterStatus queries the status of every cosigner, ordered by shard ID.
+func getClusterStatus(
+	ctx context.Context,
+	cosigners signer.CosignersConfig,
+	transport signer.CosignerTransport,
+) []cosignerStatus {
+	statuses := make([]cosignerStatus, len(cosigners))
+	var wg sync.WaitGroup
+	for i, c := range cosigners {
+		statuses[i] = cosignerStatus{ShardID: c.ShardID, Address: c.P2PAddr}
+		wg.Add(1)
+		go func(s *cosignerStatus) {
+			defer wg.Done()
+			rc, err := signer.NewRemoteCosigner(s.ShardID, s.Address, transport)
+			if err != nil {
+				s.Error = err.Error()
+				return
+			}
+			ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			defer cancel()
+			if s.Status, err = rc.GetStatus(ctx); err != nil {
+				s.Error = err.Error()
+			}
+		}(&statuses[i])
+	}
+	wg.Wait()
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].ShardID < statuses[j].ShardID })
+	return statuses
+}
+
+func clusterStatusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Print the status of every cosigner of the cluster",
+		Long: `Query every cosigner of the config for its view of the leader, the reachability
+and protocol version of its peers, its nonce cache and the greatest HRS it signed
+of each chain. Only the leader pings its peers and keeps a nonce cache.`,
+		Args: cobra.NoArgs,
+		Example: `horcrux cluster status
+horcrux cluster status --output json`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, _ := cmd.Flags().GetString(flagOutput)
+			if output != "table" && output != "json" {
+				return fmt.Errorf("--%s must be table or json", flagOutput)
+			}
+
+			if config.Config.ThresholdModeConfig == nil {
+				return fmt.Errorf("threshold mode configuration is not present in config file")
+			}
+			thresholdCfg := config.Config.ThresholdModeConfig
+
+			transport, err := thresholdCfg.CosignerTransport()
+			if err != nil {
+				return err
+			}
+
+			statuses := getClusterStatus(cmd.Context(), thresholdCfg.Cosigners, transport)
+
+			out := cmd.OutOrStdout()
+			if output == "json" {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(statuses)
+			}
+			return printClusterStatus(out, statuses)
+		},
+	}
+
+	cmd.Flags().StringP(flagOutput, "o", "table", "output format, table or json")
+
+	return cmd
+}
+
+func clusterPromoteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "promote",
+		Short: "Promote this cluster to the active cluster of the shared watermark store",
+		Long: `Make this cluster the active cluster of the clusters that share the watermark store
+with failover. The cluster that was active can no longer claim a height, so it stops
+signing, and this cluster signs from the next height above the shared watermark.
+
+The promotion fails if another cluster is promoted at the same time.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if config.Config.ThresholdModeConfig == nil {
+				return fmt.Errorf("threshold mode configuration is not present in config file")
+			}
+			watermarkCfg := config.Config.ThresholdModeConfig.Watermark
+			if watermarkCfg == nil || !watermarkCfg.Failover {
+				return fmt.Errorf("promotion requires a watermark store with failover")
+			}
+			if err := watermarkCfg.Validate(); err != nil {
+				return err
+			}
+
+			store, err := signer.NewEtcdWatermarkStore(cometlog.NewNopLogger(), watermarkCfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+			defer cancel()
+			previous, err := store.Promote(ctx)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			switch previous {
+			case watermarkCfg.ClusterID:
+				fmt.Fprintf(out, "Cluster %s is already active\n", watermarkCfg.ClusterID)
+			case "":
+				fmt.Fprintf(out, "Cluster %s promoted, no cluster was active before\n", watermarkCfg.ClusterID)
+			default:
+				fmt.Fprintf(out, "Cluster %s promoted, cluster %s is on standby\n", watermarkCfg.ClusterID, previous)
+			}
+			return nil
+		},
+	}
+}
+
+const flagTimeout = "timeout"
+
+func clusterTransferLeaderCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transfer-leader id",
+		Short: "Transfer the leadership to a cosigner between sign rounds, e.g. for planned maintenance",
+		Long: `Transfer the leadership to the cosigner with the shard ID. The leader finishes the
+sign rounds in flight and holds new sign requests until the leadership is transferred,
+when they are proxied to the new leader. The command returns once the new leader is
+serving: it reports itself as the leader and every reachable cosigner agrees.`,
+		Args:         cobra.ExactArgs(1),
+		Example:      `horcrux cluster transfer-leader 2`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid cosigner shard ID %q: %w", args[0], err)
+			}
+			timeout, _ := cmd.Flags().GetDuration(flagTimeout)
+
+			if config.Config.ThresholdModeConfig == nil {
+				return fmt.Errorf("threshold mode configuration is not present in config file")
+			}
+			thresholdCfg := config.Config.ThresholdModeConfig
+			if !slices.ContainsFunc(thresholdCfg.Cosigners, func(c signer.CosignerConfig) bool {
+				return c.ShardID == target
+			}) {
+				return fmt.Errorf("cosigner %d is not in the config", target)
+			}
+
+			transport, err := thresholdCfg.CosignerTransport()
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			return transferClusterLeader(ctx, cmd.OutOrStdout(), thresholdCfg.Cosigners, transport, target)
+		},
+	}
+
+	cmd.Flags().Duration(flagTimeout, 30*time.Second, "how long to wait for the transfer and the new leader")
+
+	return cmd
+}
+
+// transferClusterLeader transfers the leadership to the cosigner with the shard ID, and waits until
+// it is the leader and serving.
+func transferClusterLeader(
+	ctx context.Context,
+	out io.Writer,
+	cosigners signer.CosignersConfig,
+	transport signer.CosignerTransport,
+	target int,
+) error {
+	statuses := getClusterStatus(ctx, cosigners, transport)
+	if clusterLeaderServing(statuses, target) == nil {
+		fmt.Fprintf(out, "Cosigner %d is already the leader\n", target)
+		return nil
+	}
+	leader := slices.IndexFunc(statuses, func(s cosignerStatus) bool {
+		return s.Status != nil && s.Status.IsLeader
+	})
+	if leader == -1 {
+		return fmt.Errorf("no cosigner reports itself as the leader")
+	}
+
+	rc, err := signer.NewRemoteCosigner(statuses[leader].ShardID, statuses[leader].Address, transport)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Transferring the leadership from cosigner %d to cosigner %d\n", rc.GetID(), target)
+	if _, err := rc.TransferLeadership(ctx, strconv.Itoa(target)); err != nil {
+		return fmt.Errorf("failed to transfer the leadership: %w", err)
+	}
+
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		err := clusterLeaderServing(getClusterStatus(ctx, cosigners, transport), target)
+		if err == nil {
+			fmt.Fprintf(out, "Cosigner %d is the leader and serving\n", target)
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("leadership transferred, but the new leader is not confirmed: %w", err)
+		case <-ticker.C:
+		}
+	}
+}
+
+// clusterLeaderServing returns nil if the cosigner with the shard ID reports itself as the leader
+// and every other reachable cosigner agrees.
+func clusterLeaderServing(statuses []cosignerStatus, leader int) error {
+	for _, s := range statuses {
+		switch {
+		case s.ShardID != leader:
+		case s.Status == nil:
+			return fmt.Errorf("cosigner %d is unreachable: %s", leader, s.Error)
+		case !s.Status.IsLeader:
+			return fmt.Errorf("cosigner %d is not the leader", leader)
+		}
+	}
+	for _, s := range statuses {
+		if s.Status != nil && int(s.Status.Leader) != leader {
+			return fmt.Errorf("cosigner %d sees cosigner %d as the leader", s.ShardID, s.Status.Leader)
+		}
+	}
+	return nil
+}
+
+// printClusterStatus prints the cosigners, the peers as seen by the leaders, and the chains as tables.
+func printClusterStatus(out io.Writer, statuses []cosignerStatus) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SHARD\tADDRESS\tLEADER\tVERSION\tPROTOCOL\tNONCES\tSTATUS")
+	for _, s := range statuses {
+		if s.Status == nil {
+			fmt.Fprintf(w, "%d\t%s\t-\t-\t-\t-\tunreachable: %s\n", s.ShardID, s.Address, s.Error)
+			continue
+		}
+		leader := strconv.Itoa(int(s.Status.Leader))
+		if s.Status.IsLeader {
+			leader += " (self)"
+		}
+		nonces := "-"
+		if s.Status.NonceCacheTargetSize > 0 {
+			nonces = fmt.Sprintf("%d/%d", s.Status.NonceCacheSize, s.Status.NonceCacheTargetSize)
+		}
+		version := s.Status.SoftwareVersion
+		if version == "" {
+			version = "-"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%s\tok\n",
+			s.ShardID, s.Address, leader, version, s.Status.ProtocolVersion, nonces)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, s := range statuses {
+		if s.Status == nil || len(s.Status.Peers) == 0 || !s.Status.Peers[0].Pinged {
+			continue
+		}
+		fmt.Fprintf(out, "\nPeers as seen by shard %d:\n", s.ShardID)
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SHARD\tADDRESS\tREACHABLE\tRTT\tPROTOCOL")
+		for _, p := range s.Status.Peers {
+			rtt := "-"
+			if p.Reachable {
+				rtt = fmt.Sprintf("%.1fms", p.RttMs)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%t\t%s\t%d\n", p.Id, p.Address, p.Reachable, rtt, p.ProtocolVersion)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	var chainIDs []string
+	for _, s := range statuses {
+		if s.Status == nil {
+			continue
+		}
+		for _, c := range s.Status.Chains {
+			if !slices.Contains(chainIDs, c.ChainID) {
+				chainIDs = append(chainIDs, c.ChainID)
+			}
+		}
+	}
+	if len(chainIDs) == 0 {
+		return nil
+	}
+	sort.Strings(chainIDs)
+
+	fmt.Fprintln(out, "\nLast signed height/round/step:")
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	header := []string{"CHAIN"}
+	for _, s := range statuses {
+		header = append(header, fmt.Sprintf("SHARD %d", s.ShardID))
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, chainID := range chainIDs {
+		row := []string{chainID}
+		for _, s := range statuses {
+			hrs := "-"
+			if s.Status != nil {
+				for _, c := range s.Status.Chains {
+					if c.ChainID == chainID {
+						hrs = fmt.Sprintf("%d/%d/%d", c.Height, c.Round, c.Step)
+					}
+				}
+			}
+			row = append(row, hrs)
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/strangelove-ventures/horcrux/signer/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintClusterStatus(t *testing.T) {
+	statuses := []cosignerStatus{
+		{ShardID: 1, Address: "tcp://cosigner-1:2222", Status: &proto.GetStatusResponse{
+			Id: 1, Leader: 1, IsLeader: true, SoftwareVersion: "v3.3.0", ProtocolVersion: 1,
+			NonceCacheSize: 80, NonceCacheTargetSize: 100,
+			Peers: []*proto.PeerStatus{
+				{Id: 2, Address: "tcp://cosigner-2:2222", Pinged: true, Reachable: true, RttMs: 1.5, ProtocolVersion: 1},
+				{Id: 3, Address: "tcp://cosigner-3:2222", Pinged: true},
+			},
+			Chains: []*proto.ChainStatus{{ChainID: "cosmoshub-4", Height: 100, Round: 0, Step: 3}},
+		}},
+		{ShardID: 2, Address: "tcp://cosigner-2:2222", Status: &proto.GetStatusResponse{
+			Id: 2, Leader: 1, SoftwareVersion: "v3.3.0", ProtocolVersion: 1,
+			Chains: []*proto.ChainStatus{{ChainID: "cosmoshub-4", Height: 99, Round: 0, Step: 2}},
+		}},
+		{ShardID: 3, Address: "tcp://cosigner-3:2222", Error: "connection refused"},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, printClusterStatus(&out, statuses))
+	require.Equal(t, `SHARD  ADDRESS                LEADER    VERSION  PROTOCOL  NONCES  STATUS
+1      tcp://cosigner-1:2222  1 (self)  v3.3.0   1         80/100  ok
+2      tcp://cosigner-2:2222  1         v3.3.0   1         -       ok
+3      tcp://cosigner-3:2222  -         -        -         -       unreachable: connection refused
+
+Peers as seen by shard 1:
+SHARD  ADDRESS                REACHABLE  RTT    PROTOCOL
+2      tcp://cosigner-2:2222  true       1.5ms  1
+3      tcp://cosigner-3:2222  false      -      0
+
+Last signed height/round/step:
+CHAIN        SHARD 1  SHARD 2  SHARD 3
+cosmoshub-4  100/0/3  99/0/2   -
+`, out.String())
+}
+
+func TestClusterLeaderServing(t *testing.T) {
+	statuses := []cosignerStatus{
+		{ShardID: 1, Status: &proto.GetStatusResponse{Leader: 1, IsLeader: true}},
+		{ShardID: 2, Status: &proto.GetStatusResponse{Leader: 1}},
+		{ShardID: 3, Error: "connection refused"},
+	}
+	require.NoError(t, clusterLeaderServing(statuses, 1))
+	require.ErrorContains(t, clusterLeaderServing(statuses, 2), "cosigner 2 is not the leader")
+	require.ErrorContains(t, clusterLeaderServing(statuses, 3), "cosigner 3 is unreachable")
+
+	statuses[1].Status.Leader = 3
+	require.ErrorContains(t, clusterLeaderServing(statuses, 1), "cosigner 2 sees cosigner 3 as the leader")
+}
+
+func TestRollingRestartChecks(t *testing.T) {
+	statuses := []cosignerStatus{
+		{ShardID: 1, Status: &proto.GetStatusResponse{Leader: 2, StartedAt: 10}},
+		{ShardID: 2, Status: &proto.GetStatusResponse{
+			Leader: 2, IsLeader: true, StartedAt: 20, NonceCacheSize: 8, NonceCacheTargetSize: 10,
+			Peers: []*proto.PeerStatus{{Id: 1, Pinged: true}, {Id: 3, Pinged: true, Reachable: true}},
+		}},
+		{ShardID: 3, Error: "connection refused"},
+	}
+
+	_, err := clusterReady(statuses)
+	require.ErrorContains(t, err, "cosigner 3 is unreachable")
+	require.ErrorContains(t, cosignerRejoined(statuses, 3, 30), "cosigner 3 is unreachable")
+
+	statuses[2] = cosignerStatus{ShardID: 3, Status: &proto.GetStatusResponse{Leader: 2, StartedAt: 30}}
+	_, err = clusterReady(statuses)
+	require.ErrorContains(t, err, "nonce cache of leader 2 is refilling: 8/10")
+
+	statuses[1].Status.NonceCacheSize = 10
+	leader, err := clusterReady(statuses)
+	require.NoError(t, err)
+	require.Equal(t, 2, leader)
+
+	require.ErrorContains(t, cosignerRejoined(statuses, 1, 10), "cosigner 1 has not restarted yet")
+
+	statuses[0].Status = &proto.GetStatusResponse{StartedAt: 11}
+	require.ErrorContains(t, cosignerRejoined(statuses, 1, 10), "cosigner 1 does not see a leader yet")
+
+	statuses[0].Status.Leader = 2
+	require.ErrorContains(t, cosignerRejoined(statuses, 1, 10), "leader 2 does not reach cosigner 1 yet")
+
+	statuses[1].Status.Peers[0].Reachable = true
+	require.NoError(t, cosignerRejoined(statuses, 1, 10))
+	require.NoError(t, cosignerRejoined(statuses, 3, 29))
+
+	// a restarted leader rejoins once another cosigner leads with a full nonce cache.
+	statuses[1].Status.StartedAt = 21
+	statuses[1].Status.NonceCacheSize = 0
+	require.ErrorContains(t, cosignerRejoined(statuses, 2, 20), "nonce cache of leader 2 is refilling: 0/10")
+}
@@ -1,8 +1,13 @@
 package cmd
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"github.com/strangelove-ventures/horcrux/signer"
@@ -19,6 +24,7 @@ const (
 	flagOverwrite   = "overwrite"
 	flagBare        = "bare"
 	flagGRPCAddress = "flagGRPCAddress"
+	flagProbe       = "probe"
 )
 
 func configCmd() *cobra.Command {
@@ -29,6 +35,7 @@ func configCmd() *cobra.Command {
 
 	cmd.AddCommand(initCmd())
 	cmd.AddCommand(migrateCmd())
+	cmd.AddCommand(validateCmd())
 
 	return cmd
 }
@@ -40,11 +47,30 @@ func initCmd() *cobra.Command {
 		Short:   "initialize configuration file and home directory if one doesn't already exist",
 		Long: `initialize configuration file.
 for threshold signer mode, --cosigner flags and --threshold flag are required.
+with --interactive, a wizard creates the configs and the keys of every cosigner of a new cluster instead.
+with --manifest and --cosigner-id, the config of the cosigner is derived from the cluster manifest instead.
 		`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			cmdFlags := cmd.Flags()
 
+			if interactive, _ := cmdFlags.GetBool(flagInteractive); interactive {
+				cmd.SilenceUsage = true
+				w := &initWizard{in: bufio.NewReader(cmd.InOrStdin()), out: cmd.OutOrStdout()}
+				plan, err := w.plan()
+				if err != nil {
+					return err
+				}
+				return plan.write(cmd.OutOrStdout())
+			}
+
+			if manifest, _ := cmdFlags.GetString(flagManifest); manifest != "" {
+				cmd.SilenceUsage = true
+				shardID, _ := cmdFlags.GetInt(flagCosignerID)
+				overwrite, _ := cmdFlags.GetBool(flagOverwrite)
+				return initFromManifest(cmd.OutOrStdout(), manifest, shardID, overwrite)
+			}
+
 			bare, _ := cmdFlags.GetBool(flagBare)
 			nodes, _ := cmdFlags.GetStringSlice(flagNode)
 
@@ -162,5 +188,75 @@ for threshold signer mode, --cosigner flags and --threshold flag are required.
 		"allows initialization without providing any flags. If flags are provided, will not perform final validation",
 	)
 	f.StringP(flagGRPCAddress, "g", "", "GRPC address if listener should be enabled")
+	f.BoolP(flagInteractive, "i", false, "create the configs and keys of every cosigner of a new cluster with a wizard")
+	f.String(flagManifest, "", "cluster manifest to derive the config of the cosigner from")
+	f.Int(flagCosignerID, 0, "shard ID of the cosigner to derive the config of from the cluster manifest")
 	return cmd
 }
+
+func validateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the config, the key files and the certificates before start",
+		Long: `Validate the config for its sign mode, that the key files are present and of this
+cosigner, and that the TLS certificates load and are valid. With --probe, also dial
+every chain node and every peer cosigner, and check that the clock skew to the peer
+cosigners is below 1s. Every check is reported as pass, warn or fail, and the command
+fails if any check fails.`,
+		Args: cobra.NoArgs,
+		Example: `horcrux config validate
+horcrux config validate --probe
+horcrux config validate --probe --output json`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, _ := cmd.Flags().GetString(flagOutput)
+			if output != "table" && output != "json" {
+				return fmt.Errorf("--%s must be table or json", flagOutput)
+			}
+			probe, _ := cmd.Flags().GetBool(flagProbe)
+
+			checks := config.CheckConfig(cmd.Context(), probe)
+
+			out := cmd.OutOrStdout()
+			if output == "json" {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(checks); err != nil {
+					return err
+				}
+			} else if err := printConfigChecks(out, checks); err != nil {
+				return err
+			}
+
+			failed := 0
+			for _, c := range checks {
+				if c.Status == signer.ConfigCheckFail {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool(flagProbe, false, "dial the chain nodes and the peer cosigners")
+	cmd.Flags().StringP(flagOutput, "o", "table", "output format, table or json")
+
+	return cmd
+}
+
+// printConfigChecks prints the checks as a table.
+func printConfigChecks(out io.Writer, checks []signer.ConfigCheck) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHECK\tSTATUS\tDETAIL")
+	for _, c := range checks {
+		detail := c.Detail
+		if detail == "" {
+			detail = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", c.Name, strings.ToUpper(string(c.Status)), detail)
+	}
+	return w.Flush()
+}
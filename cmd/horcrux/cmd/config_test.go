@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"bytes"
 	"io"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/strangelove-ventures/horcrux/signer"
 	"github.com/stretchr/testify/require"
 )
 
@@ -171,3 +173,51 @@ grpcAddr: ""
 		})
 	}
 }
+
+func TestConfigFileFormats(t *testing.T) {
+	for _, name := range []string{"config.json", "config.toml"} {
+		t.Run(name, func(t *testing.T) {
+			home := t.TempDir()
+			c := signer.RuntimeConfig{
+				ConfigFile: filepath.Join(home, name),
+				Config: signer.Config{
+					SignMode: signer.SignModeThreshold,
+					ThresholdModeConfig: &signer.ThresholdModeConfig{
+						Threshold: 2,
+						Cosigners: signer.CosignersConfig{
+							{ShardID: 1, P2PAddr: "tcp://10.168.1.1:2222"},
+							{ShardID: 2, P2PAddr: "tcp://10.168.1.2:2222"},
+						},
+						GRPCTimeout: "1000ms",
+						RaftTimeout: "1000ms",
+					},
+					ChainNodes: signer.ChainNodes{{PrivValAddr: "tcp://10.168.0.1:1234"}},
+				},
+			}
+			require.NoError(t, c.WriteConfigFile())
+
+			cmd := rootCmd()
+			cmd.SetOutput(io.Discard)
+			cmd.SetArgs([]string{"--home", home, "version"})
+			require.NoError(t, cmd.Execute())
+
+			require.Equal(t, c.ConfigFile, config.ConfigFile)
+			require.Equal(t, c.Config.ThresholdModeConfig, config.Config.ThresholdModeConfig)
+			require.Equal(t, c.Config.ChainNodes, config.Config.ChainNodes)
+		})
+	}
+}
+
+func TestPrintConfigChecks(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, printConfigChecks(&out, []signer.ConfigCheck{
+		{Name: "config", Status: signer.ConfigCheckPass, Detail: "threshold mode"},
+		{Name: "key shard cosmoshub-4", Status: signer.ConfigCheckFail, Detail: "key shard is of cosigner 2"},
+		{Name: "transport certificate", Status: signer.ConfigCheckWarn},
+	}))
+	require.Equal(t, `CHECK                  STATUS  DETAIL
+config                 PASS    threshold mode
+key shard cosmoshub-4  FAIL    key shard is of cosigner 2
+transport certificate  WARN    -
+`, out.String())
+}
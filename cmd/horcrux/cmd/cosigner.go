@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/strangelove-ventures/horcrux/signer"
+)
+
+const flagCount = "count"
+
+func cosignerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cosigner",
+		Short: "Commands to diagnose the cosigners",
+	}
+
+	cmd.AddCommand(cosignerPingCmd())
+
+	return cmd
+}
+
+func cosignerPingCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ping [shard-id...]",
+		Short: "Ping the cosigners over the cosigner gRPC API to debug connectivity",
+		Long: `Dial every cosigner of the config, or those with the shard IDs, at its p2pAddr over the
+configured transport, shake hands with it and ping it over the cosigner gRPC API, as the
+leader does. For each cosigner, print how long dialing took, the TLS connection and
+certificate with the websocket transport over TLS, the version and protocol version of
+the cosigner, the round trip times of the pings, or the first step that failed.
+
+The command fails if a cosigner is unreachable. It does not need a running signer.`,
+		Example: `horcrux cosigner ping
+horcrux cosigner ping 2 3 --count 10 --output json`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, _ := cmd.Flags().GetString(flagOutput)
+			if output != "table" && output != "json" {
+				return fmt.Errorf("--%s must be table or json", flagOutput)
+			}
+			count, _ := cmd.Flags().GetInt(flagCount)
+			if count < 1 {
+				return fmt.Errorf("--%s must be at least 1", flagCount)
+			}
+			timeout, _ := cmd.Flags().GetDuration(flagTimeout)
+
+			if config.Config.ThresholdModeConfig == nil {
+				return fmt.Errorf("threshold mode configuration is not present in config file")
+			}
+			thresholdCfg := config.Config.ThresholdModeConfig
+
+			cosigners := thresholdCfg.Cosigners
+			if len(args) > 0 {
+				cosigners = nil
+				for _, arg := range args {
+					shardID, err := strconv.Atoi(arg)
+					if err != nil {
+						return fmt.Errorf("invalid cosigner shard ID %q: %w", arg, err)
+					}
+					i := slices.IndexFunc(thresholdCfg.Cosigners, func(c signer.CosignerConfig) bool {
+						return c.ShardID == shardID
+					})
+					if i == -1 {
+						return fmt.Errorf("cosigner %d is not in the config", shardID)
+					}
+					cosigners = append(cosigners, thresholdCfg.Cosigners[i])
+				}
+			}
+
+			transport, err := thresholdCfg.CosignerTransport()
+			if err != nil {
+				return err
+			}
+
+			pings := make([]signer.CosignerPing, len(cosigners))
+			var wg sync.WaitGroup
+			for i, c := range cosigners {
+				wg.Add(1)
+				go func(i int, c signer.CosignerConfig) {
+					defer wg.Done()
+					pings[i] = signer.PingCosigner(cmd.Context(), c.ShardID, c.P2PAddr, transport, count, timeout)
+				}(i, c)
+			}
+			wg.Wait()
+			slices.SortFunc(pings, func(a, b signer.CosignerPing) int { return a.ShardID - b.ShardID })
+
+			out := cmd.OutOrStdout()
+			if output == "json" {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				err = enc.Encode(pings)
+			} else {
+				err = printCosignerPings(out, pings)
+			}
+			if err != nil {
+				return err
+			}
+
+			var unreachable []int
+			for _, p := range pings {
+				if !p.Reachable() {
+					unreachable = append(unreachable, p.ShardID)
+				}
+			}
+			if len(unreachable) > 0 {
+				return fmt.Errorf("cosigners unreachable: %v", unreachable)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Int(flagCount, 3, "number of pings of each cosigner")
+	cmd.Flags().Duration(flagTimeout, 5*time.Second, "timeout of dialing, the handshake and each ping")
+	cmd.Flags().StringP(flagOutput, "o", "table", "output format, table or json")
+
+	return cmd
+}
+
+// printCosignerPings prints the pings of the cosigners as a table, followed by the TLS connections.
+func printCosignerPings(out io.Writer, pings []signer.CosignerPing) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SHARD\tADDRESS\tDIAL\tRTT MIN/AVG/MAX\tVERSION\tPROTOCOL\tSTATUS")
+	for _, p := range pings {
+		dial, rtt, version, protocol := "-", "-", "-", "-"
+		if p.DialMilliseconds > 0 {
+			dial = fmt.Sprintf("%.1fms", p.DialMilliseconds)
+		}
+		if len(p.RTTMilliseconds) > 0 {
+			minRTT, maxRTT, sum := p.RTTMilliseconds[0], p.RTTMilliseconds[0], 0.0
+			for _, r := range p.RTTMilliseconds {
+				minRTT, maxRTT, sum = min(minRTT, r), max(maxRTT, r), sum+r
+			}
+			rtt = fmt.Sprintf("%.1f/%.1f/%.1fms", minRTT, sum/float64(len(p.RTTMilliseconds)), maxRTT)
+		}
+		if p.Build != nil && p.Build.Version != "" {
+			version = p.Build.Version
+			if p.VersionSkew != signer.VersionSkewNone {
+				version += fmt.Sprintf(" (%s skew)", p.VersionSkew)
+			}
+		}
+		if p.ProtocolVersion > 0 {
+			protocol = strconv.Itoa(int(p.ProtocolVersion))
+		}
+		status := "ok"
+		if !p.Reachable() {
+			status = p.Error
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", p.ShardID, p.Address, dial, rtt, version, protocol, status)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if !slices.ContainsFunc(pings, func(p signer.CosignerPing) bool { return p.TLS != nil }) {
+		return nil
+	}
+	fmt.Fprintln(out, "\nTLS:")
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SHARD\tVERSION\tCIPHER SUITE\tSUBJECT\tISSUER\tEXPIRES")
+	for _, p := range pings {
+		if p.TLS == nil {
+			continue
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", p.ShardID, p.TLS.Version, p.TLS.CipherSuite,
+			p.TLS.Subject, p.TLS.Issuer, p.TLS.NotAfter.UTC().Format(time.RFC3339))
+	}
+	return w.Flush()
+}
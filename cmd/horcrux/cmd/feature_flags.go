@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	grpcretry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
+	"github.com/spf13/cobra"
+	"github.com/strangelove-ventures/horcrux/signer"
+	"github.com/strangelove-ventures/horcrux/signer/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	flagFeatureChainID    = "chain-id"
+	flagFeaturePercentage = "percentage"
+	flagFeatureDisable    = "disable"
+)
+
+func featureFlagsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "feature-flags",
+		Aliases: []string{"ff"},
+		Short:   "Commands to roll out or roll back new signer behaviors across the cosigner cluster",
+	}
+
+	cmd.AddCommand(listFeatureFlagsCmd())
+	cmd.AddCommand(setFeatureFlagCmd())
+	cmd.AddCommand(resetFeatureFlagCmd())
+
+	return cmd
+}
+
+func listFeatureFlagsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "list",
+		Aliases:      []string{"ls"},
+		Short:        "List the feature flags of the raft leader",
+		Args:         cobra.NoArgs,
+		Example:      `horcrux feature-flags list`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withLeaderCosignerClient(func(ctx context.Context, grpcClient proto.CosignerClient) error {
+				res, err := grpcClient.GetFeatureFlags(ctx, &proto.GetFeatureFlagsRequest{})
+				if err != nil {
+					return err
+				}
+
+				out := cmd.OutOrStdout()
+				if len(res.Flags) == 0 {
+					fmt.Fprintln(out, "No feature flags defined")
+					return nil
+				}
+				for _, f := range res.Flags {
+					chains := "all"
+					if len(f.ChainIDs) > 0 {
+						chains = strings.Join(f.ChainIDs, ",")
+					}
+					percentage := f.Percentage
+					if percentage == 0 {
+						percentage = 100
+					}
+					source := "config"
+					if f.Overridden {
+						source = "runtime"
+					}
+					fmt.Fprintf(out, "%s: enabled=%t chains=%s percentage=%d source=%s\n",
+						f.Name, f.Enabled, chains, percentage, source)
+				}
+				return nil
+			})
+		},
+	}
+}
+
+func setFeatureFlagCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set name",
+		Short: "Enable or disable a feature flag on all cosigners",
+		Long: `Override a feature flag at runtime on all cosigners through the raft leader.
+The override is replicated through raft and takes precedence over the config
+until it is reset. The change is signed with the key of this cosigner.
+
+The signer behaviors behind feature flags, enabled by default:
+  alternate-cosigners: retry the share signature of a failed peer on the next fastest peer
+  nonce-fallback:      fetch nonces from the peers when the nonce cache has none`,
+		Args: cobra.ExactArgs(1),
+		Example: `horcrux feature-flags set nonce-fallback --disable # roll back for all chains and requests
+horcrux feature-flags set nonce-fallback --chain-id cosmoshub-4 --percentage 10
+horcrux feature-flags set nonce-fallback # enable again`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chainIDs, _ := cmd.Flags().GetStringSlice(flagFeatureChainID)
+			percentage, _ := cmd.Flags().GetUint32(flagFeaturePercentage)
+			disable, _ := cmd.Flags().GetBool(flagFeatureDisable)
+
+			if percentage > 100 {
+				return fmt.Errorf("--%s must be between 0 and 100", flagFeaturePercentage)
+			}
+
+			return withLeaderCosignerClient(func(ctx context.Context, grpcClient proto.CosignerClient) error {
+				err := setFeatureFlag(ctx, grpcClient, &proto.SetFeatureFlagRequest{
+					Flag: &proto.FeatureFlag{
+						Name:       args[0],
+						Enabled:    !disable,
+						ChainIDs:   chainIDs,
+						Percentage: percentage,
+					},
+				})
+				if err != nil {
+					return err
+				}
+
+				fmt.Fprintf(cmd.OutOrStdout(), "Feature flag %s updated\n", args[0])
+				return nil
+			})
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringSlice(flagFeatureChainID, nil, "restrict the behavior to the chain ID(s), default all chains")
+	f.Uint32(flagFeaturePercentage, 0, "percentage of requests (1-100) which use the behavior, default all requests")
+	f.Bool(flagFeatureDisable, false, "disable the behavior")
+
+	return cmd
+}
+
+func resetFeatureFlagCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "reset name",
+		Short:        "Remove the runtime override of a feature flag, reverting to the config",
+		Args:         cobra.ExactArgs(1),
+		Example:      `horcrux feature-flags reset nonce-fallback`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withLeaderCosignerClient(func(ctx context.Context, grpcClient proto.CosignerClient) error {
+				err := setFeatureFlag(ctx, grpcClient, &proto.SetFeatureFlagRequest{
+					Flag:          &proto.FeatureFlag{Name: args[0]},
+					ClearOverride: true,
+				})
+				if err != nil {
+					return err
+				}
+
+				fmt.Fprintf(cmd.OutOrStdout(), "Feature flag %s reset\n", args[0])
+				return nil
+			})
+		},
+	}
+}
+
+// setFeatureFlag sends the feature flag change to the raft leader, signed with the key of this
+// cosigner, which the leader only accepts from a cosigner of the cluster.
+func setFeatureFlag(ctx context.Context, grpcClient proto.CosignerClient, req *proto.SetFeatureFlagRequest) error {
+	security, err := config.CosignerSecurity()
+	if err != nil {
+		return fmt.Errorf("feature flag changes are signed with the key of the cosigner: %w", err)
+	}
+	leader, err := grpcClient.GetLeader(ctx, &proto.GetLeaderRequest{})
+	if err != nil {
+		return err
+	}
+	if err := signer.SignSetFeatureFlagRequest(security, int(leader.Leader), req); err != nil {
+		return err
+	}
+	_, err = grpcClient.SetFeatureFlag(ctx, req)
+	return err
+}
+
+// withLeaderCosignerClient dials the raft leader of the cosigner cluster and calls fn with a client.
+func withLeaderCosignerClient(fn func(ctx context.Context, grpcClient proto.CosignerClient) error) error {
+	if config.Config.ThresholdModeConfig == nil {
+		return fmt.Errorf("threshold mode configuration is not present in config file")
+	}
+
+	if len(config.Config.ThresholdModeConfig.Cosigners) == 0 {
+		return fmt.Errorf("threshold mode configuration has no cosigners")
+	}
+
+	serviceConfig := `{"healthCheckConfig": {"serviceName": "Leader"}, "loadBalancingConfig": [ { "round_robin": {} } ]}`
+	retryOpts := []grpcretry.CallOption{
+		grpcretry.WithBackoff(grpcretry.BackoffExponential(100 * time.Millisecond)),
+		grpcretry.WithMax(5),
+	}
+
+	grpcAddress, err := config.Config.ThresholdModeConfig.LeaderElectMultiAddress()
+	if err != nil {
+		return err
+	}
+
+	conn, err := grpc.Dial(grpcAddress,
+		grpc.WithDefaultServiceConfig(serviceConfig), grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.WaitForReady(true)),
+		grpc.WithUnaryInterceptor(grpcretry.UnaryClientInterceptor(retryOpts...)))
+	if err != nil {
+		return fmt.Errorf("dialing failed: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancelFunc()
+
+	return fn(ctx, proto.NewCosignerClient(conn))
+}
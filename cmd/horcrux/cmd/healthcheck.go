@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/strangelove-ventures/horcrux/signer"
+)
+
+const flagReady = "ready"
+
+func healthcheckCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "healthcheck",
+		Short: "Check the health of the running signer, for container probes",
+		Long: `Query the liveness probe, or with --ready the readiness probe, of the signer
+running with this config, and exit with a non-zero status if the probe fails.
+
+The probe is queried on the debug server at debugAddr, with the TLS and authentication
+of debugServer, or on the admin API if the debug server is disabled. Use it as a Docker
+HEALTHCHECK or a Kubernetes exec probe without an HTTP client in the image.`,
+		Args: cobra.NoArgs,
+		Example: `horcrux healthcheck
+horcrux healthcheck --ready
+horcrux healthcheck --address 127.0.0.1:6001`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ready, _ := cmd.Flags().GetBool(flagReady)
+			address, _ := cmd.Flags().GetString(flagAddress)
+			timeout, _ := cmd.Flags().GetDuration(flagTimeout)
+
+			client, path, err := healthcheckClient(config.Config, address, ready)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			if err := client.Probe(ctx, path); err != nil {
+				return err
+			}
+			if ready {
+				fmt.Fprintln(cmd.OutOrStdout(), "ready")
+			} else {
+				fmt.Fprintln(cmd.OutOrStdout(), "live")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool(flagReady, false, "check that the signer is ready to sign, not only that it is live")
+	cmd.Flags().String(flagAddress, "", "address of the debug server or admin API, defaults to that of the config")
+	cmd.Flags().Duration(flagTimeout, 5*time.Second, "timeout of the check")
+
+	return cmd
+}
+
+// healthcheckClient returns the client of the debug server of the config, or of its admin API if
+// the debug server is disabled, and the path of the probe.
+func healthcheckClient(cfg signer.Config, address string, ready bool) (*signer.AdminClient, string, error) {
+	probe := "/live"
+	if ready {
+		probe = "/ready"
+	}
+
+	if cfg.DebugAddr != "" {
+		if address == "" {
+			address = cfg.DebugAddr
+		}
+		client, err := signer.NewDebugServerClient(cfg.DebugServer, address)
+		return client, probe, err
+	}
+	if cfg.Admin != nil {
+		client, err := signer.NewAdminClient(cfg.Admin, address)
+		return client, "/v1" + probe, err
+	}
+	return nil, "", fmt.Errorf("neither debugAddr nor admin is configured, enable one to check the health")
+}
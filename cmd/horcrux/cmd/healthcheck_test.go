@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/strangelove-ventures/horcrux/signer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthcheckClient(t *testing.T) {
+	_, _, err := healthcheckClient(signer.Config{}, "", false)
+	require.EqualError(t, err, "neither debugAddr nor admin is configured, enable one to check the health")
+
+	// the debug server is preferred over the admin API, which requires authentication.
+	_, path, err := healthcheckClient(signer.Config{DebugAddr: "0.0.0.0:6001"}, "", false)
+	require.NoError(t, err)
+	require.Equal(t, "/live", path)
+
+	_, path, err = healthcheckClient(signer.Config{DebugAddr: "0.0.0.0:6001"}, "", true)
+	require.NoError(t, err)
+	require.Equal(t, "/ready", path)
+
+	_, _, err = healthcheckClient(signer.Config{DebugAddr: "6001"}, "", true)
+	require.ErrorContains(t, err, `invalid debug server address "6001"`)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("secret"), 0600))
+	admin := &signer.AdminAPIConfig{
+		ListenAddr:        "127.0.0.1:6100",
+		DebugServerConfig: signer.DebugServerConfig{BearerTokenFile: tokenFile},
+	}
+	_, path, err = healthcheckClient(signer.Config{Admin: admin}, "", true)
+	require.NoError(t, err)
+	require.Equal(t, "/v1/ready", path)
+}
@@ -0,0 +1,318 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	"github.com/cometbft/cometbft/privval"
+	"github.com/strangelove-ventures/horcrux/signer"
+)
+
+const flagInteractive = "interactive"
+
+// clusterPlan is the setup of a threshold cluster gathered by the init wizard.
+type clusterPlan struct {
+	chainID   string
+	threshold int
+	cosigners []plannedCosigner
+	// keyFile is the priv_validator_key.json to shard, or empty to generate a new key.
+	keyFile   string
+	debugAddr string
+	outDir    string
+}
+
+// plannedCosigner is a cosigner of a clusterPlan.
+type plannedCosigner struct {
+	p2pAddr    string
+	chainNodes []string
+}
+
+// initWizard asks the questions of the init wizard on out and reads the answers from in.
+type initWizard struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// ask asks the question until the answer, or def for an empty answer, is valid.
+func (w *initWizard) ask(question, def string, valid func(string) error) (string, error) {
+	for {
+		if def != "" {
+			fmt.Fprintf(w.out, "%s [%s]: ", question, def)
+		} else {
+			fmt.Fprintf(w.out, "%s: ", question)
+		}
+		line, err := w.in.ReadString('\n')
+		if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+			return "", fmt.Errorf("no answer to %q: %w", question, err)
+		}
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			answer = def
+		}
+		if err := valid(answer); err != nil {
+			fmt.Fprintf(w.out, "Invalid answer: %v\n", err)
+			continue
+		}
+		return answer, nil
+	}
+}
+
+// askInt asks the question until the answer is an integer between lo and hi.
+func (w *initWizard) askInt(question string, def, lo, hi int) (int, error) {
+	answer, err := w.ask(question, strconv.Itoa(def), func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < lo || n > hi {
+			return fmt.Errorf("must be a number between %d and %d", lo, hi)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(answer)
+}
+
+// plan walks the operator through the setup of a cluster.
+func (w *initWizard) plan() (*clusterPlan, error) {
+	fmt.Fprintln(w.out, "This wizard creates the config and the keys of every cosigner of a new threshold cluster.")
+	fmt.Fprintln(w.out, "Press enter to accept the default in brackets.")
+	fmt.Fprintln(w.out)
+
+	var p clusterPlan
+	var err error
+
+	p.chainID, err = w.ask("Chain ID of the validator", "", func(s string) error {
+		if s == "" {
+			return fmt.Errorf("chain ID is required")
+		}
+		if strings.ContainsAny(s, `/\`) {
+			return fmt.Errorf("chain ID cannot contain path separators")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	shards, err := w.askInt("Number of cosigners", 3, 2, 255)
+	if err != nil {
+		return nil, err
+	}
+	p.threshold, err = w.askInt(
+		"Number of cosigners required to sign (threshold)", shards/2+1, shards/2+1, shards,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for id := 1; id <= shards; id++ {
+		var c plannedCosigner
+		c.p2pAddr, err = w.ask(
+			fmt.Sprintf("P2P address of cosigner %d, reachable by the other cosigners", id),
+			fmt.Sprintf("tcp://horcrux-%d:2222", id),
+			func(s string) error {
+				if err := (signer.CosignersConfig{{ShardID: 1, P2PAddr: s}}).Validate(); err != nil {
+					return err
+				}
+				for i, other := range p.cosigners {
+					if other.p2pAddr == s {
+						return fmt.Errorf("cosigner %d already has this address", i+1)
+					}
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+		nodes, err := w.ask(
+			fmt.Sprintf("Chain nodes of cosigner %d, comma separated", id),
+			fmt.Sprintf("tcp://sentry-%d:1234", id),
+			func(s string) error {
+				_, err := signer.ChainNodesFromFlag(splitList(s))
+				return err
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+		c.chainNodes = splitList(nodes)
+		p.cosigners = append(p.cosigners, c)
+	}
+
+	p.keyFile, err = w.ask(
+		"priv_validator_key.json of the validator to shard, or empty to generate a new key", "",
+		func(s string) error {
+			if s == "" {
+				return nil
+			}
+			_, err := signer.ReadPrivValidatorFile(s)
+			return err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	p.debugAddr, err = w.ask("Listen address of the debug server and metrics, or none", "0.0.0.0:6001",
+		func(string) error { return nil })
+	if err != nil {
+		return nil, err
+	}
+	if p.debugAddr == "none" {
+		p.debugAddr = ""
+	}
+
+	p.outDir, err = w.ask("Directory to write the files of the cosigners to", "horcrux-cluster", func(s string) error {
+		if entries, err := os.ReadDir(s); err == nil && len(entries) > 0 {
+			return fmt.Errorf("%s is not empty", s)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// splitList splits a comma separated list, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// config returns the config of the cosigner with the shard ID.
+func (p *clusterPlan) config(id int) (signer.Config, error) {
+	cosigners := make(signer.CosignersConfig, len(p.cosigners))
+	for i, c := range p.cosigners {
+		cosigners[i] = signer.CosignerConfig{ShardID: i + 1, P2PAddr: c.p2pAddr}
+	}
+	nodes, err := signer.ChainNodesFromFlag(p.cosigners[id-1].chainNodes)
+	if err != nil {
+		return signer.Config{}, err
+	}
+	cfg := signer.Config{
+		SignMode: signer.SignModeThreshold,
+		ThresholdModeConfig: &signer.ThresholdModeConfig{
+			Threshold:   p.threshold,
+			Cosigners:   cosigners,
+			GRPCTimeout: "1000ms",
+			RaftTimeout: "1000ms",
+		},
+		ChainNodes: nodes,
+		DebugAddr:  p.debugAddr,
+	}
+	return cfg, cfg.ValidateThresholdModeConfig()
+}
+
+// write writes the config, the key shard and the ECIES key of every cosigner to a directory of the
+// cosigner in the output directory, with the distribution plan of the files.
+func (p *clusterPlan) write(out io.Writer) error {
+	configs := make([]signer.Config, len(p.cosigners))
+	for i := range p.cosigners {
+		cfg, err := p.config(i + 1)
+		if err != nil {
+			return fmt.Errorf("invalid config of cosigner %d: %w", i+1, err)
+		}
+		configs[i] = cfg
+	}
+
+	if err := os.MkdirAll(p.outDir, 0700); err != nil {
+		return err
+	}
+
+	var pv privval.FilePVKey
+	keyFile := p.keyFile
+	if keyFile == "" {
+		keyFile = filepath.Join(p.outDir, "priv_validator_key.json")
+		filePV := privval.NewFilePV(cometcryptoed25519.GenPrivKey(), keyFile, "")
+		filePV.Key.Save()
+		pv = filePV.Key
+		fmt.Fprintf(out, "Generated validator key %s\n", keyFile)
+	} else {
+		var err error
+		if pv, err = signer.ReadPrivValidatorFile(keyFile); err != nil {
+			return err
+		}
+	}
+
+	shards := signer.CreateCosignerEd25519Shards(pv, uint8(p.threshold), uint8(len(p.cosigners)))
+	eciesKeys, err := signer.CreateCosignerECIESShards(len(p.cosigners))
+	if err != nil {
+		return err
+	}
+
+	for i, cfg := range configs {
+		dir, err := createCosignerDirectoryIfNecessary(p.outDir, i+1)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, "config.yaml"), cfg.MustMarshalYaml(), 0600); err != nil {
+			return err
+		}
+		shardFile := filepath.Join(dir, fmt.Sprintf("%s_shard.json", p.chainID))
+		if err := signer.WriteCosignerEd25519ShardFile(shards[i], shardFile); err != nil {
+			return err
+		}
+		if err := signer.WriteCosignerECIESShardFile(eciesKeys[i], filepath.Join(dir, "ecies_keys.json")); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Created config and keys of cosigner %d in %s\n", i+1, dir)
+	}
+
+	pubKey, err := signer.PubKey("", pv.PubKey)
+	if err != nil {
+		return err
+	}
+	planFile := filepath.Join(p.outDir, "DISTRIBUTION.md")
+	if err := os.WriteFile(planFile, []byte(p.distribution(pubKey)), 0600); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "\nRead %s for where to copy the files.\n", planFile)
+	return nil
+}
+
+// distribution returns the plan of where to copy the files of the cosigners to.
+func (p *clusterPlan) distribution(pubKey string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Distribution Plan\n\n")
+	fmt.Fprintf(&b, "Threshold cluster of %d cosigners, %d of which sign, for chain %s.\n\n",
+		len(p.cosigners), p.threshold, p.chainID)
+	fmt.Fprintf(&b, "Validator public key: `%s`\n\n", pubKey)
+	fmt.Fprintf(&b, "Copy the files of each cosigner to the home directory of horcrux on its node, "+
+		"`~/.horcrux` by default, over a secure channel. Every file holds secrets of only that cosigner: "+
+		"never copy the files of a cosigner to another node.\n\n")
+	for i, c := range p.cosigners {
+		fmt.Fprintf(&b, "## Cosigner %d (%s)\n\n", i+1, c.p2pAddr)
+		fmt.Fprintf(&b, "| File | Copy to |\n|------|---------|\n")
+		fmt.Fprintf(&b, "| `cosigner_%d/config.yaml` | `~/.horcrux/config.yaml` |\n", i+1)
+		fmt.Fprintf(&b, "| `cosigner_%d/%s_shard.json` | `~/.horcrux/%s_shard.json` |\n", i+1, p.chainID, p.chainID)
+		fmt.Fprintf(&b, "| `cosigner_%d/ecies_keys.json` | `~/.horcrux/ecies_keys.json` |\n\n", i+1)
+		fmt.Fprintf(&b, "Chain nodes: %s, each with `priv_validator_laddr` listening on the port of its address.\n\n",
+			strings.Join(c.chainNodes, ", "))
+	}
+	fmt.Fprintf(&b, "## Afterwards\n\n")
+	fmt.Fprintf(&b, "1. On every cosigner, run `horcrux config validate --probe` once all cosigners are set up.\n")
+	fmt.Fprintf(&b, "2. Start the cosigners with `horcrux start`.\n")
+	if p.keyFile == "" {
+		fmt.Fprintf(&b, "3. Store `priv_validator_key.json`, the full key of the validator, offline, "+
+			"and delete it and this directory from this machine.\n")
+	} else {
+		fmt.Fprintf(&b, "3. Store `%s`, the full key of the validator, offline, "+
+			"and delete it and this directory from this machine.\n", p.keyFile)
+	}
+	return b.String()
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strangelove-ventures/horcrux/signer"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestConfigInitInteractive(t *testing.T) {
+	home := t.TempDir()
+	outDir := filepath.Join(home, "cluster")
+
+	answers := strings.Join([]string{
+		"",            // the chain ID is required
+		"cosmoshub-4", // chain ID
+		"",            // 3 cosigners
+		"1",           // a threshold of 1 is not a majority
+		"",            // threshold 2
+		"",            // p2p address of cosigner 1
+		"tcp://10.168.0.1:1234, tcp://10.168.0.2:1234",
+		"tcp://horcrux-1:2222", // already the address of cosigner 1
+		"",                     // p2p address of cosigner 2
+		"",                     // chain nodes of cosigner 2
+		"",                     // p2p address of cosigner 3
+		"",                     // chain nodes of cosigner 3
+		"",                     // generate a new key
+		"none",                 // no debug server
+		outDir,
+	}, "\n") + "\n"
+
+	var out bytes.Buffer
+	cmd := rootCmd()
+	cmd.SetIn(strings.NewReader(answers))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--home", filepath.Join(home, ".horcrux"), "config", "init", "--interactive"})
+	require.NoError(t, cmd.Execute())
+
+	require.Contains(t, out.String(), "Invalid answer: chain ID is required")
+	require.Contains(t, out.String(), "Invalid answer: must be a number between 2 and 3")
+	require.Contains(t, out.String(), "Invalid answer: cosigner 1 already has this address")
+
+	pv, err := signer.ReadPrivValidatorFile(filepath.Join(outDir, "priv_validator_key.json"))
+	require.NoError(t, err)
+
+	for id := 1; id <= 3; id++ {
+		dir := filepath.Join(outDir, fmt.Sprintf("cosigner_%d", id))
+
+		bz, err := os.ReadFile(filepath.Join(dir, "config.yaml"))
+		require.NoError(t, err)
+		var cfg signer.Config
+		require.NoError(t, yaml.Unmarshal(bz, &cfg))
+		require.NoError(t, cfg.ValidateThresholdModeConfig())
+		require.Equal(t, 2, cfg.ThresholdModeConfig.Threshold)
+		require.Equal(t, "tcp://horcrux-2:2222", cfg.ThresholdModeConfig.Cosigners[1].P2PAddr)
+		require.Empty(t, cfg.DebugAddr)
+		if id == 1 {
+			require.Equal(t, signer.ChainNodes{
+				{PrivValAddr: "tcp://10.168.0.1:1234"}, {PrivValAddr: "tcp://10.168.0.2:1234"},
+			}, cfg.ChainNodes)
+		} else {
+			require.Equal(t, signer.ChainNodes{{PrivValAddr: fmt.Sprintf("tcp://sentry-%d:1234", id)}},
+				cfg.ChainNodes)
+		}
+
+		key, err := signer.LoadCosignerEd25519Key(filepath.Join(dir, "cosmoshub-4_shard.json"))
+		require.NoError(t, err)
+		require.Equal(t, id, key.ID)
+		require.Equal(t, pv.PubKey, key.PubKey)
+
+		eciesKey, err := signer.LoadCosignerECIESKey(filepath.Join(dir, "ecies_keys.json"))
+		require.NoError(t, err)
+		require.Equal(t, id, eciesKey.ID)
+	}
+
+	plan, err := os.ReadFile(filepath.Join(outDir, "DISTRIBUTION.md"))
+	require.NoError(t, err)
+	require.Contains(t, string(plan), "Threshold cluster of 3 cosigners, 2 of which sign, for chain cosmoshub-4.")
+	require.Contains(t, string(plan), "| `cosigner_3/cosmoshub-4_shard.json` | `~/.horcrux/cosmoshub-4_shard.json` |")
+
+	// the wizard refuses to overwrite the files of another cluster.
+	cmd = rootCmd()
+	cmd.SetIn(strings.NewReader("cosmoshub-4\n\n\n\n\n\n\n\n\n\n\n" + outDir + "\n"))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--home", filepath.Join(home, ".horcrux"), "config", "init", "--interactive"})
+	require.ErrorContains(t, cmd.Execute(), `no answer to "Directory to write the files of the cosigners to"`)
+	require.Contains(t, out.String(), "Invalid answer: "+outDir+" is not empty")
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"io"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/strangelove-ventures/horcrux/signer"
+)
+
+const flagLogFormat = "log-format"
+
+// logFormatFlag is the value of the --log-format flag, which overrides the logFormat of the config.
+var logFormatFlag string
+
+// logLevels are the log levels of all loggers returned by newLogger,
+// adjustable at runtime through the debug server.
+var logLevels = signer.NewLogLevels()
+
+// logFormat returns the log format from the --log-format flag if set, otherwise from the config.
+func logFormat() signer.LogFormat {
+	if logFormatFlag != "" {
+		return signer.LogFormat(logFormatFlag)
+	}
+	return config.Config.LogFormat
+}
+
+// newLogger returns a logger writing to out in the configured log format, filtered by logLevels.
+func newLogger(out io.Writer) cometlog.Logger {
+	return logLevels.Filter(newFormatLogger(out))
+}
+
+// newFormatLogger returns a logger writing to out in the configured log format.
+func newFormatLogger(out io.Writer) cometlog.Logger {
+	if logFormat() == signer.LogFormatJSON {
+		return cometlog.NewTMJSONLogger(cometlog.NewSyncWriter(out))
+	}
+	return cometlog.NewTMLogger(cometlog.NewSyncWriter(out))
+}
+
+// newSignerLogger returns the logger of the signer, which writes to out and to the log file and
+// syslog of the config, filtered by logLevels, with a function that closes the log file and syslog.
+func newSignerLogger(out io.Writer) (cometlog.Logger, func(), error) {
+	loggers := []cometlog.Logger{newFormatLogger(out)}
+	var closers []io.Closer
+	closeAll := func() {
+		for _, c := range closers {
+			_ = c.Close()
+		}
+	}
+
+	if cfg := config.Config.LogFile; cfg != nil {
+		file, err := signer.NewRotatingFile(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		closers = append(closers, file)
+		loggers = append(loggers, newFormatLogger(file))
+	}
+
+	if cfg := config.Config.Syslog; cfg != nil {
+		logger, closer, err := signer.NewSyslogLogger(cfg, newFormatLogger)
+		if err != nil {
+			closeAll()
+			return nil, nil, err
+		}
+		closers = append(closers, closer)
+		loggers = append(loggers, logger)
+	}
+
+	return logLevels.Filter(signer.NewTeeLogger(loggers...)), closeAll, nil
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/strangelove-ventures/horcrux/signer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLogger(t *testing.T) {
+	prevConfig, prevFlag := config, logFormatFlag
+	t.Cleanup(func() {
+		config, logFormatFlag = prevConfig, prevFlag
+	})
+
+	tcs := []struct {
+		name       string
+		configured signer.LogFormat
+		flag       string
+		expectJSON bool
+	}{
+		{name: "default", expectJSON: false},
+		{name: "config plain", configured: signer.LogFormatPlain, expectJSON: false},
+		{name: "config json", configured: signer.LogFormatJSON, expectJSON: true},
+		{name: "flag overrides config", configured: signer.LogFormatJSON, flag: "plain", expectJSON: false},
+		{name: "flag json", flag: "json", expectJSON: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			config.Config.LogFormat = tc.configured
+			logFormatFlag = tc.flag
+
+			var out bytes.Buffer
+			newLogger(&out).Info("Signed", "chain_id", "horcrux-1", "height", 10, "cosigner", 2)
+
+			var entry map[string]any
+			err := json.Unmarshal(out.Bytes(), &entry)
+			if !tc.expectJSON {
+				require.Error(t, err)
+				require.Contains(t, out.String(), "chain_id=horcrux-1")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "info", entry["level"])
+			require.Equal(t, "Signed", entry["_msg"])
+			require.Equal(t, "horcrux-1", entry["chain_id"])
+			require.Equal(t, float64(10), entry["height"])
+			require.Equal(t, float64(2), entry["cosigner"])
+		})
+	}
+}
+
+func TestLogFormatValidate(t *testing.T) {
+	require.NoError(t, signer.LogFormat("").Validate())
+	require.NoError(t, signer.LogFormatPlain.Validate())
+	require.NoError(t, signer.LogFormatJSON.Validate())
+	require.Error(t, signer.LogFormat("logfmt").Validate())
+}
+
+func TestNewSignerLogger(t *testing.T) {
+	prevConfig, prevFlag := config, logFormatFlag
+	t.Cleanup(func() {
+		config, logFormatFlag = prevConfig, prevFlag
+	})
+
+	logFile := filepath.Join(t.TempDir(), "horcrux.log")
+	config.Config.LogFormat = signer.LogFormatJSON
+	config.Config.LogFile = &signer.LogFileConfig{Path: logFile}
+	logFormatFlag = ""
+
+	var out bytes.Buffer
+	logger, closeLogs, err := newSignerLogger(&out)
+	require.NoError(t, err)
+	logger.Info("Signed", "chain_id", "horcrux-1")
+	closeLogs()
+
+	bz, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	require.Contains(t, out.String(), `"chain_id":"horcrux-1"`)
+	require.Contains(t, string(bz), `"chain_id":"horcrux-1"`)
+
+	config.Config.Syslog = &signer.SyslogConfig{Network: "tcp", Address: "127.0.0.1:1"}
+	_, _, err = newSignerLogger(&out)
+	require.ErrorContains(t, err, "failed to connect to syslog")
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/strangelove-ventures/horcrux/signer"
+)
+
+const (
+	flagManifest   = "manifest"
+	flagCosignerID = "cosigner-id"
+)
+
+// initFromManifest writes the config of the cosigner with the shard ID, derived from the cluster
+// manifest file.
+func initFromManifest(out io.Writer, file string, shardID int, overwrite bool) error {
+	if shardID < 1 {
+		return fmt.Errorf("--%s is required with --%s", flagCosignerID, flagManifest)
+	}
+	if _, err := os.Stat(config.ConfigFile); !os.IsNotExist(err) && !overwrite {
+		return fmt.Errorf("%s already exists. Provide the -o flag to overwrite the existing config",
+			config.ConfigFile)
+	}
+
+	m, bz, err := signer.ReadClusterManifest(file)
+	if err != nil {
+		return err
+	}
+	cfg, err := m.ConfigOf(shardID)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(config.StateDir, 0755); err != nil {
+		return err
+	}
+	format, err := signer.ConfigFormatOf(config.ConfigFile)
+	if err != nil {
+		return err
+	}
+	cfgBz, err := cfg.MarshalFormat(format)
+	if err != nil {
+		return err
+	}
+	// JSON has no comments.
+	if format != signer.ConfigFormatJSON {
+		header := fmt.Sprintf("# Derived from the cluster manifest %s (sha256 %s) for cosigner %d.\n"+
+			"# Edit the manifest and derive the config again instead of editing this file.\n",
+			file, signer.ClusterManifestDigest(bz), shardID)
+		cfgBz = append([]byte(header), cfgBz...)
+	}
+	if err := os.WriteFile(config.ConfigFile, cfgBz, 0600); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Successfully derived the configuration of cosigner %d from %s: %s\n",
+		shardID, file, config.ConfigFile)
+	return nil
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/strangelove-ventures/horcrux/signer"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestConfigInitManifest(t *testing.T) {
+	tmp := t.TempDir()
+	manifest := filepath.Join(tmp, "cluster.yaml")
+	require.NoError(t, os.WriteFile(manifest, []byte(`config:
+  thresholdMode:
+    threshold: 2
+    grpcTimeout: 1000ms
+    raftTimeout: 1000ms
+cosigners:
+  - shardID: 1
+    p2pAddr: tcp://horcrux-1:2222
+    chainNodes:
+      - privValAddr: tcp://sentry-1:1234
+  - shardID: 2
+    p2pAddr: tcp://horcrux-2:2222
+    chainNodes:
+      - privValAddr: tcp://sentry-2:1234
+`), 0600))
+
+	for id := 1; id <= 2; id++ {
+		home := filepath.Join(tmp, fmt.Sprintf("horcrux-%d", id))
+		var out bytes.Buffer
+		cmd := rootCmd()
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{
+			"--home", home, "config", "init", "--manifest", manifest, "--cosigner-id", strconv.Itoa(id),
+		})
+		require.NoError(t, cmd.Execute())
+		require.Contains(t, out.String(), "Successfully derived the configuration")
+
+		bz, err := os.ReadFile(filepath.Join(home, "config.yaml"))
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(string(bz), "# Derived from the cluster manifest "+manifest))
+
+		var cfg signer.Config
+		require.NoError(t, yaml.Unmarshal(bz, &cfg))
+		require.NoError(t, cfg.ValidateThresholdModeConfig())
+		require.Len(t, cfg.ThresholdModeConfig.Cosigners, 2)
+		require.Equal(t, fmt.Sprintf("tcp://sentry-%d:1234", id), cfg.ChainNodes[0].PrivValAddr)
+	}
+
+	cmd := rootCmd()
+	cmd.SetArgs([]string{"--home", filepath.Join(tmp, "horcrux-1"), "config", "init", "--manifest", manifest,
+		"--cosigner-id", "1"})
+	require.ErrorContains(t, cmd.Execute(), "already exists")
+
+	cmd = rootCmd()
+	cmd.SetArgs([]string{"--home", filepath.Join(tmp, "horcrux-3"), "config", "init", "--manifest", manifest,
+		"--cosigner-id", "3"})
+	require.EqualError(t, cmd.Execute(), "cosigner with shard ID 3 is not in the cluster manifest")
+
+	cmd = rootCmd()
+	cmd.SetArgs([]string{"--home", filepath.Join(tmp, "horcrux-3"), "config", "init", "--manifest", manifest})
+	require.EqualError(t, cmd.Execute(), "--cosigner-id is required with --manifest")
+}
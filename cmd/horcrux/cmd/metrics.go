@@ -11,12 +11,12 @@ import (
 
 	"github.com/armon/go-metrics"
 	gmprometheus "github.com/armon/go-metrics/prometheus"
-	cometlog "github.com/cometbft/cometbft/libs/log"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/strangelove-ventures/horcrux/signer"
 )
 
 func AddPrometheusMetrics(mux *http.ServeMux, out io.Writer) {
-	logger := cometlog.NewTMLogger(cometlog.NewSyncWriter(out)).With("module", "metrics")
+	logger := newLogger(out).With("module", "metrics")
 
 	// Add metrics from raft's implementation of go-metrics
 	cfg := gmprometheus.DefaultPrometheusOpts
@@ -36,8 +36,15 @@ func AddPrometheusMetrics(mux *http.ServeMux, out io.Writer) {
 }
 
 // EnableDebugAndMetrics - Initialization errors are not fatal, only logged
-func EnableDebugAndMetrics(ctx context.Context, out io.Writer) {
-	logger := cometlog.NewTMLogger(cometlog.NewSyncWriter(out)).With("module", "debugserver")
+func EnableDebugAndMetrics(
+	ctx context.Context,
+	out io.Writer,
+	history *signer.SignatureHistory,
+	levels *signer.LogLevels,
+	health *signer.Health,
+	buildInfo signer.BuildInfo,
+) {
+	logger := newLogger(out).With("module", "debugserver")
 
 	// Configure Shared Debug HTTP Server for pprof and prometheus
 	if len(config.Config.DebugAddr) == 0 {
@@ -61,9 +68,45 @@ func EnableDebugAndMetrics(ctx context.Context, out io.Writer) {
 	// Add prometheus metrics
 	AddPrometheusMetrics(mux, out)
 
+	// Add recent signature history for audits
+	if history != nil {
+		mux.Handle("/signatures", history)
+		logger.Info("Signature History Listening", "address", config.Config.DebugAddr, "path", "/signatures")
+	}
+
+	// Add health report for external monitors
+	mux.Handle("/health", health)
+	logger.Info("Health Listening", "address", config.Config.DebugAddr, "path", "/health")
+
+	// Add liveness and readiness probes for orchestrators
+	mux.HandleFunc("/live", health.ServeLive)
+	mux.HandleFunc("/ready", health.ServeReady)
+	logger.Info("Probes Listening", "address", config.Config.DebugAddr, "paths", "/live,/ready")
+
+	// Add build info for fleet tooling
+	mux.Handle("/build_info", buildInfo)
+	logger.Info("Build Info Listening", "address", config.Config.DebugAddr, "path", "/build_info")
+
+	// Add runtime adjustment of log levels
+	mux.Handle("/debug/log_level", levels)
+	logger.Info("Log Levels Listening", "address", config.Config.DebugAddr, "path", "/debug/log_level")
+
+	// Authenticate requests if configured
+	handler, err := config.Config.DebugServer.Handler(mux)
+	if err != nil {
+		logger.Error("Debug server disabled, failed to configure authentication", "error", err)
+		return
+	}
+	tlsConfig, err := config.Config.DebugServer.TLSConfig()
+	if err != nil {
+		logger.Error("Debug server disabled, failed to configure TLS", "error", err)
+		return
+	}
+
 	// Configure Debug Server Network Parameters
 	srv := &http.Server{
-		Handler:           mux,
+		Handler:           handler,
+		TLSConfig:         tlsConfig,
 		Addr:              config.Config.DebugAddr,
 		ReadTimeout:       1 * time.Second,
 		WriteTimeout:      30 * time.Second,
@@ -73,7 +116,14 @@ func EnableDebugAndMetrics(ctx context.Context, out io.Writer) {
 
 	// Start Debug Server.
 	go func() {
-		if err := srv.ListenAndServe(); err != nil {
+		var err error
+		if tlsConfig != nil {
+			// the certificate is already in the TLS config.
+			err = srv.ListenAndServeTLS("", "")
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil {
 			if errors.Is(err, http.ErrServerClosed) {
 				logger.Info("Debug Server Shutdown Complete")
 				return
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/strangelove-ventures/horcrux/signer"
+	"github.com/strangelove-ventures/horcrux/signer/proto"
+)
+
+// rollingRestartPollInterval is how often the cluster is queried while a cosigner restarts.
+const rollingRestartPollInterval = time.Second
+
+func clusterRollingRestartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rolling-restart",
+		Short: "Restart the cosigners one at a time without dipping below the threshold, e.g. to upgrade",
+		Long: `Restart every cosigner of the cluster one at a time through its admin API, at the
+adminAddr of the cosigner in the config. Each cosigner stops its services and replaces
+its process with a new one of the same command, which picks up an upgraded binary.
+
+The followers are restarted first, by shard ID, and the leader last, after the leadership
+is transferred to another cosigner. The next cosigner is only restarted once the restarted
+one sees the leader, the leader reaches it again and the nonce cache of the leader is
+refilled, so that the cluster never has fewer than the threshold of cosigners ready.
+
+Every cosigner must share the admin API credentials and certificate of this config, and be
+reachable with a full nonce cache before the first restart. The command stops at the first
+cosigner that does not rejoin within the timeout.`,
+		Args:         cobra.NoArgs,
+		Example:      `horcrux cluster rolling-restart --timeout 10m`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			timeout, _ := cmd.Flags().GetDuration(flagTimeout)
+
+			if config.Config.ThresholdModeConfig == nil {
+				return fmt.Errorf("threshold mode configuration is not present in config file")
+			}
+			thresholdCfg := config.Config.ThresholdModeConfig
+			if config.Config.Admin == nil {
+				return fmt.Errorf("rolling restart requires the admin API, add admin to the config")
+			}
+			if len(thresholdCfg.Cosigners)-1 < thresholdCfg.Threshold {
+				return fmt.Errorf("restarting a cosigner of %d would dip below the threshold of %d",
+					len(thresholdCfg.Cosigners), thresholdCfg.Threshold)
+			}
+			for _, c := range thresholdCfg.Cosigners {
+				if c.AdminAddr == "" {
+					return fmt.Errorf("cosigner %d has no adminAddr in the config", c.ShardID)
+				}
+			}
+
+			transport, err := thresholdCfg.CosignerTransport()
+			if err != nil {
+				return err
+			}
+
+			return rollingRestart(cmd.Context(), cmd.OutOrStdout(), thresholdCfg.Cosigners, transport, timeout)
+		},
+	}
+
+	cmd.Flags().Duration(flagTimeout, 5*time.Minute, "how long to wait for each cosigner to restart and rejoin")
+
+	return cmd
+}
+
+// rollingRestart restarts the followers by shard ID, then the leader after transferring the
+// leadership, waiting for each cosigner to rejoin the cluster before the next.
+func rollingRestart(
+	ctx context.Context,
+	out io.Writer,
+	cosigners signer.CosignersConfig,
+	transport signer.CosignerTransport,
+	timeout time.Duration,
+) error {
+	statuses := getClusterStatus(ctx, cosigners, transport)
+	leader, err := clusterReady(statuses)
+	if err != nil {
+		return fmt.Errorf("cluster is not ready for a rolling restart: %w", err)
+	}
+
+	order := make([]int, 0, len(statuses))
+	for _, s := range statuses {
+		if s.ShardID != leader {
+			order = append(order, s.ShardID)
+		}
+	}
+	order = append(order, leader)
+
+	for _, shardID := range order {
+		if err := restartCosigner(ctx, out, cosigners, transport, shardID, timeout); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(out, "Restarted %d cosigners\n", len(order))
+	return nil
+}
+
+// restartCosigner restarts the cosigner with the shard ID, transferring the leadership away first if
+// it is the leader, and waits until it rejoined the cluster.
+func restartCosigner(
+	ctx context.Context,
+	out io.Writer,
+	cosigners signer.CosignersConfig,
+	transport signer.CosignerTransport,
+	shardID int,
+	timeout time.Duration,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	statuses := getClusterStatus(ctx, cosigners, transport)
+	leader, err := clusterReady(statuses)
+	if err != nil {
+		return fmt.Errorf("cluster is not ready to restart cosigner %d: %w", shardID, err)
+	}
+	if leader == shardID {
+		next := slices.IndexFunc(statuses, func(s cosignerStatus) bool { return s.ShardID != shardID })
+		if err := transferClusterLeader(ctx, out, cosigners, transport, statuses[next].ShardID); err != nil {
+			return err
+		}
+	}
+
+	i := slices.IndexFunc(statuses, func(s cosignerStatus) bool { return s.ShardID == shardID })
+	startedAt := statuses[i].Status.StartedAt
+	c := cosigners[slices.IndexFunc(cosigners, func(c signer.CosignerConfig) bool { return c.ShardID == shardID })]
+	admin, err := signer.NewAdminClient(config.Config.Admin, c.AdminAddr)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Restarting cosigner %d\n", shardID)
+	if err := admin.Restart(ctx); err != nil {
+		return fmt.Errorf("failed to restart cosigner %d: %w", shardID, err)
+	}
+
+	ticker := time.NewTicker(rollingRestartPollInterval)
+	defer ticker.Stop()
+	for {
+		err := cosignerRejoined(getClusterStatus(ctx, cosigners, transport), shardID, startedAt)
+		if err == nil {
+			fmt.Fprintf(out, "Cosigner %d restarted and rejoined the cluster\n", shardID)
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("cosigner %d did not rejoin the cluster, stopping the rolling restart: %w", shardID, err)
+		case <-ticker.C:
+		}
+	}
+}
+
+// clusterReady returns the shard ID of the leader if every cosigner is reachable, agrees on the
+// leader, and the nonce cache of the leader is full.
+func clusterReady(statuses []cosignerStatus) (int, error) {
+	for _, s := range statuses {
+		if s.Status == nil {
+			return 0, fmt.Errorf("cosigner %d is unreachable: %s", s.ShardID, s.Error)
+		}
+	}
+	i := slices.IndexFunc(statuses, func(s cosignerStatus) bool { return s.Status.IsLeader })
+	if i == -1 {
+		return 0, fmt.Errorf("no cosigner reports itself as the leader")
+	}
+	leader := statuses[i]
+	if err := clusterLeaderServing(statuses, leader.ShardID); err != nil {
+		return 0, err
+	}
+	if err := leaderNonceCacheFull(leader); err != nil {
+		return 0, err
+	}
+	return leader.ShardID, nil
+}
+
+// cosignerRejoined returns nil once the cosigner with the shard ID restarted after startedAt, in unix
+// nanoseconds, sees the leader, the leader reaches it, and the nonce cache of the leader is refilled.
+func cosignerRejoined(statuses []cosignerStatus, shardID int, startedAt int64) error {
+	i := slices.IndexFunc(statuses, func(s cosignerStatus) bool { return s.ShardID == shardID })
+	switch s := statuses[i]; {
+	case s.Status == nil:
+		return fmt.Errorf("cosigner %d is unreachable: %s", shardID, s.Error)
+	case s.Status.StartedAt == startedAt:
+		return fmt.Errorf("cosigner %d has not restarted yet", shardID)
+	case s.Status.Leader <= 0:
+		return fmt.Errorf("cosigner %d does not see a leader yet", shardID)
+	}
+
+	leader := int(statuses[i].Status.Leader)
+	j := slices.IndexFunc(statuses, func(s cosignerStatus) bool { return s.ShardID == leader })
+	if j == -1 {
+		return fmt.Errorf("cosigner %d sees cosigner %d as the leader, which is not in the config", shardID, leader)
+	}
+	if err := clusterLeaderServing(statuses, leader); err != nil {
+		return err
+	}
+	l := statuses[j]
+	if leader != shardID {
+		p := slices.IndexFunc(l.Status.Peers, func(p *proto.PeerStatus) bool { return int(p.Id) == shardID })
+		if p == -1 || !l.Status.Peers[p].Reachable {
+			return fmt.Errorf("leader %d does not reach cosigner %d yet", leader, shardID)
+		}
+	}
+	return leaderNonceCacheFull(l)
+}
+
+// leaderNonceCacheFull returns nil if the nonce cache of the leader is full.
+func leaderNonceCacheFull(leader cosignerStatus) error {
+	size, target := leader.Status.NonceCacheSize, leader.Status.NonceCacheTargetSize
+	if size < target {
+		return fmt.Errorf("nonce cache of leader %d is refilling: %d/%d", leader.ShardID, size, target)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -23,6 +24,8 @@ func rootCmd() *cobra.Command {
 	cmd.AddCommand(configCmd())
 	cmd.AddCommand(startCmd())
 	cmd.AddCommand(addressCmd())
+	cmd.AddCommand(verifySignatureCmd())
+	cmd.AddCommand(signMessageCmd())
 	cmd.AddCommand(createCosignerEd25519ShardsCmd())
 	cmd.AddCommand(createCosignerECIESShardsCmd())
 
@@ -38,7 +41,15 @@ horcrux create-ecies-shards
 	cmd.AddCommand(rsaCmd)
 	cmd.AddCommand(leaderElectionCmd())
 	cmd.AddCommand(getLeaderCmd())
+	cmd.AddCommand(featureFlagsCmd())
 	cmd.AddCommand(stateCmd())
+	cmd.AddCommand(auditCmd())
+	cmd.AddCommand(approvalCmd())
+	cmd.AddCommand(clusterCmd())
+	cmd.AddCommand(cosignerCmd())
+	cmd.AddCommand(statusCmd())
+	cmd.AddCommand(healthcheckCmd())
+	cmd.AddCommand(benchmarkCmd())
 	cmd.AddCommand(versionCmd())
 
 	cmd.PersistentFlags().StringVar(
@@ -47,6 +58,12 @@ horcrux create-ecies-shards
 		"",
 		"Directory for config and data (default is $HOME/.horcrux)",
 	)
+	cmd.PersistentFlags().StringVar(
+		&logFormatFlag,
+		flagLogFormat,
+		"",
+		"Log format, plain or json (default is the logFormat of the config, or plain)",
+	)
 
 	return cmd
 }
@@ -66,6 +83,8 @@ func init() {
 
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
+	handleInitError(signer.LogFormat(logFormatFlag).Validate())
+
 	var home string
 	if config.HomeDir == "" {
 		userHome, err := homedir.Dir()
@@ -74,24 +93,38 @@ func initConfig() {
 	} else {
 		home = config.HomeDir
 	}
+	configFile, err := signer.FindConfigFile(home)
+	handleInitError(err)
 	config = signer.RuntimeConfig{
 		HomeDir:    home,
-		ConfigFile: filepath.Join(home, "config.yaml"),
+		ConfigFile: configFile,
 		StateDir:   filepath.Join(home, "state"),
-		PidFile:    filepath.Join(home, "horcrux.pid"),
 	}
 	viper.SetConfigFile(config.ConfigFile)
+	// the config is decoded from YAML whatever the format of the config file.
+	viper.SetConfigType("yaml")
 	viper.SetEnvPrefix("horcrux")
 	viper.AutomaticEnv()
-	err := viper.ReadInConfig()
+	bz, err := os.ReadFile(config.ConfigFile)
 	if err != nil {
 		fmt.Println("no config exists at default location", err)
 		return
 	}
-	handleInitError(viper.Unmarshal(&config.Config))
-	bz, err := os.ReadFile(viper.ConfigFileUsed())
+	format, err := signer.ConfigFormatOf(config.ConfigFile)
 	handleInitError(err)
+	if bz, err = signer.ConfigToYAML(format, bz); err != nil {
+		fmt.Println("failed to read config", err)
+		return
+	}
+	// resolve the environment variable and file references before the config is decoded.
+	bz, err = signer.ExpandConfigReferences(bz)
+	handleInitError(err)
+	handleInitError(viper.ReadConfig(bytes.NewReader(bz)))
+	handleInitError(viper.Unmarshal(&config.Config))
 	handleInitError(yaml.Unmarshal(bz, &config.Config))
+	handleInitError(config.Config.LogFormat.Validate())
+	handleInitError(logLevels.SetLevel(config.Config.LogLevel))
+	handleInitError(signer.SetStateEncryption(config.Config.SignStateFileEncryption))
 }
 
 func handleInitError(err error) {
@@ -48,6 +48,7 @@ const (
 	flagShards    = "shards"
 	flagKeyFile   = "key-file"
 	flagChainID   = "chain-id"
+	flagValidator = "validator"
 )
 
 func addOutputDirFlag(cmd *cobra.Command) {
@@ -70,6 +71,7 @@ func createCosignerEd25519ShardsCmd() *cobra.Command {
 			flags := cmd.Flags()
 
 			chainID, _ := flags.GetString(flagChainID)
+			validator, _ := flags.GetString(flagValidator)
 			keyFile, _ := flags.GetString(flagKeyFile)
 			threshold, _ := flags.GetUint8(flagThreshold)
 			shards, _ := flags.GetUint8(flagShards)
@@ -84,6 +86,11 @@ func createCosignerEd25519ShardsCmd() *cobra.Command {
 				return fmt.Errorf("chain-id flag must not be empty")
 			}
 
+			if err := signer.ValidateValidatorName(validator); err != nil {
+				return err
+			}
+			chainID = signer.ValidatorChainID(validator, chainID)
+
 			if threshold == 0 {
 				return fmt.Errorf("threshold flag must be > 0, <= --shards, and > --shards/2")
 			}
@@ -152,6 +159,7 @@ func createCosignerEd25519ShardsCmd() *cobra.Command {
 	_ = cmd.MarkFlagRequired(flagKeyFile)
 	f.String(flagChainID, "", "key shards will sign for this chain ID")
 	_ = cmd.MarkFlagRequired(flagChainID)
+	f.String(flagValidator, "", "key shards will sign for this validator, on a cluster that hosts several validators")
 
 	return cmd
 }
@@ -45,6 +45,28 @@ func TestEd25519Shards(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		{
+			name: "valid threshold and shards of a validator",
+			args: []string{
+				"--chain-id", testChainID,
+				"--validator", "acme",
+				"--key-file", privValidatorKeyFile,
+				"--threshold", "2",
+				"--shards", "3",
+			},
+			expectErr: false,
+		},
+		{
+			name: "invalid validator",
+			args: []string{
+				"--chain-id", testChainID,
+				"--validator", "acme@corp",
+				"--key-file", privValidatorKeyFile,
+				"--threshold", "2",
+				"--shards", "3",
+			},
+			expectErr: true,
+		},
 		{
 			name: "threshold exactly half of shards",
 			args: []string{
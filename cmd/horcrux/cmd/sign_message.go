@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/strangelove-ventures/horcrux/signer"
+)
+
+func signMessageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sign-message chain-id type [file]",
+		Short: "Threshold sign an arbitrary message with the key of a chain",
+		Long: `Threshold sign the payload of a message, e.g. a proof of possession or an ICS key
+assignment message, with the key of the chain through the admin API of the leader running
+with this config, without reconstructing the key.
+
+The file holds the payload, or it is read from stdin if it is - or omitted. The signature is
+not over the payload itself but over its sign bytes, which separate the domain of the message
+from the votes and proposals: the prefix "\x00horcrux/message/v1\x00" followed by the chain ID,
+the type and the payload, each prefixed by its length as a varint. Every cosigner must list the
+type in messageSigning types, or refuses to sign. Requires admin in the config.`,
+		Example: `horcrux sign-message cosmoshub-4 proof-of-possession payload.bin
+echo -n "hello" | horcrux sign-message cosmoshub-4 ics-key-assignment -o json`,
+		SilenceUsage: true,
+		Args:         cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, _ := cmd.Flags().GetString(flagOutput)
+			if output != "table" && output != "json" {
+				return fmt.Errorf("--%s must be table or json", flagOutput)
+			}
+			chainID, msgType := args[0], args[1]
+
+			var payload []byte
+			var err error
+			if len(args) == 3 && args[2] != "-" {
+				payload, err = os.ReadFile(args[2])
+			} else {
+				payload, err = io.ReadAll(cmd.InOrStdin())
+			}
+			if err != nil {
+				return err
+			}
+
+			address, _ := cmd.Flags().GetString(flagAddress)
+			client, err := signer.NewAdminClient(config.Config.Admin, address)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			defer cancel()
+			sig, err := client.SignMessage(ctx, chainID, msgType, payload)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if output == "json" {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(sig)
+			}
+			return printMessageSignature(out, sig)
+		},
+	}
+
+	cmd.Flags().StringP(flagOutput, "o", "table", "output format, table or json")
+	cmd.Flags().String(flagAddress, "", "address of the admin API, defaults to listenAddr of the admin config")
+
+	return cmd
+}
+
+// printMessageSignature prints the signature of the message, in base64, with the sign bytes it is
+// over, in hex.
+func printMessageSignature(out io.Writer, sig *signer.MessageSignature) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Chain ID:\t%s\n", sig.ChainID)
+	fmt.Fprintf(w, "Type:\t%s\n", sig.Type)
+	fmt.Fprintf(w, "Public key:\t%s\n", base64.StdEncoding.EncodeToString(sig.PubKey))
+	fmt.Fprintf(w, "Sign bytes:\t%s\n", hex.EncodeToString(sig.SignBytes))
+	fmt.Fprintf(w, "Signature:\t%s\n", base64.StdEncoding.EncodeToString(sig.Signature))
+	return w.Flush()
+}
@@ -1,11 +1,12 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
-	cometlog "github.com/cometbft/cometbft/libs/log"
 	"github.com/cometbft/cometbft/libs/service"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/cobra"
 	"github.com/strangelove-ventures/horcrux/signer"
 )
@@ -18,12 +19,17 @@ func startCmd() *cobra.Command {
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			out := cmd.OutOrStdout()
-			logger := cometlog.NewTMLogger(cometlog.NewSyncWriter(out))
+			logger, closeLogs, err := newSignerLogger(out)
+			if err != nil {
+				return fmt.Errorf("failed to initialize logging: %w", err)
+			}
+			defer closeLogs()
 
-			err := signer.RequireNotRunning(logger, config.PidFile)
+			lock, err := signer.LockStateDir(config.StateDir)
 			if err != nil {
 				return err
 			}
+			defer lock.Unlock()
 
 			if _, err := legacyConfig(); err == nil {
 				return fmt.Errorf("this is a legacy config. run `horcrux config migrate` to migrate to the latest format")
@@ -34,34 +40,248 @@ func startCmd() *cobra.Command {
 				return err
 			}
 
+			if err := config.ValidateSignStateFiles(); err != nil {
+				var decryptionErr *signer.StateFileDecryptionError
+				if config.Config.SignMode != signer.SignModeThreshold || errors.As(err, &decryptionErr) {
+					return fmt.Errorf("refusing to start with invalid sign state: %w", err)
+				}
+				// cosigners recover a corrupt sign state from their peers when it is loaded.
+				logger.Error("Invalid sign state, it will be recovered from peer cosigners", "error", err)
+			}
+
 			logger.Info(
 				"Horcrux Validator",
 				"mode", config.Config.SignMode,
 				"priv-state-dir", config.StateDir,
 			)
 
+			var errorReporter *signer.ErrorReporter
+			if config.Config.ErrorReporting != nil {
+				errorReporter, err = signer.NewErrorReporter(
+					logger.With("module", "error_reporting"), config.Config.ErrorReporting, Version,
+				)
+				if err != nil {
+					return fmt.Errorf("failed to initialize error reporting: %w", err)
+				}
+				if err := errorReporter.Start(); err != nil {
+					return fmt.Errorf("failed to start error reporting: %w", err)
+				}
+				signer.SetErrorReporter(errorReporter)
+				defer signer.ReportPanic()
+			}
+
 			acceptRisk, _ := cmd.Flags().GetBool(flagAcceptRisk)
 
 			var val signer.PrivValidator
 			var services []service.Service
+			var health *signer.Health
+			var cosigners *signer.CosignerMembership
+			var thresholdVal *signer.ThresholdValidator
+			var signed signer.SignedTimestamps
+			keyTypes := []string{signer.KeyTypeEd25519}
+
+			var auditLog *signer.AuditLog
+			if config.Config.AuditLog != nil {
+				auditLog, err = config.AuditLog()
+				if err != nil {
+					return fmt.Errorf("failed to open audit log, check it with horcrux audit verify: %w", err)
+				}
+			}
+
+			var approvals *signer.AdminApprovals
+			if config.Config.Admin != nil && config.Config.Admin.Approvals != nil {
+				approvals, err = signer.NewAdminApprovals(
+					logger.With("module", "admin"), config.Config.Admin.Approvals, auditLog,
+				)
+				if err != nil {
+					return fmt.Errorf("failed to initialize admin approvals: %w", err)
+				}
+			}
+
+			// the kill switch is loaded before the cosigner gRPC server starts, as it may be engaged.
+			kill, err := signer.NewKillSwitchValidator(nil, config.StateDir)
+			if err != nil {
+				return fmt.Errorf("failed to load kill switch: %w", err)
+			}
+			if status := kill.Status(); status.Engaged {
+				logger.Error("Kill switch is engaged, signing is disabled until it is re-armed through the admin API",
+					"since", status.Since, "reason", status.Reason)
+			}
 
 			switch config.Config.SignMode {
 			case signer.SignModeThreshold:
-				services, val, err = NewThresholdValidator(cmd.Context(), logger)
+				services, thresholdVal, err = NewThresholdValidator(cmd.Context(), logger, kill, approvals)
 				if err != nil {
 					return err
 				}
+				val, signed = thresholdVal, thresholdVal
+				health = signer.NewHealth(config.Config.SignMode, thresholdVal)
+				cosigners, err = signer.NewCosignerMembership(logger.With("module", "cosigners"), &config, thresholdVal)
+				if err != nil {
+					return fmt.Errorf("failed to initialize cosigner membership: %w", err)
+				}
+				keyTypes = append(keyTypes, thresholdVal.CosignerSecurityKeyType())
 			case signer.SignModeSingle:
-				val, err = NewSingleSignerValidator(out, acceptRisk)
+				singleVal, err := NewSingleSignerValidator(out, acceptRisk)
 				if err != nil {
 					return err
 				}
+				val, signed = singleVal, singleVal
+				health = signer.NewHealth(config.Config.SignMode, nil)
 			default:
 				panic(fmt.Errorf("unexpected sign mode: %s", config.Config.SignMode))
 			}
 
+			if config.Config.SignLock != nil {
+				lock, err := signer.NewEtcdSignLock(
+					logger.With("module", signer.LogModuleSignLock), config.Config.SignLock,
+				)
+				if err != nil {
+					return fmt.Errorf("failed to initialize sign lock: %w", err)
+				}
+				if err := lock.Start(); err != nil {
+					return fmt.Errorf("failed to start sign lock: %w", err)
+				}
+				services = append(services, lock)
+				if thresholdVal != nil {
+					thresholdVal.SetSignLock(lock)
+				}
+				val = signer.NewSignLockValidator(val, lock)
+			}
+
+			if config.Config.SignScheduler != nil {
+				val, err = signer.NewSignScheduler(val, config.Config.SignScheduler)
+				if err != nil {
+					return fmt.Errorf("failed to initialize sign scheduler: %w", err)
+				}
+			}
+
+			if len(config.Config.ChainRPC) > 0 {
+				val, err = signer.NewChainTipGuard(logger, val, config.Config.ChainRPC)
+				if err != nil {
+					return fmt.Errorf("failed to initialize chain tip guard: %w", err)
+				}
+			}
+
+			if config.Config.Chains.ChecksTimestamps() {
+				val = signer.NewTimestampSkewGuard(val, config.Config.Chains, signed)
+			}
+
+			allowlist := signer.NewChainAllowlistValidator(val, config.Config.ChainIDAllowlist())
+			val = allowlist
+
+			if config.Config.Chains.HasSignPolicies() {
+				val = signer.NewSignPolicyGuard(val, config.Config.Chains)
+			}
+
+			halt := signer.NewHaltHeightValidator(val, config.Config.Chains)
+			val = halt
+
+			val = kill.Wrap(val)
+
+			pause := signer.NewPauseValidator(val)
+			val = signer.NewHealthValidator(pause, health)
+			if thresholdVal != nil {
+				thresholdVal.SetSigningPause(pause)
+			}
+
+			var notifier *signer.AlertNotifier
+			if config.Config.Alerts != nil {
+				notifier, err = signer.NewAlertNotifier(logger.With("module", "alerts"), config.Config.Alerts, health)
+				if err != nil {
+					return fmt.Errorf("failed to initialize alerts: %w", err)
+				}
+				if err := notifier.Start(); err != nil {
+					return fmt.Errorf("failed to start alerts: %w", err)
+				}
+				services = append(services, notifier)
+				val = signer.NewAlertValidator(val, notifier)
+			}
+
+			if config.Config.ChainRPC.WatchMissedBlocks() {
+				watcher, err := signer.NewMissedBlockWatcher(
+					logger.With("module", "missed_blocks"), val, config.Config.ChainRPC, notifier,
+				)
+				if err != nil {
+					return fmt.Errorf("failed to initialize missed block watcher: %w", err)
+				}
+				if err := watcher.Start(); err != nil {
+					return fmt.Errorf("failed to start missed block watcher: %w", err)
+				}
+				services = append(services, watcher)
+				val = signer.NewMissedBlockValidator(val, watcher)
+			}
+
+			if config.Config.Events != nil {
+				bus, err := signer.NewEventBus(logger.With("module", "events"), config.Config.Events, health)
+				if err != nil {
+					return fmt.Errorf("failed to initialize events: %w", err)
+				}
+				if err := bus.Start(); err != nil {
+					return fmt.Errorf("failed to start events: %w", err)
+				}
+				services = append(services, bus)
+				val = signer.NewEventValidator(val, bus)
+			}
+
+			if errorReporter != nil {
+				services = append(services, errorReporter)
+				val = signer.NewErrorReportingValidator(val, errorReporter)
+			}
+
+			var history *signer.SignatureHistory
+			if config.Config.SignatureHistory != nil {
+				history = signer.NewSignatureHistory(config.Config.SignatureHistory.Size)
+				val = signer.NewSignatureHistoryValidator(val, history)
+			}
+
+			if config.Config.SignDecisionLog != nil {
+				decisionLog, interrupted, err := config.SignDecisionLog()
+				if err != nil {
+					return fmt.Errorf("failed to open sign decision log: %w", err)
+				}
+				for _, d := range interrupted {
+					logger.Error(
+						"Sign request was interrupted by a restart",
+						"chain_id", d.ChainID,
+						"height", d.Height,
+						"round", d.Round,
+						"step", d.Step,
+					)
+					if err := decisionLog.Interrupted(d); err != nil {
+						return fmt.Errorf("failed to record interrupted sign request: %w", err)
+					}
+				}
+				val = signer.NewSignDecisionLogValidator(logger, val, decisionLog)
+			}
+
+			if auditLog != nil {
+				val = signer.NewAuditLogValidator(logger, val, auditLog)
+			}
+
+			limiter := signer.NewSignRateLimiter(config.Config.SignRateLimit)
+
 			if config.Config.GRPCAddr != "" {
 				grpcServer := signer.NewRemoteSignerGRPCServer(logger, val, config.Config.GRPCAddr)
+				tlsConfig, err := config.Config.GRPCTLS.TLSConfig()
+				if err != nil {
+					return err
+				}
+				if cfg := config.Config.GRPCTLS; cfg != nil && cfg.ACME != nil {
+					acmeManager, err := signer.NewACMEManager(
+						logger.With("module", "acme"), cfg.ACME, config.ACMECacheDir(),
+					)
+					if err != nil {
+						return fmt.Errorf("failed to initialize acme: %w", err)
+					}
+					if err := acmeManager.Start(); err != nil {
+						return fmt.Errorf("failed to start acme: %w", err)
+					}
+					services = append(services, acmeManager)
+					tlsConfig = acmeManager.TLSConfig()
+				}
+				grpcServer.SetTLSConfig(tlsConfig)
+				grpcServer.SetRateLimiter(limiter)
 				services = append(services, grpcServer)
 
 				if err := grpcServer.Start(); err != nil {
@@ -69,14 +289,115 @@ func startCmd() *cobra.Command {
 				}
 			}
 
-			go EnableDebugAndMetrics(cmd.Context(), out)
+			if config.Config.Backup != nil {
+				backupService, err := signer.NewStateBackupService(logger, config)
+				if err != nil {
+					return fmt.Errorf("failed to initialize state backups: %w", err)
+				}
+				if err := backupService.Start(); err != nil {
+					return fmt.Errorf("failed to start state backups: %w", err)
+				}
+				services = append(services, backupService)
+			}
+
+			if config.Config.Statsd != nil {
+				exporter, err := signer.NewStatsdExporter(
+					logger.With("module", "statsd"), config.Config.Statsd, prometheus.DefaultGatherer,
+				)
+				if err != nil {
+					return fmt.Errorf("failed to initialize statsd exporter: %w", err)
+				}
+				if err := exporter.Start(); err != nil {
+					return fmt.Errorf("failed to start statsd exporter: %w", err)
+				}
+				services = append(services, exporter)
+			}
+
+			if config.Config.Profiling != nil {
+				profiler, err := signer.NewProfiler(
+					logger.With("module", "profiling"), config.Config.Profiling, health, Version,
+				)
+				if err != nil {
+					return fmt.Errorf("failed to initialize profiling: %w", err)
+				}
+				if err := profiler.Start(); err != nil {
+					return fmt.Errorf("failed to start profiling: %w", err)
+				}
+				services = append(services, profiler)
+			}
+
+			buildInfo, err := signer.NewBuildInfo(&config.Config, Version, Commit, keyTypes)
+			if err != nil {
+				return fmt.Errorf("failed to compute build info: %w", err)
+			}
+			buildInfo.SetMetric()
+
+			go EnableDebugAndMetrics(cmd.Context(), out, history, logLevels, health, buildInfo)
 
-			services, err = signer.StartRemoteSigners(services, logger, val, config.Config.Nodes())
+			codecs, err := config.Config.ChainSignBytesCodecs()
+			if err != nil {
+				return err
+			}
+
+			var remoteSigners *signer.RemoteSigners
+			services, remoteSigners, err = signer.StartRemoteSigners(
+				services, logger.With("module", signer.LogModuleRemoteSigner), val, codecs, config.Config.AllChainNodes(),
+				health.HoldChainNodes(), limiter,
+			)
 			if err != nil {
 				return fmt.Errorf("failed to start remote signer(s): %w", err)
 			}
 
-			signer.WaitAndTerminate(logger, services, config.PidFile)
+			restart := signer.NewSignerRestart()
+			if config.Config.Admin != nil {
+				admin, err := signer.NewAdminAPI(
+					logger.With("module", "admin"), config.Config.Admin,
+					health, logLevels, pause, remoteSigners, thresholdVal, cosigners,
+				)
+				if err != nil {
+					return fmt.Errorf("failed to initialize admin API: %w", err)
+				}
+				admin.SetRestart(restart)
+				admin.SetHaltHeights(halt)
+				admin.SetKillSwitch(kill)
+				if approvals != nil {
+					admin.SetApprovals(approvals)
+				}
+				if err := admin.Start(); err != nil {
+					return fmt.Errorf("failed to start admin API: %w", err)
+				}
+				services = append(services, admin)
+			}
+
+			reloader := signer.NewConfigReloader(
+				logger.With("module", "config"), config.ConfigFile, config.Config, logLevels, remoteSigners, thresholdVal,
+			)
+			reloader.SetChainAllowlist(allowlist)
+			if err := reloader.Start(); err != nil {
+				return fmt.Errorf("failed to start config reloader: %w", err)
+			}
+			services = append(services, reloader)
+
+			systemd, err := signer.NewSystemdNotifier(logger.With("module", "systemd"), health)
+			if err != nil {
+				return fmt.Errorf("failed to initialize systemd notifications: %w", err)
+			}
+			if systemd != nil {
+				systemd.SetRestart(restart)
+				if err := systemd.Start(); err != nil {
+					return fmt.Errorf("failed to start systemd notifications: %w", err)
+				}
+				services = append(services, systemd)
+			}
+
+			if signer.WaitAndTerminate(logger, services, restart) {
+				// the deferred unlock and close do not run once the process is replaced.
+				if err := lock.Unlock(); err != nil {
+					return fmt.Errorf("failed to unlock the state directory to restart: %w", err)
+				}
+				closeLogs()
+				return restart.Exec()
+			}
 
 			return nil
 		},
@@ -2,12 +2,18 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -34,6 +40,9 @@ func stateCmd() *cobra.Command {
 	cmd.AddCommand(showStateCmd())
 	cmd.AddCommand(setStateCmd())
 	cmd.AddCommand(importStateCmd())
+	cmd.AddCommand(exportStateCmd())
+	cmd.AddCommand(restoreStateCmd())
+	cmd.AddCommand(auditStateCmd())
 
 	return cmd
 }
@@ -53,12 +62,12 @@ func showStateCmd() *cobra.Command {
 				return fmt.Errorf("%s does not exist, initialize config with horcrux config init and try again", config.HomeDir)
 			}
 
-			pv, err := signer.LoadSignState(config.PrivValStateFile(chainID))
+			pv, err := config.LoadSignState(chainID, signer.SignStateKindPrivVal)
 			if err != nil {
 				return err
 			}
 
-			cs, err := signer.LoadSignState(config.CosignerStateFile(chainID))
+			cs, err := config.LoadSignState(chainID, signer.SignStateKindCosigner)
 			if err != nil {
 				return err
 			}
@@ -74,17 +83,20 @@ func showStateCmd() *cobra.Command {
 }
 
 func setStateCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:          "set chain-id height",
-		Aliases:      []string{"s"},
-		Short:        "Set the height for the sign state of a specific chain-id",
+	cmd := &cobra.Command{
+		Use:     "set chain-id height",
+		Aliases: []string{"s"},
+		Short:   "Set the height for the sign state of a specific chain-id",
+		Long: "Set the height for the sign state of a specific chain-id.\n" +
+			"If the approvals of the admin config require approvals of set_sign_state, they are passed with\n" +
+			"--approval, approved with horcrux approval sign SET " +
+			"'/sign_state?chain_id=<chain-id>&height=<height>&round=0&step=0'.",
 		Args:         cobra.ExactArgs(2),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			chainID := args[0]
 
 			out := cmd.OutOrStdout()
-			logger := cometlog.NewTMLogger(cometlog.NewSyncWriter(out))
 
 			if _, err := os.Stat(config.HomeDir); os.IsNotExist(err) {
 				cmd.SilenceUsage = false
@@ -93,16 +105,18 @@ func setStateCmd() *cobra.Command {
 
 			// Resetting the priv_validator_state.json should only be allowed if the
 			// signer is not running.
-			if err := signer.RequireNotRunning(logger, config.PidFile); err != nil {
+			lock, err := signer.LockStateDir(config.StateDir)
+			if err != nil {
 				return err
 			}
+			defer lock.Unlock()
 
-			pv, err := signer.LoadOrCreateSignState(config.PrivValStateFile(chainID))
+			pv, err := config.LoadOrCreateSignState(chainID, signer.SignStateKindPrivVal)
 			if err != nil {
 				return err
 			}
 
-			cs, err := signer.LoadOrCreateSignState(config.CosignerStateFile(chainID))
+			cs, err := config.LoadOrCreateSignState(chainID, signer.SignStateKindCosigner)
 			if err != nil {
 				return err
 			}
@@ -113,6 +127,10 @@ func setStateCmd() *cobra.Command {
 				return err
 			}
 
+			if err := approveSignState(cmd, chainID, signer.HRSKey{Height: height}); err != nil {
+				return err
+			}
+
 			fmt.Fprintf(out, "Setting height %d\n", height)
 
 			pv.NoncePublic, cs.NoncePublic = nil, nil
@@ -136,17 +154,59 @@ func setStateCmd() *cobra.Command {
 			return nil
 		},
 	}
+
+	cmd.Flags().StringArray(flagApproval, nil, "approval of horcrux approval sign, repeated for each operator")
+
+	return cmd
 }
 
 func importStateCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:     "import chain-id",
+	cmd := &cobra.Command{
+		Use:     "import [chain-id]",
 		Aliases: []string{"i"},
 		Short: "Read the old priv_validator_state.json and set the height, round and step" +
 			"(good for migrations but NOT shared state update)",
-		Args:         cobra.ExactArgs(1),
+		Long: "Read the old priv_validator_state.json and set the height, round and step " +
+			"(good for migrations but NOT shared state update).\n" +
+			"With --file, merge a sign state export from horcrux state export instead. For each chain the " +
+			"greater height, round and step of the local and exported sign state is kept, so sign state never regresses.\n" +
+			"--format reads the export in the horcrux sign state interchange format, or the sign state of a single " +
+			"chain, the chain-id, from a priv_validator_state.json or a tmkms consensus state file.\n" +
+			"Without --file, if the approvals of the admin config require approvals of set_sign_state, they are " +
+			"passed with --approval, approved with horcrux approval sign SET " +
+			"'/sign_state?chain_id=<chain-id>&height=<height>&round=<round>&step=<step>' of the pasted state.",
+		Example: `horcrux state import --file sign-state.json
+horcrux state import --file interchange.json --format interchange
+horcrux state import cosmoshub-4 --file ~/.gaia/data/priv_validator_state.json --format priv-validator
+horcrux state import cosmoshub-4 --file /var/lib/tmkms/state/cosmoshub-4-consensus.json --format tmkms`,
+		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if file, _ := cmd.Flags().GetString(flagFile); file != "" {
+				format, _ := cmd.Flags().GetString(flagFormat)
+				var chainID string
+				switch format {
+				case signer.SignStateFormatHorcrux, signer.SignStateFormatInterchange:
+					if len(args) != 0 {
+						cmd.SilenceUsage = false
+						return fmt.Errorf("chain-id cannot be used with --%s in the %s format", flagFile, format)
+					}
+				case signer.SignStateFormatPrivValidator, signer.SignStateFormatTMKMS:
+					if len(args) != 1 {
+						cmd.SilenceUsage = false
+						return fmt.Errorf("the %s format requires chain-id", format)
+					}
+					chainID = args[0]
+				default:
+					cmd.SilenceUsage = false
+					return fmt.Errorf("--%s must be one of %v", flagFormat, signer.SignStateFormats)
+				}
+				return importSignStateExport(cmd, file, format, chainID)
+			}
+			if len(args) != 1 {
+				cmd.SilenceUsage = false
+				return fmt.Errorf("requires chain-id, or --%s", flagFile)
+			}
 			chainID := args[0]
 
 			if _, err := os.Stat(config.HomeDir); os.IsNotExist(err) {
@@ -155,22 +215,23 @@ func importStateCmd() *cobra.Command {
 			}
 
 			out := cmd.OutOrStdout()
-			logger := cometlog.NewTMLogger(cometlog.NewSyncWriter(out))
 
 			// Resetting the priv_validator_state.json should only be allowed if the
 			// signer is not running.
-			if err := signer.RequireNotRunning(logger, config.PidFile); err != nil {
+			lock, err := signer.LockStateDir(config.StateDir)
+			if err != nil {
 				return err
 			}
+			defer lock.Unlock()
 
-			// Recreate privValStateFile if necessary
-			pv, err := signer.LoadOrCreateSignState(config.PrivValStateFile(chainID))
+			// Recreate the priv validator sign state if necessary
+			pv, err := config.LoadOrCreateSignState(chainID, signer.SignStateKindPrivVal)
 			if err != nil {
 				return err
 			}
 
-			// shareStateFile does not exist during default config init, so create if necessary
-			cs, err := signer.LoadOrCreateSignState(config.CosignerStateFile(chainID))
+			// the share sign state does not exist during default config init, so create if necessary
+			cs, err := config.LoadOrCreateSignState(chainID, signer.SignStateKindCosigner)
 			if err != nil {
 				return err
 			}
@@ -210,6 +271,9 @@ func importStateCmd() *cobra.Command {
 				Signature: nil,
 				SignBytes: nil,
 			}
+			if err := approveSignState(cmd, chainID, signState.HRSKey()); err != nil {
+				return err
+			}
 			fmt.Printf("Saving New Sign State: \n"+
 				"  Height:    %v\n"+
 				"  Round:     %v\n"+
@@ -230,6 +294,252 @@ func importStateCmd() *cobra.Command {
 			return nil
 		},
 	}
+
+	cmd.Flags().String(flagFile, "", "sign state export to merge, or - for stdin")
+	cmd.Flags().String(flagFormat, signer.SignStateFormatHorcrux,
+		"format of --file: horcrux, interchange, priv-validator or tmkms")
+	cmd.Flags().StringArray(flagApproval, nil, "approval of horcrux approval sign, repeated for each operator")
+
+	return cmd
+}
+
+// approveSignState verifies the --approval flags of setting the sign state of the chain to the HRS,
+// if the approvals of the admin config require approvals of set_sign_state, and records the decision
+// in the audit log. The state directory must be locked, so that the signer does not write the log.
+func approveSignState(cmd *cobra.Command, chainID string, hrs signer.HRSKey) error {
+	return approveStateOperation(cmd, signer.AdminOperationSetSignState, signer.SignStateApprovalRequest(chainID, hrs))
+}
+
+// approveStateRestore verifies the --approval flags of restoring the state backup at the source URL
+// over the existing files, like approveSignState.
+func approveStateRestore(cmd *cobra.Command, source string) error {
+	return approveStateOperation(cmd, signer.AdminOperationRestoreState, signer.StateRestoreApprovalRequest(source))
+}
+
+func approveStateOperation(cmd *cobra.Command, operation, request string) error {
+	if config.Config.Admin == nil || config.Config.Admin.Approvals == nil {
+		return nil
+	}
+	auditLog, err := config.AuditLog()
+	if err != nil {
+		return fmt.Errorf("failed to open audit log, check it with horcrux audit verify: %w", err)
+	}
+	defer auditLog.Close()
+
+	approvals, err := signer.NewAdminApprovals(cometlog.NewNopLogger(), config.Config.Admin.Approvals, auditLog)
+	if err != nil {
+		return err
+	}
+	flags, _ := cmd.Flags().GetStringArray(flagApproval)
+	_, err = approvals.Approve(operation, request, flags)
+	return err
+}
+
+func exportStateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export [chain-id...]",
+		Short: "Export the sign state of all chains, or the specified chains, as JSON",
+		Long: "Export the sign state of all chains, or the specified chains, as JSON.\n" +
+			"The export can be merged into another signer with horcrux state import --file.\n" +
+			"--format exports the greatest height, round and step of each chain in the horcrux sign state " +
+			"interchange format, or of a single chain as a priv_validator_state.json or a tmkms consensus state file.",
+		Example: `horcrux state export --file sign-state.json
+horcrux state export --format interchange --file interchange.json
+horcrux state export cosmoshub-4 --format priv-validator --file priv_validator_state.json`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, _ := cmd.Flags().GetString(flagFormat)
+			if !slices.Contains(signer.SignStateFormats, format) {
+				cmd.SilenceUsage = false
+				return fmt.Errorf("--%s must be one of %v", flagFormat, signer.SignStateFormats)
+			}
+
+			if _, err := os.Stat(config.HomeDir); os.IsNotExist(err) {
+				return fmt.Errorf("%s does not exist, initialize config with horcrux config init and try again", config.HomeDir)
+			}
+
+			chainIDs := args
+			if len(chainIDs) == 0 {
+				var err error
+				chainIDs, err = config.SignStateChainIDs()
+				if err != nil {
+					return err
+				}
+			}
+
+			export, err := config.ExportSignStates(chainIDs)
+			if err != nil {
+				return err
+			}
+
+			var bz []byte
+			if format == signer.SignStateFormatHorcrux {
+				bz, err = json.MarshalIndent(export, "", "  ")
+			} else {
+				bz, err = signer.MarshalSignStateInterchange(signer.NewSignStateInterchange(export), format)
+			}
+			if err != nil {
+				return err
+			}
+
+			file, _ := cmd.Flags().GetString(flagFile)
+			if file == "" || file == "-" {
+				fmt.Fprintln(cmd.OutOrStdout(), string(bz))
+				return nil
+			}
+			return os.WriteFile(file, bz, 0600)
+		},
+	}
+
+	cmd.Flags().String(flagFile, "", "file to write the export to. Defaults to stdout")
+	cmd.Flags().String(flagFormat, signer.SignStateFormatHorcrux,
+		"format of the export: horcrux, interchange, priv-validator or tmkms")
+
+	return cmd
+}
+
+func importSignStateExport(cmd *cobra.Command, file, format, chainID string) error {
+	out := cmd.OutOrStdout()
+
+	if _, err := os.Stat(config.HomeDir); os.IsNotExist(err) {
+		return fmt.Errorf("%s does not exist, initialize config with horcrux config init and try again", config.HomeDir)
+	}
+
+	// Importing sign state should only be allowed if the signer is not running.
+	lock, err := signer.LockStateDir(config.StateDir)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	var bz []byte
+	if file == "-" {
+		bz, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		bz, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return err
+	}
+
+	export := new(signer.SignStateExport)
+	if format == signer.SignStateFormatHorcrux {
+		if err := json.Unmarshal(bz, export); err != nil {
+			return fmt.Errorf("failed to parse sign state export: %w", err)
+		}
+	} else {
+		ic, err := signer.ParseSignStateInterchange(bz, format, chainID)
+		if err != nil {
+			return err
+		}
+		if export, err = ic.SignStateExport(); err != nil {
+			return err
+		}
+	}
+
+	results, err := config.ImportSignStates(export)
+	for _, r := range results {
+		action := "Kept local"
+		if r.Updated {
+			action = "Imported"
+		}
+		fmt.Fprintf(out, "%s %s sign state for %s: local %d.%d.%d, imported %d.%d.%d\n",
+			action, r.Kind, r.ChainID,
+			r.Local.Height, r.Local.Round, r.Local.Step,
+			r.Imported.Height, r.Imported.Round, r.Imported.Step,
+		)
+	}
+	return err
+}
+
+const (
+	flagFile     = "file"
+	flagFormat   = "format"
+	flagFrom     = "from"
+	flagEndpoint = "endpoint"
+	flagRegion   = "region"
+	flagForce    = "force"
+	flagMaxLag   = "max-lag"
+	flagApproval = "approval"
+)
+
+func restoreStateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore the config, sign state and keys from an encrypted state backup",
+		Long: "Restore the config, sign state and keys from an encrypted state backup.\n" +
+			"If --from is a prefix rather than a backup object, the latest backup under the prefix is restored.\n" +
+			"Object store credentials are read from AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.",
+		Example:      `horcrux state restore --from s3://my-bucket/horcrux/cosigner-1 --key-file ~/backup.key`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+
+			// Restoring the sign state should only be allowed if the signer is not running.
+			lock, err := signer.LockStateDir(config.StateDir)
+			if err != nil {
+				return err
+			}
+			defer lock.Unlock()
+
+			from, _ := cmd.Flags().GetString(flagFrom)
+			keyFile, _ := cmd.Flags().GetString(flagKeyFile)
+			endpoint, _ := cmd.Flags().GetString(flagEndpoint)
+			region, _ := cmd.Flags().GetString(flagRegion)
+			force, _ := cmd.Flags().GetBool(flagForce)
+
+			if backup := config.Config.Backup; backup != nil {
+				if keyFile == "" {
+					keyFile = backup.EncryptionKeyFile
+				}
+				if endpoint == "" {
+					endpoint = backup.Endpoint
+				}
+				if region == "" {
+					region = backup.Region
+				}
+			}
+			if keyFile == "" {
+				cmd.SilenceUsage = false
+				return fmt.Errorf("--%s is required", flagKeyFile)
+			}
+
+			key, err := signer.LoadStateBackupKey(keyFile)
+			if err != nil {
+				return err
+			}
+
+			source, backup, err := signer.DownloadStateBackup(cmd.Context(), from, endpoint, region)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Restoring state backup %s\n", source)
+
+			if force {
+				if err := approveStateRestore(cmd, source); err != nil {
+					return err
+				}
+			}
+
+			restored, err := config.RestoreStateBackup(key, backup, force)
+			for _, f := range restored {
+				fmt.Fprintf(out, "  Restored %s\n", f)
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().String(flagFrom, "", "s3:// or gs:// URL of the backup, or of the prefix to restore the latest backup from")
+	cmd.Flags().String(flagKeyFile, "", "file containing the base64 encoded backup encryption key. "+
+		"Defaults to backup.encryptionKeyFile from the config")
+	cmd.Flags().String(flagEndpoint, "", "object store endpoint override, e.g. for S3 compatible stores")
+	cmd.Flags().String(flagRegion, "", "object store region")
+	cmd.Flags().Bool(flagForce, false, "overwrite existing files. Sign state is never restored over a newer local sign state")
+	cmd.Flags().StringArray(flagApproval, nil, "approval of horcrux approval sign of --force, repeated for each operator")
+	_ = cmd.MarkFlagRequired(flagFrom)
+
+	return cmd
 }
 
 func printSignState(out io.Writer, ss *signer.SignState) {
@@ -248,3 +558,132 @@ func printSignState(out io.Writer, ss *signer.SignState) {
 		fmt.Fprintln(out, "  SignBytes:", ss.SignBytes)
 	}
 }
+
+// cosignerSignStateAudit is the share sign state reported by a cosigner.
+type cosignerSignStateAudit struct {
+	shardID int
+	address string
+	hrs     *signer.HRSKey
+	err     error
+
+	status string
+}
+
+func auditStateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit [chain-id]",
+		Short: "Compare the share sign state of all cosigners for a specific chain-id",
+		Long: "Query the share sign state of every cosigner in the config over the cosigner API and\n" +
+			"report cosigners which are unreachable, or behind or ahead of the others by more than --max-lag blocks.\n" +
+			"Exits with an error if any cosigner is unreachable or diverges.",
+		Args:         cobra.ExactArgs(1),
+		Example:      `horcrux state audit cosmoshub-4`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chainID := args[0]
+			maxLag, _ := cmd.Flags().GetInt64(flagMaxLag)
+
+			if config.Config.ThresholdModeConfig == nil {
+				return fmt.Errorf("threshold mode configuration is not present in config file")
+			}
+			thresholdCfg := config.Config.ThresholdModeConfig
+
+			transport, err := thresholdCfg.CosignerTransport()
+			if err != nil {
+				return err
+			}
+
+			audits := make([]cosignerSignStateAudit, len(thresholdCfg.Cosigners))
+			var wg sync.WaitGroup
+			for i, c := range thresholdCfg.Cosigners {
+				audits[i] = cosignerSignStateAudit{shardID: c.ShardID, address: c.P2PAddr}
+				wg.Add(1)
+				go func(a *cosignerSignStateAudit) {
+					defer wg.Done()
+					rc, err := signer.NewRemoteCosigner(a.shardID, a.address, transport)
+					if err != nil {
+						a.err = err
+						return
+					}
+					ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+					defer cancel()
+					a.hrs, a.err = rc.GetSignStateOfKind(ctx, chainID, signer.SignStateKindCosigner)
+				}(&audits[i])
+			}
+			wg.Wait()
+
+			diverged := auditSignStates(audits, maxLag)
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Share sign state of chain %s:\n", chainID)
+			w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "SHARD\tADDRESS\tHEIGHT\tROUND\tSTEP\tSTATUS")
+			for _, a := range audits {
+				height, round, step := "-", "-", "-"
+				if a.hrs != nil {
+					height = strconv.FormatInt(a.hrs.Height, 10)
+					round = strconv.FormatInt(a.hrs.Round, 10)
+					step = strconv.Itoa(int(a.hrs.Step))
+				}
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", a.shardID, a.address, height, round, step, a.status)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+
+			if diverged > 0 {
+				return fmt.Errorf("%d of %d cosigners are unreachable or diverge", diverged, len(audits))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Int64(flagMaxLag, 2, "number of blocks a cosigner may be behind or ahead of the others")
+
+	return cmd
+}
+
+// auditSignStates sets the status of each audit by comparing its height to the median height
+// of the reachable cosigners, and returns the number of cosigners that are unreachable or diverge.
+func auditSignStates(audits []cosignerSignStateAudit, maxLag int64) (diverged int) {
+	var heights []int64
+	for _, a := range audits {
+		if a.err == nil {
+			var height int64
+			if a.hrs != nil {
+				height = a.hrs.Height
+			}
+			heights = append(heights, height)
+		}
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+
+	for i := range audits {
+		a := &audits[i]
+		if a.err != nil {
+			a.status = fmt.Sprintf("unreachable: %v", a.err)
+			diverged++
+			continue
+		}
+
+		var height int64
+		if a.hrs != nil {
+			height = a.hrs.Height
+		}
+		median := heights[len(heights)/2]
+
+		switch {
+		case height < median-maxLag:
+			a.status = fmt.Sprintf("behind by %d blocks", median-height)
+			diverged++
+		case height > median+maxLag:
+			a.status = fmt.Sprintf("ahead by %d blocks", height-median)
+			diverged++
+		case a.hrs == nil:
+			a.status = "ok, no sign state"
+		default:
+			a.status = "ok"
+		}
+	}
+	return diverged
+}
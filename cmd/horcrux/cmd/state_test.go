@@ -1,9 +1,17 @@
 package cmd
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
 	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -85,3 +93,316 @@ func TestStateSetCmd(t *testing.T) {
 		})
 	}
 }
+
+func TestStateCmdSignStateStore(t *testing.T) {
+	tmpHome := t.TempDir()
+	tmpConfig := filepath.Join(tmpHome, ".horcrux")
+	stateDir := filepath.Join(tmpConfig, "state")
+
+	chainID := "horcrux-1"
+
+	cmd := rootCmd()
+	cmd.SetOutput(io.Discard)
+	cmd.SetArgs([]string{
+		"--home", tmpConfig,
+		"config", "init",
+		"-n", "tcp://10.168.0.1:1234",
+		"-t", "2",
+		"-c", "tcp://10.168.1.1:2222,tcp://10.168.1.2:2222,tcp://10.168.1.3:2222",
+	})
+	require.NoError(t, cmd.Execute())
+	config.Config.SignState = &signer.SignStateStoreConfig{Type: signer.SignStateStoreSQLite}
+	require.NoError(t, config.WriteConfigFile())
+
+	cmd = setStateCmd()
+	cmd.SetOutput(io.Discard)
+	cmd.SetArgs([]string{chainID, "100"})
+	require.NoError(t, cmd.Execute())
+
+	// the sign state is set in the configured store, not in the sign state files.
+	for _, kind := range []signer.SignStateKind{signer.SignStateKindPrivVal, signer.SignStateKindCosigner} {
+		store, err := config.SignStateStore(chainID, kind)
+		require.NoError(t, err)
+		ssc, err := store.GetHRS()
+		require.NoError(t, err)
+		require.Equal(t, int64(100), ssc.Height)
+	}
+	require.NoFileExists(t, filepath.Join(stateDir, chainID+"_priv_validator_state.json"))
+	require.NoFileExists(t, filepath.Join(stateDir, chainID+"_share_sign_state.json"))
+
+	var out strings.Builder
+	cmd = showStateCmd()
+	cmd.SetOutput(&out)
+	cmd.SetArgs([]string{chainID})
+	require.NoError(t, cmd.Execute())
+	require.Contains(t, out.String(), "100")
+}
+
+func TestStateSetCmdApprovals(t *testing.T) {
+	tmpHome := t.TempDir()
+	tmpConfig := filepath.Join(tmpHome, ".horcrux")
+
+	chainID := "horcrux-1"
+
+	cmd := rootCmd()
+	cmd.SetOutput(io.Discard)
+	cmd.SetArgs([]string{
+		"--home", tmpConfig,
+		"config", "init",
+		"-n", "tcp://10.168.0.1:1234",
+		"-t", "2",
+		"-c", "tcp://10.168.1.1:2222,tcp://10.168.1.2:2222,tcp://10.168.1.3:2222",
+	})
+	require.NoError(t, cmd.Execute())
+
+	keys := make(map[string]ed25519.PrivateKey)
+	var operators []signer.AdminOperator
+	for _, name := range []string{"alice", "bob"} {
+		pub, key, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		keys[name] = key
+		operators = append(operators, signer.AdminOperator{Name: name, PubKey: base64.StdEncoding.EncodeToString(pub)})
+	}
+	config.Config.Admin = &signer.AdminAPIConfig{
+		ListenAddr:        "127.0.0.1:6100",
+		DebugServerConfig: signer.DebugServerConfig{BearerTokenFile: filepath.Join(tmpHome, "token")},
+		Approvals:         &signer.AdminApprovalConfig{Operators: operators},
+	}
+	config.Config.AuditLog = &signer.AuditLogConfig{}
+	require.NoError(t, config.WriteConfigFile())
+
+	setState := func(height string, approvals ...string) error {
+		cmd := setStateCmd()
+		cmd.SetOutput(io.Discard)
+		args := []string{chainID, height}
+		for _, approval := range approvals {
+			args = append(args, "--"+flagApproval, approval)
+		}
+		cmd.SetArgs(args)
+		return cmd.Execute()
+	}
+	approve := func(name string, height int64) string {
+		request := signer.SignStateApprovalRequest(chainID, signer.HRSKey{Height: height})
+		return signer.NewAdminApproval(name, keys[name], request, time.Now().Add(5*time.Minute))
+	}
+
+	require.NoError(t, setState("100", approve("alice", 100), approve("bob", 100)))
+	pv, err := config.LoadSignState(chainID, signer.SignStateKindPrivVal)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), pv.Height)
+
+	// setting the sign state requires the approvals of the height, and each approval is used once,
+	// also by another process.
+	require.ErrorContains(t, setState("200"), "requires the approvals of 2 distinct operators, got 0")
+	require.ErrorContains(t, setState("200", approve("alice", 100), approve("bob", 100)), "does not verify")
+	aliceApproval, bobApproval := approve("alice", 200), approve("bob", 200)
+	require.NoError(t, setState("200", aliceApproval, bobApproval))
+	require.ErrorContains(t, setState("200", aliceApproval, bobApproval), "was already used")
+	pv, err = config.LoadSignState(chainID, signer.SignStateKindPrivVal)
+	require.NoError(t, err)
+	require.Equal(t, int64(200), pv.Height)
+
+	head, err := signer.VerifyAuditLog(config.AuditLogFile())
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), head.Seq)
+}
+
+func TestStateRestoreCmdApprovals(t *testing.T) {
+	tmpHome := t.TempDir()
+	tmpConfig := filepath.Join(tmpHome, ".horcrux")
+
+	cmd := rootCmd()
+	cmd.SetOutput(io.Discard)
+	cmd.SetArgs([]string{
+		"--home", tmpConfig,
+		"config", "init",
+		"-n", "tcp://10.168.0.1:1234",
+		"-t", "2",
+		"-c", "tcp://10.168.1.1:2222,tcp://10.168.1.2:2222,tcp://10.168.1.3:2222",
+	})
+	require.NoError(t, cmd.Execute())
+
+	keys := make(map[string]ed25519.PrivateKey)
+	var operators []signer.AdminOperator
+	for _, name := range []string{"alice", "bob"} {
+		pub, key, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		keys[name] = key
+		operators = append(operators, signer.AdminOperator{Name: name, PubKey: base64.StdEncoding.EncodeToString(pub)})
+	}
+	config.Config.Admin = &signer.AdminAPIConfig{
+		ListenAddr:        "127.0.0.1:6100",
+		DebugServerConfig: signer.DebugServerConfig{BearerTokenFile: filepath.Join(tmpHome, "token")},
+		Approvals:         &signer.AdminApprovalConfig{Operators: operators},
+	}
+	config.Config.AuditLog = &signer.AuditLogConfig{}
+	require.NoError(t, config.WriteConfigFile())
+
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	keyFile := filepath.Join(tmpHome, "backup.key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key)), 0600))
+	backup, err := config.CreateStateBackup(key, false)
+	require.NoError(t, err)
+
+	const object = "cosigner-1/20231018T120000Z.hbk"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bucket/"+object {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(backup)
+	}))
+	defer srv.Close()
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	source := "s3://bucket/" + object
+	restore := func(args ...string) error {
+		cmd := restoreStateCmd()
+		cmd.SetOutput(io.Discard)
+		cmd.SetArgs(append([]string{"--from", source, "--endpoint", srv.URL, "--key-file", keyFile}, args...))
+		return cmd.Execute()
+	}
+	approve := func(name string) string {
+		request := signer.StateRestoreApprovalRequest(source)
+		return signer.NewAdminApproval(name, keys[name], request, time.Now().Add(5*time.Minute))
+	}
+
+	// without --force nothing is overwritten, so no approvals are required.
+	require.ErrorContains(t, restore(), "already exists, use force to overwrite")
+
+	// overwriting the existing files requires the approvals of the backup.
+	require.ErrorContains(t, restore("--force"), "requires the approvals of 2 distinct operators, got 0")
+	require.NoError(t, restore("--force", "--"+flagApproval, approve("alice"), "--"+flagApproval, approve("bob")))
+
+	head, err := signer.VerifyAuditLog(config.AuditLogFile())
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), head.Seq)
+}
+
+func TestStateExportImportCmd(t *testing.T) {
+	tmpHome := t.TempDir()
+	tmpConfig := filepath.Join(tmpHome, ".horcrux")
+	stateDir := filepath.Join(tmpConfig, "state")
+	exportFile := filepath.Join(tmpHome, "export.json")
+
+	chainID := "horcrux-1"
+
+	cmd := rootCmd()
+	cmd.SetOutput(io.Discard)
+	cmd.SetArgs([]string{
+		"--home", tmpConfig,
+		"config", "init",
+		"-n", "tcp://10.168.0.1:1234",
+		"-t", "2",
+		"-c", "tcp://10.168.1.1:2222,tcp://10.168.1.2:2222,tcp://10.168.1.3:2222",
+	})
+	require.NoError(t, cmd.Execute())
+
+	cmd = setStateCmd()
+	cmd.SetOutput(io.Discard)
+	cmd.SetArgs([]string{chainID, "100"})
+	require.NoError(t, cmd.Execute())
+
+	cmd = exportStateCmd()
+	cmd.SetOutput(io.Discard)
+	cmd.SetArgs([]string{"--file", exportFile})
+	require.NoError(t, cmd.Execute())
+
+	// move the local state ahead of the export.
+	cmd = setStateCmd()
+	cmd.SetOutput(io.Discard)
+	cmd.SetArgs([]string{chainID, "200"})
+	require.NoError(t, cmd.Execute())
+
+	cmd = importStateCmd()
+	cmd.SetOutput(io.Discard)
+	cmd.SetArgs([]string{"--file", exportFile})
+	require.NoError(t, cmd.Execute())
+
+	ss, err := signer.LoadSignState(filepath.Join(stateDir, chainID+"_priv_validator_state.json"))
+	require.NoError(t, err)
+	require.Equal(t, int64(200), ss.Height)
+
+	cmd = importStateCmd()
+	cmd.SetOutput(io.Discard)
+	cmd.SetArgs([]string{chainID, "--file", exportFile})
+	require.Error(t, cmd.Execute())
+}
+
+func TestStateInterchangeCmd(t *testing.T) {
+	tmpHome := t.TempDir()
+	tmpConfig := filepath.Join(tmpHome, ".horcrux")
+	stateDir := filepath.Join(tmpConfig, "state")
+	exportFile := filepath.Join(tmpHome, "priv_validator_state.json")
+
+	chainID := "horcrux-1"
+
+	cmd := rootCmd()
+	cmd.SetOutput(io.Discard)
+	cmd.SetArgs([]string{
+		"--home", tmpConfig,
+		"config", "init",
+		"-n", "tcp://10.168.0.1:1234",
+		"-t", "2",
+		"-c", "tcp://10.168.1.1:2222,tcp://10.168.1.2:2222,tcp://10.168.1.3:2222",
+	})
+	require.NoError(t, cmd.Execute())
+
+	cmd = setStateCmd()
+	cmd.SetOutput(io.Discard)
+	cmd.SetArgs([]string{chainID, "100"})
+	require.NoError(t, cmd.Execute())
+
+	cmd = exportStateCmd()
+	cmd.SetOutput(io.Discard)
+	cmd.SetArgs([]string{chainID, "--format", signer.SignStateFormatPrivValidator, "--file", exportFile})
+	require.NoError(t, cmd.Execute())
+
+	bz, err := os.ReadFile(exportFile)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"height": "100", "round": 0, "step": 0}`, string(bz))
+
+	// a priv_validator_state.json of a node that signed further.
+	require.NoError(t, os.WriteFile(exportFile, []byte(`{"height": "150", "round": 2, "step": 3}`), 0600))
+
+	cmd = importStateCmd()
+	cmd.SetOutput(io.Discard)
+	cmd.SetArgs([]string{"--format", signer.SignStateFormatPrivValidator, "--file", exportFile})
+	require.ErrorContains(t, cmd.Execute(), "requires chain-id")
+
+	cmd = importStateCmd()
+	cmd.SetOutput(io.Discard)
+	cmd.SetArgs([]string{chainID, "--format", signer.SignStateFormatPrivValidator, "--file", exportFile})
+	require.NoError(t, cmd.Execute())
+
+	for _, kind := range []string{"priv_validator", "share_sign"} {
+		ss, err := signer.LoadSignState(filepath.Join(stateDir, chainID+"_"+kind+"_state.json"))
+		require.NoError(t, err)
+		require.Equal(t, signer.HRSKey{Height: 150, Round: 2, Step: 3}, ss.HRSKey(), kind)
+	}
+
+	cmd = exportStateCmd()
+	cmd.SetOutput(io.Discard)
+	cmd.SetArgs([]string{"--format", "eip-3076"})
+	require.Error(t, cmd.Execute())
+}
+
+func TestAuditSignStates(t *testing.T) {
+	audits := []cosignerSignStateAudit{
+		{shardID: 1, hrs: &signer.HRSKey{Height: 100, Round: 0, Step: 3}},
+		{shardID: 2, hrs: &signer.HRSKey{Height: 99, Round: 0, Step: 2}},
+		{shardID: 3, hrs: &signer.HRSKey{Height: 50, Round: 0, Step: 3}},
+		{shardID: 4, hrs: &signer.HRSKey{Height: 200, Round: 0, Step: 3}},
+		{shardID: 5, err: errors.New("connection refused")},
+	}
+
+	require.Equal(t, 3, auditSignStates(audits, 2))
+	require.Equal(t, "ok", audits[0].status)
+	require.Equal(t, "ok", audits[1].status)
+	require.Equal(t, "behind by 50 blocks", audits[2].status)
+	require.Equal(t, "ahead by 100 blocks", audits[3].status)
+	require.Equal(t, "unreachable: connection refused", audits[4].status)
+}
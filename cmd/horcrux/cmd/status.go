@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/strangelove-ventures/horcrux/signer"
+)
+
+const flagAddress = "address"
+
+// signerStatus is the status of a running signer, as served by its admin API.
+type signerStatus struct {
+	Health     *signer.HealthReport  `json:"health"`
+	Signing    *signer.SigningStatus `json:"signing"`
+	ChainNodes []string              `json:"chain_nodes"`
+}
+
+func statusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Print the status of the running signer",
+		Long: `Query the admin API of the signer running with this config for its sign mode,
+whether signing is paused, the chain nodes it connects to, the last height/round/step
+it signed of each chain and, in threshold mode, the leader, the peer cosigners and
+their health. Requires admin in the config.`,
+		Args: cobra.NoArgs,
+		Example: `horcrux status
+horcrux status --output json
+horcrux status --address 10.0.0.1:6100`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, _ := cmd.Flags().GetString(flagOutput)
+			if output != "table" && output != "json" {
+				return fmt.Errorf("--%s must be table or json", flagOutput)
+			}
+			address, _ := cmd.Flags().GetString(flagAddress)
+
+			client, err := signer.NewAdminClient(config.Config.Admin, address)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+			defer cancel()
+
+			var status signerStatus
+			if status.Health, err = client.Status(ctx); err != nil {
+				return err
+			}
+			if status.Signing, err = client.Signing(ctx); err != nil {
+				return err
+			}
+			if status.ChainNodes, err = client.ChainNodes(ctx); err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if output == "json" {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(status)
+			}
+			return printSignerStatus(out, status)
+		},
+	}
+
+	cmd.Flags().StringP(flagOutput, "o", "table", "output format, table or json")
+	cmd.Flags().String(flagAddress, "", "address of the admin API, defaults to listenAddr of the admin config")
+
+	return cmd
+}
+
+// printSignerStatus prints the signer, its peers in threshold mode, its chain nodes and its chains.
+func printSignerStatus(out io.Writer, status signerStatus) error {
+	health := status.Health
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Sign mode:\t%s\n", health.SignMode)
+	signing := "active"
+	if status.Signing.Paused {
+		signing = "paused"
+		if status.Signing.Cluster {
+			signing += " (cluster)"
+		}
+		if status.Signing.Since != nil {
+			signing += " since " + status.Signing.Since.UTC().Format(time.RFC3339)
+		}
+	}
+	fmt.Fprintf(w, "Signing:\t%s\n", signing)
+	if th := health.Threshold; th != nil {
+		fmt.Fprintf(w, "Cosigner:\t%d\n", th.CosignerID)
+		leader := strconv.Itoa(th.Leader)
+		if th.IsLeader {
+			leader += " (self)"
+		}
+		fmt.Fprintf(w, "Leader:\t%s\n", leader)
+		if th.SplitBrain != nil {
+			fmt.Fprintf(w, "Split brain:\tcosigner %d claims the leadership too, fenced: %t\n",
+				th.SplitBrain.Leader, th.SplitBrain.Fenced)
+		}
+		if th.NonceCache.TargetSize > 0 {
+			fmt.Fprintf(w, "Nonce cache:\t%d/%d\n", th.NonceCache.Size, th.NonceCache.TargetSize)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if th := health.Threshold; th != nil && len(th.Peers) > 0 {
+		fmt.Fprintln(out, "\nPeers:")
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SHARD\tADDRESS\tREACHABLE\tRTT\tSCORE\tPROTOCOL")
+		for _, p := range th.Peers {
+			reachable, rtt := "-", "-"
+			if p.Reachable != nil {
+				reachable = strconv.FormatBool(*p.Reachable)
+				if *p.Reachable {
+					rtt = fmt.Sprintf("%.1fms", p.RTTMilliseconds)
+				}
+			}
+			score := "-"
+			if p.Score != nil {
+				score = fmt.Sprintf("%.2f", *p.Score)
+			}
+			if p.Quarantined {
+				score += " (quarantined)"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%d\n", p.ID, p.Address, reachable, rtt, score, p.ProtocolVersion)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(out, "\nChain nodes:")
+	if len(status.ChainNodes) == 0 {
+		fmt.Fprintln(out, "none")
+	}
+	for _, address := range status.ChainNodes {
+		fmt.Fprintln(out, address)
+	}
+
+	if len(health.Chains) == 0 {
+		return nil
+	}
+	chainIDs := make([]string, 0, len(health.Chains))
+	for chainID := range health.Chains {
+		chainIDs = append(chainIDs, chainID)
+	}
+	sort.Strings(chainIDs)
+
+	fmt.Fprintln(out, "\nLast signed height/round/step:")
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHAIN\tHRS\tSIGNED")
+	for _, chainID := range chainIDs {
+		c := health.Chains[chainID]
+		fmt.Fprintf(w, "%s\t%d/%d/%d\t%s ago\n", chainID, c.Height, c.Round, c.Step,
+			time.Duration(c.SecondsSinceLastSign*float64(time.Second)).Round(time.Second))
+	}
+	return w.Flush()
+}
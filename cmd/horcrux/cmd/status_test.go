@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/strangelove-ventures/horcrux/signer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintSignerStatus(t *testing.T) {
+	reachable, unreachable := true, false
+	score := 0.42
+	since := time.Date(2023, 10, 18, 12, 0, 0, 0, time.UTC)
+	status := signerStatus{
+		Health: &signer.HealthReport{
+			SignMode: signer.SignModeThreshold,
+			Threshold: &signer.ThresholdHealth{
+				CosignerID: 1, Leader: 1, IsLeader: true,
+				Peers: []signer.PeerHealth{
+					{ID: 2, Address: "tcp://cosigner-2:2222", Reachable: &reachable, RTTMilliseconds: 1.5, ProtocolVersion: 1},
+					{ID: 3, Address: "tcp://cosigner-3:2222", Reachable: &unreachable, Score: &score, Quarantined: true},
+				},
+				NonceCache: signer.NonceCacheHealth{Size: 80, TargetSize: 100},
+			},
+			Chains: map[string]signer.ChainHealth{
+				"cosmoshub-4": {Height: 100, Round: 0, Step: 3, SecondsSinceLastSign: 5.2},
+			},
+		},
+		Signing:    &signer.SigningStatus{Paused: true, Since: &since, Cluster: true},
+		ChainNodes: []string{"tcp://sentry-1:1234", "tcp://sentry-2:1234"},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, printSignerStatus(&out, status))
+	require.Equal(t, `Sign mode:    threshold
+Signing:      paused (cluster) since 2023-10-18T12:00:00Z
+Cosigner:     1
+Leader:       1 (self)
+Nonce cache:  80/100
+
+Peers:
+SHARD  ADDRESS                REACHABLE  RTT    SCORE               PROTOCOL
+2      tcp://cosigner-2:2222  true       1.5ms  -                   1
+3      tcp://cosigner-3:2222  false      -      0.42 (quarantined)  0
+
+Chain nodes:
+tcp://sentry-1:1234
+tcp://sentry-2:1234
+
+Last signed height/round/step:
+CHAIN        HRS      SIGNED
+cosmoshub-4  100/0/3  5s ago
+`, out.String())
+
+	out.Reset()
+	require.NoError(t, printSignerStatus(&out, signerStatus{
+		Health:  &signer.HealthReport{SignMode: signer.SignModeSingle},
+		Signing: &signer.SigningStatus{},
+	}))
+	require.Equal(t, `Sign mode:  single
+Signing:    active
+
+Chain nodes:
+none
+`, out.String())
+}
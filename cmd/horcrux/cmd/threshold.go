@@ -14,9 +14,13 @@ import (
 
 const maxWaitForSameBlockAttempts = 3
 
+// NewThresholdValidator returns the threshold validator of the config and starts its leader election,
+// whose cosigner gRPC server already enforces the kill switch and the approvals of the re-arm.
 func NewThresholdValidator(
 	ctx context.Context,
 	logger cometlog.Logger,
+	kill *signer.KillSwitchValidator,
+	approvals *signer.AdminApprovals,
 ) ([]cometservice.Service, *signer.ThresholdValidator, error) {
 	if err := config.Config.ValidateThresholdModeConfig(); err != nil {
 		return nil, nil, err
@@ -24,24 +28,24 @@ func NewThresholdValidator(
 
 	thresholdCfg := config.Config.ThresholdModeConfig
 
+	signer.SoftwareVersion = Version
+	signer.SoftwareCommit = Commit
+
 	remoteCosigners := make([]signer.Cosigner, 0, len(thresholdCfg.Cosigners)-1)
 
 	var p2pListen string
 
-	var security signer.CosignerSecurity
-	var eciesErr error
-	security, eciesErr = config.CosignerSecurityECIES()
-	if eciesErr != nil {
-		var rsaErr error
-		security, rsaErr = config.CosignerSecurityRSA()
-		if rsaErr != nil {
-			return nil, nil, fmt.Errorf("failed to initialize cosigner ECIES / RSA security : %w / %w", eciesErr, rsaErr)
-		}
+	security, err := config.CosignerSecurity()
+	if err != nil {
+		return nil, nil, err
 	}
 
+	// Validated prior in ValidateThresholdModeConfig
+	transport, _ := thresholdCfg.CosignerTransport()
+
 	for _, c := range thresholdCfg.Cosigners {
 		if c.ShardID != security.GetID() {
-			rc, err := signer.NewRemoteCosigner(c.ShardID, c.P2PAddr)
+			rc, err := signer.NewRemoteCosigner(c.ShardID, c.P2PAddr, transport)
 			if err != nil {
 				return nil, nil, fmt.Errorf("failed to initialize remote cosigner: %w", err)
 			}
@@ -67,23 +71,11 @@ func NewThresholdValidator(
 
 	// Validated prior in ValidateThresholdModeConfig
 	grpcTimeout, _ := time.ParseDuration(thresholdCfg.GRPCTimeout)
-	raftTimeout, _ := time.ParseDuration(thresholdCfg.RaftTimeout)
-
-	raftDir := filepath.Join(config.HomeDir, "raft")
-	if err := os.MkdirAll(raftDir, 0700); err != nil {
-		return nil, nil, fmt.Errorf("error creating raft directory: %w", err)
-	}
-
-	// RAFT node ID is the cosigner ID
-	nodeID := fmt.Sprint(security.GetID())
 
-	// Start RAFT store listener
-	raftStore := signer.NewRaftStore(nodeID,
-		raftDir, p2pListen, raftTimeout, logger, localCosigner, remoteCosigners)
-	if err := raftStore.Start(); err != nil {
-		return nil, nil, fmt.Errorf("error starting raft store: %w", err)
+	leader, err := newClusterLeader(logger, thresholdCfg, localCosigner, remoteCosigners, p2pListen, transport)
+	if err != nil {
+		return nil, nil, err
 	}
-	services := []cometservice.Service{raftStore}
 
 	val := signer.NewThresholdValidator(
 		logger,
@@ -93,14 +85,119 @@ func NewThresholdValidator(
 		maxWaitForSameBlockAttempts,
 		localCosigner,
 		remoteCosigners,
-		raftStore,
+		leader,
 	)
 
-	raftStore.SetThresholdValidator(val)
+	// the approvals are set first, so that the peers cannot re-arm the kill switch without them.
+	val.SetAdminApprovals(approvals)
+	val.SetKillSwitch(kill)
+
+	leader.SetThresholdValidator(val)
+	if err := leader.Start(); err != nil {
+		return nil, nil, fmt.Errorf("error starting leader election: %w", err)
+	}
+	services := []cometservice.Service{leader}
+
+	watermark, err := thresholdCfg.WatermarkStore(logger.With("module", signer.LogModuleWatermark))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize watermark store: %w", err)
+	}
+	if watermark != nil {
+		val.SetWatermarkStore(watermark)
+		if s, ok := watermark.(cometservice.Service); ok {
+			if err := s.Start(); err != nil {
+				return nil, nil, fmt.Errorf("failed to start watermark store: %w", err)
+			}
+			services = append(services, s)
+		}
+	}
 
 	if err := val.Start(ctx); err != nil {
 		return nil, nil, fmt.Errorf("failed to start threshold validator: %w", err)
 	}
 
+	priority, err := signer.NewLeaderPriority(
+		logger.With("module", signer.LogModuleLeaderElection), thresholdCfg, leader, val)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize leader priority: %w", err)
+	}
+	if priority != nil {
+		if err := priority.Start(); err != nil {
+			return nil, nil, fmt.Errorf("failed to start leader priority: %w", err)
+		}
+		services = append(services, priority)
+	}
+
 	return services, val, nil
 }
+
+// clusterLeader is the leader election of the cosigners, which serves the cosigner gRPC server.
+type clusterLeader interface {
+	signer.ClusterLeader
+	cometservice.Service
+	SetThresholdValidator(thresholdValidator *signer.ThresholdValidator)
+}
+
+// newClusterLeader returns the leader election of the cosigners selected in the config.
+func newClusterLeader(
+	logger cometlog.Logger,
+	thresholdCfg *signer.ThresholdModeConfig,
+	localCosigner *signer.LocalCosigner,
+	remoteCosigners []signer.Cosigner,
+	p2pListen string,
+	transport signer.CosignerTransport,
+) (clusterLeader, error) {
+	switch thresholdCfg.LeaderElectionType() {
+	case signer.LeaderElectionKubernetes:
+		leader, err := signer.NewKubernetesLeaseLeader(
+			logger.With("module", signer.LogModuleLeaderElection),
+			thresholdCfg.LeaderElection.Kubernetes,
+			localCosigner,
+			remoteCosigners,
+			p2pListen,
+			transport,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize kubernetes leader election: %w", err)
+		}
+		return leader, nil
+	case signer.LeaderElectionEtcd:
+		leader, err := signer.NewEtcdLeader(
+			logger.With("module", signer.LogModuleLeaderElection),
+			thresholdCfg.LeaderElection.Etcd,
+			localCosigner,
+			p2pListen,
+			transport,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize etcd leader election: %w", err)
+		}
+		return leader, nil
+	case signer.LeaderElectionLeaderless:
+		return signer.NewLeaderlessCoordinator(
+			logger.With("module", signer.LogModuleLeaderElection),
+			localCosigner,
+			remoteCosigners,
+			p2pListen,
+			transport,
+		), nil
+	default:
+		// Validated prior in ValidateThresholdModeConfig
+		raftTimeout, _ := time.ParseDuration(thresholdCfg.RaftTimeout)
+		raftTimings, _ := thresholdCfg.RaftTimings()
+
+		raftDir := filepath.Join(config.HomeDir, "raft")
+		if err := os.MkdirAll(raftDir, 0700); err != nil {
+			return nil, fmt.Errorf("error creating raft directory: %w", err)
+		}
+
+		// RAFT node ID is the cosigner ID
+		nodeID := fmt.Sprint(localCosigner.GetID())
+
+		raftStore := signer.NewRaftStore(nodeID,
+			raftDir, p2pListen, raftTimeout, logger.With("module", signer.LogModuleRaft), localCosigner, remoteCosigners)
+		raftStore.SetTimings(raftTimings)
+		raftStore.SetTransport(transport)
+		return raftStore, nil
+	}
+}
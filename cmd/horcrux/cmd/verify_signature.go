@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	cometjson "github.com/cometbft/cometbft/libs/json"
+	cometproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	comet "github.com/cometbft/cometbft/types"
+	"github.com/spf13/cobra"
+	"github.com/strangelove-ventures/horcrux/signer"
+)
+
+const (
+	flagSignBytes = "sign-bytes"
+	flagSignature = "signature"
+)
+
+type VerifySignatureCmdOutput struct {
+	ChainID    string
+	HexAddress string
+	signer.SignatureVerification
+}
+
+func verifySignatureCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify-signature chain-id [file]",
+		Short: "Verify a signed vote or proposal against the validator key and sign state",
+		Long: `Verify the signature of a signed vote or proposal against the consensus public key of the
+validator of the chain, and compare its height, round and step with the sign state of the
+validator, to find out whether the signer made it.
+
+The file holds the vote or proposal in the JSON format of CometBFT, or is read from stdin if
+it is - or omitted. Raw sign bytes can be verified instead with --sign-bytes, in hex, and
+--signature, in base64.
+
+The status is one of:
+  signed         the signature is of the last sign bytes of the sign state
+  conflicting    the sign state has different sign bytes at the same height, round and step
+  behind         the signature is at or below the sign state, which does not keep its sign bytes
+  ahead          the signature is above the sign state, the signer did not make it
+  no_sign_state  the chain has no sign state to compare with
+  invalid        the signature does not verify against the public key, the command fails`,
+		Example: `horcrux verify-signature cosmoshub-4 vote.json
+horcrux verify-signature cosmoshub-4 --sign-bytes 6A080111... --signature 3q2+7w== -o table`,
+		SilenceUsage: true,
+		Args:         cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, _ := cmd.Flags().GetString(flagOutput)
+			if output != "table" && output != "json" {
+				return fmt.Errorf("--%s must be table or json", flagOutput)
+			}
+			chainID := args[0]
+
+			codec, err := config.SignBytesCodec(chainID)
+			if err != nil {
+				return err
+			}
+
+			var signBytes, signature []byte
+			signBytesHex, _ := cmd.Flags().GetString(flagSignBytes)
+			signatureBase64, _ := cmd.Flags().GetString(flagSignature)
+			if signBytesHex != "" || signatureBase64 != "" {
+				if len(args) == 2 {
+					cmd.SilenceUsage = false
+					return fmt.Errorf("file cannot be used with --%s and --%s", flagSignBytes, flagSignature)
+				}
+				signBytes, signature, err = decodeSignBytes(signBytesHex, signatureBase64)
+			} else {
+				var bz []byte
+				if len(args) == 2 && args[1] != "-" {
+					bz, err = os.ReadFile(args[1])
+				} else {
+					bz, err = io.ReadAll(cmd.InOrStdin())
+				}
+				if err != nil {
+					return err
+				}
+				signBytes, signature, err = signedMessageSignBytes(codec, chainID, bz)
+			}
+			if err != nil {
+				return err
+			}
+
+			pubKey, err := consensusPubKey(chainID)
+			if err != nil {
+				return err
+			}
+			signState, err := config.ReadSignState(chainID, signer.SignStateKindPrivVal)
+			if err != nil {
+				return err
+			}
+
+			v, err := signer.VerifySignature(codec, pubKey, signBytes, signature, signState)
+			if err != nil {
+				return err
+			}
+			res := VerifySignatureCmdOutput{
+				ChainID:               chainID,
+				HexAddress:            pubKey.Address().String(),
+				SignatureVerification: v,
+			}
+
+			if output == "table" {
+				err = printSignatureVerification(cmd.OutOrStdout(), res)
+			} else {
+				var jsonOut []byte
+				jsonOut, err = json.Marshal(res)
+				fmt.Fprintln(cmd.OutOrStdout(), string(jsonOut))
+			}
+			if err != nil {
+				return err
+			}
+			if !v.Valid {
+				return fmt.Errorf("signature does not verify against the public key of validator %s", res.HexAddress)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagSignBytes, "", "sign bytes to verify, in hex")
+	cmd.Flags().String(flagSignature, "", "signature of the sign bytes, in base64")
+	cmd.Flags().StringP(flagOutput, "o", "table", "output format, table or json")
+
+	return cmd
+}
+
+// decodeSignBytes decodes the raw sign bytes in hex and their signature in base64.
+func decodeSignBytes(signBytesHex, signatureBase64 string) ([]byte, []byte, error) {
+	if signBytesHex == "" || signatureBase64 == "" {
+		return nil, nil, fmt.Errorf("--%s and --%s must be set together", flagSignBytes, flagSignature)
+	}
+	signBytes, err := hex.DecodeString(signBytesHex)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid --%s, must be hex: %w", flagSignBytes, err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(signatureBase64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid --%s, must be base64: %w", flagSignature, err)
+	}
+	return signBytes, signature, nil
+}
+
+// signedMessageSignBytes returns the sign bytes and the signature of the vote or proposal in the
+// JSON format of CometBFT.
+func signedMessageSignBytes(codec signer.SignBytesCodec, chainID string, bz []byte) ([]byte, []byte, error) {
+	var msg struct {
+		Type cometproto.SignedMsgType `json:"type"`
+	}
+	if err := json.Unmarshal(bz, &msg); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse signed vote or proposal: %w", err)
+	}
+
+	switch msg.Type {
+	case cometproto.PrevoteType, cometproto.PrecommitType:
+		var vote comet.Vote
+		if err := cometjson.Unmarshal(bz, &vote); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse signed vote: %w", err)
+		}
+		if len(vote.Signature) == 0 {
+			return nil, nil, fmt.Errorf("vote is not signed")
+		}
+		return codec.VoteSignBytes(chainID, vote.ToProto()), vote.Signature, nil
+	case cometproto.ProposalType:
+		var proposal comet.Proposal
+		if err := cometjson.Unmarshal(bz, &proposal); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse signed proposal: %w", err)
+		}
+		if len(proposal.Signature) == 0 {
+			return nil, nil, fmt.Errorf("proposal is not signed")
+		}
+		return codec.ProposalSignBytes(chainID, proposal.ToProto()), proposal.Signature, nil
+	default:
+		return nil, nil, fmt.Errorf("unexpected type %d, must be a vote (1, 2) or a proposal (32)", msg.Type)
+	}
+}
+
+// printSignatureVerification prints the result of the verification of a signature as a table.
+func printSignatureVerification(out io.Writer, res VerifySignatureCmdOutput) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Chain ID:\t%s\n", res.ChainID)
+	fmt.Fprintf(w, "Validator address:\t%s\n", res.HexAddress)
+	fmt.Fprintf(w, "Type:\t%s\n", res.Type)
+	fmt.Fprintf(w, "Height/round/step:\t%d/%d/%d\n", res.Height, res.Round, res.Step)
+	fmt.Fprintf(w, "Timestamp:\t%s\n", res.Timestamp.Format(time.RFC3339Nano))
+	fmt.Fprintf(w, "Valid signature:\t%t\n", res.Valid)
+	if res.SignState != nil {
+		fmt.Fprintf(w, "Sign state:\t%d/%d/%d\n", res.SignState.Height, res.SignState.Round, res.SignState.Step)
+	} else {
+		fmt.Fprintf(w, "Sign state:\tnone\n")
+	}
+	fmt.Fprintf(w, "Status:\t%s\n", res.Status.Description())
+	return w.Flush()
+}
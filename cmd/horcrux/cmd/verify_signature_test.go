@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	cometjson "github.com/cometbft/cometbft/libs/json"
+	cometprivval "github.com/cometbft/cometbft/privval"
+	cometproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	comet "github.com/cometbft/cometbft/types"
+	"github.com/strangelove-ventures/horcrux/signer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifySignatureCmd(t *testing.T) {
+	tmpHome := t.TempDir()
+	tmpConfig := filepath.Join(tmpHome, ".horcrux")
+	chainID := "horcrux-1"
+
+	cmd := rootCmd()
+	cmd.SetOutput(io.Discard)
+	cmd.SetArgs([]string{
+		"--home", tmpConfig,
+		"config", "init",
+		"-m", "single",
+		"-n", "tcp://10.168.0.1:1234",
+	})
+	require.NoError(t, cmd.Execute())
+
+	pv := cometprivval.GenFilePV(
+		filepath.Join(tmpConfig, chainID+"_priv_validator_key.json"),
+		filepath.Join(tmpHome, "priv_validator_state.json"),
+	)
+	pv.Key.Save()
+
+	vote := &cometproto.Vote{
+		Type:      cometproto.PrevoteType,
+		Height:    100,
+		Round:     0,
+		Timestamp: time.Unix(1700000000, 0).UTC(),
+	}
+	signBytes := comet.VoteSignBytes(chainID, vote)
+	vote.Signature, _ = pv.Key.PrivKey.Sign(signBytes)
+
+	signedVote, err := comet.VoteFromProto(vote)
+	require.NoError(t, err)
+	voteJSON, err := cometjson.Marshal(signedVote)
+	require.NoError(t, err)
+	voteFile := filepath.Join(tmpHome, "vote.json")
+	require.NoError(t, os.WriteFile(voteFile, voteJSON, 0600))
+
+	signState, err := signer.LoadOrCreateSignState(filepath.Join(tmpConfig, "state", chainID+"_priv_validator_state.json"))
+	require.NoError(t, err)
+	require.NoError(t, signState.Save(signer.SignStateConsensus{
+		Height:    100,
+		Step:      2,
+		Signature: vote.Signature,
+		SignBytes: signBytes,
+	}, nil))
+
+	verify := func(args ...string) (VerifySignatureCmdOutput, error) {
+		var out bytes.Buffer
+		cmd := rootCmd()
+		cmd.SetOut(&out)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(append([]string{"--home", tmpConfig, "verify-signature", chainID, "-o", "json"}, args...))
+		err := cmd.Execute()
+		var res VerifySignatureCmdOutput
+		if bytes.HasPrefix(out.Bytes(), []byte("{")) {
+			require.NoError(t, json.Unmarshal(out.Bytes(), &res))
+		}
+		return res, err
+	}
+
+	res, err := verify(voteFile)
+	require.NoError(t, err)
+	require.Equal(t, signer.SignatureStatusSigned, res.Status)
+	require.Equal(t, pv.Key.Address.String(), res.HexAddress)
+	require.Equal(t, "prevote", res.Type)
+	require.Equal(t, int64(100), res.Height)
+
+	res, err = verify("--sign-bytes", hex.EncodeToString(signBytes),
+		"--signature", base64.StdEncoding.EncodeToString(vote.Signature))
+	require.NoError(t, err)
+	require.Equal(t, signer.SignatureStatusSigned, res.Status)
+
+	res, err = verify("--sign-bytes", hex.EncodeToString(signBytes),
+		"--signature", base64.StdEncoding.EncodeToString(make([]byte, 64)))
+	require.Error(t, err)
+	require.Equal(t, signer.SignatureStatusInvalid, res.Status)
+
+	_, err = verify(voteFile, "--sign-bytes", hex.EncodeToString(signBytes))
+	require.Error(t, err)
+	_, err = verify("--sign-bytes", hex.EncodeToString(signBytes))
+	require.ErrorContains(t, err, "must be set together")
+
+	var out bytes.Buffer
+	require.NoError(t, printSignatureVerification(&out, res))
+	require.Contains(t, out.String(), "Height/round/step:  100/0/2\n")
+	require.True(t, strings.HasSuffix(out.String(), "Status:             "+
+		signer.SignatureStatusInvalid.Description()+"\n"))
+}
+
+func TestSignedMessageSignBytes(t *testing.T) {
+	codec := signer.CometSignBytesCodec{}
+	proposal := &comet.Proposal{
+		Type:      cometproto.ProposalType,
+		Height:    5,
+		Round:     1,
+		POLRound:  -1,
+		Timestamp: time.Unix(1700000000, 0).UTC(),
+		Signature: []byte("signature"),
+	}
+	bz, err := cometjson.Marshal(proposal)
+	require.NoError(t, err)
+
+	signBytes, signature, err := signedMessageSignBytes(codec, "horcrux-1", bz)
+	require.NoError(t, err)
+	require.Equal(t, comet.ProposalSignBytes("horcrux-1", proposal.ToProto()), signBytes)
+	require.Equal(t, []byte("signature"), signature)
+
+	proposal.Signature = nil
+	bz, err = cometjson.Marshal(proposal)
+	require.NoError(t, err)
+	_, _, err = signedMessageSignBytes(codec, "horcrux-1", bz)
+	require.ErrorContains(t, err, "not signed")
+
+	_, _, err = signedMessageSignBytes(codec, "horcrux-1", []byte(`{"type":3}`))
+	require.ErrorContains(t, err, "unexpected type 3")
+}
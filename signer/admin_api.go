@@ -0,0 +1,665 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	cometservice "github.com/cometbft/cometbft/libs/service"
+)
+
+const (
+	adminLeaderTransferTimeout = 30 * time.Second
+	adminShutdownTimeout       = 5 * time.Second
+	adminRemoveChainTimeout    = 30 * time.Second
+
+	// adminMaxKeyShardSize bounds the body of a request that adds a chain.
+	adminMaxKeyShardSize = 1 << 20
+
+	adminSignMessageTimeout = 10 * time.Second
+
+	// adminMaxMessageSize bounds the payload of a message to sign.
+	adminMaxMessageSize = 64 << 10
+
+	// adminMaxKillSwitchReasonSize bounds the reason the kill switch is engaged with.
+	adminMaxKillSwitchReasonSize = 256
+)
+
+// AdminAPIConfig configures the admin API, which serves the runtime operations of the signer on a
+// listener separate from the debug server.
+type AdminAPIConfig struct {
+	// ListenAddr is the address the admin API listens on, e.g. 127.0.0.1:6100.
+	ListenAddr string `yaml:"listenAddr"`
+
+	// DebugServerConfig configures TLS and authentication as for the debug server, except that the
+	// admin API requires authentication of every path.
+	DebugServerConfig `yaml:",inline"`
+
+	// Approvals requires the destructive operations to be approved by several operators.
+	Approvals *AdminApprovalConfig `yaml:"approvals,omitempty"`
+}
+
+func (cfg *AdminAPIConfig) Validate() error {
+	if cfg == nil {
+		return nil
+	}
+	if cfg.ListenAddr == "" {
+		return fmt.Errorf("admin API requires listenAddr")
+	}
+	if err := cfg.DebugServerConfig.Validate(); err != nil {
+		return fmt.Errorf("admin API: %w", err)
+	}
+	if cfg.PasswordFile == "" && cfg.BearerTokenFile == "" {
+		return fmt.Errorf("admin API requires passwordFile or bearerTokenFile for authentication")
+	}
+	if len(cfg.UnauthenticatedPaths) > 0 {
+		return fmt.Errorf("admin API does not allow unauthenticated paths")
+	}
+	if err := cfg.Approvals.Validate(); err != nil {
+		return fmt.Errorf("admin API: %w", err)
+	}
+	return nil
+}
+
+// AdminAPI serves the runtime operations of the signer as JSON over HTTP: its status, the chain
+// nodes it connects to, pausing and resuming signing of the signer or the cluster, the kill switch
+// of the signer or the cluster, the halt heights
+// of the chains, the leadership transfer, the restart, the log levels, the nonce cache and the
+// signing of messages, so that the signer is operated without restarts and changes to config.yaml.
+type AdminAPI struct {
+	cometservice.BaseService
+
+	logger cometlog.Logger
+	cfg    *AdminAPIConfig
+	srv    *http.Server
+
+	health  *Health
+	levels  *LogLevels
+	pause   *PauseValidator
+	signers *RemoteSigners
+	restart *SignerRestart
+	halt    *HaltHeightValidator
+	kill    *KillSwitchValidator
+
+	approvals *AdminApprovals
+
+	// val and cosigners are nil unless in threshold mode.
+	val       *ThresholdValidator
+	cosigners *CosignerMembership
+}
+
+// NewAdminAPI returns the AdminAPI of the signer. val and cosigners may be nil.
+func NewAdminAPI(
+	logger cometlog.Logger,
+	cfg *AdminAPIConfig,
+	health *Health,
+	levels *LogLevels,
+	pause *PauseValidator,
+	signers *RemoteSigners,
+	val *ThresholdValidator,
+	cosigners *CosignerMembership,
+) (*AdminAPI, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	a := &AdminAPI{
+		logger:    logger,
+		cfg:       cfg,
+		health:    health,
+		levels:    levels,
+		pause:     pause,
+		signers:   signers,
+		val:       val,
+		cosigners: cosigners,
+	}
+	a.BaseService = *cometservice.NewBaseService(logger, "AdminAPI", a)
+	return a, nil
+}
+
+// SetRestart sets the restart of the signer requested by a POST request to /v1/restart, which is
+// only served once it is set.
+func (a *AdminAPI) SetRestart(restart *SignerRestart) {
+	a.restart = restart
+}
+
+// SetHaltHeights sets the halt heights of the chains served at /v1/halt_heights, which is only served
+// once they are set.
+func (a *AdminAPI) SetHaltHeights(halt *HaltHeightValidator) {
+	a.halt = halt
+}
+
+// SetKillSwitch sets the kill switch served at /v1/kill_switch, and at /v1/cluster/kill_switch in
+// threshold mode, which are only served once it is set.
+func (a *AdminAPI) SetKillSwitch(kill *KillSwitchValidator) {
+	a.kill = kill
+}
+
+// SetApprovals sets the approvals that the destructive operations require.
+func (a *AdminAPI) SetApprovals(approvals *AdminApprovals) {
+	a.approvals = approvals
+}
+
+// Handler returns the routes of the admin API, without authentication.
+func (a *AdminAPI) Handler() http.Handler {
+	mux := http.NewServeMux()
+	a.route(mux, "/v1/status", a.health)
+	a.route(mux, "/v1/live", http.HandlerFunc(a.health.ServeLive))
+	a.route(mux, "/v1/ready", http.HandlerFunc(a.health.ServeReady))
+	a.route(mux, "/v1/chain_nodes", http.HandlerFunc(a.serveChainNodes))
+	a.route(mux, "/v1/signing/pause", http.HandlerFunc(a.servePause))
+	a.route(mux, "/v1/signing/resume", http.HandlerFunc(a.servePause))
+	a.route(mux, "/v1/signing", http.HandlerFunc(a.servePause))
+	a.route(mux, "/v1/log_level", a.levels)
+	if a.halt != nil {
+		a.route(mux, "/v1/halt_heights", http.HandlerFunc(a.serveHaltHeights))
+	}
+	if a.restart != nil {
+		a.route(mux, "/v1/restart", http.HandlerFunc(a.serveRestart))
+	}
+	if a.kill != nil {
+		a.route(mux, "/v1/kill_switch/engage", http.HandlerFunc(a.serveKillSwitch))
+		a.route(mux, "/v1/kill_switch/rearm", http.HandlerFunc(a.serveKillSwitch))
+		a.route(mux, "/v1/kill_switch", http.HandlerFunc(a.serveKillSwitch))
+	}
+	if a.val != nil {
+		a.route(mux, "/v1/leader/transfer", http.HandlerFunc(a.serveLeaderTransfer))
+		a.route(mux, "/v1/nonce_cache", http.HandlerFunc(a.serveNonceCache))
+		a.route(mux, "/v1/chains", http.HandlerFunc(a.serveChains))
+		a.route(mux, "/v1/messages/sign", http.HandlerFunc(a.serveSignMessage))
+		a.route(mux, "/v1/cluster/signing/pause", http.HandlerFunc(a.serveClusterPause))
+		a.route(mux, "/v1/cluster/signing/resume", http.HandlerFunc(a.serveClusterPause))
+		a.route(mux, "/v1/cluster/signing", http.HandlerFunc(a.serveClusterPause))
+		if a.kill != nil {
+			a.route(mux, "/v1/cluster/kill_switch/engage", http.HandlerFunc(a.serveClusterKillSwitch))
+			a.route(mux, "/v1/cluster/kill_switch/rearm", http.HandlerFunc(a.serveClusterKillSwitch))
+			a.route(mux, "/v1/cluster/kill_switch", http.HandlerFunc(a.serveClusterKillSwitch))
+		}
+	}
+	if a.cosigners != nil {
+		a.route(mux, "/v1/cosigners", a.cosigners)
+	}
+	if a.approvals != nil {
+		return a.approvals.Handler(mux)
+	}
+	return mux
+}
+
+// route serves the path with h, counting the requests by status code.
+func (a *AdminAPI) route(mux *http.ServeMux, path string, h http.Handler) {
+	mux.Handle(path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &adminResponseWriter{ResponseWriter: w, code: http.StatusOK}
+		h.ServeHTTP(rw, r)
+		totalAdminRequests.WithLabelValues(path, strconv.Itoa(rw.code)).Inc()
+		if r.Method != http.MethodGet {
+			a.logger.Info("Admin request", "method", r.Method, "path", path, "query", r.URL.RawQuery, "code", rw.code)
+		}
+	}))
+}
+
+// adminResponseWriter records the status code of a response.
+type adminResponseWriter struct {
+	http.ResponseWriter
+	code int
+}
+
+func (w *adminResponseWriter) WriteHeader(code int) {
+	w.code = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (a *AdminAPI) OnStart() error {
+	handler, err := a.cfg.Handler(a.Handler())
+	if err != nil {
+		return err
+	}
+	tlsConfig, err := a.cfg.TLSConfig()
+	if err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", a.cfg.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on admin API address: %w", err)
+	}
+	a.srv = &http.Server{
+		Handler:           handler,
+		TLSConfig:         tlsConfig,
+		ReadTimeout:       5 * time.Second,
+		WriteTimeout:      adminLeaderTransferTimeout + 5*time.Second,
+		IdleTimeout:       30 * time.Second,
+		ReadHeaderTimeout: 2 * time.Second,
+	}
+	go func() {
+		var err error
+		if tlsConfig != nil {
+			// the certificate is already in the TLS config.
+			err = a.srv.ServeTLS(ln, "", "")
+		} else {
+			err = a.srv.Serve(ln)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			a.logger.Error("Admin API stopped", "error", err)
+		}
+	}()
+	a.logger.Info("Admin API Listening", "address", ln.Addr().String())
+	return nil
+}
+
+func (a *AdminAPI) OnStop() {
+	ctx, cancel := context.WithTimeout(context.Background(), adminShutdownTimeout)
+	defer cancel()
+	if err := a.srv.Shutdown(ctx); err != nil {
+		a.logger.Error("Failed to stop admin API", "error", err)
+		_ = a.srv.Close()
+	}
+}
+
+// serveChainNodes serves the priv validator addresses of the chain nodes as JSON. A POST request
+// with the address query parameter connects to the chain node, which signs with the validator of
+// the optional validator query parameter, and only signs the chain of the optional chain_id query
+// parameter, a chain ID or validator chain ID. A DELETE request disconnects.
+func (a *AdminAPI) serveChainNodes(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost, http.MethodDelete:
+		query := r.URL.Query()
+		address := query.Get("address")
+		if address == "" {
+			http.Error(w, "address is required", http.StatusBadRequest)
+			return
+		}
+		var err error
+		if r.Method == http.MethodPost {
+			node := ChainNode{PrivValAddr: address, Validator: query.Get("validator")}
+			if chainID := query.Get("chain_id"); chainID != "" {
+				validator, id := SplitValidatorChainID(chainID)
+				if validator != "" && node.Validator != "" && validator != node.Validator {
+					http.Error(w, fmt.Sprintf("chain_id %s is not of validator %q", chainID, node.Validator),
+						http.StatusBadRequest)
+					return
+				}
+				if validator != "" {
+					node.Validator = validator
+				}
+				node.ChainID = id
+			}
+			err = a.signers.AddNode(node)
+		} else {
+			err = a.signers.Remove(address)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeAdminJSON(w, a.signers.Addresses())
+}
+
+// SigningStatus is whether signing is paused, since when, and whether signing of the whole cluster
+// is paused.
+type SigningStatus struct {
+	Paused  bool       `json:"paused"`
+	Since   *time.Time `json:"since,omitempty"`
+	Cluster bool       `json:"cluster,omitempty"`
+}
+
+// servePause serves whether signing is paused as JSON. A POST request to the pause path pauses
+// signing, and to the resume path resumes it.
+func (a *AdminAPI) servePause(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/signing":
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/signing/pause":
+		a.pause.Pause()
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/signing/resume":
+		a.pause.Resume()
+	case r.URL.Path == "/v1/signing":
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	default:
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeAdminJSON(w, a.pause.Status())
+}
+
+// serveClusterPause serves whether signing is paused on each cosigner as JSON. A POST request to the
+// pause path pauses signing of the whole cluster, and to the resume path resumes it. The response is
+// 502 Bad Gateway if a cosigner did not answer, with the status of the other cosigners.
+func (a *AdminAPI) serveClusterPause(w http.ResponseWriter, r *http.Request) {
+	var res ClusterSigningStatus
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/cluster/signing":
+		res = a.val.ClusterSigning(r.Context())
+	case r.Method == http.MethodPost && r.URL.Path != "/v1/cluster/signing":
+		var err error
+		res, err = a.val.SetClusterSigningPaused(r.Context(), r.URL.Path == "/v1/cluster/signing/pause")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	case r.URL.Path == "/v1/cluster/signing":
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	default:
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if r.Method == http.MethodPost && res.Failed() {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadGateway)
+		_ = json.NewEncoder(w).Encode(res)
+		return
+	}
+	writeAdminJSON(w, res)
+}
+
+// killSwitchReason returns the reason query parameter of a request that engages the kill switch.
+func killSwitchReason(r *http.Request) (string, error) {
+	reason := r.URL.Query().Get("reason")
+	if len(reason) > adminMaxKillSwitchReasonSize {
+		return "", fmt.Errorf("reason must be at most %d bytes", adminMaxKillSwitchReasonSize)
+	}
+	return reason, nil
+}
+
+// serveKillSwitch serves whether the kill switch is engaged as JSON. A POST request to the engage
+// path engages the kill switch with the optional reason query parameter, and to the rearm path
+// re-arms signing.
+func (a *AdminAPI) serveKillSwitch(w http.ResponseWriter, r *http.Request) {
+	var err error
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/kill_switch":
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/kill_switch/engage":
+		var reason string
+		if reason, err = killSwitchReason(r); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		a.logger.Error("Kill switch engaged, signing is disabled until it is re-armed", "reason", reason)
+		_, err = a.kill.Engage(reason)
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/kill_switch/rearm":
+		a.logger.Info("Kill switch re-armed")
+		_, err = a.kill.Rearm()
+	case r.URL.Path == "/v1/kill_switch":
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	default:
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeAdminJSON(w, a.kill.Status())
+}
+
+// serveClusterKillSwitch serves whether the kill switch of each cosigner is engaged as JSON. A POST
+// request to the engage path engages the kill switch of every cosigner with the optional reason
+// query parameter, and to the rearm path re-arms them. The response is 502 Bad Gateway if a
+// cosigner did not answer, with the status of the other cosigners.
+func (a *AdminAPI) serveClusterKillSwitch(w http.ResponseWriter, r *http.Request) {
+	var res ClusterKillSwitchStatus
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/cluster/kill_switch":
+		res = a.val.ClusterKillSwitch(r.Context())
+	case r.Method == http.MethodPost && r.URL.Path != "/v1/cluster/kill_switch":
+		reason, err := killSwitchReason(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		res, err = a.val.SetClusterKillSwitch(r.Context(), r.URL.Path == "/v1/cluster/kill_switch/engage", reason,
+			r.Header.Values(AdminApprovalHeader))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	case r.URL.Path == "/v1/cluster/kill_switch":
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	default:
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if r.Method == http.MethodPost && res.Failed() {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadGateway)
+		_ = json.NewEncoder(w).Encode(res)
+		return
+	}
+	writeAdminJSON(w, res)
+}
+
+// serveHaltHeights serves the halt height of each chain as JSON. A POST request with the chain_id
+// and height query parameters halts signing of the chain after the height, and a DELETE request
+// with the chain_id query parameter clears the halt height of the chain.
+func (a *AdminAPI) serveHaltHeights(w http.ResponseWriter, r *http.Request) {
+	chainID := r.URL.Query().Get("chain_id")
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if chainID == "" {
+			http.Error(w, "chain_id is required", http.StatusBadRequest)
+			return
+		}
+		height, err := strconv.ParseInt(r.URL.Query().Get("height"), 10, 64)
+		if err != nil {
+			http.Error(w, "height must be a number", http.StatusBadRequest)
+			return
+		}
+		if err := a.halt.SetHaltHeight(chainID, height); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	case http.MethodDelete:
+		if chainID == "" {
+			http.Error(w, "chain_id is required", http.StatusBadRequest)
+			return
+		}
+		if !a.halt.ClearHaltHeight(chainID) {
+			http.Error(w, fmt.Sprintf("chain %s has no halt height", chainID), http.StatusNotFound)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeAdminJSON(w, a.halt.HaltHeights())
+}
+
+// serveRestart requests the restart of the signer, which stops its services and replaces its process
+// once the response is sent.
+func (a *AdminAPI) serveRestart(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(struct {
+		Restarting bool `json:"restarting"`
+	}{Restarting: true})
+	a.restart.Request()
+}
+
+// serveLeaderTransfer transfers the leadership to the cosigner with the shardID query parameter, or
+// to the next eligible cosigner without it, after the sign requests in flight. This cosigner must
+// be the leader.
+func (a *AdminAPI) serveLeaderTransfer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	shardID := r.URL.Query().Get("shardID")
+	if shardID != "" {
+		if _, err := strconv.Atoi(shardID); err != nil {
+			http.Error(w, "shardID must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), adminLeaderTransferTimeout)
+	defer cancel()
+	leaderID, leaderAddress, err := a.val.TransferLeadership(ctx, shardID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	writeAdminJSON(w, struct {
+		LeaderID      string `json:"leader_id,omitempty"`
+		LeaderAddress string `json:"leader_address,omitempty"`
+	}{
+		LeaderID:      leaderID,
+		LeaderAddress: leaderAddress,
+	})
+}
+
+// serveSignMessage threshold signs the body of a POST request as a message of the type query
+// parameter for the chain of the chain_id query parameter, and serves the signature with the sign
+// bytes it is over as JSON. This cosigner must be the leader.
+func (a *AdminAPI) serveSignMessage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	chainID, msgType := r.URL.Query().Get("chain_id"), r.URL.Query().Get("type")
+	if chainID == "" || msgType == "" {
+		http.Error(w, "chain_id and type are required", http.StatusBadRequest)
+		return
+	}
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, adminMaxMessageSize))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read message: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), adminSignMessageTimeout)
+	defer cancel()
+	sig, err := a.val.SignMessage(ctx, chainID, msgType, payload)
+	if err != nil {
+		code := http.StatusInternalServerError
+		switch err.(type) {
+		case *MessageSigningError:
+			code = http.StatusForbidden
+		case *KillSwitchError, *SigningPausedError:
+			code = http.StatusServiceUnavailable
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+	writeAdminJSON(w, sig)
+}
+
+// serveNonceCache serves a summary of the nonces ready in the nonce cache as JSON.
+func (a *AdminAPI) serveNonceCache(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeAdminJSON(w, a.val.InspectNonceCache())
+}
+
+// serveChains serves the chains of the cosigners and whether they are ready as JSON. A POST request
+// with the chain_id query parameter adds the chain with the key shard of this cosigner in the body,
+// seeding the sign states at the optional height query parameter. A DELETE request removes the
+// chain. Both connect to, or disconnect from, the chain nodes in the chain_node query parameters,
+// which only sign the chain, with the validator of the chain ID.
+func (a *AdminAPI) serveChains(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	chainID := query.Get("chain_id")
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if chainID == "" {
+			http.Error(w, "chain_id is required", http.StatusBadRequest)
+			return
+		}
+		var height int64
+		if h := query.Get("height"); h != "" {
+			var err error
+			if height, err = strconv.ParseInt(h, 10, 64); err != nil {
+				http.Error(w, "height must be a number", http.StatusBadRequest)
+				return
+			}
+		}
+		var shard CosignerEd25519Key
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, adminMaxKeyShardSize)).Decode(&shard); err != nil {
+			http.Error(w, fmt.Sprintf("invalid key shard: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := a.val.AddChain(chainID, shard, height); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		validator, id := SplitValidatorChainID(chainID)
+		for _, address := range query["chain_node"] {
+			if err := a.signers.AddNode(ChainNode{PrivValAddr: address, Validator: validator, ChainID: id}); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+	case http.MethodDelete:
+		if chainID == "" {
+			http.Error(w, "chain_id is required", http.StatusBadRequest)
+			return
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), adminRemoveChainTimeout)
+		defer cancel()
+		if err := a.val.RemoveChain(ctx, chainID); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, address := range query["chain_node"] {
+			if err := a.signers.Remove(address); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	chains, err := a.val.Chains(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeAdminJSON(w, chains)
+}
+
+func writeAdminJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
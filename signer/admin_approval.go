@@ -0,0 +1,398 @@
+package signer
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+)
+
+// The destructive admin operations that can require the approval of several operators.
+const (
+	AdminOperationKillSwitchRearm = "kill_switch_rearm"
+	AdminOperationRemoveChain     = "remove_chain"
+	AdminOperationRemoveCosigner  = "remove_cosigner"
+	AdminOperationClearHaltHeight = "clear_halt_height"
+	AdminOperationSetSignState    = "set_sign_state"
+	AdminOperationRestoreState    = "restore_state"
+)
+
+// AdminOperations are the admin operations that can require approvals.
+var AdminOperations = []string{
+	AdminOperationKillSwitchRearm,
+	AdminOperationRemoveChain,
+	AdminOperationRemoveCosigner,
+	AdminOperationClearHaltHeight,
+	AdminOperationSetSignState,
+	AdminOperationRestoreState,
+}
+
+const (
+	// AdminApprovalHeader is the header of an approval of an admin request, repeated for each operator.
+	AdminApprovalHeader = "X-Horcrux-Approval"
+
+	adminApprovalDomain = "horcrux/admin-approval/v1"
+
+	defaultAdminApprovalsRequired = 2
+	defaultAdminApprovalMaxAge    = 15 * time.Minute
+)
+
+// AdminApprovalConfig configures the operators that must approve the destructive admin operations.
+type AdminApprovalConfig struct {
+	// Operators are the operators that approve the operations with their ed25519 keys.
+	Operators []AdminOperator `yaml:"operators"`
+
+	// Required is the number of distinct operators that must approve an operation. Defaults to 2.
+	Required int `yaml:"required,omitempty"`
+
+	// Operations are the operations that require approvals. Defaults to all of AdminOperations.
+	Operations []string `yaml:"operations,omitempty"`
+
+	// MaxAge bounds how long before its expiry an approval is signed, e.g. 15m, the default.
+	MaxAge string `yaml:"maxAge,omitempty"`
+}
+
+// AdminOperator is an operator identity, with the base64 ed25519 public key of its approvals.
+type AdminOperator struct {
+	Name   string `yaml:"name"`
+	PubKey string `yaml:"pubKey"`
+}
+
+func (cfg *AdminApprovalConfig) Validate() error {
+	if cfg == nil {
+		return nil
+	}
+	required := cfg.required()
+	if required < 2 {
+		return fmt.Errorf("approvals: required must be at least 2, got %d", required)
+	}
+	if len(cfg.Operators) < required {
+		return fmt.Errorf("approvals: %d operators cannot give the %d required approvals", len(cfg.Operators), required)
+	}
+	names := make(map[string]bool, len(cfg.Operators))
+	keys := make(map[string]bool, len(cfg.Operators))
+	for _, o := range cfg.Operators {
+		if o.Name == "" || strings.ContainsAny(o.Name, ": ,") {
+			return fmt.Errorf("approvals: invalid operator name %q", o.Name)
+		}
+		if names[o.Name] {
+			return fmt.Errorf("approvals: duplicate operator %s", o.Name)
+		}
+		names[o.Name] = true
+		key, err := o.pubKey()
+		if err != nil {
+			return fmt.Errorf("approvals: %w", err)
+		}
+		if keys[string(key)] {
+			return fmt.Errorf("approvals: operator %s has the key of another operator", o.Name)
+		}
+		keys[string(key)] = true
+	}
+	for _, op := range cfg.Operations {
+		if !slices.Contains(AdminOperations, op) {
+			return fmt.Errorf("approvals: unknown operation %q, must be one of %v", op, AdminOperations)
+		}
+	}
+	if cfg.MaxAge != "" {
+		if d, err := time.ParseDuration(cfg.MaxAge); err != nil || d <= 0 {
+			return fmt.Errorf("approvals: invalid maxAge %q", cfg.MaxAge)
+		}
+	}
+	return nil
+}
+
+func (cfg *AdminApprovalConfig) required() int {
+	if cfg.Required == 0 {
+		return defaultAdminApprovalsRequired
+	}
+	return cfg.Required
+}
+
+func (o AdminOperator) pubKey() (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(o.PubKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("operator %s: pubKey must be a base64 ed25519 public key", o.Name)
+	}
+	return key, nil
+}
+
+type AdminApprovalError struct {
+	msg string
+}
+
+func (e *AdminApprovalError) Error() string { return e.msg }
+
+func newAdminApprovalError(format string, args ...any) *AdminApprovalError {
+	return &AdminApprovalError{msg: fmt.Sprintf(format, args...)}
+}
+
+// adminOperation returns the operation of the admin request that can require approvals, or "".
+func adminOperation(r *http.Request) string {
+	switch {
+	case r.Method == http.MethodPost &&
+		(r.URL.Path == "/v1/kill_switch/rearm" || r.URL.Path == "/v1/cluster/kill_switch/rearm"):
+		return AdminOperationKillSwitchRearm
+	case r.Method == http.MethodDelete && r.URL.Path == "/v1/chains":
+		return AdminOperationRemoveChain
+	case r.Method == http.MethodDelete && r.URL.Path == "/v1/cosigners":
+		return AdminOperationRemoveCosigner
+	case r.Method == http.MethodDelete && r.URL.Path == "/v1/halt_heights":
+		return AdminOperationClearHaltHeight
+	}
+	return ""
+}
+
+// AdminApprovalRequest returns the admin request an approval is bound to, the method and the path
+// of the target with its sorted query, e.g. DELETE /v1/chains?chain_id=cosmoshub-4.
+func AdminApprovalRequest(method, target string) (string, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", fmt.Errorf("invalid request target: %w", err)
+	}
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return "", fmt.Errorf("invalid request query: %w", err)
+	}
+	request := strings.ToUpper(method) + " " + u.Path
+	if len(query) > 0 {
+		request += "?" + query.Encode()
+	}
+	return request, nil
+}
+
+// SignStateApprovalRequest returns the request an approval of setting the sign state of the chain
+// to the height, round and step with horcrux state set or horcrux state import is bound to, which
+// is not a request of the admin API, e.g. SET /sign_state?chain_id=cosmoshub-4&height=100&round=0&step=0.
+func SignStateApprovalRequest(chainID string, hrs HRSKey) string {
+	return "SET /sign_state?" + url.Values{
+		"chain_id": {chainID},
+		"height":   {strconv.FormatInt(hrs.Height, 10)},
+		"round":    {strconv.FormatInt(hrs.Round, 10)},
+		"step":     {strconv.Itoa(int(hrs.Step))},
+	}.Encode()
+}
+
+// StateRestoreApprovalRequest returns the request an approval of restoring the state backup at the
+// source URL with horcrux state restore --force is bound to, which is not a request of the admin API,
+// e.g. RESTORE /state?source=s3%3A%2F%2Fmy-bucket%2Fcosigner-1%2F20231018T120000Z.hbk.
+func StateRestoreApprovalRequest(source string) string {
+	return "RESTORE /state?" + url.Values{"source": {source}}.Encode()
+}
+
+// AdminApprovalSignBytes returns the bytes the operator signs to approve the request until expires.
+func AdminApprovalSignBytes(operator, request string, expires time.Time) []byte {
+	return []byte(strings.Join([]string{
+		adminApprovalDomain,
+		operator,
+		request,
+		strconv.FormatInt(expires.Unix(), 10),
+	}, "\n"))
+}
+
+// NewAdminApproval returns the AdminApprovalHeader of the approval of the request by the operator
+// with its key until expires, as operator:expires:signature.
+func NewAdminApproval(operator string, key ed25519.PrivateKey, request string, expires time.Time) string {
+	sig := ed25519.Sign(key, AdminApprovalSignBytes(operator, request, expires))
+	return fmt.Sprintf("%s:%d:%s", operator, expires.Unix(), base64.StdEncoding.EncodeToString(sig))
+}
+
+// AdminApprovals requires the destructive admin operations to be approved by distinct operators,
+// so that no single operator, or a single leaked admin credential, can e.g. re-arm a kill switch
+// engaged by a security team. Every decision is recorded in the audit log.
+type AdminApprovals struct {
+	logger     cometlog.Logger
+	operators  map[string]ed25519.PublicKey
+	required   int
+	operations map[string]bool
+	maxAge     time.Duration
+	audit      *AuditLog
+
+	mu sync.Mutex
+	// used are the signatures of the approvals already used, also before a restart as recorded in
+	// the audit log, until they expire, so that each approval approves a single request.
+	used map[string]time.Time
+}
+
+// NewAdminApprovals returns the AdminApprovals of the config, which records its decisions in audit.
+func NewAdminApprovals(logger cometlog.Logger, cfg *AdminApprovalConfig, audit *AuditLog) (*AdminApprovals, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if audit == nil {
+		return nil, fmt.Errorf("approvals require the audit log, which records them")
+	}
+	a := &AdminApprovals{
+		logger:     logger,
+		operators:  make(map[string]ed25519.PublicKey, len(cfg.Operators)),
+		required:   cfg.required(),
+		operations: make(map[string]bool),
+		maxAge:     defaultAdminApprovalMaxAge,
+		audit:      audit,
+		used:       make(map[string]time.Time),
+	}
+	for _, o := range cfg.Operators {
+		a.operators[o.Name], _ = o.pubKey()
+	}
+	operations := cfg.Operations
+	if len(operations) == 0 {
+		operations = AdminOperations
+	}
+	for _, op := range operations {
+		a.operations[op] = true
+	}
+	if cfg.MaxAge != "" {
+		a.maxAge, _ = time.ParseDuration(cfg.MaxAge)
+	}
+	// an approval expires at most maxAge after it was used.
+	now := time.Now()
+	for sig, used := range audit.usedApprovals() {
+		if expires := used.Add(a.maxAge); expires.After(now) {
+			a.used[sig] = expires
+		}
+	}
+	return a, nil
+}
+
+// Requires returns whether the operation requires approvals.
+func (a *AdminApprovals) Requires(operation string) bool {
+	return a != nil && a.operations[operation]
+}
+
+// Approve verifies the approvals of the request of the operation, if the operation requires
+// approvals, and records the decision in the audit log. It returns the distinct operators that
+// approved the request, and an AdminApprovalError if the request is refused.
+func (a *AdminApprovals) Approve(operation, request string, approvals []string) ([]string, error) {
+	if !a.Requires(operation) {
+		return nil, nil
+	}
+	approvers, sigs, err := a.approve(request, approvals, time.Now())
+	if auditErr := a.audit.RecordAdminOperation(operation, request, approvers, sigs, err); auditErr != nil {
+		// an operation that cannot be audited is not approved.
+		a.logger.Error("Failed to record admin operation in audit log", "request", request, "error", auditErr)
+		return nil, fmt.Errorf("failed to record the approvals in the audit log: %w", auditErr)
+	}
+	if err != nil {
+		totalAdminApprovalRefusals.WithLabelValues(operation).Inc()
+		a.logger.Error("Refused admin operation without approvals", "request", request, "error", err)
+		return approvers, err
+	}
+	a.logger.Info("Admin operation approved", "request", request, "approvers", strings.Join(approvers, ","))
+	return approvers, nil
+}
+
+// Handler serves the requests of the operations that require approvals with next only once they
+// carry the approvals of enough distinct operators, and the other requests with next.
+func (a *AdminApprovals) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		operation := adminOperation(r)
+		if !a.Requires(operation) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		request, err := AdminApprovalRequest(r.Method, r.URL.RequestURI())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		_, err = a.Approve(operation, request, r.Header.Values(AdminApprovalHeader))
+		var approvalErr *AdminApprovalError
+		switch {
+		case errors.As(err, &approvalErr):
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		case err != nil:
+			http.Error(w, "failed to record the approvals in the audit log", http.StatusInternalServerError)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// approve verifies the approvals of the request at now, and returns the distinct operators that
+// approved it and the signatures of their approvals. The approvals are only used up once the
+// request is approved.
+func (a *AdminApprovals) approve(request string, approvals []string, now time.Time) ([]string, [][]byte, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for sig, expires := range a.used {
+		if now.After(expires) {
+			delete(a.used, sig)
+		}
+	}
+
+	var approvers []string
+	var sigs [][]byte
+	var expiries []time.Time
+	var errs []string
+	for _, approval := range approvals {
+		operator, expires, sig, err := a.verify(request, approval, now)
+		switch {
+		case err != nil:
+			errs = append(errs, err.Error())
+		case slices.Contains(approvers, operator):
+			errs = append(errs, fmt.Sprintf("operator %s approved more than once", operator))
+		default:
+			approvers = append(approvers, operator)
+			sigs = append(sigs, sig)
+			expiries = append(expiries, expires)
+		}
+	}
+	slices.Sort(approvers)
+	if len(approvers) < a.required {
+		msg := fmt.Sprintf("%s requires the approvals of %d distinct operators, got %d",
+			request, a.required, len(approvers))
+		if len(errs) > 0 {
+			msg += ": " + strings.Join(errs, "; ")
+		}
+		return approvers, nil, newAdminApprovalError("%s", msg)
+	}
+	for i, sig := range sigs {
+		a.used[string(sig)] = expiries[i]
+	}
+	return approvers, sigs, nil
+}
+
+// verify verifies an approval of the request at now, and returns its operator, expiry and signature.
+// The signature is decoded strictly, so that an approval cannot be used again with another encoding
+// of the same signature.
+func (a *AdminApprovals) verify(request, approval string, now time.Time) (string, time.Time, []byte, error) {
+	parts := strings.Split(approval, ":")
+	if len(parts) != 3 {
+		return "", time.Time{}, nil, fmt.Errorf("invalid approval, must be operator:expires:signature")
+	}
+	operator := parts[0]
+	key, ok := a.operators[operator]
+	if !ok {
+		return "", time.Time{}, nil, fmt.Errorf("unknown operator %q", operator)
+	}
+	unix, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", time.Time{}, nil, fmt.Errorf("approval of %s has an invalid expiry", operator)
+	}
+	expires := time.Unix(unix, 0)
+	if now.After(expires) {
+		return "", time.Time{}, nil, fmt.Errorf("approval of %s expired", operator)
+	}
+	if expires.Sub(now) > a.maxAge {
+		return "", time.Time{}, nil, fmt.Errorf("approval of %s expires more than %s from now", operator, a.maxAge)
+	}
+	sig, err := base64.StdEncoding.Strict().DecodeString(parts[2])
+	if err != nil || !ed25519.Verify(key, AdminApprovalSignBytes(operator, request, expires), sig) {
+		return "", time.Time{}, nil, fmt.Errorf("approval of %s does not verify for %s", operator, request)
+	}
+	if _, ok := a.used[string(sig)]; ok {
+		return "", time.Time{}, nil, fmt.Errorf("approval of %s was already used", operator)
+	}
+	return operator, expires, sig, nil
+}
@@ -0,0 +1,271 @@
+package signer
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/strangelove-ventures/horcrux/signer/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type testOperator struct {
+	name string
+	key  ed25519.PrivateKey
+}
+
+func newTestOperator(t *testing.T, name string) testOperator {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return testOperator{name: name, key: key}
+}
+
+func (o testOperator) config() AdminOperator {
+	return AdminOperator{Name: o.name, PubKey: base64.StdEncoding.EncodeToString(o.key.Public().(ed25519.PublicKey))}
+}
+
+func (o testOperator) approve(t *testing.T, method, target string, expires time.Time) string {
+	request, err := AdminApprovalRequest(method, target)
+	require.NoError(t, err)
+	return NewAdminApproval(o.name, o.key, request, expires)
+}
+
+func TestAdminApprovalConfig(t *testing.T) {
+	alice, bob := newTestOperator(t, "alice"), newTestOperator(t, "bob")
+	operators := []AdminOperator{alice.config(), bob.config()}
+
+	require.NoError(t, (*AdminApprovalConfig)(nil).Validate())
+	require.NoError(t, (&AdminApprovalConfig{Operators: operators, MaxAge: "5m"}).Validate())
+
+	for name, cfg := range map[string]AdminApprovalConfig{
+		"single approval":   {Operators: operators, Required: 1},
+		"too few operators": {Operators: operators, Required: 3},
+		"duplicate name":    {Operators: []AdminOperator{alice.config(), alice.config()}},
+		"duplicate key": {Operators: []AdminOperator{alice.config(), {
+			Name: "mallory", PubKey: alice.config().PubKey,
+		}}},
+		"invalid name":      {Operators: []AdminOperator{{Name: "a:b", PubKey: alice.config().PubKey}, bob.config()}},
+		"invalid key":       {Operators: []AdminOperator{{Name: "carol", PubKey: "AAAA"}, bob.config()}},
+		"unknown operation": {Operators: operators, Operations: []string{"restart"}},
+		"invalid max age":   {Operators: operators, MaxAge: "-1m"},
+	} {
+		cfg := cfg
+		require.Error(t, cfg.Validate(), name)
+	}
+}
+
+func TestAdminAPIApprovals(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("secret"), 0600))
+	auditFile := filepath.Join(t.TempDir(), "audit.log")
+	audit, err := OpenAuditLog(auditFile)
+	require.NoError(t, err)
+	defer audit.Close()
+
+	alice, bob, carol := newTestOperator(t, "alice"), newTestOperator(t, "bob"), newTestOperator(t, "carol")
+	mallory := newTestOperator(t, "alice")
+
+	val, kill := newKillSwitchTestValidator(t, 1)
+	cfg := &AdminAPIConfig{
+		ListenAddr:        "127.0.0.1:0",
+		DebugServerConfig: DebugServerConfig{BearerTokenFile: tokenFile},
+		Approvals: &AdminApprovalConfig{
+			Operators:  []AdminOperator{alice.config(), bob.config(), carol.config()},
+			Operations: []string{AdminOperationKillSwitchRearm},
+		},
+	}
+	a, err := NewAdminAPI(cometlog.NewNopLogger(), cfg, NewHealth(SignModeThreshold, nil), NewLogLevels(),
+		val.signingPause(), nil, val, nil)
+	require.NoError(t, err)
+	a.SetKillSwitch(kill)
+
+	_, err = NewAdminApprovals(cometlog.NewNopLogger(), cfg.Approvals, nil)
+	require.Error(t, err)
+	approvals, err := NewAdminApprovals(cometlog.NewNopLogger(), cfg.Approvals, audit)
+	require.NoError(t, err)
+	a.SetApprovals(approvals)
+
+	handler, err := cfg.Handler(a.Handler())
+	require.NoError(t, err)
+
+	request := func(method, target string, approvals ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		for _, approval := range approvals {
+			req.Header.Add(AdminApprovalHeader, approval)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// the operations that do not require approvals are served as before.
+	require.Equal(t, http.StatusOK, request(http.MethodPost, "/v1/kill_switch/engage?reason=incident").Code)
+	require.True(t, kill.Status().Engaged)
+
+	const rearm = "/v1/kill_switch/rearm"
+	expires := time.Now().Add(5 * time.Minute)
+
+	rec := request(http.MethodPost, rearm)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+	require.Contains(t, rec.Body.String(), "requires the approvals of 2 distinct operators, got 0")
+
+	// a single operator cannot approve twice, nor with the name of another operator.
+	aliceApproval := alice.approve(t, http.MethodPost, rearm, expires)
+	rec = request(http.MethodPost, rearm, aliceApproval, aliceApproval)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+	require.Contains(t, rec.Body.String(), "operator alice approved more than once")
+	rec = request(http.MethodPost, rearm, aliceApproval, mallory.approve(t, http.MethodPost, rearm, expires))
+	require.Equal(t, http.StatusForbidden, rec.Code)
+	require.Contains(t, rec.Body.String(), "approval of alice does not verify")
+
+	// approvals are bound to the request, and expire.
+	rec = request(http.MethodPost, rearm, aliceApproval,
+		bob.approve(t, http.MethodPost, "/v1/cluster/kill_switch/rearm", expires))
+	require.Equal(t, http.StatusForbidden, rec.Code)
+	require.Contains(t, rec.Body.String(), "approval of bob does not verify")
+	rec = request(http.MethodPost, rearm, aliceApproval,
+		bob.approve(t, http.MethodPost, rearm, time.Now().Add(-time.Second)))
+	require.Contains(t, rec.Body.String(), "approval of bob expired")
+	rec = request(http.MethodPost, rearm, aliceApproval,
+		bob.approve(t, http.MethodPost, rearm, time.Now().Add(time.Hour)))
+	require.Contains(t, rec.Body.String(), "approval of bob expires more than 15m0s from now")
+	require.True(t, kill.Status().Engaged)
+	require.Equal(t, float64(6),
+		testutil.ToFloat64(totalAdminApprovalRefusals.WithLabelValues(AdminOperationKillSwitchRearm)))
+
+	bobApproval := bob.approve(t, http.MethodPost, rearm, expires)
+	rec = request(http.MethodPost, rearm, aliceApproval, bobApproval)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.False(t, kill.Status().Engaged)
+
+	// each approval approves a single request.
+	_, err = kill.Engage("incident")
+	require.NoError(t, err)
+	rec = request(http.MethodPost, rearm, aliceApproval, bobApproval)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+	require.Contains(t, rec.Body.String(), "approval of alice was already used")
+	// approving the same request until the same expiry again is the same approval.
+	rec = request(http.MethodPost, rearm, carol.approve(t, http.MethodPost, rearm, expires),
+		bob.approve(t, http.MethodPost, rearm, expires))
+	require.Contains(t, rec.Body.String(), "approval of bob was already used")
+	require.Equal(t, http.StatusOK, request(http.MethodPost, rearm, carol.approve(t, http.MethodPost, rearm, expires),
+		bob.approve(t, http.MethodPost, rearm, expires.Add(time.Second))).Code)
+	require.False(t, kill.Status().Engaged)
+
+	head, err := VerifyAuditLog(auditFile)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), head.Seq)
+
+	contents, err := os.ReadFile(auditFile)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(contents)), "\n")
+	var refused, approved AuditEntry
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &refused))
+	require.Equal(t, AdminOperationKillSwitchRearm, refused.Operation)
+	require.Equal(t, "POST "+rearm, refused.Request)
+	require.Equal(t, "refused", refused.Decision)
+	require.NotEmpty(t, refused.Reason)
+	require.NoError(t, json.Unmarshal([]byte(lines[6]), &approved))
+	require.Equal(t, "approved", approved.Decision)
+	require.Equal(t, []string{"alice", "bob"}, approved.Approvers)
+	require.Len(t, approved.Approvals, 2)
+
+	// an approval cannot be used again with another encoding of its signature.
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+	parts := strings.Split(aliceApproval, ":")
+	sig := []byte(parts[2])
+	sig[85] = alphabet[strings.IndexByte(alphabet, sig[85])|1]
+	_, err = base64.StdEncoding.DecodeString(string(sig))
+	require.NoError(t, err)
+	rec = request(http.MethodPost, rearm, strings.Join([]string{parts[0], parts[1], string(sig)}, ":"),
+		carol.approve(t, http.MethodPost, rearm, expires.Add(2*time.Second)))
+	require.Contains(t, rec.Body.String(), "approval of alice does not verify")
+
+	// nor after a restart, as the audit log records the approvals used.
+	require.NoError(t, audit.Close())
+	audit, err = OpenAuditLog(auditFile)
+	require.NoError(t, err)
+	approvals, err = NewAdminApprovals(cometlog.NewNopLogger(), cfg.Approvals, audit)
+	require.NoError(t, err)
+	_, err = approvals.Approve(AdminOperationKillSwitchRearm, "POST "+rearm, []string{aliceApproval, bobApproval})
+	require.ErrorContains(t, err, "approval of alice was already used")
+	require.ErrorContains(t, err, "approval of bob was already used")
+}
+
+func TestClusterKillSwitchRearmApprovals(t *testing.T) {
+	audit, err := OpenAuditLog(filepath.Join(t.TempDir(), "audit.log"))
+	require.NoError(t, err)
+	defer audit.Close()
+	alice, bob := newTestOperator(t, "alice"), newTestOperator(t, "bob")
+	approvals, err := NewAdminApprovals(cometlog.NewNopLogger(), &AdminApprovalConfig{
+		Operators: []AdminOperator{alice.config(), bob.config()},
+	}, audit)
+	require.NoError(t, err)
+
+	peerVal, peerKill := newKillSwitchTestValidator(t, 2)
+	peerVal.SetAdminApprovals(approvals)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcServer := grpc.NewServer()
+	proto.RegisterCosignerServer(grpcServer, NewCosignerGRPCServer(nil, peerVal, nil))
+	go func() { _ = grpcServer.Serve(ln) }()
+	defer grpcServer.Stop()
+
+	peer, err := NewRemoteCosigner(2, "tcp://"+ln.Addr().String(), TCPCosignerTransport{})
+	require.NoError(t, err)
+	val, kill := newKillSwitchTestValidator(t, 1, peer)
+	ctx := context.Background()
+
+	_, err = val.SetClusterKillSwitch(ctx, true, "incident", nil)
+	require.NoError(t, err)
+
+	// a peer re-arms the kill switch of the cluster only with the approvals its re-arm requires.
+	status, err := val.SetClusterKillSwitch(ctx, false, "", nil)
+	require.NoError(t, err)
+	require.False(t, kill.Status().Engaged)
+	require.True(t, peerKill.Status().Engaged)
+	require.Contains(t, status.Cosigners[1].Error, "requires the approvals of 2 distinct operators, got 0")
+
+	const rearm = "/v1/cluster/kill_switch/rearm"
+	expires := time.Now().Add(5 * time.Minute)
+	rearmApprovals := []string{
+		alice.approve(t, http.MethodPost, rearm, expires),
+		bob.approve(t, http.MethodPost, rearm, expires),
+	}
+	status, err = val.SetClusterKillSwitch(ctx, false, "", rearmApprovals)
+	require.NoError(t, err)
+	require.False(t, status.Failed())
+	require.False(t, peerKill.Status().Engaged)
+
+	_, err = val.SetClusterKillSwitch(ctx, true, "incident", nil)
+	require.NoError(t, err)
+	status, err = val.SetClusterKillSwitch(ctx, false, "", rearmApprovals)
+	require.NoError(t, err)
+	require.Contains(t, status.Cosigners[1].Error, "was already used")
+	require.True(t, peerKill.Status().Engaged)
+}
+
+func TestAdminApprovalRequest(t *testing.T) {
+	request, err := AdminApprovalRequest("delete", "/v1/chains?chain_node=tcp%3A%2F%2Fb&chain_id=c-1&chain_node=tcp://a")
+	require.NoError(t, err)
+	require.Equal(t, "DELETE /v1/chains?chain_id=c-1&chain_node=tcp%3A%2F%2Fb&chain_node=tcp%3A%2F%2Fa", request)
+
+	request, err = AdminApprovalRequest(http.MethodPost, "/v1/kill_switch/rearm")
+	require.NoError(t, err)
+	require.Equal(t, "POST /v1/kill_switch/rearm", request)
+}
@@ -0,0 +1,202 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const adminClientTimeout = 10 * time.Second
+
+// AdminClient queries the admin API, or the debug server, of a running signer with the credentials
+// of its config.
+type AdminClient struct {
+	// name names the server in errors.
+	name    string
+	baseURL string
+	client  *http.Client
+
+	username, password string
+	token              string
+}
+
+// NewAdminClient returns an AdminClient of the admin API configured by cfg. address overrides the
+// listenAddr of cfg if set; an unspecified host of the listen address is reached on the loopback.
+func NewAdminClient(cfg *AdminAPIConfig, address string) (*AdminClient, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("admin API is not configured, add admin to the config")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if address == "" {
+		address = cfg.ListenAddr
+	}
+	return newAdminClient("admin API", &cfg.DebugServerConfig, address)
+}
+
+// NewDebugServerClient returns an AdminClient of the debug server listening on address, with the
+// TLS and authentication of cfg, which is optional.
+func NewDebugServerClient(cfg *DebugServerConfig, address string) (*AdminClient, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		cfg = &DebugServerConfig{}
+	}
+	return newAdminClient("debug server", cfg, address)
+}
+
+func newAdminClient(name string, cfg *DebugServerConfig, address string) (*AdminClient, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s address %q: %w", name, address, err)
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "127.0.0.1"
+	}
+
+	c := &AdminClient{
+		name:    name,
+		baseURL: "http://" + net.JoinHostPort(host, port),
+		client:  &http.Client{Timeout: adminClientTimeout},
+	}
+
+	if cfg.CertFile != "" {
+		// the certificate is trusted as is, it is often self-signed.
+		pem, err := os.ReadFile(cfg.CertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s certificate: %w", name, err)
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", cfg.CertFile)
+		}
+		c.baseURL = "https://" + net.JoinHostPort(host, port)
+		c.client.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{
+				RootCAs:    roots,
+				ServerName: host,
+				MinVersion: tls.VersionTLS12,
+			},
+		}
+	}
+
+	switch {
+	case cfg.BearerTokenFile != "":
+		token, err := readDebugServerSecret(cfg.BearerTokenFile)
+		if err != nil {
+			return nil, err
+		}
+		c.token = string(token)
+	case cfg.PasswordFile != "":
+		password, err := readDebugServerSecret(cfg.PasswordFile)
+		if err != nil {
+			return nil, err
+		}
+		c.username, c.password = cfg.Username, string(password)
+	}
+
+	return c, nil
+}
+
+// Status returns the health report of the signer.
+func (c *AdminClient) Status(ctx context.Context) (*HealthReport, error) {
+	var report HealthReport
+	if err := c.get(ctx, "/v1/status", &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Signing returns whether signing is paused.
+func (c *AdminClient) Signing(ctx context.Context) (*SigningStatus, error) {
+	var status SigningStatus
+	if err := c.get(ctx, "/v1/signing", &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// ChainNodes returns the priv validator addresses of the chain nodes the signer connects to.
+func (c *AdminClient) ChainNodes(ctx context.Context) ([]string, error) {
+	var addresses []string
+	if err := c.get(ctx, "/v1/chain_nodes", &addresses); err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+// Restart requests the restart of the signer, which replaces its process once its services are
+// stopped.
+func (c *AdminClient) Restart(ctx context.Context) error {
+	var v json.RawMessage
+	return c.do(ctx, http.MethodPost, "/v1/restart", nil, &v)
+}
+
+// SignMessage threshold signs the payload as a message of the type for the chain. The signer must be
+// the leader.
+func (c *AdminClient) SignMessage(
+	ctx context.Context,
+	chainID, msgType string,
+	payload []byte,
+) (*MessageSignature, error) {
+	path := "/v1/messages/sign?" + url.Values{"chain_id": {chainID}, "type": {msgType}}.Encode()
+	var sig MessageSignature
+	if err := c.do(ctx, http.MethodPost, path, bytes.NewReader(payload), &sig); err != nil {
+		return nil, err
+	}
+	return &sig, nil
+}
+
+// Probe returns nil if a GET request of the path responds with 200 OK, e.g. for the liveness and
+// readiness probes, otherwise an error with the response.
+func (c *AdminClient) Probe(ctx context.Context, path string) error {
+	var v json.RawMessage
+	return c.get(ctx, path, &v)
+}
+
+// get decodes the JSON response to a GET request of the path into v.
+func (c *AdminClient) get(ctx context.Context, path string, v any) error {
+	return c.do(ctx, http.MethodGet, path, nil, v)
+}
+
+// do decodes the JSON response to a request of the path with the method and body, which may be nil,
+// into v.
+func (c *AdminClient) do(ctx context.Context, method, path string, body io.Reader, v any) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	switch {
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	case c.username != "":
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query %s: %w", c.name, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("%s %s: %s: %s", c.name, path, res.Status, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(res.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode %s %s: %w", c.name, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,525 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	cometservice "github.com/cometbft/cometbft/libs/service"
+)
+
+const (
+	defaultAlertMissedPrecommits = 3
+	defaultPagerDutyEventsURL    = "https://events.pagerduty.com/v2/enqueue"
+
+	alertQueueSize      = 100
+	alertWebhookTimeout = 5 * time.Second
+	alertWatchInterval  = time.Second
+)
+
+// Alert webhook formats.
+const (
+	AlertFormatGeneric   = "generic"
+	AlertFormatSlack     = "slack"
+	AlertFormatPagerDuty = "pagerduty"
+)
+
+// Alert events.
+const (
+	AlertEventMissedPrecommits    = "missed_precommits"
+	AlertEventLeaderChange        = "leader_change"
+	AlertEventCosignerUnreachable = "cosigner_unreachable"
+	AlertEventCosignerReachable   = "cosigner_reachable"
+	AlertEventDoubleSignAttempt   = "double_sign_attempt"
+	AlertEventMissedBlock         = "missed_block"
+	AlertEventSplitBrain          = "split_brain"
+)
+
+// Alert severities.
+const (
+	AlertSeverityCritical = "critical"
+	AlertSeverityWarning  = "warning"
+	AlertSeverityInfo     = "info"
+)
+
+var alertEvents = []string{
+	AlertEventMissedPrecommits,
+	AlertEventLeaderChange,
+	AlertEventCosignerUnreachable,
+	AlertEventCosignerReachable,
+	AlertEventDoubleSignAttempt,
+	AlertEventMissedBlock,
+	AlertEventSplitBrain,
+}
+
+// AlertsConfig configures the webhooks notified of signing anomalies.
+type AlertsConfig struct {
+	Webhooks []AlertWebhookConfig `yaml:"webhooks"`
+
+	// MissedPrecommits is the number of consecutive heights without a precommit signature
+	// that triggers a missed precommits alert. Defaults to 3.
+	MissedPrecommits int64 `yaml:"missedPrecommits,omitempty"`
+}
+
+// AlertWebhookConfig configures a webhook notified of alerts.
+type AlertWebhookConfig struct {
+	// URL the alerts are POSTed to. Defaults to the PagerDuty Events API for the pagerduty format.
+	URL string `yaml:"url,omitempty"`
+
+	// Format of the request body, generic (default), slack or pagerduty.
+	Format string `yaml:"format,omitempty"`
+
+	// RoutingKey is the integration key of the PagerDuty service, required for the pagerduty format.
+	RoutingKey string `yaml:"routingKey,omitempty"`
+
+	// Events the webhook is notified of. Defaults to all events.
+	Events []string `yaml:"events,omitempty"`
+}
+
+func (cfg *AlertsConfig) Validate() error {
+	if cfg == nil {
+		return nil
+	}
+	if len(cfg.Webhooks) == 0 {
+		return fmt.Errorf("alerts require at least one webhook")
+	}
+	if cfg.MissedPrecommits < 0 {
+		return fmt.Errorf("alerts missedPrecommits must not be negative")
+	}
+	for i, w := range cfg.Webhooks {
+		if err := w.Validate(); err != nil {
+			return fmt.Errorf("invalid alert webhook %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (cfg AlertWebhookConfig) Validate() error {
+	switch cfg.format() {
+	case AlertFormatGeneric, AlertFormatSlack:
+		if cfg.URL == "" {
+			return fmt.Errorf("url is required")
+		}
+	case AlertFormatPagerDuty:
+		if cfg.RoutingKey == "" {
+			return fmt.Errorf("routingKey is required for the pagerduty format")
+		}
+	default:
+		return fmt.Errorf(
+			"invalid format %q, must be %s, %s or %s",
+			cfg.Format, AlertFormatGeneric, AlertFormatSlack, AlertFormatPagerDuty,
+		)
+	}
+	if _, err := url.ParseRequestURI(cfg.url()); err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	for _, event := range cfg.Events {
+		if !isAlertEvent(event) {
+			return fmt.Errorf("invalid event %q, must be one of %v", event, alertEvents)
+		}
+	}
+	return nil
+}
+
+func (cfg AlertWebhookConfig) format() string {
+	if cfg.Format == "" {
+		return AlertFormatGeneric
+	}
+	return cfg.Format
+}
+
+func (cfg AlertWebhookConfig) url() string {
+	if cfg.URL == "" && cfg.format() == AlertFormatPagerDuty {
+		return defaultPagerDutyEventsURL
+	}
+	return cfg.URL
+}
+
+func (cfg AlertWebhookConfig) notifies(event string) bool {
+	if len(cfg.Events) == 0 {
+		return true
+	}
+	for _, e := range cfg.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func isAlertEvent(event string) bool {
+	for _, e := range alertEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Alert is a notification of a signing anomaly.
+type Alert struct {
+	Event    string    `json:"event"`
+	Severity string    `json:"severity"`
+	Message  string    `json:"message"`
+	Source   string    `json:"source"`
+	Time     time.Time `json:"time"`
+	ChainID  string    `json:"chain_id,omitempty"`
+	Height   int64     `json:"height,omitempty"`
+	Cosigner int       `json:"cosigner,omitempty"`
+}
+
+// AlertNotifier POSTs alerts to the configured webhooks. Alerts are queued and sent in
+// the background, so that a slow webhook never delays signing. In threshold mode, it watches
+// the health of the cosigner for leader changes and unreachable peer cosigners.
+type AlertNotifier struct {
+	cometservice.BaseService
+
+	logger cometlog.Logger
+	cfg    *AlertsConfig
+	health *Health
+	source string
+	client *http.Client
+
+	queue   chan Alert
+	cancel  context.CancelFunc
+	stopped sync.WaitGroup
+
+	// state of the last health check, leader is 0 before the first check.
+	leader     int
+	reachable  map[int]bool
+	splitBrain *SplitBrainHealth
+}
+
+// NewAlertNotifier returns an AlertNotifier for cfg. health is watched for leader changes
+// and unreachable cosigners in threshold mode, and may be nil.
+func NewAlertNotifier(logger cometlog.Logger, cfg *AlertsConfig, health *Health) (*AlertNotifier, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	source, err := os.Hostname()
+	if err != nil {
+		source = "horcrux"
+	}
+	n := &AlertNotifier{
+		logger:    logger,
+		cfg:       cfg,
+		health:    health,
+		source:    source,
+		client:    &http.Client{Timeout: alertWebhookTimeout},
+		queue:     make(chan Alert, alertQueueSize),
+		reachable: make(map[int]bool),
+	}
+	n.BaseService = *cometservice.NewBaseService(logger, "AlertNotifier", n)
+	return n, nil
+}
+
+func (n *AlertNotifier) OnStart() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	n.cancel = cancel
+
+	n.stopped.Add(1)
+	go func() {
+		defer n.stopped.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case alert := <-n.queue:
+				n.send(ctx, alert)
+			}
+		}
+	}()
+
+	if n.health != nil && n.health.threshold != nil {
+		n.stopped.Add(1)
+		go func() {
+			defer n.stopped.Done()
+			ticker := time.NewTicker(alertWatchInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					n.checkHealth(n.health.threshold.Health())
+				}
+			}
+		}()
+	}
+	return nil
+}
+
+func (n *AlertNotifier) OnStop() {
+	n.cancel()
+	n.stopped.Wait()
+}
+
+// Notify queues the alert for the webhooks. The alert is dropped if the queue is full.
+func (n *AlertNotifier) Notify(alert Alert) {
+	alert.Source = n.source
+	if alert.Time.IsZero() {
+		alert.Time = time.Now()
+	}
+	n.logger.Info(
+		"Alert",
+		"event", alert.Event,
+		"severity", alert.Severity,
+		"message", alert.Message,
+	)
+	select {
+	case n.queue <- alert:
+	default:
+		totalDroppedAlerts.Inc()
+		n.logger.Error("Alert queue is full, dropping alert", "event", alert.Event)
+	}
+}
+
+// checkHealth notifies of changes of the leader and of the reachability of peer cosigners
+// since the last check.
+func (n *AlertNotifier) checkHealth(health ThresholdHealth) {
+	if health.Leader != n.leader {
+		// the leader at startup is not a change.
+		if n.leader != 0 {
+			severity, cosigner := AlertSeverityInfo, health.Leader
+			var message string
+			switch {
+			case health.Leader == -1:
+				severity, cosigner = AlertSeverityWarning, n.leader
+				message = fmt.Sprintf("raft lost its leader, cosigner %d", n.leader)
+			case n.leader == -1:
+				message = fmt.Sprintf("raft elected cosigner %d as leader", health.Leader)
+			default:
+				message = fmt.Sprintf("raft leader changed from cosigner %d to cosigner %d", n.leader, health.Leader)
+			}
+			n.Notify(Alert{
+				Event:    AlertEventLeaderChange,
+				Severity: severity,
+				Message:  message,
+				Cosigner: cosigner,
+			})
+		}
+		n.leader = health.Leader
+	}
+
+	n.checkSplitBrain(health)
+
+	for _, p := range health.Peers {
+		if p.Reachable == nil {
+			// only the leader pings the peers.
+			delete(n.reachable, p.ID)
+			continue
+		}
+		wasReachable, known := n.reachable[p.ID]
+		n.reachable[p.ID] = *p.Reachable
+		switch {
+		case !*p.Reachable && (!known || wasReachable):
+			n.Notify(Alert{
+				Event:    AlertEventCosignerUnreachable,
+				Severity: AlertSeverityWarning,
+				Message:  fmt.Sprintf("cosigner %d at %s is unreachable", p.ID, p.Address),
+				Cosigner: p.ID,
+			})
+		case *p.Reachable && known && !wasReachable:
+			n.Notify(Alert{
+				Event:    AlertEventCosignerReachable,
+				Severity: AlertSeverityInfo,
+				Message:  fmt.Sprintf("cosigner %d at %s is reachable again", p.ID, p.Address),
+				Cosigner: p.ID,
+			})
+		}
+	}
+}
+
+// checkSplitBrain notifies when another cosigner starts or stops claiming the leadership at the
+// same time as this cosigner.
+func (n *AlertNotifier) checkSplitBrain(health ThresholdHealth) {
+	last, splitBrain := n.splitBrain, health.SplitBrain
+	n.splitBrain = splitBrain
+	switch {
+	case splitBrain != nil && (last == nil || *last != *splitBrain):
+		fenced := splitBrain.Leader
+		if splitBrain.Fenced {
+			fenced = health.CosignerID
+		}
+		n.Notify(Alert{
+			Event:    AlertEventSplitBrain,
+			Severity: AlertSeverityCritical,
+			Message: fmt.Sprintf(
+				"split brain, cosigners %d and %d both claim the leadership, cosigner %d is fenced from signing",
+				health.CosignerID, splitBrain.Leader, fenced,
+			),
+			Cosigner: splitBrain.Leader,
+		})
+	case splitBrain == nil && last != nil:
+		n.Notify(Alert{
+			Event:    AlertEventSplitBrain,
+			Severity: AlertSeverityInfo,
+			Message:  fmt.Sprintf("split brain resolved, cosigner %d no longer claims the leadership", last.Leader),
+			Cosigner: last.Leader,
+		})
+	}
+}
+
+func (n *AlertNotifier) send(ctx context.Context, alert Alert) {
+	for _, w := range n.cfg.Webhooks {
+		if !w.notifies(alert.Event) {
+			continue
+		}
+		if err := n.post(ctx, w, alert); err != nil && !errors.Is(err, context.Canceled) {
+			totalAlertWebhookFailures.WithLabelValues(w.format()).Inc()
+			n.logger.Error("Failed to send alert", "event", alert.Event, "format", w.format(), "error", err)
+		}
+	}
+}
+
+func (n *AlertNotifier) post(ctx context.Context, w AlertWebhookConfig, alert Alert) error {
+	body, err := json.Marshal(alertPayload(w, alert))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", res.Status)
+	}
+	return nil
+}
+
+// alertPayload returns the request body of the alert in the format of the webhook.
+func alertPayload(w AlertWebhookConfig, alert Alert) any {
+	switch w.format() {
+	case AlertFormatSlack:
+		return map[string]string{
+			"text": fmt.Sprintf("[horcrux %s] %s: %s", alert.Source, alert.Severity, alert.Message),
+		}
+	case AlertFormatPagerDuty:
+		// alert severities are PagerDuty severities.
+		return map[string]any{
+			"routing_key":  w.RoutingKey,
+			"event_action": "trigger",
+			"payload": map[string]any{
+				"summary":        alert.Message,
+				"source":         alert.Source,
+				"severity":       alert.Severity,
+				"timestamp":      alert.Time.UTC().Format(time.RFC3339),
+				"component":      "horcrux",
+				"class":          alert.Event,
+				"custom_details": alert,
+			},
+		}
+	default:
+		return alert
+	}
+}
+
+// AlertValidator is a PrivValidator that notifies an AlertNotifier of missed precommits
+// and of rejected double sign attempts.
+type AlertValidator struct {
+	val              PrivValidator
+	notifier         *AlertNotifier
+	missedPrecommits int64
+
+	mu sync.Mutex
+	// lastPrecommit is the height of the last precommit signed for each chain.
+	lastPrecommit map[string]int64
+}
+
+// NewAlertValidator returns an AlertValidator for the sign requests of val.
+func NewAlertValidator(val PrivValidator, notifier *AlertNotifier) *AlertValidator {
+	missedPrecommits := notifier.cfg.MissedPrecommits
+	if missedPrecommits == 0 {
+		missedPrecommits = defaultAlertMissedPrecommits
+	}
+	return &AlertValidator{
+		val:              val,
+		notifier:         notifier,
+		missedPrecommits: missedPrecommits,
+		lastPrecommit:    make(map[string]int64),
+	}
+}
+
+// Sign implements PrivValidator.
+func (v *AlertValidator) Sign(ctx context.Context, chainID string, block Block) ([]byte, time.Time, error) {
+	sig, timestamp, err := v.val.Sign(ctx, chainID, block)
+	if err != nil {
+		if isDoubleSignAttempt(err) {
+			v.notifier.Notify(Alert{
+				Event:    AlertEventDoubleSignAttempt,
+				Severity: AlertSeverityCritical,
+				Message: fmt.Sprintf(
+					"rejected double sign attempt for chain %s at %d.%d.%d: %v",
+					chainID, block.Height, block.Round, block.Step, err,
+				),
+				ChainID: chainID,
+				Height:  block.Height,
+			})
+		}
+		return sig, timestamp, err
+	}
+
+	if block.Step == stepPrecommit {
+		v.checkMissedPrecommits(chainID, block.Height)
+	}
+	return sig, timestamp, nil
+}
+
+func (v *AlertValidator) checkMissedPrecommits(chainID string, height int64) {
+	v.mu.Lock()
+	last := v.lastPrecommit[chainID]
+	if height > last {
+		v.lastPrecommit[chainID] = height
+	}
+	v.mu.Unlock()
+
+	if last == 0 {
+		return
+	}
+	if missed := height - last - 1; missed >= v.missedPrecommits {
+		v.notifier.Notify(Alert{
+			Event:    AlertEventMissedPrecommits,
+			Severity: AlertSeverityWarning,
+			Message: fmt.Sprintf(
+				"missed %d precommits for chain %s, between heights %d and %d",
+				missed, chainID, last, height,
+			),
+			ChainID: chainID,
+			Height:  height,
+		})
+	}
+}
+
+// GetPubKey implements PrivValidator.
+func (v *AlertValidator) GetPubKey(ctx context.Context, chainID string) ([]byte, error) {
+	return v.val.GetPubKey(ctx, chainID)
+}
+
+// Stop implements PrivValidator.
+func (v *AlertValidator) Stop() {
+	v.val.Stop()
+}
+
+// isDoubleSignAttempt returns true if the sign request was rejected because a different block
+// was already signed at the same height, round and step.
+func isDoubleSignAttempt(err error) bool {
+	var (
+		conflictingErr *ConflictingDataError
+		blockIDsErr    *DiffBlockIDsError
+	)
+	return errors.As(err, &conflictingErr) || errors.As(err, &blockIDsErr)
+}
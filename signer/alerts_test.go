@@ -0,0 +1,174 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+)
+
+type rejectingPrivValidator struct {
+	err error
+}
+
+func (pv *rejectingPrivValidator) Sign(_ context.Context, _ string, block Block) ([]byte, time.Time, error) {
+	return nil, block.Timestamp, pv.err
+}
+
+func (pv *rejectingPrivValidator) GetPubKey(context.Context, string) ([]byte, error) { return nil, nil }
+
+func (pv *rejectingPrivValidator) Stop() {}
+
+func TestAlertsConfigValidate(t *testing.T) {
+	var cfg *AlertsConfig
+	require.NoError(t, cfg.Validate())
+
+	require.Error(t, (&AlertsConfig{}).Validate())
+	require.NoError(t, (&AlertsConfig{Webhooks: []AlertWebhookConfig{{URL: "https://example.com/hook"}}}).Validate())
+	require.Error(t, (&AlertsConfig{Webhooks: []AlertWebhookConfig{{Format: AlertFormatSlack}}}).Validate())
+	require.Error(t, (&AlertsConfig{Webhooks: []AlertWebhookConfig{{Format: AlertFormatPagerDuty}}}).Validate())
+	require.NoError(t, (&AlertsConfig{Webhooks: []AlertWebhookConfig{
+		{Format: AlertFormatPagerDuty, RoutingKey: "key"},
+	}}).Validate())
+	require.Error(t, (&AlertsConfig{Webhooks: []AlertWebhookConfig{
+		{URL: "https://example.com/hook", Format: "email"},
+	}}).Validate())
+	require.Error(t, (&AlertsConfig{Webhooks: []AlertWebhookConfig{
+		{URL: "https://example.com/hook", Events: []string{"unknown"}},
+	}}).Validate())
+}
+
+func TestAlertNotifierWebhooks(t *testing.T) {
+	var mu sync.Mutex
+	bodies := make(map[string][]map[string]any)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		bz, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(bz, &body))
+		mu.Lock()
+		bodies[r.URL.Path] = append(bodies[r.URL.Path], body)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	notifier, err := NewAlertNotifier(cometlog.NewNopLogger(), &AlertsConfig{
+		Webhooks: []AlertWebhookConfig{
+			{URL: srv.URL + "/generic"},
+			{URL: srv.URL + "/slack", Format: AlertFormatSlack},
+			{URL: srv.URL + "/pagerduty", Format: AlertFormatPagerDuty, RoutingKey: "key"},
+			{URL: srv.URL + "/filtered", Events: []string{AlertEventLeaderChange}},
+		},
+	}, nil)
+	require.NoError(t, err)
+	require.NoError(t, notifier.Start())
+	defer func() { _ = notifier.Stop() }()
+
+	val := NewAlertValidator(&rejectingPrivValidator{err: newConflictingDataError([]byte{1}, []byte{2})}, notifier)
+	_, _, err = val.Sign(context.Background(), "horcrux-1", Block{Height: 10, Step: stepPrevote})
+	require.Error(t, err)
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(bodies["/generic"]) == 1 && len(bodies["/slack"]) == 1 && len(bodies["/pagerduty"]) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	generic := bodies["/generic"][0]
+	require.Equal(t, AlertEventDoubleSignAttempt, generic["event"])
+	require.Equal(t, AlertSeverityCritical, generic["severity"])
+	require.Equal(t, "horcrux-1", generic["chain_id"])
+
+	require.Contains(t, bodies["/slack"][0]["text"], "rejected double sign attempt for chain horcrux-1")
+
+	pagerduty := bodies["/pagerduty"][0]
+	require.Equal(t, "key", pagerduty["routing_key"])
+	require.Equal(t, "trigger", pagerduty["event_action"])
+	require.Equal(t, AlertSeverityCritical, pagerduty["payload"].(map[string]any)["severity"])
+
+	require.Empty(t, bodies["/filtered"])
+}
+
+func TestAlertValidatorMissedPrecommits(t *testing.T) {
+	notifier, err := NewAlertNotifier(cometlog.NewNopLogger(), &AlertsConfig{
+		Webhooks:         []AlertWebhookConfig{{URL: "http://localhost/hook"}},
+		MissedPrecommits: 2,
+	}, nil)
+	require.NoError(t, err)
+
+	val := NewAlertValidator(&mockPrivValidator{}, notifier)
+	sign := func(height int64, step int8) {
+		_, _, err := val.Sign(context.Background(), "horcrux-1", Block{Height: height, Step: step})
+		require.NoError(t, err)
+	}
+
+	sign(10, stepPrecommit)
+	sign(11, stepPrecommit)
+	sign(13, stepPrecommit) // 1 missed
+	sign(20, stepPrevote)
+	require.Empty(t, notifier.queue)
+
+	sign(16, stepPrecommit) // 2 missed
+	require.Len(t, notifier.queue, 1)
+	alert := <-notifier.queue
+	require.Equal(t, AlertEventMissedPrecommits, alert.Event)
+	require.Equal(t, int64(16), alert.Height)
+
+	// regressions of a lagging sentry are not missed precommits.
+	sign(14, stepPrecommit)
+	sign(17, stepPrecommit)
+	require.Empty(t, notifier.queue)
+}
+
+func TestAlertNotifierCheckHealth(t *testing.T) {
+	notifier, err := NewAlertNotifier(cometlog.NewNopLogger(), &AlertsConfig{
+		Webhooks: []AlertWebhookConfig{{URL: "http://localhost/hook"}},
+	}, nil)
+	require.NoError(t, err)
+
+	reachable, unreachable := true, false
+	events := func() (out []string) {
+		for len(notifier.queue) > 0 {
+			out = append(out, (<-notifier.queue).Event)
+		}
+		return out
+	}
+
+	notifier.checkHealth(ThresholdHealth{Leader: 1, Peers: []PeerHealth{{ID: 2, Reachable: &reachable}}})
+	require.Empty(t, events())
+
+	notifier.checkHealth(ThresholdHealth{Leader: 1, Peers: []PeerHealth{{ID: 2, Reachable: &unreachable}}})
+	require.Equal(t, []string{AlertEventCosignerUnreachable}, events())
+
+	notifier.checkHealth(ThresholdHealth{Leader: 1, Peers: []PeerHealth{{ID: 2, Reachable: &unreachable}}})
+	require.Empty(t, events())
+
+	notifier.checkHealth(ThresholdHealth{Leader: 1, Peers: []PeerHealth{{ID: 2, Reachable: &reachable}}})
+	require.Equal(t, []string{AlertEventCosignerReachable}, events())
+
+	notifier.checkHealth(ThresholdHealth{Leader: -1, Peers: []PeerHealth{{ID: 2}}})
+	require.Equal(t, []string{AlertEventLeaderChange}, events())
+
+	notifier.checkHealth(ThresholdHealth{Leader: 2, Peers: []PeerHealth{{ID: 2}}})
+	require.Equal(t, []string{AlertEventLeaderChange}, events())
+	splitBrain := &SplitBrainHealth{Leader: 2, Fenced: true}
+	notifier.checkHealth(ThresholdHealth{CosignerID: 1, Leader: 2, SplitBrain: splitBrain})
+	require.Equal(t, []string{AlertEventSplitBrain}, events())
+
+	notifier.checkHealth(ThresholdHealth{CosignerID: 1, Leader: 2, SplitBrain: splitBrain})
+	require.Empty(t, events())
+
+	notifier.checkHealth(ThresholdHealth{CosignerID: 1, Leader: 2})
+	require.Equal(t, []string{AlertEventSplitBrain}, events())
+}
@@ -0,0 +1,398 @@
+package signer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	cometbytes "github.com/cometbft/cometbft/libs/bytes"
+	cometlog "github.com/cometbft/cometbft/libs/log"
+)
+
+const (
+	defaultAuditLogFile = "audit.log"
+	auditLogHeadSuffix  = ".head"
+)
+
+// AuditLogConfig configures the tamper-evident audit log of sign decisions.
+type AuditLogConfig struct {
+	// Path of the log file. Relative paths are relative to the state directory.
+	// Defaults to audit.log.
+	Path string `yaml:"path,omitempty"`
+}
+
+// AuditLog opens the configured audit log. See OpenAuditLog.
+func (c RuntimeConfig) AuditLog() (*AuditLog, error) {
+	return OpenAuditLog(c.AuditLogFile())
+}
+
+// AuditLogFile returns the path of the audit log.
+func (c RuntimeConfig) AuditLogFile() string {
+	path := defaultAuditLogFile
+	if cfg := c.Config.AuditLog; cfg != nil && cfg.Path != "" {
+		path = cfg.Path
+	}
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(c.StateDir, path)
+}
+
+// AuditEntry is an entry of the audit log. Hash commits to the entry and, through PrevHash,
+// to every entry before it.
+type AuditEntry struct {
+	Seq     uint64    `json:"seq"`
+	Time    time.Time `json:"time"`
+	ChainID string    `json:"chain_id"`
+	Height  int64     `json:"height"`
+	Round   int64     `json:"round"`
+	Step    int8      `json:"step"`
+
+	// SignBytesHash is the sha256 hash of the sign bytes of the request.
+	SignBytesHash cometbytes.HexBytes `json:"signbytes_hash"`
+
+	// Operation, Request and Approvers are set on the entries of admin operations that require the
+	// approval of several operators, instead of the chain ID, height, round, step and sign bytes hash.
+	// Approvals are the signatures of the approvals of an approved operation, which are used up.
+	Operation string                `json:"operation,omitempty"`
+	Request   string                `json:"request,omitempty"`
+	Approvers []string              `json:"approvers,omitempty"`
+	Approvals []cometbytes.HexBytes `json:"approvals,omitempty"`
+
+	// Decision is one of signed, rejected or error, or approved or refused for admin operations.
+	// Reason is the error of requests that were not signed or approved.
+	Decision string `json:"decision"`
+	Reason   string `json:"reason,omitempty"`
+
+	PrevHash cometbytes.HexBytes `json:"prev_hash"`
+	Hash     cometbytes.HexBytes `json:"hash,omitempty"`
+}
+
+// computeHash returns the sha256 hash of the entry without its hash.
+func (e AuditEntry) computeHash() ([]byte, error) {
+	e.Hash = nil
+	bz, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(bz)
+	return sum[:], nil
+}
+
+// AuditLogHead is the last entry of the audit log. It is kept next to the log so that
+// the truncation of entries from the end of the log can be detected.
+type AuditLogHead struct {
+	Seq  uint64              `json:"seq"`
+	Hash cometbytes.HexBytes `json:"hash"`
+}
+
+// auditLogGenesisHash is the previous hash of the first entry.
+var auditLogGenesisHash = make([]byte, sha256.Size)
+
+type AuditLogTamperedError struct {
+	msg string
+}
+
+func (e *AuditLogTamperedError) Error() string { return e.msg }
+
+func newAuditLogTamperedError(path string, line int, format string, args ...any) *AuditLogTamperedError {
+	return &AuditLogTamperedError{
+		msg: fmt.Sprintf("audit log %s has been tampered with at line %d: %s", path, line, fmt.Sprintf(format, args...)),
+	}
+}
+
+// AuditLog is an append-only, hash-chained log of sign decisions. Each entry commits to the
+// previous one, so that a modified, removed or reordered entry breaks the chain, and the head
+// file records the last entry, so that a truncated log no longer matches it.
+type AuditLog struct {
+	path string
+
+	// approvals are the times the signatures of the approvals in the log were used.
+	approvals map[string]time.Time
+
+	mu   sync.Mutex
+	file *os.File
+	head AuditLogHead
+}
+
+// OpenAuditLog opens the audit log at path for appending. An entry that was only partially written
+// by a crash is truncated. It returns an AuditLogTamperedError if the log does not verify.
+func OpenAuditLog(path string) (*AuditLog, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	approvals := make(map[string]time.Time)
+	head, valid, err := verifyAuditLog(path, f, func(e AuditEntry) {
+		for _, sig := range e.Approvals {
+			approvals[string(sig)] = e.Time
+		}
+	})
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	if err := f.Truncate(valid); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(valid, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &AuditLog{path: path, approvals: approvals, file: f, head: head}, nil
+}
+
+// VerifyAuditLog verifies the hash chain of the audit log at path against its head file,
+// and returns the head of the log.
+func VerifyAuditLog(path string) (AuditLogHead, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return AuditLogHead{}, err
+	}
+	defer f.Close()
+
+	head, _, err := verifyAuditLog(path, f, nil)
+	return head, err
+}
+
+// verifyAuditLog verifies the entries read from r and returns the head of the log and
+// the size of its complete entries. A trailing line without a newline is a partial write
+// and is not verified. The head file may lag the log by the last entry, which is written first.
+// onEntry, if not nil, is called with each verified entry.
+func verifyAuditLog(path string, r io.Reader, onEntry func(AuditEntry)) (AuditLogHead, int64, error) {
+	head := AuditLogHead{Hash: auditLogGenesisHash}
+	var prevHash []byte
+
+	br := bufio.NewReader(r)
+	var valid int64
+	for line := 1; ; line++ {
+		bz, err := br.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return head, valid, err
+		}
+
+		var e AuditEntry
+		if err := json.Unmarshal(bytes.TrimSpace(bz), &e); err != nil {
+			return head, valid, newAuditLogTamperedError(path, line, "invalid entry: %v", err)
+		}
+		if e.Seq != head.Seq+1 {
+			return head, valid, newAuditLogTamperedError(path, line, "expected seq %d, got %d", head.Seq+1, e.Seq)
+		}
+		if !bytes.Equal(e.PrevHash, head.Hash) {
+			return head, valid, newAuditLogTamperedError(path, line, "previous hash does not match the previous entry")
+		}
+		hash, err := e.computeHash()
+		if err != nil {
+			return head, valid, err
+		}
+		if !bytes.Equal(e.Hash, hash) {
+			return head, valid, newAuditLogTamperedError(path, line, "hash does not match the entry")
+		}
+
+		prevHash = head.Hash
+		head = AuditLogHead{Seq: e.Seq, Hash: e.Hash}
+		valid += int64(len(bz))
+		if onEntry != nil {
+			onEntry(e)
+		}
+	}
+
+	recorded, err := readAuditLogHead(path)
+	if err != nil {
+		return head, valid, err
+	}
+	switch {
+	case recorded.Seq == head.Seq && bytes.Equal(recorded.Hash, head.Hash):
+	case recorded.Seq+1 == head.Seq && bytes.Equal(recorded.Hash, prevHash):
+		// the signer stopped between appending the entry and updating the head file.
+	default:
+		return head, valid, newAuditLogTamperedError(path, int(head.Seq)+1,
+			"the log ends at seq %d, but its head file records seq %d", head.Seq, recorded.Seq)
+	}
+
+	return head, valid, nil
+}
+
+// readAuditLogHead reads the head file of the audit log at path. A missing head file
+// is the head of an empty log.
+func readAuditLogHead(path string) (AuditLogHead, error) {
+	head := AuditLogHead{Hash: auditLogGenesisHash}
+
+	bz, err := readStateFile(path + auditLogHeadSuffix)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return head, nil
+		}
+		return head, err
+	}
+	if err := json.Unmarshal(bz, &head); err != nil {
+		return head, fmt.Errorf("invalid audit log head file: %w", err)
+	}
+	return head, nil
+}
+
+// Record appends an entry with the decision on the sign request for the block.
+func (l *AuditLog) Record(chainID string, block Block, signErr error) error {
+	signBytesHash := sha256.Sum256(block.SignBytes)
+	e := AuditEntry{
+		ChainID:       chainID,
+		Height:        block.Height,
+		Round:         block.Round,
+		Step:          block.Step,
+		SignBytesHash: signBytesHash[:],
+		Decision:      signDecisionOutcome(signErr),
+	}
+	if signErr != nil {
+		e.Reason = signErr.Error()
+	}
+	return l.append(e)
+}
+
+// RecordAdminOperation appends an entry with the decision on the admin request of the operation,
+// approved by the approvers with the signatures of their approvals unless err is not nil.
+func (l *AuditLog) RecordAdminOperation(
+	operation, request string,
+	approvers []string,
+	approvals [][]byte,
+	err error,
+) error {
+	e := AuditEntry{
+		Operation: operation,
+		Request:   request,
+		Approvers: approvers,
+		Decision:  "approved",
+	}
+	if err != nil {
+		e.Decision = "refused"
+		e.Reason = err.Error()
+	} else {
+		for _, sig := range approvals {
+			e.Approvals = append(e.Approvals, sig)
+		}
+	}
+	return l.append(e)
+}
+
+// usedApprovals returns the times the signatures of the approvals in the log when it was opened
+// were used, so that an approval is not used again after a restart.
+func (l *AuditLog) usedApprovals() map[string]time.Time {
+	return l.approvals
+}
+
+// append appends the entry after the head of the log, and updates the head file.
+func (l *AuditLog) append(e AuditEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e.Seq = l.head.Seq + 1
+	e.Time = time.Now().UTC()
+	e.PrevHash = l.head.Hash
+
+	hash, err := e.computeHash()
+	if err != nil {
+		return err
+	}
+	e.Hash = hash
+
+	bz, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	bz = append(bz, '\n')
+
+	if _, err := l.file.Write(bz); err != nil {
+		return err
+	}
+	if err := l.file.Sync(); err != nil {
+		return err
+	}
+
+	head := AuditLogHead{Seq: e.Seq, Hash: e.Hash}
+	headBz, err := json.Marshal(head)
+	if err != nil {
+		return err
+	}
+	if err := writeStateFile(l.path+auditLogHeadSuffix, headBz); err != nil {
+		return err
+	}
+	l.head = head
+	return nil
+}
+
+// Head returns the last entry of the log.
+func (l *AuditLog) Head() AuditLogHead {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.head
+}
+
+// Close closes the log.
+func (l *AuditLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// AuditLogValidator is a PrivValidator that records the decision on every sign request in an AuditLog.
+type AuditLogValidator struct {
+	logger cometlog.Logger
+	val    PrivValidator
+	log    *AuditLog
+}
+
+// NewAuditLogValidator returns an AuditLogValidator that records the sign decisions of val in log.
+func NewAuditLogValidator(logger cometlog.Logger, val PrivValidator, log *AuditLog) *AuditLogValidator {
+	return &AuditLogValidator{
+		logger: logger,
+		val:    val,
+		log:    log,
+	}
+}
+
+// Sign implements PrivValidator.
+func (v *AuditLogValidator) Sign(ctx context.Context, chainID string, block Block) ([]byte, time.Time, error) {
+	sig, timestamp, signErr := v.val.Sign(ctx, chainID, block)
+
+	if err := v.log.Record(chainID, block, signErr); err != nil {
+		// the signature is already in the sign state, withholding it does not make it safer.
+		v.logger.Error(
+			"Failed to record sign decision in audit log",
+			"chain_id", chainID,
+			"height", block.Height,
+			"round", block.Round,
+			"step", block.Step,
+			"error", err,
+		)
+	}
+
+	return sig, timestamp, signErr
+}
+
+// GetPubKey implements PrivValidator.
+func (v *AuditLogValidator) GetPubKey(ctx context.Context, chainID string) ([]byte, error) {
+	return v.val.GetPubKey(ctx, chainID)
+}
+
+// Stop implements PrivValidator.
+func (v *AuditLogValidator) Stop() {
+	v.val.Stop()
+	if err := v.log.Close(); err != nil {
+		v.logger.Error("Failed to close audit log", "error", err)
+	}
+}
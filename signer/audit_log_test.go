@@ -0,0 +1,100 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	l, err := OpenAuditLog(path)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	block := Block{Height: 10, Round: 0, Step: stepPrevote, SignBytes: []byte("sign bytes")}
+
+	_, _, err = NewAuditLogValidator(log.NewNopLogger(), &mockPrivValidator{}, l).Sign(ctx, testChainID, block)
+	require.NoError(t, err)
+
+	_, _, err = NewAuditLogValidator(log.NewNopLogger(), &failingPrivValidator{
+		err: &HeightRegressionError{regressed: 9, last: 10},
+	}, l).Sign(ctx, testChainID, block)
+	require.Error(t, err)
+
+	require.NoError(t, l.Close())
+
+	// the chain continues after a restart.
+	l, err = OpenAuditLog(path)
+	require.NoError(t, err)
+	lagging := l.Head()
+	require.Equal(t, uint64(2), lagging.Seq)
+
+	_, _, err = NewAuditLogValidator(log.NewNopLogger(), &failingPrivValidator{
+		err: errors.New("insufficient cosigners"),
+	}, l).Sign(ctx, testChainID, block)
+	require.Error(t, err)
+	head := l.Head()
+	require.NoError(t, l.Close())
+
+	verified, err := VerifyAuditLog(path)
+	require.NoError(t, err)
+	require.Equal(t, head, verified)
+	require.Equal(t, uint64(3), verified.Seq)
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := bytes.SplitAfter(contents, []byte("\n"))
+	require.Contains(t, string(lines[0]), `"decision":"signed"`)
+	require.Contains(t, string(lines[1]), `"decision":"rejected"`)
+	require.Contains(t, string(lines[2]), `"decision":"error","reason":"insufficient cosigners"`)
+
+	var tamperedErr *AuditLogTamperedError
+
+	// modified entry.
+	modified := bytes.Replace(contents, []byte(`"height":10`), []byte(`"height":11`), 1)
+	require.NoError(t, os.WriteFile(path, modified, 0600))
+	_, err = VerifyAuditLog(path)
+	require.ErrorAs(t, err, &tamperedErr)
+	_, err = OpenAuditLog(path)
+	require.ErrorAs(t, err, &tamperedErr)
+
+	// removed entry.
+	require.NoError(t, os.WriteFile(path, bytes.Join([][]byte{lines[0], lines[2]}, nil), 0600))
+	_, err = VerifyAuditLog(path)
+	require.ErrorAs(t, err, &tamperedErr)
+
+	// truncated log.
+	require.NoError(t, os.WriteFile(path, bytes.Join(lines[:1], nil), 0600))
+	_, err = VerifyAuditLog(path)
+	require.ErrorAs(t, err, &tamperedErr)
+
+	// the head file lags the log by one entry if the signer stopped before updating it.
+	require.NoError(t, os.WriteFile(path, contents, 0600))
+	headBz, err := json.Marshal(lagging)
+	require.NoError(t, err)
+	require.NoError(t, writeStateFile(path+auditLogHeadSuffix, headBz))
+	verified, err = VerifyAuditLog(path)
+	require.NoError(t, err)
+	require.Equal(t, head, verified)
+
+	// a partial entry is truncated when the log is opened.
+	require.NoError(t, os.WriteFile(path, append(bytes.Join(lines[:3], nil), `{"seq":4,"ti`...), 0600))
+	l, err = OpenAuditLog(path)
+	require.NoError(t, err)
+	require.Equal(t, head, l.Head())
+	require.NoError(t, l.Record(testChainID, block, nil))
+	require.NoError(t, l.Close())
+
+	verified, err = VerifyAuditLog(path)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), verified.Seq)
+}
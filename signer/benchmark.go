@@ -0,0 +1,375 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/privval"
+	cometproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	comet "github.com/cometbft/cometbft/types"
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	BenchmarkEncryptionECIES = "ecies"
+	BenchmarkEncryptionRSA   = "rsa"
+
+	benchmarkChainIDPrefix = "benchmark-"
+)
+
+// SignBenchmarkConfig configures a sign benchmark.
+type SignBenchmarkConfig struct {
+	// Threshold is the number of cosigners that sign each block.
+	Threshold int
+	// Shards is the number of cosigners of the cluster.
+	Shards int
+	// Encryption of the nonces between the cosigners, ecies or rsa.
+	Encryption string
+	// Signs is the number of blocks signed one after the other to measure the latency, and again
+	// concurrently to measure the sign rate.
+	Signs int
+	// Concurrency is the number of chains signed concurrently to measure the sign rate.
+	Concurrency int
+}
+
+func (c SignBenchmarkConfig) Validate() error {
+	if c.Threshold < 2 {
+		return fmt.Errorf("threshold must be at least 2")
+	}
+	if c.Shards > 255 {
+		return fmt.Errorf("shards must be at most 255")
+	}
+	if c.Shards < c.Threshold {
+		return fmt.Errorf("shards (%d) must be at least the threshold (%d)", c.Shards, c.Threshold)
+	}
+	if c.Encryption != BenchmarkEncryptionECIES && c.Encryption != BenchmarkEncryptionRSA {
+		return fmt.Errorf("encryption must be %s or %s", BenchmarkEncryptionECIES, BenchmarkEncryptionRSA)
+	}
+	if c.Signs < 1 {
+		return fmt.Errorf("signs must be positive")
+	}
+	if c.Concurrency < 1 {
+		return fmt.Errorf("concurrency must be positive")
+	}
+	return nil
+}
+
+// LatencyPercentiles are the percentiles of the latency of a stage of the sign pipeline.
+type LatencyPercentiles struct {
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+	Max time.Duration `json:"max"`
+}
+
+func newLatencyPercentiles(latencies []time.Duration) LatencyPercentiles {
+	sorted := slices.Clone(latencies)
+	slices.Sort(sorted)
+	percentile := func(p int) time.Duration {
+		return sorted[(len(sorted)-1)*p/100]
+	}
+	return LatencyPercentiles{
+		P50: percentile(50),
+		P90: percentile(90),
+		P99: percentile(99),
+		Max: sorted[len(sorted)-1],
+	}
+}
+
+// SignBenchmarkResult is the result of a sign benchmark.
+type SignBenchmarkResult struct {
+	Threshold   int    `json:"threshold"`
+	Shards      int    `json:"shards"`
+	Encryption  string `json:"encryption"`
+	Signs       int    `json:"signs"`
+	Concurrency int    `json:"concurrency"`
+
+	// Nonces is the latency of getting the nonces of the threshold cosigners.
+	Nonces LatencyPercentiles `json:"nonces"`
+	// Sign is the latency of setting the nonces and signing with the shard of the threshold cosigners.
+	Sign LatencyPercentiles `json:"sign"`
+	// Combine is the latency of combining the partial signatures and verifying the signature.
+	Combine LatencyPercentiles `json:"combine"`
+	// Total is the latency of the whole pipeline.
+	Total LatencyPercentiles `json:"total"`
+
+	// SignsPerSecond is the rate of signs with the chains signed concurrently.
+	SignsPerSecond float64 `json:"signs_per_second"`
+}
+
+// signBenchmark is a cluster of local cosigners that sign synthetic blocks, without the network.
+type signBenchmark struct {
+	threshold int
+	cosigners []*LocalCosigner
+}
+
+// RunSignBenchmark runs the threshold sign pipeline of a cluster of local cosigners, from the
+// nonce generation to the combined signature, against synthetic blocks. The cosigners sign with
+// a new key and keep their sign states in a temporary directory, which is removed afterwards.
+func RunSignBenchmark(
+	ctx context.Context,
+	logger cometlog.Logger,
+	cfg SignBenchmarkConfig,
+) (*SignBenchmarkResult, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "horcrux-benchmark")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	b, err := newSignBenchmark(logger, dir, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, c := range b.cosigners {
+			c.waitForSignStatesToFlushToDisk()
+		}
+	}()
+
+	res := &SignBenchmarkResult{
+		Threshold:   cfg.Threshold,
+		Shards:      cfg.Shards,
+		Encryption:  cfg.Encryption,
+		Signs:       cfg.Signs,
+		Concurrency: cfg.Concurrency,
+	}
+
+	var nonces, sign, combine, total []time.Duration
+	for height := int64(1); height <= int64(cfg.Signs); height++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		lat, err := b.sign(ctx, benchmarkChainIDPrefix+"latency", height)
+		if err != nil {
+			return nil, err
+		}
+		nonces = append(nonces, lat.nonces)
+		sign = append(sign, lat.sign)
+		combine = append(combine, lat.combine)
+		total = append(total, lat.nonces+lat.sign+lat.combine)
+	}
+	res.Nonces = newLatencyPercentiles(nonces)
+	res.Sign = newLatencyPercentiles(sign)
+	res.Combine = newLatencyPercentiles(combine)
+	res.Total = newLatencyPercentiles(total)
+
+	// each chain signs its share of the blocks one after the other, like a chain node.
+	var (
+		next int
+		mu   sync.Mutex
+	)
+	eg, egCtx := errgroup.WithContext(ctx)
+	start := time.Now()
+	for i := 0; i < cfg.Concurrency; i++ {
+		chainID := fmt.Sprintf("%srate-%d", benchmarkChainIDPrefix, i)
+		eg.Go(func() error {
+			for height := int64(1); ; height++ {
+				mu.Lock()
+				done := next >= cfg.Signs
+				next++
+				mu.Unlock()
+				if done {
+					return nil
+				}
+				if err := egCtx.Err(); err != nil {
+					return err
+				}
+				if _, err := b.sign(egCtx, chainID, height); err != nil {
+					return err
+				}
+			}
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	res.SignsPerSecond = float64(cfg.Signs) / time.Since(start).Seconds()
+
+	return res, nil
+}
+
+func newSignBenchmark(logger cometlog.Logger, dir string, cfg SignBenchmarkConfig) (*signBenchmark, error) {
+	security := make([]CosignerSecurity, cfg.Shards)
+	switch cfg.Encryption {
+	case BenchmarkEncryptionECIES:
+		keys, err := CreateCosignerECIESShards(cfg.Shards)
+		if err != nil {
+			return nil, err
+		}
+		for i, key := range keys {
+			security[i] = NewCosignerSecurityECIES(key)
+		}
+	case BenchmarkEncryptionRSA:
+		keys, err := CreateCosignerRSAShards(cfg.Shards)
+		if err != nil {
+			return nil, err
+		}
+		for i, key := range keys {
+			security[i] = NewCosignerSecurityRSA(key)
+		}
+	}
+
+	privKey := cometcryptoed25519.GenPrivKey()
+	pubKey := privKey.PubKey().(cometcryptoed25519.PubKey)
+	shards := CreateCosignerEd25519Shards(
+		privval.FilePVKey{PrivKey: privKey, PubKey: pubKey},
+		uint8(cfg.Threshold),
+		uint8(cfg.Shards),
+	)
+
+	cosignersConfig := make(CosignersConfig, cfg.Shards)
+	for i := range cosignersConfig {
+		cosignersConfig[i] = CosignerConfig{ShardID: i + 1}
+	}
+	config := Config{
+		ThresholdModeConfig: &ThresholdModeConfig{
+			Threshold: cfg.Threshold,
+			Cosigners: cosignersConfig,
+		},
+	}
+
+	chainIDs := []string{benchmarkChainIDPrefix + "latency"}
+	for i := 0; i < cfg.Concurrency; i++ {
+		chainIDs = append(chainIDs, fmt.Sprintf("%srate-%d", benchmarkChainIDPrefix, i))
+	}
+
+	b := &signBenchmark{
+		threshold: cfg.Threshold,
+	}
+	// only the threshold cosigners sign, the others would not be asked for nonces.
+	for i := 0; i < cfg.Threshold; i++ {
+		cosignerDir := filepath.Join(dir, fmt.Sprintf("cosigner%d", i+1))
+		if err := os.Mkdir(cosignerDir, 0700); err != nil {
+			return nil, err
+		}
+		cosigner := NewLocalCosigner(
+			logger,
+			&RuntimeConfig{
+				HomeDir:  cosignerDir,
+				StateDir: cosignerDir,
+				Config:   config,
+			},
+			security[i],
+			"",
+		)
+		keyBz, err := shards[i].MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		for _, chainID := range chainIDs {
+			if err := os.WriteFile(cosigner.config.KeyFilePathCosigner(chainID), keyBz, 0600); err != nil {
+				return nil, err
+			}
+			if err := cosigner.LoadSignStateIfNecessary(chainID); err != nil {
+				return nil, err
+			}
+		}
+		b.cosigners = append(b.cosigners, cosigner)
+	}
+	return b, nil
+}
+
+// signLatency is the latency of the stages of a sign.
+type signLatency struct {
+	nonces  time.Duration
+	sign    time.Duration
+	combine time.Duration
+}
+
+// sign signs a prevote of the chain at the height like the leader does: it gets nonces from the
+// threshold cosigners, has them sign with the nonces of each other and combines their signatures.
+func (b *signBenchmark) sign(ctx context.Context, chainID string, height int64) (signLatency, error) {
+	var lat signLatency
+
+	vote := cometproto.Vote{
+		Height:    height,
+		Type:      cometproto.PrevoteType,
+		Timestamp: time.Now(),
+	}
+	signBytes := comet.VoteSignBytes(chainID, &vote)
+
+	u := uuid.New()
+
+	start := time.Now()
+	nonces := make([][]CosignerNonce, b.threshold)
+	var eg errgroup.Group
+	for i, c := range b.cosigners {
+		i, c := i, c
+		eg.Go(func() error {
+			res, err := c.GetNonces(ctx, []uuid.UUID{u})
+			if err != nil {
+				return err
+			}
+			nonces[i] = res[0].Nonces
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return lat, fmt.Errorf("failed to get nonces: %w", err)
+	}
+	lat.nonces = time.Since(start)
+
+	start = time.Now()
+	sigs := make([]PartialSignature, b.threshold)
+	for i, c := range b.cosigners {
+		i, c := i, c
+		eg.Go(func() error {
+			var cosignerNonces []CosignerNonce
+			for j, n := range nonces {
+				if i == j {
+					continue
+				}
+				for _, nonce := range n {
+					if nonce.DestinationID == c.GetID() {
+						cosignerNonces = append(cosignerNonces, nonce)
+					}
+				}
+			}
+			res, err := c.SetNoncesAndSign(ctx, CosignerSetNoncesAndSignRequest{
+				Nonces: &CosignerUUIDNonces{
+					UUID:   u,
+					Nonces: cosignerNonces,
+				},
+				ChainID:   chainID,
+				SignBytes: signBytes,
+			})
+			if err != nil {
+				return err
+			}
+			sigs[i] = PartialSignature{
+				ID:        c.GetID(),
+				Signature: res.Signature,
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return lat, fmt.Errorf("failed to sign: %w", err)
+	}
+	lat.sign = time.Since(start)
+
+	start = time.Now()
+	sig, err := b.cosigners[0].CombineSignatures(chainID, sigs)
+	if err != nil {
+		return lat, fmt.Errorf("failed to combine signatures: %w", err)
+	}
+	if !b.cosigners[0].VerifySignature(chainID, signBytes, sig) {
+		return lat, fmt.Errorf("combined signature is not valid")
+	}
+	lat.combine = time.Since(start)
+
+	return lat, nil
+}
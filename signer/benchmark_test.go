@@ -0,0 +1,38 @@
+package signer
+
+import (
+	"context"
+	"testing"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSignBenchmark(t *testing.T) {
+	res, err := RunSignBenchmark(context.Background(), cometlog.NewNopLogger(), SignBenchmarkConfig{
+		Threshold:   2,
+		Shards:      3,
+		Encryption:  BenchmarkEncryptionECIES,
+		Signs:       10,
+		Concurrency: 3,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 10, res.Signs)
+	require.Positive(t, res.SignsPerSecond)
+	for _, lat := range []LatencyPercentiles{res.Nonces, res.Sign, res.Combine, res.Total} {
+		require.Positive(t, lat.P50)
+		require.LessOrEqual(t, lat.P50, lat.P90)
+		require.LessOrEqual(t, lat.P90, lat.P99)
+		require.LessOrEqual(t, lat.P99, lat.Max)
+	}
+	require.GreaterOrEqual(t, res.Total.Max, res.Sign.Max)
+
+	_, err = RunSignBenchmark(context.Background(), cometlog.NewNopLogger(), SignBenchmarkConfig{
+		Threshold:   3,
+		Shards:      2,
+		Encryption:  BenchmarkEncryptionECIES,
+		Signs:       10,
+		Concurrency: 1,
+	})
+	require.ErrorContains(t, err, "shards (2) must be at least the threshold (3)")
+}
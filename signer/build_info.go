@@ -0,0 +1,110 @@
+package signer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// KeyTypeEd25519 is the type of the validator key.
+const KeyTypeEd25519 = "ed25519"
+
+// BuildInfo identifies the build and the effective config of the signer, so that fleet tooling
+// can detect drift across cosigners.
+type BuildInfo struct {
+	Version   string   `json:"version"`
+	Commit    string   `json:"commit"`
+	GoVersion string   `json:"go_version"`
+	SignMode  SignMode `json:"sign_mode"`
+
+	// KeyTypes are the type of the validator key and, in threshold mode, the type of the keys
+	// that encrypt the communication between cosigners.
+	KeyTypes []string `json:"key_types"`
+
+	// Cosigners and Threshold are only set in threshold mode.
+	Cosigners int `json:"cosigners,omitempty"`
+	Threshold int `json:"threshold,omitempty"`
+
+	// ConfigHash is the sha256 hash of the effective config. It differs between cosigners
+	// that connect to different sentries.
+	ConfigHash string `json:"config_hash"`
+
+	// ThresholdConfigHash is the sha256 hash of the threshold mode config, which is the same on
+	// every cosigner of a cluster.
+	ThresholdConfigHash string `json:"threshold_config_hash,omitempty"`
+}
+
+// NewBuildInfo returns the BuildInfo of the signer with the config and the key types in use.
+func NewBuildInfo(cfg *Config, version, commit string, keyTypes []string) (BuildInfo, error) {
+	info := BuildInfo{
+		Version:   version,
+		Commit:    commit,
+		GoVersion: runtime.Version(),
+		SignMode:  cfg.SignMode,
+		KeyTypes:  keyTypes,
+	}
+
+	var err error
+	if info.ConfigHash, err = configHash(cfg); err != nil {
+		return info, err
+	}
+	if cfg.ThresholdModeConfig != nil {
+		info.Cosigners = len(cfg.ThresholdModeConfig.Cosigners)
+		info.Threshold = cfg.ThresholdModeConfig.Threshold
+		if info.ThresholdConfigHash, err = configHash(cfg.ThresholdModeConfig); err != nil {
+			return info, err
+		}
+	}
+	return info, nil
+}
+
+// configHash returns the hex encoded sha256 hash of the config as YAML.
+func configHash(cfg any) (string, error) {
+	bz, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(bz)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// SetMetric sets the build info metric, which is always 1 with the build info as labels.
+func (info BuildInfo) SetMetric() {
+	buildInfo.Reset()
+	buildInfo.WithLabelValues(
+		info.Version,
+		info.Commit,
+		info.GoVersion,
+		string(info.SignMode),
+		strings.Join(info.KeyTypes, ","),
+		strconv.Itoa(info.Cosigners),
+		strconv.Itoa(info.Threshold),
+		info.ConfigHash,
+		info.ThresholdConfigHash,
+	).Set(1)
+}
+
+// ServeHTTP serves the build info as JSON.
+func (info BuildInfo) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(info)
+}
+
+// CosignerSecurityKeyType returns the type of the keys that encrypt the communication with the
+// peer cosigners, ecies or rsa.
+func (pv *ThresholdValidator) CosignerSecurityKeyType() string {
+	switch pv.myCosigner.getSecurity().(type) {
+	case *CosignerSecurityECIES:
+		return "ecies"
+	case *CosignerSecurityRSA:
+		return "rsa"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,58 @@
+package signer
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildInfo(t *testing.T) {
+	cfg := &Config{
+		SignMode: SignModeThreshold,
+		ThresholdModeConfig: &ThresholdModeConfig{
+			Threshold: 2,
+			Cosigners: CosignersConfig{
+				{ShardID: 1, P2PAddr: "tcp://cosigner-1:2222"},
+				{ShardID: 2, P2PAddr: "tcp://cosigner-2:2222"},
+				{ShardID: 3, P2PAddr: "tcp://cosigner-3:2222"},
+			},
+		},
+		ChainNodes: ChainNodes{{PrivValAddr: "tcp://sentry-1:1234"}},
+	}
+
+	info, err := NewBuildInfo(cfg, "v3.3.0", "abcdef", []string{KeyTypeEd25519, "ecies"})
+	require.NoError(t, err)
+	require.Equal(t, 3, info.Cosigners)
+	require.Equal(t, 2, info.Threshold)
+	require.Len(t, info.ConfigHash, 64)
+
+	// another cosigner of the cluster connects to other sentries.
+	cfg.ChainNodes = ChainNodes{{PrivValAddr: "tcp://sentry-2:1234"}}
+	other, err := NewBuildInfo(cfg, "v3.3.0", "abcdef", []string{KeyTypeEd25519, "ecies"})
+	require.NoError(t, err)
+	require.NotEqual(t, info.ConfigHash, other.ConfigHash)
+	require.Equal(t, info.ThresholdConfigHash, other.ThresholdConfigHash)
+
+	cfg.ThresholdModeConfig.Threshold = 3
+	drifted, err := NewBuildInfo(cfg, "v3.3.0", "abcdef", []string{KeyTypeEd25519, "ecies"})
+	require.NoError(t, err)
+	require.NotEqual(t, info.ThresholdConfigHash, drifted.ThresholdConfigHash)
+
+	info.SetMetric()
+	require.Equal(t, 1, testutil.CollectAndCount(buildInfo))
+	require.Equal(t, float64(1), testutil.ToFloat64(buildInfo.WithLabelValues(
+		"v3.3.0", "abcdef", info.GoVersion, "threshold", "ed25519,ecies", "3", "2",
+		info.ConfigHash, info.ThresholdConfigHash,
+	)))
+
+	rec := httptest.NewRecorder()
+	info.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/build_info", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var served BuildInfo
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &served))
+	require.Equal(t, info, served)
+}
@@ -0,0 +1,121 @@
+package signer
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// certReloadInterval bounds how often the certificate files are checked for changes, since a
+// certificate is checked on the TLS handshakes.
+const certReloadInterval = time.Second
+
+// certFilesStamp identifies the contents of the certificate and key files by their size and
+// modification time, which change when the files are written or the symlinks of a Kubernetes secret
+// volume are swapped.
+type certFilesStamp struct {
+	certSize, keySize       int64
+	certModTime, keyModTime time.Time
+}
+
+// certReloader serves a TLS certificate and key pair, and loads them again once the files change,
+// so that short-lived certificates, e.g. issued by cert-manager or Vault, are rotated without
+// restarting the listeners.
+type certReloader struct {
+	certFile, keyFile string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	stamp   certFilesStamp
+	checked time.Time
+}
+
+// newCertReloader returns a certReloader of the certificate and key files, which must be valid.
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		checked:  time.Now(),
+	}
+	stamp, err := r.filesStamp()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(stamp); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// newReloadingTLSConfig returns a TLS server config that serves the certificate and key files, and
+// loads them again once they change.
+func newReloadingTLSConfig(certFile, keyFile string) (*tls.Config, error) {
+	r, err := newCertReloader(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		GetCertificate: r.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}, nil
+}
+
+// GetCertificate returns the certificate, after loading it again if the files changed since the
+// last check. A certificate that fails to load, e.g. while the key is not written yet, is counted
+// and the previous certificate is served until the files change again.
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if time.Since(r.checked) < certReloadInterval {
+		return r.cert, nil
+	}
+	r.checked = time.Now()
+
+	stamp, err := r.filesStamp()
+	if err != nil || stamp == r.stamp {
+		return r.cert, nil
+	}
+	if err := r.load(stamp); err != nil {
+		totalCertificateReloads.WithLabelValues(r.certFile, "failed").Inc()
+		r.stamp = stamp
+		return r.cert, nil
+	}
+	totalCertificateReloads.WithLabelValues(r.certFile, "reloaded").Inc()
+	return r.cert, nil
+}
+
+// load loads the certificate and key files of the stamp.
+func (r *certReloader) load(stamp certFilesStamp) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load certificate %s: %w", r.certFile, err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("failed to parse certificate %s: %w", r.certFile, err)
+	}
+	cert.Leaf = leaf
+	r.cert, r.stamp = &cert, stamp
+	certificateNotAfter.WithLabelValues(r.certFile).Set(float64(leaf.NotAfter.Unix()))
+	return nil
+}
+
+func (r *certReloader) filesStamp() (certFilesStamp, error) {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return certFilesStamp{}, fmt.Errorf("failed to read certificate %s: %w", r.certFile, err)
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return certFilesStamp{}, fmt.Errorf("failed to read key %s: %w", r.keyFile, err)
+	}
+	return certFilesStamp{
+		certSize:    certInfo.Size(),
+		keySize:     keyInfo.Size(),
+		certModTime: certInfo.ModTime(),
+		keyModTime:  keyInfo.ModTime(),
+	}, nil
+}
@@ -0,0 +1,73 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCert writes a self-signed certificate with the common name and its key to the files.
+func writeTestCert(t *testing.T, certFile, keyFile, commonName string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{commonName},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	_, err := newCertReloader(certFile, keyFile)
+	require.Error(t, err)
+
+	writeTestCert(t, certFile, keyFile, "horcrux-1")
+	r, err := newCertReloader(certFile, keyFile)
+	require.NoError(t, err)
+
+	commonName := func() string {
+		cert, err := r.GetCertificate(&tls.ClientHelloInfo{})
+		require.NoError(t, err)
+		return cert.Leaf.Subject.CommonName
+	}
+	require.Equal(t, "horcrux-1", commonName())
+
+	// the files are checked at most once per interval.
+	writeTestCert(t, certFile, keyFile, "horcrux-2")
+	require.Equal(t, "horcrux-1", commonName())
+
+	r.checked = time.Time{}
+	require.Equal(t, "horcrux-2", commonName())
+
+	// a certificate that fails to load keeps the previous one.
+	require.NoError(t, os.WriteFile(keyFile, []byte("not a key"), 0600))
+	r.checked = time.Time{}
+	require.Equal(t, "horcrux-2", commonName())
+
+	writeTestCert(t, certFile, keyFile, "horcrux-3")
+	r.checked = time.Time{}
+	require.Equal(t, "horcrux-3", commonName())
+}
@@ -0,0 +1,146 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// chainNotAllowedUnknownLabel is the chain_id label of the refusals of the chains that are not in
+// the config, which bounds the labels of the metric when a node requests arbitrary chain IDs.
+const chainNotAllowedUnknownLabel = "unknown"
+
+type ChainNotAllowedError struct {
+	msg string
+}
+
+func (e *ChainNotAllowedError) Error() string { return e.msg }
+
+func newChainNotAllowedError(chainID string) *ChainNotAllowedError {
+	return &ChainNotAllowedError{
+		msg: fmt.Sprintf(
+			"refusing request for chain %s: chain is not in allowedChainIDs or the chains config, "+
+				"check that the chain node is on the intended network",
+			chainID,
+		),
+	}
+}
+
+// ChainIDAllowlist are the chain IDs, or validator chain IDs, that the signer serves. The requests
+// for other chains are refused, e.g. those of a chain node pointed at the wrong network.
+type ChainIDAllowlist []string
+
+func (l ChainIDAllowlist) Validate(chains ChainConfigs) error {
+	for _, id := range l {
+		if _, chainID := SplitValidatorChainID(id); chainID == "" {
+			return fmt.Errorf("invalid allowed chain ID %q", id)
+		}
+	}
+	if len(l) == 0 {
+		return nil
+	}
+	for id := range chains {
+		if !l.Allows(id) {
+			return fmt.Errorf("chain %s of the chains config is not in allowedChainIDs", id)
+		}
+	}
+	return nil
+}
+
+// ChainIDAllowlist returns the chain IDs the signer serves: allowedChainIDs, or the chain IDs of the
+// chains config if allowedChainIDs is not set. If neither is set, every chain is served.
+func (c *Config) ChainIDAllowlist() ChainIDAllowlist {
+	if len(c.AllowedChainIDs) > 0 {
+		return c.AllowedChainIDs
+	}
+	allowlist := make(ChainIDAllowlist, 0, len(c.Chains))
+	for id := range c.Chains {
+		allowlist = append(allowlist, id)
+	}
+	sort.Strings(allowlist)
+	return allowlist
+}
+
+// Allows returns true if the chain ID, or validator chain ID, is served. An allowed chain ID allows
+// every validator of the chain, an allowed validator chain ID only the validator. An empty allowlist
+// allows every chain.
+func (l ChainIDAllowlist) Allows(id string) bool {
+	if len(l) == 0 || slices.Contains(l, id) {
+		return true
+	}
+	_, chainID := SplitValidatorChainID(id)
+	return slices.Contains(l, chainID)
+}
+
+// knows returns true if the chain of the chain ID, or validator chain ID, is in the allowlist, e.g. of
+// a validator of an allowed chain that is not allowed itself.
+func (l ChainIDAllowlist) knows(id string) bool {
+	_, chainID := SplitValidatorChainID(id)
+	for _, allowed := range l {
+		if _, allowedChainID := SplitValidatorChainID(allowed); allowedChainID == chainID {
+			return true
+		}
+	}
+	return false
+}
+
+// ChainAllowlistValidator is a PrivValidator that refuses the sign and public key requests of the
+// chains that are not allowed.
+type ChainAllowlistValidator struct {
+	val       PrivValidator
+	allowlist atomic.Pointer[ChainIDAllowlist]
+}
+
+// NewChainAllowlistValidator returns a ChainAllowlistValidator that passes the requests of the
+// allowed chains to val.
+func NewChainAllowlistValidator(val PrivValidator, allowlist ChainIDAllowlist) *ChainAllowlistValidator {
+	v := &ChainAllowlistValidator{val: val}
+	v.SetAllowlist(allowlist)
+	return v
+}
+
+// Allowlist returns the allowlist of the chains that are served.
+func (v *ChainAllowlistValidator) Allowlist() ChainIDAllowlist {
+	return *v.allowlist.Load()
+}
+
+// SetAllowlist sets the allowlist of the chains that are served, e.g. on a config reload, from the
+// next request.
+func (v *ChainAllowlistValidator) SetAllowlist(allowlist ChainIDAllowlist) {
+	v.allowlist.Store(&allowlist)
+}
+
+// refused counts the refusal of the request for the chain ID.
+func (v *ChainAllowlistValidator) refused(chainID, request string) {
+	label := chainID
+	if !v.Allowlist().knows(chainID) {
+		label = chainNotAllowedUnknownLabel
+	}
+	totalChainNotAllowedRefusals.WithLabelValues(label, request).Inc()
+}
+
+// Sign implements PrivValidator.
+func (v *ChainAllowlistValidator) Sign(ctx context.Context, chainID string, block Block) ([]byte, time.Time, error) {
+	if !v.Allowlist().Allows(chainID) {
+		v.refused(chainID, "sign")
+		return nil, block.Timestamp, newChainNotAllowedError(chainID)
+	}
+	return v.val.Sign(ctx, chainID, block)
+}
+
+// GetPubKey implements PrivValidator.
+func (v *ChainAllowlistValidator) GetPubKey(ctx context.Context, chainID string) ([]byte, error) {
+	if !v.Allowlist().Allows(chainID) {
+		v.refused(chainID, "pubkey")
+		return nil, newChainNotAllowedError(chainID)
+	}
+	return v.val.GetPubKey(ctx, chainID)
+}
+
+// Stop implements PrivValidator.
+func (v *ChainAllowlistValidator) Stop() {
+	v.val.Stop()
+}
@@ -0,0 +1,66 @@
+package signer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainIDAllowlistValidate(t *testing.T) {
+	allowlist := ChainIDAllowlist{"osmosis-1", "acme@cosmoshub-4"}
+	require.NoError(t, allowlist.Validate(ChainConfigs{
+		"osmosis-1":        {HaltHeight: 100},
+		"acme@osmosis-1":   {HaltHeight: 100},
+		"acme@cosmoshub-4": {HaltHeight: 100},
+	}))
+	require.Error(t, allowlist.Validate(ChainConfigs{"cosmoshub-4": {HaltHeight: 100}}))
+	require.Error(t, ChainIDAllowlist{""}.Validate(nil))
+	require.NoError(t, ChainIDAllowlist(nil).Validate(ChainConfigs{"cosmoshub-4": {HaltHeight: 100}}))
+}
+
+func TestChainAllowlistValidator(t *testing.T) {
+	pv := &mockPrivValidator{}
+	v := NewChainAllowlistValidator(pv, ChainIDAllowlist{"osmosis-1", "acme@cosmoshub-4"})
+
+	ctx := context.Background()
+	_, _, err := v.Sign(ctx, "osmosis-1", Block{Height: 1})
+	require.NoError(t, err)
+
+	// an allowed chain ID allows every validator of the chain, an allowed validator chain ID only the
+	// validator.
+	_, _, err = v.Sign(ctx, "acme@osmosis-1", Block{Height: 1})
+	require.NoError(t, err)
+	_, _, err = v.Sign(ctx, "acme@cosmoshub-4", Block{Height: 1})
+	require.NoError(t, err)
+	// a validator of an allowed chain that is not allowed itself is counted by its chain ID.
+	refusals := totalChainNotAllowedRefusals.WithLabelValues("cosmoshub-4", "sign")
+	before := testutil.ToFloat64(refusals)
+	_, _, err = v.Sign(ctx, "cosmoshub-4", Block{Height: 1})
+	require.IsType(t, &ChainNotAllowedError{}, err)
+	require.Equal(t, before+1, testutil.ToFloat64(refusals))
+
+	// the refusals of chains that are not in the config are counted as unknown.
+	refusals = totalChainNotAllowedRefusals.WithLabelValues("unknown", "pubkey")
+	before = testutil.ToFloat64(refusals)
+	_, err = v.GetPubKey(ctx, "osmo-testnet-5")
+	require.IsType(t, &ChainNotAllowedError{}, err)
+	require.Equal(t, before+1, testutil.ToFloat64(refusals))
+	_, err = v.GetPubKey(ctx, "osmosis-1")
+	require.NoError(t, err)
+	require.Equal(t, 3, pv.signed)
+}
+
+func TestConfigChainIDAllowlist(t *testing.T) {
+	// the chains of the chains config are allowed by default.
+	c := Config{Chains: ChainConfigs{"osmosis-1": {HaltHeight: 100}, "acme@cosmoshub-4": {HaltHeight: 100}}}
+	require.Equal(t, ChainIDAllowlist{"acme@cosmoshub-4", "osmosis-1"}, c.ChainIDAllowlist())
+	require.False(t, c.ChainIDAllowlist().Allows("osmo-testnet-5"))
+
+	c.AllowedChainIDs = ChainIDAllowlist{"osmosis-1", "acme@cosmoshub-4", "osmo-testnet-5"}
+	require.Equal(t, c.AllowedChainIDs, c.ChainIDAllowlist())
+
+	// without either, every chain is allowed.
+	require.True(t, (&Config{}).ChainIDAllowlist().Allows("osmo-testnet-5"))
+}
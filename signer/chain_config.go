@@ -0,0 +1,363 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+)
+
+// ChainConfig overrides the settings of the signer for a chain, since one set of tunables does not
+// fit both chains with short and with long block times.
+type ChainConfig struct {
+	// GRPCTimeout overrides thresholdMode.grpcTimeout for the requests to the peer cosigners while
+	// signing the chain.
+	GRPCTimeout string `yaml:"grpcTimeout,omitempty"`
+
+	// NonceExpiration is the age above which cached nonces are not used to sign the chain. It is at
+	// most the expiration of the nonce cache, which applies to the chains without it.
+	NonceExpiration string `yaml:"nonceExpiration,omitempty"`
+
+	// MaxTimestampSkew is the difference between the timestamp of a vote or proposal of the chain and
+	// the clock of the signer above which the sign request is refused. Without it, the timestamps
+	// are not checked.
+	MaxTimestampSkew string `yaml:"maxTimestampSkew,omitempty"`
+
+	// TimestampRegressionCheck refuses the votes and proposals of the chain timestamped before the
+	// last signature, by more than TimestampRegressionTolerance. Without it, the timestamps are not
+	// compared with the last signature.
+	TimestampRegressionCheck bool `yaml:"timestampRegressionCheck,omitempty"`
+
+	// TimestampRegressionTolerance is how far a timestamp may go back before the last signature, e.g.
+	// for the clock skew between the sentries of the chain.
+	TimestampRegressionTolerance string `yaml:"timestampRegressionTolerance,omitempty"`
+
+	// ChainNodes are connected to in addition to the chain nodes of the config, and only sign the
+	// chain.
+	ChainNodes ChainNodes `yaml:"chainNodes,omitempty"`
+
+	// Bech32Prefix is the base bech32 prefix of the addresses of the chain, e.g. cosmos, with which
+	// horcrux address prints the consensus address and public key of the validator.
+	Bech32Prefix string `yaml:"bech32Prefix,omitempty"`
+
+	// HaltHeight is the last height of the chain that is signed, e.g. the height at which the chain
+	// halts for a coordinated upgrade, so that the signer cannot sign on a fork of the chain. The
+	// halt is cleared through the admin API once the chain is upgraded.
+	HaltHeight int64 `yaml:"haltHeight,omitempty"`
+
+	// Policy are the rules the sign requests of the chain must pass before they are signed.
+	Policy *SignPolicyConfig `yaml:"policy,omitempty"`
+}
+
+// bech32PrefixPattern matches a base bech32 prefix.
+var bech32PrefixPattern = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// ChainConfigs holds the ChainConfig of each chain ID, or validator chain ID.
+type ChainConfigs map[string]ChainConfig
+
+func (cfgs ChainConfigs) Validate() error {
+	for id, cfg := range cfgs {
+		if err := cfg.validate(id); err != nil {
+			return fmt.Errorf("invalid config of chain %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+func (cfg ChainConfig) validate(id string) error {
+	if _, err := parseChainDuration("grpcTimeout", cfg.GRPCTimeout); err != nil {
+		return err
+	}
+	expiration, err := parseChainDuration("nonceExpiration", cfg.NonceExpiration)
+	if err != nil {
+		return err
+	}
+	if expiration >= nonceExpiration {
+		return fmt.Errorf("nonceExpiration must be below the expiration of the nonces on the cosigners %s",
+			nonceExpiration)
+	}
+	if _, err := parseChainDuration("maxTimestampSkew", cfg.MaxTimestampSkew); err != nil {
+		return err
+	}
+	if _, err := parseChainDuration("timestampRegressionTolerance", cfg.TimestampRegressionTolerance); err != nil {
+		return err
+	}
+	if cfg.TimestampRegressionTolerance != "" && !cfg.TimestampRegressionCheck {
+		return fmt.Errorf("timestampRegressionTolerance requires timestampRegressionCheck")
+	}
+
+	if cfg.HaltHeight < 0 {
+		return fmt.Errorf("haltHeight must not be negative")
+	}
+
+	if err := cfg.Policy.validate(); err != nil {
+		return err
+	}
+
+	if cfg.Bech32Prefix != "" && !bech32PrefixPattern.MatchString(cfg.Bech32Prefix) {
+		return fmt.Errorf("invalid bech32Prefix %q, must be lowercase letters and digits", cfg.Bech32Prefix)
+	}
+
+	validator, _ := SplitValidatorChainID(id)
+	for _, n := range cfg.ChainNodes {
+		if n.Validator != "" && n.Validator != validator {
+			return fmt.Errorf("chain node %s signs with validator %q, not with the validator of the chain",
+				n.PrivValAddr, n.Validator)
+		}
+	}
+	return cfg.ChainNodes.Validate()
+}
+
+// overrides returns true if the config overrides a setting of the signer other than the chain nodes.
+func (cfg ChainConfig) overrides() bool {
+	return cfg.GRPCTimeout != "" || cfg.NonceExpiration != "" || cfg.MaxTimestampSkew != "" || cfg.HaltHeight != 0 ||
+		cfg.TimestampRegressionCheck || cfg.Policy != nil
+}
+
+// parseChainDuration parses the optional duration of the setting, which must be positive.
+func parseChainDuration(setting, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", setting, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%s must be positive", setting)
+	}
+	return d, nil
+}
+
+// grpcTimeout returns the gRPC timeout of the chain, or 0 if it is not overridden.
+func (cfgs ChainConfigs) grpcTimeout(chainID string) time.Duration {
+	cfg, _ := validatorChainValue(cfgs, chainID)
+	// validated with the config.
+	d, _ := parseChainDuration("grpcTimeout", cfg.GRPCTimeout)
+	return d
+}
+
+// nonceExpiration returns the age above which cached nonces are not used to sign the chain, or 0 if
+// it is not overridden.
+func (cfgs ChainConfigs) nonceExpiration(chainID string) time.Duration {
+	cfg, _ := validatorChainValue(cfgs, chainID)
+	d, _ := parseChainDuration("nonceExpiration", cfg.NonceExpiration)
+	return d
+}
+
+// maxTimestampSkew returns the max timestamp skew of the chain, or 0 if the timestamps of the chain
+// are not checked.
+func (cfgs ChainConfigs) maxTimestampSkew(chainID string) time.Duration {
+	cfg, _ := validatorChainValue(cfgs, chainID)
+	d, _ := parseChainDuration("maxTimestampSkew", cfg.MaxTimestampSkew)
+	return d
+}
+
+// timestampRegressionTolerance returns how far the timestamps of the chain may go back before the
+// last signature, or false if they are not compared with the last signature.
+func (cfgs ChainConfigs) timestampRegressionTolerance(chainID string) (time.Duration, bool) {
+	cfg, _ := validatorChainValue(cfgs, chainID)
+	d, _ := parseChainDuration("timestampRegressionTolerance", cfg.TimestampRegressionTolerance)
+	return d, cfg.TimestampRegressionCheck
+}
+
+// ChecksTimestamps returns true if any chain checks the timestamps of its sign requests.
+func (cfgs ChainConfigs) ChecksTimestamps() bool {
+	for _, cfg := range cfgs {
+		if cfg.MaxTimestampSkew != "" || cfg.TimestampRegressionCheck {
+			return true
+		}
+	}
+	return false
+}
+
+// Bech32Prefix returns the base bech32 prefix of the addresses of the chain, or empty if it is not
+// configured.
+func (cfgs ChainConfigs) Bech32Prefix(chainID string) string {
+	cfg, _ := validatorChainValue(cfgs, chainID)
+	return cfg.Bech32Prefix
+}
+
+// haltHeights returns the halt height of each chain ID, or validator chain ID, with one.
+func (cfgs ChainConfigs) haltHeights() map[string]int64 {
+	heights := make(map[string]int64)
+	for id, cfg := range cfgs {
+		if cfg.HaltHeight > 0 {
+			heights[id] = cfg.HaltHeight
+		}
+	}
+	return heights
+}
+
+// AllChainNodes returns the chain nodes of the config, followed by the chain nodes of the chains,
+// which only sign their chain with the validator of the chain.
+func (c *Config) AllChainNodes() ChainNodes {
+	nodes := slices.Clone(c.ChainNodes)
+	ids := make([]string, 0, len(c.Chains))
+	for id := range c.Chains {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		validator, chainID := SplitValidatorChainID(id)
+		for _, n := range c.Chains[id].ChainNodes {
+			n.Validator = validator
+			n.ChainID = chainID
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// withChainNodesOf returns the chain configs with the chains, the chain nodes and the bech32 prefixes
+// of next, e.g. once the chain nodes of next are connected to on a config reload. The bech32 prefixes
+// are not used by the signer, so they apply right away. The chains added by next are added without
+// their other settings, which only apply after a restart, and the chains removed by next are omitted
+// unless they keep settings that still apply.
+func (cfgs ChainConfigs) withChainNodesOf(next ChainConfigs) ChainConfigs {
+	out := make(ChainConfigs, len(cfgs))
+	for id, cfg := range cfgs {
+		cfg.ChainNodes = next[id].ChainNodes
+		cfg.Bech32Prefix = next[id].Bech32Prefix
+		out[id] = cfg
+	}
+	for id, cfg := range next {
+		if _, ok := out[id]; !ok {
+			out[id] = ChainConfig{ChainNodes: cfg.ChainNodes, Bech32Prefix: cfg.Bech32Prefix}
+		}
+	}
+	for id, cfg := range out {
+		if _, listed := next[id]; !listed && !cfg.overrides() {
+			delete(out, id)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+type TimestampSkewError struct {
+	msg string
+}
+
+func (e *TimestampSkewError) Error() string { return e.msg }
+
+func newTimestampSkewError(chainID string, height int64, skew, maxSkew time.Duration) *TimestampSkewError {
+	return &TimestampSkewError{
+		msg: fmt.Sprintf(
+			"refusing to sign chain %s at height %d: timestamp is %s off the signer clock (max skew %s), "+
+				"the clock of the chain node or of the signer may be wrong",
+			chainID, height, skew.Round(time.Millisecond), maxSkew,
+		),
+	}
+}
+
+func newTimestampRegressionError(chainID string, block Block, last time.Time) *TimestampSkewError {
+	return &TimestampSkewError{
+		msg: fmt.Sprintf(
+			"refusing to sign chain %s at height %d, round %d, step %d: timestamp %s is %s before the last "+
+				"signed timestamp %s, the chain node may be compromised or its clock went back",
+			chainID, block.Height, block.Round, block.Step, block.Timestamp.UTC().Format(time.RFC3339Nano),
+			last.Sub(block.Timestamp).Round(time.Millisecond), last.UTC().Format(time.RFC3339Nano),
+		),
+	}
+}
+
+// SignedTimestamps returns the HRS and the timestamp of the last signature of a chain, from its
+// persisted sign state.
+type SignedTimestamps interface {
+	// LastSignedTimestamp returns the HRS and the timestamp of the sign bytes of the last signature of
+	// the chain, or false if none was signed with sign bytes.
+	LastSignedTimestamp(chainID string) (HRSTKey, bool, error)
+}
+
+// lastSignedTimestamp returns the HRS and the timestamp of the greatest of the sign states with
+// sign bytes, or false if none has sign bytes.
+func lastSignedTimestamp(codec SignBytesCodec, signStates ...SignStateConsensus) (HRSTKey, bool, error) {
+	var last *SignStateConsensus
+	for i, ssc := range signStates {
+		if len(ssc.SignBytes) > 0 && (last == nil || ssc.HRSKey().GreaterThan(last.HRSKey())) {
+			last = &signStates[i]
+		}
+	}
+	if last == nil {
+		return HRSTKey{}, false, nil
+	}
+	hrst, err := codec.UnpackHRST(last.SignBytes)
+	if err != nil {
+		return HRSTKey{}, false, fmt.Errorf("failed to unpack the last signed timestamp: %w", err)
+	}
+	return hrst, true, nil
+}
+
+// TimestampSkewGuard is a PrivValidator that refuses to sign votes and proposals whose timestamp is
+// too far off the clock of the signer, for the chains with a max timestamp skew, or before the
+// timestamp of the last signature at a lower height, round or step by more than the tolerance, for
+// the chains that check timestamp regressions. The last signature is read from the persisted sign
+// state, so the timestamps do not regress across restarts either.
+type TimestampSkewGuard struct {
+	val    PrivValidator
+	chains ChainConfigs
+	signed SignedTimestamps
+}
+
+// NewTimestampSkewGuard returns a TimestampSkewGuard that checks the sign requests of the chains
+// against the last signatures of signed before passing them to val.
+func NewTimestampSkewGuard(val PrivValidator, chains ChainConfigs, signed SignedTimestamps) *TimestampSkewGuard {
+	return &TimestampSkewGuard{
+		val:    val,
+		chains: chains,
+		signed: signed,
+	}
+}
+
+// comparableSteps returns true if the timestamps of the steps are comparable. The votes are
+// timestamped with the clock of the chain node, while a proposal is timestamped with the block time,
+// which is before the votes of the previous height.
+func comparableSteps(a, b int8) bool {
+	return (a == stepPropose) == (b == stepPropose)
+}
+
+// Sign implements PrivValidator.
+func (g *TimestampSkewGuard) Sign(ctx context.Context, chainID string, block Block) ([]byte, time.Time, error) {
+	if block.Timestamp.IsZero() {
+		return g.val.Sign(ctx, chainID, block)
+	}
+	if maxSkew := g.chains.maxTimestampSkew(chainID); maxSkew > 0 {
+		if skew := time.Until(block.Timestamp); skew.Abs() > maxSkew {
+			totalTimestampSkewRefusals.WithLabelValues(chainID).Inc()
+			return nil, block.Timestamp, newTimestampSkewError(chainID, block.Height, skew, maxSkew)
+		}
+	}
+
+	tolerance, checksRegression := g.chains.timestampRegressionTolerance(chainID)
+	if !checksRegression {
+		return g.val.Sign(ctx, chainID, block)
+	}
+	last, ok, err := g.signed.LastSignedTimestamp(chainID)
+	if err != nil {
+		return nil, block.Timestamp, err
+	}
+	// a request at or below the last signed HRS is left to the sign state, which returns the
+	// signature of the same block or refuses it.
+	lastTimestamp := time.Unix(0, last.Timestamp)
+	if ok && comparableSteps(block.Step, last.Step) && block.HRSKey().GreaterThan(last.HRSKey()) &&
+		block.Timestamp.Before(lastTimestamp.Add(-tolerance)) {
+		totalTimestampRegressionRefusals.WithLabelValues(chainID).Inc()
+		return nil, block.Timestamp, newTimestampRegressionError(chainID, block, lastTimestamp)
+	}
+
+	return g.val.Sign(ctx, chainID, block)
+}
+
+// GetPubKey implements PrivValidator.
+func (g *TimestampSkewGuard) GetPubKey(ctx context.Context, chainID string) ([]byte, error) {
+	return g.val.GetPubKey(ctx, chainID)
+}
+
+// Stop implements PrivValidator.
+func (g *TimestampSkewGuard) Stop() {
+	g.val.Stop()
+}
@@ -0,0 +1,225 @@
+package signer
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	cometprotoprivval "github.com/cometbft/cometbft/proto/tendermint/privval"
+	cometproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	comet "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainConfigsValidate(t *testing.T) {
+	require.NoError(t, ChainConfigs(nil).Validate())
+	require.NoError(t, ChainConfigs{
+		"osmosis-1": {GRPCTimeout: "300ms", NonceExpiration: "5s", MaxTimestampSkew: "1s", Bech32Prefix: "osmo"},
+		"acme@cosmoshub-4": {ChainNodes: ChainNodes{
+			{PrivValAddr: "tcp://acme-sentry-1:1234"},
+			{PrivValAddr: "tcp://acme-sentry-2:1234", Validator: "acme"},
+		}},
+	}.Validate())
+
+	for _, cfg := range []ChainConfig{
+		{GRPCTimeout: "fast"},
+		{GRPCTimeout: "-1s"},
+		{NonceExpiration: "20s"},
+		{MaxTimestampSkew: "0s"},
+		{TimestampRegressionCheck: true, TimestampRegressionTolerance: "-1s"},
+		{TimestampRegressionTolerance: "1s"},
+		{ChainNodes: ChainNodes{{PrivValAddr: "tcp://sentry-1:1234", Validator: "other"}}},
+		{Bech32Prefix: "Cosmos"},
+		{HaltHeight: -1},
+	} {
+		require.Error(t, ChainConfigs{"cosmoshub-4": cfg}.Validate(), cfg)
+	}
+}
+
+func TestChainConfigsOverrides(t *testing.T) {
+	cfgs := ChainConfigs{
+		"osmosis-1":        {GRPCTimeout: "300ms", NonceExpiration: "5s", MaxTimestampSkew: "1s"},
+		"acme@osmosis-1":   {GRPCTimeout: "200ms"},
+		"acme@cosmoshub-4": {ChainNodes: ChainNodes{{PrivValAddr: "tcp://acme-sentry-1:1234"}}},
+		"cosmoshub-4":      {Bech32Prefix: "cosmos"},
+	}
+	require.Equal(t, "cosmos", cfgs.Bech32Prefix("cosmoshub-4"))
+	require.Equal(t, "cosmos", cfgs.Bech32Prefix("other@cosmoshub-4"))
+	require.Empty(t, cfgs.Bech32Prefix("acme@cosmoshub-4"), "a validator chain config overrides the chain config")
+	require.Equal(t, 300*time.Millisecond, cfgs.grpcTimeout("osmosis-1"))
+	require.Equal(t, 200*time.Millisecond, cfgs.grpcTimeout("acme@osmosis-1"))
+	require.Equal(t, 300*time.Millisecond, cfgs.grpcTimeout("other@osmosis-1"))
+	require.Zero(t, cfgs.grpcTimeout("cosmoshub-4"))
+	require.Equal(t, 5*time.Second, cfgs.nonceExpiration("osmosis-1"))
+	require.Zero(t, cfgs.nonceExpiration("acme@osmosis-1"), "a validator chain config overrides the chain config")
+	require.Equal(t, time.Second, cfgs.maxTimestampSkew("osmosis-1"))
+	require.True(t, cfgs.ChecksTimestamps())
+	_, ok := cfgs.timestampRegressionTolerance("osmosis-1")
+	require.False(t, ok)
+
+	c := Config{
+		ChainNodes: ChainNodes{{PrivValAddr: "tcp://sentry-1:1234"}},
+		Chains:     cfgs,
+	}
+	require.Equal(t, ChainNodes{
+		{PrivValAddr: "tcp://sentry-1:1234"},
+		{PrivValAddr: "tcp://acme-sentry-1:1234", Validator: "acme", ChainID: "cosmoshub-4"},
+	}, c.AllChainNodes())
+	require.Equal(t, []string{"tcp://sentry-1:1234", "tcp://acme-sentry-1:1234"}, c.Nodes())
+
+	// the chains, chain nodes and bech32 prefixes of the chains are replaced, and the chains removed
+	// without other settings omitted.
+	next := ChainConfigs{
+		"osmosis-1": {GRPCTimeout: "1s", ChainNodes: ChainNodes{{PrivValAddr: "tcp://osmosis-sentry-1:1234"}}},
+		"juno-1":    {Bech32Prefix: "juno"},
+		"stride-1":  {HaltHeight: 100},
+	}
+	require.Equal(t, ChainConfigs{
+		"osmosis-1": {
+			GRPCTimeout:      "300ms",
+			NonceExpiration:  "5s",
+			MaxTimestampSkew: "1s",
+			ChainNodes:       ChainNodes{{PrivValAddr: "tcp://osmosis-sentry-1:1234"}},
+		},
+		"acme@osmosis-1": {GRPCTimeout: "200ms"},
+		"juno-1":         {Bech32Prefix: "juno"},
+		"stride-1":       {},
+	}, cfgs.withChainNodesOf(next))
+}
+
+// signedTimestamps is a PrivValidator whose sign state is the last block it signed of each chain.
+type signedTimestamps struct {
+	mockPrivValidator
+	last map[string]HRSTKey
+}
+
+func newSignedTimestamps() *signedTimestamps {
+	return &signedTimestamps{last: make(map[string]HRSTKey)}
+}
+
+func (pv *signedTimestamps) Sign(ctx context.Context, chainID string, block Block) ([]byte, time.Time, error) {
+	if last, ok := pv.last[chainID]; !ok || block.HRSKey().GreaterThan(last.HRSKey()) {
+		pv.last[chainID] = block.HRSTKey()
+	}
+	return pv.mockPrivValidator.Sign(ctx, chainID, block)
+}
+
+func (pv *signedTimestamps) LastSignedTimestamp(chainID string) (HRSTKey, bool, error) {
+	last, ok := pv.last[chainID]
+	return last, ok, nil
+}
+
+func TestTimestampSkewGuard(t *testing.T) {
+	pv := newSignedTimestamps()
+	g := NewTimestampSkewGuard(pv, ChainConfigs{"osmosis-1": {MaxTimestampSkew: "1s"}}, pv)
+
+	_, _, err := g.Sign(context.Background(), "osmosis-1", Block{Height: 1, Timestamp: time.Now()})
+	require.NoError(t, err)
+	_, _, err = g.Sign(context.Background(), "osmosis-1", Block{Height: 2, Timestamp: time.Now().Add(-2 * time.Second)})
+	require.IsType(t, &TimestampSkewError{}, err)
+	_, _, err = g.Sign(context.Background(), "osmosis-1", Block{Height: 3, Timestamp: time.Now().Add(2 * time.Second)})
+	require.IsType(t, &TimestampSkewError{}, err)
+
+	// requests without a timestamp, and other chains, are not checked for skew.
+	_, _, err = g.Sign(context.Background(), "osmosis-1", Block{Height: 4})
+	require.NoError(t, err)
+	_, _, err = g.Sign(context.Background(), "cosmoshub-4", Block{Height: 1, Timestamp: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	require.Equal(t, 3, pv.signed)
+}
+
+func TestTimestampSkewGuardRegression(t *testing.T) {
+	pv := newSignedTimestamps()
+	// the timestamps are checked against the last signature without a max timestamp skew too.
+	chains := ChainConfigs{"osmosis-1": {TimestampRegressionCheck: true, TimestampRegressionTolerance: "20ms"}}
+	require.True(t, chains.ChecksTimestamps())
+	g := NewTimestampSkewGuard(pv, chains, pv)
+	ctx := context.Background()
+	now := time.Now()
+
+	_, _, err := g.Sign(ctx, "osmosis-1", Block{Height: 10, Step: stepPrecommit, Timestamp: now})
+	require.NoError(t, err)
+
+	// a later vote timestamped before the last signed vote by more than the tolerance is refused.
+	_, _, err = g.Sign(ctx, "osmosis-1", Block{Height: 11, Step: stepPrevote, Timestamp: now.Add(-100 * time.Millisecond)})
+	require.IsType(t, &TimestampSkewError{}, err)
+	require.ErrorContains(t, err, "before the last signed timestamp")
+
+	// a proposal is timestamped with the block time, which is before the votes of the previous height.
+	_, _, err = g.Sign(ctx, "osmosis-1", Block{Height: 11, Step: stepPropose, Timestamp: now.Add(-100 * time.Millisecond)})
+	require.NoError(t, err)
+	// within the tolerance, e.g. for the clock skew between sentries, a vote is signed.
+	_, _, err = g.Sign(ctx, "osmosis-1", Block{Height: 11, Step: stepPrevote, Timestamp: now.Add(-10 * time.Millisecond)})
+	require.NoError(t, err)
+
+	// a request for the last signed HRS is left to the sign state.
+	_, _, err = g.Sign(ctx, "osmosis-1", Block{Height: 11, Step: stepPrevote, Timestamp: now.Add(-60 * time.Millisecond)})
+	require.NoError(t, err)
+
+	// the last signed timestamp is read from the sign state, so a new guard, e.g. after a restart,
+	// refuses it as well.
+	g = NewTimestampSkewGuard(pv, chains, pv)
+	_, _, err = g.Sign(ctx, "osmosis-1", Block{Height: 11, Step: stepPrecommit, Timestamp: now.Add(-time.Second)})
+	require.IsType(t, &TimestampSkewError{}, err)
+
+	// the timestamps of the chains that do not check regressions may go back.
+	_, _, err = g.Sign(ctx, "cosmoshub-4", Block{Height: 1, Step: stepPrevote, Timestamp: now})
+	require.NoError(t, err)
+	_, _, err = g.Sign(ctx, "cosmoshub-4", Block{Height: 2, Step: stepPrevote, Timestamp: now.Add(-time.Hour)})
+	require.NoError(t, err)
+	require.Equal(t, 6, pv.signed)
+}
+
+func TestLastSignedTimestamp(t *testing.T) {
+	now := time.Now()
+	vote := cometproto.Vote{Height: 10, Round: 1, Type: cometproto.PrecommitType, Timestamp: now}
+	signBytes := comet.VoteSignBytes("osmosis-1", &vote)
+
+	_, ok, err := lastSignedTimestamp(CometSignBytesCodec{}, SignStateConsensus{Height: 12})
+	require.NoError(t, err)
+	require.False(t, ok, "a sign state without sign bytes, e.g. after horcrux state set, has no timestamp")
+
+	last, ok, err := lastSignedTimestamp(CometSignBytesCodec{},
+		SignStateConsensus{Height: 9, Step: stepPrecommit, SignBytes: []byte("older")},
+		SignStateConsensus{Height: 10, Round: 1, Step: stepPrecommit, SignBytes: signBytes},
+		SignStateConsensus{Height: 12},
+	)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, HRSTKey{Height: 10, Round: 1, Step: stepPrecommit, Timestamp: now.UnixNano()}, last)
+}
+
+func TestReconnRemoteSignerChainID(t *testing.T) {
+	pv := &mockPrivValidator{}
+	rs := NewReconnRemoteSigner(
+		ChainNode{PrivValAddr: "tcp://127.0.0.1:1234", ChainID: "osmosis-1"},
+		cometlog.NewNopLogger(),
+		pv,
+		nil,
+		net.Dialer{},
+	)
+
+	vote := func(chainID string) cometprotoprivval.Message {
+		return cometprotoprivval.Message{Sum: &cometprotoprivval.Message_SignVoteRequest{
+			SignVoteRequest: &cometprotoprivval.SignVoteRequest{
+				ChainId: chainID,
+				Vote:    &cometproto.Vote{Type: cometproto.PrevoteType, Height: 1},
+			},
+		}}
+	}
+
+	res := rs.handleRequest(vote("cosmoshub-4"))
+	require.NotNil(t, res.GetSignedVoteResponse().Error)
+	require.Zero(t, pv.signed)
+
+	res = rs.handleRequest(vote("osmosis-1"))
+	require.Nil(t, res.GetSignedVoteResponse().Error)
+	require.Equal(t, 1, pv.signed)
+
+	res = rs.handleRequest(cometprotoprivval.Message{Sum: &cometprotoprivval.Message_PubKeyRequest{
+		PubKeyRequest: &cometprotoprivval.PubKeyRequest{ChainId: "cosmoshub-4"},
+	}})
+	require.NotNil(t, res.GetPubKeyResponse().Error)
+}
@@ -0,0 +1,337 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+)
+
+const (
+	defaultChainRPCMaxLag  = 100
+	defaultChainRPCTimeout = time.Second
+
+	// chainTipCacheTTL bounds how often the chain RPC is queried for the tip,
+	// since a single height is usually signed several times in quick succession.
+	chainTipCacheTTL = time.Second
+
+	// chainTipMaxRetryBackoff bounds how long the failure of a chain RPC query is cached.
+	chainTipMaxRetryBackoff = 30 * time.Second
+)
+
+// ChainRPCConfig configures the chain RPC endpoint used to check sign requests against the chain tip.
+type ChainRPCConfig struct {
+	// URL is the CometBFT RPC endpoint of a node of the chain, e.g. http://sentry-1:26657.
+	URL string `yaml:"url"`
+
+	// MaxLag is the number of blocks a sign request may be below the chain tip. Defaults to 100.
+	MaxLag int64 `yaml:"maxLag,omitempty"`
+
+	// Timeout of a chain RPC request. Defaults to 1s.
+	Timeout string `yaml:"timeout,omitempty"`
+
+	// InitSignState initializes the sign state of the chain at the latest block height
+	// when a signer or cosigner has no sign state for the chain.
+	InitSignState bool `yaml:"initSignState,omitempty"`
+
+	// WatchMissedBlocks checks the commits of the chain for blocks the validator did not sign.
+	WatchMissedBlocks bool `yaml:"watchMissedBlocks,omitempty"`
+}
+
+// ChainRPCConfigs holds the ChainRPCConfig for each chain ID.
+type ChainRPCConfigs map[string]ChainRPCConfig
+
+func (cfgs ChainRPCConfigs) Validate() error {
+	for chainID, cfg := range cfgs {
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("invalid chain rpc for chain %s: %w", chainID, err)
+		}
+	}
+	return nil
+}
+
+// WatchMissedBlocks returns true if any chain watches missed blocks.
+func (cfgs ChainRPCConfigs) WatchMissedBlocks() bool {
+	for _, cfg := range cfgs {
+		if cfg.WatchMissedBlocks {
+			return true
+		}
+	}
+	return false
+}
+
+func (cfg ChainRPCConfig) Validate() error {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("url %q must be http or https", cfg.URL)
+	}
+	if cfg.MaxLag < 0 {
+		return fmt.Errorf("maxLag must not be negative")
+	}
+	if _, err := cfg.timeout(); err != nil {
+		return fmt.Errorf("invalid timeout: %w", err)
+	}
+	return nil
+}
+
+func (cfg ChainRPCConfig) maxLag() int64 {
+	if cfg.MaxLag == 0 {
+		return defaultChainRPCMaxLag
+	}
+	return cfg.MaxLag
+}
+
+func (cfg ChainRPCConfig) timeout() (time.Duration, error) {
+	if cfg.Timeout == "" {
+		return defaultChainRPCTimeout, nil
+	}
+	d, err := time.ParseDuration(cfg.Timeout)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("timeout must be positive")
+	}
+	return d, nil
+}
+
+type ChainTipLagError struct {
+	msg string
+}
+
+func (e *ChainTipLagError) Error() string { return e.msg }
+
+func newChainTipLagError(chainID string, height, tip, maxLag int64) *ChainTipLagError {
+	return &ChainTipLagError{
+		msg: fmt.Sprintf(
+			"refusing to sign chain %s at height %d: %d blocks below chain tip %d (max lag %d), "+
+				"the sign request may be a replay or the sentry may be misconfigured",
+			chainID, height, tip-height, tip, maxLag,
+		),
+	}
+}
+
+// chainTip queries and caches the latest block height of a chain from its RPC.
+type chainTip struct {
+	url    string
+	maxLag int64
+	client *http.Client
+
+	mu      sync.Mutex
+	height  int64
+	fetched time.Time
+
+	// err is the failure of the last query, returned until retryAt.
+	err      error
+	retryAt  time.Time
+	failures int
+
+	// fetching is closed when the query in progress completes.
+	fetching chan struct{}
+}
+
+func newChainTip(cfg ChainRPCConfig) *chainTip {
+	timeout, _ := cfg.timeout()
+	return &chainTip{
+		url:    cfg.URL,
+		maxLag: cfg.maxLag(),
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+type chainRPCStatus struct {
+	Result struct {
+		SyncInfo struct {
+			LatestBlockHeight string `json:"latest_block_height"`
+		} `json:"sync_info"`
+	} `json:"result"`
+}
+
+// chainTipRetryBackoff returns how long the failure of a query is cached after consecutive failures,
+// doubling from chainTipCacheTTL up to chainTipMaxRetryBackoff.
+func chainTipRetryBackoff(failures int) time.Duration {
+	backoff := chainTipCacheTTL
+	for i := 1; i < failures && backoff < chainTipMaxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, chainTipMaxRetryBackoff)
+}
+
+// get returns the latest block height of the chain, from the cache if it is recent.
+// A failed query is also cached, for longer after each consecutive failure, so that an
+// unreachable chain RPC does not delay every sign request by the timeout of the query.
+// Concurrent callers wait for the query in progress rather than query the chain RPC again.
+func (t *chainTip) get(ctx context.Context) (int64, error) {
+	for {
+		t.mu.Lock()
+		now := time.Now()
+		if t.err != nil && now.Before(t.retryAt) {
+			err := t.err
+			t.mu.Unlock()
+			return 0, err
+		}
+		if t.err == nil && !t.fetched.IsZero() && now.Sub(t.fetched) < chainTipCacheTTL {
+			height := t.height
+			t.mu.Unlock()
+			return height, nil
+		}
+		if fetching := t.fetching; fetching != nil {
+			t.mu.Unlock()
+			select {
+			case <-fetching:
+				continue
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			}
+		}
+		fetching := make(chan struct{})
+		t.fetching = fetching
+		t.mu.Unlock()
+
+		height, err := t.fetch(ctx)
+
+		t.mu.Lock()
+		t.fetching = nil
+		close(fetching)
+		switch {
+		case err == nil:
+			// the tip never moves backwards, e.g. if the rpc is load balanced over nodes that are not in sync.
+			if height > t.height {
+				t.height = height
+			}
+			t.fetched = time.Now()
+			t.err = nil
+			t.failures = 0
+			height = t.height
+		case ctx.Err() == nil:
+			// a query abandoned by its caller says nothing of the chain RPC.
+			t.failures++
+			t.err = err
+			t.retryAt = time.Now().Add(chainTipRetryBackoff(t.failures))
+		}
+		t.mu.Unlock()
+		return height, err
+	}
+}
+
+// fetch queries the latest block height of the chain from its RPC.
+func (t *chainTip) fetch(ctx context.Context) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(t.url, "/")+"/status", nil)
+	if err != nil {
+		return 0, err
+	}
+	res, err := t.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("chain rpc status request failed: %s", res.Status)
+	}
+
+	var status chainRPCStatus
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&status); err != nil {
+		return 0, fmt.Errorf("failed to decode chain rpc status: %w", err)
+	}
+	height, err := strconv.ParseInt(status.Result.SyncInfo.LatestBlockHeight, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid chain rpc latest block height: %w", err)
+	}
+	return height, nil
+}
+
+// initialSignStateHeight returns the latest block height of the chain from its chain RPC
+// if the chain RPC is configured to initialize new sign states, otherwise 0.
+func (c RuntimeConfig) initialSignStateHeight(chainID string) (int64, error) {
+	cfg, ok := validatorChainValue(c.Config.ChainRPC, chainID)
+	if !ok || !cfg.InitSignState {
+		return 0, nil
+	}
+	timeout, err := cfg.timeout()
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return newChainTip(cfg).get(ctx)
+}
+
+// ChainTipGuard is a PrivValidator that refuses to sign at heights far below the tip of the chain,
+// which indicates a replayed sign request or a sentry that is misconfigured or far behind.
+// If the chain RPC cannot be reached, signing proceeds and the failure is logged.
+type ChainTipGuard struct {
+	logger cometlog.Logger
+	val    PrivValidator
+	chains map[string]*chainTip
+}
+
+// NewChainTipGuard returns a ChainTipGuard that checks sign requests for the chains configured in cfgs
+// before passing them to val.
+func NewChainTipGuard(logger cometlog.Logger, val PrivValidator, cfgs ChainRPCConfigs) (*ChainTipGuard, error) {
+	if err := cfgs.Validate(); err != nil {
+		return nil, err
+	}
+	chains := make(map[string]*chainTip, len(cfgs))
+	for chainID, cfg := range cfgs {
+		chains[chainID] = newChainTip(cfg)
+	}
+	return &ChainTipGuard{
+		logger: logger,
+		val:    val,
+		chains: chains,
+	}, nil
+}
+
+// Sign implements PrivValidator.
+func (g *ChainTipGuard) Sign(ctx context.Context, chainID string, block Block) ([]byte, time.Time, error) {
+	if err := g.check(ctx, chainID, block.Height); err != nil {
+		return nil, block.Timestamp, err
+	}
+	return g.val.Sign(ctx, chainID, block)
+}
+
+func (g *ChainTipGuard) check(ctx context.Context, chainID string, height int64) error {
+	tip, ok := validatorChainValue(g.chains, chainID)
+	if !ok {
+		return nil
+	}
+
+	tipHeight, err := tip.get(ctx)
+	if err != nil {
+		totalChainTipQueryFailures.WithLabelValues(chainID).Inc()
+		g.logger.Error(
+			"Failed to get chain tip, signing without chain tip check",
+			"chain_id", chainID,
+			"height", height,
+			"error", err,
+		)
+		return nil
+	}
+
+	if height+tip.maxLag < tipHeight {
+		totalChainTipLagRefusals.WithLabelValues(chainID).Inc()
+		return newChainTipLagError(chainID, height, tipHeight, tip.maxLag)
+	}
+	return nil
+}
+
+// GetPubKey implements PrivValidator.
+func (g *ChainTipGuard) GetPubKey(ctx context.Context, chainID string) ([]byte, error) {
+	return g.val.GetPubKey(ctx, chainID)
+}
+
+// Stop implements PrivValidator.
+func (g *ChainTipGuard) Stop() {
+	g.val.Stop()
+}
@@ -0,0 +1,198 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPrivValidator struct {
+	signed int
+}
+
+func (pv *mockPrivValidator) Sign(_ context.Context, _ string, block Block) ([]byte, time.Time, error) {
+	pv.signed++
+	return []byte("signature"), block.Timestamp, nil
+}
+
+func (pv *mockPrivValidator) GetPubKey(context.Context, string) ([]byte, error) { return nil, nil }
+
+func (pv *mockPrivValidator) Stop() {}
+
+func TestChainTipGuard(t *testing.T) {
+	var tip atomic.Int64
+	tip.Store(1000)
+	var queries atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/status", r.URL.Path)
+		queries.Add(1)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":-1,"result":{"sync_info":{"latest_block_height":"%d"}}}`, tip.Load())
+	}))
+	defer srv.Close()
+
+	pv := &mockPrivValidator{}
+	guard, err := NewChainTipGuard(log.NewNopLogger(), pv, ChainRPCConfigs{
+		testChainID: {URL: srv.URL, MaxLag: 10},
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, _, err = guard.Sign(ctx, testChainID, Block{Height: 1001, Step: stepPrevote})
+	require.NoError(t, err)
+
+	_, _, err = guard.Sign(ctx, testChainID, Block{Height: 990, Step: stepPrevote})
+	require.NoError(t, err)
+
+	_, _, err = guard.Sign(ctx, testChainID, Block{Height: 989, Step: stepPrevote})
+	var lagErr *ChainTipLagError
+	require.ErrorAs(t, err, &lagErr)
+	require.ErrorContains(t, err, "11 blocks below chain tip 1000")
+	require.Equal(t, 2, pv.signed)
+
+	// the tip is cached between sign requests.
+	require.Equal(t, int32(1), queries.Load())
+
+	// chains without a chain rpc are not checked.
+	_, _, err = guard.Sign(ctx, "other-chain", Block{Height: 1, Step: stepPrevote})
+	require.NoError(t, err)
+	require.Equal(t, 3, pv.signed)
+}
+
+func TestChainTipGuardUnreachable(t *testing.T) {
+	var queries atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		queries.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	pv := &mockPrivValidator{}
+	guard, err := NewChainTipGuard(log.NewNopLogger(), pv, ChainRPCConfigs{
+		testChainID: {URL: srv.URL},
+	})
+	require.NoError(t, err)
+
+	// signing proceeds if the chain tip cannot be queried.
+	_, _, err = guard.Sign(context.Background(), testChainID, Block{Height: 1, Step: stepPrevote})
+	require.NoError(t, err)
+	require.Equal(t, 1, pv.signed)
+
+	// the failure is cached, so that the chain rpc is not queried for every sign request.
+	_, _, err = guard.Sign(context.Background(), testChainID, Block{Height: 1, Step: stepPrecommit})
+	require.NoError(t, err)
+	require.Equal(t, 2, pv.signed)
+	require.Equal(t, int32(1), queries.Load())
+
+	// and retried once it expires, with a backoff.
+	tip := guard.chains[testChainID]
+	tip.mu.Lock()
+	tip.retryAt = time.Now()
+	tip.mu.Unlock()
+	_, err = tip.get(context.Background())
+	require.ErrorContains(t, err, "503 Service Unavailable")
+	require.Equal(t, int32(2), queries.Load())
+	tip.mu.Lock()
+	require.Greater(t, time.Until(tip.retryAt), chainTipCacheTTL)
+	tip.mu.Unlock()
+}
+
+func TestChainTipRetryBackoff(t *testing.T) {
+	require.Equal(t, chainTipCacheTTL, chainTipRetryBackoff(1))
+	require.Equal(t, 2*chainTipCacheTTL, chainTipRetryBackoff(2))
+	require.Equal(t, 4*chainTipCacheTTL, chainTipRetryBackoff(3))
+	require.Equal(t, chainTipMaxRetryBackoff, chainTipRetryBackoff(100))
+}
+
+func TestChainTipConcurrent(t *testing.T) {
+	var queries atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		queries.Add(1)
+		<-release
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":-1,"result":{"sync_info":{"latest_block_height":"1000"}}}`)
+	}))
+	defer srv.Close()
+
+	tip := newChainTip(ChainRPCConfig{URL: srv.URL, Timeout: "10s"})
+
+	// concurrent callers wait for the query in progress.
+	results := make(chan int64, 3)
+	for i := 0; i < cap(results); i++ {
+		go func() {
+			height, err := tip.get(context.Background())
+			require.NoError(t, err)
+			results <- height
+		}()
+	}
+	require.Eventually(t, func() bool { return queries.Load() == 1 }, time.Second, time.Millisecond)
+
+	// the mutex is not held during the query, so a caller that gives up is not blocked by it.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := tip.get(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	for i := 0; i < cap(results); i++ {
+		require.Equal(t, int64(1000), <-results)
+	}
+	require.Equal(t, int32(1), queries.Load())
+}
+
+func TestChainRPCConfigValidate(t *testing.T) {
+	require.NoError(t, ChainRPCConfigs{testChainID: {URL: "http://localhost:26657"}}.Validate())
+	require.Error(t, ChainRPCConfigs{testChainID: {URL: "tcp://localhost:26657"}}.Validate())
+	require.Error(t, ChainRPCConfigs{testChainID: {URL: "http://localhost:26657", MaxLag: -1}}.Validate())
+	require.Error(t, ChainRPCConfigs{testChainID: {URL: "http://localhost:26657", Timeout: "soon"}}.Validate())
+}
+
+func TestInitSignStateFromChainRPC(t *testing.T) {
+	var tip atomic.Int64
+	tip.Store(5000)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":-1,"result":{"sync_info":{"latest_block_height":"%d"}}}`, tip.Load())
+	}))
+	defer srv.Close()
+
+	c := RuntimeConfig{
+		StateDir: t.TempDir(),
+		Config: Config{
+			ChainRPC: ChainRPCConfigs{
+				testChainID: {URL: srv.URL, InitSignState: true},
+			},
+		},
+	}
+
+	for _, kind := range []SignStateKind{SignStateKindPrivVal, SignStateKindCosigner} {
+		ss, err := c.loadSignState(log.NewNopLogger(), testChainID, kind, nil)
+		require.NoError(t, err)
+		require.Equal(t, HRSKey{Height: 5000}, ss.HRSKey())
+	}
+
+	// an existing sign state is not changed.
+	tip.Store(6000)
+	ss, err := c.loadSignState(log.NewNopLogger(), testChainID, SignStateKindPrivVal, nil)
+	require.NoError(t, err)
+	require.Equal(t, HRSKey{Height: 5000}, ss.HRSKey())
+
+	// chains without initSignState start at height 0.
+	ss, err = c.loadSignState(log.NewNopLogger(), "other-chain", SignStateKindPrivVal, nil)
+	require.NoError(t, err)
+	require.Equal(t, HRSKey{}, ss.HRSKey())
+
+	// signing does not start from height 0 if the chain rpc is unreachable.
+	srv.Close()
+	c.Config.ChainRPC["chain-2"] = ChainRPCConfig{URL: srv.URL, InitSignState: true}
+	_, err = c.loadSignState(log.NewNopLogger(), "chain-2", SignStateKindPrivVal, nil)
+	require.ErrorContains(t, err, "failed to initialize priv_validator sign state from chain rpc")
+}
@@ -0,0 +1,169 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	keyShardSuffix = "_shard.json"
+
+	// removedKeyShardSuffix is appended to the key shard file of a removed chain, so that the chain
+	// is not signed after a restart either, and the key shard can be restored.
+	removedKeyShardSuffix = ".removed"
+)
+
+// chainIDPattern restricts the chain IDs added at runtime, without the validator, to names that are
+// safe in file names.
+var chainIDPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// ChainReadiness is a chain and the cosigners that have a key shard of it. The chain is ready to be
+// signed once threshold cosigners have a key shard.
+type ChainReadiness struct {
+	ChainID   string `json:"chain_id"`
+	Cosigners []int  `json:"cosigners"`
+	Ready     bool   `json:"ready"`
+}
+
+// KeyShardChainIDs returns the chain IDs this cosigner has a key shard of.
+func (pv *ThresholdValidator) KeyShardChainIDs() ([]string, error) {
+	return pv.config.keyFileChainIDs(keyShardSuffix)
+}
+
+// AddChain adds the chain with the key shard of this cosigner, so that it is signed without a
+// restart. The sign states of the chain are raised to height, unless they are already above it.
+// Every cosigner must add the chain with its own key shard; the chain can be signed once threshold
+// cosigners have added it, which Chains reports.
+func (pv *ThresholdValidator) AddChain(chainID string, shard CosignerEd25519Key, height int64) error {
+	if _, id := SplitValidatorChainID(chainID); !chainIDPattern.MatchString(id) {
+		return fmt.Errorf("invalid chain ID %q", chainID)
+	}
+	if shard.ID != pv.myCosigner.GetID() {
+		return fmt.Errorf("key shard is of cosigner %d, not of cosigner %d", shard.ID, pv.myCosigner.GetID())
+	}
+	if height < 0 {
+		return fmt.Errorf("height cannot be negative")
+	}
+	keyFile := pv.config.KeyFilePathCosigner(chainID)
+	if _, err := os.Stat(keyFile); err == nil {
+		return fmt.Errorf("chain %s is already added", chainID)
+	}
+
+	for _, kind := range []SignStateKind{SignStateKindPrivVal, SignStateKindCosigner} {
+		if err := pv.seedSignState(chainID, kind, height); err != nil {
+			return err
+		}
+	}
+
+	if err := WriteCosignerEd25519ShardFile(shard, keyFile); err != nil {
+		return fmt.Errorf("failed to write key shard: %w", err)
+	}
+	// a chain that was signed before the key shard was added has no cosigner chain state.
+	pv.chainState.Delete(chainID)
+	if err := pv.LoadSignStateIfNecessary(chainID); err != nil {
+		pv.chainState.Delete(chainID)
+		return errors.Join(fmt.Errorf("failed to load chain %s: %w", chainID, err), os.Remove(keyFile))
+	}
+
+	pv.logger.Info("Added chain", "chain_id", chainID, "height", height)
+	return nil
+}
+
+// seedSignState raises the sign state of the kind of the chain to height.
+func (pv *ThresholdValidator) seedSignState(chainID string, kind SignStateKind, height int64) error {
+	store, err := pv.config.SignStateStore(chainID, kind)
+	if err != nil {
+		return err
+	}
+	signState, err := LoadOrCreateSignStateFromStore(store)
+	if err != nil {
+		return fmt.Errorf("failed to load %s sign state: %w", kind, err)
+	}
+	if signState.HRSKey().Height >= height {
+		return nil
+	}
+	if err := signState.Save(NewSignStateConsensus(height, 0, 0), nil); err != nil {
+		return fmt.Errorf("failed to save %s sign state: %w", kind, err)
+	}
+	return nil
+}
+
+// RemoveChain stops signing the chain once the sign requests in flight are finished. The key
+// shard is moved aside and the sign states are kept, so that the chain can be added again.
+func (pv *ThresholdValidator) RemoveChain(ctx context.Context, chainID string) error {
+	keyFile := pv.config.KeyFilePathCosigner(chainID)
+	if _, err := os.Stat(keyFile); err != nil {
+		return fmt.Errorf("chain %s is not added", chainID)
+	}
+
+	if err := pv.drainSignRounds(ctx); err != nil {
+		return err
+	}
+	defer pv.signRounds.Unlock()
+
+	if err := os.Rename(keyFile, keyFile+removedKeyShardSuffix); err != nil {
+		return fmt.Errorf("failed to move key shard aside: %w", err)
+	}
+	pv.chainState.Delete(chainID)
+	pv.myCosigner.chainState.Delete(chainID)
+
+	pv.logger.Info("Removed chain", "chain_id", chainID)
+	return nil
+}
+
+// Chains returns the chains that this cosigner or its peers have a key shard of, and which
+// cosigners have one. Unreachable peers are counted as without key shards.
+func (pv *ThresholdValidator) Chains(ctx context.Context) ([]ChainReadiness, error) {
+	local, err := pv.KeyShardChainIDs()
+	if err != nil {
+		return nil, err
+	}
+	cosigners := map[string][]int{}
+	for _, chainID := range local {
+		cosigners[chainID] = append(cosigners[chainID], pv.myCosigner.GetID())
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pv.GRPCTimeout())
+	defer cancel()
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, peer := range pv.Peers() {
+		rc, ok := peer.(*RemoteCosigner)
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status, err := rc.GetStatus(ctx)
+			if err != nil {
+				pv.logger.Debug("Failed to get the chains of cosigner", "cosigner", rc.GetID(), "error", err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, chainID := range status.KeyShardChainIDs {
+				cosigners[chainID] = append(cosigners[chainID], rc.GetID())
+			}
+		}()
+	}
+	wg.Wait()
+
+	chains := make([]ChainReadiness, 0, len(cosigners))
+	for chainID, ids := range cosigners {
+		slices.Sort(ids)
+		chains = append(chains, ChainReadiness{
+			ChainID:   chainID,
+			Cosigners: ids,
+			Ready:     len(ids) >= pv.threshold,
+		})
+	}
+	slices.SortFunc(chains, func(a, b ChainReadiness) int { return strings.Compare(a.ChainID, b.ChainID) })
+	return chains, nil
+}
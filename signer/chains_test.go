@@ -0,0 +1,67 @@
+package signer
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/privval"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThresholdValidatorAddRemoveChain(t *testing.T) {
+	cosigners, _ := getTestLocalCosigners(t, 2, 3)
+	pv := NewThresholdValidator(
+		cometlog.NewNopLogger(),
+		cosigners[0].config,
+		2,
+		time.Second,
+		1,
+		cosigners[0],
+		[]Cosigner{cosigners[1], cosigners[2]},
+		&MockLeader{id: 1},
+	)
+	defer pv.Stop()
+
+	const chainID = "horcrux-3"
+	shards := CreateCosignerEd25519Shards(privval.FilePVKey{
+		PubKey:  cometcryptoed25519.GenPrivKey().PubKey(),
+		PrivKey: cometcryptoed25519.GenPrivKey(),
+	}, 2, 3)
+
+	require.ErrorContains(t, pv.AddChain("../horcrux", shards[0], 0), "invalid chain ID")
+	require.ErrorContains(t, pv.AddChain(chainID, shards[1], 0), "key shard is of cosigner 2")
+	require.ErrorContains(t, pv.AddChain(testChainID, shards[0], 0), "already added")
+
+	require.NoError(t, pv.AddChain(chainID, shards[0], 100))
+	chainIDs, err := pv.KeyShardChainIDs()
+	require.NoError(t, err)
+	require.Equal(t, []string{testChainID, testChainID2, chainID}, chainIDs)
+	for _, kind := range []SignStateKind{SignStateKindPrivVal, SignStateKindCosigner} {
+		hrs, err := pv.GetSignState(context.Background(), chainID, kind)
+		require.NoError(t, err)
+		require.EqualValues(t, 100, hrs.Height)
+	}
+
+	// the peers are not remote cosigners, so only this cosigner has a key shard of the chain.
+	chains, err := pv.Chains(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, ChainReadiness{ChainID: chainID, Cosigners: []int{1}}, chains[2])
+
+	require.NoError(t, pv.RemoveChain(context.Background(), chainID))
+	require.ErrorContains(t, pv.RemoveChain(context.Background(), chainID), "not added")
+	_, err = os.Stat(pv.config.KeyFilePathCosigner(chainID) + removedKeyShardSuffix)
+	require.NoError(t, err)
+	chainIDs, err = pv.KeyShardChainIDs()
+	require.NoError(t, err)
+	require.Equal(t, []string{testChainID, testChainID2}, chainIDs)
+
+	// the chain is added again with its sign states, which are not lowered.
+	require.NoError(t, pv.AddChain(chainID, shards[0], 0))
+	hrs, err := pv.GetSignState(context.Background(), chainID, SignStateKindPrivVal)
+	require.NoError(t, err)
+	require.EqualValues(t, 100, hrs.Height)
+}
@@ -0,0 +1,166 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CosignerKillSwitchStatus is whether the kill switch of a cosigner is engaged, or the error of the
+// request to the cosigner.
+type CosignerKillSwitchStatus struct {
+	ID int `json:"id"`
+	KillSwitchStatus
+	Error string `json:"error,omitempty"`
+}
+
+// ClusterKillSwitchStatus is whether the kill switch of each cosigner of the cluster is engaged.
+type ClusterKillSwitchStatus struct {
+	// Engaged is whether the kill switch is engaged on every cosigner.
+	Engaged   bool                       `json:"engaged"`
+	Cosigners []CosignerKillSwitchStatus `json:"cosigners"`
+}
+
+// Failed returns whether a cosigner did not answer.
+func (s ClusterKillSwitchStatus) Failed() bool {
+	for _, c := range s.Cosigners {
+		if c.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// SetKillSwitch sets the kill switch of this cosigner, which is engaged and re-armed by the other
+// cosigners when the kill switch of the cluster is.
+func (pv *ThresholdValidator) SetKillSwitch(kill *KillSwitchValidator) {
+	pv.kill.Store(kill)
+}
+
+func (pv *ThresholdValidator) killSwitch() *KillSwitchValidator {
+	return pv.kill.Load()
+}
+
+// killSwitchEngagedError returns a *KillSwitchError if the kill switch of this cosigner is engaged,
+// or an error if it is not loaded yet, as it may be engaged in the state directory.
+func (pv *ThresholdValidator) killSwitchEngagedError() error {
+	kill := pv.killSwitch()
+	if kill == nil {
+		return status.Error(codes.Unavailable, "kill switch of the cosigner is not loaded")
+	}
+	return kill.EngagedError()
+}
+
+// SetAdminApprovals sets the approvals that the admin operations require, which must be set before
+// the kill switch: the other cosigners re-arm the kill switch of this cosigner with the approvals of
+// the re-arm of the cluster, which this cosigner verifies as well.
+func (pv *ThresholdValidator) SetAdminApprovals(approvals *AdminApprovals) {
+	pv.approvals.Store(approvals)
+}
+
+// approveClusterRearm verifies the approvals of the re-arm of the cluster forwarded by a peer, if
+// the re-arm requires approvals on this cosigner, and returns a PermissionDenied error if they do not.
+func (pv *ThresholdValidator) approveClusterRearm(approvals []string) error {
+	request, err := AdminApprovalRequest(http.MethodPost, "/v1/cluster/kill_switch/rearm")
+	if err != nil {
+		return err
+	}
+	_, err = pv.approvals.Load().Approve(AdminOperationKillSwitchRearm, request, approvals)
+	var approvalErr *AdminApprovalError
+	if errors.As(err, &approvalErr) {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return err
+}
+
+// setKillSwitch engages the kill switch of this cosigner, or re-arms signing.
+func (pv *ThresholdValidator) setKillSwitch(engaged bool, reason string) (KillSwitchStatus, error) {
+	kill := pv.killSwitch()
+	if kill == nil {
+		return KillSwitchStatus{}, fmt.Errorf("kill switch cannot be engaged before the signer is started")
+	}
+	if engaged {
+		pv.logger.Error("Kill switch engaged, signing is disabled until it is re-armed", "reason", reason)
+		return kill.Engage(reason)
+	}
+	pv.logger.Info("Kill switch re-armed")
+	return kill.Rearm()
+}
+
+// SetClusterKillSwitch engages the kill switch of every cosigner of the cluster, or re-arms them.
+// A cosigner with an engaged kill switch refuses the sign requests of its chain nodes, the sign
+// requests proxied by the other cosigners and signing with its key shard, also after a restart,
+// until it is re-armed. The cosigners that do not answer within the gRPC timeout are reported with
+// their error, engage or re-arm them again once they are reachable. The approvals of the re-arm of
+// the cluster are forwarded to the other cosigners, which verify them if their re-arm requires them.
+func (pv *ThresholdValidator) SetClusterKillSwitch(
+	ctx context.Context,
+	engaged bool,
+	reason string,
+	approvals []string,
+) (ClusterKillSwitchStatus, error) {
+	if engaged {
+		approvals = nil
+	}
+	local, err := pv.setKillSwitch(engaged, reason)
+	if err != nil {
+		return ClusterKillSwitchStatus{}, err
+	}
+	statuses := []CosignerKillSwitchStatus{{ID: pv.myCosigner.GetID(), KillSwitchStatus: local}}
+
+	for _, r := range eachRemotePeer(ctx, pv, func(ctx context.Context, rc *RemoteCosigner) (KillSwitchStatus, error) {
+		auth, err := pv.signCosignerRequest(rc.GetID(), "SetKillSwitch",
+			append([]string{strconv.FormatBool(engaged), reason}, approvals...)...)
+		if err != nil {
+			return KillSwitchStatus{}, err
+		}
+		res, err := rc.SetKillSwitch(ctx, engaged, reason, approvals, auth)
+		if err != nil {
+			return KillSwitchStatus{}, err
+		}
+		return KillSwitchStatusFromProto(res.GetStatus()), nil
+	}) {
+		if r.err != "" {
+			pv.logger.Error("Failed to engage or re-arm the kill switch of cosigner", "cosigner", r.id, "error", r.err)
+		}
+		statuses = append(statuses, CosignerKillSwitchStatus{ID: r.id, KillSwitchStatus: r.status, Error: r.err})
+	}
+	return newClusterKillSwitchStatus(statuses), nil
+}
+
+// ClusterKillSwitch returns whether the kill switch of each cosigner of the cluster is engaged.
+func (pv *ThresholdValidator) ClusterKillSwitch(ctx context.Context) ClusterKillSwitchStatus {
+	var local KillSwitchStatus
+	if kill := pv.killSwitch(); kill != nil {
+		local = kill.Status()
+	}
+	statuses := []CosignerKillSwitchStatus{{ID: pv.myCosigner.GetID(), KillSwitchStatus: local}}
+
+	for _, r := range eachRemotePeer(ctx, pv, func(ctx context.Context, rc *RemoteCosigner) (KillSwitchStatus, error) {
+		res, err := rc.GetStatus(ctx)
+		if err != nil {
+			return KillSwitchStatus{}, err
+		}
+		return KillSwitchStatusFromProto(res.GetKillSwitch()), nil
+	}) {
+		statuses = append(statuses, CosignerKillSwitchStatus{ID: r.id, KillSwitchStatus: r.status, Error: r.err})
+	}
+	return newClusterKillSwitchStatus(statuses)
+}
+
+func newClusterKillSwitchStatus(statuses []CosignerKillSwitchStatus) ClusterKillSwitchStatus {
+	slices.SortFunc(statuses, func(a, b CosignerKillSwitchStatus) int { return a.ID - b.ID })
+	engaged := true
+	for _, s := range statuses {
+		if !s.Engaged || s.Error != "" {
+			engaged = false
+		}
+	}
+	return ClusterKillSwitchStatus{Engaged: engaged, Cosigners: statuses}
+}
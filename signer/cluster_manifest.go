@@ -0,0 +1,128 @@
+package signer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ClusterManifest describes a whole threshold cluster: the config shared by every cosigner, and
+// the cosigners with their own addresses and chain nodes. Every cosigner derives its config from
+// the same manifest by its shard ID, so that the configs of the cosigners cannot drift apart.
+type ClusterManifest struct {
+	// Config is the config shared by every cosigner, in the format of config.yaml. The cosigners
+	// of thresholdMode are taken from Cosigners, and must not be set.
+	Config Config `yaml:"config"`
+
+	Cosigners []ClusterManifestCosigner `yaml:"cosigners"`
+}
+
+// ClusterManifestCosigner is a cosigner of a ClusterManifest.
+type ClusterManifestCosigner struct {
+	CosignerConfig `yaml:",inline"`
+
+	// ChainNodes, DebugAddr and GRPCAddr replace those of the shared config for the cosigner if set.
+	ChainNodes ChainNodes `yaml:"chainNodes,omitempty"`
+	DebugAddr  string     `yaml:"debugAddr,omitempty"`
+	GRPCAddr   string     `yaml:"grpcAddr,omitempty"`
+
+	// Chains are the chain nodes of the cosigner that only sign a chain, by chain ID. They are
+	// added to the chain nodes of the chain in the shared config.
+	Chains map[string]ChainNodes `yaml:"chains,omitempty"`
+}
+
+// ReadClusterManifest reads the cluster manifest from the file. The environment variable and
+// file references of the values are kept, and resolved when the derived config is loaded.
+func ReadClusterManifest(file string) (*ClusterManifest, []byte, error) {
+	bz, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	var m ClusterManifest
+	if err := yaml.UnmarshalStrict(bz, &m); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse cluster manifest %s: %w", file, err)
+	}
+	return &m, bz, nil
+}
+
+// ClusterManifestDigest returns the hex SHA-256 of the manifest file contents, which identifies
+// the manifest that a config was derived from.
+func ClusterManifestDigest(bz []byte) string {
+	sum := sha256.Sum256(bz)
+	return hex.EncodeToString(sum[:])
+}
+
+// ConfigOf derives the config of the cosigner with the shard ID from the manifest, and validates
+// it with its references resolved.
+func (m *ClusterManifest) ConfigOf(shardID int) (Config, error) {
+	if m.Config.SignMode != "" && m.Config.SignMode != SignModeThreshold {
+		return Config{}, fmt.Errorf("cluster manifest must be of sign mode %s", SignModeThreshold)
+	}
+	if m.Config.ThresholdModeConfig == nil {
+		return Config{}, fmt.Errorf("cluster manifest is missing config.thresholdMode")
+	}
+	if len(m.Config.ThresholdModeConfig.Cosigners) != 0 {
+		return Config{}, fmt.Errorf("cluster manifest cannot set config.thresholdMode.cosigners, " +
+			"they are taken from cosigners")
+	}
+
+	var self *ClusterManifestCosigner
+	cosigners := make(CosignersConfig, len(m.Cosigners))
+	for i := range m.Cosigners {
+		cosigners[i] = m.Cosigners[i].CosignerConfig
+		if m.Cosigners[i].ShardID == shardID {
+			self = &m.Cosigners[i]
+		}
+	}
+	if self == nil {
+		return Config{}, fmt.Errorf("cosigner with shard ID %d is not in the cluster manifest", shardID)
+	}
+
+	cfg := m.Config
+	cfg.SignMode = SignModeThreshold
+
+	thresholdCfg := *m.Config.ThresholdModeConfig
+	thresholdCfg.Cosigners = cosigners
+	cfg.ThresholdModeConfig = &thresholdCfg
+
+	if self.ChainNodes != nil {
+		cfg.ChainNodes = self.ChainNodes
+	}
+	if self.DebugAddr != "" {
+		cfg.DebugAddr = self.DebugAddr
+	}
+	if self.GRPCAddr != "" {
+		cfg.GRPCAddr = self.GRPCAddr
+	}
+
+	if len(self.Chains) > 0 {
+		chains := make(ChainConfigs, len(m.Config.Chains)+len(self.Chains))
+		for id, chain := range m.Config.Chains {
+			chains[id] = chain
+		}
+		for id, nodes := range self.Chains {
+			chain := chains[id]
+			chain.ChainNodes = append(append(ChainNodes(nil), chain.ChainNodes...), nodes...)
+			chains[id] = chain
+		}
+		cfg.Chains = chains
+	}
+
+	// validate the config as it is loaded, with its references resolved.
+	bz, err := ExpandConfigReferences(cfg.MustMarshalYaml())
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid config of cosigner %d: %w", shardID, err)
+	}
+	var expanded Config
+	if err := yaml.Unmarshal(bz, &expanded); err != nil {
+		return Config{}, fmt.Errorf("invalid config of cosigner %d: %w", shardID, err)
+	}
+	if err := expanded.ValidateThresholdModeConfig(); err != nil {
+		return Config{}, fmt.Errorf("invalid config of cosigner %d: %w", shardID, err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,132 @@
+package signer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testClusterManifest = `config:
+  thresholdMode:
+    threshold: 2
+    grpcTimeout: 1000ms
+    raftTimeout: 1000ms
+  debugAddr: 0.0.0.0:6001
+  chains:
+    osmosis-1:
+      grpcTimeout: 300ms
+cosigners:
+  - shardID: 1
+    p2pAddr: tcp://horcrux-1:2222
+    chainNodes:
+      - privValAddr: tcp://sentry-1:1234
+    chains:
+      osmosis-1:
+        - privValAddr: tcp://osmosis-sentry-1:1234
+  - shardID: 2
+    p2pAddr: tcp://horcrux-2:2222
+    priority: 1
+    chainNodes:
+      - privValAddr: tcp://${HORCRUX_TEST_SENTRY}:1234
+    debugAddr: 0.0.0.0:6002
+  - shardID: 3
+    p2pAddr: tcp://horcrux-3:2222
+    chainNodes:
+      - privValAddr: tcp://sentry-3:1234
+`
+
+func TestClusterManifestConfigOf(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "cluster.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(testClusterManifest), 0600))
+	t.Setenv("HORCRUX_TEST_SENTRY", "sentry-2")
+
+	m, bz, err := ReadClusterManifest(file)
+	require.NoError(t, err)
+	require.Len(t, ClusterManifestDigest(bz), 64)
+
+	cosigners := CosignersConfig{
+		{ShardID: 1, P2PAddr: "tcp://horcrux-1:2222"},
+		{ShardID: 2, P2PAddr: "tcp://horcrux-2:2222", Priority: 1},
+		{ShardID: 3, P2PAddr: "tcp://horcrux-3:2222"},
+	}
+
+	cfg, err := m.ConfigOf(1)
+	require.NoError(t, err)
+	require.Equal(t, SignModeThreshold, cfg.SignMode)
+	require.Equal(t, 2, cfg.ThresholdModeConfig.Threshold)
+	require.Equal(t, cosigners, cfg.ThresholdModeConfig.Cosigners)
+	require.Equal(t, ChainNodes{{PrivValAddr: "tcp://sentry-1:1234"}}, cfg.ChainNodes)
+	require.Equal(t, "0.0.0.0:6001", cfg.DebugAddr)
+	require.Equal(t, ChainConfigs{"osmosis-1": {
+		GRPCTimeout: "300ms",
+		ChainNodes:  ChainNodes{{PrivValAddr: "tcp://osmosis-sentry-1:1234"}},
+	}}, cfg.Chains)
+
+	cfg, err = m.ConfigOf(2)
+	require.NoError(t, err)
+	require.Equal(t, cosigners, cfg.ThresholdModeConfig.Cosigners)
+	// the references are kept, and resolved when the config is loaded.
+	require.Equal(t, ChainNodes{{PrivValAddr: "tcp://${HORCRUX_TEST_SENTRY}:1234"}}, cfg.ChainNodes)
+	require.Equal(t, "0.0.0.0:6002", cfg.DebugAddr)
+	require.Equal(t, ChainConfigs{"osmosis-1": {GRPCTimeout: "300ms"}}, cfg.Chains)
+
+	// deriving a config does not change the manifest.
+	require.Nil(t, m.Config.ThresholdModeConfig.Cosigners)
+	require.Empty(t, m.Config.Chains["osmosis-1"].ChainNodes)
+
+	_, err = m.ConfigOf(4)
+	require.EqualError(t, err, "cosigner with shard ID 4 is not in the cluster manifest")
+}
+
+func TestClusterManifestConfigOfInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest ClusterManifest
+		err      string
+	}{
+		{
+			name: "no threshold mode",
+			manifest: ClusterManifest{
+				Cosigners: []ClusterManifestCosigner{{CosignerConfig: CosignerConfig{ShardID: 1}}},
+			},
+			err: "cluster manifest is missing config.thresholdMode",
+		},
+		{
+			name: "cosigners in config",
+			manifest: ClusterManifest{
+				Config: Config{ThresholdModeConfig: &ThresholdModeConfig{Cosigners: CosignersConfig{{ShardID: 1}}}},
+			},
+			err: "cluster manifest cannot set config.thresholdMode.cosigners, they are taken from cosigners",
+		},
+		{
+			name: "duplicate shard ID",
+			manifest: ClusterManifest{
+				Config: Config{ThresholdModeConfig: &ThresholdModeConfig{
+					Threshold:   2,
+					GRPCTimeout: "1000ms",
+					RaftTimeout: "1000ms",
+				}},
+				Cosigners: []ClusterManifestCosigner{
+					{
+						CosignerConfig: CosignerConfig{ShardID: 1, P2PAddr: "tcp://horcrux-1:2222"},
+						ChainNodes:     ChainNodes{{PrivValAddr: "tcp://sentry-1:1234"}},
+					},
+					{
+						CosignerConfig: CosignerConfig{ShardID: 1, P2PAddr: "tcp://horcrux-2:2222"},
+						ChainNodes:     ChainNodes{{PrivValAddr: "tcp://sentry-2:1234"}},
+					},
+				},
+			},
+			err: "invalid config of cosigner 1: found duplicate cosigner shard ID(s) in args: " +
+				"map[1:[tcp://horcrux-1:2222 tcp://horcrux-2:2222]]",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.manifest.ConfigOf(1)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
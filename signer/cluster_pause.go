@@ -0,0 +1,193 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/strangelove-ventures/horcrux/signer/proto"
+)
+
+// CosignerSigningStatus is whether signing is paused on a cosigner, or the error of the request
+// to the cosigner.
+type CosignerSigningStatus struct {
+	ID int `json:"id"`
+	SigningStatus
+	Error string `json:"error,omitempty"`
+}
+
+// ClusterSigningStatus is whether signing is paused on each cosigner of the cluster.
+type ClusterSigningStatus struct {
+	// Paused is whether signing of the cluster is paused on every cosigner.
+	Paused    bool                    `json:"paused"`
+	Cosigners []CosignerSigningStatus `json:"cosigners"`
+}
+
+// Failed returns whether a cosigner did not answer.
+func (s ClusterSigningStatus) Failed() bool {
+	for _, c := range s.Cosigners {
+		if c.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// SetSigningPause sets the pause of the sign requests of the chain nodes of this cosigner, which
+// is paused and resumed by the other cosigners when signing of the cluster is.
+func (pv *ThresholdValidator) SetSigningPause(pause *PauseValidator) {
+	pv.pause.Store(pause)
+}
+
+func (pv *ThresholdValidator) signingPause() *PauseValidator {
+	return pv.pause.Load()
+}
+
+// setSigningPaused pauses signing of the cluster on this cosigner, or resumes signing.
+func (pv *ThresholdValidator) setSigningPaused(paused bool) (SigningStatus, error) {
+	pause := pv.signingPause()
+	if pause == nil {
+		return SigningStatus{}, fmt.Errorf("signing cannot be paused before the signer is started")
+	}
+	if paused {
+		pause.PauseCluster()
+	} else {
+		pause.Resume()
+	}
+	return pause.Status(), nil
+}
+
+// SetClusterSigningPaused pauses signing of the whole cluster, e.g. for a planned chain halt or an
+// emergency stop, or resumes it. Every cosigner refuses the sign requests of its chain nodes, the
+// sign requests proxied by the other cosigners and signing with its key shard while signing of the
+// cluster is paused. The cosigners that do not answer within the gRPC timeout are reported with
+// their error, pause or resume them again once they are reachable.
+func (pv *ThresholdValidator) SetClusterSigningPaused(ctx context.Context, paused bool) (ClusterSigningStatus, error) {
+	local, err := pv.setSigningPaused(paused)
+	if err != nil {
+		return ClusterSigningStatus{}, err
+	}
+	statuses := []CosignerSigningStatus{{ID: pv.myCosigner.GetID(), SigningStatus: local}}
+
+	for _, r := range eachRemotePeer(ctx, pv, func(ctx context.Context, rc *RemoteCosigner) (SigningStatus, error) {
+		auth, err := pv.signCosignerRequest(rc.GetID(), "SetSigningPaused", strconv.FormatBool(paused))
+		if err != nil {
+			return SigningStatus{}, err
+		}
+		res, err := rc.SetSigningPaused(ctx, paused, auth)
+		if err != nil {
+			return SigningStatus{}, err
+		}
+		return SigningStatusFromProto(res.GetStatus()), nil
+	}) {
+		statuses = append(statuses, CosignerSigningStatus{ID: r.id, SigningStatus: r.status, Error: r.err})
+	}
+
+	for _, s := range statuses {
+		if s.Error != "" {
+			pv.logger.Error("Failed to pause or resume signing of cosigner", "cosigner", s.ID, "error", s.Error)
+		}
+	}
+	if paused {
+		pv.logger.Info("Paused signing of the cluster")
+	} else {
+		pv.logger.Info("Resumed signing of the cluster")
+	}
+	return newClusterSigningStatus(statuses), nil
+}
+
+// ClusterSigning returns whether signing is paused on each cosigner of the cluster.
+func (pv *ThresholdValidator) ClusterSigning(ctx context.Context) ClusterSigningStatus {
+	var local SigningStatus
+	if pause := pv.signingPause(); pause != nil {
+		local = pause.Status()
+	}
+	statuses := []CosignerSigningStatus{{ID: pv.myCosigner.GetID(), SigningStatus: local}}
+
+	for _, r := range eachRemotePeer(ctx, pv, func(ctx context.Context, rc *RemoteCosigner) (SigningStatus, error) {
+		res, err := rc.GetStatus(ctx)
+		if err != nil {
+			return SigningStatus{}, err
+		}
+		return SigningStatusFromProto(res.GetSigning()), nil
+	}) {
+		statuses = append(statuses, CosignerSigningStatus{ID: r.id, SigningStatus: r.status, Error: r.err})
+	}
+
+	return newClusterSigningStatus(statuses)
+}
+
+// peerResult is the status returned by a remote peer cosigner, or its error.
+type peerResult[S any] struct {
+	id     int
+	status S
+	err    string
+}
+
+// eachRemotePeer calls f for each remote peer cosigner of pv concurrently within the gRPC timeout,
+// and returns the status of each peer, or its error.
+func eachRemotePeer[S any](
+	ctx context.Context,
+	pv *ThresholdValidator,
+	f func(ctx context.Context, rc *RemoteCosigner) (S, error),
+) []peerResult[S] {
+	ctx, cancel := context.WithTimeout(ctx, pv.GRPCTimeout())
+	defer cancel()
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var results []peerResult[S]
+	for _, peer := range pv.Peers() {
+		rc, ok := peer.(*RemoteCosigner)
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := peerResult[S]{id: rc.GetID()}
+			s, err := f(ctx, rc)
+			if err != nil {
+				result.err = err.Error()
+			} else {
+				result.status = s
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			results = append(results, result)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func newClusterSigningStatus(statuses []CosignerSigningStatus) ClusterSigningStatus {
+	slices.SortFunc(statuses, func(a, b CosignerSigningStatus) int { return a.ID - b.ID })
+	paused := true
+	for _, s := range statuses {
+		if !s.Cluster || s.Error != "" {
+			paused = false
+		}
+	}
+	return ClusterSigningStatus{Paused: paused, Cosigners: statuses}
+}
+
+// ToProto returns the signing status as protobuf.
+func (s SigningStatus) ToProto() *proto.SigningStatus {
+	res := &proto.SigningStatus{Paused: s.Paused, Cluster: s.Cluster}
+	if s.Since != nil {
+		res.Since = s.Since.UnixNano()
+	}
+	return res
+}
+
+// SigningStatusFromProto returns the signing status of its protobuf.
+func SigningStatusFromProto(s *proto.SigningStatus) SigningStatus {
+	if s == nil || !s.Paused {
+		return SigningStatus{}
+	}
+	since := time.Unix(0, s.Since)
+	return SigningStatus{Paused: true, Since: &since, Cluster: s.Cluster}
+}
@@ -0,0 +1,140 @@
+package signer
+
+import (
+	"context"
+	"crypto/rand"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/ethereum/go-ethereum/crypto/ecies"
+	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+	"github.com/strangelove-ventures/horcrux/signer/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// pauseTestKeys are the ECIES keys of the cosigners of newPauseTestValidator, by shard ID - 1.
+var pauseTestKeys = sync.OnceValue(func() []*ecies.PrivateKey {
+	keys := make([]*ecies.PrivateKey, 3)
+	for i := range keys {
+		key, err := ecies.GenerateKey(rand.Reader, secp256k1.S256(), nil)
+		if err != nil {
+			panic(err)
+		}
+		keys[i] = key
+	}
+	return keys
+})
+
+// newPauseTestValidator returns a ThresholdValidator of the cosigner with the shard ID of a cluster
+// of 3 cosigners that only pauses signing, with the peers.
+func newPauseTestValidator(t *testing.T, id int, peers ...Cosigner) (*ThresholdValidator, *PauseValidator) {
+	keys := pauseTestKeys()
+	pubs := make([]*ecies.PublicKey, len(keys))
+	for i, key := range keys {
+		pubs[i] = &key.PublicKey
+	}
+	cosigner := NewLocalCosigner(cometlog.NewNopLogger(), &RuntimeConfig{}, NewCosignerSecurityECIES(
+		CosignerECIESKey{ID: id, ECIESKey: keys[id-1], ECIESPubs: pubs},
+	), "")
+	val := &ThresholdValidator{logger: cometlog.NewNopLogger(), myCosigner: cosigner, peerCosigners: peers}
+	val.SetGRPCTimeout(time.Second)
+	pause := NewPauseValidator(&mockPrivValidator{})
+	val.SetSigningPause(pause)
+	return val, pause
+}
+
+func TestPauseValidatorCluster(t *testing.T) {
+	pause := NewPauseValidator(&mockPrivValidator{})
+	require.Equal(t, SigningStatus{}, pause.Status())
+	require.NoError(t, pause.ClusterPausedError())
+	require.NoError(t, (*PauseValidator)(nil).ClusterPausedError())
+
+	pause.Pause()
+	require.NoError(t, pause.ClusterPausedError())
+	_, _, err := pause.Sign(context.Background(), testChainID, Block{Height: 1})
+	require.ErrorContains(t, err, "signing is paused for maintenance since")
+
+	pause.PauseCluster()
+	status := pause.Status()
+	require.True(t, status.Paused)
+	require.True(t, status.Cluster)
+	require.IsType(t, &SigningPausedError{}, pause.ClusterPausedError())
+	_, _, err = pause.Sign(context.Background(), testChainID, Block{Height: 1})
+	require.ErrorContains(t, err, "signing of the cluster is paused for maintenance since")
+	require.ErrorContains(t, err, "retry once it is resumed")
+
+	pause.Resume()
+	require.Equal(t, SigningStatus{}, pause.Status())
+	require.NoError(t, pause.ClusterPausedError())
+
+	decoded := SigningStatusFromProto(status.ToProto())
+	require.True(t, decoded.Paused)
+	require.True(t, decoded.Cluster)
+	require.True(t, status.Since.Equal(*decoded.Since))
+	require.Equal(t, SigningStatus{}, SigningStatusFromProto(nil))
+}
+
+func TestSetClusterSigningPaused(t *testing.T) {
+	peerVal, _ := newKillSwitchTestValidator(t, 2)
+	peerPause := peerVal.signingPause()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcServer := grpc.NewServer()
+	proto.RegisterCosignerServer(grpcServer, NewCosignerGRPCServer(nil, peerVal, nil))
+	go func() { _ = grpcServer.Serve(ln) }()
+	defer grpcServer.Stop()
+
+	peer, err := NewRemoteCosigner(2, "tcp://"+ln.Addr().String(), TCPCosignerTransport{})
+	require.NoError(t, err)
+	// nothing listens on port 1.
+	unreachable, err := NewRemoteCosigner(3, "tcp://127.0.0.1:1", TCPCosignerTransport{})
+	require.NoError(t, err)
+
+	val, pause := newPauseTestValidator(t, 1, peer, unreachable)
+	ctx := context.Background()
+
+	status, err := val.SetClusterSigningPaused(ctx, true)
+	require.NoError(t, err)
+	require.False(t, status.Paused)
+	require.True(t, status.Failed())
+	require.Len(t, status.Cosigners, 3)
+	require.Equal(t, 1, status.Cosigners[0].ID)
+	require.True(t, status.Cosigners[0].Cluster)
+	require.Equal(t, 2, status.Cosigners[1].ID)
+	require.True(t, status.Cosigners[1].Cluster)
+	require.NotNil(t, status.Cosigners[1].Since)
+	require.Equal(t, 3, status.Cosigners[2].ID)
+	require.NotEmpty(t, status.Cosigners[2].Error)
+
+	require.True(t, pause.Status().Cluster)
+	require.True(t, peerPause.Status().Cluster)
+
+	// a cosigner of a paused cluster refuses the sign requests proxied by the other cosigners and
+	// signing with its key shard.
+	_, err = peer.Sign(ctx, CosignerSignBlockRequest{ChainID: testChainID, Block: &Block{Height: 1}})
+	require.ErrorContains(t, err, "signing of the cluster is paused")
+	_, err = peer.SetNoncesAndSign(ctx, CosignerSetNoncesAndSignRequest{
+		ChainID: testChainID,
+		Nonces:  &CosignerUUIDNonces{},
+	})
+	require.ErrorContains(t, err, "signing of the cluster is paused")
+
+	status, err = val.SetClusterSigningPaused(ctx, false)
+	require.NoError(t, err)
+	require.False(t, status.Paused)
+	require.False(t, status.Cosigners[0].Paused)
+	require.False(t, status.Cosigners[1].Paused)
+	require.False(t, peerPause.Status().Paused)
+
+	// without the unreachable cosigner, the cluster is paused.
+	val.peerCosigners = Cosigners{peer}
+	status, err = val.SetClusterSigningPaused(ctx, true)
+	require.NoError(t, err)
+	require.True(t, status.Paused)
+	require.False(t, status.Failed())
+}
@@ -27,18 +27,66 @@ const (
 	SignModeSingle    SignMode = "single"
 )
 
-// Config maps to the on-disk yaml format
-type Config struct {
-	PrivValKeyDir       *string              `yaml:"keyDir,omitempty"`
-	SignMode            SignMode             `yaml:"signMode"`
-	ThresholdModeConfig *ThresholdModeConfig `yaml:"thresholdMode,omitempty"`
-	ChainNodes          ChainNodes           `yaml:"chainNodes"`
-	DebugAddr           string               `yaml:"debugAddr"`
-	GRPCAddr            string               `yaml:"grpcAddr"`
+type LogFormat string
+
+const (
+	LogFormatPlain LogFormat = "plain"
+	LogFormatJSON  LogFormat = "json"
+)
+
+func (f LogFormat) Validate() error {
+	switch f {
+	case "", LogFormatPlain, LogFormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid log format %q, must be %s or %s", f, LogFormatPlain, LogFormatJSON)
+	}
 }
 
+// Config maps to the on-disk yaml format
+type Config struct {
+	PrivValKeyDir           *string                 `yaml:"keyDir,omitempty"`
+	SignMode                SignMode                `yaml:"signMode"`
+	ThresholdModeConfig     *ThresholdModeConfig    `yaml:"thresholdMode,omitempty"`
+	ChainNodes              ChainNodes              `yaml:"chainNodes"`
+	DebugAddr               string                  `yaml:"debugAddr"`
+	DebugServer             *DebugServerConfig      `yaml:"debugServer,omitempty"`
+	Admin                   *AdminAPIConfig         `yaml:"admin,omitempty"`
+	GRPCAddr                string                  `yaml:"grpcAddr"`
+	GRPCTLS                 *GRPCTLSConfig          `yaml:"grpcTLS,omitempty"`
+	SignBytesCodecs         map[string]string       `yaml:"signBytesCodecs,omitempty"`
+	FeatureFlags            map[string]FeatureFlag  `yaml:"featureFlags,omitempty"`
+	SignState               *SignStateStoreConfig   `yaml:"signState,omitempty"`
+	Backup                  *StateBackupConfig      `yaml:"backup,omitempty"`
+	ChainRPC                ChainRPCConfigs         `yaml:"chainRPC,omitempty"`
+	Chains                  ChainConfigs            `yaml:"chains,omitempty"`
+	AllowedChainIDs         ChainIDAllowlist        `yaml:"allowedChainIDs,omitempty"`
+	SignRateLimit           *SignRateLimitConfig    `yaml:"signRateLimit,omitempty"`
+	SignScheduler           *SignSchedulerConfig    `yaml:"signScheduler,omitempty"`
+	MessageSigning          *MessageSigningConfig   `yaml:"messageSigning,omitempty"`
+	SignLock                *SignLockConfig         `yaml:"signLock,omitempty"`
+	SignatureHistory        *SignatureHistoryConfig `yaml:"signatureHistory,omitempty"`
+	SignStateFileEncryption *StateEncryptionConfig  `yaml:"signStateFileEncryption,omitempty"`
+	SignDecisionLog         *SignDecisionLogConfig  `yaml:"signDecisionLog,omitempty"`
+	LogFormat               LogFormat               `yaml:"logFormat,omitempty"`
+	LogLevel                LogLevel                `yaml:"logLevel,omitempty"`
+	LogFile                 *LogFileConfig          `yaml:"logFile,omitempty"`
+	Syslog                  *SyslogConfig           `yaml:"syslog,omitempty"`
+	Alerts                  *AlertsConfig           `yaml:"alerts,omitempty"`
+	AuditLog                *AuditLogConfig         `yaml:"auditLog,omitempty"`
+	Statsd                  *StatsdConfig           `yaml:"statsd,omitempty"`
+	ErrorReporting          *ErrorReportingConfig   `yaml:"errorReporting,omitempty"`
+	Events                  *EventsConfig           `yaml:"events,omitempty"`
+	Profiling               *ProfilingConfig        `yaml:"profiling,omitempty"`
+
+	// StateEncryption is the former name of SignStateFileEncryption, which is refused rather than
+	// ignored so that the sign state files are not written in plaintext.
+	StateEncryption *StateEncryptionConfig `yaml:"stateEncryption,omitempty"`
+}
+
+// Nodes returns the priv validator addresses of the chain nodes of the config and of its chains.
 func (c *Config) Nodes() (out []string) {
-	for _, n := range c.ChainNodes {
+	for _, n := range c.AllChainNodes() {
 		out = append(out, n.PrivValAddr)
 	}
 	return out
@@ -53,7 +101,107 @@ func (c *Config) MustMarshalYaml() []byte {
 }
 
 func (c *Config) ValidateSingleSignerConfig() error {
-	return c.ChainNodes.Validate()
+	if err := c.ChainNodes.Validate(); err != nil {
+		return err
+	}
+	if err := c.SignState.Validate(); err != nil {
+		return err
+	}
+	if err := c.validateSignStateFileEncryption(); err != nil {
+		return err
+	}
+	if err := c.Backup.Validate(); err != nil {
+		return err
+	}
+	if err := c.ChainRPC.Validate(); err != nil {
+		return err
+	}
+	if err := c.Chains.Validate(); err != nil {
+		return err
+	}
+	if err := c.AllowedChainIDs.Validate(c.Chains); err != nil {
+		return err
+	}
+	if err := c.SignRateLimit.Validate(); err != nil {
+		return err
+	}
+	if err := c.SignScheduler.Validate(); err != nil {
+		return err
+	}
+	if err := c.MessageSigning.Validate(); err != nil {
+		return err
+	}
+	if err := c.SignLock.Validate(); err != nil {
+		return err
+	}
+	if err := c.SignatureHistory.Validate(); err != nil {
+		return err
+	}
+	if err := c.SignDecisionLog.Validate(); err != nil {
+		return err
+	}
+	if err := c.LogFormat.Validate(); err != nil {
+		return err
+	}
+	if err := c.LogLevel.Validate(); err != nil {
+		return err
+	}
+	if err := c.LogFile.Validate(); err != nil {
+		return err
+	}
+	if err := c.Syslog.Validate(); err != nil {
+		return err
+	}
+	if err := c.Alerts.Validate(); err != nil {
+		return err
+	}
+	if err := c.Statsd.Validate(); err != nil {
+		return err
+	}
+	if err := c.ErrorReporting.Validate(); err != nil {
+		return err
+	}
+	if err := c.DebugServer.Validate(); err != nil {
+		return err
+	}
+	if err := c.Admin.Validate(); err != nil {
+		return err
+	}
+	if c.Admin != nil && c.Admin.Approvals != nil && c.AuditLog == nil {
+		return fmt.Errorf("admin approvals require auditLog, which records them")
+	}
+	if c.GRPCTLS != nil && c.GRPCAddr == "" {
+		return fmt.Errorf("grpcTLS requires grpcAddr")
+	}
+	if err := c.GRPCTLS.Validate(); err != nil {
+		return err
+	}
+	if err := c.Events.Validate(); err != nil {
+		return err
+	}
+	if err := c.Profiling.Validate(); err != nil {
+		return err
+	}
+	for name, flag := range c.FeatureFlags {
+		if err := flag.Validate(); err != nil {
+			return fmt.Errorf("invalid feature flag %s: %w", name, err)
+		}
+	}
+	_, err := c.ChainSignBytesCodecs()
+	return err
+}
+
+// ChainSignBytesCodecs resolves the configured sign bytes codec name for each chain ID.
+func (c *Config) ChainSignBytesCodecs() (ChainSignBytesCodecs, error) {
+	codecs := make(ChainSignBytesCodecs, len(c.SignBytesCodecs))
+	for chainID, name := range c.SignBytesCodecs {
+		codec, err := GetSignBytesCodec(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sign bytes codec for chain %s: %w", chainID, err)
+		}
+		codecs[chainID] = codec
+	}
+	return codecs, nil
 }
 
 func (c *Config) ValidateThresholdModeConfig() error {
@@ -78,14 +226,48 @@ func (c *Config) ValidateThresholdModeConfig() error {
 			numShards, c.ThresholdModeConfig.Threshold)
 	}
 
-	if _, err := time.ParseDuration(c.ThresholdModeConfig.RaftTimeout); err != nil {
-		return fmt.Errorf("invalid raftTimeout: %w", err)
+	if _, err := c.ThresholdModeConfig.RaftTimings(); err != nil {
+		return err
 	}
 
 	if _, err := time.ParseDuration(c.ThresholdModeConfig.GRPCTimeout); err != nil {
 		return fmt.Errorf("invalid grpcTimeout: %w", err)
 	}
 
+	if _, err := c.ThresholdModeConfig.CosignerTransport(); err != nil {
+		return err
+	}
+
+	if err := c.ThresholdModeConfig.Watermark.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.ThresholdModeConfig.Quarantine.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.ThresholdModeConfig.NonceCache.Validate(); err != nil {
+		return err
+	}
+	ncp, _ := c.ThresholdModeConfig.NonceCache.params()
+	for id := range c.Chains {
+		if d := c.Chains.nonceExpiration(id); d > ncp.expiration {
+			return fmt.Errorf("invalid config of chain %s: nonceExpiration cannot be above the nonce cache expiration %s",
+				id, ncp.expiration)
+		}
+	}
+
+	switch c.ThresholdModeConfig.VersionSkewPolicy {
+	case "", VersionSkewPolicyWarn, VersionSkewPolicyRefuse:
+	default:
+		return fmt.Errorf("invalid versionSkewPolicy %q, must be %s or %s",
+			c.ThresholdModeConfig.VersionSkewPolicy, VersionSkewPolicyWarn, VersionSkewPolicyRefuse)
+	}
+
+	if err := c.ThresholdModeConfig.LeaderElection.Validate(); err != nil {
+		return err
+	}
+
 	if err := c.ThresholdModeConfig.Cosigners.Validate(); err != nil {
 		return err
 	}
@@ -97,7 +279,6 @@ type RuntimeConfig struct {
 	HomeDir    string
 	ConfigFile string
 	StateDir   string
-	PidFile    string
 	Config     Config
 }
 
@@ -129,6 +310,20 @@ func (c RuntimeConfig) CosignerSecurityRSA() (*CosignerSecurityRSA, error) {
 	return NewCosignerSecurityRSA(key), nil
 }
 
+// CosignerSecurity returns the security of the ECIES key of the cosigner, or of the RSA key if
+// there is no ECIES key.
+func (c RuntimeConfig) CosignerSecurity() (CosignerSecurity, error) {
+	eciesSecurity, eciesErr := c.CosignerSecurityECIES()
+	if eciesErr == nil {
+		return eciesSecurity, nil
+	}
+	rsaSecurity, rsaErr := c.CosignerSecurityRSA()
+	if rsaErr != nil {
+		return nil, fmt.Errorf("failed to initialize cosigner ECIES / RSA security : %w / %w", eciesErr, rsaErr)
+	}
+	return rsaSecurity, nil
+}
+
 func (c RuntimeConfig) cachedKeyDirectory() string {
 	if c.Config.PrivValKeyDir != nil {
 		return *c.Config.PrivValKeyDir
@@ -168,6 +363,17 @@ func (c RuntimeConfig) KeyFilePathCosignerECIES() string {
 	return filepath.Join(keyDir, "ecies_keys.json")
 }
 
+// SignBytesCodec returns the SignBytesCodec configured for the chain ID.
+func (c RuntimeConfig) SignBytesCodec(chainID string) (SignBytesCodec, error) {
+	// the sign bytes are the same for every validator of the chain.
+	_, chainID = SplitValidatorChainID(chainID)
+	name, ok := c.Config.SignBytesCodecs[chainID]
+	if !ok {
+		return CometSignBytesCodec{}, nil
+	}
+	return GetSignBytesCodec(name)
+}
+
 func (c RuntimeConfig) PrivValStateFile(chainID string) string {
 	return filepath.Join(c.StateDir, fmt.Sprintf("%s_priv_validator_state.json", chainID))
 }
@@ -176,8 +382,17 @@ func (c RuntimeConfig) CosignerStateFile(chainID string) string {
 	return filepath.Join(c.StateDir, fmt.Sprintf("%s_share_sign_state.json", chainID))
 }
 
+// WriteConfigFile writes the config to the config file, in the format of its extension.
 func (c RuntimeConfig) WriteConfigFile() error {
-	return os.WriteFile(c.ConfigFile, c.Config.MustMarshalYaml(), 0600)
+	format, err := ConfigFormatOf(c.ConfigFile)
+	if err != nil {
+		return err
+	}
+	bz, err := c.Config.MarshalFormat(format)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.ConfigFile, bz, 0600)
 }
 
 func fileExists(file string) error {
@@ -217,10 +432,20 @@ func (c RuntimeConfig) KeyFileExistsCosignerECIES() (string, error) {
 
 // ThresholdModeConfig is the on disk config format for threshold sign mode.
 type ThresholdModeConfig struct {
-	Threshold   int             `yaml:"threshold"`
-	Cosigners   CosignersConfig `yaml:"cosigners"`
-	GRPCTimeout string          `yaml:"grpcTimeout"`
-	RaftTimeout string          `yaml:"raftTimeout"`
+	Threshold   int                       `yaml:"threshold"`
+	Cosigners   CosignersConfig           `yaml:"cosigners"`
+	GRPCTimeout string                    `yaml:"grpcTimeout"`
+	RaftTimeout string                    `yaml:"raftTimeout"`
+	Transport   *CosignerTransportConfig  `yaml:"transport,omitempty"`
+	Watermark   *WatermarkStoreConfig     `yaml:"watermark,omitempty"`
+	Quarantine  *CosignerQuarantineConfig `yaml:"quarantine,omitempty"`
+	NonceCache  *NonceCacheConfig         `yaml:"nonceCache,omitempty"`
+
+	// VersionSkewPolicy is what the leader does with the peer cosigners on an incompatible software
+	// version: warn, by default, or refuse to sign with them.
+	VersionSkewPolicy string `yaml:"versionSkewPolicy,omitempty"`
+
+	LeaderElection *LeaderElectionConfig `yaml:"leaderElection,omitempty"`
 }
 
 func (cfg *ThresholdModeConfig) LeaderElectMultiAddress() (string, error) {
@@ -235,6 +460,14 @@ func (cfg *ThresholdModeConfig) LeaderElectMultiAddress() (string, error) {
 type CosignerConfig struct {
 	ShardID int    `yaml:"shardID"`
 	P2PAddr string `yaml:"p2pAddr"`
+
+	// Priority of the cosigner to be the leader, the leader hands the leadership over to
+	// a reachable cosigner with a higher priority.
+	Priority int `yaml:"priority,omitempty"`
+
+	// AdminAddr is the address of the admin API of the cosigner, e.g. 10.0.0.1:6100, which the
+	// rolling restart of the cluster restarts it through.
+	AdminAddr string `yaml:"adminAddr,omitempty"`
 }
 
 type CosignersConfig []CosignerConfig
@@ -254,18 +487,18 @@ func (cosigners CosignersConfig) Validate() error {
 				cosigner.ShardID, shards)
 		}
 
-		url, err := url.Parse(cosigner.P2PAddr)
-		if err != nil {
-			return fmt.Errorf("failed to parse cosigner (shard ID: %d) p2p address: %w", cosigner.ShardID, err)
+		if err := cosigner.validateP2PAddr(); err != nil {
+			return err
 		}
 
-		host, _, err := net.SplitHostPort(url.Host)
-		if err != nil {
-			return fmt.Errorf("failed to parse cosigner (shard ID: %d) host port: %w", cosigner.ShardID, err)
+		if cosigner.Priority < 0 {
+			return fmt.Errorf("cosigner (shard ID: %d) priority cannot be negative", cosigner.ShardID)
 		}
 
-		if host == "0.0.0.0" {
-			return fmt.Errorf("host cannot be 0.0.0.0, must be reachable from other cosigners")
+		if cosigner.AdminAddr != "" {
+			if _, _, err := net.SplitHostPort(cosigner.AdminAddr); err != nil {
+				return fmt.Errorf("invalid cosigner (shard ID: %d) admin address: %w", cosigner.ShardID, err)
+			}
 		}
 	}
 
@@ -278,6 +511,23 @@ func (cosigners CosignersConfig) Validate() error {
 	return nil
 }
 
+func (cosigner CosignerConfig) validateP2PAddr() error {
+	url, err := url.Parse(cosigner.P2PAddr)
+	if err != nil {
+		return fmt.Errorf("failed to parse cosigner (shard ID: %d) p2p address: %w", cosigner.ShardID, err)
+	}
+
+	host, _, err := net.SplitHostPort(url.Host)
+	if err != nil {
+		return fmt.Errorf("failed to parse cosigner (shard ID: %d) host port: %w", cosigner.ShardID, err)
+	}
+
+	if host == "0.0.0.0" {
+		return fmt.Errorf("host cannot be 0.0.0.0, must be reachable from other cosigners")
+	}
+	return nil
+}
+
 func duplicateCosigners(cosigners []CosignerConfig) (duplicates map[int][]string) {
 	idAddrs := make(map[int][]string)
 	for _, cosigner := range cosigners {
@@ -314,9 +564,19 @@ func CosignersFromFlag(cosigners []string) (out []CosignerConfig, err error) {
 
 type ChainNode struct {
 	PrivValAddr string `json:"privValAddr" yaml:"privValAddr"`
+
+	// Validator is the validator the chain node signs with, for clusters that host several
+	// validators. The default validator has an empty name.
+	Validator string `json:"validator,omitempty" yaml:"validator,omitempty"`
+
+	// ChainID restricts the chain node to sign the chain. It is set for the chain nodes of the chains.
+	ChainID string `json:"chainID,omitempty" yaml:"-"`
 }
 
 func (cn ChainNode) Validate() error {
+	if err := ValidateValidatorName(cn.Validator); err != nil {
+		return err
+	}
 	_, err := url.Parse(cn.PrivValAddr)
 	return err
 }
@@ -0,0 +1,275 @@
+package signer
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	cometnet "github.com/cometbft/cometbft/libs/net"
+)
+
+const (
+	// configCheckCertExpiryWarning is how long before its expiry a certificate is reported.
+	configCheckCertExpiryWarning = 30 * 24 * time.Hour
+
+	// configCheckMaxClockSkew is the clock skew to a peer cosigner above which the check fails.
+	configCheckMaxClockSkew = time.Second
+
+	configCheckProbeTimeout = 5 * time.Second
+)
+
+// ConfigCheckStatus is the outcome of a ConfigCheck.
+type ConfigCheckStatus string
+
+const (
+	ConfigCheckPass ConfigCheckStatus = "pass"
+	ConfigCheckWarn ConfigCheckStatus = "warn"
+	ConfigCheckFail ConfigCheckStatus = "fail"
+)
+
+// ConfigCheck is the outcome of a check of the config, the key files or a connection.
+type ConfigCheck struct {
+	Name   string            `json:"name"`
+	Status ConfigCheckStatus `json:"status"`
+	Detail string            `json:"detail,omitempty"`
+}
+
+func configCheck(name string, err error, detail string) ConfigCheck {
+	if err != nil {
+		return ConfigCheck{Name: name, Status: ConfigCheckFail, Detail: err.Error()}
+	}
+	return ConfigCheck{Name: name, Status: ConfigCheckPass, Detail: detail}
+}
+
+// CheckConfig validates the config, the key files and the certificates for the sign mode. With
+// probe, it also dials every chain node and every peer cosigner, and measures the clock skew to
+// the peer cosigners. The checks are independent, so that every problem is reported at once.
+func (c RuntimeConfig) CheckConfig(ctx context.Context, probe bool) []ConfigCheck {
+	var err error
+	switch c.Config.SignMode {
+	case SignModeThreshold:
+		err = c.Config.ValidateThresholdModeConfig()
+	case SignModeSingle:
+		err = c.Config.ValidateSingleSignerConfig()
+	default:
+		err = fmt.Errorf("unexpected sign mode: %s", c.Config.SignMode)
+	}
+	checks := []ConfigCheck{configCheck("config", err, string(c.Config.SignMode)+" mode")}
+	if err != nil {
+		// the other checks depend on a valid config.
+		return checks
+	}
+
+	if c.Config.SignMode == SignModeThreshold {
+		checks = append(checks, c.checkCosignerKeys()...)
+	} else {
+		checks = append(checks, c.checkSingleSignerKeys())
+	}
+	checks = append(checks, c.checkCertificates(time.Now())...)
+
+	if probe {
+		checks = append(checks, c.probeChainNodes(ctx)...)
+		if c.Config.SignMode == SignModeThreshold {
+			checks = append(checks, c.probeCosigners(ctx)...)
+		}
+	}
+	return checks
+}
+
+// keyFileChainIDs returns the chain IDs of the key files with the suffix in the key directory.
+func (c RuntimeConfig) keyFileChainIDs(suffix string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(c.keyDirectory(), "*"+suffix))
+	if err != nil {
+		return nil, err
+	}
+	chainIDs := make([]string, len(files))
+	for i, file := range files {
+		chainIDs[i] = strings.TrimSuffix(filepath.Base(file), suffix)
+	}
+	return chainIDs, nil
+}
+
+func (c RuntimeConfig) checkSingleSignerKeys() ConfigCheck {
+	chainIDs, err := c.keyFileChainIDs("_priv_validator_key.json")
+	if err == nil && len(chainIDs) == 0 {
+		err = fmt.Errorf("no priv validator key file found in %s", c.keyDirectory())
+	}
+	return configCheck("priv validator keys", err, strings.Join(chainIDs, ", "))
+}
+
+// checkCosignerKeys checks that the cosigner key of the cosigner is in the config, and that every
+// key shard is of the same cosigner.
+func (c RuntimeConfig) checkCosignerKeys() []ConfigCheck {
+	security, err := c.CosignerSecurity()
+	if err == nil && !c.Config.ThresholdModeConfig.Cosigners.hasShard(security.GetID()) {
+		err = fmt.Errorf("cosigner %d is not in the cosigners of the config", security.GetID())
+	}
+	if err != nil {
+		return []ConfigCheck{configCheck("cosigner key", err, "")}
+	}
+	checks := []ConfigCheck{configCheck("cosigner key", nil, fmt.Sprintf("cosigner %d", security.GetID()))}
+
+	chainIDs, err := c.keyFileChainIDs(keyShardSuffix)
+	if err == nil && len(chainIDs) == 0 {
+		err = fmt.Errorf("no key shard found in %s", c.keyDirectory())
+	}
+	if err != nil {
+		return append(checks, configCheck("key shards", err, ""))
+	}
+	for _, chainID := range chainIDs {
+		key, err := LoadCosignerEd25519Key(c.KeyFilePathCosigner(chainID))
+		if err == nil && key.ID != security.GetID() {
+			err = fmt.Errorf("key shard is of cosigner %d, not of cosigner %d", key.ID, security.GetID())
+		}
+		checks = append(checks, configCheck("key shard "+chainID, err, fmt.Sprintf("cosigner %d", key.ID)))
+	}
+	return checks
+}
+
+func (cosigners CosignersConfig) hasShard(shardID int) bool {
+	for _, c := range cosigners {
+		if c.ShardID == shardID {
+			return true
+		}
+	}
+	return false
+}
+
+// checkCertificates checks that the configured TLS certificates load with their keys and are valid at now.
+func (c RuntimeConfig) checkCertificates(now time.Time) []ConfigCheck {
+	type certificate struct{ name, certFile, keyFile string }
+	var certs []certificate
+	if cfg := c.Config.DebugServer; cfg != nil && cfg.CertFile != "" {
+		certs = append(certs, certificate{"debug server certificate", cfg.CertFile, cfg.KeyFile})
+	}
+	if cfg := c.Config.Admin; cfg != nil && cfg.CertFile != "" {
+		certs = append(certs, certificate{"admin API certificate", cfg.CertFile, cfg.KeyFile})
+	}
+	if tc := c.Config.ThresholdModeConfig; tc != nil && tc.Transport != nil && tc.Transport.CertFile != "" {
+		certs = append(certs, certificate{"transport certificate", tc.Transport.CertFile, tc.Transport.KeyFile})
+	}
+
+	checks := make([]ConfigCheck, 0, len(certs))
+	for _, cert := range certs {
+		checks = append(checks, checkCertificate(cert.name, cert.certFile, cert.keyFile, now))
+	}
+	return checks
+}
+
+func checkCertificate(name, certFile, keyFile string, now time.Time) ConfigCheck {
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return configCheck(name, err, "")
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return configCheck(name, err, "")
+	}
+	switch {
+	case now.Before(leaf.NotBefore):
+		return configCheck(name, fmt.Errorf("not valid before %s", leaf.NotBefore.UTC().Format(time.RFC3339)), "")
+	case now.After(leaf.NotAfter):
+		return configCheck(name, fmt.Errorf("expired at %s", leaf.NotAfter.UTC().Format(time.RFC3339)), "")
+	}
+	detail := "expires at " + leaf.NotAfter.UTC().Format(time.RFC3339)
+	if leaf.NotAfter.Sub(now) < configCheckCertExpiryWarning {
+		return ConfigCheck{Name: name, Status: ConfigCheckWarn, Detail: detail}
+	}
+	return configCheck(name, nil, detail)
+}
+
+// probeChainNodes dials every chain node, which accepts the connection of the signer.
+func (c RuntimeConfig) probeChainNodes(ctx context.Context) []ConfigCheck {
+	nodes := c.Config.Nodes()
+	checks := make([]ConfigCheck, len(nodes))
+	var wg sync.WaitGroup
+	for i, address := range nodes {
+		wg.Add(1)
+		go func(i int, address string) {
+			defer wg.Done()
+			dialer := net.Dialer{Timeout: configCheckProbeTimeout}
+			proto, addr := cometnet.ProtocolAndAddress(address)
+			start := time.Now()
+			conn, err := dialer.DialContext(ctx, proto, addr)
+			if err == nil {
+				_ = conn.Close()
+			}
+			detail := fmt.Sprintf("connected in %s", time.Since(start).Round(time.Millisecond))
+			checks[i] = configCheck("chain node "+address, err, detail)
+		}(i, address)
+	}
+	wg.Wait()
+	return checks
+}
+
+// probeCosigners queries the status of every peer cosigner, and checks that it is the cosigner of
+// its shard ID and that the clock skew to it is below configCheckMaxClockSkew.
+func (c RuntimeConfig) probeCosigners(ctx context.Context) []ConfigCheck {
+	tc := c.Config.ThresholdModeConfig
+	security, err := c.CosignerSecurity()
+	if err != nil {
+		// reported by checkCosignerKeys.
+		return nil
+	}
+	transport, err := tc.CosignerTransport()
+	if err != nil {
+		return []ConfigCheck{configCheck("cosigner transport", err, "")}
+	}
+
+	var peers CosignersConfig
+	for _, cosigner := range tc.Cosigners {
+		if cosigner.ShardID != security.GetID() {
+			peers = append(peers, cosigner)
+		}
+	}
+	checks := make([]ConfigCheck, len(peers))
+	var wg sync.WaitGroup
+	for i, peer := range peers {
+		wg.Add(1)
+		go func(i int, peer CosignerConfig) {
+			defer wg.Done()
+			checks[i] = probeCosigner(ctx, peer, transport)
+		}(i, peer)
+	}
+	wg.Wait()
+	return checks
+}
+
+func probeCosigner(ctx context.Context, peer CosignerConfig, transport CosignerTransport) ConfigCheck {
+	name := fmt.Sprintf("cosigner %d %s", peer.ShardID, peer.P2PAddr)
+	rc, err := NewRemoteCosigner(peer.ShardID, peer.P2PAddr, transport)
+	if err != nil {
+		return configCheck(name, err, "")
+	}
+	ctx, cancel := context.WithTimeout(ctx, configCheckProbeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	status, err := rc.GetStatus(ctx)
+	rtt := time.Since(start)
+	if err != nil {
+		return configCheck(name, err, "")
+	}
+	if int(status.Id) != peer.ShardID {
+		return configCheck(name, fmt.Errorf("answered as cosigner %d", status.Id), "")
+	}
+
+	detail := fmt.Sprintf("version %s, protocol %d, rtt %s", status.SoftwareVersion, status.ProtocolVersion,
+		rtt.Round(time.Millisecond))
+	if status.Timestamp == 0 {
+		return ConfigCheck{Name: name, Status: ConfigCheckWarn, Detail: detail + ", clock skew unknown"}
+	}
+	// the cosigner answered halfway through the round trip.
+	skew := time.Unix(0, status.Timestamp).Sub(start.Add(rtt / 2))
+	detail += fmt.Sprintf(", clock skew %s", skew.Round(time.Millisecond))
+	if skew.Abs() > configCheckMaxClockSkew {
+		return configCheck(name, fmt.Errorf("%s, above %s", detail, configCheckMaxClockSkew), "")
+	}
+	return configCheck(name, nil, detail)
+}
@@ -0,0 +1,121 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	"github.com/cometbft/cometbft/privval"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckConfig(t *testing.T) {
+	dir := t.TempDir()
+	config := RuntimeConfig{
+		HomeDir: dir,
+		Config: Config{
+			SignMode: SignModeThreshold,
+			ThresholdModeConfig: &ThresholdModeConfig{
+				Threshold: 2,
+				Cosigners: CosignersConfig{
+					{ShardID: 1, P2PAddr: "tcp://127.0.0.1:2222"},
+					{ShardID: 2, P2PAddr: "tcp://127.0.0.1:2223"},
+					{ShardID: 3, P2PAddr: "tcp://127.0.0.1:2224"},
+				},
+				GRPCTimeout: "1000ms",
+				RaftTimeout: "1000ms",
+			},
+		},
+	}
+
+	// the other checks are skipped with an invalid config.
+	invalid := config
+	invalid.Config.SignMode = "multi"
+	checks := invalid.CheckConfig(context.Background(), false)
+	require.Len(t, checks, 1)
+	require.Equal(t, ConfigCheckFail, checks[0].Status)
+
+	checks = config.CheckConfig(context.Background(), false)
+	require.Equal(t, []ConfigCheckStatus{ConfigCheckPass, ConfigCheckFail}, configCheckStatuses(checks))
+
+	eciesKeys, err := CreateCosignerECIESShards(3)
+	require.NoError(t, err)
+	require.NoError(t, WriteCosignerECIESShardFile(eciesKeys[0], config.KeyFilePathCosignerECIES()))
+	checks = config.CheckConfig(context.Background(), false)
+	require.Equal(t, []ConfigCheckStatus{ConfigCheckPass, ConfigCheckPass, ConfigCheckFail}, configCheckStatuses(checks))
+	require.Equal(t, "key shards", checks[2].Name)
+
+	shards := CreateCosignerEd25519Shards(privval.FilePVKey{
+		PubKey:  cometcryptoed25519.GenPrivKey().PubKey(),
+		PrivKey: cometcryptoed25519.GenPrivKey(),
+	}, 2, 3)
+	require.NoError(t, WriteCosignerEd25519ShardFile(shards[0], config.KeyFilePathCosigner("horcrux-1")))
+	require.NoError(t, WriteCosignerEd25519ShardFile(shards[1], config.KeyFilePathCosigner("horcrux-2")))
+	checks = config.CheckConfig(context.Background(), false)
+	require.Equal(t, []ConfigCheck{
+		{Name: "config", Status: ConfigCheckPass, Detail: "threshold mode"},
+		{Name: "cosigner key", Status: ConfigCheckPass, Detail: "cosigner 1"},
+		{Name: "key shard horcrux-1", Status: ConfigCheckPass, Detail: "cosigner 1"},
+		{Name: "key shard horcrux-2", Status: ConfigCheckFail, Detail: "key shard is of cosigner 2, not of cosigner 1"},
+	}, checks)
+
+	// the probes dial the chain nodes and the peer cosigners.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	config.Config.ChainNodes = ChainNodes{{PrivValAddr: "tcp://" + ln.Addr().String()}}
+	require.NoError(t, os.Remove(config.KeyFilePathCosigner("horcrux-2")))
+
+	checks = config.CheckConfig(context.Background(), true)
+	require.Len(t, checks, 6)
+	require.Equal(t, ConfigCheckPass, checks[3].Status, checks[3].Detail)
+	require.Equal(t, ConfigCheckFail, checks[4].Status)
+	require.Equal(t, "cosigner 2 tcp://127.0.0.1:2223", checks[4].Name)
+}
+
+func configCheckStatuses(checks []ConfigCheck) []ConfigCheckStatus {
+	statuses := make([]ConfigCheckStatus, len(checks))
+	for i, c := range checks {
+		statuses[i] = c.Status
+	}
+	return statuses
+}
+
+func TestCheckCertificate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "horcrux"},
+		NotBefore:    time.Now().Add(-60 * 24 * time.Hour),
+		NotAfter:     time.Now().Add(7 * 24 * time.Hour),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	now := time.Now()
+	require.Equal(t, ConfigCheckPass, checkCertificate("cert", certFile, keyFile, now.Add(-30*24*time.Hour)).Status)
+	require.Equal(t, ConfigCheckWarn, checkCertificate("cert", certFile, keyFile, now).Status)
+	require.Equal(t, ConfigCheckFail, checkCertificate("cert", certFile, keyFile, now.Add(8*24*time.Hour)).Status)
+	require.Equal(t, ConfigCheckFail, checkCertificate("cert", certFile, keyFile, now.Add(-61*24*time.Hour)).Status)
+	require.Equal(t, ConfigCheckFail, checkCertificate("cert", keyFile, certFile, now).Status)
+}
@@ -0,0 +1,112 @@
+package signer
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// configFileReferencePrefix is the prefix of a config value that is replaced by the contents of a file.
+const configFileReferencePrefix = "file://"
+
+// configEnvReference matches ${NAME}, and $${NAME} which escapes it.
+var configEnvReference = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ExpandConfigReferences resolves the references in the values of the YAML config bz, so that
+// secrets and addresses can be injected by the orchestration:
+//
+//   - ${NAME} is replaced by the environment variable NAME, which must be set. $${NAME} is
+//     replaced by a literal ${NAME}.
+//   - A value that is file:///path after the environment variables are replaced is replaced by
+//     the contents of the file, without trailing newlines.
+//
+// A substituted value that is not quoted is typed as if written in the config, e.g. a number.
+// Keys and comments are not expanded.
+func ExpandConfigReferences(bz []byte) ([]byte, error) {
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal(bz, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	changed, err := expandConfigNode(&doc)
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		return bz, nil
+	}
+	return yamlv3.Marshal(&doc)
+}
+
+// expandConfigNode resolves the references in the scalar values under the node, and returns
+// true if any was resolved.
+func expandConfigNode(node *yamlv3.Node) (bool, error) {
+	switch node.Kind {
+	case yamlv3.ScalarNode:
+		value, err := expandConfigValue(node.Value)
+		if err != nil || value == node.Value {
+			return false, err
+		}
+		node.Value = value
+		if node.Style&(yamlv3.DoubleQuotedStyle|yamlv3.SingleQuotedStyle) == 0 {
+			// resolve the type of the substituted value.
+			node.Tag = ""
+			node.Style = 0
+		}
+		return true, nil
+	case yamlv3.MappingNode:
+		changed := false
+		// the keys are at even and the values at odd indexes.
+		for i := 1; i < len(node.Content); i += 2 {
+			c, err := expandConfigNode(node.Content[i])
+			if err != nil {
+				return false, fmt.Errorf("%s: %w", node.Content[i-1].Value, err)
+			}
+			changed = changed || c
+		}
+		return changed, nil
+	case yamlv3.DocumentNode, yamlv3.SequenceNode:
+		changed := false
+		for _, n := range node.Content {
+			c, err := expandConfigNode(n)
+			if err != nil {
+				return false, err
+			}
+			changed = changed || c
+		}
+		return changed, nil
+	default:
+		return false, nil
+	}
+}
+
+// expandConfigValue resolves the references in a config value.
+func expandConfigValue(value string) (string, error) {
+	var err error
+	value = configEnvReference.ReplaceAllStringFunc(value, func(ref string) string {
+		if strings.HasPrefix(ref, "$$") {
+			return ref[1:]
+		}
+		name := ref[2 : len(ref)-1]
+		env, ok := os.LookupEnv(name)
+		if !ok && err == nil {
+			err = fmt.Errorf("environment variable %s is not set", name)
+		}
+		return env
+	})
+	if err != nil {
+		return "", err
+	}
+
+	path, ok := strings.CutPrefix(value, configFileReferencePrefix)
+	if !ok {
+		return value, nil
+	}
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file reference: %w", err)
+	}
+	return strings.TrimRight(string(bz), "\r\n"), nil
+}
@@ -0,0 +1,59 @@
+package signer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestExpandConfigReferences(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("s3cret\n"), 0600))
+
+	t.Setenv("HORCRUX_TEST_THRESHOLD", "2")
+	t.Setenv("HORCRUX_TEST_SENTRY", "sentry-1")
+	t.Setenv("HORCRUX_TEST_TOKEN_FILE", tokenFile)
+	t.Setenv("HORCRUX_TEST_USERNAME", "0x10")
+
+	bz, err := ExpandConfigReferences([]byte(`
+signMode: threshold
+thresholdMode:
+  threshold: ${HORCRUX_TEST_THRESHOLD}
+  cosigners:
+  - shardID: 1
+    p2pAddr: tcp://cosigner-1:2222
+  grpcTimeout: 1000ms
+  raftTimeout: 1000ms
+chainNodes:
+- privValAddr: tcp://${HORCRUX_TEST_SENTRY}:1234
+debugServer:
+  username: "${HORCRUX_TEST_USERNAME}"
+  passwordFile: file://${HORCRUX_TEST_TOKEN_FILE}
+  unauthenticatedPaths:
+  - /$${HORCRUX_TEST_SENTRY}
+`))
+	require.NoError(t, err)
+
+	var cfg Config
+	require.NoError(t, yaml.Unmarshal(bz, &cfg))
+	require.Equal(t, 2, cfg.ThresholdModeConfig.Threshold)
+	require.Equal(t, "tcp://sentry-1:1234", cfg.ChainNodes[0].PrivValAddr)
+	require.Equal(t, "0x10", cfg.DebugServer.Username)
+	require.Equal(t, "s3cret", cfg.DebugServer.PasswordFile)
+	require.Equal(t, []string{"/${HORCRUX_TEST_SENTRY}"}, cfg.DebugServer.UnauthenticatedPaths)
+
+	// a config without references is returned as is.
+	unchanged := []byte("signMode: single # comment\n")
+	bz, err = ExpandConfigReferences(unchanged)
+	require.NoError(t, err)
+	require.Equal(t, unchanged, bz)
+
+	_, err = ExpandConfigReferences([]byte("debugAddr: ${HORCRUX_TEST_UNSET}\n"))
+	require.ErrorContains(t, err, "debugAddr: environment variable HORCRUX_TEST_UNSET is not set")
+
+	_, err = ExpandConfigReferences([]byte("debugAddr: file:///nonexistent/horcrux\n"))
+	require.ErrorContains(t, err, "failed to read file reference")
+}
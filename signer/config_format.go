@@ -0,0 +1,150 @@
+package signer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// ConfigFormat is the format of a config file, detected by the extension of the file.
+type ConfigFormat string
+
+const (
+	ConfigFormatYAML ConfigFormat = "yaml"
+	ConfigFormatJSON ConfigFormat = "json"
+	ConfigFormatTOML ConfigFormat = "toml"
+)
+
+// configFileNames are the names of the config file looked up in the home directory.
+var configFileNames = []string{"config.yaml", "config.yml", "config.json", "config.toml"}
+
+// ConfigFormatOf returns the format of the config file by its extension.
+func ConfigFormatOf(file string) (ConfigFormat, error) {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		return ConfigFormatYAML, nil
+	case ".json":
+		return ConfigFormatJSON, nil
+	case ".toml":
+		return ConfigFormatTOML, nil
+	default:
+		return "", fmt.Errorf("unsupported config file extension of %s, must be .yaml, .yml, .json or .toml", file)
+	}
+}
+
+// FindConfigFile returns the config file of the home directory, which is the one of config.yaml,
+// config.yml, config.json and config.toml that exists, or config.yaml if none exists.
+func FindConfigFile(homeDir string) (string, error) {
+	var found []string
+	for _, name := range configFileNames {
+		file := filepath.Join(homeDir, name)
+		if _, err := os.Stat(file); err == nil {
+			found = append(found, file)
+		} else if !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+	switch len(found) {
+	case 0:
+		return filepath.Join(homeDir, configFileNames[0]), nil
+	case 1:
+		return found[0], nil
+	default:
+		return "", fmt.Errorf("found several config files, keep only one of them: %s", strings.Join(found, ", "))
+	}
+}
+
+// ConfigToYAML converts the contents of a config file in its format to YAML, which the config is
+// decoded from.
+func ConfigToYAML(format ConfigFormat, bz []byte) ([]byte, error) {
+	var doc any
+	switch format {
+	case ConfigFormatYAML:
+		if err := yamlv3.Unmarshal(bz, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML config: %w", err)
+		}
+		return bz, nil
+	case ConfigFormatJSON:
+		if err := json.Unmarshal(bz, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON config: %w", err)
+		}
+	case ConfigFormatTOML:
+		if err := toml.Unmarshal(bz, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse TOML config: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config format %q", format)
+	}
+	return yamlv3.Marshal(doc)
+}
+
+// ReadConfigYAML reads the config file in its format, and returns it as YAML with the environment
+// variable and file references of its values resolved.
+func ReadConfigYAML(file string) ([]byte, error) {
+	format, err := ConfigFormatOf(file)
+	if err != nil {
+		return nil, err
+	}
+	bz, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if bz, err = ConfigToYAML(format, bz); err != nil {
+		return nil, err
+	}
+	return ExpandConfigReferences(bz)
+}
+
+// MarshalFormat marshals the config in the format.
+func (c *Config) MarshalFormat(format ConfigFormat) ([]byte, error) {
+	bz := c.MustMarshalYaml()
+	if format == ConfigFormatYAML {
+		return bz, nil
+	}
+	var doc map[string]any
+	if err := yamlv3.Unmarshal(bz, &doc); err != nil {
+		return nil, err
+	}
+	switch format {
+	case ConfigFormatJSON:
+		out, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(out, '\n'), nil
+	case ConfigFormatTOML:
+		// TOML has no null, the settings that are not set are left out.
+		return toml.Marshal(withoutNulls(doc))
+	default:
+		return nil, fmt.Errorf("unsupported config format %q", format)
+	}
+}
+
+// withoutNulls returns the value without the null values of its maps and lists.
+func withoutNulls(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, e := range v {
+			if e != nil {
+				out[k] = withoutNulls(e)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, 0, len(v))
+		for _, e := range v {
+			if e != nil {
+				out = append(out, withoutNulls(e))
+			}
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,119 @@
+package signer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestConfigFormatOf(t *testing.T) {
+	for file, format := range map[string]ConfigFormat{
+		"config.yaml": ConfigFormatYAML,
+		"config.yml":  ConfigFormatYAML,
+		"config.json": ConfigFormatJSON,
+		"CONFIG.TOML": ConfigFormatTOML,
+	} {
+		f, err := ConfigFormatOf(file)
+		require.NoError(t, err)
+		require.Equal(t, format, f)
+	}
+	_, err := ConfigFormatOf("config.ini")
+	require.EqualError(t, err, "unsupported config file extension of config.ini, must be .yaml, .yml, .json or .toml")
+}
+
+func TestFindConfigFile(t *testing.T) {
+	home := t.TempDir()
+
+	file, err := FindConfigFile(home)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(home, "config.yaml"), file)
+
+	require.NoError(t, os.WriteFile(filepath.Join(home, "config.toml"), nil, 0600))
+	file, err = FindConfigFile(home)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(home, "config.toml"), file)
+
+	require.NoError(t, os.WriteFile(filepath.Join(home, "config.json"), nil, 0600))
+	_, err = FindConfigFile(home)
+	require.EqualError(t, err, "found several config files, keep only one of them: "+
+		filepath.Join(home, "config.json")+", "+filepath.Join(home, "config.toml"))
+}
+
+func TestConfigFormatsRoundTrip(t *testing.T) {
+	keyDir := "/keys"
+	cfg := Config{
+		PrivValKeyDir: &keyDir,
+		SignMode:      SignModeThreshold,
+		ThresholdModeConfig: &ThresholdModeConfig{
+			Threshold: 2,
+			Cosigners: CosignersConfig{
+				{ShardID: 1, P2PAddr: "tcp://cosigner-1:2222"},
+				{ShardID: 2, P2PAddr: "tcp://cosigner-2:2222", Priority: 1},
+				{ShardID: 3, P2PAddr: "tcp://cosigner-3:2222"},
+			},
+			GRPCTimeout: "1000ms",
+			RaftTimeout: "1000ms",
+		},
+		ChainNodes: ChainNodes{{PrivValAddr: "tcp://127.0.0.1:1234"}},
+		DebugAddr:  "0.0.0.0:6001",
+		Chains: ChainConfigs{
+			"osmosis-1": {GRPCTimeout: "300ms", ChainNodes: ChainNodes{{PrivValAddr: "tcp://127.0.0.1:1235"}}},
+		},
+		FeatureFlags: map[string]FeatureFlag{"soft-sign": {Enabled: true}},
+	}
+
+	for _, name := range []string{"config.yaml", "config.json", "config.toml"} {
+		t.Run(name, func(t *testing.T) {
+			c := RuntimeConfig{ConfigFile: filepath.Join(t.TempDir(), name), Config: cfg}
+			require.NoError(t, c.WriteConfigFile())
+
+			bz, err := ReadConfigYAML(c.ConfigFile)
+			require.NoError(t, err)
+			var read Config
+			require.NoError(t, yaml.Unmarshal(bz, &read))
+			require.Equal(t, cfg, read)
+		})
+	}
+}
+
+func TestReadConfigYAML(t *testing.T) {
+	t.Setenv("HORCRUX_TEST_THRESHOLD", "2")
+	dir := t.TempDir()
+
+	jsonFile := filepath.Join(dir, "config.json")
+	require.NoError(t, os.WriteFile(jsonFile, []byte(`{
+	"signMode": "threshold",
+	"thresholdMode": {"threshold": "${HORCRUX_TEST_THRESHOLD}", "grpcTimeout": "1s"},
+	"chainNodes": [{"privValAddr": "tcp://sentry-1:1234"}]
+}`), 0600))
+
+	tomlFile := filepath.Join(dir, "config.toml")
+	require.NoError(t, os.WriteFile(tomlFile, []byte(`signMode = "threshold"
+
+[thresholdMode]
+threshold = "${HORCRUX_TEST_THRESHOLD}"
+grpcTimeout = "1s"
+
+[[chainNodes]]
+privValAddr = "tcp://sentry-1:1234"
+`), 0600))
+
+	for _, file := range []string{jsonFile, tomlFile} {
+		bz, err := ReadConfigYAML(file)
+		require.NoError(t, err)
+		var cfg Config
+		require.NoError(t, yaml.Unmarshal(bz, &cfg))
+		require.Equal(t, SignModeThreshold, cfg.SignMode)
+		// a reference is a string in JSON and TOML, and is typed as if written in the config.
+		require.Equal(t, 2, cfg.ThresholdModeConfig.Threshold)
+		require.Equal(t, "1s", cfg.ThresholdModeConfig.GRPCTimeout)
+		require.Equal(t, ChainNodes{{PrivValAddr: "tcp://sentry-1:1234"}}, cfg.ChainNodes)
+	}
+
+	require.NoError(t, os.WriteFile(jsonFile, []byte(`{"signMode": `), 0600))
+	_, err := ReadConfigYAML(jsonFile)
+	require.ErrorContains(t, err, "failed to parse JSON config")
+}
@@ -0,0 +1,273 @@
+package signer
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	cometservice "github.com/cometbft/cometbft/libs/service"
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigReloader reloads config.yaml on SIGHUP and applies the changes of the reloadable settings
+// without dropping the connections to the chain nodes or the cosigner cluster: the chains that are
+// served, the chain nodes, the log level and, in threshold mode, the gRPC timeout. Changes of other
+// settings, including the other settings of the chains, are logged and only apply after a restart.
+type ConfigReloader struct {
+	cometservice.BaseService
+
+	logger     cometlog.Logger
+	configFile string
+
+	levels  *LogLevels
+	signers *RemoteSigners
+	// val is nil unless in threshold mode.
+	val *ThresholdValidator
+	// allowlist is nil until set with SetChainAllowlist.
+	allowlist *ChainAllowlistValidator
+
+	// mu serializes the reloads. running is the config the signer runs with, with the changes of
+	// the reloadable settings applied.
+	mu      sync.Mutex
+	running Config
+
+	hup  chan os.Signal
+	quit chan struct{}
+}
+
+// NewConfigReloader returns a ConfigReloader of configFile for a signer started with running.
+func NewConfigReloader(
+	logger cometlog.Logger,
+	configFile string,
+	running Config,
+	levels *LogLevels,
+	signers *RemoteSigners,
+	val *ThresholdValidator,
+) *ConfigReloader {
+	r := &ConfigReloader{
+		logger:     logger,
+		configFile: configFile,
+		running:    running,
+		levels:     levels,
+		signers:    signers,
+		val:        val,
+		hup:        make(chan os.Signal, 1),
+		quit:       make(chan struct{}),
+	}
+	r.BaseService = *cometservice.NewBaseService(logger, "ConfigReloader", r)
+	return r
+}
+
+// SetChainAllowlist sets the ChainAllowlistValidator that the chains of the reloaded config are
+// applied to.
+func (r *ConfigReloader) SetChainAllowlist(allowlist *ChainAllowlistValidator) {
+	r.allowlist = allowlist
+}
+
+func (r *ConfigReloader) OnStart() error {
+	signal.Notify(r.hup, syscall.SIGHUP)
+	go r.loop()
+	return nil
+}
+
+func (r *ConfigReloader) OnStop() {
+	signal.Stop(r.hup)
+	close(r.quit)
+}
+
+func (r *ConfigReloader) loop() {
+	for {
+		select {
+		case <-r.quit:
+			return
+		case <-r.hup:
+			r.logger.Info("Received SIGHUP, reloading config", "file", r.configFile)
+			if err := r.Reload(); err != nil {
+				r.logger.Error("Failed to reload config, keeping the running config", "error", err)
+			}
+		}
+	}
+}
+
+// Reload reads and validates the config file and applies the changes of the reloadable settings.
+// An invalid config is not applied at all.
+func (r *ConfigReloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, err := readConfigFile(r.configFile)
+	if err != nil {
+		totalConfigReloads.WithLabelValues("invalid").Inc()
+		return err
+	}
+
+	if err := r.applyChainNodes(next); err != nil {
+		totalConfigReloads.WithLabelValues("failed").Inc()
+		return err
+	}
+	r.applyChains(next)
+
+	if next.LogLevel != r.running.LogLevel {
+		if err := r.levels.SetLevel(next.LogLevel); err != nil {
+			totalConfigReloads.WithLabelValues("failed").Inc()
+			return err
+		}
+		r.logger.Info("Changed log level", "level", next.LogLevel)
+		r.running.LogLevel = next.LogLevel
+	}
+
+	if r.val != nil && next.ThresholdModeConfig != nil && r.running.ThresholdModeConfig != nil &&
+		next.ThresholdModeConfig.GRPCTimeout != r.running.ThresholdModeConfig.GRPCTimeout {
+		// validated with the config.
+		timeout, _ := time.ParseDuration(next.ThresholdModeConfig.GRPCTimeout)
+		r.val.SetGRPCTimeout(timeout)
+		r.logger.Info("Changed gRPC timeout", "timeout", timeout)
+		tc := *r.running.ThresholdModeConfig
+		tc.GRPCTimeout = next.ThresholdModeConfig.GRPCTimeout
+		r.running.ThresholdModeConfig = &tc
+	}
+
+	totalConfigReloads.WithLabelValues("applied").Inc()
+
+	if changes := restartRequiredChanges(r.running, *next); len(changes) > 0 {
+		configRestartRequired.Set(1)
+		r.logger.Error("Config changes only apply after a restart", "settings", strings.Join(changes, ","))
+	} else {
+		configRestartRequired.Set(0)
+	}
+	return nil
+}
+
+// applyChainNodes connects to the chain nodes added to the config or its chains and disconnects from
+// the chain nodes removed from them. A chain node whose validator or chain changed is reconnected.
+// Chain nodes connected or disconnected at runtime, e.g. through the admin API, are left alone
+// unless the config changes them too.
+func (r *ConfigReloader) applyChainNodes(nextConfig *Config) error {
+	running, next := r.running.AllChainNodes(), nextConfig.AllChainNodes()
+	var added, removed ChainNodes
+	for _, n := range next {
+		if !slices.Contains(running, n) && !slices.Contains(added, n) {
+			added = append(added, n)
+		}
+	}
+	for _, n := range running {
+		if !slices.Contains(next, n) {
+			removed = append(removed, n)
+		}
+	}
+
+	connected := r.signers.Addresses()
+	for _, n := range removed {
+		if !slices.Contains(connected, n.PrivValAddr) {
+			continue
+		}
+		if err := r.signers.Remove(n.PrivValAddr); err != nil {
+			return fmt.Errorf("failed to disconnect from chain node %s: %w", n.PrivValAddr, err)
+		}
+		connected = slices.DeleteFunc(connected, func(address string) bool { return address == n.PrivValAddr })
+		r.logger.Info("Disconnected from chain node removed from the config", "address", n.PrivValAddr)
+	}
+	for _, n := range added {
+		if slices.Contains(connected, n.PrivValAddr) {
+			continue
+		}
+		if err := r.signers.AddNode(n); err != nil {
+			return fmt.Errorf("failed to connect to chain node %s: %w", n.PrivValAddr, err)
+		}
+		r.logger.Info("Connected to chain node added to the config",
+			"address", n.PrivValAddr, "validator", n.Validator, "chain_id", n.ChainID)
+	}
+	r.running.ChainNodes = nextConfig.ChainNodes
+	r.running.Chains = r.running.Chains.withChainNodesOf(nextConfig.Chains)
+	return nil
+}
+
+// applyChains serves the chains of allowedChainIDs and the chains of next, and refuses the chains
+// removed from them, once their chain nodes are applied. A chain removed from the chains with
+// settings that only apply after a restart is served until the restart.
+func (r *ConfigReloader) applyChains(next *Config) {
+	r.running.AllowedChainIDs = next.AllowedChainIDs
+	if r.allowlist == nil {
+		return
+	}
+	allowlist := r.running.ChainIDAllowlist()
+	if slices.Equal(allowlist, r.allowlist.Allowlist()) {
+		return
+	}
+	r.allowlist.SetAllowlist(allowlist)
+	r.logger.Info("Changed the chains that are served", "chain_ids", strings.Join(allowlist, ","))
+}
+
+// readConfigFile reads and validates the config file for its sign mode.
+func readConfigFile(file string) (*Config, error) {
+	bz, err := ReadConfigYAML(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(bz, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	switch cfg.SignMode {
+	case SignModeThreshold:
+		err = cfg.ValidateThresholdModeConfig()
+	case SignModeSingle:
+		err = cfg.ValidateSingleSignerConfig()
+	default:
+		err = fmt.Errorf("unexpected sign mode: %s", cfg.SignMode)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// restartRequiredChanges returns the yaml keys of the settings that differ between the running
+// config and next, other than the reloadable settings, which are already applied.
+func restartRequiredChanges(running, next Config) []string {
+	var changes []string
+	rv, nv := reflect.ValueOf(running), reflect.ValueOf(next)
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		if reflect.DeepEqual(rv.Field(i).Interface(), nv.Field(i).Interface()) {
+			continue
+		}
+		if field.Name == "ThresholdModeConfig" && running.ThresholdModeConfig != nil &&
+			next.ThresholdModeConfig != nil {
+			for _, c := range restartRequiredThresholdChanges(*running.ThresholdModeConfig,
+				*next.ThresholdModeConfig) {
+				changes = append(changes, "thresholdMode."+c)
+			}
+			continue
+		}
+		changes = append(changes, yamlKey(field))
+	}
+	return changes
+}
+
+// restartRequiredThresholdChanges returns the yaml keys of the threshold mode settings that differ.
+func restartRequiredThresholdChanges(running, next ThresholdModeConfig) []string {
+	var changes []string
+	rv, nv := reflect.ValueOf(running), reflect.ValueOf(next)
+	for i := 0; i < rv.NumField(); i++ {
+		if !reflect.DeepEqual(rv.Field(i).Interface(), nv.Field(i).Interface()) {
+			changes = append(changes, yamlKey(rv.Type().Field(i)))
+		}
+	}
+	return changes
+}
+
+func yamlKey(field reflect.StructField) string {
+	key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if key == "" {
+		return field.Name
+	}
+	return key
+}
@@ -0,0 +1,151 @@
+package signer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestConfigReloader(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	cfg := Config{
+		SignMode: SignModeThreshold,
+		ThresholdModeConfig: &ThresholdModeConfig{
+			Threshold: 2,
+			Cosigners: CosignersConfig{
+				{ShardID: 1, P2PAddr: "tcp://cosigner-1:2222"},
+				{ShardID: 2, P2PAddr: "tcp://cosigner-2:2222"},
+				{ShardID: 3, P2PAddr: "tcp://cosigner-3:2222"},
+			},
+			GRPCTimeout: "1000ms",
+			RaftTimeout: "1000ms",
+		},
+		ChainNodes: ChainNodes{{PrivValAddr: "tcp://127.0.0.1:1234"}},
+		LogLevel:   LogLevelInfo,
+	}
+	writeConfig := func(cfg Config) {
+		bz, err := yaml.Marshal(&cfg)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(configFile, bz, 0600))
+	}
+	writeConfig(cfg)
+
+	signers := NewRemoteSigners(cometlog.NewNopLogger(), &mockPrivValidator{}, nil)
+	require.NoError(t, signers.Start())
+	defer func() { _ = signers.Stop() }()
+	require.NoError(t, signers.Add("tcp://127.0.0.1:1234"))
+
+	levels := NewLogLevels()
+	val := &ThresholdValidator{}
+	val.SetGRPCTimeout(time.Second)
+	r := NewConfigReloader(cometlog.NewNopLogger(), configFile, cfg, levels, signers, val)
+
+	// an unchanged config changes nothing.
+	require.NoError(t, r.Reload())
+	require.Equal(t, []string{"tcp://127.0.0.1:1234"}, signers.Addresses())
+
+	// the reloadable settings are applied.
+	next := cfg
+	next.ChainNodes = ChainNodes{{PrivValAddr: "tcp://127.0.0.1:1235"}, {PrivValAddr: "tcp://127.0.0.1:1236"}}
+	next.LogLevel = LogLevelError
+	tc := *cfg.ThresholdModeConfig
+	tc.GRPCTimeout = "2s"
+	next.ThresholdModeConfig = &tc
+	writeConfig(next)
+
+	require.NoError(t, r.Reload())
+	require.Equal(t, []string{"tcp://127.0.0.1:1235", "tcp://127.0.0.1:1236"}, signers.Addresses())
+	level, _ := levels.Level()
+	require.Equal(t, LogLevelError, level)
+	require.Equal(t, 2*time.Second, val.GRPCTimeout())
+	require.Empty(t, restartRequiredChanges(r.running, next))
+
+	// a chain node connected at runtime is left alone.
+	require.NoError(t, signers.Add("tcp://127.0.0.1:1237"))
+	require.NoError(t, r.Reload())
+	require.Len(t, signers.Addresses(), 3)
+
+	// a chain node whose validator changed is reconnected.
+	next.ChainNodes = ChainNodes{
+		{PrivValAddr: "tcp://127.0.0.1:1235"},
+		{PrivValAddr: "tcp://127.0.0.1:1236", Validator: "acme"},
+	}
+	writeConfig(next)
+	require.NoError(t, r.Reload())
+	require.Len(t, signers.Addresses(), 3)
+	require.Equal(t, "acme", signers.signers["tcp://127.0.0.1:1236"].validator)
+
+	// the chain nodes of the chains are connected to, without a restart.
+	next.Chains = ChainConfigs{"osmosis-1": {ChainNodes: ChainNodes{{PrivValAddr: "tcp://127.0.0.1:1239"}}}}
+	writeConfig(next)
+	require.NoError(t, r.Reload())
+	require.Len(t, signers.Addresses(), 4)
+	require.Equal(t, "osmosis-1", signers.signers["tcp://127.0.0.1:1239"].chainID)
+	require.Empty(t, restartRequiredChanges(r.running, next))
+
+	// the chains that are served are applied, without a restart.
+	allowlist := NewChainAllowlistValidator(&mockPrivValidator{}, r.running.ChainIDAllowlist())
+	r.SetChainAllowlist(allowlist)
+	next.Chains = ChainConfigs{
+		"osmosis-1":   {ChainNodes: ChainNodes{{PrivValAddr: "tcp://127.0.0.1:1239"}}},
+		"cosmoshub-4": {},
+	}
+	writeConfig(next)
+	require.NoError(t, r.Reload())
+	require.Equal(t, ChainIDAllowlist{"cosmoshub-4", "osmosis-1"}, allowlist.Allowlist())
+	require.Empty(t, restartRequiredChanges(r.running, next))
+
+	next.AllowedChainIDs = ChainIDAllowlist{"osmosis-1", "cosmoshub-4", "juno-1"}
+	writeConfig(next)
+	require.NoError(t, r.Reload())
+	require.Equal(t, next.AllowedChainIDs, allowlist.Allowlist())
+	require.Empty(t, restartRequiredChanges(r.running, next))
+
+	// a chain removed with a setting that only applies after a restart is served until the restart.
+	r.running.Chains["cosmoshub-4"] = ChainConfig{HaltHeight: 100}
+	next.AllowedChainIDs = nil
+	delete(next.Chains, "cosmoshub-4")
+	writeConfig(next)
+	require.NoError(t, r.Reload())
+	require.Equal(t, ChainIDAllowlist{"cosmoshub-4", "osmosis-1"}, allowlist.Allowlist())
+	require.Equal(t, []string{"chains"}, restartRequiredChanges(r.running, next))
+
+	// an invalid config is not applied at all.
+	invalid := next
+	invalid.ChainNodes = ChainNodes{{PrivValAddr: "tcp://127.0.0.1:1238"}}
+	invalid.LogLevel = "verbose"
+	writeConfig(invalid)
+	require.Error(t, r.Reload())
+	require.Len(t, signers.Addresses(), 4)
+	level, _ = levels.Level()
+	require.Equal(t, LogLevelError, level)
+}
+
+func TestRestartRequiredChanges(t *testing.T) {
+	running := Config{
+		SignMode:  SignModeThreshold,
+		DebugAddr: "localhost:6001",
+		ThresholdModeConfig: &ThresholdModeConfig{
+			Threshold:   2,
+			GRPCTimeout: "1000ms",
+			RaftTimeout: "1000ms",
+		},
+	}
+	next := running
+	require.Empty(t, restartRequiredChanges(running, next))
+
+	next.DebugAddr = "localhost:6002"
+	tc := *running.ThresholdModeConfig
+	tc.RaftTimeout = "2000ms"
+	next.ThresholdModeConfig = &tc
+	require.Equal(t, []string{"thresholdMode.raftTimeout", "debugAddr"}, restartRequiredChanges(running, next))
+
+	next = running
+	next.ThresholdModeConfig = nil
+	require.Equal(t, []string{"thresholdMode"}, restartRequiredChanges(running, next))
+}
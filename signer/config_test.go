@@ -277,6 +277,37 @@ func TestValidateThresholdModeConfig(t *testing.T) {
 			},
 			expectErr: fmt.Errorf("invalid grpcTimeout: %w", fmt.Errorf("time: missing unit in duration \"1000\"")),
 		},
+		{
+			name: "invalid version skew policy",
+			config: signer.Config{
+				ThresholdModeConfig: &signer.ThresholdModeConfig{
+					Threshold:         2,
+					RaftTimeout:       "1000ms",
+					GRPCTimeout:       "1000ms",
+					VersionSkewPolicy: "ignore",
+					Cosigners: signer.CosignersConfig{
+						{
+							ShardID: 1,
+							P2PAddr: "tcp://127.0.0.1:2222",
+						},
+						{
+							ShardID: 2,
+							P2PAddr: "tcp://127.0.0.1:2223",
+						},
+						{
+							ShardID: 3,
+							P2PAddr: "tcp://127.0.0.1:2224",
+						},
+					},
+				},
+				ChainNodes: []signer.ChainNode{
+					{
+						PrivValAddr: "tcp://127.0.0.1:1234",
+					},
+				},
+			},
+			expectErr: fmt.Errorf("invalid versionSkewPolicy \"ignore\", must be warn or refuse"),
+		},
 		{
 			name: "invalid node address",
 			config: signer.Config{
@@ -307,6 +338,70 @@ func TestValidateThresholdModeConfig(t *testing.T) {
 			},
 			expectErr: &url.Error{Op: "parse", URL: "abc://\\invalid_addr", Err: url.InvalidHostError("\\")},
 		},
+		{
+			name: "unknown transport",
+			config: signer.Config{
+				ThresholdModeConfig: &signer.ThresholdModeConfig{
+					Threshold:   2,
+					RaftTimeout: "1000ms",
+					GRPCTimeout: "1000ms",
+					Transport:   &signer.CosignerTransportConfig{Type: "carrier-pigeon"},
+					Cosigners: signer.CosignersConfig{
+						{
+							ShardID: 1,
+							P2PAddr: "tcp://127.0.0.1:2222",
+						},
+						{
+							ShardID: 2,
+							P2PAddr: "tcp://127.0.0.1:2223",
+						},
+						{
+							ShardID: 3,
+							P2PAddr: "tcp://127.0.0.1:2224",
+						},
+					},
+				},
+				ChainNodes: []signer.ChainNode{
+					{
+						PrivValAddr: "tcp://127.0.0.1:1234",
+					},
+				},
+			},
+			expectErr: fmt.Errorf(`unknown cosigner transport "carrier-pigeon", must be one of [tcp tor websocket]`),
+		},
+		{
+			name: "chain nonce expiration above nonce cache expiration",
+			config: signer.Config{
+				ThresholdModeConfig: &signer.ThresholdModeConfig{
+					Threshold:   2,
+					RaftTimeout: "1000ms",
+					GRPCTimeout: "1000ms",
+					NonceCache:  &signer.NonceCacheConfig{Expiration: "5s"},
+					Cosigners: signer.CosignersConfig{
+						{
+							ShardID: 1,
+							P2PAddr: "tcp://127.0.0.1:2222",
+						},
+						{
+							ShardID: 2,
+							P2PAddr: "tcp://127.0.0.1:2223",
+						},
+						{
+							ShardID: 3,
+							P2PAddr: "tcp://127.0.0.1:2224",
+						},
+					},
+				},
+				ChainNodes: []signer.ChainNode{
+					{
+						PrivValAddr: "tcp://127.0.0.1:1234",
+					},
+				},
+				Chains: signer.ChainConfigs{"osmosis-1": {NonceExpiration: "8s"}},
+			},
+			expectErr: fmt.Errorf("invalid config of chain osmosis-1: nonceExpiration cannot be above the " +
+				"nonce cache expiration 5s"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,171 @@
+package signer
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/strangelove-ventures/horcrux/signer/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// cosignerAuthMaxAge is how far the time a peer signed a request may be from the time of the
+// cosigner, which also bounds how long the cosigner remembers the requests it served.
+const cosignerAuthMaxAge = time.Minute
+
+// cosignerAuthRequest is what a cosigner signs to authenticate a request to a peer that changes
+// the state of the peer, so that it can only be sent to that peer, for that method and arguments.
+type cosignerAuthRequest struct {
+	Method        string   `json:"method"`
+	Args          []string `json:"args"`
+	SourceID      int      `json:"sourceID"`
+	DestinationID int      `json:"destinationID"`
+	Timestamp     int64    `json:"timestamp"`
+}
+
+// signCosignerRequest signs the request of the method and arguments to the peer with the shard ID,
+// with the RSA or ECIES key of this cosigner.
+func (pv *ThresholdValidator) signCosignerRequest(
+	destinationID int,
+	method string,
+	args ...string,
+) (*proto.CosignerAuth, error) {
+	return signCosignerRequest(pv.myCosigner.getSecurity(), destinationID, method, args...)
+}
+
+// SignSetFeatureFlagRequest signs the feature flag change of the request to the cosigner with the
+// shard ID with the key of security, e.g. for horcrux feature-flags run on a cosigner.
+func SignSetFeatureFlagRequest(security CosignerSecurity, destinationID int, req *proto.SetFeatureFlagRequest) error {
+	auth, err := signCosignerRequest(security, destinationID, "SetFeatureFlag", setFeatureFlagArgs(req)...)
+	if err != nil {
+		return err
+	}
+	req.Auth = auth
+	return nil
+}
+
+// setFeatureFlagArgs are the arguments of the feature flag change of the request that are signed.
+func setFeatureFlagArgs(req *proto.SetFeatureFlagRequest) []string {
+	flag := req.GetFlag()
+	return []string{
+		flag.GetName(),
+		strconv.FormatBool(flag.GetEnabled()),
+		strings.Join(flag.GetChainIDs(), ","),
+		strconv.FormatUint(uint64(flag.GetPercentage()), 10),
+		strconv.FormatBool(req.ClearOverride),
+	}
+}
+
+func signCosignerRequest(
+	security CosignerSecurity,
+	destinationID int,
+	method string,
+	args ...string,
+) (*proto.CosignerAuth, error) {
+	auth := &proto.CosignerAuth{SourceID: int32(security.GetID()), Timestamp: time.Now().UnixNano()}
+	msg, err := json.Marshal(cosignerAuthRequest{
+		Method:        method,
+		Args:          args,
+		SourceID:      int(auth.SourceID),
+		DestinationID: destinationID,
+		Timestamp:     auth.Timestamp,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if auth.Signature, err = security.Sign(msg); err != nil {
+		return nil, err
+	}
+	return auth, nil
+}
+
+type cosignerAuthKey struct {
+	sourceID  int32
+	timestamp int64
+}
+
+// cosignerAuthVerifier verifies that the requests of the peers that change the state of the
+// cosigner are signed by a peer, recently, and serves each request once.
+type cosignerAuthVerifier struct {
+	mu sync.Mutex
+	// seen is when the requests served expire.
+	seen map[cosignerAuthKey]time.Time
+}
+
+// verify verifies the authentication of the request of the method and arguments to the cosigner
+// with the security, and returns a PermissionDenied error if it does not verify.
+func (v *cosignerAuthVerifier) verify(
+	security CosignerSecurity,
+	auth *proto.CosignerAuth,
+	method string,
+	args ...string,
+) error {
+	return v.verifyFrom(security, auth, false, method, args...)
+}
+
+// verifyCosigner verifies the authentication of the request like verify, but also accepts a
+// request signed with the key of the cosigner itself, e.g. by a horcrux command run on it.
+func (v *cosignerAuthVerifier) verifyCosigner(
+	security CosignerSecurity,
+	auth *proto.CosignerAuth,
+	method string,
+	args ...string,
+) error {
+	return v.verifyFrom(security, auth, true, method, args...)
+}
+
+func (v *cosignerAuthVerifier) verifyFrom(
+	security CosignerSecurity,
+	auth *proto.CosignerAuth,
+	fromSelf bool,
+	method string,
+	args ...string,
+) error {
+	if auth == nil {
+		return status.Errorf(codes.PermissionDenied, "%s is not signed by a peer cosigner", method)
+	}
+	sourceID := int(auth.SourceID)
+	if sourceID == security.GetID() && !fromSelf {
+		return status.Errorf(codes.PermissionDenied, "%s is signed by cosigner %d itself", method, sourceID)
+	}
+	signed := time.Unix(0, auth.Timestamp)
+	if skew := time.Since(signed).Abs(); skew > cosignerAuthMaxAge {
+		return status.Errorf(codes.PermissionDenied,
+			"%s was signed by cosigner %d %s from now, more than %s", method, sourceID, skew, cosignerAuthMaxAge)
+	}
+	msg, err := json.Marshal(cosignerAuthRequest{
+		Method:        method,
+		Args:          args,
+		SourceID:      sourceID,
+		DestinationID: security.GetID(),
+		Timestamp:     auth.Timestamp,
+	})
+	if err != nil {
+		return err
+	}
+	if err := security.Verify(sourceID, msg, auth.Signature); err != nil {
+		return status.Errorf(codes.PermissionDenied,
+			"%s does not verify with the key of cosigner %d: %v", method, sourceID, err)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	now := time.Now()
+	for k, expires := range v.seen {
+		if now.After(expires) {
+			delete(v.seen, k)
+		}
+	}
+	key := cosignerAuthKey{sourceID: auth.SourceID, timestamp: auth.Timestamp}
+	if _, ok := v.seen[key]; ok {
+		return status.Errorf(codes.PermissionDenied, "%s of cosigner %d was already served", method, sourceID)
+	}
+	if v.seen == nil {
+		v.seen = make(map[cosignerAuthKey]time.Time)
+	}
+	v.seen[key] = signed.Add(cosignerAuthMaxAge)
+	return nil
+}
@@ -0,0 +1,155 @@
+package signer
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/strangelove-ventures/horcrux/signer/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCosignerAuth(t *testing.T) {
+	peerVal, peerKill := newKillSwitchTestValidator(t, 2)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcServer := grpc.NewServer()
+	proto.RegisterCosignerServer(grpcServer, NewCosignerGRPCServer(nil, peerVal, nil))
+	go func() { _ = grpcServer.Serve(ln) }()
+	defer grpcServer.Stop()
+
+	peer, err := NewRemoteCosigner(2, "tcp://"+ln.Addr().String(), TCPCosignerTransport{})
+	require.NoError(t, err)
+	val, _ := newKillSwitchTestValidator(t, 1, peer)
+	other, _ := newKillSwitchTestValidator(t, 3)
+	ctx := context.Background()
+
+	requireDenied := func(err error, msg string) {
+		t.Helper()
+		require.Equal(t, codes.PermissionDenied, status.Code(err), err)
+		require.ErrorContains(t, err, msg)
+		require.False(t, peerKill.Status().Engaged)
+		require.False(t, peerVal.signingPause().Status().Paused)
+	}
+
+	// a request without auth, e.g. of a client that can reach the gRPC port, is refused.
+	_, err = peer.SetKillSwitch(ctx, true, "incident", nil, nil)
+	requireDenied(err, "SetKillSwitch is not signed by a peer cosigner")
+	_, err = peer.SetSigningPaused(ctx, true, nil)
+	requireDenied(err, "SetSigningPaused is not signed by a peer cosigner")
+
+	// the auth is bound to the method, its arguments and the destination.
+	auth, err := val.signCosignerRequest(2, "SetKillSwitch", "true", "incident")
+	require.NoError(t, err)
+	_, err = peer.SetKillSwitch(ctx, true, "another incident", nil, auth)
+	requireDenied(err, "SetKillSwitch does not verify with the key of cosigner 1")
+	_, err = peer.SetSigningPaused(ctx, true, auth)
+	requireDenied(err, "SetSigningPaused does not verify with the key of cosigner 1")
+	auth, err = val.signCosignerRequest(3, "SetKillSwitch", "true", "incident")
+	require.NoError(t, err)
+	_, err = peer.SetKillSwitch(ctx, true, "incident", nil, auth)
+	requireDenied(err, "does not verify with the key of cosigner 1")
+
+	// a cosigner cannot sign with the shard ID of another, nor as the peer itself.
+	auth, err = other.signCosignerRequest(2, "SetKillSwitch", "true", "incident")
+	require.NoError(t, err)
+	auth.SourceID = 1
+	_, err = peer.SetKillSwitch(ctx, true, "incident", nil, auth)
+	requireDenied(err, "does not verify with the key of cosigner 1")
+	auth, err = peerVal.signCosignerRequest(2, "SetKillSwitch", "true", "incident")
+	require.NoError(t, err)
+	_, err = peer.SetKillSwitch(ctx, true, "incident", nil, auth)
+	requireDenied(err, "SetKillSwitch is signed by cosigner 2 itself")
+
+	// nor with a key that is not of the cluster.
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	stranger := &ThresholdValidator{myCosigner: &LocalCosigner{security: NewCosignerSecurityRSA(
+		CosignerRSAKey{ID: 4, RSAKey: *rsaKey, RSAPubs: []*rsa.PublicKey{&rsaKey.PublicKey}},
+	)}}
+	auth, err = stranger.signCosignerRequest(2, "SetKillSwitch", "true", "incident")
+	require.NoError(t, err)
+	_, err = peer.SetKillSwitch(ctx, true, "incident", nil, auth)
+	requireDenied(err, "unknown cosigner: 4")
+
+	// an auth that is not recent is refused.
+	auth, err = val.signCosignerRequest(2, "SetKillSwitch", "true", "incident")
+	require.NoError(t, err)
+	auth.Timestamp = time.Now().Add(-2 * cosignerAuthMaxAge).UnixNano()
+	_, err = peer.SetKillSwitch(ctx, true, "incident", nil, auth)
+	requireDenied(err, "more than 1m0s")
+
+	// each auth is served once.
+	auth, err = val.signCosignerRequest(2, "SetKillSwitch", "true", "incident")
+	require.NoError(t, err)
+	_, err = peer.SetKillSwitch(ctx, true, "incident", nil, auth)
+	require.NoError(t, err)
+	require.True(t, peerKill.Status().Engaged)
+	_, err = peerKill.Rearm()
+	require.NoError(t, err)
+	_, err = peer.SetKillSwitch(ctx, true, "incident", nil, auth)
+	requireDenied(err, "SetKillSwitch of cosigner 1 was already served")
+}
+
+// featureFlagLeader is a ClusterLeader that applies the feature flag changes to its flags.
+type featureFlagLeader struct {
+	transferRecordingLeader
+	flags *FeatureFlags
+}
+
+func (l *featureFlagLeader) SetFeatureFlag(event FeatureFlagEvent) error { return event.Apply(l.flags) }
+
+func TestCosignerAuthSetFeatureFlag(t *testing.T) {
+	peerVal, _ := newPauseTestValidator(t, 2)
+	leader := &featureFlagLeader{flags: NewFeatureFlags(nil)}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcServer := grpc.NewServer()
+	proto.RegisterCosignerServer(grpcServer, NewCosignerGRPCServer(peerVal.myCosigner, peerVal, leader))
+	go func() { _ = grpcServer.Serve(ln) }()
+	defer grpcServer.Stop()
+
+	peer, err := NewRemoteCosigner(2, "tcp://"+ln.Addr().String(), TCPCosignerTransport{})
+	require.NoError(t, err)
+	val, _ := newPauseTestValidator(t, 1)
+	ctx := context.Background()
+	newRequest := func() *proto.SetFeatureFlagRequest {
+		return &proto.SetFeatureFlagRequest{Flag: &proto.FeatureFlag{
+			Name:       FeatureFlagNonceFallback,
+			ChainIDs:   []string{testChainID},
+			Percentage: 10,
+		}}
+	}
+
+	// a change without auth, e.g. of a client that can reach the gRPC port, is refused.
+	_, err = peer.client.SetFeatureFlag(ctx, newRequest())
+	require.Equal(t, codes.PermissionDenied, status.Code(err), err)
+	require.True(t, leader.flags.Enabled(FeatureFlagNonceFallback, testChainID))
+
+	// the auth is bound to the change.
+	req := newRequest()
+	require.NoError(t, SignSetFeatureFlagRequest(val.myCosigner.getSecurity(), 2, req))
+	req.Flag.Percentage = 100
+	_, err = peer.client.SetFeatureFlag(ctx, req)
+	require.ErrorContains(t, err, "SetFeatureFlag does not verify with the key of cosigner 1")
+	require.True(t, leader.flags.Enabled(FeatureFlagNonceFallback, testChainID))
+
+	// a change signed by a cosigner of the cluster, e.g. by horcrux feature-flags run on it or on
+	// the leader itself, is applied.
+	for _, security := range []CosignerSecurity{val.myCosigner.getSecurity(), peerVal.myCosigner.getSecurity()} {
+		req := newRequest()
+		require.NoError(t, SignSetFeatureFlagRequest(security, 2, req))
+		_, err = peer.client.SetFeatureFlag(ctx, req)
+		require.NoError(t, err)
+	}
+	flag, _ := leader.flags.Get(FeatureFlagNonceFallback)
+	require.Equal(t, FeatureFlag{ChainIDs: []string{testChainID}, Percentage: 10}, flag)
+}
@@ -2,10 +2,14 @@ package signer
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
-	"github.com/hashicorp/raft"
 	"github.com/strangelove-ventures/horcrux/signer/proto"
 )
 
@@ -14,19 +18,20 @@ var _ proto.CosignerServer = &CosignerGRPCServer{}
 type CosignerGRPCServer struct {
 	cosigner           *LocalCosigner
 	thresholdValidator *ThresholdValidator
-	raftStore          *RaftStore
+	leader             ClusterLeader
+	auth               cosignerAuthVerifier
 	proto.UnimplementedCosignerServer
 }
 
 func NewCosignerGRPCServer(
 	cosigner *LocalCosigner,
 	thresholdValidator *ThresholdValidator,
-	raftStore *RaftStore,
+	leader ClusterLeader,
 ) *CosignerGRPCServer {
 	return &CosignerGRPCServer{
 		cosigner:           cosigner,
 		thresholdValidator: thresholdValidator,
-		raftStore:          raftStore,
+		leader:             leader,
 	}
 }
 
@@ -34,7 +39,13 @@ func (rpc *CosignerGRPCServer) SignBlock(
 	ctx context.Context,
 	req *proto.SignBlockRequest,
 ) (*proto.SignBlockResponse, error) {
-	res, _, err := rpc.thresholdValidator.Sign(ctx, req.ChainID, BlockFromProto(req.Block))
+	if err := rpc.thresholdValidator.killSwitchEngagedError(); err != nil {
+		return nil, err
+	}
+	if err := rpc.thresholdValidator.signingPause().ClusterPausedError(); err != nil {
+		return nil, err
+	}
+	res, _, err := rpc.thresholdValidator.Sign(withProxiedSignRequest(ctx), req.ChainID, BlockFromProto(req.Block))
 	if err != nil {
 		return nil, err
 	}
@@ -47,6 +58,12 @@ func (rpc *CosignerGRPCServer) SetNoncesAndSign(
 	ctx context.Context,
 	req *proto.SetNoncesAndSignRequest,
 ) (*proto.SetNoncesAndSignResponse, error) {
+	if err := rpc.thresholdValidator.killSwitchEngagedError(); err != nil {
+		return nil, err
+	}
+	if err := rpc.thresholdValidator.signingPause().ClusterPausedError(); err != nil {
+		return nil, err
+	}
 	res, err := rpc.cosigner.SetNoncesAndSign(ctx, CosignerSetNoncesAndSignRequest{
 		ChainID: req.ChainID,
 		Nonces: &CosignerUUIDNonces{
@@ -57,7 +74,7 @@ func (rpc *CosignerGRPCServer) SetNoncesAndSign(
 		SignBytes: req.GetSignBytes(),
 	})
 	if err != nil {
-		rpc.raftStore.logger.Error(
+		rpc.cosigner.logger.Error(
 			"Failed to sign with shard",
 			"chain_id", req.ChainID,
 			"height", req.Hrst.Height,
@@ -67,7 +84,7 @@ func (rpc *CosignerGRPCServer) SetNoncesAndSign(
 		)
 		return nil, err
 	}
-	rpc.raftStore.logger.Info(
+	rpc.cosigner.logger.Info(
 		"Signed with shard",
 		"chain_id", req.ChainID,
 		"height", req.Hrst.Height,
@@ -102,38 +119,298 @@ func (rpc *CosignerGRPCServer) GetNonces(
 	}, nil
 }
 
-func (rpc *CosignerGRPCServer) TransferLeadership(
+// StreamNonces answers the requests for nonces of the leader on a stream, each as soon as its nonces
+// are generated, until the leader closes the stream.
+func (rpc *CosignerGRPCServer) StreamNonces(stream proto.Cosigner_StreamNoncesServer) error {
+	var (
+		wg     sync.WaitGroup
+		sendMu sync.Mutex
+	)
+	// the stream cannot be sent on once this returns.
+	defer wg.Wait()
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			uuids := make([]uuid.UUID, len(req.Uuids))
+			for i, uuidBytes := range req.Uuids {
+				uuids[i] = uuid.UUID(uuidBytes)
+			}
+			res := &proto.StreamNoncesResponse{Id: req.Id}
+			nonces, err := rpc.cosigner.GetNonces(stream.Context(), uuids)
+			if err != nil {
+				res.Error = err.Error()
+			} else {
+				res.Nonces = nonces.toProto()
+			}
+			sendMu.Lock()
+			defer sendMu.Unlock()
+			// a failed send breaks the stream, which the next receive returns.
+			_ = stream.Send(res)
+		}()
+	}
+}
+
+// PushNonces applies the changes of the nonce cache pushed by the leader.
+func (rpc *CosignerGRPCServer) PushNonces(
 	_ context.Context,
+	req *proto.PushNoncesRequest,
+) (*proto.PushNoncesResponse, error) {
+	if rpc.thresholdValidator == nil {
+		return nil, errors.New("no nonce cache to push nonces to")
+	}
+	if err := rpc.thresholdValidator.nonceCache.ApplyPush(req); err != nil {
+		return nil, err
+	}
+	return &proto.PushNoncesResponse{}, nil
+}
+
+func (rpc *CosignerGRPCServer) TransferLeadership(
+	ctx context.Context,
 	req *proto.TransferLeadershipRequest,
 ) (*proto.TransferLeadershipResponse, error) {
-	if rpc.raftStore.raft.State() != raft.Leader {
-		return &proto.TransferLeadershipResponse{}, nil
-	}
-	leaderID := req.GetLeaderID()
-	if leaderID != "" {
-		for _, c := range rpc.raftStore.Cosigners {
-			shardID := fmt.Sprint(c.GetID())
-			if shardID == leaderID {
-				raftAddress := p2pURLToRaftAddress(c.GetAddress())
-				fmt.Printf("Transferring leadership to ID: %s - Address: %s\n", shardID, raftAddress)
-				rpc.raftStore.raft.LeadershipTransferToServer(raft.ServerID(shardID), raft.ServerAddress(raftAddress))
-				return &proto.TransferLeadershipResponse{LeaderID: shardID, LeaderAddress: raftAddress}, nil
-			}
+	if req.Drain {
+		leaderID, leaderAddress, err := rpc.thresholdValidator.TransferLeadership(ctx, req.GetLeaderID())
+		if err != nil {
+			return nil, err
 		}
+		return &proto.TransferLeadershipResponse{LeaderID: leaderID, LeaderAddress: leaderAddress}, nil
 	}
-	fmt.Printf("Transferring leadership to next candidate\n")
-	rpc.raftStore.raft.LeadershipTransfer()
-	return &proto.TransferLeadershipResponse{}, nil
+	leaderID, leaderAddress := rpc.leader.TransferLeadership(req.GetLeaderID())
+	return &proto.TransferLeadershipResponse{LeaderID: leaderID, LeaderAddress: leaderAddress}, nil
 }
 
 func (rpc *CosignerGRPCServer) GetLeader(
 	context.Context,
 	*proto.GetLeaderRequest,
 ) (*proto.GetLeaderResponse, error) {
-	leader := rpc.raftStore.GetLeader()
+	leader := rpc.leader.GetLeader()
 	return &proto.GetLeaderResponse{Leader: int32(leader)}, nil
 }
 
-func (rpc *CosignerGRPCServer) Ping(context.Context, *proto.PingRequest) (*proto.PingResponse, error) {
-	return &proto.PingResponse{}, nil
+func (rpc *CosignerGRPCServer) Handshake(
+	_ context.Context,
+	req *proto.HandshakeRequest,
+) (*proto.HandshakeResponse, error) {
+	if _, err := negotiateProtocolVersion(
+		MinCosignerProtocolVersion, CosignerProtocolVersion,
+		req.MinProtocolVersion, req.MaxProtocolVersion,
+	); err != nil {
+		rpc.cosigner.logger.Error(
+			"Incompatible cosigner protocol version",
+			"cosigner", req.Id,
+			"cosigner_version", req.SoftwareVersion,
+			"error", err,
+		)
+	}
+	remote := CosignerBuild{Version: req.SoftwareVersion, Commit: req.Commit}
+	if skew := versionSkew(localCosignerBuild(), remote); skew.Incompatible() {
+		rpc.cosigner.logger.Error(
+			"Leader cosigner is on an incompatible version",
+			"cosigner", req.Id,
+			"error", newVersionSkewError(int(req.Id), localCosignerBuild(), remote, skew),
+		)
+	}
+	return &proto.HandshakeResponse{
+		Id:                 int32(rpc.cosigner.GetID()),
+		MinProtocolVersion: MinCosignerProtocolVersion,
+		MaxProtocolVersion: CosignerProtocolVersion,
+		SoftwareVersion:    SoftwareVersion,
+		Commit:             SoftwareCommit,
+	}, nil
+}
+
+func (rpc *CosignerGRPCServer) SetFeatureFlag(
+	_ context.Context,
+	req *proto.SetFeatureFlagRequest,
+) (*proto.SetFeatureFlagResponse, error) {
+	if req.Flag == nil {
+		return nil, fmt.Errorf("feature flag is required")
+	}
+	if err := rpc.auth.verifyCosigner(
+		rpc.cosigner.getSecurity(),
+		req.Auth,
+		"SetFeatureFlag",
+		setFeatureFlagArgs(req)...,
+	); err != nil {
+		return nil, err
+	}
+	event := FeatureFlagEvent{
+		Name: req.Flag.Name,
+		Flag: FeatureFlag{
+			Enabled:    req.Flag.Enabled,
+			ChainIDs:   req.Flag.ChainIDs,
+			Percentage: req.Flag.Percentage,
+		},
+		Reset: req.ClearOverride,
+	}
+	if err := rpc.leader.SetFeatureFlag(event); err != nil {
+		return nil, err
+	}
+	rpc.cosigner.logger.Info(
+		"Feature flag change replicated",
+		"flag", event.Name,
+		"reset", event.Reset,
+	)
+	return &proto.SetFeatureFlagResponse{}, nil
+}
+
+func (rpc *CosignerGRPCServer) GetFeatureFlags(
+	_ context.Context,
+	_ *proto.GetFeatureFlagsRequest,
+) (*proto.GetFeatureFlagsResponse, error) {
+	featureFlags := rpc.thresholdValidator.FeatureFlags()
+	names := featureFlags.Names()
+	flags := make([]*proto.FeatureFlag, 0, len(names))
+	for _, name := range names {
+		flag, _ := featureFlags.Get(name)
+		flags = append(flags, &proto.FeatureFlag{
+			Name:       name,
+			Enabled:    flag.Enabled,
+			ChainIDs:   flag.ChainIDs,
+			Percentage: flag.Percentage,
+			Overridden: featureFlags.IsOverridden(name),
+		})
+	}
+	return &proto.GetFeatureFlagsResponse{
+		Flags: flags,
+	}, nil
+}
+
+func (rpc *CosignerGRPCServer) Ping(_ context.Context, req *proto.PingRequest) (*proto.PingResponse, error) {
+	if rpc.thresholdValidator == nil {
+		return &proto.PingResponse{}, nil
+	}
+	fence := rpc.thresholdValidator.splitBrain
+	if req.Id != 0 {
+		fence.Observe(int(req.Id), req.IsLeader, req.LeaderTerm, time.Now())
+	}
+	return fence.pingResponse(), nil
+}
+
+func (rpc *CosignerGRPCServer) GetSignState(
+	ctx context.Context,
+	req *proto.GetSignStateRequest,
+) (*proto.GetSignStateResponse, error) {
+	hrs, err := rpc.thresholdValidator.GetSignState(ctx, req.ChainID, SignStateKind(req.Kind))
+	if err != nil {
+		return nil, err
+	}
+	if hrs == nil {
+		return &proto.GetSignStateResponse{}, nil
+	}
+	return &proto.GetSignStateResponse{
+		Found:  true,
+		Height: hrs.Height,
+		Round:  hrs.Round,
+		Step:   int32(hrs.Step),
+	}, nil
+}
+
+func (rpc *CosignerGRPCServer) GetStatus(
+	ctx context.Context,
+	_ *proto.GetStatusRequest,
+) (*proto.GetStatusResponse, error) {
+	health := rpc.thresholdValidator.Health()
+	res := &proto.GetStatusResponse{
+		Id:                   int32(health.CosignerID),
+		Leader:               int32(health.Leader),
+		IsLeader:             health.IsLeader,
+		SoftwareVersion:      SoftwareVersion,
+		ProtocolVersion:      CosignerProtocolVersion,
+		NonceCacheSize:       int32(health.NonceCache.Size),
+		NonceCacheTargetSize: int32(health.NonceCache.TargetSize),
+		Timestamp:            time.Now().UnixNano(),
+		StartedAt:            signerStarted.UnixNano(),
+	}
+	chainIDs, err := rpc.thresholdValidator.KeyShardChainIDs()
+	if err != nil {
+		return nil, err
+	}
+	res.KeyShardChainIDs = chainIDs
+	if pause := rpc.thresholdValidator.signingPause(); pause != nil {
+		res.Signing = pause.Status().ToProto()
+	}
+	if kill := rpc.thresholdValidator.killSwitch(); kill != nil {
+		res.KillSwitch = kill.Status().ToProto()
+	}
+	if lock := rpc.thresholdValidator.signLock.Load(); lock != nil {
+		res.SignLockLease = lock.Lease()
+	}
+	for _, p := range health.Peers {
+		res.Peers = append(res.Peers, &proto.PeerStatus{
+			Id:              int32(p.ID),
+			Address:         p.Address,
+			Pinged:          p.Reachable != nil,
+			Reachable:       p.Reachable != nil && *p.Reachable,
+			RttMs:           p.RTTMilliseconds,
+			ProtocolVersion: p.ProtocolVersion,
+		})
+	}
+	for _, chainID := range rpc.thresholdValidator.ChainIDs() {
+		hrs, err := rpc.thresholdValidator.GetSignState(ctx, chainID, "")
+		if err != nil {
+			return nil, err
+		}
+		if hrs == nil {
+			continue
+		}
+		res.Chains = append(res.Chains, &proto.ChainStatus{
+			ChainID: chainID,
+			Height:  hrs.Height,
+			Round:   hrs.Round,
+			Step:    int32(hrs.Step),
+		})
+	}
+	return res, nil
+}
+
+func (rpc *CosignerGRPCServer) SetSigningPaused(
+	_ context.Context,
+	req *proto.SetSigningPausedRequest,
+) (*proto.SetSigningPausedResponse, error) {
+	if err := rpc.auth.verify(
+		rpc.thresholdValidator.myCosigner.getSecurity(),
+		req.Auth,
+		"SetSigningPaused",
+		strconv.FormatBool(req.Paused),
+	); err != nil {
+		return nil, err
+	}
+	status, err := rpc.thresholdValidator.setSigningPaused(req.Paused)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.SetSigningPausedResponse{Status: status.ToProto()}, nil
+}
+
+func (rpc *CosignerGRPCServer) SetKillSwitch(
+	_ context.Context,
+	req *proto.SetKillSwitchRequest,
+) (*proto.SetKillSwitchResponse, error) {
+	if err := rpc.auth.verify(
+		rpc.thresholdValidator.myCosigner.getSecurity(),
+		req.Auth,
+		"SetKillSwitch",
+		append([]string{strconv.FormatBool(req.Engaged), req.Reason}, req.Approvals...)...,
+	); err != nil {
+		return nil, err
+	}
+	if !req.Engaged {
+		if err := rpc.thresholdValidator.approveClusterRearm(req.Approvals); err != nil {
+			return nil, err
+		}
+	}
+	status, err := rpc.thresholdValidator.setKillSwitch(req.Engaged, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.SetKillSwitchResponse{Status: status.ToProto()}, nil
 }
@@ -2,33 +2,74 @@ package signer
 
 import (
 	"context"
+	"slices"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	cometlog "github.com/cometbft/cometbft/libs/log"
-	"github.com/strangelove-ventures/horcrux/signer/proto"
 )
 
 const (
 	pingInterval = 1 * time.Second
+
+	// statsWeight is the weight of the latest request in the recent latency and error rate of a cosigner.
+	statsWeight = 0.2
+
+	// errorRatePenalty scales the latency of a cosigner by its recent error rate when ranking the
+	// cosigners, so that a cosigner failing half of its requests ranks as if it were 6 times slower.
+	errorRatePenalty = 10
 )
 
+// cosignerStats are the recent latency and error rate of the requests to a cosigner, and the rate of
+// its shares that did not combine into a valid signature, as exponentially weighted moving averages.
+type cosignerStats struct {
+	// latency of the successful requests in nanoseconds, 0 until a request succeeded.
+	latency     float64
+	errorRate   float64
+	invalidRate float64
+}
+
 type CosignerHealth struct {
-	logger    cometlog.Logger
+	logger cometlog.Logger
+	// id is the shard ID of this cosigner, which it handshakes with.
+	id        int
 	cosigners []Cosigner
 	rtt       map[int]int64
+	stats     map[int]*cosignerStats
 	mu        sync.RWMutex
 
 	leader Leader
+
+	// fence exchanges the leadership claims in the pings, and may be nil.
+	fence *SplitBrainFence
+
+	// refuseVersionSkew excludes the cosigners on an incompatible version as if unreachable.
+	refuseVersionSkew atomic.Bool
+
+	// quarantine is nil unless the degraded cosigners are quarantined.
+	quarantine *cosignerQuarantine
+	threshold  int
+	// quarantined cosigners, with the time since their score is recovering, zero if it is not.
+	quarantined map[int]time.Time
+
+	// nonceBackoff is nil unless the cosigners whose nonces are cleared repeatedly are backed off.
+	nonceBackoff *nonceBackoff
+	backoffs     map[int]*nonceBackoffState
 }
 
-func NewCosignerHealth(logger cometlog.Logger, cosigners []Cosigner, leader Leader) *CosignerHealth {
+func NewCosignerHealth(logger cometlog.Logger, id int, cosigners []Cosigner, leader Leader) *CosignerHealth {
 	return &CosignerHealth{
 		logger:    logger,
+		id:        id,
 		cosigners: cosigners,
 		rtt:       make(map[int]int64),
+		stats:     make(map[int]*cosignerStats),
 		leader:    leader,
+
+		quarantined: make(map[int]time.Time),
+		backoffs:    make(map[int]*nonceBackoffState),
 	}
 }
 
@@ -36,14 +77,20 @@ func (ch *CosignerHealth) Reconcile(ctx context.Context) {
 	if !ch.leader.IsLeader() {
 		return
 	}
+	ch.mu.RLock()
+	cosigners := ch.cosigners
+	ch.mu.RUnlock()
 	var wg sync.WaitGroup
-	wg.Add(len(ch.cosigners))
-	for _, cosigner := range ch.cosigners {
+	wg.Add(len(cosigners))
+	for _, cosigner := range cosigners {
 		if rc, ok := cosigner.(*RemoteCosigner); ok {
 			go ch.updateRTT(ctx, rc, &wg)
 		}
 	}
 	wg.Wait()
+	now := time.Now()
+	ch.updateQuarantine(now)
+	ch.updateNonceBackoff(now)
 }
 
 func (ch *CosignerHealth) Start(ctx context.Context) {
@@ -59,6 +106,22 @@ func (ch *CosignerHealth) Start(ctx context.Context) {
 	}
 }
 
+// SetCosigners changes the peer cosigners whose health is tracked. The health of a cosigner that
+// was removed or replaced, e.g. with a new address, is forgotten.
+func (ch *CosignerHealth) SetCosigners(cosigners []Cosigner) {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	for _, old := range ch.cosigners {
+		if !slices.Contains(cosigners, old) {
+			delete(ch.rtt, old.GetID())
+			delete(ch.stats, old.GetID())
+			delete(ch.quarantined, old.GetID())
+			delete(ch.backoffs, old.GetID())
+		}
+	}
+	ch.cosigners = cosigners
+}
+
 func (ch *CosignerHealth) MarkUnhealthy(cosigner Cosigner) {
 	ch.mu.Lock()
 	defer ch.mu.Unlock()
@@ -78,31 +141,122 @@ func (ch *CosignerHealth) updateRTT(ctx context.Context, cosigner *RemoteCosigne
 	ctx, cancel := context.WithTimeout(ctx, 1*time.Second)
 	defer cancel()
 
-	_, err := cosigner.client.Ping(ctx, &proto.PingRequest{})
+	res, err := cosigner.client.Ping(ctx, ch.fence.pingRequest())
+	ch.recordProbe(cosigner, time.Since(start), err)
 	if err != nil {
 		ch.logger.Error("Failed to ping", "cosigner", cosigner.GetID(), "error", err)
+		// the peer may be restarted with a different version, so negotiate again on reconnect.
+		cosigner.protocolVersion.Store(0)
+		// the peer may be restarted at a different address, so dial it again without backoff.
+		cosigner.resetConnectBackoff()
 		return
 	}
-	rtt = time.Since(start).Nanoseconds()
+	elapsed := time.Since(start).Nanoseconds()
+	ch.fence.Observe(cosigner.GetID(), res.IsLeader, res.LeaderTerm, time.Now())
+
+	if cosigner.ProtocolVersion() == 0 {
+		version, err := cosigner.Handshake(ctx, ch.id)
+		if err != nil {
+			ch.logger.Error("Failed cosigner protocol handshake", "cosigner", cosigner.GetID(), "error", err)
+			return
+		}
+		build, _ := cosigner.Build()
+		ch.logger.Info(
+			"Negotiated cosigner protocol version",
+			"cosigner", cosigner.GetID(),
+			"version", version,
+			"cosigner_version", build.Version,
+			"cosigner_commit", build.Commit,
+		)
+		ch.logVersionSkew(cosigner)
+	}
+	if ch.refusesVersionSkew(cosigner) {
+		return
+	}
+
+	rtt = elapsed
 }
 
+// RTT returns the round trip time of the last ping of the cosigner. It returns -1 if the last ping
+// failed, and false if the cosigner has not been pinged, which is the case unless this cosigner is the leader.
+func (ch *CosignerHealth) RTT(cosigner Cosigner) (time.Duration, bool) {
+	ch.mu.RLock()
+	defer ch.mu.RUnlock()
+	rtt, ok := ch.rtt[cosigner.GetID()]
+	return time.Duration(rtt), ok
+}
+
+// RecordResult records the latency and outcome of a request to the cosigner, e.g. fetching nonces
+// or signing a share, in the recent latency and error rate used to rank the cosigners.
+func (ch *CosignerHealth) RecordResult(cosigner Cosigner, latency time.Duration, err error) {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	s := ch.statsFor(cosigner.GetID())
+	if err != nil {
+		s.errorRate += statsWeight * (1 - s.errorRate)
+		return
+	}
+	s.errorRate -= statsWeight * s.errorRate
+	if s.latency == 0 {
+		s.latency = float64(latency)
+	} else {
+		s.latency += statsWeight * (float64(latency) - s.latency)
+	}
+}
+
+// statsFor returns the stats of the cosigner, created if none were recorded yet. ch.mu must be held.
+func (ch *CosignerHealth) statsFor(id int) *cosignerStats {
+	s, ok := ch.stats[id]
+	if !ok {
+		s = new(cosignerStats)
+		ch.stats[id] = s
+	}
+	return s
+}
+
+// score returns the rank of the cosigner, lower is better, or false if the last ping of the
+// cosigner failed or it has not been pinged. The score is the recent latency of the requests to the
+// cosigner, or the ping round trip time before a request succeeded, penalized by the recent error rate.
+func (ch *CosignerHealth) score(id int) (float64, bool) {
+	rtt, ok := ch.rtt[id]
+	if !ok || rtt == -1 {
+		return 0, false
+	}
+	latency := float64(rtt)
+	s, ok := ch.stats[id]
+	if !ok {
+		return latency, true
+	}
+	if s.latency > 0 {
+		latency = s.latency
+	}
+	return latency * (1 + errorRatePenalty*s.errorRate), true
+}
+
+// GetFastest returns the peer cosigners ordered by their recent latency and error rate, the best
+// first. Cosigners whose last ping failed or that have not been pinged are last, and quarantined
+// cosigners and refused cosigners on an incompatible version are left out.
 func (ch *CosignerHealth) GetFastest() []Cosigner {
 	ch.mu.RLock()
 	defer ch.mu.RUnlock()
 
-	fastest := make([]Cosigner, len(ch.cosigners))
-	copy(fastest, ch.cosigners)
+	fastest := make([]Cosigner, 0, len(ch.cosigners))
+	for _, c := range ch.cosigners {
+		if _, ok := ch.quarantined[c.GetID()]; !ok && !ch.refusesVersionSkew(c) {
+			fastest = append(fastest, c)
+		}
+	}
 
-	sort.Slice(fastest, func(i, j int) bool {
-		rtt1, ok1 := ch.rtt[fastest[i].GetID()]
-		rtt2, ok2 := ch.rtt[fastest[j].GetID()]
-		if rtt1 == -1 || !ok1 {
+	sort.SliceStable(fastest, func(i, j int) bool {
+		score1, ok1 := ch.score(fastest[i].GetID())
+		score2, ok2 := ch.score(fastest[j].GetID())
+		if !ok1 {
 			return false
 		}
-		if rtt2 == -1 || !ok2 {
+		if !ok2 {
 			return true
 		}
-		return rtt1 < rtt2
+		return score1 < score2
 	})
 
 	return fastest
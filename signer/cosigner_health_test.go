@@ -1,16 +1,23 @@
 package signer
 
 import (
+	"context"
+	"errors"
+	"net"
 	"os"
+	"sync"
 	"testing"
 
 	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/strangelove-ventures/horcrux/signer/proto"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 )
 
 func TestCosignerHealth(t *testing.T) {
 	ch := NewCosignerHealth(
 		cometlog.NewTMLogger(cometlog.NewSyncWriter(os.Stdout)),
+		1,
 		[]Cosigner{
 			&RemoteCosigner{id: 2},
 			&RemoteCosigner{id: 3},
@@ -34,3 +41,93 @@ func TestCosignerHealth(t *testing.T) {
 	require.Equal(t, 4, fastest[0].GetID())
 	require.Equal(t, 2, fastest[1].GetID())
 }
+
+func TestCosignerHealthRecentResults(t *testing.T) {
+	ch := NewCosignerHealth(
+		cometlog.NewNopLogger(),
+		1,
+		[]Cosigner{
+			&RemoteCosigner{id: 2},
+			&RemoteCosigner{id: 3},
+			&RemoteCosigner{id: 4},
+		},
+		&MockLeader{id: 1},
+	)
+
+	ch.rtt = map[int]int64{
+		2: 100,
+		3: 200,
+		4: 300,
+	}
+
+	// the latency of the requests takes precedence over the ping round trip time.
+	ch.RecordResult(&RemoteCosigner{id: 4}, 50, nil)
+	require.Equal(t, []int{4, 2, 3}, cosignerIDs(ch.GetFastest()))
+
+	// a cosigner failing its requests ranks below slower cosigners.
+	for i := 0; i < 3; i++ {
+		ch.RecordResult(&RemoteCosigner{id: 4}, 0, errors.New("timeout"))
+	}
+	require.Equal(t, []int{2, 3, 4}, cosignerIDs(ch.GetFastest()))
+
+	// and recovers once its requests succeed again.
+	for i := 0; i < 20; i++ {
+		ch.RecordResult(&RemoteCosigner{id: 4}, 50, nil)
+	}
+	require.Equal(t, []int{4, 2, 3}, cosignerIDs(ch.GetFastest()))
+
+	// a cosigner whose last ping failed is last regardless of its requests.
+	ch.MarkUnhealthy(&RemoteCosigner{id: 4})
+	require.Equal(t, []int{2, 3, 4}, cosignerIDs(ch.GetFastest()))
+}
+
+func cosignerIDs(cosigners []Cosigner) []int {
+	ids := make([]int, len(cosigners))
+	for i, c := range cosigners {
+		ids[i] = c.GetID()
+	}
+	return ids
+}
+
+// handshakeRecorder is a cosigner gRPC server that records the shard IDs handshaken with.
+type handshakeRecorder struct {
+	proto.UnimplementedCosignerServer
+	ids chan int32
+}
+
+func (r *handshakeRecorder) Ping(context.Context, *proto.PingRequest) (*proto.PingResponse, error) {
+	return &proto.PingResponse{}, nil
+}
+
+func (r *handshakeRecorder) Handshake(
+	_ context.Context,
+	req *proto.HandshakeRequest,
+) (*proto.HandshakeResponse, error) {
+	r.ids <- req.Id
+	return &proto.HandshakeResponse{
+		Id:                 2,
+		MinProtocolVersion: MinCosignerProtocolVersion,
+		MaxProtocolVersion: CosignerProtocolVersion,
+	}, nil
+}
+
+func TestCosignerHealthHandshake(t *testing.T) {
+	recorder := &handshakeRecorder{ids: make(chan int32, 1)}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcServer := grpc.NewServer()
+	proto.RegisterCosignerServer(grpcServer, recorder)
+	go func() { _ = grpcServer.Serve(ln) }()
+	defer grpcServer.Stop()
+
+	peer, err := NewRemoteCosigner(2, "tcp://"+ln.Addr().String(), TCPCosignerTransport{})
+	require.NoError(t, err)
+
+	// the cosigner handshakes with its own shard ID, whichever cosigner the leader election reports.
+	ch := NewCosignerHealth(cometlog.NewNopLogger(), 1, []Cosigner{peer}, &MockLeader{id: 3})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	ch.updateRTT(context.Background(), peer, &wg)
+	require.Equal(t, int32(1), <-recorder.ids)
+	require.Equal(t, CosignerProtocolVersion, peer.ProtocolVersion())
+}
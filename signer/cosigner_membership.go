@@ -0,0 +1,154 @@
+package signer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+)
+
+// CosignerMembership adds, moves and removes the peer cosigners of the threshold validator at
+// runtime, so that a cosigner can be taken out of the cluster or moved to a new address without
+// restarting the other cosigners.
+//
+// The shard IDs are those of the key shares, so a cosigner can only be added back with a shard ID
+// of the key shares, and the threshold does not change. The changes are not written to
+// config.yaml, which still needs every cosigner for the next restart.
+type CosignerMembership struct {
+	logger    cometlog.Logger
+	config    *RuntimeConfig
+	val       *ThresholdValidator
+	transport CosignerTransport
+	shards    int
+
+	// mu serializes the changes.
+	mu sync.Mutex
+}
+
+// NewCosignerMembership returns the CosignerMembership of the threshold validator.
+func NewCosignerMembership(
+	logger cometlog.Logger,
+	config *RuntimeConfig,
+	val *ThresholdValidator,
+) (*CosignerMembership, error) {
+	transport, err := config.Config.ThresholdModeConfig.CosignerTransport()
+	if err != nil {
+		return nil, err
+	}
+	return &CosignerMembership{
+		logger:    logger,
+		config:    config,
+		val:       val,
+		transport: transport,
+		shards:    len(config.Config.ThresholdModeConfig.Cosigners),
+	}, nil
+}
+
+// AddCosigner adds the peer cosigner with the shard ID at the P2P address, or moves it to the
+// address if it is already a peer. The cosigner key is read again first, so that the new public key
+// of a peer that was given a new key is used.
+func (m *CosignerMembership) AddCosigner(shardID int, p2pAddr string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if shardID < 1 || shardID > m.shards {
+		return fmt.Errorf("cosigner shard ID %d is out of range, must be between 1 and %d, inclusive",
+			shardID, m.shards)
+	}
+	if shardID == m.val.myCosigner.GetID() {
+		return fmt.Errorf("cosigner shard ID %d is this cosigner", shardID)
+	}
+	if err := (CosignerConfig{ShardID: shardID, P2PAddr: p2pAddr}).validateP2PAddr(); err != nil {
+		return err
+	}
+
+	security, err := m.config.CosignerSecurity()
+	if err != nil {
+		return err
+	}
+	if err := m.val.myCosigner.SetSecurity(security); err != nil {
+		return err
+	}
+
+	existing := m.val.Peers()
+	if p := existing.GetByID(shardID); p != nil && p.GetAddress() == p2pAddr {
+		return nil
+	}
+
+	rc, err := NewRemoteCosigner(shardID, p2pAddr, m.transport)
+	if err != nil {
+		return fmt.Errorf("failed to initialize remote cosigner: %w", err)
+	}
+	peers := slices.DeleteFunc(slices.Clone(existing), func(p Cosigner) bool { return p.GetID() == shardID })
+	peers = append(peers, rc)
+	slices.SortFunc(peers, func(a, b Cosigner) int { return a.GetID() - b.GetID() })
+
+	if err := m.val.SetPeers(peers); err != nil {
+		return err
+	}
+	m.logger.Info("Added cosigner", "cosigner", shardID, "address", p2pAddr)
+	return nil
+}
+
+// RemoveCosigner removes the peer cosigner with the shard ID. At least threshold cosigners must remain.
+func (m *CosignerMembership) RemoveCosigner(shardID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing := m.val.Peers()
+	if existing.GetByID(shardID) == nil {
+		return fmt.Errorf("cosigner shard ID %d is not a peer", shardID)
+	}
+	peers := slices.DeleteFunc(slices.Clone(existing), func(p Cosigner) bool { return p.GetID() == shardID })
+
+	if err := m.val.SetPeers(peers); err != nil {
+		return err
+	}
+	m.logger.Info("Removed cosigner", "cosigner", shardID)
+	return nil
+}
+
+// ServeHTTP serves the peer cosigners as JSON. A POST request with the shardID and p2pAddr query
+// parameters adds or moves the cosigner, and a DELETE request with the shardID query parameter
+// removes it. It must only be served authenticated, as by the admin API at /v1/cosigners.
+func (m *CosignerMembership) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost, http.MethodDelete:
+		query := r.URL.Query()
+		shardID, err := strconv.Atoi(query.Get("shardID"))
+		if err != nil {
+			http.Error(w, "shardID is required", http.StatusBadRequest)
+			return
+		}
+		if r.Method == http.MethodPost {
+			err = m.AddCosigner(shardID, query.Get("p2pAddr"))
+		} else {
+			err = m.RemoveCosigner(shardID)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	type cosigner struct {
+		ShardID int    `json:"shardID"`
+		P2PAddr string `json:"p2pAddr"`
+	}
+	peers := m.val.Peers()
+	cosigners := make([]cosigner, len(peers))
+	for i, p := range peers {
+		cosigners[i] = cosigner{ShardID: p.GetID(), P2PAddr: p.GetAddress()}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(cosigners)
+}
@@ -0,0 +1,79 @@
+package signer
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCosignerMembership(t *testing.T) {
+	cosigners, _ := getTestLocalCosigners(t, 2, 3)
+
+	// the cosigner key is read again when a cosigner is added.
+	key, err := json.Marshal(&cosigners[0].security.(*CosignerSecurityECIES).key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(cosigners[0].config.KeyFilePathCosignerECIES(), key, 0600))
+
+	leader := &MockLeader{id: 1}
+	validator := NewThresholdValidator(
+		cometlog.NewNopLogger(),
+		cosigners[0].config,
+		2,
+		time.Second,
+		1,
+		cosigners[0],
+		[]Cosigner{cosigners[1], cosigners[2]},
+		leader,
+	)
+	defer validator.Stop()
+	leader.SetLeader(validator)
+
+	m, err := NewCosignerMembership(cometlog.NewNopLogger(), cosigners[0].config, validator)
+	require.NoError(t, err)
+
+	validator.cosignerHealth.MarkUnhealthy(cosigners[2])
+	require.NoError(t, m.RemoveCosigner(3))
+	require.Equal(t, Cosigners{cosigners[1]}, validator.Peers())
+	require.Len(t, validator.nonceCache.cosigners, 2)
+	// the health of a removed cosigner is forgotten.
+	_, ok := validator.cosignerHealth.RTT(cosigners[2])
+	require.False(t, ok)
+
+	// threshold cosigners must remain.
+	require.Error(t, m.RemoveCosigner(2))
+	require.Error(t, m.RemoveCosigner(3))
+
+	// only peers with a shard ID of the key shares can be added.
+	require.Error(t, m.AddCosigner(1, "tcp://cosigner-1:2222"))
+	require.Error(t, m.AddCosigner(4, "tcp://cosigner-4:2222"))
+	require.Error(t, m.AddCosigner(3, "tcp://0.0.0.0:2222"))
+
+	require.NoError(t, m.AddCosigner(3, "tcp://cosigner-3:2222"))
+	peers := validator.Peers()
+	require.Len(t, peers, 2)
+	require.Equal(t, cosigners[1], peers[0])
+	require.Equal(t, "tcp://cosigner-3:2222", peers[1].GetAddress())
+	require.Len(t, validator.nonceCache.cosigners, 3)
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/cosigners?shardID=3&p2pAddr=tcp://cosigner-3b:2222", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var res []struct {
+		ShardID int    `json:"shardID"`
+		P2PAddr string `json:"p2pAddr"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+	require.Len(t, res, 2)
+	require.Equal(t, 3, res[1].ShardID)
+	require.Equal(t, "tcp://cosigner-3b:2222", res[1].P2PAddr)
+
+	rec = httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/v1/cosigners", nil))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
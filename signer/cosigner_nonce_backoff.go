@@ -0,0 +1,192 @@
+package signer
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultNonceBackoffClears     = 3
+	defaultNonceBackoffWindow     = 30 * time.Second
+	defaultNonceBackoffMinBackoff = 10 * time.Second
+	defaultNonceBackoffMaxBackoff = 5 * time.Minute
+)
+
+// NonceCacheBackoffConfig is the on disk config format of the backoff from the peer cosigners whose
+// nonces are cleared repeatedly, e.g. because they time out or lost their nonces. A backed off
+// cosigner is left out of the nonce generation, instead of delaying every refill of the nonce cache
+// until it times out, and is included again once the backoff passed and a ping succeeds.
+type NonceCacheBackoffConfig struct {
+	// Disabled keeps including the cosigners whose nonces are cleared in the nonce generation.
+	Disabled bool `yaml:"disabled,omitempty"`
+
+	// Clears is the number of times the nonces of a cosigner are cleared within window before it is
+	// backed off. Defaults to 3.
+	Clears int `yaml:"clears,omitempty"`
+
+	// Window is the period over which the clears are counted. Defaults to 30s.
+	Window string `yaml:"window,omitempty"`
+
+	// MinBackoff is the backoff of a cosigner, doubled each time it is backed off again soon after
+	// it was included again, or its ping fails once the backoff passed. Defaults to 10s.
+	MinBackoff string `yaml:"minBackoff,omitempty"`
+
+	// MaxBackoff is the longest backoff of a cosigner. Defaults to 5m.
+	MaxBackoff string `yaml:"maxBackoff,omitempty"`
+}
+
+// nonceBackoff are the parameters of the backoff from the cosigners whose nonces are cleared.
+type nonceBackoff struct {
+	disabled   bool
+	clears     int
+	window     time.Duration
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+// nonceBackoffState is the backoff of a peer cosigner from the nonce generation.
+type nonceBackoffState struct {
+	// cleared are the times its nonces were cleared within the window.
+	cleared []time.Time
+
+	// until is when the backoff passes, zero if the cosigner is not backed off.
+	until   time.Time
+	backoff time.Duration
+
+	// included is when the cosigner was last included again.
+	included time.Time
+}
+
+// params returns the backoff of the config, the defaults of def without a config.
+func (cfg *NonceCacheBackoffConfig) params(def nonceBackoff) (nonceBackoff, error) {
+	if cfg == nil {
+		return def, nil
+	}
+	b := def
+	b.disabled = cfg.Disabled
+	if cfg.Clears < 0 {
+		return nonceBackoff{}, fmt.Errorf("nonce cache backoff clears (%d) cannot be negative", cfg.Clears)
+	}
+	if cfg.Clears != 0 {
+		b.clears = cfg.Clears
+	}
+	var err error
+	if b.window, err = parseDurationOrDefault(cfg.Window, b.window); err != nil {
+		return nonceBackoff{}, fmt.Errorf("invalid nonce cache backoff window: %w", err)
+	}
+	if b.minBackoff, err = parseDurationOrDefault(cfg.MinBackoff, b.minBackoff); err != nil {
+		return nonceBackoff{}, fmt.Errorf("invalid nonce cache backoff minBackoff: %w", err)
+	}
+	if b.maxBackoff, err = parseDurationOrDefault(cfg.MaxBackoff, b.maxBackoff); err != nil {
+		return nonceBackoff{}, fmt.Errorf("invalid nonce cache backoff maxBackoff: %w", err)
+	}
+	if b.window <= 0 || b.minBackoff <= 0 || b.maxBackoff < b.minBackoff {
+		return nonceBackoff{}, fmt.Errorf(
+			"nonce cache backoff window (%s) and minBackoff (%s) must be positive, maxBackoff (%s) not below minBackoff",
+			b.window, b.minBackoff, b.maxBackoff,
+		)
+	}
+	return b, nil
+}
+
+// SetNonceBackoff backs off from the peer cosigners whose nonces are cleared repeatedly, as long as
+// threshold cosigners, including this one, are left for the nonce generation.
+func (ch *CosignerHealth) SetNonceBackoff(b nonceBackoff, threshold int) {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	ch.nonceBackoff = &b
+	ch.threshold = threshold
+}
+
+// RecordNoncesCleared records that the nonces of the peer cosigner were cleared at now, and backs it
+// off from the nonce generation once they were cleared too often within the window.
+func (ch *CosignerHealth) RecordNoncesCleared(cosigner Cosigner, now time.Time) {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	if ch.nonceBackoff == nil || ch.nonceBackoff.disabled || !slices.Contains(ch.cosigners, cosigner) {
+		return
+	}
+	id := cosigner.GetID()
+	s, ok := ch.backoffs[id]
+	if !ok {
+		s = new(nonceBackoffState)
+		ch.backoffs[id] = s
+	}
+	if !s.until.IsZero() {
+		return
+	}
+	s.cleared = append(slices.DeleteFunc(s.cleared, func(t time.Time) bool {
+		return now.Sub(t) >= ch.nonceBackoff.window
+	}), now)
+	if len(s.cleared) < ch.nonceBackoff.clears {
+		return
+	}
+
+	// the peers left for the nonce generation with this cosigner must still make the threshold.
+	left := 0
+	for _, c := range ch.cosigners {
+		b, backedOff := ch.backoffs[c.GetID()]
+		_, quarantined := ch.quarantined[c.GetID()]
+		if c.GetID() != id && !quarantined && (!backedOff || b.until.IsZero()) {
+			left++
+		}
+	}
+	if left < ch.threshold-1 {
+		ch.logger.Debug("Not backing off cosigner, too few cosigners would be left for nonce generation",
+			"cosigner", id)
+		return
+	}
+
+	// a cosigner backed off again soon after it was included again backs off for longer.
+	if !s.included.IsZero() && now.Sub(s.included) < ch.nonceBackoff.maxBackoff {
+		s.backoff = ch.nextNonceBackoff(s.backoff)
+	} else {
+		s.backoff = ch.nonceBackoff.minBackoff
+	}
+	s.until = now.Add(s.backoff)
+	s.cleared = nil
+
+	peerID := strconv.Itoa(id)
+	totalNonceCacheBackoffs.WithLabelValues(peerID).Inc()
+	nonceCacheBackedOff.WithLabelValues(peerID).Set(1)
+	ch.logger.Error("Backed off cosigner from nonce generation after its nonces were cleared repeatedly",
+		"cosigner", id, "backoff", s.backoff)
+}
+
+// nextNonceBackoff returns the backoff following d. ch.mu must be held.
+func (ch *CosignerHealth) nextNonceBackoff(d time.Duration) time.Duration {
+	return min(2*d, ch.nonceBackoff.maxBackoff)
+}
+
+// BackedOff returns true if the cosigner is backed off from the nonce generation.
+func (ch *CosignerHealth) BackedOff(cosigner Cosigner) bool {
+	ch.mu.RLock()
+	defer ch.mu.RUnlock()
+	s, ok := ch.backoffs[cosigner.GetID()]
+	return ok && !s.until.IsZero()
+}
+
+// updateNonceBackoff includes the backed off cosigners whose backoff passed at now again if their
+// last ping succeeded, and backs them off for longer otherwise.
+func (ch *CosignerHealth) updateNonceBackoff(now time.Time) {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	for id, s := range ch.backoffs {
+		if s.until.IsZero() || now.Before(s.until) {
+			continue
+		}
+		if rtt, ok := ch.rtt[id]; !ok || rtt == -1 {
+			s.backoff = ch.nextNonceBackoff(s.backoff)
+			s.until = now.Add(s.backoff)
+			ch.logger.Debug("Cosigner still unreachable, extending its nonce generation backoff",
+				"cosigner", id, "backoff", s.backoff)
+			continue
+		}
+		s.until = time.Time{}
+		s.included = now
+		nonceCacheBackedOff.WithLabelValues(strconv.Itoa(id)).Set(0)
+		ch.logger.Info("Included cosigner in nonce generation again after its backoff", "cosigner", id)
+	}
+}
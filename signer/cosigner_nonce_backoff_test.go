@@ -0,0 +1,102 @@
+package signer
+
+import (
+	"testing"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonceCacheBackoffConfig(t *testing.T) {
+	def := nonceBackoff{clears: 3, window: time.Minute, minBackoff: time.Second, maxBackoff: time.Minute}
+	b, err := (*NonceCacheBackoffConfig)(nil).params(def)
+	require.NoError(t, err)
+	require.Equal(t, def, b)
+
+	b, err = (&NonceCacheBackoffConfig{Clears: 5, MaxBackoff: "10m"}).params(def)
+	require.NoError(t, err)
+	require.Equal(t, nonceBackoff{
+		clears:     5,
+		window:     time.Minute,
+		minBackoff: time.Second,
+		maxBackoff: 10 * time.Minute,
+	}, b)
+
+	b, err = (&NonceCacheBackoffConfig{Disabled: true}).params(def)
+	require.NoError(t, err)
+	require.True(t, b.disabled)
+
+	for _, cfg := range []*NonceCacheBackoffConfig{
+		{Clears: -1},
+		{Window: "0s"},
+		{MinBackoff: "soon"},
+		{MinBackoff: "2m"},
+	} {
+		_, err := cfg.params(def)
+		require.Error(t, err)
+	}
+}
+
+func TestNonceCacheBackoff(t *testing.T) {
+	c2, c3, c4 := &RemoteCosigner{id: 2}, &RemoteCosigner{id: 3}, &RemoteCosigner{id: 4}
+	ch := NewCosignerHealth(cometlog.NewNopLogger(), 1, []Cosigner{c2, c3, c4}, &MockLeader{id: 1})
+	ch.SetNonceBackoff(nonceBackoff{
+		clears:     3,
+		window:     30 * time.Second,
+		minBackoff: 10 * time.Second,
+		maxBackoff: time.Minute,
+	}, 3)
+
+	now := time.Now()
+
+	// clears spread beyond the window do not back off the cosigner.
+	ch.RecordNoncesCleared(c2, now)
+	ch.RecordNoncesCleared(c2, now.Add(20*time.Second))
+	ch.RecordNoncesCleared(c2, now.Add(40*time.Second))
+	require.False(t, ch.BackedOff(c2))
+
+	// repeated clears within the window do.
+	now = now.Add(45 * time.Second)
+	ch.RecordNoncesCleared(c2, now)
+	require.True(t, ch.BackedOff(c2))
+
+	// the last peers are not backed off, since the cosigners would be fewer than the threshold.
+	for i := 0; i < 3; i++ {
+		ch.RecordNoncesCleared(c3, now)
+	}
+	require.False(t, ch.BackedOff(c3))
+
+	// the cosigner is probed once its backoff passed, and backed off for longer while unreachable.
+	ch.rtt = map[int]int64{2: -1, 3: 100, 4: 100}
+	ch.updateNonceBackoff(now.Add(5 * time.Second))
+	require.True(t, ch.BackedOff(c2))
+	ch.updateNonceBackoff(now.Add(10 * time.Second))
+	require.True(t, ch.BackedOff(c2))
+	ch.rtt[2] = 100
+	ch.updateNonceBackoff(now.Add(25 * time.Second))
+	require.True(t, ch.BackedOff(c2))
+
+	// it is included again once it answers its ping after its backoff.
+	now = now.Add(30 * time.Second)
+	ch.updateNonceBackoff(now)
+	require.False(t, ch.BackedOff(c2))
+
+	// backed off again soon after, it backs off for longer.
+	for i := 0; i < 3; i++ {
+		ch.RecordNoncesCleared(c2, now)
+	}
+	require.True(t, ch.BackedOff(c2))
+	ch.updateNonceBackoff(now.Add(30 * time.Second))
+	require.True(t, ch.BackedOff(c2))
+	ch.updateNonceBackoff(now.Add(40 * time.Second))
+	require.False(t, ch.BackedOff(c2))
+
+	// a removed cosigner is forgotten.
+	for i := 0; i < 3; i++ {
+		ch.RecordNoncesCleared(c2, now.Add(40*time.Second))
+	}
+	require.True(t, ch.BackedOff(c2))
+	ch.SetCosigners([]Cosigner{c3, c4})
+	require.False(t, ch.BackedOff(c2))
+}
@@ -2,7 +2,9 @@ package signer
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -15,20 +17,314 @@ const (
 	defaultGetNoncesInterval = 3 * time.Second
 	defaultGetNoncesTimeout  = 4 * time.Second
 	defaultNonceExpiration   = 10 * time.Second // half of the local cosigner cache expiration
+
+	// defaultNonceCacheTargetMultiplier is the share of the demand over an interval that the
+	// cache keeps ready, so that it keeps up with demand that rises between two reconciliations.
+	defaultNonceCacheTargetMultiplier = 1.2
+
+	// defaultNonceCacheWarmupMinFill is the fill ratio the nonce cache of the leader must reach once
+	// before the leader is ready.
+	defaultNonceCacheWarmupMinFill = 0.5
+	defaultNonceCacheWarmupTimeout = 30 * time.Second
+
+	// defaultNonceCacheQueueDepth is the number of sign requests that wait for nonces loaded on
+	// demand at a time, and defaultNonceCacheWaitDeadline how long they wait, which leaves time to
+	// fetch nonces while signing if none are loaded.
+	defaultNonceCacheQueueDepth   = 32
+	defaultNonceCacheWaitDeadline = time.Second
+
+	// defaultNonceCacheQuorumGrace is how long the nonces of the cosigners beyond the first quorum
+	// are waited for, so that the signing can fail over to them.
+	defaultNonceCacheQuorumGrace = 50 * time.Millisecond
 )
 
+// NonceCacheConfig is the on disk config format for tuning the nonce cache of the leader, which
+// keeps nonces of the cosigners ready so that signing does not wait for them. The defaults suit
+// chains with block times of a few seconds.
+type NonceCacheConfig struct {
+	// TargetMultiplier scales the nonces kept ready for the demand over a reconcile interval.
+	// Defaults to 1.2.
+	TargetMultiplier float64 `yaml:"targetMultiplier,omitempty"`
+
+	// Expiration is the age after which cached nonces are discarded. It must be below the 20s
+	// after which the cosigners discard their nonces. Defaults to 10s.
+	Expiration string `yaml:"expiration,omitempty"`
+
+	// ReconcileInterval is how often expired nonces are pruned and the cache is refilled to meet
+	// demand. Defaults to 3s.
+	ReconcileInterval string `yaml:"reconcileInterval,omitempty"`
+
+	// ExpirationJitter expires each cached nonce up to the jitter before the expiration, so that the
+	// nonces loaded together are not all pruned at once. Defaults to 0.
+	ExpirationJitter string `yaml:"expirationJitter,omitempty"`
+
+	// Pruning is how the cache is pruned: expiration prunes the expired nonces, lru also prunes the
+	// least recently loaded nonces above the target. Defaults to expiration.
+	Pruning string `yaml:"pruning,omitempty"`
+
+	// FetchTimeout is the timeout of the requests for nonces to each cosigner. Defaults to 4s.
+	FetchTimeout string `yaml:"fetchTimeout,omitempty"`
+
+	// QuorumGrace is how long the nonces of the other cosigners are waited for once threshold
+	// cosigners, including this one, responded, so that a slow cosigner does not delay the refills.
+	// Defaults to 50ms.
+	QuorumGrace string `yaml:"quorumGrace,omitempty"`
+
+	// Warmup is the fill the nonce cache of a freshly started leader must reach before the leader
+	// is ready.
+	Warmup *NonceCacheWarmupConfig `yaml:"warmup,omitempty"`
+
+	// OnDemand is how sign requests that find no cached nonces wait for nonces loaded on demand.
+	OnDemand *NonceCacheOnDemandConfig `yaml:"onDemand,omitempty"`
+
+	// Burst is how the cache is refilled while it is empty with sign requests waiting for nonces.
+	Burst *NonceCacheBurstConfig `yaml:"burst,omitempty"`
+
+	// Push pushes the cached nonces of the leader to the followers, which inherit them once promoted.
+	Push *NonceCachePushConfig `yaml:"push,omitempty"`
+
+	// Backoff leaves the cosigners whose nonces are cleared repeatedly out of the nonce generation.
+	Backoff *NonceCacheBackoffConfig `yaml:"backoff,omitempty"`
+}
+
+// NonceCacheOnDemandConfig is the on disk config format of the nonces loaded on demand. A sign
+// request that finds no cached nonces, e.g. right after a leadership change, is queued and the
+// nonces of the queued requests are loaded at once. A request that is not served until the wait
+// deadline, or finds the queue full, fetches nonces from the cosigners on its own.
+type NonceCacheOnDemandConfig struct {
+	// QueueDepth is the number of sign requests that wait at a time. Defaults to 32.
+	QueueDepth int `yaml:"queueDepth,omitempty"`
+
+	// WaitDeadline is how long a sign request waits. Defaults to 1s.
+	WaitDeadline string `yaml:"waitDeadline,omitempty"`
+}
+
+// NonceCacheWarmupConfig is the on disk config format of the warm-up of the nonce cache, which keeps
+// a freshly started leader from failing its first sign rounds for lack of nonces.
+type NonceCacheWarmupConfig struct {
+	// MinFill is the share of its target size the nonce cache must fill. Defaults to 0.5.
+	MinFill float64 `yaml:"minFill,omitempty"`
+
+	// MinNonces is the number of nonces the nonce cache must hold, in addition to MinFill.
+	MinNonces int `yaml:"minNonces,omitempty"`
+
+	// HoldChainNodes holds the connections to the chain nodes until the nonce cache warmed up, so
+	// that the chain nodes sign with another cluster or wait instead of timing out.
+	HoldChainNodes bool `yaml:"holdChainNodes,omitempty"`
+
+	// Timeout is how long the connections to the chain nodes are held at most. Defaults to 30s.
+	Timeout string `yaml:"timeout,omitempty"`
+}
+
+// nonceCacheParams are the parameters of the nonce cache.
+type nonceCacheParams struct {
+	targetMultiplier  float64
+	expiration        time.Duration
+	reconcileInterval time.Duration
+	fetchTimeout      time.Duration
+	quorumGrace       time.Duration
+	warmup            nonceCacheWarmup
+	onDemand          nonceCacheOnDemand
+	burst             nonceCacheBurst
+	backoff           nonceBackoff
+	// pushInterval is how often the cache is pushed to the followers, 0 if it is not.
+	pushInterval     time.Duration
+	expirationJitter time.Duration
+	lruPruning       bool
+}
+
+// nonceCacheOnDemand is how sign requests without cached nonces wait for nonces loaded on demand.
+type nonceCacheOnDemand struct {
+	queueDepth   int
+	waitDeadline time.Duration
+}
+
+// nonceCacheWarmup is the fill the nonce cache must reach once before the leader is ready.
+type nonceCacheWarmup struct {
+	minFill        float64
+	minNonces      int
+	holdChainNodes bool
+	timeout        time.Duration
+}
+
+// warm returns true if the nonce cache reached the fill.
+func (w nonceCacheWarmup) warm(health NonceCacheHealth) bool {
+	return health.TargetSize > 0 && health.FillRatio >= w.minFill && health.Size >= w.minNonces
+}
+
+func (cfg *NonceCacheConfig) Validate() error {
+	_, err := cfg.params()
+	return err
+}
+
+// params returns the parameters of the nonce cache, the defaults without a config.
+func (cfg *NonceCacheConfig) params() (nonceCacheParams, error) {
+	p := nonceCacheParams{
+		targetMultiplier:  defaultNonceCacheTargetMultiplier,
+		expiration:        defaultNonceExpiration,
+		reconcileInterval: defaultGetNoncesInterval,
+		fetchTimeout:      defaultGetNoncesTimeout,
+		quorumGrace:       defaultNonceCacheQuorumGrace,
+		warmup: nonceCacheWarmup{
+			minFill: defaultNonceCacheWarmupMinFill,
+			timeout: defaultNonceCacheWarmupTimeout,
+		},
+		onDemand: nonceCacheOnDemand{
+			queueDepth:   defaultNonceCacheQueueDepth,
+			waitDeadline: defaultNonceCacheWaitDeadline,
+		},
+		burst: nonceCacheBurst{
+			concurrency: defaultNonceCacheBurstConcurrency,
+			batchSize:   defaultNonceCacheBurstBatchSize,
+			interval:    defaultNonceCacheBurstInterval,
+			timeout:     defaultNonceCacheBurstTimeout,
+		},
+		backoff: nonceBackoff{
+			clears:     defaultNonceBackoffClears,
+			window:     defaultNonceBackoffWindow,
+			minBackoff: defaultNonceBackoffMinBackoff,
+			maxBackoff: defaultNonceBackoffMaxBackoff,
+		},
+	}
+	if cfg == nil {
+		return p, nil
+	}
+	if cfg.TargetMultiplier != 0 {
+		p.targetMultiplier = cfg.TargetMultiplier
+	}
+	if p.targetMultiplier < 1 {
+		return nonceCacheParams{}, fmt.Errorf("nonce cache targetMultiplier (%v) must be at least 1", p.targetMultiplier)
+	}
+	var err error
+	if p.expiration, err = parseDurationOrDefault(cfg.Expiration, p.expiration); err != nil {
+		return nonceCacheParams{}, fmt.Errorf("invalid nonce cache expiration: %w", err)
+	}
+	if p.reconcileInterval, err = parseDurationOrDefault(cfg.ReconcileInterval, p.reconcileInterval); err != nil {
+		return nonceCacheParams{}, fmt.Errorf("invalid nonce cache reconcileInterval: %w", err)
+	}
+	if p.fetchTimeout, err = parseDurationOrDefault(cfg.FetchTimeout, p.fetchTimeout); err != nil {
+		return nonceCacheParams{}, fmt.Errorf("invalid nonce cache fetchTimeout: %w", err)
+	}
+	if p.quorumGrace, err = parseDurationOrDefault(cfg.QuorumGrace, p.quorumGrace); err != nil {
+		return nonceCacheParams{}, fmt.Errorf("invalid nonce cache quorumGrace: %w", err)
+	}
+	if p.quorumGrace < 0 {
+		return nonceCacheParams{}, fmt.Errorf("nonce cache quorumGrace (%s) cannot be negative", p.quorumGrace)
+	}
+	if p.expiration <= 0 || p.reconcileInterval <= 0 || p.fetchTimeout <= 0 {
+		return nonceCacheParams{}, fmt.Errorf(
+			"nonce cache expiration (%s), reconcileInterval (%s) and fetchTimeout (%s) must be positive",
+			p.expiration, p.reconcileInterval, p.fetchTimeout,
+		)
+	}
+	if p.expiration >= nonceExpiration {
+		return nonceCacheParams{}, fmt.Errorf(
+			"nonce cache expiration (%s) must be below the expiration of the nonces on the cosigners (%s)",
+			p.expiration, nonceExpiration,
+		)
+	}
+	if p.lruPruning, p.expirationJitter, err = nonceCachePruningParams(
+		cfg.Pruning, cfg.ExpirationJitter, p.expiration,
+	); err != nil {
+		return nonceCacheParams{}, err
+	}
+	if p.warmup, err = cfg.Warmup.params(p.warmup); err != nil {
+		return nonceCacheParams{}, err
+	}
+	if p.onDemand, err = cfg.OnDemand.params(p.onDemand); err != nil {
+		return nonceCacheParams{}, err
+	}
+	if p.burst, err = cfg.Burst.params(p.burst); err != nil {
+		return nonceCacheParams{}, err
+	}
+	if cfg.Push != nil {
+		if p.pushInterval, err = cfg.Push.params(); err != nil {
+			return nonceCacheParams{}, err
+		}
+	}
+	if p.backoff, err = cfg.Backoff.params(p.backoff); err != nil {
+		return nonceCacheParams{}, err
+	}
+	return p, nil
+}
+
+// params returns the on demand loading of the config, the defaults of def without a config.
+func (cfg *NonceCacheOnDemandConfig) params(def nonceCacheOnDemand) (nonceCacheOnDemand, error) {
+	if cfg == nil {
+		return def, nil
+	}
+	d := def
+	if cfg.QueueDepth < 0 {
+		return nonceCacheOnDemand{}, fmt.Errorf("nonce cache onDemand queueDepth (%d) cannot be negative", cfg.QueueDepth)
+	}
+	if cfg.QueueDepth != 0 {
+		d.queueDepth = cfg.QueueDepth
+	}
+	var err error
+	if d.waitDeadline, err = parseDurationOrDefault(cfg.WaitDeadline, d.waitDeadline); err != nil {
+		return nonceCacheOnDemand{}, fmt.Errorf("invalid nonce cache onDemand waitDeadline: %w", err)
+	}
+	if d.waitDeadline <= 0 {
+		return nonceCacheOnDemand{}, fmt.Errorf("nonce cache onDemand waitDeadline (%s) must be positive", d.waitDeadline)
+	}
+	return d, nil
+}
+
+// params returns the warm-up of the config, the defaults of def without a config.
+func (cfg *NonceCacheWarmupConfig) params(def nonceCacheWarmup) (nonceCacheWarmup, error) {
+	if cfg == nil {
+		return def, nil
+	}
+	w := def
+	if cfg.MinFill != 0 {
+		w.minFill = cfg.MinFill
+	}
+	if w.minFill < 0 || w.minFill > 1 {
+		return nonceCacheWarmup{}, fmt.Errorf("nonce cache warmup minFill (%v) must be between 0 and 1", w.minFill)
+	}
+	if cfg.MinNonces < 0 {
+		return nonceCacheWarmup{}, fmt.Errorf("nonce cache warmup minNonces (%d) cannot be negative", cfg.MinNonces)
+	}
+	w.minNonces = cfg.MinNonces
+	w.holdChainNodes = cfg.HoldChainNodes
+	var err error
+	if w.timeout, err = parseDurationOrDefault(cfg.Timeout, w.timeout); err != nil {
+		return nonceCacheWarmup{}, fmt.Errorf("invalid nonce cache warmup timeout: %w", err)
+	}
+	if w.timeout <= 0 {
+		return nonceCacheWarmup{}, fmt.Errorf("nonce cache warmup timeout (%s) must be positive", w.timeout)
+	}
+	return w, nil
+}
+
 type CosignerNonceCache struct {
-	logger    cometlog.Logger
-	cosigners []Cosigner
+	logger      cometlog.Logger
+	cosigners   []Cosigner
+	cosignersMu sync.RWMutex
 
 	leader Leader
 
+	// health records the latency and errors of the nonce requests to the peers, if set.
+	health *CosignerHealth
+
 	lastReconcileNonces atomic.Uint64
 	lastReconcileTime   time.Time
 
+	// lastLoop is the time in unix nanoseconds the loop of Start last ran.
+	lastLoop atomic.Int64
+
+	// targetSize is the number of nonces the cache keeps ready, as of the last reconciliation.
+	targetSize atomic.Int64
+
 	getNoncesInterval time.Duration
 	getNoncesTimeout  time.Duration
 	nonceExpiration   time.Duration
+	// expirationJitter is how much earlier than the expiration each nonce expires at most.
+	expirationJitter time.Duration
+
+	// quorumGrace is how long the nonces of the other cosigners are waited for once a quorum of
+	// cosigners responded.
+	quorumGrace time.Duration
 
 	threshold uint8
 
@@ -38,6 +334,36 @@ type CosignerNonceCache struct {
 
 	movingAverage *movingAverage
 
+	// stats are the demand statistics as of the last reconciliation as the leader, saved on shutdown.
+	stats atomic.Pointer[nonceCacheStats]
+
+	// controller sizes the cache for the moving average of the demand.
+	controller *nonceCacheController
+
+	// warmup is the fill the cache must reach once before the leader is ready.
+	warmup nonceCacheWarmup
+
+	// burst is how an empty cache with demand is refilled, and burstStart when the cache started to
+	// be refilled in bursts, zero while it is refilled at the steady pace.
+	burst      nonceCacheBurst
+	burstStart time.Time
+	// starved is set once a sign request found the cache empty.
+	starved atomic.Bool
+
+	// push are the changes of the cache to push to the followers, nil unless they are pushed.
+	push *noncePush
+
+	// onDemand is how sign requests without cached nonces wait for nonces loaded on demand.
+	onDemand nonceCacheOnDemand
+	// queued is the number of sign requests waiting for nonces loaded on demand.
+	queued atomic.Int32
+	// demand triggers loading nonces for the queued sign requests.
+	demand chan struct{}
+
+	// loaded is closed, and replaced, once nonces are loaded.
+	loadedMu sync.Mutex
+	loaded   chan struct{}
+
 	empty chan struct{}
 }
 
@@ -115,25 +441,17 @@ func (nc *NonceCache) Delete(index int) {
 func (nc *NonceCache) PruneNonces() int {
 	nc.mu.Lock()
 	defer nc.mu.Unlock()
-	nonExpiredIndex := -1
-	for i := 0; i < len(nc.cache); i++ {
-		if time.Now().Before(nc.cache[i].Expiration) {
-			nonExpiredIndex = i
-			break
-		}
-	}
-
-	var deleteCount int
-	if nonExpiredIndex == -1 {
+	now := time.Now()
+	n := len(nc.cache)
+	// the expirations are jittered, so the expired nonces are not only at the front of the cache.
+	nc.cache = slices.DeleteFunc(nc.cache, func(cn *CachedNonce) bool {
+		return !now.Before(cn.Expiration)
+	})
+	if len(nc.cache) == 0 {
 		// No non-expired nonces, delete everything
-		deleteCount = len(nc.cache)
 		nc.cache = nil
-	} else {
-		// Prune everything up to the non-expired nonce
-		deleteCount = nonExpiredIndex
-		nc.cache = nc.cache[nonExpiredIndex:]
 	}
-	return deleteCount
+	return n - len(nc.cache)
 }
 
 type CosignerNoncesRel struct {
@@ -174,12 +492,30 @@ func NewCosignerNonceCache(
 		getNoncesInterval: getNoncesInterval,
 		getNoncesTimeout:  getNoncesTimeout,
 		nonceExpiration:   nonceExpiration,
+		quorumGrace:       defaultNonceCacheQuorumGrace,
 		threshold:         threshold,
 		pruner:            pruner,
 		cache:             new(NonceCache),
 		// buffer up to 1000 empty events so that we don't ever block
 		empty:         make(chan struct{}, 1000),
 		movingAverage: newMovingAverage(4 * getNoncesInterval), // weighted average over 4 intervals
+		controller:    newNonceCacheController(getNoncesInterval, defaultNonceCacheTargetMultiplier),
+		warmup: nonceCacheWarmup{
+			minFill: defaultNonceCacheWarmupMinFill,
+			timeout: defaultNonceCacheWarmupTimeout,
+		},
+		onDemand: nonceCacheOnDemand{
+			queueDepth:   defaultNonceCacheQueueDepth,
+			waitDeadline: defaultNonceCacheWaitDeadline,
+		},
+		burst: nonceCacheBurst{
+			concurrency: defaultNonceCacheBurstConcurrency,
+			batchSize:   defaultNonceCacheBurstBatchSize,
+			interval:    defaultNonceCacheBurstInterval,
+			timeout:     defaultNonceCacheBurstTimeout,
+		},
+		demand: make(chan struct{}, 1),
+		loaded: make(chan struct{}),
 	}
 	// the only time pruner is expected to be non-nil is during tests, otherwise we use the cache logic.
 	if pruner == nil {
@@ -197,8 +533,10 @@ func (cnc *CosignerNonceCache) getUuids(n int) []uuid.UUID {
 	return uuids
 }
 
+// target returns the number of nonces to keep ready for the demand, without the correction of the
+// controller.
 func (cnc *CosignerNonceCache) target(noncesPerMinute float64) int {
-	t := int((noncesPerMinute / 60) * ((cnc.getNoncesInterval.Seconds() * 1.2) + 0.5))
+	t := int(cnc.controller.feedForward(noncesPerMinute))
 	if t <= 0 {
 		return 1 // always target at least one nonce ready
 	}
@@ -208,11 +546,18 @@ func (cnc *CosignerNonceCache) target(noncesPerMinute float64) int {
 func (cnc *CosignerNonceCache) reconcile(ctx context.Context) {
 	// prune expired nonces
 	pruned := cnc.pruner.PruneNonces()
+	nonceCachePruned.Set(float64(pruned))
+	totalNonceCacheExpired.Add(float64(pruned))
+
+	remainingNonces := cnc.cache.Size()
+	nonceCacheSize.Set(float64(remainingNonces))
 
 	if !cnc.leader.IsLeader() {
+		if !cnc.burstStart.IsZero() {
+			cnc.endBurst()
+		}
 		return
 	}
-	remainingNonces := cnc.cache.Size()
 	timeSinceLastReconcile := time.Since(cnc.lastReconcileTime)
 
 	lastReconcileNonces := cnc.lastReconcileNonces.Load()
@@ -224,15 +569,31 @@ func (cnc *CosignerNonceCache) reconcile(ctx context.Context) {
 
 	cnc.movingAverage.add(timeSinceLastReconcile, noncesPerMin)
 
-	// calculate how many nonces we need to load to keep up with demand
-	// load 120% the number of nonces we need to keep up with demand,
-	// plus a couple seconds worth of nonces to account for nonce consumption during LoadN
-	// plus 10 for padding
-
+	// calculate how many nonces we need to load to keep up with demand: the demand over an interval
+	// times the target multiplier, plus the demand while the nonces are loaded, corrected by the
+	// shortfall of the nonces left since the last reconciliation.
 	avgNoncesPerMin := cnc.movingAverage.average()
-	t := cnc.target(avgNoncesPerMin)
+	bursting := cnc.startBurst(remainingNonces)
+	var (
+		t          int
+		correction float64
+	)
+	if bursting {
+		// the controller is tuned to the steady reconcile interval, so a burst refills the cache to
+		// the demand alone, and at least to a batch.
+		t = max(cnc.target(avgNoncesPerMin), cnc.burst.batchSize)
+		defer cnc.stopBurst(t)
+	} else {
+		t, correction = cnc.controller.target(avgNoncesPerMin, remainingNonces, pruned)
+	}
 	additional := t - remainingNonces
 
+	cnc.targetSize.Store(int64(t))
+	cnc.updateStats(t)
+	nonceCacheDemand.Set(avgNoncesPerMin)
+	nonceCacheTargetSize.Set(float64(t))
+	nonceCacheTargetCorrection.Set(correction)
+
 	defer func() {
 		cnc.lastReconcileNonces.Store(uint64(remainingNonces + additional))
 		cnc.lastReconcileTime = time.Now()
@@ -247,6 +608,7 @@ func (cnc *CosignerNonceCache) reconcile(ctx context.Context) {
 			"remaining", remainingNonces,
 			"nonces_per_min", noncesPerMin,
 			"avg_nonces_per_min", avgNoncesPerMin,
+			"correction", correction,
 		)
 		return
 	}
@@ -258,56 +620,154 @@ func (cnc *CosignerNonceCache) reconcile(ctx context.Context) {
 		"additional", additional,
 		"nonces_per_min", noncesPerMin,
 		"avg_nonces_per_min", avgNoncesPerMin,
+		"correction", correction,
+		"burst", bursting,
 	)
 
+	if bursting {
+		cnc.loadBurst(ctx, additional)
+		return
+	}
+
+	start := time.Now()
 	cnc.LoadN(ctx, additional)
+	cnc.controller.observeRefill(time.Since(start))
+	nonceCacheRefillLatency.Set(cnc.controller.latency.Seconds())
+}
+
+// Size returns the number of nonces ready in the cache and the number of nonces
+// the cache keeps ready to meet demand, which is 0 unless this cosigner is the leader.
+func (cnc *CosignerNonceCache) Size() (size int, target int) {
+	return cnc.cache.Size(), int(cnc.targetSize.Load())
+}
+
+// NonceCacheInspection summarizes the nonces ready in the nonce cache.
+type NonceCacheInspection struct {
+	Size       int `json:"size"`
+	TargetSize int `json:"target_size"`
+
+	// Cosigners is the number of cached nonces that include nonces of each cosigner, by shard ID.
+	Cosigners map[int]int `json:"cosigners"`
+
+	// NextExpiration is when the cached nonce that expires first expires, if any.
+	NextExpiration *time.Time `json:"next_expiration,omitempty"`
+}
+
+// Inspect returns a summary of the nonces ready in the cache.
+func (cnc *CosignerNonceCache) Inspect() NonceCacheInspection {
+	cnc.cache.mu.RLock()
+	defer cnc.cache.mu.RUnlock()
+
+	inspection := NonceCacheInspection{
+		Size:       len(cnc.cache.cache),
+		TargetSize: int(cnc.targetSize.Load()),
+		Cosigners:  make(map[int]int),
+	}
+	for _, cn := range cnc.cache.cache {
+		for _, n := range cn.Nonces {
+			inspection.Cosigners[n.Cosigner.GetID()]++
+		}
+		if inspection.NextExpiration == nil || cn.Expiration.Before(*inspection.NextExpiration) {
+			expiration := cn.Expiration
+			inspection.NextExpiration = &expiration
+		}
+	}
+	return inspection
 }
 
 func (cnc *CosignerNonceCache) LoadN(ctx context.Context, n int) {
+	cnc.loadN(ctx, n)
+}
+
+// loadN loads n sets of nonces from the cosigners and returns the number of sets added to the cache.
+func (cnc *CosignerNonceCache) loadN(ctx context.Context, n int) int {
 	if n == 0 {
-		return
+		return 0
 	}
+	defer cnc.broadcastLoaded()
 	uuids := cnc.getUuids(n)
-	nonces := make([]*CachedNonceSingle, len(cnc.cosigners))
-	var wg sync.WaitGroup
-	wg.Add(len(cnc.cosigners))
+	cnc.cosignersMu.RLock()
+	cosigners := cnc.cosigners
+	cnc.cosignersMu.RUnlock()
+
+	// the requests are hedged: the nonces are loaded once threshold cosigners, including this one,
+	// responded and the others did not respond within the quorum grace, so that a slow cosigner
+	// does not delay the refill.
+	var mu sync.Mutex
+	nonces := make([]*CachedNonceSingle, len(cosigners))
+	collected := false
+	responded := make(chan struct{}, len(cosigners))
 
 	expiration := time.Now().Add(cnc.nonceExpiration)
 
-	for i, p := range cnc.cosigners {
+	for i, p := range cosigners {
 		i := i
 		p := p
 		go func() {
-			defer wg.Done()
+			defer func() { responded <- struct{}{} }()
+			if cnc.health != nil && (cnc.health.Excluded(p) || cnc.health.BackedOff(p)) {
+				return
+			}
 			ctx, cancel := context.WithTimeout(ctx, cnc.getNoncesTimeout)
 			defer cancel()
 
 			peerStartTime := time.Now()
 			n, err := p.GetNonces(ctx, uuids)
+			if cnc.health != nil {
+				cnc.health.RecordResult(p, time.Since(peerStartTime), err)
+			}
 			if err != nil {
 				// Significant missing shares may lead to signature failure
 				missedNonces.WithLabelValues(p.GetAddress()).Add(float64(1))
 				totalMissedNonces.WithLabelValues(p.GetAddress()).Inc()
 
-				cnc.logger.Error("Failed to get nonces from peer", "peer", p.GetID(), "error", err)
+				cnc.logger.Error("Failed to get nonces from peer", "cosigner", p.GetID(), "error", err)
 				return
 			}
 
 			missedNonces.WithLabelValues(p.GetAddress()).Set(0)
 			timedCosignerNonceLag.WithLabelValues(p.GetAddress()).Observe(time.Since(peerStartTime).Seconds())
 
+			mu.Lock()
+			defer mu.Unlock()
+			if collected {
+				totalNonceCacheLateCosigners.WithLabelValues(p.GetAddress()).Inc()
+				return
+			}
 			nonces[i] = &CachedNonceSingle{
 				Cosigner: p,
 				Nonces:   n,
 			}
 		}()
 	}
-	wg.Wait()
+
+	var grace <-chan time.Time
+WaitLoop:
+	for pending := len(cosigners); pending > 0; {
+		select {
+		case <-responded:
+			pending--
+			mu.Lock()
+			quorum := cnc.quorum(cosigners, nonces)
+			mu.Unlock()
+			if grace == nil && quorum {
+				timer := time.NewTimer(cnc.quorumGrace)
+				defer timer.Stop()
+				grace = timer.C
+			}
+		case <-grace:
+			break WaitLoop
+		}
+	}
+	mu.Lock()
+	collected = true
+	mu.Unlock()
+
 	added := 0
 	for i, u := range uuids {
 		nonce := CachedNonce{
 			UUID:       u,
-			Expiration: expiration,
+			Expiration: cnc.jitteredExpiration(expiration),
 		}
 		num := uint8(0)
 		for _, n := range nonces {
@@ -321,17 +781,67 @@ func (cnc *CosignerNonceCache) LoadN(ctx context.Context, n int) {
 			})
 		}
 		if num >= cnc.threshold {
+			cnc.pushAdded(&nonce)
 			cnc.cache.Add(&nonce)
 			added++
 		}
 	}
+	nonceCacheSize.Set(float64(cnc.cache.Size()))
 	cnc.logger.Debug("Loaded nonces", "desired", n, "added", added)
+	return added
+}
+
+// quorum returns true if threshold cosigners, including the local cosigner, responded with nonces.
+func (cnc *CosignerNonceCache) quorum(cosigners []Cosigner, nonces []*CachedNonceSingle) bool {
+	count := 0
+	for i, n := range nonces {
+		if n != nil {
+			count++
+		} else if _, ok := cosigners[i].(*LocalCosigner); ok {
+			return false
+		}
+	}
+	return count >= int(cnc.threshold)
+}
+
+// broadcastLoaded wakes up the sign requests waiting for nonces to be loaded.
+func (cnc *CosignerNonceCache) broadcastLoaded() {
+	cnc.loadedMu.Lock()
+	defer cnc.loadedMu.Unlock()
+	close(cnc.loaded)
+	cnc.loaded = make(chan struct{})
+}
+
+// loadedSignal returns a channel that is closed once nonces are loaded next.
+func (cnc *CosignerNonceCache) loadedSignal() <-chan struct{} {
+	cnc.loadedMu.Lock()
+	defer cnc.loadedMu.Unlock()
+	return cnc.loaded
+}
+
+// loadDemand loads a set of nonces for each queued sign request.
+func (cnc *CosignerNonceCache) loadDemand(ctx context.Context) {
+	n := int(cnc.queued.Load())
+	if n <= 0 {
+		return
+	}
+	cnc.logger.Debug("Loading nonces on demand", "queued", n)
+	added := cnc.loadN(ctx, n)
+	// the nonces are used by the queued sign requests, which the demand of the next reconciliation
+	// accounts for.
+	cnc.lastReconcileNonces.Add(uint64(added))
 }
 
 func (cnc *CosignerNonceCache) Start(ctx context.Context) {
 	cnc.lastReconcileNonces.Store(uint64(cnc.cache.Size()))
 	cnc.lastReconcileTime = time.Now()
 
+	cnc.lastLoop.Store(time.Now().UnixNano())
+
+	if cnc.push != nil {
+		go cnc.startPush(ctx)
+	}
+
 	ticker := time.NewTimer(cnc.getNoncesInterval)
 	for {
 		select {
@@ -343,10 +853,23 @@ func (cnc *CosignerNonceCache) Start(ctx context.Context) {
 			for len(cnc.empty) > 0 {
 				<-cnc.empty
 			}
+		case <-cnc.demand:
+			cnc.loadDemand(ctx)
+			cnc.lastLoop.Store(time.Now().UnixNano())
+			continue
 		}
 		cnc.reconcile(ctx)
-		ticker.Reset(cnc.getNoncesInterval)
+		cnc.lastLoop.Store(time.Now().UnixNano())
+		ticker.Reset(cnc.reconcileInterval())
+	}
+}
+
+// LastLoop returns the time the loop of Start last ran, or the zero time if it was not started.
+func (cnc *CosignerNonceCache) LastLoop() time.Time {
+	if t := cnc.lastLoop.Load(); t != 0 {
+		return time.Unix(0, t)
 	}
+	return time.Time{}
 }
 
 func (cnc *CosignerNonceCache) GetNonces(fastestPeers []Cosigner) (*CosignerUUIDNonces, error) {
@@ -354,46 +877,229 @@ func (cnc *CosignerNonceCache) GetNonces(fastestPeers []Cosigner) (*CosignerUUID
 	defer cnc.cache.mu.Unlock()
 CheckNoncesLoop:
 	for i, cn := range cnc.cache.cache {
-		var nonces CosignerNonces
 		for _, p := range fastestPeers {
-			found := false
-			for _, n := range cn.Nonces {
-				if n.Cosigner.GetID() == p.GetID() {
-					found = true
-					nonces = append(nonces, n.Nonces...)
-					break
-				}
-			}
-			if !found {
+			if !cn.hasCosigner(p) {
 				// this set of nonces doesn't have the peer we need
 				continue CheckNoncesLoop
 			}
 		}
 
-		// remove this set of nonces from the cache
-		cnc.cache.Delete(i)
+		// all peers found
+		return cnc.take(i, fastestPeers), nil
+	}
 
-		if len(cnc.cache.cache) == 0 && len(cnc.empty) == 0 {
-			cnc.logger.Debug("Nonce cache is empty, triggering reload")
-			cnc.empty <- struct{}{}
+	return nil, cnc.noNoncesFound(fastestPeers)
+}
+
+// GetBestNonces returns the cached nonces for the local cosigner and the threshold-1 peers that rank
+// best of the ranked peers, along with the cosigners to sign with. If no cached set of nonces includes
+// the best ranked peers, the set whose peers rank best is used, so that the signing fails over to the
+// next ranked peers instead of fetching nonces from all cosigners.
+func (cnc *CosignerNonceCache) GetBestNonces(
+	myCosigner Cosigner,
+	rankedPeers []Cosigner,
+) (*CosignerUUIDNonces, []Cosigner, error) {
+	return cnc.GetBestNoncesMaxAge(myCosigner, rankedPeers, 0)
+}
+
+// GetBestNoncesMaxAge is GetBestNonces without the cached nonces fetched longer than maxAge ago,
+// which are left for other chains. A maxAge of 0 uses the cached nonces of any age.
+func (cnc *CosignerNonceCache) GetBestNoncesMaxAge(
+	myCosigner Cosigner,
+	rankedPeers []Cosigner,
+	maxAge time.Duration,
+) (*CosignerUUIDNonces, []Cosigner, error) {
+	cnc.cache.mu.Lock()
+	defer cnc.cache.mu.Unlock()
+
+	nonces, cosigners, ok := cnc.bestNonces(myCosigner, rankedPeers, maxAge)
+	if !ok {
+		cosigners := append([]Cosigner{myCosigner}, rankedPeers[:min(len(rankedPeers), int(cnc.threshold)-1)]...)
+		return nil, nil, cnc.noNoncesFound(cosigners)
+	}
+	return nonces, cosigners, nil
+}
+
+// WaitBestNonces is GetBestNoncesMaxAge that, if no cached nonces are found, queues the sign request
+// and loads nonces on demand until nonces are found, the wait deadline passes or ctx is done. If the
+// queue is full, the error of GetBestNoncesMaxAge is returned right away.
+func (cnc *CosignerNonceCache) WaitBestNonces(
+	ctx context.Context,
+	myCosigner Cosigner,
+	rankedPeers []Cosigner,
+	maxAge time.Duration,
+) (*CosignerUUIDNonces, []Cosigner, error) {
+	nonces, cosigners, err := cnc.GetBestNoncesMaxAge(myCosigner, rankedPeers, maxAge)
+	if err == nil {
+		return nonces, cosigners, nil
+	}
+
+	if int(cnc.queued.Add(1)) > cnc.onDemand.queueDepth {
+		cnc.queued.Add(-1)
+		totalNonceCacheOnDemandWaits.WithLabelValues("rejected").Inc()
+		return nil, nil, err
+	}
+	nonceCacheOnDemandQueue.Inc()
+	defer func() {
+		cnc.queued.Add(-1)
+		nonceCacheOnDemandQueue.Dec()
+	}()
+
+	deadline := time.NewTimer(cnc.onDemand.waitDeadline)
+	defer deadline.Stop()
+	for {
+		loaded := cnc.loadedSignal()
+		select {
+		case cnc.demand <- struct{}{}:
+		default:
+			// a load is triggered already.
 		}
 
-		// all peers found
-		return &CosignerUUIDNonces{
-			UUID:   cn.UUID,
-			Nonces: nonces,
-		}, nil
+		select {
+		case <-loaded:
+		case <-deadline.C:
+			totalNonceCacheOnDemandWaits.WithLabelValues("timeout").Inc()
+			return nil, nil, fmt.Errorf("%w: no nonces loaded on demand within %s", err, cnc.onDemand.waitDeadline)
+		case <-ctx.Done():
+			totalNonceCacheOnDemandWaits.WithLabelValues("canceled").Inc()
+			return nil, nil, errors.Join(err, ctx.Err())
+		}
+
+		cnc.cache.mu.Lock()
+		nonces, cosigners, ok := cnc.bestNonces(myCosigner, rankedPeers, maxAge)
+		cnc.cache.mu.Unlock()
+		if ok {
+			totalNonceCacheOnDemandWaits.WithLabelValues("served").Inc()
+			return nonces, cosigners, nil
+		}
 	}
+}
+
+// bestNonces returns the cached nonces of GetBestNoncesMaxAge, and false if none are found. The cache
+// must be locked.
+func (cnc *CosignerNonceCache) bestNonces(
+	myCosigner Cosigner,
+	rankedPeers []Cosigner,
+	maxAge time.Duration,
+) (*CosignerUUIDNonces, []Cosigner, bool) {
+	// the cost of a set of nonces is the sum of the ranks of its peers, so the best ranked peers cost
+	// 0 + 1 + ... + threshold-2.
+	minCost := (int(cnc.threshold) - 1) * (int(cnc.threshold) - 2) / 2
 
+	best := -1
+	var bestCosigners []Cosigner
+	bestCost := 0
+	for i, cn := range cnc.cache.cache {
+		if !cn.hasCosigner(myCosigner) {
+			continue
+		}
+		// a nonce with a jittered expiration counts as older than it is.
+		if maxAge > 0 && time.Since(cn.Expiration.Add(-cnc.nonceExpiration)) > maxAge {
+			continue
+		}
+		cosigners := []Cosigner{myCosigner}
+		cost := 0
+		for rank, p := range rankedPeers {
+			if len(cosigners) == int(cnc.threshold) {
+				break
+			}
+			if cn.hasCosigner(p) {
+				cosigners = append(cosigners, p)
+				cost += rank
+			}
+		}
+		if len(cosigners) < int(cnc.threshold) {
+			continue
+		}
+		if best == -1 || cost < bestCost {
+			best, bestCosigners, bestCost = i, cosigners, cost
+		}
+		if cost == minCost {
+			// no other set of nonces can include better ranked peers.
+			break
+		}
+	}
+
+	if best == -1 {
+		return nil, nil, false
+	}
+
+	return cnc.take(best, bestCosigners), bestCosigners, true
+}
+
+func (cn *CachedNonce) hasCosigner(cosigner Cosigner) bool {
+	for _, n := range cn.Nonces {
+		if n.Cosigner.GetID() == cosigner.GetID() {
+			return true
+		}
+	}
+	return false
+}
+
+// take removes the set of nonces at the index from the cache and returns the nonces of the cosigners.
+// The cache must be locked.
+func (cnc *CosignerNonceCache) take(index int, cosigners []Cosigner) *CosignerUUIDNonces {
+	cn := cnc.cache.cache[index]
+	var nonces CosignerNonces
+	for _, p := range cosigners {
+		for _, n := range cn.Nonces {
+			if n.Cosigner.GetID() == p.GetID() {
+				nonces = append(nonces, n.Nonces...)
+				break
+			}
+		}
+	}
+
+	// remove this set of nonces from the cache
+	cnc.cache.Delete(index)
+	cnc.pushRemoved(cn.UUID)
+	nonceCacheSize.Set(float64(len(cnc.cache.cache)))
+
+	if len(cnc.cache.cache) == 0 && len(cnc.empty) == 0 {
+		cnc.logger.Debug("Nonce cache is empty, triggering reload")
+		cnc.empty <- struct{}{}
+	}
+
+	return &CosignerUUIDNonces{
+		UUID:   cn.UUID,
+		Nonces: nonces,
+	}
+}
+
+// noNoncesFound records that no cached nonces involve the cosigners and returns the error.
+// The cache must be locked.
+func (cnc *CosignerNonceCache) noNoncesFound(cosigners []Cosigner) error {
 	// increment so it's taken into account in the nonce burn rate in the next reconciliation
 	cnc.lastReconcileNonces.Add(1)
 
+	if len(cnc.cache.cache) == 0 {
+		cnc.starved.Store(true)
+		totalNonceCacheGetNoncesFailures.WithLabelValues("empty").Inc()
+	} else {
+		totalNonceCacheGetNoncesFailures.WithLabelValues("cosigners").Inc()
+	}
+
 	// no nonces found
-	cosignerInts := make([]int, len(fastestPeers))
-	for i, p := range fastestPeers {
+	cosignerInts := make([]int, len(cosigners))
+	for i, p := range cosigners {
 		cosignerInts[i] = p.GetID()
 	}
-	return nil, fmt.Errorf("no nonces found involving cosigners %+v", cosignerInts)
+	return fmt.Errorf("no nonces found involving cosigners %+v", cosignerInts)
+}
+
+// SetCosigners changes the cosigners that nonces are loaded from. The cached nonces of a cosigner
+// that was removed or replaced, e.g. with a new address, are cleared.
+func (cnc *CosignerNonceCache) SetCosigners(cosigners []Cosigner) {
+	cnc.cosignersMu.Lock()
+	old := cnc.cosigners
+	cnc.cosigners = cosigners
+	cnc.cosignersMu.Unlock()
+
+	for _, c := range old {
+		if !slices.Contains(cosigners, c) {
+			cnc.ClearNonces(c)
+		}
+	}
 }
 
 func (cnc *CosignerNonceCache) ClearNonces(cosigner Cosigner) {
@@ -414,10 +1120,14 @@ func (cnc *CosignerNonceCache) ClearNonces(cosigner Cosigner) {
 			if len(cn.Nonces)-1 < int(cnc.threshold) {
 				// If cosigners on this nonce drops below threshold, delete it as it's no longer usable
 				cnc.cache.Delete(i)
+				cnc.pushRemoved(cn.UUID)
+				totalNonceCacheCleared.Inc()
 				i--
 			} else {
 				cn.Nonces = append(cn.Nonces[:deleteID], cn.Nonces[deleteID+1:]...)
+				cnc.pushChanged(cn)
 			}
 		}
 	}
+	nonceCacheSize.Set(float64(len(cnc.cache.cache)))
 }
@@ -0,0 +1,151 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultNonceCacheBurstConcurrency = 4
+	defaultNonceCacheBurstBatchSize   = 20
+	defaultNonceCacheBurstInterval    = 500 * time.Millisecond
+	defaultNonceCacheBurstTimeout     = 30 * time.Second
+)
+
+// NonceCacheBurstConfig is the on disk config format of the burst refills of the nonce cache. When
+// the cache of the leader is empty while sign requests are waiting for nonces, e.g. after a restart
+// or a leadership change, it is refilled in larger batches of concurrent requests and reconciled
+// more often until it reaches its target, which shortens the window in which signing is slow.
+type NonceCacheBurstConfig struct {
+	// Disabled refills an empty cache at the steady pace.
+	Disabled bool `yaml:"disabled,omitempty"`
+
+	// Concurrency is the number of concurrent requests for nonces to each cosigner. Defaults to 4.
+	Concurrency int `yaml:"concurrency,omitempty"`
+
+	// BatchSize is the least number of nonces the cache is refilled to. Defaults to 20.
+	BatchSize int `yaml:"batchSize,omitempty"`
+
+	// Interval is how often the cache is reconciled. Defaults to 500ms.
+	Interval string `yaml:"interval,omitempty"`
+
+	// Timeout is how long the cache is refilled in bursts at most. Defaults to 30s.
+	Timeout string `yaml:"timeout,omitempty"`
+}
+
+// nonceCacheBurst are the parameters of the burst refills of the nonce cache.
+type nonceCacheBurst struct {
+	disabled    bool
+	concurrency int
+	batchSize   int
+	interval    time.Duration
+	timeout     time.Duration
+}
+
+// params returns the burst refills of the config, the defaults of def without a config.
+func (cfg *NonceCacheBurstConfig) params(def nonceCacheBurst) (nonceCacheBurst, error) {
+	if cfg == nil {
+		return def, nil
+	}
+	b := def
+	b.disabled = cfg.Disabled
+	if cfg.Concurrency < 0 || cfg.BatchSize < 0 {
+		return nonceCacheBurst{}, fmt.Errorf(
+			"nonce cache burst concurrency (%d) and batchSize (%d) cannot be negative",
+			cfg.Concurrency, cfg.BatchSize,
+		)
+	}
+	if cfg.Concurrency != 0 {
+		b.concurrency = cfg.Concurrency
+	}
+	if cfg.BatchSize != 0 {
+		b.batchSize = cfg.BatchSize
+	}
+	var err error
+	if b.interval, err = parseDurationOrDefault(cfg.Interval, b.interval); err != nil {
+		return nonceCacheBurst{}, fmt.Errorf("invalid nonce cache burst interval: %w", err)
+	}
+	if b.timeout, err = parseDurationOrDefault(cfg.Timeout, b.timeout); err != nil {
+		return nonceCacheBurst{}, fmt.Errorf("invalid nonce cache burst timeout: %w", err)
+	}
+	if b.interval <= 0 || b.timeout <= 0 {
+		return nonceCacheBurst{}, fmt.Errorf(
+			"nonce cache burst interval (%s) and timeout (%s) must be positive", b.interval, b.timeout,
+		)
+	}
+	return b, nil
+}
+
+// startBurst starts refilling the cache in bursts if it is empty while sign requests found no nonces
+// since the last reconciliation or are waiting for nonces, and returns whether it is refilled in
+// bursts. A cache emptied by a sign request that found nonces is refilled at the steady pace.
+func (cnc *CosignerNonceCache) startBurst(remaining int) bool {
+	starved := cnc.starved.Swap(false)
+	if !cnc.burstStart.IsZero() {
+		return true
+	}
+	if cnc.burst.disabled || remaining > 0 || (!starved && cnc.queued.Load() == 0) {
+		return false
+	}
+	cnc.burstStart = time.Now()
+	nonceCacheBursting.Set(1)
+	totalNonceCacheBursts.Inc()
+	cnc.logger.Info("Nonce cache is empty with sign requests waiting, refilling in bursts")
+	return true
+}
+
+// stopBurst refills the cache at the steady pace again once it reached the target or the burst
+// timed out.
+func (cnc *CosignerNonceCache) stopBurst(target int) {
+	size := cnc.cache.Size()
+	if size < target && time.Since(cnc.burstStart) < cnc.burst.timeout {
+		return
+	}
+	if size < target {
+		cnc.logger.Error("Nonce cache did not reach its target while refilling in bursts",
+			"size", size, "target", target, "timeout", cnc.burst.timeout)
+	} else {
+		cnc.logger.Info("Nonce cache reached its target, refilling at the steady pace",
+			"size", size, "elapsed", time.Since(cnc.burstStart))
+	}
+	cnc.endBurst()
+}
+
+// endBurst refills the cache at the steady pace again.
+func (cnc *CosignerNonceCache) endBurst() {
+	cnc.burstStart = time.Time{}
+	nonceCacheBursting.Set(0)
+}
+
+// loadBurst loads n sets of nonces in concurrent batches, and returns the number of sets added to
+// the cache.
+func (cnc *CosignerNonceCache) loadBurst(ctx context.Context, n int) int {
+	batch := (n + cnc.burst.concurrency - 1) / cnc.burst.concurrency
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		added int
+	)
+	for ; n > 0; n -= batch {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			a := cnc.loadN(ctx, n)
+			mu.Lock()
+			added += a
+			mu.Unlock()
+		}(min(n, batch))
+	}
+	wg.Wait()
+	return added
+}
+
+// reconcileInterval returns how long until the next reconciliation.
+func (cnc *CosignerNonceCache) reconcileInterval() time.Duration {
+	if !cnc.burstStart.IsZero() {
+		return cnc.burst.interval
+	}
+	return cnc.getNoncesInterval
+}
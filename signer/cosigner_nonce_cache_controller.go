@@ -0,0 +1,99 @@
+package signer
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// gains of the nonce cache controller, applied to the shortfall of nonces left at a
+	// reconciliation, in nonces.
+	nonceCacheKp = 0.5
+	nonceCacheKi = 0.1
+	nonceCacheKd = 0.3
+
+	// nonceRefillMargin is the least time of demand kept ready for a refill, so that a fast refill
+	// that is delayed, e.g. by a slow cosigner, does not starve the cache.
+	nonceRefillMargin = 500 * time.Millisecond
+
+	// nonceRefillLatencyWeight is the weight of the latest refill in the average refill latency.
+	nonceRefillLatencyWeight = 0.25
+)
+
+// nonceCacheController sizes the nonce cache. The demand over a reconcile interval and the nonces
+// consumed while a refill is in flight set the target, which a PID controller corrects by the
+// shortfall of the nonces left at each reconciliation. The correction reacts to bursts of demand,
+// e.g. while blocks of a congested chain arrive in bursts, before the moving average of the demand
+// catches up, and settles once the demand is steady instead of swinging the target around it.
+type nonceCacheController struct {
+	interval   time.Duration
+	multiplier float64
+
+	// latency is the moving average of the time a refill takes.
+	latency time.Duration
+
+	integral float64
+	lastErr  float64
+
+	// expected is the number of nonces the last target expected to be left at the next
+	// reconciliation, or -1 before the first target.
+	expected float64
+}
+
+func newNonceCacheController(interval time.Duration, multiplier float64) *nonceCacheController {
+	return &nonceCacheController{
+		interval:   interval,
+		multiplier: multiplier,
+		expected:   -1,
+	}
+}
+
+// observeRefill records the time a refill took.
+func (c *nonceCacheController) observeRefill(d time.Duration) {
+	c.latency = time.Duration(
+		nonceRefillLatencyWeight*float64(d) + (1-nonceRefillLatencyWeight)*float64(c.latency),
+	)
+}
+
+// feedForward returns the number of nonces to keep ready for the demand over a reconcile interval,
+// and over the refill that follows it.
+func (c *nonceCacheController) feedForward(noncesPerMinute float64) float64 {
+	refill := max(c.latency, nonceRefillMargin)
+	return (noncesPerMinute / 60) * (c.interval.Seconds()*c.multiplier + refill.Seconds())
+}
+
+// target returns the number of nonces to keep ready for the demand, given the number of nonces
+// left at the reconciliation and the number of them that expired, and the correction of the
+// controller included in it.
+func (c *nonceCacheController) target(noncesPerMinute float64, remaining, pruned int) (int, float64) {
+	ff := c.feedForward(noncesPerMinute)
+
+	var correction float64
+	if c.expected >= 0 {
+		// a positive error is a shortfall: demand consumed more than the last target expected.
+		// Expired nonces were not consumed.
+		e := c.expected - float64(remaining+pruned)
+
+		// bound the integral so that it neither winds up while the cosigners cannot keep up, nor
+		// stays wound up once they can.
+		bound := math.Max(ff, 1) / nonceCacheKi
+		c.integral = math.Max(-bound, math.Min(bound, c.integral+e))
+
+		correction = nonceCacheKp*e + nonceCacheKi*c.integral + nonceCacheKd*(e-c.lastErr)
+		c.lastErr = e
+	}
+	// the controller may trim the target below the demand, but not starve it, and at most doubles it.
+	correction = math.Max(-ff/2, math.Min(ff, correction))
+
+	t := int(ff + correction)
+	if t <= 0 {
+		t = 1 // always target at least one nonce ready
+	}
+
+	// the nonces left at the next reconciliation, if demand matches its moving average. Nonces above
+	// the target are not loaded, but neither are the nonces in excess dropped.
+	ready := math.Max(float64(t), float64(remaining))
+	c.expected = math.Max(0, ready-(noncesPerMinute/60)*c.interval.Seconds())
+
+	return t, correction
+}
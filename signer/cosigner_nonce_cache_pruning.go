@@ -0,0 +1,76 @@
+package signer
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+const (
+	// NonceCachePruningExpiration prunes the cached nonces once they expired.
+	NonceCachePruningExpiration = "expiration"
+
+	// NonceCachePruningLRU prunes the cached nonces once they expired, and the least recently loaded
+	// nonces above the target of the cache.
+	NonceCachePruningLRU = "lru"
+)
+
+// nonceCachePruningParams returns whether the cache is pruned of the least recently loaded nonces
+// for the pruning strategy, and the expiration jitter of the nonces below the expiration.
+func nonceCachePruningParams(pruning, jitter string, expiration time.Duration) (bool, time.Duration, error) {
+	var lru bool
+	switch pruning {
+	case "", NonceCachePruningExpiration:
+	case NonceCachePruningLRU:
+		lru = true
+	default:
+		return false, 0, fmt.Errorf("nonce cache pruning (%s) must be %s or %s",
+			pruning, NonceCachePruningExpiration, NonceCachePruningLRU)
+	}
+	j, err := parseDurationOrDefault(jitter, 0)
+	if err != nil {
+		return false, 0, fmt.Errorf("invalid nonce cache expirationJitter: %w", err)
+	}
+	if j < 0 || j >= expiration {
+		return false, 0, fmt.Errorf(
+			"nonce cache expirationJitter (%s) cannot be negative and must be below the expiration (%s)", j, expiration,
+		)
+	}
+	return lru, j, nil
+}
+
+// lruNonceCachePruner prunes the expired nonces of the cache, and the least recently loaded nonces
+// above its target, so that the nonces in excess of the demand are pruned a few at a time as the
+// demand falls instead of all at once when they expire.
+type lruNonceCachePruner struct {
+	cnc *CosignerNonceCache
+}
+
+func (p lruNonceCachePruner) PruneNonces() int {
+	pruned := p.cnc.cache.PruneNonces()
+	target := int(p.cnc.targetSize.Load())
+	if target <= 0 {
+		// not sized yet.
+		return pruned
+	}
+
+	nc := p.cnc.cache
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	excess := len(nc.cache) - target
+	if excess <= 0 {
+		return pruned
+	}
+	// the nonces are cached in the order they were loaded.
+	nc.cache = nc.cache[excess:]
+	return pruned + excess
+}
+
+// jitteredExpiration returns the expiration of a loaded nonce, up to the expiration jitter earlier
+// than expiration, so that the nonces loaded together do not all expire in the same reconciliation.
+func (cnc *CosignerNonceCache) jitteredExpiration(expiration time.Time) time.Time {
+	if cnc.expirationJitter <= 0 {
+		return expiration
+	}
+	return expiration.Add(-time.Duration(rand.Int63n(int64(cnc.expirationJitter)))) //nolint:gosec
+}
@@ -0,0 +1,290 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/strangelove-ventures/horcrux/signer/proto"
+)
+
+const (
+	defaultNonceCachePushInterval = 500 * time.Millisecond
+
+	// noncePushProtocolVersion is the lowest cosigner protocol version with the PushNonces RPC.
+	noncePushProtocolVersion uint32 = 3
+)
+
+// NonceCachePushConfig is the on disk config format of the push of the nonce cache of the leader to
+// the followers. The leader pushes the nonces it caches, and the nonces it uses or drops, so that a
+// follower promoted to leader signs with the inherited nonces instead of starting with an empty
+// cache. The changes are pushed every interval, so a promoted leader can inherit nonces that the
+// previous leader used after the last push, or is still using. The nonces are held by the cosigners,
+// which take a nonce out before they sign with it, so the second sign with an inherited nonce fails
+// on every cosigner that signed with it, rather than using the nonce twice.
+type NonceCachePushConfig struct {
+	// Interval is how often the changes of the nonce cache are pushed. Defaults to 500ms.
+	Interval string `yaml:"interval,omitempty"`
+}
+
+// params returns the interval of the push of the config.
+func (cfg *NonceCachePushConfig) params() (time.Duration, error) {
+	interval, err := parseDurationOrDefault(cfg.Interval, defaultNonceCachePushInterval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid nonce cache push interval: %w", err)
+	}
+	if interval <= 0 {
+		return 0, fmt.Errorf("nonce cache push interval (%s) must be positive", interval)
+	}
+	return interval, nil
+}
+
+// noncePush are the changes of the nonce cache of the leader not pushed to the followers yet.
+type noncePush struct {
+	interval time.Duration
+
+	mu      sync.Mutex
+	added   map[uuid.UUID]*proto.CachedNonce
+	removed [][]byte
+	// snapshot are the followers that are pushed all the cached nonces next, e.g. after a failed push.
+	snapshot map[int]bool
+}
+
+// SetPush pushes the changes of the nonce cache to the followers every interval while this cosigner
+// is the leader.
+func (cnc *CosignerNonceCache) SetPush(interval time.Duration) {
+	cnc.push = &noncePush{
+		interval: interval,
+		added:    make(map[uuid.UUID]*proto.CachedNonce),
+		snapshot: make(map[int]bool),
+	}
+}
+
+// pushAdded records the nonces added to the cache, or changed, to be pushed.
+func (cnc *CosignerNonceCache) pushAdded(cn *CachedNonce) {
+	if cnc.push == nil {
+		return
+	}
+	p := cachedNonceToProto(cn)
+	cnc.push.mu.Lock()
+	defer cnc.push.mu.Unlock()
+	cnc.push.added[cn.UUID] = p
+}
+
+// pushRemoved records the nonces removed from the cache, to be pushed.
+func (cnc *CosignerNonceCache) pushRemoved(u uuid.UUID) {
+	if cnc.push == nil {
+		return
+	}
+	cnc.push.mu.Lock()
+	defer cnc.push.mu.Unlock()
+	if _, ok := cnc.push.added[u]; ok {
+		// the followers never got it.
+		delete(cnc.push.added, u)
+		return
+	}
+	cnc.push.removed = append(cnc.push.removed, u[:])
+}
+
+// pushChanged records the nonces whose cosigners changed in the cache, to be pushed.
+func (cnc *CosignerNonceCache) pushChanged(cn *CachedNonce) {
+	if cnc.push == nil {
+		return
+	}
+	cnc.push.mu.Lock()
+	_, pending := cnc.push.added[cn.UUID]
+	if !pending {
+		// replaced on the followers.
+		cnc.push.removed = append(cnc.push.removed, cn.UUID[:])
+	}
+	cnc.push.mu.Unlock()
+	cnc.pushAdded(cn)
+}
+
+// startPush pushes the changes of the cache to the followers every push interval until ctx is done.
+func (cnc *CosignerNonceCache) startPush(ctx context.Context) {
+	ticker := time.NewTicker(cnc.push.interval)
+	defer ticker.Stop()
+	leader := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		wasLeader := leader
+		leader = cnc.leader.IsLeader()
+
+		cnc.push.mu.Lock()
+		added, removed := cnc.push.added, cnc.push.removed
+		cnc.push.added, cnc.push.removed = make(map[uuid.UUID]*proto.CachedNonce), nil
+		cnc.push.mu.Unlock()
+		if !leader {
+			continue
+		}
+		if !wasLeader {
+			// the followers may hold the nonces of a previous leader.
+			cnc.resetPushSnapshots()
+		}
+		cnc.pushToFollowers(ctx, added, removed)
+	}
+}
+
+// resetPushSnapshots pushes all the cached nonces to every follower next.
+func (cnc *CosignerNonceCache) resetPushSnapshots() {
+	cnc.cosignersMu.RLock()
+	defer cnc.cosignersMu.RUnlock()
+	cnc.push.mu.Lock()
+	defer cnc.push.mu.Unlock()
+	for _, c := range cnc.cosigners {
+		cnc.push.snapshot[c.GetID()] = true
+	}
+}
+
+// pushToFollowers pushes the added and removed nonces to the followers, or all the cached nonces to
+// the followers that need a snapshot.
+func (cnc *CosignerNonceCache) pushToFollowers(
+	ctx context.Context,
+	added map[uuid.UUID]*proto.CachedNonce,
+	removed [][]byte,
+) {
+	cnc.cosignersMu.RLock()
+	cosigners := cnc.cosigners
+	cnc.cosignersMu.RUnlock()
+
+	delta := &proto.PushNoncesRequest{Removed: removed}
+	for _, p := range added {
+		delta.Added = append(delta.Added, p)
+	}
+
+	var snapshot *proto.PushNoncesRequest
+	var wg sync.WaitGroup
+	for _, c := range cosigners {
+		rc, ok := c.(*RemoteCosigner)
+		if !ok || rc.ProtocolVersion() < noncePushProtocolVersion {
+			continue
+		}
+		req := delta
+		cnc.push.mu.Lock()
+		needsSnapshot := cnc.push.snapshot[rc.GetID()]
+		delete(cnc.push.snapshot, rc.GetID())
+		cnc.push.mu.Unlock()
+		if needsSnapshot {
+			if snapshot == nil {
+				snapshot = cnc.snapshotToProto()
+			}
+			req = snapshot
+		} else if len(req.Added) == 0 && len(req.Removed) == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, cnc.getNoncesTimeout)
+			defer cancel()
+			peerID := strconv.Itoa(rc.GetID())
+			if err := rc.PushNonces(ctx, req); err != nil {
+				totalNonceCachePushFailures.WithLabelValues(peerID).Inc()
+				cnc.logger.Debug("Failed to push nonces to follower", "cosigner", rc.GetID(), "error", err)
+				// the follower missed the changes.
+				cnc.push.mu.Lock()
+				cnc.push.snapshot[rc.GetID()] = true
+				cnc.push.mu.Unlock()
+				return
+			}
+			totalNonceCachePushed.WithLabelValues(peerID).Add(float64(len(req.Added)))
+		}()
+	}
+	wg.Wait()
+}
+
+// snapshotToProto returns a push of all the cached nonces.
+func (cnc *CosignerNonceCache) snapshotToProto() *proto.PushNoncesRequest {
+	cnc.cache.mu.RLock()
+	defer cnc.cache.mu.RUnlock()
+	req := &proto.PushNoncesRequest{Snapshot: true}
+	for _, cn := range cnc.cache.cache {
+		req.Added = append(req.Added, cachedNonceToProto(cn))
+	}
+	return req
+}
+
+// ApplyPush applies the changes of the nonce cache pushed by the leader to the cache of this
+// follower. It fails if this cosigner is the leader.
+func (cnc *CosignerNonceCache) ApplyPush(req *proto.PushNoncesRequest) error {
+	if cnc.leader.IsLeader() {
+		return fmt.Errorf("cannot apply the nonces pushed to the leader")
+	}
+
+	cnc.cosignersMu.RLock()
+	cosigners := make(map[int]Cosigner, len(cnc.cosigners))
+	for _, c := range cnc.cosigners {
+		cosigners[c.GetID()] = c
+	}
+	cnc.cosignersMu.RUnlock()
+
+	added := make([]*CachedNonce, 0, len(req.Added))
+AddedLoop:
+	for _, p := range req.Added {
+		cn := &CachedNonce{
+			UUID:       uuid.UUID(p.Uuid),
+			Expiration: time.Unix(0, p.Expiration),
+		}
+		for _, n := range p.Nonces {
+			c, ok := cosigners[int(n.CosignerID)]
+			if !ok {
+				// a cosigner this follower does not know of, e.g. while the peers change.
+				continue AddedLoop
+			}
+			cn.Nonces = append(cn.Nonces, CosignerNoncesRel{
+				Cosigner: c,
+				Nonces:   CosignerNoncesFromProto(n.Nonces),
+			})
+		}
+		added = append(added, cn)
+	}
+
+	cnc.cache.mu.Lock()
+	defer cnc.cache.mu.Unlock()
+	if req.Snapshot {
+		cnc.cache.cache = nil
+	}
+	if len(req.Removed) > 0 {
+		removed := make(map[uuid.UUID]bool, len(req.Removed))
+		for _, u := range req.Removed {
+			removed[uuid.UUID(u)] = true
+		}
+		cache := cnc.cache.cache[:0]
+		for _, cn := range cnc.cache.cache {
+			if !removed[cn.UUID] {
+				cache = append(cache, cn)
+			}
+		}
+		cnc.cache.cache = cache
+	}
+	cnc.cache.cache = append(cnc.cache.cache, added...)
+	// the cache is pruned from the oldest nonces.
+	slices.SortStableFunc(cnc.cache.cache, func(a, b *CachedNonce) int {
+		return a.Expiration.Compare(b.Expiration)
+	})
+	nonceCacheSize.Set(float64(len(cnc.cache.cache)))
+	return nil
+}
+
+func cachedNonceToProto(cn *CachedNonce) *proto.CachedNonce {
+	p := &proto.CachedNonce{
+		Uuid:       cn.UUID[:],
+		Expiration: cn.Expiration.UnixNano(),
+	}
+	for _, n := range cn.Nonces {
+		p.Nonces = append(p.Nonces, &proto.CachedCosignerNonces{
+			CosignerID: int32(n.Cosigner.GetID()),
+			Nonces:     n.Nonces.toProto(),
+		})
+	}
+	return p
+}
@@ -0,0 +1,106 @@
+package signer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+)
+
+// nonceCacheStatsMaxAge is the age after which saved nonce demand statistics no longer reflect the
+// demand of the chains, and are discarded instead of sizing the cache.
+const nonceCacheStatsMaxAge = 10 * time.Minute
+
+// nonceCacheStats are the demand statistics of the nonce cache of the leader, saved to the state
+// directory on shutdown so that a restarted leader sizes its cache for the demand right away
+// instead of learning it again over the next reconciliations.
+type nonceCacheStats struct {
+	SavedAt time.Time `json:"saved_at"`
+
+	// Demand is the moving average of the demand, the latest first.
+	Demand []nonceDemandStat `json:"demand"`
+
+	// RefillLatency is the moving average of the time a refill takes.
+	RefillLatency time.Duration `json:"refill_latency"`
+
+	// TargetSize is the number of nonces the cache kept ready.
+	TargetSize int `json:"target_size"`
+}
+
+// nonceDemandStat is the demand of the nonce cache between two reconciliations.
+type nonceDemandStat struct {
+	Interval        time.Duration `json:"interval"`
+	NoncesPerMinute float64       `json:"nonces_per_minute"`
+}
+
+// NonceCacheStatsFile returns the path of the file the nonce demand statistics are saved to.
+func (c RuntimeConfig) NonceCacheStatsFile() string {
+	return filepath.Join(c.StateDir, "nonce_cache_stats.json")
+}
+
+// updateStats records the demand statistics as of the last reconciliation, to be saved.
+func (cnc *CosignerNonceCache) updateStats(target int) {
+	stats := &nonceCacheStats{
+		Demand:        make([]nonceDemandStat, len(cnc.movingAverage.items)),
+		RefillLatency: cnc.controller.latency,
+		TargetSize:    target,
+	}
+	for i, e := range cnc.movingAverage.items {
+		stats.Demand[i] = nonceDemandStat{Interval: e.timeSinceLastReconcile, NoncesPerMinute: e.noncesPerMinute}
+	}
+	cnc.stats.Store(stats)
+}
+
+// SaveStats saves the demand statistics to the file, if this cosigner reconciled the cache as the
+// leader since it started.
+func (cnc *CosignerNonceCache) SaveStats(file string) error {
+	stats := cnc.stats.Load()
+	if stats == nil {
+		return nil
+	}
+	saved := *stats
+	saved.SavedAt = time.Now()
+	data, err := json.Marshal(&saved)
+	if err != nil {
+		return err
+	}
+	if err := writeStateFile(file, data); err != nil {
+		return fmt.Errorf("failed to save nonce cache stats: %w", err)
+	}
+	cnc.logger.Info("Saved nonce cache stats", "file", file, "target", saved.TargetSize)
+	return nil
+}
+
+// LoadStats sizes the cache with the demand statistics saved to the file, unless there are none or
+// they are too old. It must be called before the cache is started.
+func (cnc *CosignerNonceCache) LoadStats(file string) error {
+	data, err := readStateFile(file)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to load nonce cache stats: %w", err)
+	}
+	var stats nonceCacheStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return fmt.Errorf("failed to load nonce cache stats: %w", err)
+	}
+	if age := time.Since(stats.SavedAt); age > nonceCacheStatsMaxAge {
+		cnc.logger.Info("Discarding stale nonce cache stats", "file", file, "age", age)
+		return nil
+	}
+
+	cnc.movingAverage.items = make([]movingAverageItem, len(stats.Demand))
+	for i, d := range stats.Demand {
+		cnc.movingAverage.items[i] = movingAverageItem{
+			timeSinceLastReconcile: d.Interval,
+			noncesPerMinute:        d.NoncesPerMinute,
+		}
+	}
+	cnc.controller.latency = stats.RefillLatency
+	cnc.targetSize.Store(int64(stats.TargetSize))
+	cnc.logger.Info("Loaded nonce cache stats", "file", file, "target", stats.TargetSize)
+	return nil
+}
@@ -2,13 +2,17 @@ package signer
 
 import (
 	"context"
+	"encoding/json"
 	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
 
 	cometlog "github.com/cometbft/cometbft/libs/log"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/strangelove-ventures/horcrux/signer/proto"
 	"github.com/stretchr/testify/require"
 )
 
@@ -55,6 +59,140 @@ func TestMovingAverage(t *testing.T) {
 	require.Equal(t, float64(1000), ma.average())
 }
 
+func TestNonceCacheConfig(t *testing.T) {
+	p, err := (*NonceCacheConfig)(nil).params()
+	require.NoError(t, err)
+	require.Equal(t, nonceCacheParams{
+		targetMultiplier:  defaultNonceCacheTargetMultiplier,
+		expiration:        defaultNonceExpiration,
+		reconcileInterval: defaultGetNoncesInterval,
+		fetchTimeout:      defaultGetNoncesTimeout,
+		quorumGrace:       defaultNonceCacheQuorumGrace,
+		warmup: nonceCacheWarmup{
+			minFill: defaultNonceCacheWarmupMinFill,
+			timeout: defaultNonceCacheWarmupTimeout,
+		},
+		onDemand: nonceCacheOnDemand{
+			queueDepth:   defaultNonceCacheQueueDepth,
+			waitDeadline: defaultNonceCacheWaitDeadline,
+		},
+		burst: nonceCacheBurst{
+			concurrency: defaultNonceCacheBurstConcurrency,
+			batchSize:   defaultNonceCacheBurstBatchSize,
+			interval:    defaultNonceCacheBurstInterval,
+			timeout:     defaultNonceCacheBurstTimeout,
+		},
+		backoff: nonceBackoff{
+			clears:     defaultNonceBackoffClears,
+			window:     defaultNonceBackoffWindow,
+			minBackoff: defaultNonceBackoffMinBackoff,
+			maxBackoff: defaultNonceBackoffMaxBackoff,
+		},
+	}, p)
+
+	p, err = (&NonceCacheConfig{
+		TargetMultiplier:  2,
+		Expiration:        "15s",
+		ReconcileInterval: "1s",
+		FetchTimeout:      "6s",
+		QuorumGrace:       "0s",
+		Warmup: &NonceCacheWarmupConfig{
+			MinNonces:      20,
+			HoldChainNodes: true,
+		},
+		OnDemand: &NonceCacheOnDemandConfig{QueueDepth: 8},
+	}).params()
+	require.NoError(t, err)
+	require.Equal(t, nonceCacheParams{
+		targetMultiplier:  2,
+		expiration:        15 * time.Second,
+		reconcileInterval: time.Second,
+		fetchTimeout:      6 * time.Second,
+		quorumGrace:       0,
+		warmup: nonceCacheWarmup{
+			minFill:        defaultNonceCacheWarmupMinFill,
+			minNonces:      20,
+			holdChainNodes: true,
+			timeout:        defaultNonceCacheWarmupTimeout,
+		},
+		onDemand: nonceCacheOnDemand{
+			queueDepth:   8,
+			waitDeadline: defaultNonceCacheWaitDeadline,
+		},
+		burst: nonceCacheBurst{
+			concurrency: defaultNonceCacheBurstConcurrency,
+			batchSize:   defaultNonceCacheBurstBatchSize,
+			interval:    defaultNonceCacheBurstInterval,
+			timeout:     defaultNonceCacheBurstTimeout,
+		},
+		backoff: nonceBackoff{
+			clears:     defaultNonceBackoffClears,
+			window:     defaultNonceBackoffWindow,
+			minBackoff: defaultNonceBackoffMinBackoff,
+			maxBackoff: defaultNonceBackoffMaxBackoff,
+		},
+	}, p)
+
+	require.Error(t, (&NonceCacheConfig{TargetMultiplier: 0.5}).Validate())
+	require.Error(t, (&NonceCacheConfig{Expiration: "20s"}).Validate())
+	require.Error(t, (&NonceCacheConfig{ReconcileInterval: "-1s"}).Validate())
+	require.Error(t, (&NonceCacheConfig{FetchTimeout: "soon"}).Validate())
+	require.Error(t, (&NonceCacheConfig{QuorumGrace: "-1ms"}).Validate())
+	require.Error(t, (&NonceCacheConfig{Warmup: &NonceCacheWarmupConfig{MinFill: 1.5}}).Validate())
+	require.Error(t, (&NonceCacheConfig{Warmup: &NonceCacheWarmupConfig{MinNonces: -1}}).Validate())
+	require.Error(t, (&NonceCacheConfig{Warmup: &NonceCacheWarmupConfig{Timeout: "0s"}}).Validate())
+	require.Error(t, (&NonceCacheConfig{OnDemand: &NonceCacheOnDemandConfig{QueueDepth: -1}}).Validate())
+	require.Error(t, (&NonceCacheConfig{OnDemand: &NonceCacheOnDemandConfig{WaitDeadline: "-1s"}}).Validate())
+}
+
+func TestNonceCacheTarget(t *testing.T) {
+	cnc := NewCosignerNonceCache(cometlog.NewNopLogger(), nil, nil, 2*time.Second, time.Second,
+		defaultNonceExpiration, 2, nil)
+
+	// 60 nonces per minute over 2s, and half a second of margin.
+	require.Equal(t, 2, cnc.target(60))
+	cnc.controller.multiplier = 2
+	require.Equal(t, 4, cnc.target(60))
+	require.Equal(t, 1, cnc.target(0))
+}
+
+func TestNonceCacheController(t *testing.T) {
+	c := newNonceCacheController(time.Second, 1.2)
+
+	// 10 nonces per second over 1.2s, and the 0.5s margin of a refill.
+	tgt, correction := c.target(600, 0, 0)
+	require.Equal(t, 17, tgt)
+	require.Zero(t, correction)
+
+	// the demand met the expectation, 7 nonces are left.
+	tgt, correction = c.target(600, 7, 0)
+	require.Equal(t, 17, tgt)
+	require.Zero(t, correction)
+
+	// expired nonces are not a shortfall.
+	tgt, correction = c.target(600, 3, 4)
+	require.Equal(t, 17, tgt)
+	require.Zero(t, correction)
+
+	// a burst drained the cache before the moving average caught up.
+	tgt, correction = c.target(600, 0, 0)
+	require.Greater(t, tgt, 17)
+	require.Positive(t, correction)
+
+	// the correction settles once the demand is steady again.
+	for i := 0; i < 20; i++ {
+		tgt, _ = c.target(600, tgt-10, 0)
+	}
+	require.InDelta(t, 17, tgt, 1)
+
+	// slow refills keep more nonces ready.
+	c.observeRefill(time.Second)
+	require.InDelta(t, 17, c.feedForward(600), 0.001)
+	c.observeRefill(6 * time.Second)
+	require.Equal(t, 1687500*time.Microsecond, c.latency)
+	require.InDelta(t, 28.875, c.feedForward(600), 0.001)
+}
+
 func TestClearNonces(t *testing.T) {
 	lcs, _ := getTestLocalCosigners(t, 2, 3)
 	cosigners := make([]Cosigner, len(lcs))
@@ -118,6 +256,76 @@ func TestClearNonces(t *testing.T) {
 	require.Equal(t, 0, cnc.cache.Size())
 }
 
+func TestNonceCacheInspect(t *testing.T) {
+	cnc := CosignerNonceCache{cache: new(NonceCache)}
+	require.Equal(t, NonceCacheInspection{Cosigners: map[int]int{}}, cnc.Inspect())
+
+	c1, c2, c3 := &RemoteCosigner{id: 1}, &RemoteCosigner{id: 2}, &RemoteCosigner{id: 3}
+	first := time.Now().Add(time.Second)
+	cnc.cache.Add(&CachedNonce{
+		UUID:       uuid.New(),
+		Expiration: first.Add(time.Second),
+		Nonces:     []CosignerNoncesRel{{Cosigner: c1}, {Cosigner: c2}},
+	})
+	cnc.cache.Add(&CachedNonce{
+		UUID:       uuid.New(),
+		Expiration: first,
+		Nonces:     []CosignerNoncesRel{{Cosigner: c1}, {Cosigner: c3}},
+	})
+	cnc.targetSize.Store(5)
+
+	inspection := cnc.Inspect()
+	require.Equal(t, 2, inspection.Size)
+	require.Equal(t, 5, inspection.TargetSize)
+	require.Equal(t, map[int]int{1: 2, 2: 1, 3: 1}, inspection.Cosigners)
+	require.Equal(t, first, *inspection.NextExpiration)
+}
+
+func TestNonceCacheMetrics(t *testing.T) {
+	lcs, _ := getTestLocalCosigners(t, 2, 3)
+	cosigners := make([]Cosigner, len(lcs))
+	for i, lc := range lcs {
+		cosigners[i] = lc
+	}
+
+	cnc := CosignerNonceCache{
+		threshold: 2,
+		cache:     new(NonceCache),
+	}
+
+	emptyFailures := testutil.ToFloat64(totalNonceCacheGetNoncesFailures.WithLabelValues("empty"))
+	cosignersFailures := testutil.ToFloat64(totalNonceCacheGetNoncesFailures.WithLabelValues("cosigners"))
+	cleared := testutil.ToFloat64(totalNonceCacheCleared)
+
+	_, err := cnc.GetNonces([]Cosigner{cosigners[0], cosigners[1]})
+	require.Error(t, err)
+	require.Equal(t, emptyFailures+1, testutil.ToFloat64(totalNonceCacheGetNoncesFailures.WithLabelValues("empty")))
+
+	for i := 0; i < 3; i++ {
+		cnc.cache.Add(&CachedNonce{
+			UUID:       uuid.New(),
+			Expiration: time.Now().Add(1 * time.Second),
+			Nonces: []CosignerNoncesRel{
+				{Cosigner: cosigners[0]},
+				{Cosigner: cosigners[1]},
+			},
+		})
+	}
+
+	_, err = cnc.GetNonces([]Cosigner{cosigners[0], cosigners[2]})
+	require.Error(t, err)
+	require.Equal(t, cosignersFailures+1,
+		testutil.ToFloat64(totalNonceCacheGetNoncesFailures.WithLabelValues("cosigners")))
+
+	_, err = cnc.GetNonces([]Cosigner{cosigners[0], cosigners[1]})
+	require.NoError(t, err)
+	require.Equal(t, float64(2), testutil.ToFloat64(nonceCacheSize))
+
+	cnc.ClearNonces(cosigners[0])
+	require.Equal(t, cleared+2, testutil.ToFloat64(totalNonceCacheCleared))
+	require.Equal(t, float64(0), testutil.ToFloat64(nonceCacheSize))
+}
+
 type mockPruner struct {
 	cache  *NonceCache
 	count  int
@@ -470,3 +678,423 @@ func TestNonceCacheDemandSlowDefault(t *testing.T) {
 
 	require.LessOrEqual(t, nonceCache.cache.Size(), nonceCache.target(60/7))
 }
+
+func TestGetBestNonces(t *testing.T) {
+	lcs, _ := getTestLocalCosigners(t, 3, 4)
+	cosigners := make([]Cosigner, len(lcs))
+	for i, lc := range lcs {
+		cosigners[i] = lc
+	}
+
+	cnc := CosignerNonceCache{
+		logger:    cometlog.NewNopLogger(),
+		threshold: 3,
+		cache:     new(NonceCache),
+		empty:     make(chan struct{}, 1),
+	}
+
+	addNonces := func(cosigners ...Cosigner) uuid.UUID {
+		cn := &CachedNonce{UUID: uuid.New(), Expiration: time.Now().Add(time.Second)}
+		for _, c := range cosigners {
+			cn.Nonces = append(cn.Nonces, CosignerNoncesRel{Cosigner: c})
+		}
+		cnc.cache.Add(cn)
+		return cn.UUID
+	}
+
+	// peers 2, 3 and 4 ranked best first.
+	ranked := []Cosigner{cosigners[1], cosigners[2], cosigners[3]}
+
+	without3 := addNonces(cosigners[0], cosigners[1], cosigners[3])
+	all := addNonces(cosigners[0], cosigners[1], cosigners[2], cosigners[3])
+	without2 := addNonces(cosigners[0], cosigners[2], cosigners[3])
+
+	// the set with the best ranked peers is preferred.
+	nonces, signers, err := cnc.GetBestNonces(cosigners[0], ranked)
+	require.NoError(t, err)
+	require.Equal(t, all, nonces.UUID)
+	require.Equal(t, []Cosigner{cosigners[0], cosigners[1], cosigners[2]}, signers)
+
+	// otherwise the set with the next best ranked peers.
+	nonces, signers, err = cnc.GetBestNonces(cosigners[0], ranked)
+	require.NoError(t, err)
+	require.Equal(t, without3, nonces.UUID)
+	require.Equal(t, []Cosigner{cosigners[0], cosigners[1], cosigners[3]}, signers)
+
+	// the nonces must include threshold ranked peers.
+	_, _, err = cnc.GetBestNonces(cosigners[0], []Cosigner{cosigners[2]})
+	require.Error(t, err)
+
+	nonces, _, err = cnc.GetBestNonces(cosigners[0], ranked)
+	require.NoError(t, err)
+	require.Equal(t, without2, nonces.UUID)
+
+	_, _, err = cnc.GetBestNonces(cosigners[0], ranked)
+	require.Error(t, err)
+}
+
+func TestGetBestNoncesMaxAge(t *testing.T) {
+	lcs, _ := getTestLocalCosigners(t, 2, 2)
+	cosigners := []Cosigner{lcs[0], lcs[1]}
+
+	cnc := CosignerNonceCache{
+		logger:          cometlog.NewNopLogger(),
+		threshold:       2,
+		cache:           new(NonceCache),
+		empty:           make(chan struct{}, 1),
+		nonceExpiration: 10 * time.Second,
+	}
+
+	// nonces fetched 5s and 1s ago.
+	old := &CachedNonce{UUID: uuid.New(), Expiration: time.Now().Add(5 * time.Second)}
+	fresh := &CachedNonce{UUID: uuid.New(), Expiration: time.Now().Add(9 * time.Second)}
+	for _, cn := range []*CachedNonce{old, fresh} {
+		cn.Nonces = []CosignerNoncesRel{{Cosigner: cosigners[0]}, {Cosigner: cosigners[1]}}
+		cnc.cache.Add(cn)
+	}
+
+	// the old nonces are left for the chains that accept them.
+	nonces, _, err := cnc.GetBestNoncesMaxAge(cosigners[0], cosigners[1:], 3*time.Second)
+	require.NoError(t, err)
+	require.Equal(t, fresh.UUID, nonces.UUID)
+
+	_, _, err = cnc.GetBestNoncesMaxAge(cosigners[0], cosigners[1:], 3*time.Second)
+	require.Error(t, err)
+
+	nonces, _, err = cnc.GetBestNonces(cosigners[0], cosigners[1:])
+	require.NoError(t, err)
+	require.Equal(t, old.UUID, nonces.UUID)
+}
+
+func TestWaitBestNonces(t *testing.T) {
+	lcs, _ := getTestLocalCosigners(t, 2, 3)
+	cosigners := make([]Cosigner, len(lcs))
+	for i, lc := range lcs {
+		cosigners[i] = lc
+	}
+
+	nonceCache := NewCosignerNonceCache(
+		cometlog.NewNopLogger(),
+		cosigners,
+		// not the leader, so that only the nonces loaded on demand are loaded.
+		&MockLeader{id: 1},
+		time.Minute,
+		time.Second,
+		defaultNonceExpiration,
+		2,
+		nil,
+	)
+	nonceCache.onDemand.waitDeadline = 100 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// nothing loads the nonces until the loop runs.
+	_, _, err := nonceCache.WaitBestNonces(ctx, cosigners[0], cosigners[1:], 0)
+	require.ErrorContains(t, err, "no nonces loaded on demand within 100ms")
+
+	// the queue is full.
+	nonceCache.onDemand.queueDepth = 1
+	nonceCache.queued.Store(1)
+	start := time.Now()
+	_, _, err = nonceCache.WaitBestNonces(ctx, cosigners[0], cosigners[1:], 0)
+	require.Error(t, err)
+	require.Less(t, time.Since(start), nonceCache.onDemand.waitDeadline)
+	nonceCache.queued.Store(0)
+
+	served := testutil.ToFloat64(totalNonceCacheOnDemandWaits.WithLabelValues("served"))
+	go nonceCache.Start(ctx)
+	nonceCache.onDemand.waitDeadline = 5 * time.Second
+	nonces, signers, err := nonceCache.WaitBestNonces(ctx, cosigners[0], cosigners[1:], 0)
+	require.NoError(t, err)
+	require.NotNil(t, nonces)
+	require.Len(t, signers, 2)
+	require.Equal(t, served+1, testutil.ToFloat64(totalNonceCacheOnDemandWaits.WithLabelValues("served")))
+}
+
+// slowCosigner is a Cosigner that responds to the requests for nonces after a delay.
+type slowCosigner struct {
+	Cosigner
+	delay time.Duration
+}
+
+func (c slowCosigner) GetNonces(ctx context.Context, uuids []uuid.UUID) (CosignerUUIDNoncesMultiple, error) {
+	select {
+	case <-time.After(c.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return c.Cosigner.GetNonces(ctx, uuids)
+}
+
+func TestLoadNHedged(t *testing.T) {
+	lcs, _ := getTestLocalCosigners(t, 2, 3)
+	slow := slowCosigner{Cosigner: lcs[2], delay: 2 * time.Second}
+
+	nonceCache := NewCosignerNonceCache(
+		cometlog.NewNopLogger(),
+		[]Cosigner{lcs[0], lcs[1], slow},
+		&MockLeader{id: 1},
+		time.Minute,
+		5*time.Second,
+		defaultNonceExpiration,
+		2,
+		nil,
+	)
+
+	// the slow cosigner does not delay the refill.
+	start := time.Now()
+	nonceCache.LoadN(context.Background(), 1)
+	require.Less(t, time.Since(start), time.Second)
+	require.Equal(t, 1, nonceCache.cache.Size())
+	require.Len(t, nonceCache.cache.cache[0].Nonces, 2)
+	require.False(t, nonceCache.cache.cache[0].hasCosigner(slow))
+
+	// the cosigners that respond within the quorum grace are included.
+	slow.delay = 10 * time.Millisecond
+	nonceCache.SetCosigners([]Cosigner{lcs[0], lcs[1], slow})
+	nonceCache.quorumGrace = time.Second
+	nonceCache.LoadN(context.Background(), 1)
+	require.Equal(t, 2, nonceCache.cache.Size())
+	require.Len(t, nonceCache.cache.cache[1].Nonces, 3)
+}
+
+func TestNonceCacheBurst(t *testing.T) {
+	lcs, _ := getTestLocalCosigners(t, 2, 3)
+	cosigners := make([]Cosigner, len(lcs))
+	for i, lc := range lcs {
+		cosigners[i] = lc
+	}
+
+	nonceCache := NewCosignerNonceCache(
+		cometlog.NewNopLogger(),
+		cosigners,
+		&MockLeader{id: 1, leader: &ThresholdValidator{myCosigner: lcs[0]}},
+		time.Minute,
+		time.Second,
+		defaultNonceExpiration,
+		2,
+		nil,
+	)
+	ctx := context.Background()
+
+	// an empty cache without sign requests waiting is not refilled in bursts.
+	nonceCache.lastReconcileTime = time.Now().Add(-time.Minute)
+	nonceCache.reconcile(ctx)
+	require.Equal(t, 1, nonceCache.cache.Size())
+	require.Equal(t, time.Minute, nonceCache.reconcileInterval())
+
+	// sign requests that found the cache empty are refilled in a burst, at least to a batch.
+	bursts := testutil.ToFloat64(totalNonceCacheBursts)
+	nonceCache.cache.cache = nil
+	_, err := nonceCache.GetNonces(cosigners[:2])
+	require.Error(t, err)
+	nonceCache.lastReconcileTime = time.Now().Add(-time.Minute)
+	nonceCache.burst.batchSize = 10
+	nonceCache.burst.timeout = time.Minute
+	nonceCache.reconcile(ctx)
+	require.Equal(t, bursts+1, testutil.ToFloat64(totalNonceCacheBursts))
+	require.Equal(t, 10, nonceCache.cache.Size())
+
+	// the burst ends once the cache reached its target.
+	require.True(t, nonceCache.burstStart.IsZero())
+	require.Equal(t, time.Minute, nonceCache.reconcileInterval())
+
+	// the burst goes on while the cache is below its target, at the burst interval.
+	nonceCache.cache.cache = nil
+	_, err = nonceCache.GetNonces(cosigners[:2])
+	require.Error(t, err)
+	nonceCache.lastReconcileTime = time.Now().Add(-time.Minute)
+	nonceCache.burst.concurrency = 1
+	nonceCache.cosigners = cosigners[:1]
+	nonceCache.reconcile(ctx)
+	require.Zero(t, nonceCache.cache.Size())
+	require.False(t, nonceCache.burstStart.IsZero())
+	require.Equal(t, nonceCache.burst.interval, nonceCache.reconcileInterval())
+
+	// and ends once it timed out.
+	nonceCache.burstStart = time.Now().Add(-time.Minute)
+	nonceCache.reconcile(ctx)
+	require.True(t, nonceCache.burstStart.IsZero())
+
+	// a burst is not started while disabled.
+	nonceCache.burst.disabled = true
+	nonceCache.cosigners = cosigners
+	_, err = nonceCache.GetNonces(cosigners[:2])
+	require.Error(t, err)
+	nonceCache.lastReconcileTime = time.Now().Add(-time.Minute)
+	nonceCache.reconcile(ctx)
+	require.True(t, nonceCache.burstStart.IsZero())
+}
+
+func TestNonceCachePush(t *testing.T) {
+	lcs, _ := getTestLocalCosigners(t, 2, 3)
+	cosigners := make([]Cosigner, len(lcs))
+	for i, lc := range lcs {
+		cosigners[i] = lc
+	}
+
+	leader := &ThresholdValidator{myCosigner: lcs[0]}
+	newNonceCache := func(id int) *CosignerNonceCache {
+		nc := NewCosignerNonceCache(
+			cometlog.NewNopLogger(),
+			cosigners,
+			&MockLeader{id: id, leader: leader},
+			time.Minute,
+			time.Second,
+			defaultNonceExpiration,
+			2,
+			nil,
+		)
+		nc.SetPush(defaultNonceCachePushInterval)
+		return nc
+	}
+	leaderCache, followerCache := newNonceCache(1), newNonceCache(2)
+	ctx := context.Background()
+
+	require.Equal(t, 3, leaderCache.loadN(ctx, 3))
+	require.Len(t, leaderCache.push.added, 3)
+
+	// the leader cannot apply a push.
+	require.Error(t, leaderCache.ApplyPush(leaderCache.snapshotToProto()))
+
+	// a snapshot replaces the cache of the follower.
+	followerCache.cache.Add(&CachedNonce{UUID: uuid.New(), Expiration: time.Now().Add(time.Minute)})
+	require.NoError(t, followerCache.ApplyPush(leaderCache.snapshotToProto()))
+	require.Equal(t, 3, followerCache.cache.Size())
+	for i, cn := range followerCache.cache.cache {
+		require.Equal(t, leaderCache.cache.cache[i].UUID, cn.UUID)
+		require.Len(t, cn.Nonces, 3)
+	}
+
+	// the nonces taken by the leader are removed from the follower.
+	leaderCache.push.added = make(map[uuid.UUID]*proto.CachedNonce)
+	taken, err := leaderCache.GetNonces(cosigners[:2])
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{taken.UUID[:]}, leaderCache.push.removed)
+	require.NoError(t, followerCache.ApplyPush(&proto.PushNoncesRequest{Removed: leaderCache.push.removed}))
+	require.Equal(t, 2, followerCache.cache.Size())
+
+	// a nonce loaded and taken before the push is never pushed.
+	require.Equal(t, 1, leaderCache.loadN(ctx, 1))
+	require.Len(t, leaderCache.push.added, 1)
+	leaderCache.cache.cache = leaderCache.cache.cache[2:]
+	_, err = leaderCache.GetNonces(cosigners[:2])
+	require.NoError(t, err)
+	require.Empty(t, leaderCache.push.added)
+
+	// the follower promoted to leader signs with the inherited nonces.
+	_, err = followerCache.GetNonces(cosigners[:2])
+	require.NoError(t, err)
+}
+
+func TestNonceCacheStats(t *testing.T) {
+	lcs, _ := getTestLocalCosigners(t, 2, 3)
+	cosigners := make([]Cosigner, len(lcs))
+	for i, lc := range lcs {
+		cosigners[i] = lc
+	}
+
+	newNonceCache := func() *CosignerNonceCache {
+		return NewCosignerNonceCache(
+			cometlog.NewNopLogger(),
+			cosigners,
+			&MockLeader{id: 1, leader: &ThresholdValidator{myCosigner: lcs[0]}},
+			time.Minute,
+			time.Second,
+			defaultNonceExpiration,
+			2,
+			nil,
+		)
+	}
+	file := filepath.Join(t.TempDir(), "nonce_cache_stats.json")
+
+	// no stats to save before the first reconciliation, nor to load.
+	nonceCache := newNonceCache()
+	require.NoError(t, nonceCache.SaveStats(file))
+	require.NoFileExists(t, file)
+	require.NoError(t, nonceCache.LoadStats(file))
+
+	nonceCache.movingAverage.add(time.Minute, 120)
+	nonceCache.controller.latency = 200 * time.Millisecond
+	nonceCache.lastReconcileNonces.Store(100)
+	nonceCache.lastReconcileTime = time.Now().Add(-time.Minute)
+	nonceCache.reconcile(context.Background())
+	_, target := nonceCache.Size()
+	require.NoError(t, nonceCache.SaveStats(file))
+
+	// a restarted leader sizes its cache for the saved demand.
+	restarted := newNonceCache()
+	require.NoError(t, restarted.LoadStats(file))
+	require.Equal(t, nonceCache.movingAverage.average(), restarted.movingAverage.average())
+	require.Equal(t, nonceCache.stats.Load().RefillLatency, restarted.controller.latency)
+	_, restartedTarget := restarted.Size()
+	require.Equal(t, target, restartedTarget)
+
+	// stale stats are discarded.
+	stats := *nonceCache.stats.Load()
+	stats.SavedAt = time.Now().Add(-2 * nonceCacheStatsMaxAge)
+	data, err := json.Marshal(&stats)
+	require.NoError(t, err)
+	require.NoError(t, writeStateFile(file, data))
+	stale := newNonceCache()
+	require.NoError(t, stale.LoadStats(file))
+	require.Empty(t, stale.movingAverage.items)
+}
+
+func TestNonceCachePruning(t *testing.T) {
+	lcs, _ := getTestLocalCosigners(t, 2, 3)
+	cosigners := make([]Cosigner, len(lcs))
+	for i, lc := range lcs {
+		cosigners[i] = lc
+	}
+
+	nonceCache := NewCosignerNonceCache(
+		cometlog.NewNopLogger(),
+		cosigners,
+		&MockLeader{id: 1, leader: &ThresholdValidator{myCosigner: lcs[0]}},
+		time.Minute,
+		time.Second,
+		defaultNonceExpiration,
+		2,
+		nil,
+	)
+	nonceCache.expirationJitter = 5 * time.Second
+
+	// the nonces loaded together expire up to the jitter apart.
+	start := time.Now()
+	require.Equal(t, 20, nonceCache.loadN(context.Background(), 20))
+	expirations := make(map[time.Time]bool)
+	for _, cn := range nonceCache.cache.cache {
+		require.True(t, cn.Expiration.After(start.Add(defaultNonceExpiration-nonceCache.expirationJitter)))
+		require.False(t, cn.Expiration.After(time.Now().Add(defaultNonceExpiration)))
+		expirations[cn.Expiration] = true
+	}
+	require.Greater(t, len(expirations), 1)
+
+	// the expired nonces are pruned wherever they are in the cache.
+	now := time.Now()
+	nonceCache.cache.cache = []*CachedNonce{
+		{UUID: uuid.New(), Expiration: now.Add(time.Second)},
+		{UUID: uuid.New(), Expiration: now.Add(-time.Second)},
+		{UUID: uuid.New(), Expiration: now.Add(2 * time.Second)},
+		{UUID: uuid.New(), Expiration: now.Add(3 * time.Second)},
+	}
+	pruner := lruNonceCachePruner{cnc: nonceCache}
+	require.Equal(t, 1, pruner.PruneNonces())
+	require.Equal(t, 3, nonceCache.cache.Size())
+
+	// the least recently loaded nonces above the target are pruned.
+	nonceCache.targetSize.Store(1)
+	newest := nonceCache.cache.cache[2]
+	require.Equal(t, 2, pruner.PruneNonces())
+	require.Equal(t, []*CachedNonce{newest}, nonceCache.cache.cache)
+
+	p, err := (&NonceCacheConfig{Pruning: NonceCachePruningLRU, ExpirationJitter: "2s"}).params()
+	require.NoError(t, err)
+	require.True(t, p.lruPruning)
+	require.Equal(t, 2*time.Second, p.expirationJitter)
+	require.Error(t, (&NonceCacheConfig{Pruning: "fifo"}).Validate())
+	require.Error(t, (&NonceCacheConfig{ExpirationJitter: "-1s"}).Validate())
+	require.Error(t, (&NonceCacheConfig{Expiration: "5s", ExpirationJitter: "5s"}).Validate())
+}
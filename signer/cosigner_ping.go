@@ -0,0 +1,143 @@
+package signer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/strangelove-ventures/horcrux/signer/proto"
+)
+
+// CosignerPing is the result of pinging a cosigner over the cosigner gRPC API, to debug a cosigner
+// that is unreachable.
+type CosignerPing struct {
+	ShardID int    `json:"shard_id"`
+	Address string `json:"address"`
+
+	// DialMilliseconds is how long connecting to the cosigner over the transport took, including
+	// the TLS handshake.
+	DialMilliseconds float64 `json:"dial_ms,omitempty"`
+
+	// TLS is set if the transport connects to the cosigner over TLS.
+	TLS *CosignerPingTLS `json:"tls,omitempty"`
+
+	ProtocolVersion uint32         `json:"protocol_version,omitempty"`
+	Build           *CosignerBuild `json:"build,omitempty"`
+	VersionSkew     VersionSkew    `json:"version_skew,omitempty"`
+
+	// RTTMilliseconds are the round trip times of the pings that were answered.
+	RTTMilliseconds []float64 `json:"rtt_ms,omitempty"`
+
+	// Error is the first step that failed: dialing, the handshake or a ping.
+	Error string `json:"error,omitempty"`
+}
+
+// CosignerPingTLS is the TLS connection to a cosigner and its certificate.
+type CosignerPingTLS struct {
+	Version     string    `json:"version"`
+	CipherSuite string    `json:"cipher_suite"`
+	Subject     string    `json:"subject,omitempty"`
+	Issuer      string    `json:"issuer,omitempty"`
+	NotAfter    time.Time `json:"not_after,omitempty"`
+}
+
+// tlsCosignerTransport is implemented by the transports that may connect to the cosigners over TLS.
+type tlsCosignerTransport interface {
+	dialContextTLS(ctx context.Context, address string) (net.Conn, *tls.ConnectionState, error)
+}
+
+// Reachable returns whether every ping was answered.
+func (p CosignerPing) Reachable() bool {
+	return p.Error == ""
+}
+
+// PingCosigner connects to the cosigner at the p2p address over the transport, shakes hands with
+// it, and pings it count times over the cosigner gRPC API, each within the timeout. It stops at the
+// first step that fails.
+func PingCosigner(
+	ctx context.Context,
+	shardID int,
+	address string,
+	transport CosignerTransport,
+	count int,
+	timeout time.Duration,
+) CosignerPing {
+	res := CosignerPing{ShardID: shardID, Address: address}
+
+	// the connection over the transport is dialed once, to time it and inspect the TLS connection
+	// apart from gRPC, which dials on its own.
+	hostPort := address
+	if u, err := url.Parse(address); err == nil && u.Host != "" {
+		hostPort = u.Host
+	}
+	dialCtx, cancel := context.WithTimeout(ctx, timeout)
+	start := time.Now()
+	var conn net.Conn
+	var state *tls.ConnectionState
+	var err error
+	if t, ok := transport.(tlsCosignerTransport); ok {
+		conn, state, err = t.dialContextTLS(dialCtx, hostPort)
+	} else {
+		conn, err = transport.DialContext(dialCtx, hostPort)
+	}
+	cancel()
+	if err != nil {
+		res.Error = fmt.Sprintf("failed to dial: %v", err)
+		return res
+	}
+	res.DialMilliseconds = float64(time.Since(start)) / float64(time.Millisecond)
+	conn.Close()
+	if state != nil {
+		res.TLS = newCosignerPingTLS(state)
+	}
+
+	rc, err := NewRemoteCosigner(shardID, address, transport)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	defer rc.conn.Close()
+
+	handshakeCtx, cancel := context.WithTimeout(ctx, timeout)
+	version, err := rc.Handshake(handshakeCtx, 0)
+	cancel()
+	if err != nil {
+		res.Error = fmt.Sprintf("failed handshake: %v", err)
+		return res
+	}
+	res.ProtocolVersion = version
+	if build, ok := rc.Build(); ok {
+		res.Build = &build
+		res.VersionSkew = rc.VersionSkew()
+	}
+
+	for i := 0; i < count; i++ {
+		pingCtx, cancel := context.WithTimeout(ctx, timeout)
+		start := time.Now()
+		_, err := rc.client.Ping(pingCtx, &proto.PingRequest{})
+		cancel()
+		if err != nil {
+			res.Error = fmt.Sprintf("failed ping: %v", err)
+			return res
+		}
+		res.RTTMilliseconds = append(res.RTTMilliseconds, float64(time.Since(start))/float64(time.Millisecond))
+	}
+	return res
+}
+
+func newCosignerPingTLS(state *tls.ConnectionState) *CosignerPingTLS {
+	t := &CosignerPingTLS{
+		Version:     tls.VersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+	}
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		t.Subject = cert.Subject.String()
+		t.Issuer = cert.Issuer.String()
+		t.NotAfter = cert.NotAfter
+	}
+	return t
+}
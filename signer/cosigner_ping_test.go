@@ -0,0 +1,64 @@
+package signer
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/strangelove-ventures/horcrux/signer/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestPingCosigner(t *testing.T) {
+	defer func(version string) { SoftwareVersion = version }(SoftwareVersion)
+	SoftwareVersion = "v3.2.1"
+
+	peerVal, _ := newPauseTestValidator(t, 2)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcServer := grpc.NewServer()
+	proto.RegisterCosignerServer(grpcServer, NewCosignerGRPCServer(peerVal.myCosigner, nil, nil))
+	go func() { _ = grpcServer.Serve(ln) }()
+	defer grpcServer.Stop()
+
+	ctx := context.Background()
+	ping := PingCosigner(ctx, 2, "tcp://"+ln.Addr().String(), TCPCosignerTransport{}, 3, time.Second)
+	require.True(t, ping.Reachable(), ping.Error)
+	require.Positive(t, ping.DialMilliseconds)
+	require.Len(t, ping.RTTMilliseconds, 3)
+	require.Equal(t, CosignerProtocolVersion, ping.ProtocolVersion)
+	require.Equal(t, &CosignerBuild{Version: "v3.2.1"}, ping.Build)
+	require.Equal(t, VersionSkewNone, ping.VersionSkew)
+	require.Nil(t, ping.TLS)
+
+	// nothing listens on port 1.
+	ping = PingCosigner(ctx, 3, "tcp://127.0.0.1:1", TCPCosignerTransport{}, 3, time.Second)
+	require.False(t, ping.Reachable())
+	require.Contains(t, ping.Error, "failed to dial")
+	require.Empty(t, ping.RTTMilliseconds)
+}
+
+func TestNewCosignerPingTLS(t *testing.T) {
+	notAfter := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	res := newCosignerPingTLS(&tls.ConnectionState{
+		Version:     tls.VersionTLS13,
+		CipherSuite: tls.TLS_AES_128_GCM_SHA256,
+		PeerCertificates: []*x509.Certificate{{
+			Subject:  pkix.Name{CommonName: "cosigner-2"},
+			Issuer:   pkix.Name{CommonName: "horcrux CA"},
+			NotAfter: notAfter,
+		}},
+	})
+	require.Equal(t, &CosignerPingTLS{
+		Version:     "TLS 1.3",
+		CipherSuite: "TLS_AES_128_GCM_SHA256",
+		Subject:     "CN=cosigner-2",
+		Issuer:      "CN=horcrux CA",
+		NotAfter:    notAfter,
+	}, res)
+}
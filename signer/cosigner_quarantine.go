@@ -0,0 +1,198 @@
+package signer
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultQuarantineMinScore     = 0.5
+	defaultQuarantineReadmitScore = 0.9
+	defaultQuarantineReadmitAfter = time.Minute
+)
+
+// CosignerQuarantineConfig is the on disk config format for the automatic quarantine of the peer
+// cosigners whose health score degrades. A quarantined cosigner is excluded from the nonce
+// generation and the signing until its score recovers.
+type CosignerQuarantineConfig struct {
+	// MinScore is the health score below which a cosigner is quarantined. Defaults to 0.5.
+	MinScore float64 `yaml:"minScore,omitempty"`
+
+	// ReadmitScore is the health score that a quarantined cosigner must keep for readmitAfter
+	// before it is readmitted. Defaults to 0.9.
+	ReadmitScore float64 `yaml:"readmitScore,omitempty"`
+
+	// ReadmitAfter is how long the score of a quarantined cosigner must stay at or above
+	// readmitScore before it is readmitted. Defaults to 1m.
+	ReadmitAfter string `yaml:"readmitAfter,omitempty"`
+
+	// MaxLatency is the recent latency of a cosigner above which its score is lowered in
+	// proportion to the latency. Defaults to half of grpcTimeout.
+	MaxLatency string `yaml:"maxLatency,omitempty"`
+}
+
+// cosignerQuarantine are the parameters of the quarantine of the peer cosigners.
+type cosignerQuarantine struct {
+	minScore     float64
+	readmitScore float64
+	readmitAfter time.Duration
+	maxLatency   time.Duration
+}
+
+func (cfg *CosignerQuarantineConfig) Validate() error {
+	if cfg == nil {
+		return nil
+	}
+	_, err := cfg.params(0)
+	return err
+}
+
+// params returns the parameters of the quarantine, with the latency limit defaulting to half of
+// the gRPC timeout.
+func (cfg *CosignerQuarantineConfig) params(grpcTimeout time.Duration) (cosignerQuarantine, error) {
+	q := cosignerQuarantine{
+		minScore:     cfg.MinScore,
+		readmitScore: cfg.ReadmitScore,
+	}
+	if q.minScore == 0 {
+		q.minScore = defaultQuarantineMinScore
+	}
+	if q.readmitScore == 0 {
+		q.readmitScore = defaultQuarantineReadmitScore
+	}
+	if q.minScore < 0 || q.readmitScore > 1 || q.minScore > q.readmitScore {
+		return cosignerQuarantine{}, fmt.Errorf(
+			"quarantine minScore (%v) and readmitScore (%v) must be between 0 and 1, minScore not above readmitScore",
+			q.minScore, q.readmitScore,
+		)
+	}
+	var err error
+	if q.readmitAfter, err = parseDurationOrDefault(cfg.ReadmitAfter, defaultQuarantineReadmitAfter); err != nil {
+		return cosignerQuarantine{}, fmt.Errorf("invalid quarantine readmitAfter: %w", err)
+	}
+	if q.maxLatency, err = parseDurationOrDefault(cfg.MaxLatency, grpcTimeout/2); err != nil {
+		return cosignerQuarantine{}, fmt.Errorf("invalid quarantine maxLatency: %w", err)
+	}
+	if q.readmitAfter < 0 || q.maxLatency < 0 {
+		return cosignerQuarantine{}, fmt.Errorf("quarantine readmitAfter (%s) and maxLatency (%s) cannot be negative",
+			q.readmitAfter, q.maxLatency)
+	}
+	return q, nil
+}
+
+// SetQuarantine enables the quarantine of the peer cosigners whose score falls below the minimum,
+// as long as threshold cosigners, including this one, are left to sign.
+func (ch *CosignerHealth) SetQuarantine(q cosignerQuarantine, threshold int) {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	ch.quarantine = &q
+	ch.threshold = threshold
+}
+
+// Quarantined returns true if the cosigner is quarantined, and so excluded from the nonce
+// generation and the signing.
+func (ch *CosignerHealth) Quarantined(cosigner Cosigner) bool {
+	ch.mu.RLock()
+	defer ch.mu.RUnlock()
+	_, ok := ch.quarantined[cosigner.GetID()]
+	return ok
+}
+
+// Score returns the health score of the cosigner between 0 and 1, higher is better, or false if no
+// request to the cosigner was recorded yet.
+func (ch *CosignerHealth) Score(cosigner Cosigner) (float64, bool) {
+	ch.mu.RLock()
+	defer ch.mu.RUnlock()
+	if _, ok := ch.stats[cosigner.GetID()]; !ok {
+		return 0, false
+	}
+	return ch.reputation(cosigner.GetID()), true
+}
+
+// reputation returns the health score of the cosigner between 0 and 1: the share of its recent
+// requests that succeeded, times the share of its recent shares that combined into valid signatures,
+// lowered in proportion to its recent latency above the latency limit. ch.mu must be held.
+func (ch *CosignerHealth) reputation(id int) float64 {
+	s, ok := ch.stats[id]
+	if !ok {
+		return 1
+	}
+	score := (1 - s.errorRate) * (1 - s.invalidRate)
+	if ch.quarantine != nil && ch.quarantine.maxLatency > 0 && s.latency > float64(ch.quarantine.maxLatency) {
+		score *= float64(ch.quarantine.maxLatency) / s.latency
+	}
+	return score
+}
+
+// RecordShares records whether the shares of the peer cosigners combined into a valid signature.
+// The share that spoiled an invalid signature is not known, so every cosigner that contributed
+// is penalized; a cosigner that keeps sending invalid shares is penalized in every attempt.
+func (ch *CosignerHealth) RecordShares(ids []int, valid bool) {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	for _, id := range ids {
+		s := ch.statsFor(id)
+		if valid {
+			s.invalidRate -= statsWeight * s.invalidRate
+		} else {
+			s.invalidRate += statsWeight * (1 - s.invalidRate)
+		}
+	}
+}
+
+// recordProbe records the outcome of the ping of a quarantined cosigner, which is not sent any
+// request, so that its score can recover.
+func (ch *CosignerHealth) recordProbe(cosigner Cosigner, latency time.Duration, err error) {
+	if !ch.Quarantined(cosigner) {
+		return
+	}
+	ch.RecordResult(cosigner, latency, err)
+	if err == nil {
+		ch.RecordShares([]int{cosigner.GetID()}, true)
+	}
+}
+
+// updateQuarantine quarantines the cosigners whose score fell below the minimum, and readmits the
+// quarantined cosigners whose score stayed at or above the readmission score long enough at now.
+func (ch *CosignerHealth) updateQuarantine(now time.Time) {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+
+	for _, c := range ch.cosigners {
+		id := c.GetID()
+		peerID := strconv.Itoa(id)
+		score := ch.reputation(id)
+		cosignerScore.WithLabelValues(peerID).Set(score)
+		if ch.quarantine == nil {
+			continue
+		}
+
+		recovering, quarantined := ch.quarantined[id]
+		switch {
+		case quarantined && score < ch.quarantine.readmitScore:
+			ch.quarantined[id] = time.Time{}
+		case quarantined && recovering.IsZero():
+			ch.quarantined[id] = now
+		case quarantined && now.Sub(recovering) >= ch.quarantine.readmitAfter:
+			delete(ch.quarantined, id)
+			ch.logger.Info("Readmitted cosigner after its score recovered", "cosigner", id, "score", score)
+		case !quarantined && score < ch.quarantine.minScore:
+			// the peers left to sign with this cosigner must still make the threshold.
+			if len(ch.cosigners)-len(ch.quarantined)-1 < ch.threshold-1 {
+				ch.logger.Debug("Not quarantining cosigner, too few cosigners would be left to sign",
+					"cosigner", id, "score", score)
+				break
+			}
+			ch.quarantined[id] = time.Time{}
+			totalCosignerQuarantines.WithLabelValues(peerID).Inc()
+			ch.logger.Error("Quarantined cosigner with degraded score", "cosigner", id, "score", score)
+		}
+		_, quarantined = ch.quarantined[id]
+		if quarantined {
+			cosignerQuarantined.WithLabelValues(peerID).Set(1)
+		} else {
+			cosignerQuarantined.WithLabelValues(peerID).Set(0)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package signer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCosignerQuarantineConfig(t *testing.T) {
+	q, err := (&CosignerQuarantineConfig{}).params(2 * time.Second)
+	require.NoError(t, err)
+	require.Equal(t, cosignerQuarantine{
+		minScore:     0.5,
+		readmitScore: 0.9,
+		readmitAfter: time.Minute,
+		maxLatency:   time.Second,
+	}, q)
+
+	require.Error(t, (&CosignerQuarantineConfig{MinScore: 0.9, ReadmitScore: 0.5}).Validate())
+	require.Error(t, (&CosignerQuarantineConfig{ReadmitScore: 1.5}).Validate())
+	require.Error(t, (&CosignerQuarantineConfig{ReadmitAfter: "soon"}).Validate())
+	require.Error(t, (&CosignerQuarantineConfig{MaxLatency: "-1s"}).Validate())
+	require.NoError(t, (*CosignerQuarantineConfig)(nil).Validate())
+}
+
+func TestCosignerQuarantine(t *testing.T) {
+	c2, c3, c4 := &RemoteCosigner{id: 2}, &RemoteCosigner{id: 3}, &RemoteCosigner{id: 4}
+	ch := NewCosignerHealth(cometlog.NewNopLogger(), 1, []Cosigner{c2, c3, c4}, &MockLeader{id: 1})
+	ch.rtt = map[int]int64{2: 100, 3: 200, 4: 300}
+	ch.SetQuarantine(cosignerQuarantine{
+		minScore:     0.5,
+		readmitScore: 0.9,
+		readmitAfter: time.Minute,
+		maxLatency:   time.Second,
+	}, 2)
+
+	now := time.Now()
+	failed := errors.New("failed")
+
+	// a cosigner failing its requests is quarantined and left out of the signing.
+	for i := 0; i < 4; i++ {
+		ch.RecordResult(c2, time.Millisecond, failed)
+	}
+	ch.updateQuarantine(now)
+	require.True(t, ch.Quarantined(c2))
+	require.Equal(t, []int{3, 4}, cosignerIDs(ch.GetFastest()))
+
+	// shares that combine into invalid signatures lower the score of the cosigners that sent them.
+	for i := 0; i < 4; i++ {
+		ch.RecordShares([]int{3}, false)
+	}
+	score, ok := ch.Score(c3)
+	require.True(t, ok)
+	require.Less(t, score, 0.5)
+
+	// the last peer is not quarantined, since the cosigners would be fewer than the threshold.
+	ch.RecordResult(c4, 2*time.Second, nil)
+	ch.updateQuarantine(now)
+	require.True(t, ch.Quarantined(c3))
+	require.False(t, ch.Quarantined(c4))
+	require.Equal(t, []int{4}, cosignerIDs(ch.GetFastest()))
+
+	// a quarantined cosigner recovers with its pings, and is readmitted once it stayed recovered.
+	for i := 0; i < 10; i++ {
+		ch.recordProbe(c2, time.Millisecond, nil)
+	}
+	ch.updateQuarantine(now)
+	require.True(t, ch.Quarantined(c2))
+	ch.updateQuarantine(now.Add(30 * time.Second))
+	require.True(t, ch.Quarantined(c2))
+
+	// a relapse restarts the recovery.
+	ch.recordProbe(c2, time.Millisecond, failed)
+	ch.updateQuarantine(now.Add(40 * time.Second))
+	for i := 0; i < 5; i++ {
+		ch.recordProbe(c2, time.Millisecond, nil)
+	}
+	ch.updateQuarantine(now.Add(50 * time.Second))
+	ch.updateQuarantine(now.Add(100 * time.Second))
+	require.True(t, ch.Quarantined(c2))
+	ch.updateQuarantine(now.Add(110 * time.Second))
+	require.False(t, ch.Quarantined(c2))
+	require.Equal(t, []int{2, 4}, cosignerIDs(ch.GetFastest()))
+}
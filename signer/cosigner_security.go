@@ -19,4 +19,10 @@ type CosignerSecurity interface {
 		encryptedNonceShare []byte,
 		signature []byte,
 	) (noncePub []byte, nonceShare []byte, err error)
+
+	// Sign signs the message for authentication.
+	Sign(msg []byte) ([]byte, error)
+
+	// Verify verifies the signature of the message to authenticate the source cosigner.
+	Verify(id int, msg []byte, signature []byte) error
 }
@@ -251,3 +251,23 @@ func (c *CosignerSecurityECIES) DecryptAndVerify(
 
 	return noncePub, nonceShare, nil
 }
+
+// Sign signs the message with our private key for authentication.
+func (c *CosignerSecurityECIES) Sign(msg []byte) ([]byte, error) {
+	hash := sha256.Sum256(msg)
+	return ecdsa.SignASN1(rand.Reader, c.key.ECIESKey.ExportECDSA(), hash[:])
+}
+
+// Verify verifies the signature of the message to authenticate the source cosigner.
+func (c *CosignerSecurityECIES) Verify(id int, msg []byte, signature []byte) error {
+	pubKey, ok := c.eciesPubKeys[id]
+	if !ok {
+		return fmt.Errorf("unknown cosigner: %d", id)
+	}
+
+	hash := sha256.Sum256(msg)
+	if !ecdsa.VerifyASN1(pubKey.PublicKey.ExportECDSA(), hash[:], signature) {
+		return fmt.Errorf("signature is invalid")
+	}
+	return nil
+}
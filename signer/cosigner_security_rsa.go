@@ -233,3 +233,20 @@ func (c *CosignerSecurityRSA) DecryptAndVerify(
 
 	return noncePub, nonceShare, nil
 }
+
+// Sign signs the message with our private key for authentication.
+func (c *CosignerSecurityRSA) Sign(msg []byte) ([]byte, error) {
+	hash := sha256.Sum256(msg)
+	return rsa.SignPSS(rand.Reader, &c.key.RSAKey, crypto.SHA256, hash[:], nil)
+}
+
+// Verify verifies the signature of the message to authenticate the source cosigner.
+func (c *CosignerSecurityRSA) Verify(id int, msg []byte, signature []byte) error {
+	pubKey, ok := c.rsaPubKeys[id]
+	if !ok {
+		return fmt.Errorf("unknown cosigner: %d", id)
+	}
+
+	hash := sha256.Sum256(msg)
+	return rsa.VerifyPSS(&pubKey.PublicKey, crypto.SHA256, hash[:], signature, nil)
+}
@@ -0,0 +1,158 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"golang.org/x/net/proxy"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+const (
+	// CosignerTransportTCP connects cosigners directly over TCP.
+	CosignerTransportTCP = "tcp"
+
+	// CosignerTransportTor connects cosigners through a Tor SOCKS5 proxy so that
+	// peers can be addressed as .onion services.
+	CosignerTransportTor = "tor"
+
+	defaultTorSOCKSAddr = "127.0.0.1:9050"
+)
+
+const (
+	// cosignerKeepaliveTime is the interval of the keepalive pings of idle connections to peer
+	// cosigners. A connection to a peer that stopped answering, e.g. a rescheduled pod whose old IP
+	// is gone, is closed after the keepalive timeout and dialed again, which resolves the host name
+	// of the peer again instead of waiting for TCP to give up on the old IP.
+	cosignerKeepaliveTime    = 10 * time.Second
+	cosignerKeepaliveTimeout = 5 * time.Second
+)
+
+// cosignerKeepaliveDialOption is the keepalive of the gRPC connections to peer cosigners.
+func cosignerKeepaliveDialOption() grpc.DialOption {
+	return grpc.WithKeepaliveParams(keepalive.ClientParameters{
+		Time:                cosignerKeepaliveTime,
+		Timeout:             cosignerKeepaliveTimeout,
+		PermitWithoutStream: true,
+	})
+}
+
+// cosignerKeepaliveServerOption permits the keepalive pings of peer cosigners, which gRPC servers
+// otherwise answer by closing the connection.
+func cosignerKeepaliveServerOption() grpc.ServerOption {
+	return grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+		MinTime:             cosignerKeepaliveTime / 2,
+		PermitWithoutStream: true,
+	})
+}
+
+// CosignerTransport establishes the connections used for cosigner gRPC and raft traffic.
+// Addresses are host:port as derived from the cosigner p2pAddr. The host is passed to DialContext
+// unresolved, so that a host name is resolved again whenever a connection is dialed.
+type CosignerTransport interface {
+	// Listen accepts connections from peer cosigners on the address.
+	Listen(address string) (net.Listener, error)
+
+	// DialContext connects to a peer cosigner at the address.
+	DialContext(ctx context.Context, address string) (net.Conn, error)
+}
+
+var _ CosignerTransport = TCPCosignerTransport{}
+
+// TCPCosignerTransport is the default CosignerTransport using plain TCP sockets.
+type TCPCosignerTransport struct{}
+
+func (TCPCosignerTransport) Listen(address string) (net.Listener, error) {
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse local address: %s, %v", address, err)
+	}
+	return net.Listen("tcp", fmt.Sprintf(":%s", port))
+}
+
+func (TCPCosignerTransport) DialContext(ctx context.Context, address string) (net.Conn, error) {
+	var d net.Dialer
+	return d.DialContext(ctx, "tcp", address)
+}
+
+var _ CosignerTransport = &TorCosignerTransport{}
+
+// TorCosignerTransport dials peer cosigners through the Tor SOCKS5 proxy and only
+// listens on the loopback interface. The local onion service must be configured in
+// torrc to forward to the p2pAddr port, e.g. "HiddenServicePort 2222 127.0.0.1:2222".
+type TorCosignerTransport struct {
+	dialer proxy.ContextDialer
+}
+
+// NewTorCosignerTransport returns a TorCosignerTransport using the SOCKS5 proxy at socksAddr.
+func NewTorCosignerTransport(socksAddr string) (*TorCosignerTransport, error) {
+	if socksAddr == "" {
+		socksAddr = defaultTorSOCKSAddr
+	}
+	if _, _, err := net.SplitHostPort(socksAddr); err != nil {
+		return nil, fmt.Errorf("invalid tor socks address: %s, %v", socksAddr, err)
+	}
+	dialer, err := proxy.SOCKS5("tcp", socksAddr, nil, proxy.Direct)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tor socks dialer: %w", err)
+	}
+	contextDialer, ok := dialer.(proxy.ContextDialer)
+	if !ok {
+		return nil, fmt.Errorf("tor socks dialer does not support contexts")
+	}
+	return &TorCosignerTransport{dialer: contextDialer}, nil
+}
+
+func (t *TorCosignerTransport) Listen(address string) (net.Listener, error) {
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse local address: %s, %v", address, err)
+	}
+	// Only reachable through the onion service, never directly.
+	return net.Listen("tcp", net.JoinHostPort("127.0.0.1", port))
+}
+
+func (t *TorCosignerTransport) DialContext(ctx context.Context, address string) (net.Conn, error) {
+	// The address is passed to the proxy unresolved so .onion hosts are resolved by Tor.
+	return t.dialer.DialContext(ctx, "tcp", address)
+}
+
+// CosignerTransportConfig is the on disk config format for the cosigner transport.
+type CosignerTransportConfig struct {
+	Type string `yaml:"type"`
+
+	// SOCKSAddr is the Tor SOCKS5 proxy address for the tor transport. Defaults to 127.0.0.1:9050.
+	SOCKSAddr string `yaml:"socksAddr,omitempty"`
+
+	// Path is the HTTP path of the websocket transport endpoint. Defaults to /horcrux.
+	Path string `yaml:"path,omitempty"`
+
+	// TLS dials peers with wss:// for the websocket transport.
+	TLS bool `yaml:"tls,omitempty"`
+
+	// CertFile and KeyFile serve the websocket transport over TLS.
+	// Leave empty when TLS is terminated by a reverse proxy or CDN.
+	CertFile string `yaml:"certFile,omitempty"`
+	KeyFile  string `yaml:"keyFile,omitempty"`
+}
+
+// CosignerTransport returns the configured CosignerTransport, defaulting to TCP.
+func (cfg *ThresholdModeConfig) CosignerTransport() (CosignerTransport, error) {
+	if cfg.Transport == nil {
+		return TCPCosignerTransport{}, nil
+	}
+	switch cfg.Transport.Type {
+	case "", CosignerTransportTCP:
+		return TCPCosignerTransport{}, nil
+	case CosignerTransportTor:
+		return NewTorCosignerTransport(cfg.Transport.SOCKSAddr)
+	case CosignerTransportWebSocket:
+		return NewWebSocketCosignerTransport(cfg.Transport)
+	default:
+		return nil, fmt.Errorf("unknown cosigner transport %q, must be one of %v",
+			cfg.Transport.Type, []string{CosignerTransportTCP, CosignerTransportTor, CosignerTransportWebSocket})
+	}
+}
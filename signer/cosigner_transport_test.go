@@ -0,0 +1,152 @@
+package signer
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// serveSOCKS5 accepts a single no-auth SOCKS5 CONNECT, reports the requested
+// destination on dest, and connects the client to target regardless of the destination.
+func serveSOCKS5(t *testing.T, ln net.Listener, target string, dest chan<- string) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	// greeting: version, nmethods, methods
+	buf := make([]byte, 2)
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	_, err = io.ReadFull(conn, make([]byte, buf[1]))
+	require.NoError(t, err)
+	_, err = conn.Write([]byte{0x05, 0x00})
+	require.NoError(t, err)
+
+	// request: version, cmd, reserved, address type
+	req := make([]byte, 4)
+	_, err = io.ReadFull(conn, req)
+	require.NoError(t, err)
+	require.Equal(t, byte(0x03), req[3], "expected unresolved domain name")
+	l := make([]byte, 1)
+	_, err = io.ReadFull(conn, l)
+	require.NoError(t, err)
+	host := make([]byte, l[0])
+	_, err = io.ReadFull(conn, host)
+	require.NoError(t, err)
+	port := make([]byte, 2)
+	_, err = io.ReadFull(conn, port)
+	require.NoError(t, err)
+	dest <- net.JoinHostPort(string(host), strconv.Itoa(int(binary.BigEndian.Uint16(port))))
+
+	upstream, err := net.Dial("tcp", target)
+	require.NoError(t, err)
+	defer upstream.Close()
+
+	_, err = conn.Write([]byte{0x05, 0x00, 0x00, 0x01, 127, 0, 0, 1, 0, 0})
+	require.NoError(t, err)
+
+	go func() { _, _ = io.Copy(upstream, conn) }()
+	_, _ = io.Copy(conn, upstream)
+}
+
+func TestTorCosignerTransport(t *testing.T) {
+	echo, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer echo.Close()
+	go func() {
+		conn, err := echo.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = io.Copy(conn, conn)
+	}()
+
+	socks, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer socks.Close()
+
+	dest := make(chan string, 1)
+	go serveSOCKS5(t, socks, echo.Addr().String(), dest)
+
+	cfg := ThresholdModeConfig{
+		Transport: &CosignerTransportConfig{
+			Type:      CosignerTransportTor,
+			SOCKSAddr: socks.Addr().String(),
+		},
+	}
+	transport, err := cfg.CosignerTransport()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	const onion = "horcruxexampleonionaddressxxxxxxxxxxxxxxxxxxxxxxxxxxxxx.onion:2222"
+	conn, err := transport.DialContext(ctx, onion)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.Equal(t, onion, <-dest)
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	res := make([]byte, 4)
+	_, err = io.ReadFull(conn, res)
+	require.NoError(t, err)
+	require.Equal(t, "ping", string(res))
+
+	ln, err := transport.Listen("horcruxexampleonionaddressxxxxxxxxxxxxxxxxxxxxxxxxxxxxx.onion:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	require.True(t, ln.Addr().(*net.TCPAddr).IP.IsLoopback())
+}
+
+func TestWebSocketCosignerTransport(t *testing.T) {
+	cfg := ThresholdModeConfig{
+		Transport: &CosignerTransportConfig{
+			Type: CosignerTransportWebSocket,
+		},
+	}
+	transport, err := cfg.CosignerTransport()
+	require.NoError(t, err)
+
+	ln, err := transport.Listen("127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = io.Copy(conn, conn)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	port := strconv.Itoa(ln.Addr().(*net.TCPAddr).Port)
+	conn, err := transport.DialContext(ctx, net.JoinHostPort("127.0.0.1", port))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	msg := make([]byte, 64*1024)
+	for i := range msg {
+		msg[i] = byte(i)
+	}
+	go func() { _, _ = conn.Write(msg) }()
+
+	res := make([]byte, len(msg))
+	_, err = io.ReadFull(conn, res)
+	require.NoError(t, err)
+	require.Equal(t, msg, res)
+}
@@ -0,0 +1,216 @@
+package signer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+const (
+	// CosignerProtocolVersion is the highest cosigner gRPC protocol version supported by this build.
+	CosignerProtocolVersion uint32 = 3
+
+	// MinCosignerProtocolVersion is the lowest cosigner gRPC protocol version supported by this build.
+	MinCosignerProtocolVersion uint32 = 1
+
+	// legacyCosignerProtocolVersion is assumed for peers which do not implement the Handshake RPC.
+	legacyCosignerProtocolVersion uint32 = 1
+)
+
+// SoftwareVersion is the horcrux version reported to peer cosigners during the handshake.
+var SoftwareVersion = ""
+
+// SoftwareCommit is the git commit of the horcrux build reported to peer cosigners during the
+// handshake.
+var SoftwareCommit = ""
+
+const (
+	// VersionSkewPolicyWarn logs and counts the peer cosigners on an incompatible version.
+	VersionSkewPolicyWarn = "warn"
+
+	// VersionSkewPolicyRefuse also excludes the peer cosigners on an incompatible version from the
+	// nonce generation and the signing, as if they were unreachable.
+	VersionSkewPolicyRefuse = "refuse"
+)
+
+// CosignerBuild is the software version and git commit of the build of a cosigner.
+type CosignerBuild struct {
+	Version string `json:"version,omitempty"`
+	Commit  string `json:"commit,omitempty"`
+}
+
+// localCosignerBuild returns the build of this cosigner.
+func localCosignerBuild() CosignerBuild {
+	return CosignerBuild{Version: SoftwareVersion, Commit: SoftwareCommit}
+}
+
+// VersionSkew is how far apart the software versions of two cosigners are.
+type VersionSkew string
+
+const (
+	VersionSkewNone  VersionSkew = "none"
+	VersionSkewPatch VersionSkew = "patch"
+	VersionSkewMinor VersionSkew = "minor"
+	VersionSkewMajor VersionSkew = "major"
+
+	// VersionSkewUnknown is a version that is not a release version, e.g. of a development build,
+	// of a build different from the other one.
+	VersionSkewUnknown VersionSkew = "unknown"
+)
+
+// Incompatible returns whether cosigners this far apart are on incompatible versions. Releases of
+// different major or minor versions may change the messages exchanged by the cosigners, while
+// patch releases do not.
+func (s VersionSkew) Incompatible() bool {
+	return s == VersionSkewMinor || s == VersionSkewMajor
+}
+
+// metric returns the value of the version skew metric: 0 to 3 from none to major, -1 if unknown.
+func (s VersionSkew) metric() float64 {
+	switch s {
+	case VersionSkewNone:
+		return 0
+	case VersionSkewPatch:
+		return 1
+	case VersionSkewMinor:
+		return 2
+	case VersionSkewMajor:
+		return 3
+	default:
+		return -1
+	}
+}
+
+// releaseVersionRegexp matches a release version, e.g. v3.2.1 or 3.2.1-rc1.
+var releaseVersionRegexp = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)(?:[-+].*)?$`)
+
+// parseReleaseVersion returns the major, minor and patch version of a release version.
+func parseReleaseVersion(version string) ([3]int, bool) {
+	var v [3]int
+	m := releaseVersionRegexp.FindStringSubmatch(version)
+	if m == nil {
+		return v, false
+	}
+	for i := range v {
+		n, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			return v, false
+		}
+		v[i] = n
+	}
+	return v, true
+}
+
+// versionSkew returns how far apart the versions of the builds are.
+func versionSkew(local, remote CosignerBuild) VersionSkew {
+	l, lok := parseReleaseVersion(local.Version)
+	r, rok := parseReleaseVersion(remote.Version)
+	switch {
+	case !lok || !rok:
+		if local.Version != "" && local == remote {
+			return VersionSkewNone
+		}
+		return VersionSkewUnknown
+	case l[0] != r[0]:
+		return VersionSkewMajor
+	case l[1] != r[1]:
+		return VersionSkewMinor
+	case l[2] != r[2]:
+		return VersionSkewPatch
+	default:
+		return VersionSkewNone
+	}
+}
+
+type VersionSkewError struct {
+	msg string
+}
+
+func (e *VersionSkewError) Error() string { return e.msg }
+
+func newVersionSkewError(id int, local, remote CosignerBuild, skew VersionSkew) *VersionSkewError {
+	return &VersionSkewError{
+		msg: fmt.Sprintf("cosigner %d is on version %s (commit %s), incompatible with version %s (commit %s): %s "+
+			"version skew, upgrade the cosigners to the same minor version",
+			id, buildVersion(remote.Version), buildVersion(remote.Commit),
+			buildVersion(local.Version), buildVersion(local.Commit), skew,
+		),
+	}
+}
+
+// SetVersionSkewPolicy sets whether the peer cosigners on an incompatible version are only logged
+// and counted, or also excluded from the nonce generation and the signing, as if unreachable.
+func (ch *CosignerHealth) SetVersionSkewPolicy(policy string) {
+	ch.refuseVersionSkew.Store(policy == VersionSkewPolicyRefuse)
+}
+
+// logVersionSkew logs the version skew of the cosigner after a handshake, and counts it if the
+// versions are incompatible.
+func (ch *CosignerHealth) logVersionSkew(cosigner *RemoteCosigner) {
+	skew := cosigner.VersionSkew()
+	build, _ := cosigner.Build()
+	switch {
+	case skew.Incompatible():
+		totalCosignerVersionIncompatible.WithLabelValues(fmt.Sprint(cosigner.GetID())).Inc()
+		ch.logger.Error(
+			"Peer cosigner is on an incompatible version",
+			"cosigner", cosigner.GetID(),
+			"refused", ch.refuseVersionSkew.Load(),
+			"error", newVersionSkewError(cosigner.GetID(), localCosignerBuild(), build, skew),
+		)
+	case skew != VersionSkewNone:
+		ch.logger.Info(
+			"Peer cosigner is on another version",
+			"cosigner", cosigner.GetID(),
+			"skew", skew,
+			"version", buildVersion(SoftwareVersion),
+			"cosigner_version", buildVersion(build.Version),
+		)
+	}
+}
+
+// refusesVersionSkew returns whether the cosigner is excluded for being on an incompatible version.
+func (ch *CosignerHealth) refusesVersionSkew(cosigner Cosigner) bool {
+	rc, ok := cosigner.(*RemoteCosigner)
+	return ok && ch.refuseVersionSkew.Load() && rc.VersionSkew().Incompatible()
+}
+
+// Excluded returns true if the cosigner is excluded from the nonce generation and the signing,
+// because it is quarantined or on an incompatible version that is refused.
+func (ch *CosignerHealth) Excluded(cosigner Cosigner) bool {
+	return ch.Quarantined(cosigner) || ch.refusesVersionSkew(cosigner)
+}
+
+// buildVersion returns the version or commit of a build, or unknown if it is not set.
+func buildVersion(v string) string {
+	if v == "" {
+		return "unknown"
+	}
+	return v
+}
+
+type ProtocolVersionError struct {
+	msg string
+}
+
+func (e *ProtocolVersionError) Error() string { return e.msg }
+
+func newProtocolVersionError(localMin, localMax, remoteMin, remoteMax uint32) *ProtocolVersionError {
+	return &ProtocolVersionError{
+		msg: fmt.Sprintf("no common cosigner protocol version, local supports %d-%d, remote supports %d-%d",
+			localMin, localMax, remoteMin, remoteMax,
+		),
+	}
+}
+
+// negotiateProtocolVersion returns the highest protocol version supported by both ranges.
+func negotiateProtocolVersion(localMin, localMax, remoteMin, remoteMax uint32) (uint32, error) {
+	version := localMax
+	if remoteMax < version {
+		version = remoteMax
+	}
+	if version < localMin || version < remoteMin {
+		return 0, newProtocolVersionError(localMin, localMax, remoteMin, remoteMax)
+	}
+	return version, nil
+}
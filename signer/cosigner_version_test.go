@@ -0,0 +1,95 @@
+package signer
+
+import (
+	"testing"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateProtocolVersion(t *testing.T) {
+	type testCase struct {
+		name                 string
+		remoteMin, remoteMax uint32
+		expected             uint32
+		expectErr            bool
+	}
+
+	testCases := []testCase{
+		{name: "same version", remoteMin: MinCosignerProtocolVersion, remoteMax: CosignerProtocolVersion, expected: CosignerProtocolVersion},
+		{name: "older peer", remoteMin: 1, remoteMax: 1, expected: 1},
+		{name: "newer peer", remoteMin: 1, remoteMax: CosignerProtocolVersion + 1, expected: CosignerProtocolVersion},
+		{name: "peer too new", remoteMin: CosignerProtocolVersion + 1, remoteMax: CosignerProtocolVersion + 2, expectErr: true},
+		{name: "peer too old", remoteMin: 0, remoteMax: 0, expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		version, err := negotiateProtocolVersion(
+			MinCosignerProtocolVersion, CosignerProtocolVersion,
+			tc.remoteMin, tc.remoteMax,
+		)
+		if tc.expectErr {
+			var verr *ProtocolVersionError
+			require.ErrorAs(t, err, &verr, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expected, version, tc.name)
+	}
+}
+
+func TestVersionSkew(t *testing.T) {
+	type testCase struct {
+		name          string
+		local, remote CosignerBuild
+		expected      VersionSkew
+	}
+
+	v321 := CosignerBuild{Version: "v3.2.1"}
+	dev := CosignerBuild{Version: "main", Commit: "abc"}
+	testCases := []testCase{
+		{name: "same version", local: v321, remote: CosignerBuild{Version: "3.2.1"}, expected: VersionSkewNone},
+		{name: "patch", local: v321, remote: CosignerBuild{Version: "v3.2.0-rc1"}, expected: VersionSkewPatch},
+		{name: "minor", local: v321, remote: CosignerBuild{Version: "v3.3.0"}, expected: VersionSkewMinor},
+		{name: "major", local: v321, remote: CosignerBuild{Version: "v4.2.1"}, expected: VersionSkewMajor},
+		{name: "legacy peer", local: v321, remote: CosignerBuild{}, expected: VersionSkewUnknown},
+		{name: "same dev build", local: dev, remote: dev, expected: VersionSkewNone},
+		{name: "other dev build", local: dev, remote: CosignerBuild{Version: "main", Commit: "def"}, expected: VersionSkewUnknown},
+		{name: "no versions", expected: VersionSkewUnknown},
+	}
+
+	for _, tc := range testCases {
+		skew := versionSkew(tc.local, tc.remote)
+		require.Equal(t, tc.expected, skew, tc.name)
+		require.Equal(t, skew == VersionSkewMinor || skew == VersionSkewMajor, skew.Incompatible(), tc.name)
+	}
+
+	err := newVersionSkewError(2, CosignerBuild{Version: "v3.2.1", Commit: "abc"}, CosignerBuild{Version: "v3.3.0"},
+		VersionSkewMinor)
+	require.EqualError(t, err, "cosigner 2 is on version v3.3.0 (commit unknown), incompatible with version v3.2.1 "+
+		"(commit abc): minor version skew, upgrade the cosigners to the same minor version")
+}
+
+func TestCosignerHealthVersionSkewPolicy(t *testing.T) {
+	defer func(version string) { SoftwareVersion = version }(SoftwareVersion)
+	SoftwareVersion = "v3.2.1"
+
+	same, patch, minor := &RemoteCosigner{id: 2}, &RemoteCosigner{id: 3}, &RemoteCosigner{id: 4}
+	same.build.Store(&CosignerBuild{Version: "v3.2.1"})
+	patch.build.Store(&CosignerBuild{Version: "v3.2.0"})
+	minor.build.Store(&CosignerBuild{Version: "v3.1.0"})
+	ch := NewCosignerHealth(cometlog.NewNopLogger(), 1, []Cosigner{same, patch, minor}, &MockLeader{id: 1})
+	ch.rtt = map[int]int64{2: 100, 3: 200, 4: 50}
+
+	// the incompatible cosigner is only warned about by default.
+	require.Len(t, ch.GetFastest(), 3)
+	require.False(t, ch.Excluded(minor))
+
+	ch.SetVersionSkewPolicy(VersionSkewPolicyRefuse)
+	fastest := ch.GetFastest()
+	require.Len(t, fastest, 2)
+	require.Equal(t, 2, fastest[0].GetID())
+	require.Equal(t, 3, fastest[1].GetID())
+	require.True(t, ch.Excluded(minor))
+	require.False(t, ch.Excluded(patch))
+}
@@ -0,0 +1,152 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// DebugServerConfig configures TLS and authentication of the debug server, which serves
+// pprof, the metrics and operational endpoints.
+type DebugServerConfig struct {
+	// CertFile and KeyFile serve the debug server over TLS.
+	CertFile string `yaml:"certFile,omitempty"`
+	KeyFile  string `yaml:"keyFile,omitempty"`
+
+	// Username and PasswordFile, the file holding the password, enable basic authentication.
+	Username     string `yaml:"username,omitempty"`
+	PasswordFile string `yaml:"passwordFile,omitempty"`
+
+	// BearerTokenFile is the file holding the token that authenticates bearer requests.
+	BearerTokenFile string `yaml:"bearerTokenFile,omitempty"`
+
+	// UnauthenticatedPaths are served without authentication, e.g. /live and /ready for orchestrators.
+	UnauthenticatedPaths []string `yaml:"unauthenticatedPaths,omitempty"`
+}
+
+func (cfg *DebugServerConfig) Validate() error {
+	if cfg == nil {
+		return nil
+	}
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return fmt.Errorf("debug server requires both certFile and keyFile for TLS")
+	}
+	if (cfg.Username == "") != (cfg.PasswordFile == "") {
+		return fmt.Errorf("debug server requires both username and passwordFile for basic authentication")
+	}
+	for _, path := range cfg.UnauthenticatedPaths {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("debug server unauthenticated path %q must start with /", path)
+		}
+	}
+	return nil
+}
+
+// TLSConfig returns the TLS config of the debug server, or nil if TLS is not configured. The
+// certificate is loaded again once its files change.
+func (cfg *DebugServerConfig) TLSConfig() (*tls.Config, error) {
+	if cfg == nil || cfg.CertFile == "" {
+		return nil, nil
+	}
+	tlsConfig, err := newReloadingTLSConfig(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load debug server certificate: %w", err)
+	}
+	return tlsConfig, nil
+}
+
+// Handler returns next wrapped with the configured authentication. Requests to the unauthenticated
+// paths, and all requests if no authentication is configured, are passed to next as is.
+func (cfg *DebugServerConfig) Handler(next http.Handler) (http.Handler, error) {
+	if cfg == nil || (cfg.PasswordFile == "" && cfg.BearerTokenFile == "") {
+		return next, nil
+	}
+
+	h := &debugServerAuth{
+		next:                 next,
+		unauthenticatedPaths: make(map[string]bool, len(cfg.UnauthenticatedPaths)),
+	}
+	for _, path := range cfg.UnauthenticatedPaths {
+		h.unauthenticatedPaths[path] = true
+	}
+	if cfg.PasswordFile != "" {
+		password, err := readDebugServerSecret(cfg.PasswordFile)
+		if err != nil {
+			return nil, err
+		}
+		h.basic = true
+		h.username = sha256.Sum256([]byte(cfg.Username))
+		h.password = sha256.Sum256(password)
+	}
+	if cfg.BearerTokenFile != "" {
+		token, err := readDebugServerSecret(cfg.BearerTokenFile)
+		if err != nil {
+			return nil, err
+		}
+		h.bearer = true
+		h.token = sha256.Sum256(token)
+	}
+	return h, nil
+}
+
+func readDebugServerSecret(file string) ([]byte, error) {
+	bz, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read debug server secret: %w", err)
+	}
+	secret := bytes.TrimRight(bz, "\r\n")
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("debug server secret file %s is empty", file)
+	}
+	return secret, nil
+}
+
+// debugServerAuth authenticates requests to the debug server with basic or bearer authentication.
+// The credentials are compared as hashes in constant time.
+type debugServerAuth struct {
+	next                 http.Handler
+	unauthenticatedPaths map[string]bool
+
+	basic              bool
+	username, password [sha256.Size]byte
+
+	bearer bool
+	token  [sha256.Size]byte
+}
+
+func (h *debugServerAuth) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.unauthenticatedPaths[r.URL.Path] || h.authenticated(r) {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+	if h.basic {
+		w.Header().Add("WWW-Authenticate", `Basic realm="horcrux"`)
+	}
+	if h.bearer {
+		w.Header().Add("WWW-Authenticate", `Bearer realm="horcrux"`)
+	}
+	http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+}
+
+func (h *debugServerAuth) authenticated(r *http.Request) bool {
+	if h.basic {
+		if username, password, ok := r.BasicAuth(); ok {
+			u := sha256.Sum256([]byte(username))
+			p := sha256.Sum256([]byte(password))
+			// both are compared so that the time taken does not reveal which one is wrong.
+			return subtle.ConstantTimeCompare(u[:], h.username[:])&subtle.ConstantTimeCompare(p[:], h.password[:]) == 1
+		}
+	}
+	if h.bearer {
+		if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			t := sha256.Sum256([]byte(token))
+			return subtle.ConstantTimeCompare(t[:], h.token[:]) == 1
+		}
+	}
+	return false
+}
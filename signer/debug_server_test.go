@@ -0,0 +1,120 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugServerConfigValidate(t *testing.T) {
+	require.NoError(t, (*DebugServerConfig)(nil).Validate())
+	require.NoError(t, (&DebugServerConfig{CertFile: "cert.pem", KeyFile: "key.pem"}).Validate())
+	require.NoError(t, (&DebugServerConfig{Username: "admin", PasswordFile: "password"}).Validate())
+
+	require.Error(t, (&DebugServerConfig{CertFile: "cert.pem"}).Validate())
+	require.Error(t, (&DebugServerConfig{Username: "admin"}).Validate())
+	require.Error(t, (&DebugServerConfig{PasswordFile: "password"}).Validate())
+	require.Error(t, (&DebugServerConfig{UnauthenticatedPaths: []string{"live"}}).Validate())
+}
+
+func TestDebugServerAuth(t *testing.T) {
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "password")
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(passwordFile, []byte("hunter2\n"), 0600))
+	require.NoError(t, os.WriteFile(tokenFile, []byte("s3cret\n"), 0600))
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	handler, err := (*DebugServerConfig)(nil).Handler(ok)
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code, "no authentication configured")
+
+	handler, err = (&DebugServerConfig{
+		Username:             "admin",
+		PasswordFile:         passwordFile,
+		BearerTokenFile:      tokenFile,
+		UnauthenticatedPaths: []string{"/live", "/ready"},
+	}).Handler(ok)
+	require.NoError(t, err)
+
+	serve := func(path string, auth func(r *http.Request)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if auth != nil {
+			auth(req)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec = serve("/metrics", nil)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	require.Len(t, rec.Header().Values("WWW-Authenticate"), 2)
+
+	require.Equal(t, http.StatusOK, serve("/live", nil).Code)
+	require.Equal(t, http.StatusOK, serve("/ready", nil).Code)
+
+	require.Equal(t, http.StatusOK, serve("/metrics", func(r *http.Request) { r.SetBasicAuth("admin", "hunter2") }).Code)
+	require.Equal(t, http.StatusUnauthorized,
+		serve("/metrics", func(r *http.Request) { r.SetBasicAuth("admin", "hunter3") }).Code)
+	require.Equal(t, http.StatusUnauthorized,
+		serve("/metrics", func(r *http.Request) { r.SetBasicAuth("root", "hunter2") }).Code)
+
+	require.Equal(t, http.StatusOK,
+		serve("/debug/pprof/", func(r *http.Request) { r.Header.Set("Authorization", "Bearer s3cret") }).Code)
+	require.Equal(t, http.StatusUnauthorized,
+		serve("/debug/pprof/", func(r *http.Request) { r.Header.Set("Authorization", "Bearer s3cre") }).Code)
+
+	_, err = (&DebugServerConfig{BearerTokenFile: filepath.Join(dir, "missing")}).Handler(ok)
+	require.Error(t, err)
+}
+
+func TestDebugServerTLSConfig(t *testing.T) {
+	tlsConfig, err := (&DebugServerConfig{}).TLSConfig()
+	require.NoError(t, err)
+	require.Nil(t, tlsConfig)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "horcrux"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	tlsConfig, err = (&DebugServerConfig{CertFile: certFile, KeyFile: keyFile}).TLSConfig()
+	require.NoError(t, err)
+	cert, err := tlsConfig.GetCertificate(&tls.ClientHelloInfo{})
+	require.NoError(t, err)
+	require.Equal(t, "horcrux", cert.Leaf.Subject.CommonName)
+
+	_, err = (&DebugServerConfig{CertFile: keyFile, KeyFile: certFile}).TLSConfig()
+	require.Error(t, err)
+}
@@ -0,0 +1,376 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	cometservice "github.com/cometbft/cometbft/libs/service"
+	"github.com/getsentry/sentry-go"
+)
+
+const (
+	defaultErrorReportingSignFailures = 3
+
+	errorReportQueueSize = 100
+	errorReportTimeout   = 5 * time.Second
+)
+
+// Error reporting formats.
+const (
+	ErrorReportingFormatSentry  = "sentry"
+	ErrorReportingFormatGeneric = "generic"
+)
+
+// Error report levels.
+const (
+	ErrorReportLevelFatal = "fatal"
+	ErrorReportLevelError = "error"
+)
+
+// ErrorReportingConfig configures reporting panics and repeated sign failures to Sentry or a webhook.
+type ErrorReportingConfig struct {
+	// Format of the reports, sentry (default) or generic.
+	Format string `yaml:"format,omitempty"`
+
+	// URL is the DSN of the Sentry project, or the URL generic reports are POSTed to.
+	URL string `yaml:"url"`
+
+	// Environment the signer runs in, e.g. mainnet.
+	Environment string `yaml:"environment,omitempty"`
+
+	// SignFailures is the number of consecutive failed sign requests for a chain that is reported.
+	// Defaults to 3.
+	SignFailures int `yaml:"signFailures,omitempty"`
+}
+
+func (cfg *ErrorReportingConfig) Validate() error {
+	if cfg == nil {
+		return nil
+	}
+	switch cfg.format() {
+	case ErrorReportingFormatSentry:
+		if _, err := sentry.NewDsn(cfg.URL); err != nil {
+			return fmt.Errorf("invalid error reporting sentry dsn: %w", err)
+		}
+	case ErrorReportingFormatGeneric:
+		if _, err := url.ParseRequestURI(cfg.URL); err != nil {
+			return fmt.Errorf("invalid error reporting url: %w", err)
+		}
+	default:
+		return fmt.Errorf(
+			"invalid error reporting format %q, must be %s or %s",
+			cfg.Format, ErrorReportingFormatSentry, ErrorReportingFormatGeneric,
+		)
+	}
+	if cfg.SignFailures < 0 {
+		return fmt.Errorf("error reporting signFailures must not be negative")
+	}
+	return nil
+}
+
+func (cfg *ErrorReportingConfig) format() string {
+	if cfg.Format == "" {
+		return ErrorReportingFormatSentry
+	}
+	return cfg.Format
+}
+
+func (cfg *ErrorReportingConfig) signFailures() int {
+	if cfg.SignFailures == 0 {
+		return defaultErrorReportingSignFailures
+	}
+	return cfg.SignFailures
+}
+
+// ErrorReport is a panic or a repeated sign failure. It is POSTed as JSON in the generic format.
+type ErrorReport struct {
+	Level       string    `json:"level"`
+	Message     string    `json:"message"`
+	Source      string    `json:"source"`
+	Release     string    `json:"release,omitempty"`
+	Environment string    `json:"environment,omitempty"`
+	Time        time.Time `json:"time"`
+	ChainID     string    `json:"chain_id,omitempty"`
+	Height      int64     `json:"height,omitempty"`
+	Round       int64     `json:"round,omitempty"`
+	Step        int8      `json:"step,omitempty"`
+	Stack       string    `json:"stack,omitempty"`
+}
+
+var (
+	// scrubbed from reports: keys, signatures and sign bytes printed as hex or base64,
+	// credentials in URLs and IP addresses.
+	errorReportHexRegexp      = regexp.MustCompile(`\b[0-9A-Fa-f]{32,}\b`)
+	errorReportBase64Regexp   = regexp.MustCompile(`[A-Za-z0-9+/]{40,}={0,2}`)
+	errorReportUserinfoRegexp = regexp.MustCompile(`://[^/\s@]+@`)
+	errorReportIPRegexp       = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+)
+
+// scrubErrorReport removes secrets and personal information from the text of a report.
+func scrubErrorReport(s string) string {
+	s = errorReportHexRegexp.ReplaceAllString(s, "[redacted]")
+	s = errorReportBase64Regexp.ReplaceAllString(s, "[redacted]")
+	s = errorReportUserinfoRegexp.ReplaceAllString(s, "://[redacted]@")
+	s = errorReportIPRegexp.ReplaceAllString(s, "[ip]")
+	if home, err := os.UserHomeDir(); err == nil && len(home) > 1 {
+		s = strings.ReplaceAll(s, home, "~")
+	}
+	return s
+}
+
+// errorReporter is the ErrorReporter panics are reported to, see ReportPanic.
+var errorReporter atomic.Pointer[ErrorReporter]
+
+// SetErrorReporter sets the ErrorReporter panics are reported to. nil disables reporting panics.
+func SetErrorReporter(r *ErrorReporter) {
+	errorReporter.Store(r)
+}
+
+// ReportPanic reports a panic of the calling goroutine to the ErrorReporter and panics again.
+// It must be deferred directly.
+func ReportPanic() {
+	p := recover()
+	if p == nil {
+		return
+	}
+	if r := errorReporter.Load(); r != nil {
+		r.reportPanic(p, debug.Stack())
+	}
+	panic(p)
+}
+
+// ErrorReporter sends error reports to Sentry or a webhook. Reports of sign failures are queued
+// and sent in the background, so that a slow endpoint never delays signing. Panics are sent
+// before the panic continues.
+type ErrorReporter struct {
+	cometservice.BaseService
+
+	logger  cometlog.Logger
+	cfg     *ErrorReportingConfig
+	source  string
+	release string
+
+	sentry *sentry.Client
+	client *http.Client
+
+	queue   chan ErrorReport
+	cancel  context.CancelFunc
+	stopped sync.WaitGroup
+}
+
+// NewErrorReporter returns an ErrorReporter for cfg. release is the version of horcrux.
+func NewErrorReporter(logger cometlog.Logger, cfg *ErrorReportingConfig, release string) (*ErrorReporter, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	source, err := os.Hostname()
+	if err != nil {
+		source = "horcrux"
+	}
+	r := &ErrorReporter{
+		logger:  logger,
+		cfg:     cfg,
+		source:  source,
+		release: release,
+		client:  &http.Client{Timeout: errorReportTimeout},
+		queue:   make(chan ErrorReport, errorReportQueueSize),
+	}
+	if cfg.format() == ErrorReportingFormatSentry {
+		transport := sentry.NewHTTPSyncTransport()
+		transport.Timeout = errorReportTimeout
+		r.sentry, err = sentry.NewClient(sentry.ClientOptions{
+			Dsn:         cfg.URL,
+			Environment: cfg.Environment,
+			Release:     release,
+			ServerName:  source,
+			Transport:   transport,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	r.BaseService = *cometservice.NewBaseService(logger, "ErrorReporter", r)
+	return r, nil
+}
+
+func (r *ErrorReporter) OnStart() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	r.stopped.Add(1)
+	go func() {
+		defer r.stopped.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case report := <-r.queue:
+				r.send(ctx, report)
+			}
+		}
+	}()
+	return nil
+}
+
+func (r *ErrorReporter) OnStop() {
+	r.cancel()
+	r.stopped.Wait()
+}
+
+// Report queues the report. The report is dropped if the queue is full.
+func (r *ErrorReporter) Report(report ErrorReport) {
+	select {
+	case r.queue <- r.prepare(report):
+	default:
+		totalDroppedErrorReports.Inc()
+		r.logger.Error("Error report queue is full, dropping report", "message", report.Message)
+	}
+}
+
+func (r *ErrorReporter) reportPanic(p any, stack []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), errorReportTimeout)
+	defer cancel()
+	r.send(ctx, r.prepare(ErrorReport{
+		Level:   ErrorReportLevelFatal,
+		Message: fmt.Sprintf("panic: %v", p),
+		Stack:   string(stack),
+	}))
+}
+
+// prepare fills in the source of the report and scrubs it.
+func (r *ErrorReporter) prepare(report ErrorReport) ErrorReport {
+	report.Source = r.source
+	report.Release = r.release
+	report.Environment = r.cfg.Environment
+	if report.Time.IsZero() {
+		report.Time = time.Now()
+	}
+	report.Message = scrubErrorReport(report.Message)
+	report.Stack = scrubErrorReport(report.Stack)
+	return report
+}
+
+func (r *ErrorReporter) send(ctx context.Context, report ErrorReport) {
+	var err error
+	if r.sentry != nil {
+		err = r.sendSentry(report)
+	} else {
+		err = r.sendGeneric(ctx, report)
+	}
+	if err != nil {
+		totalErrorReportFailures.WithLabelValues(r.cfg.format()).Inc()
+		r.logger.Error("Failed to send error report", "format", r.cfg.format(), "error", err)
+	}
+}
+
+func (r *ErrorReporter) sendSentry(report ErrorReport) error {
+	event := sentry.NewEvent()
+	event.Level = sentry.Level(report.Level)
+	event.Message = report.Message
+	event.Timestamp = report.Time
+	event.Logger = "horcrux"
+	if report.ChainID != "" {
+		event.Tags["chain_id"] = report.ChainID
+		event.Tags["height"] = strconv.FormatInt(report.Height, 10)
+		event.Tags["round"] = strconv.FormatInt(report.Round, 10)
+		event.Tags["step"] = strconv.Itoa(int(report.Step))
+	}
+	if report.Stack != "" {
+		event.Extra["stack"] = report.Stack
+	}
+	if r.sentry.CaptureEvent(event, nil, nil) == nil {
+		return fmt.Errorf("sentry event was not sent")
+	}
+	return nil
+}
+
+func (r *ErrorReporter) sendGeneric(ctx context.Context, report ErrorReport) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", res.Status)
+	}
+	return nil
+}
+
+// ErrorReportingValidator is a PrivValidator that reports repeated sign failures of a chain.
+// Requests rejected by the double sign protection or the chain tip guard are not failures.
+type ErrorReportingValidator struct {
+	val      PrivValidator
+	reporter *ErrorReporter
+
+	mu       sync.Mutex
+	failures map[string]int
+}
+
+// NewErrorReportingValidator returns an ErrorReportingValidator that reports the sign failures of val.
+func NewErrorReportingValidator(val PrivValidator, reporter *ErrorReporter) *ErrorReportingValidator {
+	return &ErrorReportingValidator{
+		val:      val,
+		reporter: reporter,
+		failures: make(map[string]int),
+	}
+}
+
+// Sign implements PrivValidator.
+func (v *ErrorReportingValidator) Sign(ctx context.Context, chainID string, block Block) ([]byte, time.Time, error) {
+	sig, timestamp, err := v.val.Sign(ctx, chainID, block)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	switch signDecisionOutcome(err) {
+	case SignDecisionSigned:
+		delete(v.failures, chainID)
+	case SignDecisionError:
+		v.failures[chainID]++
+		// reported once per streak of failures.
+		if n := v.failures[chainID]; n == v.reporter.cfg.signFailures() {
+			v.reporter.Report(ErrorReport{
+				Level:   ErrorReportLevelError,
+				Message: fmt.Sprintf("%d consecutive sign requests for chain %s failed: %v", n, chainID, err),
+				ChainID: chainID,
+				Height:  block.Height,
+				Round:   block.Round,
+				Step:    block.Step,
+			})
+		}
+	}
+
+	return sig, timestamp, err
+}
+
+// GetPubKey implements PrivValidator.
+func (v *ErrorReportingValidator) GetPubKey(ctx context.Context, chainID string) ([]byte, error) {
+	return v.val.GetPubKey(ctx, chainID)
+}
+
+// Stop implements PrivValidator.
+func (v *ErrorReportingValidator) Stop() {
+	v.val.Stop()
+}
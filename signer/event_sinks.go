@@ -0,0 +1,284 @@
+package signer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+)
+
+const (
+	defaultNATSPort    = "4222"
+	defaultNATSSubject = "horcrux.events"
+)
+
+// newEventSink returns the sink configured by cfg.
+func newEventSink(logger cometlog.Logger, cfg EventSinkConfig) (EventSink, error) {
+	switch cfg.Type {
+	case EventSinkTypeLog:
+		return &logEventSink{logger: logger}, nil
+	case EventSinkTypeWebhook:
+		return &webhookEventSink{url: cfg.URL, client: &http.Client{}}, nil
+	case EventSinkTypeNATS:
+		return newNATSEventSink(logger, cfg)
+	default:
+		return nil, fmt.Errorf("invalid event sink type %q", cfg.Type)
+	}
+}
+
+// logEventSink logs the events.
+type logEventSink struct {
+	logger cometlog.Logger
+}
+
+func (s *logEventSink) Send(_ context.Context, event Event) error {
+	keyvals := []any{"type", event.Type, "message", event.Message}
+	if event.ChainID != "" {
+		keyvals = append(keyvals,
+			"chain_id", event.ChainID,
+			"height", event.Height,
+			"round", event.Round,
+			"step", event.Step,
+		)
+	}
+	if event.Error != "" {
+		keyvals = append(keyvals, "error", event.Error)
+	}
+	if event.Cosigner != 0 {
+		keyvals = append(keyvals, "cosigner", event.Cosigner)
+	}
+	s.logger.Info("Event", keyvals...)
+	return nil
+}
+
+func (s *logEventSink) Close() error { return nil }
+
+// webhookEventSink POSTs the events as JSON.
+type webhookEventSink struct {
+	url    string
+	client *http.Client
+}
+
+func (s *webhookEventSink) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", res.Status)
+	}
+	return nil
+}
+
+func (s *webhookEventSink) Close() error { return nil }
+
+// parseNATSURL parses a nats://[user[:password]@]host[:port] URL. A user without
+// a password is a token.
+func parseNATSURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	if u.Scheme != "nats" {
+		return nil, fmt.Errorf("url %q must be nats", raw)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("url %q has no host", raw)
+	}
+	if u.Port() == "" {
+		u.Host = net.JoinHostPort(u.Hostname(), defaultNATSPort)
+	}
+	return u, nil
+}
+
+// natsConnect is the CONNECT message of the NATS protocol.
+type natsConnect struct {
+	Verbose   bool   `json:"verbose"`
+	Pedantic  bool   `json:"pedantic"`
+	Name      string `json:"name"`
+	Lang      string `json:"lang"`
+	Version   string `json:"version"`
+	User      string `json:"user,omitempty"`
+	Pass      string `json:"pass,omitempty"`
+	AuthToken string `json:"auth_token,omitempty"`
+}
+
+// natsEventSink publishes the events as JSON to a NATS server, with the subject of each event
+// being the subject prefix followed by the event type. It speaks the plain text NATS protocol,
+// connects on the first event and reconnects on the next event after the connection failed.
+type natsEventSink struct {
+	logger  cometlog.Logger
+	addr    string
+	connect natsConnect
+	subject string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func newNATSEventSink(logger cometlog.Logger, cfg EventSinkConfig) (*natsEventSink, error) {
+	u, err := parseNATSURL(cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+	s := &natsEventSink{
+		logger:  logger,
+		addr:    u.Host,
+		connect: natsConnect{Name: "horcrux", Lang: "go", Version: "1.0.0"},
+		subject: cfg.Subject,
+	}
+	if s.subject == "" {
+		s.subject = defaultNATSSubject
+	}
+	if u.User != nil {
+		if pass, ok := u.User.Password(); ok {
+			s.connect.User, s.connect.Pass = u.User.Username(), pass
+		} else {
+			s.connect.AuthToken = u.User.Username()
+		}
+	}
+	return s, nil
+}
+
+func (s *natsEventSink) Send(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		if err := s.dial(ctx); err != nil {
+			return fmt.Errorf("failed to connect to nats: %w", err)
+		}
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "PUB %s.%s %d\r\n", s.subject, event.Type, len(payload))
+	msg.Write(payload)
+	msg.WriteString("\r\n")
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = s.conn.SetWriteDeadline(deadline)
+	}
+	if _, err := s.conn.Write(msg.Bytes()); err != nil {
+		s.closeConn()
+		return err
+	}
+	return nil
+}
+
+// dial connects and authenticates to the server, then answers its pings in the background.
+func (s *natsEventSink) dial(ctx context.Context) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	r := bufio.NewReader(conn)
+	line, err := r.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		conn.Close()
+		return fmt.Errorf("unexpected greeting %q", strings.TrimSpace(line))
+	}
+
+	connect, err := json.Marshal(s.connect)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	// the PONG to the PING confirms that the server accepted the CONNECT.
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\nPING\r\n", connect); err != nil {
+		conn.Close()
+		return err
+	}
+	line, err = r.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if line = strings.TrimSpace(line); line != "PONG" {
+		conn.Close()
+		return fmt.Errorf("server responded with %q", line)
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+	s.conn = conn
+	go s.read(conn, r)
+	return nil
+}
+
+// read answers the pings of the server and logs its errors until the connection fails.
+func (s *natsEventSink) read(conn net.Conn, r *bufio.Reader) {
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				s.logger.Error("NATS connection failed", "address", s.addr, "error", err)
+			}
+			break
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PING":
+			s.mu.Lock()
+			_, err = conn.Write([]byte("PONG\r\n"))
+			s.mu.Unlock()
+		case strings.HasPrefix(line, "-ERR"):
+			s.logger.Error("NATS server error", "address", s.addr, "error", line)
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == conn {
+		s.closeConn()
+	}
+}
+
+func (s *natsEventSink) closeConn() {
+	if s.conn != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+}
+
+func (s *natsEventSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeConn()
+	return nil
+}
@@ -0,0 +1,178 @@
+package signer
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// The feature flags of the signer behaviors, which are enabled by default and can be rolled back
+// for a chain or a percentage of the sign requests, e.g. while one is suspected in an incident.
+const (
+	// FeatureFlagAlternateCosigners retries the share signature of a peer that failed on the next
+	// fastest peer, which signs with the nonces of the block if it has them.
+	FeatureFlagAlternateCosigners = "alternate-cosigners"
+
+	// FeatureFlagNonceFallback fetches nonces from the peers for a block that the nonce cache has
+	// none for, rather than failing the sign request.
+	FeatureFlagNonceFallback = "nonce-fallback"
+)
+
+// defaultFeatureFlags are the feature flags of the signer behaviors, which the config overrides.
+var defaultFeatureFlags = map[string]FeatureFlag{
+	FeatureFlagAlternateCosigners: {Enabled: true},
+	FeatureFlagNonceFallback:      {Enabled: true},
+}
+
+// FeatureFlag controls the gradual rollout of a new signer behavior.
+type FeatureFlag struct {
+	// Enabled turns the behavior on. A disabled flag is off for every chain and request.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// ChainIDs restricts the behavior to the listed chains. Empty applies to all chains.
+	ChainIDs []string `yaml:"chainIDs,omitempty" json:"chainIDs,omitempty"`
+
+	// Percentage of requests (1-100) which use the behavior. 0 applies to all requests.
+	Percentage uint32 `yaml:"percentage,omitempty" json:"percentage,omitempty"`
+}
+
+func (f FeatureFlag) Validate() error {
+	if f.Percentage > 100 {
+		return fmt.Errorf("percentage must be between 0 and 100, got %d", f.Percentage)
+	}
+	return nil
+}
+
+func (f FeatureFlag) appliesToChain(chainID string) bool {
+	if len(f.ChainIDs) == 0 {
+		return true
+	}
+	for _, c := range f.ChainIDs {
+		if c == chainID {
+			return true
+		}
+	}
+	return false
+}
+
+// FeatureFlags holds the configured feature flags and any runtime overrides.
+// Overrides are replicated to all cosigners through raft so that a behavior
+// can be enabled or rolled back across the cluster without a restart.
+type FeatureFlags struct {
+	mu        sync.RWMutex
+	defaults  map[string]FeatureFlag
+	overrides map[string]FeatureFlag
+
+	// roll returns a value in [0, 100) used for percentage rollouts.
+	roll func() uint32
+}
+
+// NewFeatureFlags returns FeatureFlags with the given defaults from the config, which override the
+// feature flags of the signer behaviors.
+func NewFeatureFlags(defaults map[string]FeatureFlag) *FeatureFlags {
+	d := make(map[string]FeatureFlag, len(defaultFeatureFlags)+len(defaults))
+	for name, flag := range defaultFeatureFlags {
+		d[name] = flag
+	}
+	for name, flag := range defaults {
+		d[name] = flag
+	}
+	return &FeatureFlags{
+		defaults:  d,
+		overrides: make(map[string]FeatureFlag),
+		roll: func() uint32 {
+			return uint32(rand.Intn(100)) //nolint:gosec
+		},
+	}
+}
+
+// Get returns the effective flag with the given name and whether it is defined.
+func (ff *FeatureFlags) Get(name string) (FeatureFlag, bool) {
+	if ff == nil {
+		flag, ok := defaultFeatureFlags[name]
+		return flag, ok
+	}
+	ff.mu.RLock()
+	defer ff.mu.RUnlock()
+	if flag, ok := ff.overrides[name]; ok {
+		return flag, true
+	}
+	flag, ok := ff.defaults[name]
+	return flag, ok
+}
+
+// Enabled returns true if the named behavior should be used for this request on the chain.
+// Undefined flags are disabled.
+func (ff *FeatureFlags) Enabled(name, chainID string) bool {
+	flag, ok := ff.Get(name)
+	if !ok || !flag.Enabled || !flag.appliesToChain(chainID) {
+		return false
+	}
+	if flag.Percentage == 0 || flag.Percentage >= 100 {
+		return true
+	}
+	return ff.roll() < flag.Percentage
+}
+
+// Set overrides the named flag at runtime.
+func (ff *FeatureFlags) Set(name string, flag FeatureFlag) error {
+	if name == "" {
+		return fmt.Errorf("feature flag name cannot be empty")
+	}
+	if err := flag.Validate(); err != nil {
+		return fmt.Errorf("invalid feature flag %s: %w", name, err)
+	}
+	ff.mu.Lock()
+	defer ff.mu.Unlock()
+	ff.overrides[name] = flag
+	return nil
+}
+
+// Reset removes the runtime override for the named flag, reverting to the configured value.
+func (ff *FeatureFlags) Reset(name string) {
+	ff.mu.Lock()
+	defer ff.mu.Unlock()
+	delete(ff.overrides, name)
+}
+
+// Names returns the sorted names of all defined flags.
+func (ff *FeatureFlags) Names() []string {
+	ff.mu.RLock()
+	defer ff.mu.RUnlock()
+	names := make([]string, 0, len(ff.defaults)+len(ff.overrides))
+	for name := range ff.defaults {
+		names = append(names, name)
+	}
+	for name := range ff.overrides {
+		if _, ok := ff.defaults[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsOverridden returns true if the named flag has a runtime override.
+func (ff *FeatureFlags) IsOverridden(name string) bool {
+	ff.mu.RLock()
+	defer ff.mu.RUnlock()
+	_, ok := ff.overrides[name]
+	return ok
+}
+
+// FeatureFlagEvent is replicated through raft to change a feature flag on all cosigners.
+type FeatureFlagEvent struct {
+	Name  string      `json:"name"`
+	Flag  FeatureFlag `json:"flag"`
+	Reset bool        `json:"reset,omitempty"`
+}
+
+// Apply applies the event to the feature flags.
+func (e FeatureFlagEvent) Apply(ff *FeatureFlags) error {
+	if e.Reset {
+		ff.Reset(e.Name)
+		return nil
+	}
+	return ff.Set(e.Name, e.Flag)
+}
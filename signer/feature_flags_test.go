@@ -0,0 +1,52 @@
+package signer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureFlags(t *testing.T) {
+	ff := NewFeatureFlags(map[string]FeatureFlag{
+		"config-enabled": {Enabled: true, ChainIDs: []string{"chain-1"}},
+		"half":           {Enabled: true, Percentage: 50},
+	})
+
+	require.True(t, ff.Enabled("config-enabled", "chain-1"))
+	require.False(t, ff.Enabled("config-enabled", "chain-2"))
+	require.False(t, ff.Enabled("undefined", "chain-1"))
+
+	var roll uint32
+	ff.roll = func() uint32 { return roll }
+
+	roll = 49
+	require.True(t, ff.Enabled("half", "chain-1"))
+	roll = 50
+	require.False(t, ff.Enabled("half", "chain-1"))
+
+	// runtime rollback takes precedence over config
+	require.NoError(t, FeatureFlagEvent{Name: "config-enabled", Flag: FeatureFlag{Enabled: false}}.Apply(ff))
+	require.False(t, ff.Enabled("config-enabled", "chain-1"))
+	require.True(t, ff.IsOverridden("config-enabled"))
+
+	require.NoError(t, FeatureFlagEvent{Name: "runtime-only", Flag: FeatureFlag{Enabled: true}}.Apply(ff))
+	require.True(t, ff.Enabled("runtime-only", "chain-2"))
+
+	require.Equal(t, []string{
+		FeatureFlagAlternateCosigners, "config-enabled", "half", FeatureFlagNonceFallback, "runtime-only",
+	}, ff.Names())
+
+	// reset reverts to config
+	require.NoError(t, FeatureFlagEvent{Name: "config-enabled", Reset: true}.Apply(ff))
+	require.True(t, ff.Enabled("config-enabled", "chain-1"))
+	require.False(t, ff.IsOverridden("config-enabled"))
+
+	// the signer behaviors are enabled unless the config or a runtime override disables them.
+	require.True(t, ff.Enabled(FeatureFlagNonceFallback, "chain-1"))
+	ff = NewFeatureFlags(map[string]FeatureFlag{FeatureFlagNonceFallback: {Enabled: false}})
+	require.False(t, ff.Enabled(FeatureFlagNonceFallback, "chain-1"))
+	require.True(t, ff.Enabled(FeatureFlagAlternateCosigners, "chain-1"))
+
+	require.Error(t, ff.Set("bad", FeatureFlag{Enabled: true, Percentage: 101}))
+	require.Error(t, ff.Set("", FeatureFlag{Enabled: true}))
+}
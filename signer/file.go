@@ -7,14 +7,10 @@ import (
 	"os"
 	"time"
 
-	"github.com/cosmos/gogoproto/proto"
-
 	"github.com/cometbft/cometbft/crypto"
 	"github.com/cometbft/cometbft/crypto/ed25519"
 	cometjson "github.com/cometbft/cometbft/libs/json"
-	"github.com/cometbft/cometbft/libs/protoio"
 	"github.com/cometbft/cometbft/libs/tempfile"
-	cometproto "github.com/cometbft/cometbft/proto/tendermint/types"
 	"github.com/cometbft/cometbft/types"
 )
 
@@ -110,7 +106,7 @@ func (lss *FilePVLastSignState) Save() {
 	if err != nil {
 		panic(err)
 	}
-	err = tempfile.WriteFileAtomic(outFile, jsonBytes, 0600)
+	err = writeStateFile(outFile, jsonBytes)
 	if err != nil {
 		panic(err)
 	}
@@ -126,6 +122,9 @@ func (lss *FilePVLastSignState) Save() {
 type FilePV struct {
 	Key           FilePVKey
 	LastSignState FilePVLastSignState
+
+	// signBytesCodec inspects previously signed bytes. Defaults to the CometBFT codec when nil.
+	signBytesCodec SignBytesCodec
 }
 
 // NewFilePV generates a new validator from the given key and paths.
@@ -170,7 +169,7 @@ func LoadFilePV(keyFilePath, stateFilePath string, loadState bool) (*FilePV, err
 	pvState := FilePVLastSignState{}
 
 	if loadState {
-		stateJSONBytes, err := os.ReadFile(stateFilePath)
+		stateJSONBytes, err := readStateFile(stateFilePath)
 		if err != nil {
 			return nil, err
 		}
@@ -216,17 +215,17 @@ func (pv *FilePV) Sign(block Block) ([]byte, time.Time, error) {
 	// If they only differ by timestamp, use last timestamp and signature
 	// Otherwise, return error
 	if sameHRS {
-		switch {
-		case bytes.Equal(signBytes, lss.SignBytes):
+		if bytes.Equal(signBytes, lss.SignBytes) {
 			return lss.Signature, block.Timestamp, nil
-		case block.Step == stepPropose:
-			if timestamp, ok := checkProposalsOnlyDifferByTimestamp(lss.SignBytes, signBytes); ok {
-				return lss.Signature, timestamp, nil
-			}
-		case block.Step == stepPrevote || block.Step == stepPrecommit:
-			if timestamp, ok := checkVotesOnlyDifferByTimestamp(lss.SignBytes, signBytes); ok {
-				return lss.Signature, timestamp, nil
+		}
+
+		codec := pv.codec()
+		if err := codec.OnlyDifferByTimestamp(step, lss.SignBytes, signBytes); err == nil {
+			lastHRST, err := codec.UnpackHRST(lss.SignBytes)
+			if err != nil {
+				return nil, block.Timestamp, err
 			}
+			return lss.Signature, time.Unix(0, lastHRST.Timestamp), nil
 		}
 
 		return nil, block.Timestamp, fmt.Errorf("conflicting data")
@@ -242,6 +241,13 @@ func (pv *FilePV) Sign(block Block) ([]byte, time.Time, error) {
 	return sig, block.Timestamp, nil
 }
 
+func (pv *FilePV) codec() SignBytesCodec {
+	if pv.signBytesCodec == nil {
+		return CometSignBytesCodec{}
+	}
+	return pv.signBytesCodec
+}
+
 // Save persists the FilePV to disk.
 func (pv *FilePV) Save() {
 	pv.Key.Save()
@@ -282,45 +288,3 @@ func (pv *FilePV) saveSigned(height int64, round int32, step int8,
 	pv.LastSignState.SignBytes = signBytes
 	pv.LastSignState.Save()
 }
-
-//-----------------------------------------------------------------------------------------
-
-// returns the timestamp from the lastSignBytes.
-// returns true if the only difference in the votes is their timestamp.
-func checkVotesOnlyDifferByTimestamp(lastSignBytes, newSignBytes []byte) (time.Time, bool) {
-	var lastVote, newVote cometproto.CanonicalVote
-	if err := protoio.UnmarshalDelimited(lastSignBytes, &lastVote); err != nil {
-		panic(fmt.Sprintf("LastSignBytes cannot be unmarshalled into vote: %v", err))
-	}
-	if err := protoio.UnmarshalDelimited(newSignBytes, &newVote); err != nil {
-		panic(fmt.Sprintf("signBytes cannot be unmarshalled into vote: %v", err))
-	}
-
-	lastTime := lastVote.Timestamp
-	// set the times to the same value and check equality
-	now := time.Now()
-	lastVote.Timestamp = now
-	newVote.Timestamp = now
-
-	return lastTime, proto.Equal(&newVote, &lastVote)
-}
-
-// returns the timestamp from the lastSignBytes.
-// returns true if the only difference in the proposals is their timestamp
-func checkProposalsOnlyDifferByTimestamp(lastSignBytes, newSignBytes []byte) (time.Time, bool) {
-	var lastProposal, newProposal cometproto.CanonicalProposal
-	if err := protoio.UnmarshalDelimited(lastSignBytes, &lastProposal); err != nil {
-		panic(fmt.Sprintf("LastSignBytes cannot be unmarshalled into proposal: %v", err))
-	}
-	if err := protoio.UnmarshalDelimited(newSignBytes, &newProposal); err != nil {
-		panic(fmt.Sprintf("signBytes cannot be unmarshalled into proposal: %v", err))
-	}
-
-	lastTime := lastProposal.Timestamp
-	// set the times to the same value and check equality
-	now := time.Now()
-	lastProposal.Timestamp = now
-	newProposal.Timestamp = now
-
-	return lastTime, proto.Equal(&newProposal, &lastProposal)
-}
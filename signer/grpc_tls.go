@@ -0,0 +1,227 @@
+package signer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	cometservice "github.com/cometbft/cometbft/libs/service"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+const defaultACMECacheDir = "acme"
+
+// acmeDomainPattern matches a DNS name that a certificate is obtained for. Wildcard names require
+// the DNS-01 challenge, which is not supported.
+var acmeDomainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)+[a-z][a-z0-9-]*[a-z0-9]$`)
+
+// GRPCTLSConfig configures TLS of the remote signer gRPC listener at grpcAddr, with the certificate
+// of certFile and keyFile, or with certificates obtained and renewed through ACME.
+type GRPCTLSConfig struct {
+	// CertFile and KeyFile serve the listener over TLS, and are loaded again once they change.
+	CertFile string `yaml:"certFile,omitempty"`
+	KeyFile  string `yaml:"keyFile,omitempty"`
+
+	// ACME obtains and renews the certificates of the public DNS names of the listener.
+	ACME *ACMEConfig `yaml:"acme,omitempty"`
+}
+
+// ACMEConfig configures obtaining and renewing certificates from an ACME CA, e.g. Let's Encrypt.
+type ACMEConfig struct {
+	// Domains are the public DNS names the listener is reached at.
+	Domains []string `yaml:"domains"`
+
+	// Email is the contact of the ACME account, notified e.g. of certificates about to expire.
+	Email string `yaml:"email,omitempty"`
+
+	// DirectoryURL is the directory of the ACME CA. Defaults to Let's Encrypt.
+	DirectoryURL string `yaml:"directoryURL,omitempty"`
+
+	// CacheDir holds the account key and the certificates, relative to the home directory unless
+	// absolute. Defaults to acme.
+	CacheDir string `yaml:"cacheDir,omitempty"`
+
+	// HTTPAddr serves the HTTP-01 challenges, e.g. :80. Without it, only the TLS-ALPN-01 challenge
+	// is answered by the listener, which the CA requires on port 443.
+	HTTPAddr string `yaml:"httpAddr,omitempty"`
+
+	// AcceptTermsOfService accepts the terms of service of the ACME CA, which is required.
+	AcceptTermsOfService bool `yaml:"acceptTermsOfService"`
+}
+
+func (cfg *GRPCTLSConfig) Validate() error {
+	if cfg == nil {
+		return nil
+	}
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return fmt.Errorf("grpcTLS requires both certFile and keyFile")
+	}
+	if (cfg.CertFile == "") == (cfg.ACME == nil) {
+		return fmt.Errorf("grpcTLS requires either certFile and keyFile, or acme")
+	}
+	if cfg.ACME != nil {
+		if err := cfg.ACME.Validate(); err != nil {
+			return fmt.Errorf("grpcTLS: %w", err)
+		}
+	}
+	return nil
+}
+
+// TLSConfig returns the TLS config of the certificate files, or nil with ACME, whose TLS config is
+// returned by the ACMEManager.
+func (cfg *GRPCTLSConfig) TLSConfig() (*tls.Config, error) {
+	if cfg == nil || cfg.CertFile == "" {
+		return nil, nil
+	}
+	tlsConfig, err := newReloadingTLSConfig(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load grpc certificate: %w", err)
+	}
+	return tlsConfig, nil
+}
+
+func (cfg *ACMEConfig) Validate() error {
+	if len(cfg.Domains) == 0 {
+		return fmt.Errorf("acme requires domains")
+	}
+	for _, domain := range cfg.Domains {
+		if !acmeDomainPattern.MatchString(domain) {
+			return fmt.Errorf("invalid acme domain %q, must be a lowercase DNS name without wildcard", domain)
+		}
+	}
+	if cfg.DirectoryURL != "" {
+		u, err := url.Parse(cfg.DirectoryURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("invalid acme directoryURL %q, must be an https URL", cfg.DirectoryURL)
+		}
+	}
+	if cfg.HTTPAddr != "" {
+		if _, _, err := net.SplitHostPort(cfg.HTTPAddr); err != nil {
+			return fmt.Errorf("invalid acme httpAddr: %w", err)
+		}
+	}
+	if !cfg.AcceptTermsOfService {
+		return fmt.Errorf("acme requires acceptTermsOfService: true to accept the terms of service of the CA")
+	}
+	return nil
+}
+
+// ACMECacheDir returns the directory of the ACME account key and certificates.
+func (c RuntimeConfig) ACMECacheDir() string {
+	path := defaultACMECacheDir
+	if cfg := c.Config.GRPCTLS; cfg != nil && cfg.ACME != nil && cfg.ACME.CacheDir != "" {
+		path = cfg.ACME.CacheDir
+	}
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(c.HomeDir, path)
+}
+
+// ACMEManager obtains and renews the certificates of the remote signer gRPC listener from an ACME
+// CA, and answers the HTTP-01 challenges of the CA if configured.
+type ACMEManager struct {
+	cometservice.BaseService
+
+	logger  cometlog.Logger
+	cfg     *ACMEConfig
+	manager *autocert.Manager
+	srv     *http.Server
+}
+
+// NewACMEManager returns an ACMEManager of the config, which caches the account key and the
+// certificates in cacheDir.
+func NewACMEManager(logger cometlog.Logger, cfg *ACMEConfig, cacheDir string) (*ACMEManager, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	m := &ACMEManager{
+		logger: logger,
+		cfg:    cfg,
+		manager: &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			Cache:      autocert.DirCache(cacheDir),
+			HostPolicy: autocert.HostWhitelist(cfg.Domains...),
+			Email:      cfg.Email,
+		},
+	}
+	if cfg.DirectoryURL != "" {
+		m.manager.Client = &acme.Client{DirectoryURL: cfg.DirectoryURL}
+	}
+	m.BaseService = *cometservice.NewBaseService(logger, "ACMEManager", m)
+	return m, nil
+}
+
+// TLSConfig returns the TLS config of the listener, which answers the TLS-ALPN-01 challenges. A
+// client that does not send the server name is served the certificate of the first domain.
+func (m *ACMEManager) TLSConfig() *tls.Config {
+	tlsConfig := m.manager.TLSConfig()
+	tlsConfig.MinVersion = tls.VersionTLS12
+	tlsConfig.GetCertificate = func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		if hello.ServerName == "" {
+			named := *hello
+			named.ServerName = m.cfg.Domains[0]
+			hello = &named
+		}
+		return m.manager.GetCertificate(hello)
+	}
+	return tlsConfig
+}
+
+func (m *ACMEManager) OnStart() error {
+	if m.cfg.HTTPAddr != "" {
+		ln, err := net.Listen("tcp", m.cfg.HTTPAddr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on acme httpAddr: %w", err)
+		}
+		m.srv = &http.Server{
+			Handler:           m.manager.HTTPHandler(nil),
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		go func() {
+			if err := m.srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				m.logger.Error("ACME challenge server stopped", "error", err)
+			}
+		}()
+		m.logger.Info("ACME challenge server listening", "address", ln.Addr().String())
+	}
+
+	go m.obtain()
+	return nil
+}
+
+func (m *ACMEManager) OnStop() {
+	if m.srv != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), adminShutdownTimeout)
+		defer cancel()
+		_ = m.srv.Shutdown(ctx)
+	}
+}
+
+// obtain obtains the certificates of the domains, or loads them from the cache, so that a
+// misconfiguration is logged at startup rather than on the first TLS handshake. autocert renews
+// them from then on. The ECDSA certificates, which the clients are served, are obtained.
+func (m *ACMEManager) obtain() {
+	for _, domain := range m.cfg.Domains {
+		cert, err := m.manager.GetCertificate(&tls.ClientHelloInfo{
+			ServerName:       domain,
+			CipherSuites:     []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256},
+			SignatureSchemes: []tls.SignatureScheme{tls.ECDSAWithP256AndSHA256},
+			SupportedCurves:  []tls.CurveID{tls.CurveP256},
+		})
+		if err != nil {
+			m.logger.Error("Failed to obtain ACME certificate", "domain", domain, "error", err)
+			continue
+		}
+		m.logger.Info("ACME certificate ready", "domain", domain, "not_after", cert.Leaf.NotAfter)
+	}
+}
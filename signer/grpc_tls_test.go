@@ -0,0 +1,114 @@
+package signer
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/strangelove-ventures/horcrux/signer/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+func TestGRPCTLSConfigValidate(t *testing.T) {
+	require.NoError(t, (*GRPCTLSConfig)(nil).Validate())
+	require.NoError(t, (&GRPCTLSConfig{CertFile: "tls.crt", KeyFile: "tls.key"}).Validate())
+	require.NoError(t, (&GRPCTLSConfig{ACME: &ACMEConfig{
+		Domains:              []string{"signer.example.com"},
+		DirectoryURL:         "https://acme-staging-v02.api.letsencrypt.org/directory",
+		HTTPAddr:             ":80",
+		AcceptTermsOfService: true,
+	}}).Validate())
+
+	for _, cfg := range []*GRPCTLSConfig{
+		{},
+		{CertFile: "tls.crt"},
+		{CertFile: "tls.crt", KeyFile: "tls.key", ACME: &ACMEConfig{}},
+		{ACME: &ACMEConfig{AcceptTermsOfService: true}},
+		{ACME: &ACMEConfig{Domains: []string{"*.example.com"}, AcceptTermsOfService: true}},
+		{ACME: &ACMEConfig{Domains: []string{"localhost"}, AcceptTermsOfService: true}},
+		{ACME: &ACMEConfig{Domains: []string{"signer.example.com"}}},
+		{ACME: &ACMEConfig{
+			Domains:              []string{"signer.example.com"},
+			DirectoryURL:         "http://acme.example.com/directory",
+			AcceptTermsOfService: true,
+		}},
+		{ACME: &ACMEConfig{Domains: []string{"signer.example.com"}, HTTPAddr: "80", AcceptTermsOfService: true}},
+	} {
+		require.Error(t, cfg.Validate(), cfg)
+	}
+
+	c := Config{GRPCTLS: &GRPCTLSConfig{CertFile: "tls.crt", KeyFile: "tls.key"}}
+	require.ErrorContains(t, c.ValidateSingleSignerConfig(), "grpcTLS requires grpcAddr")
+
+	rc := RuntimeConfig{HomeDir: "/home/horcrux"}
+	require.Equal(t, "/home/horcrux/acme", rc.ACMECacheDir())
+	rc.Config.GRPCTLS = &GRPCTLSConfig{ACME: &ACMEConfig{CacheDir: "/var/lib/horcrux/acme"}}
+	require.Equal(t, "/var/lib/horcrux/acme", rc.ACMECacheDir())
+}
+
+func TestRemoteSignerGRPCServerTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	writeTestCert(t, certFile, keyFile, "signer.example.com")
+
+	tlsConfig, err := (&GRPCTLSConfig{CertFile: certFile, KeyFile: keyFile}).TLSConfig()
+	require.NoError(t, err)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	s := NewRemoteSignerGRPCServer(cometlog.NewNopLogger(), &mockPrivValidator{}, address)
+	s.SetTLSConfig(tlsConfig)
+	require.NoError(t, s.Start())
+	defer func() { _ = s.Stop() }()
+
+	pem, err := os.ReadFile(certFile)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(pem))
+	creds := credentials.NewTLS(&tls.Config{RootCAs: pool, ServerName: "signer.example.com", MinVersion: tls.VersionTLS12})
+	conn, err := grpc.Dial(address, grpc.WithTransportCredentials(creds))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = proto.NewRemoteSignerClient(conn).PubKey(context.Background(), &proto.PubKeyRequest{ChainId: testChainID})
+	require.NoError(t, err)
+}
+
+func TestACMEManager(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	httpAddr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	m, err := NewACMEManager(cometlog.NewNopLogger(), &ACMEConfig{
+		Domains: []string{"signer.example.com"},
+		// nothing listens on port 1, obtaining the certificate fails.
+		DirectoryURL:         "https://127.0.0.1:1/directory",
+		HTTPAddr:             httpAddr,
+		AcceptTermsOfService: true,
+	}, t.TempDir())
+	require.NoError(t, err)
+	require.Contains(t, m.TLSConfig().NextProtos, "acme-tls/1")
+
+	require.NoError(t, m.Start())
+	defer func() { _ = m.Stop() }()
+
+	// the challenge server redirects requests other than the challenges to https.
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	res, err := client.Get("http://" + httpAddr + "/")
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusFound, res.StatusCode)
+}
@@ -0,0 +1,102 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+)
+
+type HaltHeightError struct {
+	msg string
+}
+
+func (e *HaltHeightError) Error() string { return e.msg }
+
+func newHaltHeightError(chainID string, height, haltHeight int64) *HaltHeightError {
+	return &HaltHeightError{
+		msg: fmt.Sprintf(
+			"refusing to sign chain %s at height %d: signing halts after height %d for an upgrade, "+
+				"clear the halt height once the chain is upgraded",
+			chainID, height, haltHeight,
+		),
+	}
+}
+
+// HaltHeightValidator is a PrivValidator that refuses to sign the chains above their halt height, so
+// that the signer cannot sign on a fork of a chain halted for a coordinated upgrade. The halt
+// heights of the config are set and cleared at runtime, e.g. through the admin API.
+type HaltHeightValidator struct {
+	val PrivValidator
+
+	mu      sync.RWMutex
+	heights map[string]int64
+}
+
+// NewHaltHeightValidator returns a HaltHeightValidator for the sign requests of val with the halt
+// heights of the chains.
+func NewHaltHeightValidator(val PrivValidator, chains ChainConfigs) *HaltHeightValidator {
+	heights := chains.haltHeights()
+	for id, height := range heights {
+		haltHeight.WithLabelValues(id).Set(float64(height))
+	}
+	return &HaltHeightValidator{
+		val:     val,
+		heights: heights,
+	}
+}
+
+// HaltHeights returns the halt height of each chain ID, or validator chain ID, with one.
+func (v *HaltHeightValidator) HaltHeights() map[string]int64 {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return maps.Clone(v.heights)
+}
+
+// SetHaltHeight halts signing of the chain ID, or validator chain ID, after the height.
+func (v *HaltHeightValidator) SetHaltHeight(chainID string, height int64) error {
+	if height <= 0 {
+		return fmt.Errorf("halt height must be positive")
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.heights[chainID] = height
+	haltHeight.WithLabelValues(chainID).Set(float64(height))
+	return nil
+}
+
+// ClearHaltHeight signs the chain ID, or validator chain ID, above its halt height again, and returns
+// false if it has no halt height.
+func (v *HaltHeightValidator) ClearHaltHeight(chainID string) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if _, ok := v.heights[chainID]; !ok {
+		return false
+	}
+	delete(v.heights, chainID)
+	haltHeight.DeleteLabelValues(chainID)
+	return true
+}
+
+// Sign implements PrivValidator.
+func (v *HaltHeightValidator) Sign(ctx context.Context, chainID string, block Block) ([]byte, time.Time, error) {
+	v.mu.RLock()
+	height, ok := validatorChainValue(v.heights, chainID)
+	v.mu.RUnlock()
+	if ok && block.Height > height {
+		totalHaltHeightRefusals.WithLabelValues(chainID).Inc()
+		return nil, block.Timestamp, newHaltHeightError(chainID, block.Height, height)
+	}
+	return v.val.Sign(ctx, chainID, block)
+}
+
+// GetPubKey implements PrivValidator.
+func (v *HaltHeightValidator) GetPubKey(ctx context.Context, chainID string) ([]byte, error) {
+	return v.val.GetPubKey(ctx, chainID)
+}
+
+// Stop implements PrivValidator.
+func (v *HaltHeightValidator) Stop() {
+	v.val.Stop()
+}
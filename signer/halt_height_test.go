@@ -0,0 +1,46 @@
+package signer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHaltHeightValidator(t *testing.T) {
+	pv := &mockPrivValidator{}
+	v := NewHaltHeightValidator(pv, ChainConfigs{
+		"osmosis-1":        {HaltHeight: 100},
+		"acme@cosmoshub-4": {HaltHeight: 200},
+		"juno-1":           {GRPCTimeout: "1s"},
+	})
+	require.Equal(t, map[string]int64{"osmosis-1": 100, "acme@cosmoshub-4": 200}, v.HaltHeights())
+
+	ctx := context.Background()
+	_, _, err := v.Sign(ctx, "osmosis-1", Block{Height: 100})
+	require.NoError(t, err)
+	_, _, err = v.Sign(ctx, "osmosis-1", Block{Height: 101})
+	require.IsType(t, &HaltHeightError{}, err)
+
+	// the halt height of a chain applies to every validator of the chain, the halt height of a
+	// validator chain ID only to the validator.
+	_, _, err = v.Sign(ctx, "acme@osmosis-1", Block{Height: 101})
+	require.IsType(t, &HaltHeightError{}, err)
+	_, _, err = v.Sign(ctx, "acme@cosmoshub-4", Block{Height: 201})
+	require.IsType(t, &HaltHeightError{}, err)
+	_, _, err = v.Sign(ctx, "cosmoshub-4", Block{Height: 201})
+	require.NoError(t, err)
+	_, _, err = v.Sign(ctx, "juno-1", Block{Height: 1000})
+	require.NoError(t, err)
+
+	require.Error(t, v.SetHaltHeight("juno-1", 0))
+	require.NoError(t, v.SetHaltHeight("juno-1", 500))
+	_, _, err = v.Sign(ctx, "juno-1", Block{Height: 1000})
+	require.IsType(t, &HaltHeightError{}, err)
+
+	require.True(t, v.ClearHaltHeight("osmosis-1"))
+	require.False(t, v.ClearHaltHeight("osmosis-1"))
+	_, _, err = v.Sign(ctx, "osmosis-1", Block{Height: 101})
+	require.NoError(t, err)
+	require.Equal(t, 4, pv.signed)
+}
@@ -0,0 +1,356 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// warmupPollInterval is how often the warm-up of the nonce cache is checked while the connections
+// to the chain nodes are held.
+const warmupPollInterval = 250 * time.Millisecond
+
+// HealthReport is the health of the signer served on the debug server.
+type HealthReport struct {
+	SignMode SignMode `json:"sign_mode"`
+
+	// Threshold is only set in threshold mode.
+	Threshold *ThresholdHealth `json:"threshold,omitempty"`
+
+	// Chains holds the last signature of each chain signed since the signer started.
+	Chains map[string]ChainHealth `json:"chains"`
+}
+
+// ThresholdHealth is the health of a cosigner and its view of the peer cosigners.
+type ThresholdHealth struct {
+	CosignerID int  `json:"cosigner_id"`
+	Leader     int  `json:"leader"`
+	IsLeader   bool `json:"is_leader"`
+
+	Peers      []PeerHealth     `json:"peers"`
+	NonceCache NonceCacheHealth `json:"nonce_cache"`
+
+	// SplitBrain is only set while another cosigner claims the leadership too.
+	SplitBrain *SplitBrainHealth `json:"split_brain,omitempty"`
+}
+
+// SplitBrainHealth is another cosigner that claims the leadership at the same time as this leader.
+type SplitBrainHealth struct {
+	Leader int `json:"leader"`
+	// Fenced is true if this cosigner is fenced from initiating sign rounds in favor of the other leader.
+	Fenced bool `json:"fenced"`
+}
+
+// PeerHealth is the reachability of a peer cosigner. Peers are only pinged by the leader,
+// so Reachable is nil on other cosigners.
+type PeerHealth struct {
+	ID              int     `json:"id"`
+	Address         string  `json:"address"`
+	Reachable       *bool   `json:"reachable,omitempty"`
+	RTTMilliseconds float64 `json:"rtt_ms,omitempty"`
+	ProtocolVersion uint32  `json:"protocol_version"`
+	// Build and VersionSkew are set once the leader shook hands with the peer.
+	Build       *CosignerBuild `json:"build,omitempty"`
+	VersionSkew VersionSkew    `json:"version_skew,omitempty"`
+	// Score is the health score of the cosigner from 0 to 1, once a request to it was recorded.
+	Score       *float64 `json:"score,omitempty"`
+	Quarantined bool     `json:"quarantined,omitempty"`
+}
+
+// NonceCacheHealth is the fill of the nonce cache. The target size is only known on the leader.
+type NonceCacheHealth struct {
+	Size       int     `json:"size"`
+	TargetSize int     `json:"target_size"`
+	FillRatio  float64 `json:"fill_ratio"`
+}
+
+// ChainHealth is the last signature of a chain.
+type ChainHealth struct {
+	Height               int64     `json:"height"`
+	Round                int64     `json:"round"`
+	Step                 int8      `json:"step"`
+	SignedAt             time.Time `json:"signed_at"`
+	SecondsSinceLastSign float64   `json:"seconds_since_last_sign"`
+}
+
+// Health collects the health of the signer.
+type Health struct {
+	signMode  SignMode
+	threshold *ThresholdValidator
+
+	mu     sync.RWMutex
+	chains map[string]ChainHealth
+
+	// nonceCacheWarm is set once the nonce cache of the leader reached its warm-up fill.
+	nonceCacheWarm atomic.Bool
+}
+
+// NewHealth returns a Health of a signer in the sign mode. threshold is
+// the ThresholdValidator in threshold mode, otherwise nil.
+func NewHealth(signMode SignMode, threshold *ThresholdValidator) *Health {
+	return &Health{
+		signMode:  signMode,
+		threshold: threshold,
+		chains:    make(map[string]ChainHealth),
+	}
+}
+
+func (h *Health) recordSign(chainID string, block Block) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.chains[chainID] = ChainHealth{
+		Height:   block.Height,
+		Round:    block.Round,
+		Step:     block.Step,
+		SignedAt: time.Now(),
+	}
+}
+
+// Report returns the current health of the signer.
+func (h *Health) Report() HealthReport {
+	report := HealthReport{
+		SignMode: h.signMode,
+		Chains:   make(map[string]ChainHealth),
+	}
+
+	h.mu.RLock()
+	for chainID, c := range h.chains {
+		c.SecondsSinceLastSign = time.Since(c.SignedAt).Seconds()
+		report.Chains[chainID] = c
+	}
+	h.mu.RUnlock()
+
+	if h.threshold != nil {
+		health := h.threshold.Health()
+		report.Threshold = &health
+	}
+
+	return report
+}
+
+// Ready returns true if the signer is ready to serve sign requests, otherwise the reasons it is not.
+// A cosigner is ready once raft has a leader. The leader is ready while enough peer cosigners
+// to sign are reachable, and once its nonce cache has warmed up after it started.
+func (h *Health) Ready() (bool, []string) {
+	if h.threshold == nil {
+		return true, nil
+	}
+
+	health := h.threshold.Health()
+
+	var reasons []string
+	if health.Leader == -1 {
+		reasons = append(reasons, "raft has no leader")
+	}
+
+	if health.IsLeader {
+		reachable := 0
+		for _, p := range health.Peers {
+			if p.Reachable != nil && *p.Reachable {
+				reachable++
+			}
+		}
+		if required := h.threshold.threshold - 1; reachable < required {
+			reasons = append(reasons, fmt.Sprintf(
+				"%d peer cosigners reachable, %d required to sign", reachable, required,
+			))
+		}
+
+		if !h.warm(health) {
+			reasons = append(reasons, fmt.Sprintf(
+				"nonce cache warming up, %d of %d nonces ready",
+				health.NonceCache.Size, health.NonceCache.TargetSize,
+			))
+		}
+	}
+
+	return len(reasons) == 0, reasons
+}
+
+// warm returns true once the nonce cache of the leader has warmed up since the signer started.
+// Once it has, a drained nonce cache does not make the leader cold again.
+func (h *Health) warm(health ThresholdHealth) bool {
+	if h.nonceCacheWarm.Load() {
+		return true
+	}
+	if health.IsLeader && h.threshold.nonceCache.warmup.warm(health.NonceCache) {
+		h.nonceCacheWarm.Store(true)
+	}
+	return h.nonceCacheWarm.Load()
+}
+
+// HoldChainNodes returns a channel that is closed once the connections to the chain nodes may be
+// established, or nil if they are not held. With holdChainNodes in the nonce cache warm-up config,
+// the connections are held until raft has a leader and the nonce cache of the leader warmed up, or
+// at most for the warm-up timeout.
+func (h *Health) HoldChainNodes() <-chan struct{} {
+	if h.threshold == nil || !h.threshold.nonceCache.warmup.holdChainNodes {
+		return nil
+	}
+	hold := make(chan struct{})
+	go func() {
+		defer close(hold)
+		timeout := time.NewTimer(h.threshold.nonceCache.warmup.timeout)
+		defer timeout.Stop()
+		ticker := time.NewTicker(warmupPollInterval)
+		defer ticker.Stop()
+		for {
+			health := h.threshold.Health()
+			if health.Leader != -1 && (!health.IsLeader || h.warm(health)) {
+				return
+			}
+			select {
+			case <-timeout.C:
+				h.threshold.logger.Error(
+					"Nonce cache did not warm up in time, connecting to the chain nodes",
+					"timeout", h.threshold.nonceCache.warmup.timeout,
+					"size", health.NonceCache.Size,
+					"target_size", health.NonceCache.TargetSize,
+				)
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return hold
+}
+
+// Stalled returns the main loops of the signer that did not run within maxAge, e.g. because they
+// are deadlocked.
+func (h *Health) Stalled(maxAge time.Duration) []string {
+	if h.threshold == nil {
+		return nil
+	}
+	var stalled []string
+	if last := h.threshold.nonceCache.LastLoop(); !last.IsZero() && time.Since(last) > maxAge {
+		stalled = append(stalled, fmt.Sprintf(
+			"nonce cache loop did not run for %s", time.Since(last).Round(time.Second),
+		))
+	}
+	return stalled
+}
+
+// ServeLive responds with 200 OK while the signer process is serving requests.
+func (h *Health) ServeLive(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(struct {
+		Live bool `json:"live"`
+	}{
+		Live: true,
+	})
+}
+
+// ServeReady responds with 200 OK if the signer is ready to serve sign requests,
+// otherwise with 503 Service Unavailable and the reasons it is not ready.
+func (h *Health) ServeReady(w http.ResponseWriter, _ *http.Request) {
+	ready, reasons := h.Ready()
+	w.Header().Set("Content-Type", "application/json")
+	if !ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(struct {
+		Ready   bool     `json:"ready"`
+		Reasons []string `json:"reasons,omitempty"`
+	}{
+		Ready:   ready,
+		Reasons: reasons,
+	})
+}
+
+// ServeHTTP serves the health report as JSON.
+func (h *Health) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.Report())
+}
+
+// Health returns the health of the cosigner and its view of the peer cosigners.
+func (pv *ThresholdValidator) Health() ThresholdHealth {
+	peers := pv.Peers()
+	health := ThresholdHealth{
+		CosignerID: pv.myCosigner.GetID(),
+		Leader:     pv.leader.GetLeader(),
+		IsLeader:   pv.leader.IsLeader(),
+		Peers:      make([]PeerHealth, 0, len(peers)),
+	}
+
+	for _, peer := range peers {
+		p := PeerHealth{
+			ID:      peer.GetID(),
+			Address: peer.GetAddress(),
+		}
+		if rc, ok := peer.(*RemoteCosigner); ok {
+			p.ProtocolVersion = rc.ProtocolVersion()
+			if build, ok := rc.Build(); ok {
+				p.Build = &build
+				p.VersionSkew = rc.VersionSkew()
+			}
+		}
+		if rtt, ok := pv.cosignerHealth.RTT(peer); ok {
+			reachable := rtt >= 0
+			p.Reachable = &reachable
+			if reachable {
+				p.RTTMilliseconds = float64(rtt) / float64(time.Millisecond)
+			}
+		}
+		if score, ok := pv.cosignerHealth.Score(peer); ok {
+			p.Score = &score
+		}
+		p.Quarantined = pv.cosignerHealth.Quarantined(peer)
+		health.Peers = append(health.Peers, p)
+	}
+	sort.Slice(health.Peers, func(i, j int) bool { return health.Peers[i].ID < health.Peers[j].ID })
+
+	if rival, fenced := pv.splitBrain.Status(time.Now()); rival != 0 {
+		health.SplitBrain = &SplitBrainHealth{Leader: rival, Fenced: fenced}
+	}
+
+	size, target := pv.nonceCache.Size()
+	health.NonceCache = NonceCacheHealth{
+		Size:       size,
+		TargetSize: target,
+	}
+	if target > 0 {
+		health.NonceCache.FillRatio = float64(size) / float64(target)
+	}
+
+	return health
+}
+
+// HealthValidator is a PrivValidator that records the last signature of each chain in a Health.
+type HealthValidator struct {
+	val    PrivValidator
+	health *Health
+}
+
+// NewHealthValidator returns a HealthValidator that records the signatures of val in health.
+func NewHealthValidator(val PrivValidator, health *Health) *HealthValidator {
+	return &HealthValidator{
+		val:    val,
+		health: health,
+	}
+}
+
+// Sign implements PrivValidator.
+func (v *HealthValidator) Sign(ctx context.Context, chainID string, block Block) ([]byte, time.Time, error) {
+	sig, timestamp, err := v.val.Sign(ctx, chainID, block)
+	if err != nil {
+		return sig, timestamp, err
+	}
+	v.health.recordSign(chainID, block)
+	return sig, timestamp, nil
+}
+
+// GetPubKey implements PrivValidator.
+func (v *HealthValidator) GetPubKey(ctx context.Context, chainID string) ([]byte, error) {
+	return v.val.GetPubKey(ctx, chainID)
+}
+
+// Stop implements PrivValidator.
+func (v *HealthValidator) Stop() {
+	v.val.Stop()
+}
@@ -0,0 +1,208 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthSingleSigner(t *testing.T) {
+	health := NewHealth(SignModeSingle, nil)
+	val := NewHealthValidator(&mockPrivValidator{}, health)
+
+	report := health.Report()
+	require.Equal(t, SignModeSingle, report.SignMode)
+	require.Nil(t, report.Threshold)
+	require.Empty(t, report.Chains)
+
+	_, _, err := val.Sign(context.Background(), "horcrux-1", Block{Height: 10, Round: 1, Step: stepPrecommit})
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	health.ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var res HealthReport
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+	require.Contains(t, res.Chains, "horcrux-1")
+	chain := res.Chains["horcrux-1"]
+	require.Equal(t, int64(10), chain.Height)
+	require.Equal(t, int64(1), chain.Round)
+	require.Equal(t, stepPrecommit, chain.Step)
+	require.Less(t, chain.SecondsSinceLastSign, float64(10))
+}
+
+func TestHealthThreshold(t *testing.T) {
+	cosigners, _ := getTestLocalCosigners(t, 2, 3)
+
+	peers := []Cosigner{cosigners[1], cosigners[2]}
+	leader := &MockLeader{id: 1}
+
+	validator := NewThresholdValidator(
+		cometlog.NewNopLogger(),
+		cosigners[0].config,
+		2,
+		time.Second,
+		1,
+		cosigners[0],
+		peers,
+		leader,
+	)
+	defer validator.Stop()
+	leader.SetLeader(validator)
+
+	health := validator.Health()
+	require.Equal(t, 1, health.CosignerID)
+	require.Equal(t, 1, health.Leader)
+	require.True(t, health.IsLeader)
+	require.Len(t, health.Peers, 2)
+	for _, p := range health.Peers {
+		// not pinged yet.
+		require.Nil(t, p.Reachable)
+	}
+
+	validator.cosignerHealth.MarkUnhealthy(cosigners[2])
+	health = validator.Health()
+	require.Nil(t, health.Peers[0].Reachable)
+	require.NotNil(t, health.Peers[1].Reachable)
+	require.False(t, *health.Peers[1].Reachable)
+
+	validator.nonceCache.targetSize.Store(4)
+	validator.nonceCache.LoadN(context.Background(), 2)
+	health = validator.Health()
+	require.Equal(t, 2, health.NonceCache.Size)
+	require.Equal(t, 4, health.NonceCache.TargetSize)
+	require.Equal(t, 0.5, health.NonceCache.FillRatio)
+}
+
+func TestHealthReady(t *testing.T) {
+	ready, _ := NewHealth(SignModeSingle, nil).Ready()
+	require.True(t, ready)
+
+	cosigners, _ := getTestLocalCosigners(t, 2, 3)
+
+	peers := []Cosigner{cosigners[1], cosigners[2]}
+	leader := &MockLeader{id: 1}
+
+	validator := NewThresholdValidator(
+		cometlog.NewNopLogger(),
+		cosigners[0].config,
+		2,
+		time.Second,
+		1,
+		cosigners[0],
+		peers,
+		leader,
+	)
+	defer validator.Stop()
+	leader.SetLeader(validator)
+
+	health := NewHealth(SignModeThreshold, validator)
+
+	getReady := func() (int, []string) {
+		rec := httptest.NewRecorder()
+		health.ServeReady(rec, httptest.NewRequest("GET", "/ready", nil))
+		var res struct {
+			Ready   bool     `json:"ready"`
+			Reasons []string `json:"reasons"`
+		}
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		require.Equal(t, res.Ready, rec.Code == http.StatusOK)
+		return rec.Code, res.Reasons
+	}
+
+	code, reasons := getReady()
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Len(t, reasons, 2, "peers unreachable and nonce cache empty")
+
+	validator.cosignerHealth.mu.Lock()
+	validator.cosignerHealth.rtt[cosigners[1].GetID()] = int64(time.Millisecond)
+	validator.cosignerHealth.mu.Unlock()
+
+	code, reasons = getReady()
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Len(t, reasons, 1, "nonce cache empty")
+
+	validator.nonceCache.targetSize.Store(4)
+	validator.nonceCache.LoadN(context.Background(), 2)
+
+	code, _ = getReady()
+	require.Equal(t, http.StatusOK, code)
+
+	// the nonce cache only gates the warm-up.
+	_, err := validator.nonceCache.GetNonces([]Cosigner{cosigners[0], cosigners[1]})
+	require.NoError(t, err)
+	code, _ = getReady()
+	require.Equal(t, http.StatusOK, code)
+
+	validator.cosignerHealth.MarkUnhealthy(cosigners[1])
+	code, _ = getReady()
+	require.Equal(t, http.StatusServiceUnavailable, code)
+
+	rec := httptest.NewRecorder()
+	health.ServeLive(rec, httptest.NewRequest("GET", "/live", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestHealthHoldChainNodes(t *testing.T) {
+	cosigners, _ := getTestLocalCosigners(t, 2, 3)
+
+	peers := []Cosigner{cosigners[1], cosigners[2]}
+	leader := &MockLeader{id: 1}
+
+	validator := NewThresholdValidator(
+		cometlog.NewNopLogger(),
+		cosigners[0].config,
+		2,
+		time.Second,
+		1,
+		cosigners[0],
+		peers,
+		leader,
+	)
+	defer validator.Stop()
+	leader.SetLeader(validator)
+
+	health := NewHealth(SignModeThreshold, validator)
+	require.Nil(t, health.HoldChainNodes())
+
+	validator.nonceCache.warmup = nonceCacheWarmup{
+		minFill:        0.5,
+		minNonces:      3,
+		holdChainNodes: true,
+		timeout:        time.Minute,
+	}
+	hold := health.HoldChainNodes()
+	require.NotNil(t, hold)
+
+	validator.nonceCache.targetSize.Store(4)
+	validator.nonceCache.LoadN(context.Background(), 2)
+	select {
+	case <-hold:
+		t.Fatal("chain nodes released before the nonce cache held minNonces")
+	case <-time.After(2 * warmupPollInterval):
+	}
+
+	validator.nonceCache.LoadN(context.Background(), 1)
+	select {
+	case <-hold:
+	case <-time.After(time.Second):
+		t.Fatal("chain nodes held once the nonce cache warmed up")
+	}
+
+	// the hold ends after the timeout if the nonce cache does not warm up.
+	health = NewHealth(SignModeThreshold, validator)
+	validator.nonceCache.warmup.minNonces = 10
+	validator.nonceCache.warmup.timeout = 100 * time.Millisecond
+	select {
+	case <-health.HoldChainNodes():
+	case <-time.After(time.Second):
+		t.Fatal("chain nodes held after the warm-up timeout")
+	}
+}
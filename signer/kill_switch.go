@@ -0,0 +1,176 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cometbft/cometbft/libs/tempfile"
+	"github.com/strangelove-ventures/horcrux/signer/proto"
+)
+
+// KillSwitchFile is the file in the state directory that holds the kill switch while it is engaged.
+const KillSwitchFile = "kill_switch.json"
+
+type KillSwitchError struct {
+	msg string
+}
+
+func (e *KillSwitchError) Error() string { return e.msg }
+
+func newKillSwitchError(since time.Time, reason string) *KillSwitchError {
+	msg := fmt.Sprintf("signing is disabled by the kill switch since %s", since.UTC().Format(time.RFC3339))
+	if reason != "" {
+		msg += fmt.Sprintf(" (%s)", reason)
+	}
+	return &KillSwitchError{msg: msg + ", it must be re-armed through the admin API"}
+}
+
+// KillSwitchStatus is whether the kill switch is engaged, since when and why.
+type KillSwitchStatus struct {
+	Engaged bool       `json:"engaged"`
+	Since   *time.Time `json:"since,omitempty"`
+	Reason  string     `json:"reason,omitempty"`
+}
+
+// KillSwitchValidator is a PrivValidator that refuses every sign request once its kill switch is
+// engaged, e.g. by a security team on a validator that may be compromised. Unlike a pause, the kill
+// switch is saved to the state directory before it takes effect, so it stays engaged across
+// restarts until it is re-armed explicitly.
+type KillSwitchValidator struct {
+	val  PrivValidator
+	file string
+
+	mu     sync.RWMutex
+	status KillSwitchStatus
+}
+
+// NewKillSwitchValidator returns a KillSwitchValidator for the sign requests of val, engaged if the
+// kill switch file of the state directory exists. val may be nil until it is set with Wrap.
+func NewKillSwitchValidator(val PrivValidator, stateDir string) (*KillSwitchValidator, error) {
+	v := &KillSwitchValidator{
+		val:  val,
+		file: filepath.Join(stateDir, KillSwitchFile),
+	}
+	bz, err := os.ReadFile(v.file)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		killSwitchEngaged.Set(0)
+		return v, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to read kill switch: %w", err)
+	}
+	if err := json.Unmarshal(bz, &v.status); err != nil {
+		return nil, fmt.Errorf("failed to parse kill switch %s: %w", v.file, err)
+	}
+	// a kill switch file is engaged whatever it holds.
+	if v.status.Since == nil {
+		now := time.Now()
+		v.status.Since = &now
+	}
+	v.status.Engaged = true
+	killSwitchEngaged.Set(1)
+	return v, nil
+}
+
+// Wrap sets val as the validator of the sign requests of a kill switch loaded before it, e.g. to
+// pass the kill switch to the threshold validator before its cosigner gRPC server starts, and
+// returns the kill switch.
+func (v *KillSwitchValidator) Wrap(val PrivValidator) *KillSwitchValidator {
+	v.val = val
+	return v
+}
+
+// Engage refuses the sign requests until Rearm, also after a restart. The kill switch of an
+// engaged KillSwitchValidator keeps the time and reason it was first engaged with.
+func (v *KillSwitchValidator) Engage(reason string) (KillSwitchStatus, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.status.Engaged {
+		return v.status, nil
+	}
+	now := time.Now()
+	status := KillSwitchStatus{Engaged: true, Since: &now, Reason: reason}
+	bz, err := json.Marshal(status)
+	if err != nil {
+		return v.status, err
+	}
+	if err := tempfile.WriteFileAtomic(v.file, bz, 0600); err != nil {
+		return v.status, fmt.Errorf("failed to save kill switch: %w", err)
+	}
+	v.status = status
+	killSwitchEngaged.Set(1)
+	return v.status, nil
+}
+
+// Rearm signs the sign requests again, and removes the kill switch file.
+func (v *KillSwitchValidator) Rearm() (KillSwitchStatus, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := os.Remove(v.file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return v.status, fmt.Errorf("failed to remove kill switch: %w", err)
+	}
+	v.status = KillSwitchStatus{}
+	killSwitchEngaged.Set(0)
+	return v.status, nil
+}
+
+// Status returns whether the kill switch is engaged, since when and why.
+func (v *KillSwitchValidator) Status() KillSwitchStatus {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.status
+}
+
+// EngagedError returns a *KillSwitchError if the kill switch is engaged, or nil.
+func (v *KillSwitchValidator) EngagedError() error {
+	if v == nil {
+		return nil
+	}
+	if status := v.Status(); status.Engaged {
+		return newKillSwitchError(*status.Since, status.Reason)
+	}
+	return nil
+}
+
+// Sign implements PrivValidator.
+func (v *KillSwitchValidator) Sign(ctx context.Context, chainID string, block Block) ([]byte, time.Time, error) {
+	if err := v.EngagedError(); err != nil {
+		totalKillSwitchRefusals.WithLabelValues(chainID).Inc()
+		return nil, block.Timestamp, err
+	}
+	return v.val.Sign(ctx, chainID, block)
+}
+
+// GetPubKey implements PrivValidator.
+func (v *KillSwitchValidator) GetPubKey(ctx context.Context, chainID string) ([]byte, error) {
+	return v.val.GetPubKey(ctx, chainID)
+}
+
+// Stop implements PrivValidator.
+func (v *KillSwitchValidator) Stop() {
+	v.val.Stop()
+}
+
+// ToProto returns the kill switch status as protobuf.
+func (s KillSwitchStatus) ToProto() *proto.KillSwitchStatus {
+	res := &proto.KillSwitchStatus{Engaged: s.Engaged, Reason: s.Reason}
+	if s.Since != nil {
+		res.Since = s.Since.UnixNano()
+	}
+	return res
+}
+
+// KillSwitchStatusFromProto returns the kill switch status of its protobuf.
+func KillSwitchStatusFromProto(s *proto.KillSwitchStatus) KillSwitchStatus {
+	if s == nil || !s.Engaged {
+		return KillSwitchStatus{}
+	}
+	since := time.Unix(0, s.Since)
+	return KillSwitchStatus{Engaged: true, Since: &since, Reason: s.Reason}
+}
@@ -0,0 +1,217 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/strangelove-ventures/horcrux/signer/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestKillSwitchValidator(t *testing.T) {
+	const chainID = "kill-switch-1"
+	stateDir := t.TempDir()
+	pv := &mockPrivValidator{}
+
+	kill, err := NewKillSwitchValidator(pv, stateDir)
+	require.NoError(t, err)
+	require.Equal(t, KillSwitchStatus{}, kill.Status())
+	require.NoError(t, kill.EngagedError())
+	require.NoError(t, (*KillSwitchValidator)(nil).EngagedError())
+	_, _, err = kill.Sign(context.Background(), chainID, Block{Height: 1})
+	require.NoError(t, err)
+
+	status, err := kill.Engage("key may be compromised")
+	require.NoError(t, err)
+	require.True(t, status.Engaged)
+	require.FileExists(t, filepath.Join(stateDir, KillSwitchFile))
+	require.Equal(t, float64(1), testutil.ToFloat64(killSwitchEngaged))
+
+	_, _, err = kill.Sign(context.Background(), chainID, Block{Height: 2})
+	require.IsType(t, &KillSwitchError{}, err)
+	require.ErrorContains(t, err, "signing is disabled by the kill switch since")
+	require.ErrorContains(t, err, "(key may be compromised), it must be re-armed")
+	require.Equal(t, 1, pv.signed)
+	require.Equal(t, float64(1), testutil.ToFloat64(totalKillSwitchRefusals.WithLabelValues(chainID)))
+
+	// engaging again keeps the time and reason the kill switch was first engaged with.
+	again, err := kill.Engage("other")
+	require.NoError(t, err)
+	require.Equal(t, status, again)
+
+	// the kill switch stays engaged after a restart.
+	restarted, err := NewKillSwitchValidator(pv, stateDir)
+	require.NoError(t, err)
+	require.True(t, restarted.Status().Engaged)
+	require.True(t, status.Since.Equal(*restarted.Status().Since))
+	require.Equal(t, "key may be compromised", restarted.Status().Reason)
+
+	status, err = restarted.Rearm()
+	require.NoError(t, err)
+	require.Equal(t, KillSwitchStatus{}, status)
+	require.NoFileExists(t, filepath.Join(stateDir, KillSwitchFile))
+	_, _, err = restarted.Sign(context.Background(), chainID, Block{Height: 3})
+	require.NoError(t, err)
+
+	restarted, err = NewKillSwitchValidator(pv, stateDir)
+	require.NoError(t, err)
+	require.False(t, restarted.Status().Engaged)
+
+	// a kill switch file that cannot be parsed fails the start, rather than signing.
+	require.NoError(t, os.WriteFile(filepath.Join(stateDir, KillSwitchFile), []byte("engaged"), 0600))
+	_, err = NewKillSwitchValidator(pv, stateDir)
+	require.Error(t, err)
+
+	decoded := KillSwitchStatusFromProto(again.ToProto())
+	require.True(t, decoded.Engaged)
+	require.True(t, again.Since.Equal(*decoded.Since))
+	require.Equal(t, again.Reason, decoded.Reason)
+	require.Equal(t, KillSwitchStatus{}, KillSwitchStatusFromProto(nil))
+}
+
+// newKillSwitchTestValidator returns a ThresholdValidator of the cosigner with the shard ID that
+// only has a kill switch, with the peers.
+func newKillSwitchTestValidator(t *testing.T, id int, peers ...Cosigner) (*ThresholdValidator, *KillSwitchValidator) {
+	val, _ := newPauseTestValidator(t, id, peers...)
+	kill, err := NewKillSwitchValidator(&mockPrivValidator{}, t.TempDir())
+	require.NoError(t, err)
+	val.SetKillSwitch(kill)
+	return val, kill
+}
+
+func TestSetClusterKillSwitch(t *testing.T) {
+	peerVal, peerKill := newKillSwitchTestValidator(t, 2)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcServer := grpc.NewServer()
+	proto.RegisterCosignerServer(grpcServer, NewCosignerGRPCServer(nil, peerVal, nil))
+	go func() { _ = grpcServer.Serve(ln) }()
+	defer grpcServer.Stop()
+
+	peer, err := NewRemoteCosigner(2, "tcp://"+ln.Addr().String(), TCPCosignerTransport{})
+	require.NoError(t, err)
+	// nothing listens on port 1.
+	unreachable, err := NewRemoteCosigner(3, "tcp://127.0.0.1:1", TCPCosignerTransport{})
+	require.NoError(t, err)
+
+	val, kill := newKillSwitchTestValidator(t, 1, peer, unreachable)
+	ctx := context.Background()
+
+	status, err := val.SetClusterKillSwitch(ctx, true, "incident 42", nil)
+	require.NoError(t, err)
+	require.False(t, status.Engaged)
+	require.True(t, status.Failed())
+	require.Len(t, status.Cosigners, 3)
+	require.True(t, status.Cosigners[0].Engaged)
+	require.True(t, status.Cosigners[1].Engaged)
+	require.Equal(t, "incident 42", status.Cosigners[1].Reason)
+	require.NotEmpty(t, status.Cosigners[2].Error)
+
+	require.True(t, kill.Status().Engaged)
+	require.True(t, peerKill.Status().Engaged)
+
+	// a cosigner with an engaged kill switch refuses the sign requests proxied by the other
+	// cosigners and signing with its key shard.
+	_, err = peer.Sign(ctx, CosignerSignBlockRequest{ChainID: testChainID, Block: &Block{Height: 1}})
+	require.ErrorContains(t, err, "signing is disabled by the kill switch")
+	_, err = peer.SetNoncesAndSign(ctx, CosignerSetNoncesAndSignRequest{
+		ChainID: testChainID,
+		Nonces:  &CosignerUUIDNonces{},
+	})
+	require.ErrorContains(t, err, "signing is disabled by the kill switch")
+
+	// without the unreachable cosigner, the kill switch of the cluster is engaged.
+	val.peerCosigners = Cosigners{peer}
+	status, err = val.SetClusterKillSwitch(ctx, true, "incident 42", nil)
+	require.NoError(t, err)
+	require.True(t, status.Engaged)
+
+	status, err = val.SetClusterKillSwitch(ctx, false, "", nil)
+	require.NoError(t, err)
+	require.False(t, status.Engaged)
+	require.False(t, status.Failed())
+	require.False(t, kill.Status().Engaged)
+	require.False(t, peerKill.Status().Engaged)
+
+	// a cosigner whose kill switch is not loaded yet refuses them as well, as it may be engaged.
+	peerVal.SetKillSwitch(nil)
+	_, err = peer.Sign(ctx, CosignerSignBlockRequest{ChainID: testChainID, Block: &Block{Height: 1}})
+	require.ErrorContains(t, err, "kill switch of the cosigner is not loaded")
+	_, err = peer.SetNoncesAndSign(ctx, CosignerSetNoncesAndSignRequest{
+		ChainID: testChainID,
+		Nonces:  &CosignerUUIDNonces{},
+	})
+	require.ErrorContains(t, err, "kill switch of the cosigner is not loaded")
+}
+
+func TestAdminAPIKillSwitch(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("secret"), 0600))
+
+	val, kill := newKillSwitchTestValidator(t, 1)
+	cfg := &AdminAPIConfig{
+		ListenAddr:        "127.0.0.1:0",
+		DebugServerConfig: DebugServerConfig{BearerTokenFile: tokenFile},
+	}
+	a, err := NewAdminAPI(cometlog.NewNopLogger(), cfg, NewHealth(SignModeThreshold, nil), NewLogLevels(),
+		val.signingPause(), nil, val, nil)
+	require.NoError(t, err)
+	a.SetKillSwitch(kill)
+	handler, err := cfg.Handler(a.Handler())
+	require.NoError(t, err)
+
+	request := func(method, target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := request(http.MethodGet, "/v1/kill_switch")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"engaged":false}`, rec.Body.String())
+
+	rec = request(http.MethodPost, "/v1/kill_switch/engage?reason=leaked+key")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"reason":"leaked key"`)
+	require.True(t, kill.Status().Engaged)
+
+	rec = request(http.MethodPost, "/v1/kill_switch/rearm")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"engaged":false}`, rec.Body.String())
+
+	rec = request(http.MethodPost, "/v1/cluster/kill_switch/engage")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var status ClusterKillSwitchStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	require.True(t, status.Engaged)
+	require.Len(t, status.Cosigners, 1)
+
+	rec = request(http.MethodGet, "/v1/cluster/kill_switch")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"engaged":true`)
+
+	rec = request(http.MethodPost, "/v1/cluster/kill_switch/rearm")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"engaged":false,"cosigners":[{"id":1,"engaged":false}]}`, rec.Body.String())
+
+	reason := strings.Repeat("a", adminMaxKillSwitchReasonSize+1)
+	require.Equal(t, http.StatusBadRequest, request(http.MethodPost, "/v1/kill_switch/engage?reason="+reason).Code)
+	require.False(t, kill.Status().Engaged)
+
+	require.Equal(t, http.StatusMethodNotAllowed, request(http.MethodGet, "/v1/kill_switch/engage").Code)
+	require.Equal(t, http.StatusMethodNotAllowed, request(http.MethodPost, "/v1/kill_switch").Code)
+	require.Equal(t, http.StatusMethodNotAllowed, request(http.MethodPost, "/v1/cluster/kill_switch").Code)
+}
@@ -11,3 +11,17 @@ type Leader interface {
 	// Get current leader
 	GetLeader() int
 }
+
+// ClusterLeader is a Leader elected among the cosigners, which serves the leadership requests
+// of the cosigner gRPC server.
+type ClusterLeader interface {
+	Leader
+
+	// TransferLeadership hands the leadership over to the cosigner with the shard ID, or to the
+	// next eligible cosigner if the shard ID is empty. It returns the shard ID and the address of
+	// the new leader if known, and does nothing if the cosigner is not the leader.
+	TransferLeadership(shardID string) (leaderID string, leaderAddress string)
+
+	// SetFeatureFlag shares a feature flag change with all cosigners.
+	SetFeatureFlag(event FeatureFlagEvent) error
+}
@@ -0,0 +1,122 @@
+package signer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/strangelove-ventures/horcrux/signer/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// Leader election backends of the cosigners.
+const (
+	LeaderElectionRaft       = "raft"
+	LeaderElectionKubernetes = "kubernetes"
+	LeaderElectionEtcd       = "etcd"
+	LeaderElectionLeaderless = "leaderless"
+)
+
+// leaderHealthService is the gRPC health service that is serving only on the leader, which
+// `horcrux elect` uses to find the leader.
+const leaderHealthService = "Leader"
+
+// LeaderElectionConfig is the on disk config format for the leader election of the cosigners.
+// The cosigners elect the leader with raft if it is not configured.
+type LeaderElectionConfig struct {
+	Type string `yaml:"type,omitempty"`
+
+	// PriorityTakeoverDelay is how long a cosigner with a higher priority than the leader must be
+	// reachable before the leader hands the leadership over to it.
+	PriorityTakeoverDelay string `yaml:"priorityTakeoverDelay,omitempty"`
+
+	Raft       *RaftElectionConfig    `yaml:"raft,omitempty"`
+	Kubernetes *KubernetesLeaseConfig `yaml:"kubernetes,omitempty"`
+	Etcd       *EtcdElectionConfig    `yaml:"etcd,omitempty"`
+}
+
+func (cfg *LeaderElectionConfig) Validate() error {
+	if cfg == nil {
+		return nil
+	}
+	if _, err := cfg.priorityTakeoverDelay(); err != nil {
+		return fmt.Errorf("invalid priorityTakeoverDelay: %w", err)
+	}
+	switch cfg.Type {
+	case "", LeaderElectionRaft, LeaderElectionLeaderless:
+		return nil
+	case LeaderElectionKubernetes:
+		if cfg.Kubernetes == nil {
+			return fmt.Errorf("kubernetes leader election requires kubernetes config")
+		}
+		return cfg.Kubernetes.Validate()
+	case LeaderElectionEtcd:
+		if cfg.Etcd == nil {
+			return fmt.Errorf("etcd leader election requires etcd config")
+		}
+		return cfg.Etcd.Validate()
+	default:
+		return fmt.Errorf("invalid leader election type %q, must be one of %v",
+			cfg.Type, []string{LeaderElectionRaft, LeaderElectionKubernetes, LeaderElectionEtcd, LeaderElectionLeaderless})
+	}
+}
+
+func (cfg *LeaderElectionConfig) priorityTakeoverDelay() (time.Duration, error) {
+	if cfg == nil {
+		return defaultPriorityTakeoverDelay, nil
+	}
+	return parseDurationOrDefault(cfg.PriorityTakeoverDelay, defaultPriorityTakeoverDelay)
+}
+
+// LeaderElectionType returns the leader election backend of the cosigners.
+func (cfg *ThresholdModeConfig) LeaderElectionType() string {
+	if cfg.LeaderElection == nil || cfg.LeaderElection.Type == "" {
+		return LeaderElectionRaft
+	}
+	return cfg.LeaderElection.Type
+}
+
+// RaftTimings returns the timeouts of the raft leader election.
+func (cfg *ThresholdModeConfig) RaftTimings() (RaftTimings, error) {
+	raftTimeout, err := time.ParseDuration(cfg.RaftTimeout)
+	if err != nil {
+		return RaftTimings{}, fmt.Errorf("invalid raftTimeout: %w", err)
+	}
+	var raftCfg *RaftElectionConfig
+	if cfg.LeaderElection != nil {
+		raftCfg = cfg.LeaderElection.Raft
+	}
+	return raftCfg.timings(raftTimeout)
+}
+
+// serveCosignerGRPC serves the cosigner gRPC server for a leader that is not elected by raft,
+// so the listener carries no raft traffic. The health server reports the leader health service.
+func serveCosignerGRPC(
+	transport CosignerTransport,
+	listen string,
+	cosigner *LocalCosigner,
+	thresholdValidator *ThresholdValidator,
+	leader ClusterLeader,
+	healthServer *health.Server,
+) error {
+	sock, err := transport.Listen(p2pURLToRaftAddress(listen))
+	if err != nil {
+		return err
+	}
+	grpcServer := grpc.NewServer(cosignerKeepaliveServerOption())
+	proto.RegisterCosignerServer(grpcServer, NewCosignerGRPCServer(cosigner, thresholdValidator, leader))
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	reflection.Register(grpcServer)
+	return grpcServer.Serve(sock)
+}
+
+// setLeaderHealth sets the leader health service to serving if the cosigner is the leader.
+func setLeaderHealth(healthServer *health.Server, isLeader bool) {
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if isLeader {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+	healthServer.SetServingStatus(leaderHealthService, status)
+}
@@ -0,0 +1,367 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/libs/service"
+	"github.com/hashicorp/raft"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+	"google.golang.org/grpc/health"
+)
+
+var _ ClusterLeader = (*EtcdLeader)(nil)
+
+const (
+	defaultEtcdElectionPrefix = "/horcrux/leader"
+	defaultEtcdElectionTTL    = 5 * time.Second
+
+	// etcdElectionRetryPeriod is the interval of the attempts to reconnect to etcd.
+	etcdElectionRetryPeriod = time.Second
+)
+
+// EtcdElectionConfig is the on disk config format for the leader election with an etcd election.
+type EtcdElectionConfig struct {
+	Endpoints []string `yaml:"endpoints"`
+
+	// Prefix of the election keys, which must be the same on every cosigner of the cluster and
+	// different between clusters.
+	Prefix string `yaml:"prefix,omitempty"`
+
+	// TTL of the etcd lease of each cosigner, after which the leader is replaced if it stopped
+	// keeping its lease alive.
+	TTL string `yaml:"ttl,omitempty"`
+
+	DialTimeout string `yaml:"dialTimeout,omitempty"`
+	Username    string `yaml:"username,omitempty"`
+	Password    string `yaml:"password,omitempty"`
+	CAFile      string `yaml:"caFile,omitempty"`
+	CertFile    string `yaml:"certFile,omitempty"`
+	KeyFile     string `yaml:"keyFile,omitempty"`
+}
+
+func (cfg *EtcdElectionConfig) Validate() error {
+	if cfg == nil {
+		return nil
+	}
+	if len(cfg.Endpoints) == 0 {
+		return fmt.Errorf("etcd leader election requires endpoints")
+	}
+	ttl, err := cfg.ttl()
+	if err != nil {
+		return fmt.Errorf("invalid etcd leader election ttl: %w", err)
+	}
+	if ttl < time.Second || ttl%time.Second != 0 {
+		return fmt.Errorf("etcd leader election ttl %s must be whole seconds", ttl)
+	}
+	if _, err := parseDurationOrDefault(cfg.DialTimeout, defaultEtcdDialTimeout); err != nil {
+		return fmt.Errorf("invalid etcd leader election dialTimeout: %w", err)
+	}
+	return nil
+}
+
+func (cfg *EtcdElectionConfig) ttl() (time.Duration, error) {
+	return parseDurationOrDefault(cfg.TTL, defaultEtcdElectionTTL)
+}
+
+func (cfg *EtcdElectionConfig) prefix() string {
+	if cfg.Prefix == "" {
+		return defaultEtcdElectionPrefix
+	}
+	return strings.TrimSuffix(cfg.Prefix, "/")
+}
+
+// EtcdLeader elects the leader of the cosigners with an etcd election. Every cosigner campaigns
+// with its shard ID as the value of a key under the prefix, which is attached to an etcd lease of
+// the cosigner, and the cosigner with the oldest key is the leader. The cosigners do not run raft,
+// so the cosigner gRPC server is served without the raft transport.
+//
+// The other cosigners watch the key of the leader, so a leader that resigns is replaced at once,
+// and a leader that stops keeping its lease alive is replaced when the lease expires.
+type EtcdLeader struct {
+	service.BaseService
+
+	logger cometlog.Logger
+	client *clientv3.Client
+	prefix string
+	ttl    int
+	id     string
+
+	listen             string
+	transport          CosignerTransport
+	cosigner           *LocalCosigner
+	thresholdValidator *ThresholdValidator
+	health             *health.Server
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// resigned is signaled when the leader resigned, to campaign again.
+	resigned chan struct{}
+
+	mu       sync.Mutex
+	election *concurrency.Election
+	isLeader bool
+	// leader is the shard ID of the leader observed, or an empty string if there is none.
+	leader  string
+	tracker *raftLeadershipTracker
+}
+
+// NewEtcdLeader returns an EtcdLeader of the local cosigner, which serves the cosigner gRPC
+// server at the P2P address with the transport.
+func NewEtcdLeader(
+	logger cometlog.Logger,
+	cfg *EtcdElectionConfig,
+	cosigner *LocalCosigner,
+	listen string,
+	transport CosignerTransport,
+) (*EtcdLeader, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	ttl, _ := cfg.ttl()
+	dialTimeout, _ := parseDurationOrDefault(cfg.DialTimeout, defaultEtcdDialTimeout)
+
+	client, err := newEtcdClient(etcdClientConfig{
+		endpoints:   cfg.Endpoints,
+		dialTimeout: dialTimeout,
+		username:    cfg.Username,
+		password:    cfg.Password,
+		caFile:      cfg.CAFile,
+		certFile:    cfg.CertFile,
+		keyFile:     cfg.KeyFile,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	id := strconv.Itoa(cosigner.GetID())
+	l := &EtcdLeader{
+		logger:    logger,
+		client:    client,
+		prefix:    cfg.prefix(),
+		ttl:       int(ttl / time.Second),
+		id:        id,
+		listen:    listen,
+		transport: transport,
+		cosigner:  cosigner,
+		health:    health.NewServer(),
+		resigned:  make(chan struct{}, 1),
+		tracker:   newRaftLeadershipTracker(raft.ServerID(id), time.Now()),
+	}
+	setLeaderHealth(l.health, false)
+	l.BaseService = *service.NewBaseService(logger, "EtcdLeader", l)
+	return l, nil
+}
+
+func (l *EtcdLeader) SetThresholdValidator(thresholdValidator *ThresholdValidator) {
+	l.thresholdValidator = thresholdValidator
+}
+
+// OnStart serves the cosigner gRPC server and starts campaigning.
+func (l *EtcdLeader) OnStart() error {
+	l.logger.Info("Local cosigner listening", "address", p2pURLToRaftAddress(l.listen))
+	go func() {
+		defer ReportPanic()
+		err := serveCosignerGRPC(l.transport, l.listen, l.cosigner, l.thresholdValidator, l, l.health)
+		if err != nil {
+			panic(err)
+		}
+	}()
+	l.ctx, l.cancel = context.WithCancel(context.Background())
+	go l.elect()
+	return nil
+}
+
+// OnStop resigns, so that another cosigner is elected without waiting for the lease to expire.
+func (l *EtcdLeader) OnStop() {
+	l.resign()
+	l.cancel()
+	_ = l.client.Close()
+}
+
+// elect campaigns with a new session whenever the session of the cosigner ends.
+func (l *EtcdLeader) elect() {
+	for {
+		if err := l.campaign(); err != nil && l.ctx.Err() == nil {
+			l.logger.Error("Etcd leader election failed", "prefix", l.prefix, "error", err)
+		}
+		l.mu.Lock()
+		l.isLeader, l.leader = false, ""
+		l.mu.Unlock()
+		l.observeLeader()
+
+		select {
+		case <-l.ctx.Done():
+			return
+		case <-time.After(etcdElectionRetryPeriod):
+		}
+	}
+}
+
+// campaign campaigns for the leadership until the session ends, and again after resigning.
+func (l *EtcdLeader) campaign() error {
+	session, err := concurrency.NewSession(l.client, concurrency.WithTTL(l.ttl), concurrency.WithContext(l.ctx))
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	ctx, cancel := context.WithCancel(l.ctx)
+	defer cancel()
+	go func() {
+		// the campaign is abandoned once the session ends.
+		select {
+		case <-session.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	election := concurrency.NewElection(session, l.prefix)
+	l.mu.Lock()
+	l.election = election
+	l.mu.Unlock()
+	go l.observe(ctx, election)
+
+	for {
+		if err := election.Campaign(ctx, l.id); err != nil {
+			return err
+		}
+		l.mu.Lock()
+		l.isLeader, l.leader = true, l.id
+		l.mu.Unlock()
+		l.observeLeader()
+
+		select {
+		case <-ctx.Done():
+			if l.ctx.Err() == nil {
+				return errors.New("etcd session expired")
+			}
+			return nil
+		case <-l.resigned:
+		}
+	}
+}
+
+// observe follows the leader of the election until the context is done.
+func (l *EtcdLeader) observe(ctx context.Context, election *concurrency.Election) {
+	for res := range election.Observe(ctx) {
+		if len(res.Kvs) == 0 {
+			continue
+		}
+		l.mu.Lock()
+		l.leader = string(res.Kvs[0].Value)
+		l.mu.Unlock()
+		l.observeLeader()
+	}
+}
+
+// observeLeader updates the leader health service and the leadership metrics.
+func (l *EtcdLeader) observeLeader() {
+	l.mu.Lock()
+	isLeader, leader := l.isLeader, l.leader
+	changed := l.tracker.observe(raft.ServerID(leader), time.Now())
+	l.mu.Unlock()
+
+	setLeaderHealth(l.health, isLeader)
+	if changed {
+		l.logger.Info("Etcd leader changed", "prefix", l.prefix, "leader", leader)
+	}
+}
+
+// resign gives up the leadership if this cosigner is the leader.
+func (l *EtcdLeader) resign() bool {
+	l.mu.Lock()
+	election, isLeader := l.election, l.isLeader
+	l.isLeader = false
+	l.mu.Unlock()
+	if !isLeader {
+		return false
+	}
+	setLeaderHealth(l.health, false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), etcdElectionRetryPeriod)
+	defer cancel()
+	if err := election.Resign(ctx); err != nil {
+		l.logger.Error("Failed to resign etcd leadership", "prefix", l.prefix, "error", err)
+	}
+	return true
+}
+
+// IsLeader returns true if this cosigner won the election and its session has not ended since.
+func (l *EtcdLeader) IsLeader() bool {
+	if l == nil {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.isLeader
+}
+
+// LeaderTerm returns the revision of the election key of this cosigner, which is greater for later
+// campaigns, or 0 if it has not campaigned.
+func (l *EtcdLeader) LeaderTerm() uint64 {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.election == nil {
+		return 0
+	}
+	return uint64(l.election.Rev())
+}
+
+// GetLeader returns the shard ID of the leader observed, or -1 if there is none.
+func (l *EtcdLeader) GetLeader() int {
+	if l == nil {
+		return -1
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	id, err := strconv.Atoi(l.leader)
+	if err != nil {
+		return -1
+	}
+	return id
+}
+
+// ShareSigned does not share the last signed state, since there is no raft log to replicate it.
+// A new leader does not sign below the last signed state, because every cosigner refuses to sign
+// a share below its own last signed state, and any threshold cosigners include one that signed it.
+func (l *EtcdLeader) ShareSigned(_ ChainSignStateConsensus) error {
+	return nil
+}
+
+// TransferLeadership resigns, so that the cosigner that campaigned next becomes the leader.
+// The election does not allow choosing the next leader, so the shard ID is ignored.
+func (l *EtcdLeader) TransferLeadership(shardID string) (string, string) {
+	if !l.resign() {
+		return "", ""
+	}
+	if shardID != "" {
+		l.logger.Info("Etcd leader election cannot transfer to a chosen cosigner, resigned instead",
+			"prefix", l.prefix, "leader", shardID)
+	} else {
+		l.logger.Info("Resigned etcd leadership", "prefix", l.prefix)
+	}
+	// campaign again, behind the cosigners already campaigning.
+	select {
+	case l.resigned <- struct{}{}:
+	default:
+	}
+	return "", ""
+}
+
+// SetFeatureFlag fails, since feature flag changes are replicated with the raft log.
+func (l *EtcdLeader) SetFeatureFlag(_ FeatureFlagEvent) error {
+	return errors.New("feature flag changes require raft leader election")
+}
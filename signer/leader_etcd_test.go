@@ -0,0 +1,35 @@
+package signer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEtcdElectionConfig(t *testing.T) {
+	var cfg *EtcdElectionConfig
+	require.NoError(t, cfg.Validate())
+
+	cfg = &EtcdElectionConfig{}
+	require.EqualError(t, cfg.Validate(), "etcd leader election requires endpoints")
+
+	cfg.Endpoints = []string{"127.0.0.1:2379"}
+	require.NoError(t, cfg.Validate())
+	require.Equal(t, defaultEtcdElectionPrefix, cfg.prefix())
+
+	cfg.TTL = "500ms"
+	require.EqualError(t, cfg.Validate(), "etcd leader election ttl 500ms must be whole seconds")
+
+	cfg.TTL = "2s"
+	cfg.DialTimeout = "soon"
+	require.ErrorContains(t, cfg.Validate(), "invalid etcd leader election dialTimeout")
+
+	cfg.DialTimeout = "2s"
+	cfg.Prefix = "/horcrux/cosmoshub/leader/"
+	require.NoError(t, cfg.Validate())
+	require.Equal(t, "/horcrux/cosmoshub/leader", cfg.prefix())
+
+	require.EqualError(t, (&LeaderElectionConfig{Type: LeaderElectionEtcd}).Validate(),
+		"etcd leader election requires etcd config")
+	require.NoError(t, (&LeaderElectionConfig{Type: LeaderElectionEtcd, Etcd: cfg}).Validate())
+}
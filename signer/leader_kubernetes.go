@@ -0,0 +1,616 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/libs/service"
+	"github.com/hashicorp/raft"
+	"google.golang.org/grpc/health"
+)
+
+var _ ClusterLeader = (*KubernetesLeaseLeader)(nil)
+
+const (
+	defaultKubernetesLeaseName     = "horcrux"
+	defaultKubernetesLeaseDuration = 15 * time.Second
+	defaultKubernetesRenewDeadline = 10 * time.Second
+	defaultKubernetesRetryPeriod   = 2 * time.Second
+
+	kubernetesServiceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+	// kubernetesMicroTime is the format of the times of a Lease.
+	kubernetesMicroTime = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+// KubernetesLeaseConfig is the on disk config format for the leader election with a
+// coordination.k8s.io Lease. Inside a pod, the API server and the credentials of the
+// service account of the pod are used unless configured.
+type KubernetesLeaseConfig struct {
+	// Name of the Lease, which must be the same on every cosigner of the cluster.
+	Name      string `yaml:"name,omitempty"`
+	Namespace string `yaml:"namespace,omitempty"`
+
+	APIServer string `yaml:"apiServer,omitempty"`
+	TokenFile string `yaml:"tokenFile,omitempty"`
+	CAFile    string `yaml:"caFile,omitempty"`
+
+	// LeaseDuration is how long the other cosigners wait after the last renewal of the leader
+	// before they take over the Lease.
+	LeaseDuration string `yaml:"leaseDuration,omitempty"`
+
+	// RenewDeadline is how long the leader keeps signing without renewing the Lease.
+	RenewDeadline string `yaml:"renewDeadline,omitempty"`
+
+	// RetryPeriod is the interval of the attempts to acquire or renew the Lease.
+	RetryPeriod string `yaml:"retryPeriod,omitempty"`
+}
+
+func (cfg *KubernetesLeaseConfig) Validate() error {
+	if cfg == nil {
+		return nil
+	}
+	if cfg.APIServer != "" {
+		if _, err := url.Parse(cfg.APIServer); err != nil {
+			return fmt.Errorf("invalid kubernetes apiServer: %w", err)
+		}
+	}
+	leaseDuration, renewDeadline, retryPeriod, err := cfg.durations()
+	if err != nil {
+		return err
+	}
+	if leaseDuration%time.Second != 0 {
+		return fmt.Errorf("kubernetes leaseDuration %s must be whole seconds", leaseDuration)
+	}
+	if retryPeriod <= 0 || renewDeadline <= retryPeriod || leaseDuration <= renewDeadline {
+		return fmt.Errorf(
+			"kubernetes leaseDuration (%s) must be greater than renewDeadline (%s), "+
+				"which must be greater than retryPeriod (%s)",
+			leaseDuration, renewDeadline, retryPeriod,
+		)
+	}
+	return nil
+}
+
+func (cfg *KubernetesLeaseConfig) durations() (leaseDuration, renewDeadline, retryPeriod time.Duration, err error) {
+	if leaseDuration, err = parseDurationOrDefault(cfg.LeaseDuration, defaultKubernetesLeaseDuration); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid kubernetes leaseDuration: %w", err)
+	}
+	if renewDeadline, err = parseDurationOrDefault(cfg.RenewDeadline, defaultKubernetesRenewDeadline); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid kubernetes renewDeadline: %w", err)
+	}
+	if retryPeriod, err = parseDurationOrDefault(cfg.RetryPeriod, defaultKubernetesRetryPeriod); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid kubernetes retryPeriod: %w", err)
+	}
+	return leaseDuration, renewDeadline, retryPeriod, nil
+}
+
+func parseDurationOrDefault(s string, d time.Duration) (time.Duration, error) {
+	if s == "" {
+		return d, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// kubernetesLease is the part of a coordination.k8s.io/v1 Lease used for the leader election.
+// The metadata is sent back as it was received, so that updates are conditioned on its
+// resourceVersion.
+type kubernetesLease struct {
+	APIVersion string              `json:"apiVersion"`
+	Kind       string              `json:"kind"`
+	Metadata   json.RawMessage     `json:"metadata"`
+	Spec       kubernetesLeaseSpec `json:"spec"`
+}
+
+type kubernetesLeaseSpec struct {
+	HolderIdentity       string `json:"holderIdentity,omitempty"`
+	LeaseDurationSeconds int    `json:"leaseDurationSeconds,omitempty"`
+	AcquireTime          string `json:"acquireTime,omitempty"`
+	RenewTime            string `json:"renewTime,omitempty"`
+	LeaseTransitions     int    `json:"leaseTransitions,omitempty"`
+}
+
+type KubernetesLeaseConflictError struct {
+	msg string
+}
+
+func (e *KubernetesLeaseConflictError) Error() string { return e.msg }
+
+func newKubernetesLeaseConflictError(name string) *KubernetesLeaseConflictError {
+	return &KubernetesLeaseConflictError{
+		msg: fmt.Sprintf("lease %s was changed by another cosigner", name),
+	}
+}
+
+// kubernetesLeaseClient gets, creates and updates a Lease with the Kubernetes API.
+type kubernetesLeaseClient struct {
+	leasesURL string
+	name      string
+	namespace string
+	tokenFile string
+	client    *http.Client
+}
+
+func newKubernetesLeaseClient(cfg *KubernetesLeaseConfig) (*kubernetesLeaseClient, error) {
+	c := &kubernetesLeaseClient{
+		name:      cfg.Name,
+		namespace: cfg.Namespace,
+		tokenFile: cfg.TokenFile,
+		client:    &http.Client{},
+	}
+	if c.name == "" {
+		c.name = defaultKubernetesLeaseName
+	}
+
+	apiServer, caFile := cfg.APIServer, cfg.CAFile
+	if apiServer == "" {
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host == "" || port == "" {
+			return nil, fmt.Errorf("kubernetes apiServer is required outside of a kubernetes pod")
+		}
+		apiServer = "https://" + net.JoinHostPort(host, port)
+		if caFile == "" {
+			caFile = kubernetesServiceAccountDir + "/ca.crt"
+		}
+		if c.tokenFile == "" {
+			c.tokenFile = kubernetesServiceAccountDir + "/token"
+		}
+	}
+	if c.namespace == "" {
+		bz, err := os.ReadFile(kubernetesServiceAccountDir + "/namespace")
+		if err != nil {
+			return nil, fmt.Errorf("kubernetes namespace is required outside of a kubernetes pod: %w", err)
+		}
+		c.namespace = strings.TrimSpace(string(bz))
+	}
+	if caFile != "" {
+		bz, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read kubernetes caFile: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(bz) {
+			return nil, fmt.Errorf("no certificates in kubernetes caFile %s", caFile)
+		}
+		c.client.Transport = &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}}
+	}
+
+	c.leasesURL = fmt.Sprintf("%s/apis/coordination.k8s.io/v1/namespaces/%s/leases",
+		strings.TrimSuffix(apiServer, "/"), url.PathEscape(c.namespace))
+	return c, nil
+}
+
+// get returns the Lease, or nil if it does not exist.
+func (c *kubernetesLeaseClient) get(ctx context.Context) (*kubernetesLease, error) {
+	var lease kubernetesLease
+	status, err := c.do(ctx, http.MethodGet, c.leasesURL+"/"+url.PathEscape(c.name), nil, &lease)
+	if status == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &lease, nil
+}
+
+// create creates the Lease, failing if another cosigner created it first.
+func (c *kubernetesLeaseClient) create(ctx context.Context, spec kubernetesLeaseSpec) (*kubernetesLease, error) {
+	metadata, err := json.Marshal(map[string]string{"name": c.name, "namespace": c.namespace})
+	if err != nil {
+		return nil, err
+	}
+	lease := &kubernetesLease{
+		APIVersion: "coordination.k8s.io/v1",
+		Kind:       "Lease",
+		Metadata:   metadata,
+		Spec:       spec,
+	}
+	var created kubernetesLease
+	status, err := c.do(ctx, http.MethodPost, c.leasesURL, lease, &created)
+	if status == http.StatusConflict {
+		return nil, newKubernetesLeaseConflictError(c.name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// update replaces the Lease, failing if it changed since it was read.
+func (c *kubernetesLeaseClient) update(ctx context.Context, lease *kubernetesLease) (*kubernetesLease, error) {
+	var updated kubernetesLease
+	status, err := c.do(ctx, http.MethodPut, c.leasesURL+"/"+url.PathEscape(c.name), lease, &updated)
+	if status == http.StatusConflict {
+		return nil, newKubernetesLeaseConflictError(c.name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// do sends the request with the body as JSON and decodes the response into res. It returns
+// the status of the response, which is 0 if there is none.
+func (c *kubernetesLeaseClient) do(ctx context.Context, method, reqURL string, body, res any) (int, error) {
+	var reqBody io.Reader
+	if body != nil {
+		bz, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reqBody = bytes.NewReader(bz)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reqBody)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.tokenFile != "" {
+		// service account tokens are rotated, so the token is read for every request.
+		token, err := os.ReadFile(c.tokenFile)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read kubernetes token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return resp.StatusCode, fmt.Errorf("kubernetes api responded with %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return resp.StatusCode, json.NewDecoder(resp.Body).Decode(res)
+}
+
+// KubernetesLeaseLeader elects the leader of the cosigners with a coordination.k8s.io Lease,
+// whose holder identity is the shard ID of the leader. The cosigners do not run raft, so the
+// cosigner gRPC server is served without the raft transport.
+//
+// The leader stops signing when it could not renew the Lease within the renew deadline, and the
+// other cosigners take over the Lease when they have not seen it renewed for the lease duration,
+// measured with their own clocks.
+type KubernetesLeaseLeader struct {
+	service.BaseService
+
+	logger    cometlog.Logger
+	client    *kubernetesLeaseClient
+	id        string
+	cosigners []Cosigner
+
+	leaseDuration time.Duration
+	renewDeadline time.Duration
+	retryPeriod   time.Duration
+
+	listen             string
+	transport          CosignerTransport
+	cosigner           *LocalCosigner
+	thresholdValidator *ThresholdValidator
+	health             *health.Server
+
+	mu sync.Mutex
+	// observed is the spec of the Lease when it was last read or written, at observedTime.
+	observed     kubernetesLeaseSpec
+	observedTime time.Time
+	// renewed is when this cosigner last acquired or renewed the Lease.
+	renewed time.Time
+	// released is when this cosigner last gave up the Lease, which it does not take back
+	// for the lease duration.
+	released time.Time
+	tracker  *raftLeadershipTracker
+}
+
+// NewKubernetesLeaseLeader returns a KubernetesLeaseLeader of the local cosigner, which serves
+// the cosigner gRPC server at the P2P address with the transport.
+func NewKubernetesLeaseLeader(
+	logger cometlog.Logger,
+	cfg *KubernetesLeaseConfig,
+	cosigner *LocalCosigner,
+	cosigners []Cosigner,
+	listen string,
+	transport CosignerTransport,
+) (*KubernetesLeaseLeader, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	leaseDuration, renewDeadline, retryPeriod, _ := cfg.durations()
+	client, err := newKubernetesLeaseClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	id := strconv.Itoa(cosigner.GetID())
+	l := &KubernetesLeaseLeader{
+		logger:        logger,
+		client:        client,
+		id:            id,
+		cosigners:     cosigners,
+		leaseDuration: leaseDuration,
+		renewDeadline: renewDeadline,
+		retryPeriod:   retryPeriod,
+		listen:        listen,
+		transport:     transport,
+		cosigner:      cosigner,
+		health:        health.NewServer(),
+		tracker:       newRaftLeadershipTracker(raft.ServerID(id), time.Now()),
+	}
+	setLeaderHealth(l.health, false)
+	l.BaseService = *service.NewBaseService(logger, "KubernetesLeaseLeader", l)
+	return l, nil
+}
+
+func (l *KubernetesLeaseLeader) SetThresholdValidator(thresholdValidator *ThresholdValidator) {
+	l.thresholdValidator = thresholdValidator
+}
+
+// OnStart serves the cosigner gRPC server and starts the election.
+func (l *KubernetesLeaseLeader) OnStart() error {
+	l.logger.Info("Local cosigner listening", "address", p2pURLToRaftAddress(l.listen))
+	go func() {
+		defer ReportPanic()
+		err := serveCosignerGRPC(l.transport, l.listen, l.cosigner, l.thresholdValidator, l, l.health)
+		if err != nil {
+			panic(err)
+		}
+	}()
+	go l.elect()
+	return nil
+}
+
+func (l *KubernetesLeaseLeader) elect() {
+	ticker := time.NewTicker(l.retryPeriod)
+	defer ticker.Stop()
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), l.retryPeriod)
+		if err := l.tryAcquireOrRenew(ctx, time.Now()); err != nil {
+			l.logger.Error("Failed to acquire or renew lease", "lease", l.client.name, "error", err)
+		}
+		cancel()
+		l.observeLeader(time.Now())
+
+		select {
+		case <-l.Quit():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tryAcquireOrRenew renews the Lease if this cosigner holds it, or acquires it if it has no
+// holder or has not been renewed for the lease duration.
+func (l *KubernetesLeaseLeader) tryAcquireOrRenew(ctx context.Context, now time.Time) error {
+	lease, err := l.client.get(ctx)
+	if err != nil {
+		return err
+	}
+	if lease == nil {
+		created, err := l.client.create(ctx, l.acquiredSpec(kubernetesLeaseSpec{}, now))
+		if err != nil {
+			return err
+		}
+		l.setObserved(created.Spec, now, true)
+		return nil
+	}
+
+	l.setObserved(lease.Spec, now, false)
+	if lease.Spec.HolderIdentity != l.id {
+		if !l.canAcquire(now) {
+			return nil
+		}
+		lease.Spec = l.acquiredSpec(lease.Spec, now)
+	} else {
+		lease.Spec.RenewTime = now.UTC().Format(kubernetesMicroTime)
+		lease.Spec.LeaseDurationSeconds = int(l.leaseDuration / time.Second)
+	}
+
+	updated, err := l.client.update(ctx, lease)
+	if err != nil {
+		return err
+	}
+	l.setObserved(updated.Spec, now, true)
+	return nil
+}
+
+// acquiredSpec returns the spec of the Lease acquired by this cosigner at now.
+func (l *KubernetesLeaseLeader) acquiredSpec(spec kubernetesLeaseSpec, now time.Time) kubernetesLeaseSpec {
+	t := now.UTC().Format(kubernetesMicroTime)
+	if spec.HolderIdentity != "" {
+		spec.LeaseTransitions++
+	}
+	spec.HolderIdentity = l.id
+	spec.LeaseDurationSeconds = int(l.leaseDuration / time.Second)
+	spec.AcquireTime = t
+	spec.RenewTime = t
+	return spec
+}
+
+// setObserved records the spec of the Lease read or written at now. The observed time only moves
+// when the spec changes, so that a Lease not renewed by its holder expires.
+func (l *KubernetesLeaseLeader) setObserved(spec kubernetesLeaseSpec, now time.Time, written bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if written || spec != l.observed {
+		l.observed = spec
+		l.observedTime = now
+	}
+	if written && spec.HolderIdentity == l.id {
+		l.renewed = now
+	}
+}
+
+// canAcquire returns true if the Lease observed has no holder or has expired, and this cosigner
+// has not given it up within the lease duration.
+func (l *KubernetesLeaseLeader) canAcquire(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.released.IsZero() && now.Sub(l.released) < l.leaseDuration {
+		return false
+	}
+	return l.holder(now) == ""
+}
+
+// holder returns the holder of the Lease observed, or an empty string if the Lease has no holder
+// or has expired. It must be called with the lock held.
+func (l *KubernetesLeaseLeader) holder(now time.Time) string {
+	duration := l.leaseDuration
+	if l.observed.LeaseDurationSeconds > 0 {
+		duration = time.Duration(l.observed.LeaseDurationSeconds) * time.Second
+	}
+	if l.observed.HolderIdentity == "" || now.Sub(l.observedTime) >= duration {
+		return ""
+	}
+	return l.observed.HolderIdentity
+}
+
+// observeLeader updates the leader health service and the leadership metrics.
+func (l *KubernetesLeaseLeader) observeLeader(now time.Time) {
+	l.mu.Lock()
+	leader := l.holder(now)
+	if leader == l.id && now.Sub(l.renewed) >= l.renewDeadline {
+		leader = ""
+	}
+	changed := l.tracker.observe(raft.ServerID(leader), now)
+	l.mu.Unlock()
+
+	setLeaderHealth(l.health, leader == l.id)
+	if changed {
+		l.logger.Info("Lease leader changed", "lease", l.client.name, "leader", leader)
+	}
+}
+
+// IsLeader returns true if this cosigner holds the Lease and renewed it within the renew deadline.
+func (l *KubernetesLeaseLeader) IsLeader() bool {
+	if l == nil {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.observed.HolderIdentity == l.id && time.Since(l.renewed) < l.renewDeadline
+}
+
+// LeaderTerm returns the number of transitions of the Lease observed, which is greater for later holders.
+func (l *KubernetesLeaseLeader) LeaderTerm() uint64 {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return uint64(l.observed.LeaseTransitions)
+}
+
+// GetLeader returns the shard ID of the holder of the Lease, or -1 if it has no holder.
+func (l *KubernetesLeaseLeader) GetLeader() int {
+	if l == nil {
+		return -1
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	id, err := strconv.Atoi(l.holder(time.Now()))
+	if err != nil {
+		return -1
+	}
+	return id
+}
+
+// ShareSigned does not share the last signed state, since there is no raft log to replicate it.
+// A new leader does not sign below the last signed state, because every cosigner refuses to sign
+// a share below its own last signed state, and any threshold cosigners include one that signed it.
+func (l *KubernetesLeaseLeader) ShareSigned(_ ChainSignStateConsensus) error {
+	return nil
+}
+
+// SetPeers changes the peer cosigners that the Lease can be handed over to.
+func (l *KubernetesLeaseLeader) SetPeers(peers []Cosigner) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cosigners = peers
+	return nil
+}
+
+// TransferLeadership hands the Lease over to the cosigner with the shard ID, or gives it up for
+// the other cosigners to acquire if the shard ID is empty.
+func (l *KubernetesLeaseLeader) TransferLeadership(shardID string) (string, string) {
+	if !l.IsLeader() {
+		return "", ""
+	}
+	var address string
+	if shardID != "" {
+		l.mu.Lock()
+		cosigners := l.cosigners
+		l.mu.Unlock()
+		for _, c := range cosigners {
+			if strconv.Itoa(c.GetID()) == shardID {
+				address = c.GetAddress()
+			}
+		}
+		if address == "" {
+			// not a peer, so the Lease is given up instead.
+			shardID = ""
+		}
+	}
+
+	l.mu.Lock()
+	l.renewed = time.Time{}
+	l.released = time.Now()
+	l.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.renewDeadline)
+	defer cancel()
+	if err := l.release(ctx, shardID, time.Now()); err != nil {
+		l.logger.Error("Failed to transfer lease", "lease", l.client.name, "leader", shardID, "error", err)
+		return "", ""
+	}
+	l.logger.Info("Transferred lease", "lease", l.client.name, "leader", shardID)
+	return shardID, address
+}
+
+// release sets the holder of the Lease to the shard ID, which is empty to give it up.
+func (l *KubernetesLeaseLeader) release(ctx context.Context, shardID string, now time.Time) error {
+	lease, err := l.client.get(ctx)
+	if err != nil {
+		return err
+	}
+	if lease == nil || lease.Spec.HolderIdentity != l.id {
+		return fmt.Errorf("lease %s is not held by cosigner %s", l.client.name, l.id)
+	}
+	if shardID == "" {
+		lease.Spec.HolderIdentity = ""
+		lease.Spec.AcquireTime = ""
+		lease.Spec.RenewTime = ""
+	} else {
+		lease.Spec = l.acquiredSpec(lease.Spec, now)
+		lease.Spec.HolderIdentity = shardID
+	}
+	updated, err := l.client.update(ctx, lease)
+	if err != nil {
+		return err
+	}
+	l.setObserved(updated.Spec, now, true)
+	return nil
+}
+
+// SetFeatureFlag fails, since feature flag changes are replicated with the raft log.
+func (l *KubernetesLeaseLeader) SetFeatureFlag(_ FeatureFlagEvent) error {
+	return errors.New("feature flag changes require raft leader election")
+}
@@ -0,0 +1,210 @@
+package signer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/ethereum/go-ethereum/crypto/ecies"
+	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLeaseAPI serves a single Lease of the Kubernetes API, with the optimistic concurrency of
+// the resourceVersion.
+type fakeLeaseAPI struct {
+	t *testing.T
+
+	mu              sync.Mutex
+	lease           *kubernetesLease
+	resourceVersion int
+}
+
+type fakeLeaseMetadata struct {
+	Name            string `json:"name"`
+	Namespace       string `json:"namespace"`
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+}
+
+func (api *fakeLeaseAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer test-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	const leases = "/apis/coordination.k8s.io/v1/namespaces/horcrux/leases"
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == leases+"/horcrux":
+		if api.lease == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+	case r.Method == http.MethodPost && r.URL.Path == leases:
+		if api.lease != nil {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		api.write(r)
+	case r.Method == http.MethodPut && r.URL.Path == leases+"/horcrux":
+		var lease kubernetesLease
+		require.NoError(api.t, json.NewDecoder(r.Body).Decode(&lease))
+		var metadata fakeLeaseMetadata
+		require.NoError(api.t, json.Unmarshal(lease.Metadata, &metadata))
+		if api.lease == nil || metadata.ResourceVersion != strconv.Itoa(api.resourceVersion) {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		api.store(lease)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	require.NoError(api.t, json.NewEncoder(w).Encode(api.lease))
+}
+
+func (api *fakeLeaseAPI) write(r *http.Request) {
+	var lease kubernetesLease
+	require.NoError(api.t, json.NewDecoder(r.Body).Decode(&lease))
+	api.store(lease)
+}
+
+func (api *fakeLeaseAPI) store(lease kubernetesLease) {
+	var metadata fakeLeaseMetadata
+	require.NoError(api.t, json.Unmarshal(lease.Metadata, &metadata))
+	api.resourceVersion++
+	metadata.ResourceVersion = strconv.Itoa(api.resourceVersion)
+	bz, err := json.Marshal(metadata)
+	require.NoError(api.t, err)
+	lease.Metadata = bz
+	api.lease = &lease
+}
+
+func (api *fakeLeaseAPI) spec() kubernetesLeaseSpec {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	return api.lease.Spec
+}
+
+func newTestKubernetesLeaseLeader(t *testing.T, apiServer string, id int, peers ...Cosigner) *KubernetesLeaseLeader {
+	eciesKey, err := ecies.GenerateKey(rand.Reader, secp256k1.S256(), nil)
+	require.NoError(t, err)
+	cosigner := NewLocalCosigner(
+		cometlog.NewNopLogger(),
+		&RuntimeConfig{},
+		NewCosignerSecurityECIES(CosignerECIESKey{
+			ID:        id,
+			ECIESKey:  eciesKey,
+			ECIESPubs: []*ecies.PublicKey{&eciesKey.PublicKey},
+		}),
+		"",
+	)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("test-token\n"), 0600))
+
+	l, err := NewKubernetesLeaseLeader(cometlog.NewNopLogger(), &KubernetesLeaseConfig{
+		Namespace: "horcrux",
+		APIServer: apiServer,
+		TokenFile: tokenFile,
+	}, cosigner, peers, "tcp://127.0.0.1:0", TCPCosignerTransport{})
+	require.NoError(t, err)
+	return l
+}
+
+func TestKubernetesLeaseConfigValidate(t *testing.T) {
+	require.NoError(t, (&KubernetesLeaseConfig{}).Validate())
+	require.NoError(t, (&KubernetesLeaseConfig{LeaseDuration: "6s", RenewDeadline: "4s", RetryPeriod: "1s"}).Validate())
+	require.Error(t, (&KubernetesLeaseConfig{LeaseDuration: "1.5s"}).Validate())
+	require.Error(t, (&KubernetesLeaseConfig{LeaseDuration: "10s", RenewDeadline: "10s"}).Validate())
+	require.Error(t, (&KubernetesLeaseConfig{RenewDeadline: "1s", RetryPeriod: "2s"}).Validate())
+	require.Error(t, (&KubernetesLeaseConfig{RetryPeriod: "soon"}).Validate())
+
+	require.Error(t, (&LeaderElectionConfig{Type: LeaderElectionKubernetes}).Validate())
+	require.Error(t, (&LeaderElectionConfig{Type: "zookeeper"}).Validate())
+	require.NoError(t, (&LeaderElectionConfig{
+		Type:       LeaderElectionKubernetes,
+		Kubernetes: &KubernetesLeaseConfig{Name: "horcrux-cosmoshub"},
+	}).Validate())
+
+	thresholdCfg := &ThresholdModeConfig{}
+	require.Equal(t, LeaderElectionRaft, thresholdCfg.LeaderElectionType())
+	thresholdCfg.LeaderElection = &LeaderElectionConfig{Type: LeaderElectionKubernetes}
+	require.Equal(t, LeaderElectionKubernetes, thresholdCfg.LeaderElectionType())
+}
+
+func TestKubernetesLeaseLeader(t *testing.T) {
+	api := &fakeLeaseAPI{t: t}
+	srv := httptest.NewServer(api)
+	defer srv.Close()
+
+	peer1, err := NewRemoteCosigner(1, "tcp://cosigner-1:2222", TCPCosignerTransport{})
+	require.NoError(t, err)
+
+	l1 := newTestKubernetesLeaseLeader(t, srv.URL, 1)
+	l2 := newTestKubernetesLeaseLeader(t, srv.URL, 2, peer1)
+
+	ctx := context.Background()
+	now := time.Now()
+
+	// the first cosigner creates the lease.
+	require.NoError(t, l1.tryAcquireOrRenew(ctx, now))
+	require.True(t, l1.IsLeader())
+	require.Equal(t, "1", api.spec().HolderIdentity)
+
+	require.NoError(t, l2.tryAcquireOrRenew(ctx, now))
+	require.False(t, l2.IsLeader())
+	require.Equal(t, 1, l2.GetLeader())
+
+	// the lease is taken over once it was not renewed for the lease duration.
+	require.NoError(t, l2.tryAcquireOrRenew(ctx, now.Add(10*time.Second)))
+	require.False(t, l2.IsLeader())
+	expired := now.Add(defaultKubernetesLeaseDuration)
+	require.NoError(t, l2.tryAcquireOrRenew(ctx, expired))
+	require.True(t, l2.IsLeader())
+	require.Equal(t, "2", api.spec().HolderIdentity)
+	require.Equal(t, 1, api.spec().LeaseTransitions)
+
+	// the previous leader sees the new holder instead of renewing.
+	require.NoError(t, l1.tryAcquireOrRenew(ctx, expired))
+	require.False(t, l1.IsLeader())
+	require.Equal(t, 2, l1.GetLeader())
+
+	// updates of a lease that changed since it was read conflict.
+	stale, err := l1.client.get(ctx)
+	require.NoError(t, err)
+	require.NoError(t, l2.tryAcquireOrRenew(ctx, expired.Add(time.Second)))
+	_, err = l1.client.update(ctx, stale)
+	var conflictErr *KubernetesLeaseConflictError
+	require.ErrorAs(t, err, &conflictErr)
+
+	leaderID, leaderAddress := l2.TransferLeadership("1")
+	require.Equal(t, "1", leaderID)
+	require.Equal(t, "tcp://cosigner-1:2222", leaderAddress)
+	require.False(t, l2.IsLeader())
+	require.Equal(t, "1", api.spec().HolderIdentity)
+
+	require.NoError(t, l1.tryAcquireOrRenew(ctx, time.Now()))
+	require.True(t, l1.IsLeader())
+
+	// a lease given up is not taken back by the cosigner that gave it up.
+	leaderID, _ = l1.TransferLeadership("")
+	require.Empty(t, leaderID)
+	require.Empty(t, api.spec().HolderIdentity)
+	require.NoError(t, l1.tryAcquireOrRenew(ctx, time.Now()))
+	require.False(t, l1.IsLeader())
+	l3 := newTestKubernetesLeaseLeader(t, srv.URL, 3)
+	require.NoError(t, l3.tryAcquireOrRenew(ctx, time.Now()))
+	require.True(t, l3.IsLeader())
+}
@@ -0,0 +1,138 @@
+package signer
+
+import (
+	"strconv"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/libs/service"
+)
+
+const (
+	defaultPriorityTakeoverDelay = 30 * time.Second
+
+	// priorityCheckInterval is the interval of the checks for a cosigner with a higher priority.
+	priorityCheckInterval = 5 * time.Second
+)
+
+// LeaderPriority hands the leadership over to a cosigner with a higher priority than the leader,
+// so that the leadership settles on the preferred cosigners instead of the cosigner that won the
+// election after a restart. A cosigner is only preferred once it has been reachable for the
+// takeover delay, so that a cosigner that is restarting does not take the leadership back at once.
+type LeaderPriority struct {
+	service.BaseService
+
+	logger     cometlog.Logger
+	leader     ClusterLeader
+	health     *CosignerHealth
+	cosigners  []Cosigner
+	priorities map[int]int
+	myPriority int
+	delay      time.Duration
+
+	// reachableSince is when each peer with a higher priority than this cosigner became reachable.
+	reachableSince map[int]time.Time
+}
+
+// NewLeaderPriority returns the LeaderPriority of the threshold validator, or nil if all cosigners
+// have the same priority or there is no leader.
+func NewLeaderPriority(
+	logger cometlog.Logger,
+	cfg *ThresholdModeConfig,
+	leader ClusterLeader,
+	thresholdValidator *ThresholdValidator,
+) (*LeaderPriority, error) {
+	if cfg.LeaderElectionType() == LeaderElectionLeaderless {
+		return nil, nil
+	}
+	delay, err := cfg.LeaderElection.priorityTakeoverDelay()
+	if err != nil {
+		return nil, err
+	}
+
+	priorities := make(map[int]int, len(cfg.Cosigners))
+	same := true
+	for _, c := range cfg.Cosigners {
+		priorities[c.ShardID] = c.Priority
+		same = same && c.Priority == cfg.Cosigners[0].Priority
+	}
+	if same {
+		return nil, nil
+	}
+
+	p := &LeaderPriority{
+		logger:         logger,
+		leader:         leader,
+		health:         thresholdValidator.cosignerHealth,
+		cosigners:      thresholdValidator.Peers(),
+		priorities:     priorities,
+		myPriority:     priorities[thresholdValidator.myCosigner.GetID()],
+		delay:          delay,
+		reachableSince: make(map[int]time.Time),
+	}
+	p.BaseService = *service.NewBaseService(logger, "LeaderPriority", p)
+	return p, nil
+}
+
+func (p *LeaderPriority) OnStart() error {
+	go func() {
+		ticker := time.NewTicker(priorityCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.Quit():
+				return
+			case <-ticker.C:
+				p.check(time.Now())
+			}
+		}
+	}()
+	return nil
+}
+
+// check hands the leadership over to the peer with the highest priority above that of this
+// cosigner that has been reachable for the takeover delay, if this cosigner is the leader.
+func (p *LeaderPriority) check(now time.Time) {
+	if !p.leader.IsLeader() {
+		// the peers are only pinged by the leader.
+		clear(p.reachableSince)
+		return
+	}
+
+	var preferred Cosigner
+	for _, c := range p.cosigners {
+		id := c.GetID()
+		if p.priorities[id] <= p.myPriority {
+			continue
+		}
+		if rtt, ok := p.health.RTT(c); !ok || rtt < 0 {
+			delete(p.reachableSince, id)
+			continue
+		}
+		since, ok := p.reachableSince[id]
+		if !ok {
+			p.reachableSince[id] = now
+			continue
+		}
+		if now.Sub(since) < p.delay {
+			continue
+		}
+		if preferred == nil || p.priorities[id] > p.priorities[preferred.GetID()] {
+			preferred = c
+		}
+	}
+	if preferred == nil {
+		return
+	}
+
+	id := preferred.GetID()
+	p.logger.Info(
+		"Handing leadership over to cosigner with higher priority",
+		"cosigner", id,
+		"priority", p.priorities[id],
+		"my_priority", p.myPriority,
+	)
+	clear(p.reachableSince)
+	totalPriorityLeaderTransfers.Inc()
+	p.leader.TransferLeadership(strconv.Itoa(id))
+}
@@ -0,0 +1,94 @@
+package signer
+
+import (
+	"testing"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+)
+
+// transferRecordingLeader is a ClusterLeader that records the leadership transfers.
+type transferRecordingLeader struct {
+	isLeader  bool
+	transfers []string
+}
+
+func (l *transferRecordingLeader) IsLeader() bool                              { return l.isLeader }
+func (l *transferRecordingLeader) GetLeader() int                              { return 1 }
+func (l *transferRecordingLeader) ShareSigned(_ ChainSignStateConsensus) error { return nil }
+func (l *transferRecordingLeader) SetFeatureFlag(_ FeatureFlagEvent) error     { return nil }
+
+func (l *transferRecordingLeader) TransferLeadership(shardID string) (string, string) {
+	l.transfers = append(l.transfers, shardID)
+	return shardID, ""
+}
+
+func TestNewLeaderPriority(t *testing.T) {
+	cfg := &ThresholdModeConfig{Cosigners: CosignersConfig{
+		{ShardID: 1, P2PAddr: "tcp://cosigner-1:2222"},
+		{ShardID: 2, P2PAddr: "tcp://cosigner-2:2222"},
+	}}
+	p, err := NewLeaderPriority(cometlog.NewNopLogger(), cfg, &transferRecordingLeader{}, nil)
+	require.NoError(t, err)
+	require.Nil(t, p)
+
+	cfg.LeaderElection = &LeaderElectionConfig{PriorityTakeoverDelay: "soon"}
+	require.Error(t, cfg.LeaderElection.Validate())
+
+	require.Error(t, CosignersConfig{{ShardID: 1, P2PAddr: "tcp://cosigner-1:2222", Priority: -1}}.Validate())
+}
+
+func TestLeaderPriority(t *testing.T) {
+	peers := make([]Cosigner, 0, 2)
+	for _, id := range []int{2, 3} {
+		peer, err := NewRemoteCosigner(id, "tcp://cosigner:2222", TCPCosignerTransport{})
+		require.NoError(t, err)
+		peers = append(peers, peer)
+	}
+
+	leader := &transferRecordingLeader{isLeader: true}
+	health := NewCosignerHealth(cometlog.NewNopLogger(), 1, peers, leader)
+	p := &LeaderPriority{
+		logger:         cometlog.NewNopLogger(),
+		leader:         leader,
+		health:         health,
+		cosigners:      peers,
+		priorities:     map[int]int{1: 1, 2: 5, 3: 10},
+		myPriority:     1,
+		delay:          30 * time.Second,
+		reachableSince: make(map[int]time.Time),
+	}
+
+	now := time.Now()
+	health.rtt[2] = int64(time.Millisecond)
+	health.rtt[3] = -1
+
+	// a cosigner with a higher priority is only preferred once reachable for the delay.
+	p.check(now)
+	p.check(now.Add(10 * time.Second))
+	require.Empty(t, leader.transfers)
+
+	// the reachable cosigner with the highest priority is preferred.
+	health.rtt[3] = int64(time.Millisecond)
+	p.check(now.Add(20 * time.Second))
+	p.check(now.Add(30 * time.Second))
+	require.Equal(t, []string{"2"}, leader.transfers)
+
+	// followers do not hand over the leadership.
+	leader.isLeader = false
+	p.check(now.Add(time.Minute))
+	require.Empty(t, p.reachableSince)
+
+	leader.isLeader = true
+	p.check(now.Add(2 * time.Minute))
+	p.check(now.Add(3 * time.Minute))
+	require.Equal(t, []string{"2", "3"}, leader.transfers)
+
+	// a cosigner with the highest priority stays the leader.
+	p.myPriority = 10
+	leader.transfers = nil
+	p.check(now.Add(4 * time.Minute))
+	p.check(now.Add(5 * time.Minute))
+	require.Empty(t, leader.transfers)
+}
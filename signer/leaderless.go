@@ -0,0 +1,232 @@
+package signer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/libs/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/status"
+)
+
+var _ ClusterLeader = (*LeaderlessCoordinator)(nil)
+
+// Coordinator chooses the cosigner that coordinates the signing of each block, so that the
+// cosigners need no leader.
+type Coordinator interface {
+	// Coordinators returns the shard IDs of all cosigners in the order in which they coordinate
+	// the signing of the round of the chain. The first reachable cosigner coordinates.
+	Coordinators(chainID string, height int64, round int64) []int
+}
+
+// LeaderlessCoordinator lets every cosigner coordinate the signing of blocks, with the coordinator
+// of each round of a chain derived from the hash of the chain ID, height and round. Every cosigner
+// derives the same order of coordinators, so a sign request is proxied to the same cosigner by
+// all cosigners without an election, and a coordinator that is down is skipped at once instead of
+// waiting for a new leader to be elected.
+//
+// Every cosigner is a leader for the nonce cache and the health checks of the peers, since any of
+// them may coordinate the next round.
+type LeaderlessCoordinator struct {
+	service.BaseService
+
+	logger cometlog.Logger
+	id     int
+	ids    []int
+	idsMu  sync.RWMutex
+
+	listen             string
+	transport          CosignerTransport
+	cosigner           *LocalCosigner
+	thresholdValidator *ThresholdValidator
+	health             *health.Server
+}
+
+// NewLeaderlessCoordinator returns the LeaderlessCoordinator of the local cosigner, which serves
+// the cosigner gRPC server at the P2P address with the transport.
+func NewLeaderlessCoordinator(
+	logger cometlog.Logger,
+	cosigner *LocalCosigner,
+	cosigners []Cosigner,
+	listen string,
+	transport CosignerTransport,
+) *LeaderlessCoordinator {
+	ids := []int{cosigner.GetID()}
+	for _, c := range cosigners {
+		ids = append(ids, c.GetID())
+	}
+	c := &LeaderlessCoordinator{
+		logger:    logger,
+		id:        cosigner.GetID(),
+		ids:       ids,
+		listen:    listen,
+		transport: transport,
+		cosigner:  cosigner,
+		health:    health.NewServer(),
+	}
+	// every cosigner coordinates.
+	setLeaderHealth(c.health, true)
+	c.BaseService = *service.NewBaseService(logger, "LeaderlessCoordinator", c)
+	return c
+}
+
+func (c *LeaderlessCoordinator) SetThresholdValidator(thresholdValidator *ThresholdValidator) {
+	c.thresholdValidator = thresholdValidator
+}
+
+// OnStart serves the cosigner gRPC server.
+func (c *LeaderlessCoordinator) OnStart() error {
+	c.logger.Info("Local cosigner listening", "address", p2pURLToRaftAddress(c.listen))
+	go func() {
+		defer ReportPanic()
+		err := serveCosignerGRPC(c.transport, c.listen, c.cosigner, c.thresholdValidator, c, c.health)
+		if err != nil {
+			panic(err)
+		}
+	}()
+	return nil
+}
+
+func (c *LeaderlessCoordinator) Coordinators(chainID string, height int64, round int64) []int {
+	c.idsMu.RLock()
+	defer c.idsMu.RUnlock()
+	return coordinatorOrder(chainID, height, round, c.ids)
+}
+
+// SetPeers changes the peer cosigners that coordinate with this cosigner. The coordinators of the
+// rounds change with the cosigners, so the peers should be changed on every cosigner at once.
+func (c *LeaderlessCoordinator) SetPeers(peers []Cosigner) error {
+	ids := []int{c.id}
+	for _, p := range peers {
+		ids = append(ids, p.GetID())
+	}
+	c.idsMu.Lock()
+	defer c.idsMu.Unlock()
+	c.ids = ids
+	return nil
+}
+
+// coordinatorOrder orders the shard IDs by their score for the round of the chain, the highest
+// first. Each shard ID is scored separately, so that when a coordinator is down, its rounds are
+// spread over the other cosigners instead of all falling to the same cosigner.
+func coordinatorOrder(chainID string, height int64, round int64, ids []int) []int {
+	scores := make(map[int]uint64, len(ids))
+	for _, id := range ids {
+		h := sha256.New()
+		_, _ = fmt.Fprintf(h, "%s/%d/%d/%d", chainID, height, round, id)
+		scores[id] = binary.BigEndian.Uint64(h.Sum(nil))
+	}
+	order := slices.Clone(ids)
+	sort.Slice(order, func(i, j int) bool {
+		if scores[order[i]] != scores[order[j]] {
+			return scores[order[i]] > scores[order[j]]
+		}
+		return order[i] < order[j]
+	})
+	return order
+}
+
+// IsLeader returns true, since every cosigner may coordinate.
+func (c *LeaderlessCoordinator) IsLeader() bool {
+	return true
+}
+
+// GetLeader returns the shard ID of this cosigner, since every cosigner may coordinate.
+func (c *LeaderlessCoordinator) GetLeader() int {
+	return c.id
+}
+
+// ShareSigned does not share the last signed state, since every coordinator keeps its own.
+// A coordinator does not sign below the last signed state of another coordinator, because every
+// cosigner refuses to sign a share below its own last signed state, and any threshold cosigners
+// include one that signed it.
+func (c *LeaderlessCoordinator) ShareSigned(_ ChainSignStateConsensus) error {
+	return nil
+}
+
+// TransferLeadership does nothing, since there is no leader.
+func (c *LeaderlessCoordinator) TransferLeadership(_ string) (string, string) {
+	return "", ""
+}
+
+// SetFeatureFlag fails, since feature flag changes are replicated with the raft log.
+func (c *LeaderlessCoordinator) SetFeatureFlag(_ FeatureFlagEvent) error {
+	return errors.New("feature flag changes require raft leader election")
+}
+
+type proxiedSignRequestKey struct{}
+
+// withProxiedSignRequest returns a context for a sign request proxied by another cosigner.
+func withProxiedSignRequest(ctx context.Context) context.Context {
+	return context.WithValue(ctx, proxiedSignRequestKey{}, true)
+}
+
+func isProxiedSignRequest(ctx context.Context) bool {
+	proxied, _ := ctx.Value(proxiedSignRequestKey{}).(bool)
+	return proxied
+}
+
+// proxyToCoordinator proxies the sign request to the first coordinator of the round that is
+// reachable, or returns false if this cosigner is that coordinator. A coordinator is skipped if the
+// last ping of it failed or the proxied request could not reach it. Other errors are returned, since
+// the coordinator may have started signing.
+func (pv *ThresholdValidator) proxyToCoordinator(
+	ctx context.Context,
+	coordinator Coordinator,
+	chainID string,
+	block Block,
+) (bool, []byte, time.Time, error) {
+	if isProxiedSignRequest(ctx) {
+		// the cosigner that proxied the request already chose this cosigner.
+		return false, nil, time.Time{}, nil
+	}
+
+	for _, id := range coordinator.Coordinators(chainID, block.Height, block.Round) {
+		if id == pv.myCosigner.GetID() {
+			return false, nil, time.Time{}, nil
+		}
+		peer := pv.Peers().GetByID(id)
+		if peer == nil {
+			continue
+		}
+		if rtt, ok := pv.cosignerHealth.RTT(peer); ok && rtt < 0 {
+			continue
+		}
+
+		pv.logger.Debug("Proxying request to the coordinator",
+			"chain_id", chainID,
+			"height", block.Height,
+			"round", block.Round,
+			"step", block.Step,
+			"coordinator", id,
+		)
+		totalNotRaftLeader.Inc()
+
+		sig, err := pv.proxySign(ctx, peer, chainID, block)
+		if status.Code(err) == codes.Unavailable {
+			pv.logger.Error("Coordinator unavailable, proxying request to the next coordinator",
+				"chain_id", chainID,
+				"height", block.Height,
+				"round", block.Round,
+				"step", block.Step,
+				"coordinator", id,
+				"error", err,
+			)
+			pv.cosignerHealth.MarkUnhealthy(peer)
+			continue
+		}
+		return true, sig, block.Timestamp, err
+	}
+
+	// unreachable, this cosigner is one of the coordinators.
+	return false, nil, time.Time{}, nil
+}
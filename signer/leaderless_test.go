@@ -0,0 +1,78 @@
+package signer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoordinatorOrder(t *testing.T) {
+	ids := []int{1, 2, 3}
+
+	first := make(map[int]int)
+	for round := int64(0); round < 300; round++ {
+		order := coordinatorOrder(testChainID, 100, round, ids)
+		require.ElementsMatch(t, ids, order)
+		// the order does not depend on the order of the shard IDs.
+		require.Equal(t, order, coordinatorOrder(testChainID, 100, round, []int{3, 1, 2}))
+		first[order[0]]++
+	}
+
+	// the rounds are spread over the cosigners.
+	for _, id := range ids {
+		require.Greater(t, first[id], 50)
+	}
+}
+
+// fixedCoordinator coordinates every round with the cosigners in the same order.
+type fixedCoordinator []int
+
+func (c fixedCoordinator) Coordinators(string, int64, int64) []int { return c }
+
+func TestProxyToCoordinator(t *testing.T) {
+	peer, err := NewRemoteCosigner(2, "tcp://cosigner-2:2222", TCPCosignerTransport{})
+	require.NoError(t, err)
+	// nothing listens on the port, so requests fail as unavailable.
+	unavailablePeer, err := NewRemoteCosigner(3, "tcp://127.0.0.1:1", TCPCosignerTransport{})
+	require.NoError(t, err)
+	peers := []Cosigner{peer, unavailablePeer}
+
+	leader := &MockLeader{id: 1}
+	pv := &ThresholdValidator{
+		logger:         cometlog.NewNopLogger(),
+		myCosigner:     testRecoveryPeer(t, 1, nil),
+		peerCosigners:  peers,
+		cosignerHealth: NewCosignerHealth(cometlog.NewNopLogger(), 1, peers, leader),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	block := Block{Height: 100, Round: 0, Step: stepPrevote}
+
+	// this cosigner is the first coordinator.
+	proxied, _, _, err := pv.proxyToCoordinator(ctx, fixedCoordinator{1, 2}, testChainID, block)
+	require.NoError(t, err)
+	require.False(t, proxied)
+
+	// a request proxied by another cosigner is coordinated here.
+	proxied, _, _, err = pv.proxyToCoordinator(withProxiedSignRequest(ctx), fixedCoordinator{2, 1}, testChainID, block)
+	require.NoError(t, err)
+	require.False(t, proxied)
+
+	// an unreachable coordinator is skipped.
+	pv.cosignerHealth.MarkUnhealthy(peer)
+	proxied, _, _, err = pv.proxyToCoordinator(ctx, fixedCoordinator{2, 1}, testChainID, block)
+	require.NoError(t, err)
+	require.False(t, proxied)
+
+	// a coordinator that cannot be reached by the request is skipped and marked unhealthy.
+	proxied, _, _, err = pv.proxyToCoordinator(ctx, fixedCoordinator{3, 1, 2}, testChainID, block)
+	require.NoError(t, err)
+	require.False(t, proxied)
+	rtt, ok := pv.cosignerHealth.RTT(unavailablePeer)
+	require.True(t, ok)
+	require.Negative(t, rtt)
+}
@@ -27,6 +27,7 @@ type LocalCosigner struct {
 	logger        cometlog.Logger
 	config        *RuntimeConfig
 	security      CosignerSecurity
+	securityMu    sync.RWMutex
 	chainState    sync.Map
 	address       string
 	pendingDiskWG sync.WaitGroup
@@ -34,6 +35,9 @@ type LocalCosigner struct {
 	nonces map[uuid.UUID]*NoncesWithExpiration
 	// protects the nonces map
 	noncesMu sync.RWMutex
+
+	// signStateRecovery recovers missing or corrupt sign states from peers, if set.
+	signStateRecovery *SignStateRecovery
 }
 
 func NewLocalCosigner(
@@ -51,6 +55,29 @@ func NewLocalCosigner(
 	}
 }
 
+// SetSignStateRecovery sets the recovery used to initialize missing or corrupt sign states from peers.
+func (cosigner *LocalCosigner) SetSignStateRecovery(recovery *SignStateRecovery) {
+	cosigner.signStateRecovery = recovery
+}
+
+// SetSecurity replaces the security layer of the cosigner, e.g. with the public keys of added peers.
+// The security layer must be of the same cosigner.
+func (cosigner *LocalCosigner) SetSecurity(security CosignerSecurity) error {
+	if security.GetID() != cosigner.GetID() {
+		return fmt.Errorf("cosigner key is of shard %d, not %d", security.GetID(), cosigner.GetID())
+	}
+	cosigner.securityMu.Lock()
+	defer cosigner.securityMu.Unlock()
+	cosigner.security = security
+	return nil
+}
+
+func (cosigner *LocalCosigner) getSecurity() CosignerSecurity {
+	cosigner.securityMu.RLock()
+	defer cosigner.securityMu.RUnlock()
+	return cosigner.security
+}
+
 type ChainState struct {
 	// lastSignState stores the last sign state for an HRS we have fully signed
 	// incremented whenever we are asked to sign an HRS
@@ -84,14 +111,18 @@ func (cosigner *LocalCosigner) pruneNonces() {
 	}
 }
 
-func (cosigner *LocalCosigner) combinedNonces(myID int, threshold uint8, uuid uuid.UUID) ([]Nonce, error) {
-	cosigner.noncesMu.RLock()
-	defer cosigner.noncesMu.RUnlock()
+// takeNonces removes the nonces of the UUID and returns the nonces to sign with. The nonces are
+// removed before the sign, so that two sign requests with the same UUID, e.g. from an old and a
+// new leader, can never both sign with them.
+func (cosigner *LocalCosigner) takeNonces(myID int, threshold uint8, uuid uuid.UUID) ([]Nonce, error) {
+	cosigner.noncesMu.Lock()
+	defer cosigner.noncesMu.Unlock()
 
 	nonces, ok := cosigner.nonces[uuid]
 	if !ok {
 		return nil, errors.New("no metadata at HRS")
 	}
+	delete(cosigner.nonces, uuid)
 
 	combinedNonces := make([]Nonce, 0, threshold)
 
@@ -112,8 +143,8 @@ func (cosigner *LocalCosigner) combinedNonces(myID int, threshold uint8, uuid uu
 
 // Save updates the high watermark height/round/step (HRS) if it is greater
 // than the current high watermark. A mutex is used to avoid concurrent state updates.
-// The disk write is scheduled in a separate goroutine which will perform an atomic write.
-// pendingDiskWG is used upon termination in pendingDiskWG to ensure all writes have completed.
+// The state is persisted before the function returns. pendingDiskWG is used upon termination
+// to ensure all writes in flight have completed.
 func (cosigner *LocalCosigner) SaveLastSignedState(chainID string, signState SignStateConsensus) error {
 	ccs, err := cosigner.getChainState(chainID)
 	if err != nil {
@@ -135,7 +166,7 @@ func (cosigner *LocalCosigner) waitForSignStatesToFlushToDisk() {
 // GetID returns the id of the cosigner
 // Implements Cosigner interface
 func (cosigner *LocalCosigner) GetID() int {
-	return cosigner.security.GetID()
+	return cosigner.getSecurity().GetID()
 }
 
 // GetAddress returns the RPC URL of the cosigner
@@ -214,15 +245,24 @@ func (cosigner *LocalCosigner) sign(req CosignerSignRequest) (CosignerSignRespon
 		return res, err
 	}
 
+	if isMessageSignBytes(req.SignBytes) {
+		return cosigner.signMessage(ccs, req)
+	}
+
 	// This function has multiple exit points.  Only start time can be guaranteed
 	metricsTimeKeeper.SetPreviousLocalSignStart(time.Now())
 
-	hrst, err := UnpackHRST(req.SignBytes)
+	codec, err := cosigner.config.SignBytesCodec(chainID)
+	if err != nil {
+		return res, err
+	}
+
+	hrst, err := codec.UnpackHRST(req.SignBytes)
 	if err != nil {
 		return res, err
 	}
 
-	existingSignature, err := ccs.lastSignState.existingSignatureOrErrorIfRegression(hrst, req.SignBytes)
+	existingSignature, err := ccs.lastSignState.existingSignatureOrErrorIfRegression(codec, hrst, req.SignBytes)
 	if err != nil {
 		return res, err
 	}
@@ -232,7 +272,7 @@ func (cosigner *LocalCosigner) sign(req CosignerSignRequest) (CosignerSignRespon
 		return res, nil
 	}
 
-	nonces, err := cosigner.combinedNonces(
+	nonces, err := cosigner.takeNonces(
 		cosigner.GetID(),
 		uint8(cosigner.config.Config.ThresholdModeConfig.Threshold),
 		req.UUID,
@@ -260,10 +300,6 @@ func (cosigner *LocalCosigner) sign(req CosignerSignRequest) (CosignerSignRespon
 		}
 	}
 
-	cosigner.noncesMu.Lock()
-	delete(cosigner.nonces, req.UUID)
-	cosigner.noncesMu.Unlock()
-
 	res.Signature = sig
 
 	// Note - Function may return before this line so elapsed time for Finish may be multiple block times
@@ -298,7 +334,8 @@ func (cosigner *LocalCosigner) LoadSignStateIfNecessary(chainID string) error {
 		return nil
 	}
 
-	signState, err := LoadOrCreateSignState(cosigner.config.CosignerStateFile(chainID))
+	signState, err := cosigner.config.loadSignState(
+		cosigner.logger, chainID, SignStateKindCosigner, cosigner.signStateRecovery)
 	if err != nil {
 		return err
 	}
@@ -318,6 +355,16 @@ func (cosigner *LocalCosigner) LoadSignStateIfNecessary(chainID string) error {
 	return nil
 }
 
+// GetSignState returns the HRS of the last signature share for the chain,
+// or nil if there is no sign state for the chain.
+func (cosigner *LocalCosigner) GetSignState(_ context.Context, chainID string) (*HRSKey, error) {
+	if cs, ok := cosigner.chainState.Load(chainID); ok {
+		hrs := cs.(*ChainState).lastSignState.HRSKey()
+		return &hrs, nil
+	}
+	return cosigner.config.peekSignState(chainID, SignStateKindCosigner)
+}
+
 // GetNonces returns the nonces for the given UUIDs, generating if necessary.
 func (cosigner *LocalCosigner) GetNonces(
 	_ context.Context,
@@ -427,7 +474,7 @@ func (cosigner *LocalCosigner) getNonce(
 	id := cosigner.GetID()
 
 	ourCosignerMeta := meta.Nonces[id-1]
-	nonce, err := cosigner.security.EncryptAndSign(peerID, ourCosignerMeta.PubKey, ourCosignerMeta.Shares[peerID-1])
+	nonce, err := cosigner.getSecurity().EncryptAndSign(peerID, ourCosignerMeta.PubKey, ourCosignerMeta.Shares[peerID-1])
 	if err != nil {
 		return zero, err
 	}
@@ -444,7 +491,7 @@ func (cosigner *LocalCosigner) setNonce(uuid uuid.UUID, nonce CosignerNonce) err
 		return errors.New("signature field is required")
 	}
 
-	noncePub, nonceShare, err := cosigner.security.DecryptAndVerify(
+	noncePub, nonceShare, err := cosigner.getSecurity().DecryptAndVerify(
 		nonce.SourceID, nonce.PubKey, nonce.Share, nonce.Signature)
 	if err != nil {
 		return err
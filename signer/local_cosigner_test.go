@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -27,6 +29,38 @@ const (
 	bitSize      = 4096
 )
 
+func TestLocalCosignerTakeNonces(t *testing.T) {
+	cosigner := NewLocalCosigner(log.NewNopLogger(), &RuntimeConfig{}, nil, "")
+
+	nonces, err := GenerateNonces(2, 3)
+	require.NoError(t, err)
+	u, err := uuid.NewRandom()
+	require.NoError(t, err)
+	cosigner.nonces[u] = &NoncesWithExpiration{
+		Nonces:     []Nonces{nonces},
+		Expiration: time.Now().Add(nonceExpiration),
+	}
+
+	// of the concurrent sign requests with the same UUID, only one is given the nonces.
+	const requests = 8
+	var taken atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if nonces, err := cosigner.takeNonces(1, 2, u); err == nil {
+				require.Len(t, nonces, 1)
+				taken.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(1), taken.Load())
+	require.NotContains(t, cosigner.nonces, u)
+}
+
 func TestLocalCosignerSignRSA2of3(t *testing.T) {
 	testLocalCosignerSignRSA(t, 2, 3)
 }
@@ -0,0 +1,224 @@
+package signer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+)
+
+type LogLevel string
+
+const (
+	LogLevelDebug LogLevel = "debug"
+	LogLevelInfo  LogLevel = "info"
+	LogLevelError LogLevel = "error"
+	LogLevelNone  LogLevel = "none"
+)
+
+// Log modules that can be given their own log level.
+const (
+	LogModuleNonceCache     = "nonce_cache"
+	LogModuleCosignerHealth = "cosigner_health"
+	LogModuleRaft           = "raft"
+	LogModuleLeaderElection = "leader_election"
+	LogModuleRemoteSigner   = "remote_signer"
+	LogModuleWatermark      = "watermark"
+	LogModuleSignLock       = "sign_lock"
+)
+
+// severity orders the log levels, a message is logged if its severity is at least that of the level.
+func (l LogLevel) severity() (int, error) {
+	switch l {
+	case LogLevelDebug:
+		return 0, nil
+	case LogLevelInfo:
+		return 1, nil
+	case LogLevelError:
+		return 2, nil
+	case LogLevelNone:
+		return 3, nil
+	default:
+		return 0, fmt.Errorf(
+			"invalid log level %q, must be one of %s, %s, %s or %s",
+			l, LogLevelDebug, LogLevelInfo, LogLevelError, LogLevelNone,
+		)
+	}
+}
+
+func (l LogLevel) Validate() error {
+	if l == "" {
+		return nil
+	}
+	_, err := l.severity()
+	return err
+}
+
+// logLevelsState is an immutable snapshot of the log levels.
+type logLevelsState struct {
+	level   LogLevel
+	modules map[string]LogLevel
+}
+
+// LogLevels holds the log level, and the log levels of modules that differ from it,
+// of the loggers returned by Filter. The levels can be changed at runtime.
+type LogLevels struct {
+	// mu serializes updates, loggers read the current state without locking.
+	mu    sync.Mutex
+	state atomic.Pointer[logLevelsState]
+}
+
+// NewLogLevels returns LogLevels that allow all messages.
+func NewLogLevels() *LogLevels {
+	l := new(LogLevels)
+	l.state.Store(&logLevelsState{level: LogLevelDebug})
+	return l
+}
+
+// SetLevel sets the log level of all modules without a level of their own.
+// An empty level resets it to debug.
+func (l *LogLevels) SetLevel(level LogLevel) error {
+	if level == "" {
+		level = LogLevelDebug
+	}
+	if err := level.Validate(); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	prev := l.state.Load()
+	l.state.Store(&logLevelsState{level: level, modules: prev.modules})
+	return nil
+}
+
+// SetModuleLevel sets the log level of a module, e.g. debug for only the nonce cache.
+// An empty level removes the level of the module, so that it logs at the log level.
+func (l *LogLevels) SetModuleLevel(module string, level LogLevel) error {
+	if err := level.Validate(); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	prev := l.state.Load()
+	modules := make(map[string]LogLevel, len(prev.modules)+1)
+	for m, lvl := range prev.modules {
+		modules[m] = lvl
+	}
+	if level == "" {
+		delete(modules, module)
+	} else {
+		modules[module] = level
+	}
+	l.state.Store(&logLevelsState{level: prev.level, modules: modules})
+	return nil
+}
+
+// Level returns the log level and the log levels of modules.
+func (l *LogLevels) Level() (LogLevel, map[string]LogLevel) {
+	state := l.state.Load()
+	modules := make(map[string]LogLevel, len(state.modules))
+	for m, lvl := range state.modules {
+		modules[m] = lvl
+	}
+	return state.level, modules
+}
+
+func (l *LogLevels) allow(module string, level LogLevel) bool {
+	state := l.state.Load()
+	minLevel, ok := state.modules[module]
+	if !ok {
+		minLevel = state.level
+	}
+	// levels are validated when they are set.
+	minSeverity, _ := minLevel.severity()
+	severity, _ := level.severity()
+	return severity >= minSeverity
+}
+
+// Filter returns a logger that passes messages to logger if they are allowed by the current
+// log levels. The module of a message is the "module" key of the logger's With key values.
+func (l *LogLevels) Filter(logger cometlog.Logger) cometlog.Logger {
+	return &levelFilterLogger{next: logger, levels: l}
+}
+
+// ServeHTTP serves the log levels as JSON. A POST request with the level query parameter changes
+// the log level, or the log level of a module if the module query parameter is set. An empty
+// level removes the log level of the module.
+func (l *LogLevels) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		query := r.URL.Query()
+		level := LogLevel(query.Get("level"))
+		var err error
+		if module := query.Get("module"); module != "" {
+			err = l.SetModuleLevel(module, level)
+		} else if level == "" {
+			err = fmt.Errorf("level is required")
+		} else {
+			err = l.SetLevel(level)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	level, modules := l.Level()
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(struct {
+		Level   LogLevel            `json:"level"`
+		Modules map[string]LogLevel `json:"modules"`
+	}{
+		Level:   level,
+		Modules: modules,
+	})
+}
+
+// levelFilterLogger is a cometlog.Logger that drops messages below the log level of its module.
+type levelFilterLogger struct {
+	next   cometlog.Logger
+	levels *LogLevels
+	module string
+}
+
+func (l *levelFilterLogger) Debug(msg string, keyvals ...interface{}) {
+	if l.levels.allow(l.module, LogLevelDebug) {
+		l.next.Debug(msg, keyvals...)
+	}
+}
+
+func (l *levelFilterLogger) Info(msg string, keyvals ...interface{}) {
+	if l.levels.allow(l.module, LogLevelInfo) {
+		l.next.Info(msg, keyvals...)
+	}
+}
+
+func (l *levelFilterLogger) Error(msg string, keyvals ...interface{}) {
+	if l.levels.allow(l.module, LogLevelError) {
+		l.next.Error(msg, keyvals...)
+	}
+}
+
+func (l *levelFilterLogger) With(keyvals ...interface{}) cometlog.Logger {
+	module := l.module
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if key, ok := keyvals[i].(string); ok && key == "module" {
+			if m, ok := keyvals[i+1].(string); ok {
+				module = m
+			}
+		}
+	}
+	return &levelFilterLogger{
+		next:   l.next.With(keyvals...),
+		levels: l.levels,
+		module: module,
+	}
+}
@@ -0,0 +1,110 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogLevelsFilter(t *testing.T) {
+	levels := NewLogLevels()
+
+	var out bytes.Buffer
+	logger := levels.Filter(cometlog.NewTMLogger(&out))
+	nonceCacheLogger := logger.With("module", LogModuleNonceCache)
+
+	logged := func(l cometlog.Logger, log func(l cometlog.Logger)) bool {
+		out.Reset()
+		log(l)
+		return out.Len() > 0
+	}
+	debug := func(l cometlog.Logger) { l.Debug("debug") }
+	info := func(l cometlog.Logger) { l.Info("info") }
+	logError := func(l cometlog.Logger) { l.Error("error") }
+
+	// all messages are logged by default.
+	require.True(t, logged(logger, debug))
+	require.True(t, logged(nonceCacheLogger, debug))
+
+	require.NoError(t, levels.SetLevel(LogLevelInfo))
+	require.False(t, logged(logger, debug))
+	require.True(t, logged(logger, info))
+	require.False(t, logged(nonceCacheLogger, debug))
+
+	// debug logs of only the nonce cache.
+	require.NoError(t, levels.SetModuleLevel(LogModuleNonceCache, LogLevelDebug))
+	require.False(t, logged(logger, debug))
+	require.True(t, logged(nonceCacheLogger, debug))
+	require.True(t, logged(nonceCacheLogger.With("chain_id", "horcrux-1"), debug))
+
+	require.NoError(t, levels.SetLevel(LogLevelError))
+	require.False(t, logged(logger, info))
+	require.True(t, logged(logger, logError))
+	require.True(t, logged(nonceCacheLogger, debug))
+
+	require.NoError(t, levels.SetModuleLevel(LogModuleNonceCache, ""))
+	require.False(t, logged(nonceCacheLogger, debug))
+
+	require.NoError(t, levels.SetLevel(LogLevelNone))
+	require.False(t, logged(logger, logError))
+
+	require.Error(t, levels.SetLevel("trace"))
+	require.Error(t, levels.SetModuleLevel(LogModuleRaft, "trace"))
+}
+
+func TestLogLevelsServeHTTP(t *testing.T) {
+	levels := NewLogLevels()
+
+	do := func(method, query string) (int, string) {
+		req := httptest.NewRequest(method, "/debug/log_level?"+query, nil)
+		rec := httptest.NewRecorder()
+		levels.ServeHTTP(rec, req)
+		return rec.Code, rec.Body.String()
+	}
+
+	type response struct {
+		Level   LogLevel            `json:"level"`
+		Modules map[string]LogLevel `json:"modules"`
+	}
+	decode := func(body string) response {
+		var res response
+		require.NoError(t, json.NewDecoder(strings.NewReader(body)).Decode(&res))
+		return res
+	}
+
+	code, body := do(http.MethodGet, "")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, LogLevelDebug, decode(body).Level)
+
+	code, body = do(http.MethodPost, "level=info")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, LogLevelInfo, decode(body).Level)
+
+	code, body = do(http.MethodPost, "module=nonce_cache&level=debug")
+	require.Equal(t, http.StatusOK, code)
+	res := decode(body)
+	require.Equal(t, LogLevelInfo, res.Level)
+	require.Equal(t, map[string]LogLevel{LogModuleNonceCache: LogLevelDebug}, res.Modules)
+
+	code, body = do(http.MethodPost, "module=nonce_cache")
+	require.Equal(t, http.StatusOK, code)
+	require.Empty(t, decode(body).Modules)
+
+	code, _ = do(http.MethodPost, "level=trace")
+	require.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = do(http.MethodPost, "")
+	require.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = do(http.MethodDelete, "")
+	require.Equal(t, http.StatusMethodNotAllowed, code)
+
+	level, _ := levels.Level()
+	require.Equal(t, LogLevelInfo, level)
+}
@@ -0,0 +1,369 @@
+package signer
+
+import (
+	"fmt"
+	"io"
+	"log/syslog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+)
+
+const (
+	defaultLogFileMaxSizeMB  = 100
+	defaultLogFileMaxBackups = 10
+	defaultSyslogTag         = "horcrux"
+	defaultSyslogFacility    = "daemon"
+
+	// logFileBackupTimeFormat is the format of the time a rotated log file is suffixed with, which
+	// sorts in time order.
+	logFileBackupTimeFormat = "2006-01-02T15-04-05.000"
+)
+
+// LogFileConfig configures writing the logs of the signer to a file, which is rotated by size and
+// by age.
+type LogFileConfig struct {
+	// Path of the log file.
+	Path string `yaml:"path"`
+
+	// MaxSizeMB is the size in megabytes above which the log file is rotated, 100 by default.
+	MaxSizeMB int `yaml:"maxSizeMB,omitempty"`
+
+	// MaxAge is the age above which the log file is rotated, e.g. 24h, counted from when the signer
+	// opened it. Without it, the log file is only rotated by size.
+	MaxAge string `yaml:"maxAge,omitempty"`
+
+	// MaxBackups is the number of rotated log files kept, 10 by default. The oldest are removed.
+	MaxBackups int `yaml:"maxBackups,omitempty"`
+}
+
+func (cfg *LogFileConfig) Validate() error {
+	if cfg == nil {
+		return nil
+	}
+	if cfg.Path == "" {
+		return fmt.Errorf("logFile.path is required")
+	}
+	if cfg.MaxSizeMB < 0 {
+		return fmt.Errorf("logFile.maxSizeMB cannot be negative")
+	}
+	if cfg.MaxBackups < 0 {
+		return fmt.Errorf("logFile.maxBackups cannot be negative")
+	}
+	if _, err := cfg.maxAge(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cfg *LogFileConfig) maxSize() int64 {
+	if cfg.MaxSizeMB == 0 {
+		return defaultLogFileMaxSizeMB * 1024 * 1024
+	}
+	return int64(cfg.MaxSizeMB) * 1024 * 1024
+}
+
+func (cfg *LogFileConfig) maxAge() (time.Duration, error) {
+	if cfg.MaxAge == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(cfg.MaxAge)
+	if err != nil {
+		return 0, fmt.Errorf("invalid logFile.maxAge: %w", err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("logFile.maxAge must be positive")
+	}
+	return d, nil
+}
+
+func (cfg *LogFileConfig) maxBackups() int {
+	if cfg.MaxBackups == 0 {
+		return defaultLogFileMaxBackups
+	}
+	return cfg.MaxBackups
+}
+
+// RotatingFile is a log file that is rotated once it reaches its maximum size or age: it is renamed
+// with the time of the rotation as suffix, and a new file is opened in its place.
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	opened time.Time
+	now    func() time.Time
+}
+
+// NewRotatingFile opens the log file of the config, appending to it if it exists.
+func NewRotatingFile(cfg *LogFileConfig) (*RotatingFile, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	maxAge, _ := cfg.maxAge()
+	f := &RotatingFile{
+		path:       cfg.Path,
+		maxSize:    cfg.maxSize(),
+		maxAge:     maxAge,
+		maxBackups: cfg.maxBackups(),
+		now:        time.Now,
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0700); err != nil {
+		return nil, err
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file = file
+	f.size = stat.Size()
+	f.opened = f.now()
+	return nil
+}
+
+// Write writes p to the log file, rotating it first if p would exceed its maximum size or if it
+// reached its maximum age.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	full := f.size > 0 && f.size+int64(len(p)) > f.maxSize
+	old := f.maxAge > 0 && f.now().Sub(f.opened) >= f.maxAge
+	if full || old {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// rotate renames the log file to a backup, opens a new log file and removes the oldest backups.
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+	backup := f.path + "." + f.now().UTC().Format(logFileBackupTimeFormat)
+	if err := os.Rename(f.path, backup); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+	return f.removeOldBackups()
+}
+
+func (f *RotatingFile) removeOldBackups() error {
+	backups, err := filepath.Glob(f.path + ".*")
+	if err != nil {
+		return err
+	}
+	sort.Strings(backups)
+	for len(backups) > f.maxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			return fmt.Errorf("failed to remove rotated log file: %w", err)
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// SyslogConfig configures sending the logs of the signer to syslog.
+type SyslogConfig struct {
+	// Network and Address of the syslog server, e.g. udp and logs.example.com:514. Both are empty
+	// to send the logs to the local syslog.
+	Network string `yaml:"network,omitempty"`
+	Address string `yaml:"address,omitempty"`
+
+	// Tag of the log messages, horcrux by default.
+	Tag string `yaml:"tag,omitempty"`
+
+	// Facility of the log messages, daemon by default.
+	Facility string `yaml:"facility,omitempty"`
+}
+
+// syslogFacilities are the syslog facilities by name.
+var syslogFacilities = map[string]syslog.Priority{
+	"kern":     syslog.LOG_KERN,
+	"user":     syslog.LOG_USER,
+	"daemon":   syslog.LOG_DAEMON,
+	"auth":     syslog.LOG_AUTH,
+	"syslog":   syslog.LOG_SYSLOG,
+	"authpriv": syslog.LOG_AUTHPRIV,
+	"local0":   syslog.LOG_LOCAL0,
+	"local1":   syslog.LOG_LOCAL1,
+	"local2":   syslog.LOG_LOCAL2,
+	"local3":   syslog.LOG_LOCAL3,
+	"local4":   syslog.LOG_LOCAL4,
+	"local5":   syslog.LOG_LOCAL5,
+	"local6":   syslog.LOG_LOCAL6,
+	"local7":   syslog.LOG_LOCAL7,
+}
+
+func (cfg *SyslogConfig) Validate() error {
+	if cfg == nil {
+		return nil
+	}
+	if (cfg.Network == "") != (cfg.Address == "") {
+		return fmt.Errorf("syslog.network and syslog.address must be set together")
+	}
+	switch cfg.Network {
+	case "", "udp", "tcp", "unix", "unixgram":
+	default:
+		return fmt.Errorf("invalid syslog.network %q, must be udp, tcp, unix or unixgram", cfg.Network)
+	}
+	if _, err := cfg.facility(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cfg *SyslogConfig) facility() (syslog.Priority, error) {
+	if cfg.Facility == "" {
+		return syslogFacilities[defaultSyslogFacility], nil
+	}
+	facility, ok := syslogFacilities[cfg.Facility]
+	if !ok {
+		names := make([]string, 0, len(syslogFacilities))
+		for name := range syslogFacilities {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return 0, fmt.Errorf("invalid syslog.facility %q, must be one of %s", cfg.Facility, strings.Join(names, ", "))
+	}
+	return facility, nil
+}
+
+func (cfg *SyslogConfig) tag() string {
+	if cfg.Tag == "" {
+		return defaultSyslogTag
+	}
+	return cfg.Tag
+}
+
+// syslogWriter writes each log line to syslog with a severity.
+type syslogWriter struct {
+	w     *syslog.Writer
+	write func(w *syslog.Writer, m string) error
+}
+
+func (s syslogWriter) Write(p []byte) (int, error) {
+	if err := s.write(s.w, strings.TrimRight(string(p), "\n")); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// syslogLogger logs the messages of each level to syslog with the severity of the level.
+type syslogLogger struct {
+	debug, info, error cometlog.Logger
+}
+
+// NewSyslogLogger connects to the syslog of the config, and returns a logger that sends the log
+// messages to it in the log format, with the severity of their level.
+func NewSyslogLogger(
+	cfg *SyslogConfig,
+	newLogger func(io.Writer) cometlog.Logger,
+) (cometlog.Logger, io.Closer, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
+	}
+	facility, _ := cfg.facility()
+	w, err := syslog.Dial(cfg.Network, cfg.Address, facility|syslog.LOG_INFO, cfg.tag())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to syslog: %w", err)
+	}
+	return &syslogLogger{
+		debug: newLogger(syslogWriter{w: w, write: (*syslog.Writer).Debug}),
+		info:  newLogger(syslogWriter{w: w, write: (*syslog.Writer).Info}),
+		error: newLogger(syslogWriter{w: w, write: (*syslog.Writer).Err}),
+	}, w, nil
+}
+
+func (l *syslogLogger) Debug(msg string, keyvals ...any) { l.debug.Debug(msg, keyvals...) }
+
+func (l *syslogLogger) Info(msg string, keyvals ...any) { l.info.Info(msg, keyvals...) }
+
+func (l *syslogLogger) Error(msg string, keyvals ...any) { l.error.Error(msg, keyvals...) }
+
+func (l *syslogLogger) With(keyvals ...any) cometlog.Logger {
+	return &syslogLogger{
+		debug: l.debug.With(keyvals...),
+		info:  l.info.With(keyvals...),
+		error: l.error.With(keyvals...),
+	}
+}
+
+// teeLogger logs each message to all of its loggers.
+type teeLogger []cometlog.Logger
+
+// NewTeeLogger returns a logger that logs each message to all of the loggers.
+func NewTeeLogger(loggers ...cometlog.Logger) cometlog.Logger {
+	if len(loggers) == 1 {
+		return loggers[0]
+	}
+	return teeLogger(loggers)
+}
+
+func (t teeLogger) Debug(msg string, keyvals ...any) {
+	for _, l := range t {
+		l.Debug(msg, keyvals...)
+	}
+}
+
+func (t teeLogger) Info(msg string, keyvals ...any) {
+	for _, l := range t {
+		l.Info(msg, keyvals...)
+	}
+}
+
+func (t teeLogger) Error(msg string, keyvals ...any) {
+	for _, l := range t {
+		l.Error(msg, keyvals...)
+	}
+}
+
+func (t teeLogger) With(keyvals ...any) cometlog.Logger {
+	out := make(teeLogger, len(t))
+	for i, l := range t {
+		out[i] = l.With(keyvals...)
+	}
+	return out
+}
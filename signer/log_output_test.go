@@ -0,0 +1,128 @@
+package signer
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogFileConfigValidate(t *testing.T) {
+	require.NoError(t, (*LogFileConfig)(nil).Validate())
+	require.NoError(t, (&LogFileConfig{Path: "horcrux.log", MaxSizeMB: 10, MaxAge: "24h", MaxBackups: 3}).Validate())
+	require.EqualError(t, (&LogFileConfig{}).Validate(), "logFile.path is required")
+	require.EqualError(t, (&LogFileConfig{Path: "horcrux.log", MaxSizeMB: -1}).Validate(),
+		"logFile.maxSizeMB cannot be negative")
+	require.EqualError(t, (&LogFileConfig{Path: "horcrux.log", MaxAge: "0s"}).Validate(),
+		"logFile.maxAge must be positive")
+}
+
+func TestRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logs", "horcrux.log")
+
+	f, err := NewRotatingFile(&LogFileConfig{Path: path, MaxAge: "1h", MaxBackups: 2})
+	require.NoError(t, err)
+	defer f.Close()
+
+	now := time.Date(2023, 10, 18, 12, 0, 0, 0, time.UTC)
+	f.now = func() time.Time { return now }
+	f.opened = now
+	f.maxSize = 12
+
+	write := func(s string) {
+		_, err := f.Write([]byte(s))
+		require.NoError(t, err)
+	}
+	backups := func() []string {
+		files, err := filepath.Glob(path + ".*")
+		require.NoError(t, err)
+		return files
+	}
+
+	// a write is not split, the file is rotated before the write that would exceed its size.
+	write("12345\n")
+	write("1234\n")
+	require.Empty(t, backups())
+	write("123\n")
+	require.Equal(t, []string{path + ".2023-10-18T12-00-00.000"}, backups())
+
+	bz, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "123\n", string(bz))
+
+	// the file is rotated once it is older than maxAge.
+	now = now.Add(time.Hour)
+	write("a\n")
+	require.Len(t, backups(), 2)
+
+	// only maxBackups rotated files are kept.
+	now = now.Add(time.Hour)
+	write("b\n")
+	require.Equal(t, []string{path + ".2023-10-18T13-00-00.000", path + ".2023-10-18T14-00-00.000"}, backups())
+
+	bz, err = os.ReadFile(path + ".2023-10-18T14-00-00.000")
+	require.NoError(t, err)
+	require.Equal(t, "a\n", string(bz))
+
+	require.NoError(t, f.Close())
+	_, err = f.Write([]byte("c\n"))
+	require.ErrorIs(t, err, os.ErrClosed)
+}
+
+func TestSyslogConfigValidate(t *testing.T) {
+	require.NoError(t, (*SyslogConfig)(nil).Validate())
+	require.NoError(t, (&SyslogConfig{}).Validate())
+	require.NoError(t, (&SyslogConfig{Network: "udp", Address: "localhost:514", Facility: "local0"}).Validate())
+	require.EqualError(t, (&SyslogConfig{Network: "udp"}).Validate(),
+		"syslog.network and syslog.address must be set together")
+	require.EqualError(t, (&SyslogConfig{Network: "http", Address: "localhost:514"}).Validate(),
+		`invalid syslog.network "http", must be udp, tcp, unix or unixgram`)
+	require.ErrorContains(t, (&SyslogConfig{Facility: "mail"}).Validate(), `invalid syslog.facility "mail"`)
+}
+
+func TestSyslogLogger(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	var fileOut strings.Builder
+	syslogLogger, closer, err := NewSyslogLogger(
+		&SyslogConfig{Network: "udp", Address: conn.LocalAddr().String(), Tag: "horcrux-test", Facility: "local0"},
+		func(w io.Writer) cometlog.Logger { return cometlog.NewTMJSONLogger(w) },
+	)
+	require.NoError(t, err)
+	defer closer.Close()
+
+	logger := NewTeeLogger(cometlog.NewTMJSONLogger(&fileOut), syslogLogger).With("module", "test")
+
+	read := func() string {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		buf := make([]byte, 4096)
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		return string(buf[:n])
+	}
+
+	// the priority is the facility local0 (16) * 8 + the severity of the level.
+	logger.Info("Signed", "height", 10)
+	msg := read()
+	require.True(t, strings.HasPrefix(msg, "<134>"), msg)
+	require.Contains(t, msg, "horcrux-test")
+	require.Contains(t, msg, `"_msg":"Signed"`)
+	require.Contains(t, msg, `"module":"test"`)
+
+	logger.Error("Failed to sign")
+	require.True(t, strings.HasPrefix(read(), "<131>"))
+
+	logger.Debug("Nonces")
+	require.True(t, strings.HasPrefix(read(), "<135>"))
+
+	require.Equal(t, 3, strings.Count(fileOut.String(), `"module":"test"`))
+}
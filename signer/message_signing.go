@@ -0,0 +1,271 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// messageSignBytesPrefix starts the sign bytes of every message, so that a message signature is
+// never valid for anything else. The sign bytes of a vote or proposal of the CometBFT encoding start
+// with their length as a varint, which is never 0, so a message signature cannot be a valid vote or
+// proposal signature. The sign bytes of another codec may start with anything, so the messages of a
+// chain with another codec are refused.
+const messageSignBytesPrefix = "\x00horcrux/message/v1\x00"
+
+// messageTypePattern is the format of the message types, e.g. ics-key-assignment.
+var messageTypePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._/-]*$`)
+
+type MessageSigningError struct {
+	msg string
+}
+
+func (e *MessageSigningError) Error() string { return e.msg }
+
+func newMessageSigningError(chainID, msgType string) *MessageSigningError {
+	return &MessageSigningError{
+		msg: fmt.Sprintf(
+			"refusing to sign message of type %q for chain %s: the type is not in the messageSigning types",
+			msgType, chainID,
+		),
+	}
+}
+
+func newMessageSigningCodecError(chainID, codec string) *MessageSigningError {
+	return &MessageSigningError{
+		msg: fmt.Sprintf(
+			"refusing to sign messages for chain %s: its sign bytes codec %q may not be separated from messages",
+			chainID, codec,
+		),
+	}
+}
+
+// messageSigningCodecError returns a *MessageSigningError if the chain has a sign bytes codec other
+// than the default, whose sign bytes are not known to never start with the message prefix.
+func (c RuntimeConfig) messageSigningCodecError(chainID string) error {
+	_, id := SplitValidatorChainID(chainID)
+	if codec, ok := c.Config.SignBytesCodecs[id]; ok && codec != DefaultSignBytesCodec {
+		return newMessageSigningCodecError(chainID, codec)
+	}
+	return nil
+}
+
+// MessageSigningConfig allows the cosigners to threshold sign arbitrary payloads of the message
+// types, e.g. the proof of possession of a consumer chain key, without reconstructing the key. Every
+// cosigner must list a type to sign its messages.
+type MessageSigningConfig struct {
+	// Types are the message types that are signed. A type not listed is refused.
+	Types []string `yaml:"types"`
+}
+
+func (cfg *MessageSigningConfig) Validate() error {
+	if cfg == nil {
+		return nil
+	}
+	if len(cfg.Types) == 0 {
+		return fmt.Errorf("messageSigning requires types")
+	}
+	for i, t := range cfg.Types {
+		if !messageTypePattern.MatchString(t) {
+			return fmt.Errorf("invalid messageSigning type %q, must be lowercase letters, digits, ., _, / and -", t)
+		}
+		if slices.Contains(cfg.Types[:i], t) {
+			return fmt.Errorf("duplicate messageSigning type %q", t)
+		}
+	}
+	return nil
+}
+
+// Allows returns true if the messages of the type are signed.
+func (cfg *MessageSigningConfig) Allows(msgType string) bool {
+	return cfg != nil && slices.Contains(cfg.Types, msgType)
+}
+
+// MessageSignBytes returns the bytes signed for the payload of the message type for the chain: the
+// message prefix followed by the chain ID, the type and the payload, each prefixed by its length as
+// a varint.
+func MessageSignBytes(chainID, msgType string, payload []byte) []byte {
+	b := []byte(messageSignBytesPrefix)
+	for _, field := range [][]byte{[]byte(chainID), []byte(msgType), payload} {
+		b = binary.AppendUvarint(b, uint64(len(field)))
+		b = append(b, field...)
+	}
+	return b
+}
+
+// isMessageSignBytes returns true if the sign bytes are of a message rather than a vote or proposal.
+func isMessageSignBytes(signBytes []byte) bool {
+	return bytes.HasPrefix(signBytes, []byte(messageSignBytesPrefix))
+}
+
+// parseMessageSignBytes returns the chain ID, the type and the payload of the sign bytes of a message.
+func parseMessageSignBytes(signBytes []byte) (chainID, msgType string, payload []byte, err error) {
+	b, ok := bytes.CutPrefix(signBytes, []byte(messageSignBytesPrefix))
+	if !ok {
+		return "", "", nil, errors.New("sign bytes are not of a message")
+	}
+	fields := make([][]byte, 3)
+	for i := range fields {
+		n, read := binary.Uvarint(b)
+		if read <= 0 || n > uint64(len(b)-read) {
+			return "", "", nil, errors.New("malformed message sign bytes")
+		}
+		b = b[read:]
+		fields[i], b = b[:n], b[n:]
+	}
+	if len(b) > 0 {
+		return "", "", nil, errors.New("malformed message sign bytes")
+	}
+	return string(fields[0]), string(fields[1]), fields[2], nil
+}
+
+// signMessage signs the sign bytes of a message with the cosigner's shard, if its type is in the
+// messageSigning types of this cosigner. A message is not a consensus message, so the sign state is
+// neither checked nor saved.
+func (cosigner *LocalCosigner) signMessage(ccs *ChainState, req CosignerSignRequest) (CosignerSignResponse, error) {
+	res := CosignerSignResponse{}
+
+	chainID, msgType, _, err := parseMessageSignBytes(req.SignBytes)
+	if err != nil {
+		return res, err
+	}
+	if chainID != req.ChainID {
+		return res, fmt.Errorf("message of chain %s cannot be signed with the key of chain %s", chainID, req.ChainID)
+	}
+	if !cosigner.config.Config.MessageSigning.Allows(msgType) {
+		totalMessageSigningRefusals.WithLabelValues(chainID, msgType).Inc()
+		return res, newMessageSigningError(chainID, msgType)
+	}
+	if err := cosigner.config.messageSigningCodecError(chainID); err != nil {
+		totalMessageSigningRefusals.WithLabelValues(chainID, msgType).Inc()
+		return res, err
+	}
+
+	nonces, err := cosigner.takeNonces(
+		cosigner.GetID(),
+		uint8(cosigner.config.Config.ThresholdModeConfig.Threshold),
+		req.UUID,
+	)
+	if err != nil {
+		return res, err
+	}
+
+	sig, err := ccs.signer.Sign(nonces, req.SignBytes)
+	if err != nil {
+		return res, err
+	}
+
+	res.Signature = sig
+	return res, nil
+}
+
+// MessageSignature is a threshold signature of a message, over its sign bytes.
+type MessageSignature struct {
+	ChainID   string `json:"chain_id"`
+	Type      string `json:"type"`
+	SignBytes []byte `json:"sign_bytes"`
+	Signature []byte `json:"signature"`
+	PubKey    []byte `json:"pub_key"`
+}
+
+// SignMessage threshold signs the payload of the message type for the chain with the cosigners,
+// which each refuse a type that is not in their messageSigning types. This cosigner must be the
+// leader. Like the sign requests of the chain nodes, the message is refused while the kill switch of
+// this cosigner is engaged or signing is paused, and the cosigners refuse to sign with their key
+// shards while their kill switch is engaged or signing of the cluster is paused.
+func (pv *ThresholdValidator) SignMessage(
+	ctx context.Context,
+	chainID, msgType string,
+	payload []byte,
+) (*MessageSignature, error) {
+	if !pv.config.Config.MessageSigning.Allows(msgType) {
+		totalMessageSigningRefusals.WithLabelValues(chainID, msgType).Inc()
+		return nil, newMessageSigningError(chainID, msgType)
+	}
+	if err := pv.config.messageSigningCodecError(chainID); err != nil {
+		totalMessageSigningRefusals.WithLabelValues(chainID, msgType).Inc()
+		return nil, err
+	}
+	if err := pv.killSwitch().EngagedError(); err != nil {
+		totalKillSwitchRefusals.WithLabelValues(chainID).Inc()
+		return nil, err
+	}
+	if err := pv.signingPause().PausedError(); err != nil {
+		totalSigningPausedRefused.WithLabelValues(chainID).Inc()
+		return nil, err
+	}
+	if !pv.leader.IsLeader() {
+		return nil, fmt.Errorf("cosigner %d is not the leader, sign the message on the leader %d",
+			pv.myCosigner.GetID(), pv.leader.GetLeader())
+	}
+	if err := pv.LoadSignStateIfNecessary(chainID); err != nil {
+		return nil, err
+	}
+
+	signBytes := MessageSignBytes(chainID, msgType, payload)
+
+	nonces, cosigners, err := pv.nonceCache.WaitBestNonces(
+		ctx, pv.myCosigner, pv.cosignerHealth.GetFastest(), pv.config.Config.Chains.nonceExpiration(chainID))
+	if err != nil {
+		var fallbackErr error
+		nonces, cosigners, fallbackErr = pv.getNoncesFallback(ctx, chainID)
+		if fallbackErr != nil {
+			return nil, fmt.Errorf("failed to get nonces: %w", errors.Join(err, fallbackErr))
+		}
+	}
+
+	shareSigs := make([]PartialSignature, len(cosigners))
+	var eg errgroup.Group
+	for i, cosigner := range cosigners {
+		i, cosigner := i, cosigner
+		eg.Go(func() error {
+			signCtx, cancel := context.WithTimeout(ctx, pv.chainGRPCTimeout(chainID))
+			defer cancel()
+			res, err := cosigner.SetNoncesAndSign(signCtx, CosignerSetNoncesAndSignRequest{
+				ChainID:   chainID,
+				Nonces:    nonces.For(cosigner.GetID()),
+				SignBytes: signBytes,
+			})
+			if err != nil {
+				return fmt.Errorf("cosigner %d: %w", cosigner.GetID(), err)
+			}
+			shareSigs[i] = PartialSignature{ID: cosigner.GetID(), Signature: res.Signature}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, fmt.Errorf("error from cosigner(s): %w", err)
+	}
+	if len(shareSigs) < pv.threshold {
+		return nil, errors.New("not enough co-signers")
+	}
+
+	signature, err := pv.myCosigner.CombineSignatures(chainID, shareSigs)
+	if err != nil {
+		return nil, fmt.Errorf("error combining signatures: %w", err)
+	}
+	if !pv.myCosigner.VerifySignature(chainID, signBytes, signature) {
+		totalInvalidSignature.Inc()
+		return nil, errors.New("combined signature is not valid")
+	}
+	pubKey, err := pv.myCosigner.GetPubKey(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	totalMessagesSigned.WithLabelValues(chainID, msgType).Inc()
+	pv.logger.Info("Signed message", "chain_id", chainID, "type", msgType, "payload_size", len(payload))
+	return &MessageSignature{
+		ChainID:   chainID,
+		Type:      msgType,
+		SignBytes: signBytes,
+		Signature: signature,
+		PubKey:    pubKey.Bytes(),
+	}, nil
+}
@@ -0,0 +1,119 @@
+package signer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	cometproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageSigningConfigValidate(t *testing.T) {
+	require.NoError(t, (*MessageSigningConfig)(nil).Validate())
+	require.NoError(t, (&MessageSigningConfig{Types: []string{"proof-of-possession", "ics/key-assignment"}}).Validate())
+	require.Error(t, (&MessageSigningConfig{}).Validate())
+	require.Error(t, (&MessageSigningConfig{Types: []string{"Proof Of Possession"}}).Validate())
+	require.Error(t, (&MessageSigningConfig{Types: []string{"proof-of-possession", "proof-of-possession"}}).Validate())
+
+	require.False(t, (*MessageSigningConfig)(nil).Allows("proof-of-possession"))
+	require.True(t, (&MessageSigningConfig{Types: []string{"proof-of-possession"}}).Allows("proof-of-possession"))
+}
+
+func TestMessageSignBytes(t *testing.T) {
+	signBytes := MessageSignBytes("cosmoshub-4", "proof-of-possession", []byte("hello"))
+	require.True(t, isMessageSignBytes(signBytes))
+
+	chainID, msgType, payload, err := parseMessageSignBytes(signBytes)
+	require.NoError(t, err)
+	require.Equal(t, "cosmoshub-4", chainID)
+	require.Equal(t, "proof-of-possession", msgType)
+	require.Equal(t, []byte("hello"), payload)
+
+	// the chain ID and type are separated from the payload.
+	require.NotEqual(t, signBytes, MessageSignBytes("cosmoshub-4", "proof-of-possession/hello", nil))
+	require.NotEqual(t, signBytes, MessageSignBytes("cosmoshub", "-4proof-of-possession", []byte("hello")))
+
+	// the sign bytes of a message are never those of a vote or proposal, which start with their
+	// length.
+	require.Zero(t, signBytes[0])
+	vote := VoteToBlock("cosmoshub-4", &cometproto.Vote{Height: 1, Type: cometproto.PrecommitType}).SignBytes
+	require.NotZero(t, vote[0])
+	require.False(t, isMessageSignBytes(vote))
+
+	_, _, _, err = parseMessageSignBytes(signBytes[:len(signBytes)-1])
+	require.Error(t, err)
+	_, _, _, err = parseMessageSignBytes(append(signBytes, 0))
+	require.Error(t, err)
+}
+
+func TestThresholdValidatorSignMessage(t *testing.T) {
+	cosigners, pubKey := getTestLocalCosigners(t, 2, 3)
+	for _, c := range cosigners {
+		c.config.Config.MessageSigning = &MessageSigningConfig{Types: []string{"proof-of-possession"}}
+	}
+
+	leader := &MockLeader{id: 1}
+	validator := NewThresholdValidator(
+		cometlog.NewNopLogger(),
+		cosigners[0].config,
+		2,
+		time.Second,
+		1,
+		cosigners[0],
+		[]Cosigner{cosigners[1]},
+		leader,
+	)
+	defer validator.Stop()
+	leader.leader = validator
+
+	ctx := context.Background()
+	sig, err := validator.SignMessage(ctx, testChainID, "proof-of-possession", []byte("hello"))
+	require.NoError(t, err)
+	require.Equal(t, MessageSignBytes(testChainID, "proof-of-possession", []byte("hello")), sig.SignBytes)
+	require.Equal(t, pubKey.Bytes(), sig.PubKey)
+	require.True(t, pubKey.VerifySignature(sig.SignBytes, sig.Signature))
+
+	// a message leaves the sign state alone.
+	proposal := cometproto.Proposal{Height: 1, Round: 0, Type: cometproto.ProposalType}
+	block := ProposalToBlock(testChainID, &proposal)
+	signature, _, err := validator.Sign(ctx, testChainID, block)
+	require.NoError(t, err)
+	require.True(t, pubKey.VerifySignature(block.SignBytes, signature))
+
+	_, err = validator.SignMessage(ctx, testChainID, "ics-key-assignment", []byte("hello"))
+	require.IsType(t, &MessageSigningError{}, err)
+
+	// every cosigner must opt in to the type.
+	cosigners[0].config.Config.MessageSigning.Types = append(
+		cosigners[0].config.Config.MessageSigning.Types, "ics-key-assignment")
+	_, err = validator.SignMessage(ctx, testChainID, "ics-key-assignment", []byte("hello"))
+	require.ErrorContains(t, err, "not in the messageSigning types")
+
+	// messages are refused while signing is paused or the kill switch is engaged.
+	pause := NewPauseValidator(validator)
+	validator.SetSigningPause(pause)
+	pause.Pause()
+	_, err = validator.SignMessage(ctx, testChainID, "proof-of-possession", []byte("hello"))
+	require.IsType(t, &SigningPausedError{}, err)
+	pause.Resume()
+
+	kill, err := NewKillSwitchValidator(validator, t.TempDir())
+	require.NoError(t, err)
+	validator.SetKillSwitch(kill)
+	_, err = kill.Engage("incident")
+	require.NoError(t, err)
+	_, err = validator.SignMessage(ctx, testChainID, "proof-of-possession", []byte("hello"))
+	require.IsType(t, &KillSwitchError{}, err)
+	_, err = kill.Rearm()
+	require.NoError(t, err)
+
+	// the message prefix is only separated from the sign bytes of the default codec.
+	cosigners[1].config.Config.SignBytesCodecs = map[string]string{testChainID: "prefixed-test"}
+	_, err = validator.SignMessage(ctx, testChainID, "proof-of-possession", []byte("hello"))
+	require.ErrorContains(t, err, `its sign bytes codec "prefixed-test" may not be separated from messages`)
+	cosigners[0].config.Config.SignBytesCodecs = map[string]string{testChainID: "prefixed-test"}
+	_, err = validator.SignMessage(ctx, testChainID, "proof-of-possession", []byte("hello"))
+	require.IsType(t, &MessageSigningError{}, err)
+}
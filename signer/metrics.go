@@ -67,6 +67,14 @@ func (mt *metricsTimer) UpdatePrometheusMetrics() {
 	secondsSinceLastLocalNonceTime.Set(time.Since(mt.previousLocalNonce).Seconds())
 }
 
+// Phases of signing a block on the raft leader, see timedSignPhase.
+const (
+	signPhaseNonces    = "nonces"
+	signPhaseShareSign = "share_sign"
+	signPhaseCombine   = "combine"
+	signPhasePersist   = "persist"
+)
+
 var (
 	// Variables to calculate Prometheus Metrics
 	previousPrecommitHeight = int64(0)
@@ -214,6 +222,62 @@ var (
 		},
 		[]string{"peerid"},
 	)
+	protocolVersion = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "signer_cosigner_protocol_version",
+			Help: "Negotiated Cosigner Protocol Version (0 if not negotiated or incompatible)",
+		},
+		[]string{"peerid"},
+	)
+	protocolSkew = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "signer_cosigner_protocol_skew",
+			Help: "Local Max Cosigner Protocol Version minus Peer Max Cosigner Protocol Version",
+		},
+		[]string{"peerid"},
+	)
+	totalProtocolIncompatible = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_cosigner_protocol_incompatible",
+			Help: "Total Handshakes with Peer Cosigners Without a Common Protocol Version",
+		},
+		[]string{"peerid"},
+	)
+	cosignerVersionSkew = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "signer_cosigner_version_skew",
+			Help: "Software Version Skew of the Peer Cosigner: 0 Same Version, 1 Patch, 2 Minor, 3 Major, -1 Unknown",
+		},
+		[]string{"peerid"},
+	)
+	totalCosignerVersionIncompatible = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_cosigner_version_incompatible",
+			Help: "Total Handshakes with Peer Cosigners on an Incompatible Software Version",
+		},
+		[]string{"peerid"},
+	)
+	cosignerScore = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "signer_cosigner_score",
+			Help: "Health Score of the Peer Cosigner From 0 to 1, Seen by the Leader",
+		},
+		[]string{"peerid"},
+	)
+	cosignerQuarantined = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "signer_cosigner_quarantined",
+			Help: "1 if the Peer Cosigner is Quarantined From Nonce Generation and Signing, 0 Otherwise",
+		},
+		[]string{"peerid"},
+	)
+	totalCosignerQuarantines = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_cosigner_quarantines",
+			Help: "Total Times the Peer Cosigner Was Quarantined",
+		},
+		[]string{"peerid"},
+	)
 	drainedNonceCache = promauto.NewGauge(
 		prometheus.GaugeOpts{
 			Name: "signer_drained_nonce_cache",
@@ -226,6 +290,125 @@ var (
 			Help: "Total Nonces Requested When Cache is Drained",
 		},
 	)
+	nonceCacheSize = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "signer_nonce_cache_size",
+			Help: "Number of Nonces Ready in the Nonce Cache",
+		},
+	)
+	nonceCacheTargetSize = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "signer_nonce_cache_target_size",
+			Help: "Number of Nonces the Nonce Cache Keeps Ready to Meet Demand (Only on Raft Leader)",
+		},
+	)
+	nonceCacheDemand = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "signer_nonce_cache_avg_nonces_per_minute",
+			Help: "Moving Average of Nonces Used per Minute (Only on Raft Leader)",
+		},
+	)
+	nonceCacheTargetCorrection = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "signer_nonce_cache_target_correction",
+			Help: "Nonces Added to the Target Size for the Shortfall of Nonces Since the Last Reconciliation " +
+				"(Only on Raft Leader)",
+		},
+	)
+	nonceCacheRefillLatency = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "signer_nonce_cache_refill_latency_seconds",
+			Help: "Moving Average of the Seconds to Load Nonces From the Cosigners (Only on Raft Leader)",
+		},
+	)
+	totalNonceCacheLateCosigners = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_nonce_cache_late_cosigners",
+			Help: "Total Nonce Cache Refills That Went Ahead Without the Nonces of a Slow Cosigner",
+		},
+		[]string{"peerid"},
+	)
+	nonceCacheBackedOff = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "signer_nonce_cache_backed_off",
+			Help: "1 if the Peer Cosigner is Backed Off From Nonce Generation After Its Nonces Were Cleared " +
+				"Repeatedly, 0 Otherwise",
+		},
+		[]string{"peerid"},
+	)
+	totalNonceCacheBackoffs = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_nonce_cache_backoffs",
+			Help: "Total Times the Peer Cosigner Was Backed Off From Nonce Generation",
+		},
+		[]string{"peerid"},
+	)
+	nonceCacheBursting = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "signer_nonce_cache_bursting",
+			Help: "1 While the Empty Nonce Cache is Refilled in Bursts, 0 Otherwise (Only on Raft Leader)",
+		},
+	)
+	totalNonceCacheBursts = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "signer_total_nonce_cache_bursts",
+			Help: "Total Times the Empty Nonce Cache Was Refilled in Bursts",
+		},
+	)
+	totalNonceCachePushed = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_nonce_cache_pushed",
+			Help: "Total Cached Nonces Pushed to the Follower Cosigner (Only on Raft Leader)",
+		},
+		[]string{"peerid"},
+	)
+	totalNonceCachePushFailures = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_nonce_cache_push_failures",
+			Help: "Total Failed Pushes of the Nonce Cache to the Follower Cosigner (Only on Raft Leader)",
+		},
+		[]string{"peerid"},
+	)
+	nonceCacheOnDemandQueue = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "signer_nonce_cache_on_demand_queue",
+			Help: "Number of Sign Requests Waiting for Nonces Loaded on Demand (Only on Raft Leader)",
+		},
+	)
+	totalNonceCacheOnDemandWaits = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_nonce_cache_on_demand_waits",
+			Help: "Total Sign Requests That Waited for Nonces Loaded on Demand, by Result " +
+				"(served, timeout, canceled, rejected: queue full)",
+		},
+		[]string{"result"},
+	)
+	nonceCachePruned = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "signer_nonce_cache_pruned",
+			Help: "Number of Expired Nonces Pruned From the Nonce Cache in the Last Reconciliation",
+		},
+	)
+	totalNonceCacheExpired = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "signer_total_nonce_cache_expired",
+			Help: "Total Nonces Expired Before Use",
+		},
+	)
+	totalNonceCacheCleared = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "signer_total_nonce_cache_cleared",
+			Help: "Total Nonces Dropped Because Too Few Cosigners Remained After Clearing a Cosigner's Nonces",
+		},
+	)
+	totalNonceCacheGetNoncesFailures = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_nonce_cache_get_nonces_failures",
+			Help: "Total Sign Requests Without Nonces in the Nonce Cache, " +
+				"by Reason (empty: cache drained, cosigners: no nonces of the required cosigners)",
+		},
+		[]string{"reason"},
+	)
 
 	sentryConnectTries = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -241,6 +424,13 @@ var (
 		},
 		[]string{"node"},
 	)
+	totalSentrySignRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_sentry_sign_requests",
+			Help: "Total Sign Requests by Sentry, Type and Result (signed, duplicate, stale, rejected or error)",
+		},
+		[]string{"sentry", "chain_id", "type", "result"},
+	)
 
 	beyondBlockErrors = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -269,11 +459,276 @@ var (
 		Name: "signer_total_raft_leader_election_timeout",
 		Help: "Total Times Raft Leader Failed Election (Lacking Peers)",
 	})
+	raftLeaderID = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "signer_raft_leader_id",
+		Help: "Shard ID of the Current Raft Leader (-1 While There Is No Leader)",
+	})
+	raftIsLeader = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "signer_raft_is_leader",
+		Help: "1 if Signer is the Current Raft Leader, Otherwise 0",
+	})
+	raftLeaderSince = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "signer_raft_leader_since_timestamp_seconds",
+		Help: "Unix Time the Current Raft Leader Was Elected",
+	})
+	totalRaftLeaderChanges = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "signer_total_raft_leader_changes",
+		Help: "Total Times a Different Cosigner Became Raft Leader (High Rate Indicates Flapping Leadership)",
+	})
+	timedRaftLeadership = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "signer_raft_leadership_duration_seconds",
+		Help:    "Seconds a Cosigner Was Raft Leader Before Leadership Changed",
+		Buckets: prometheus.ExponentialBuckets(1, 4, 10),
+	})
+	timedRaftElection = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "signer_raft_election_duration_seconds",
+		Help:    "Seconds Raft Was Without a Leader Until One Was Elected",
+		Buckets: prometheus.ExponentialBuckets(0.05, 2, 10),
+	})
+	totalPriorityLeaderTransfers = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "signer_total_priority_leader_transfers",
+		Help: "Total Times the Leader Handed Leadership Over to a Cosigner With a Higher Priority",
+	})
+	splitBrainDetected = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "signer_split_brain",
+		Help: "1 While Another Cosigner Claims Leadership at the Same Time as This Leader, Otherwise 0",
+	})
+	splitBrainFenced = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "signer_split_brain_fenced",
+		Help: "1 While This Leader is Fenced From Initiating Sign Rounds by a Split Brain, Otherwise 0",
+	})
+	totalSplitBrainDetected = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "signer_total_split_brain_detected",
+		Help: "Total Times Another Cosigner Was Seen Claiming Leadership at the Same Time as This Leader",
+	})
+	totalSplitBrainFencedRequests = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "signer_total_split_brain_fenced_requests",
+		Help: "Total Sign Requests This Leader Did Not Sign Because it Was Fenced by a Split Brain",
+	})
 	totalInvalidSignature = promauto.NewCounter(prometheus.CounterOpts{
 		Name: "signer_error_total_invalid_signatures",
 		Help: "Total Times Combined Signature is Invalid",
 	})
 
+	sharedWatermarkHeight = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "signer_shared_watermark_height",
+			Help: "Height of the Shared Watermark Claimed by Any Cluster",
+		},
+		[]string{"chain_id"},
+	)
+	watermarkClusterActive = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "signer_watermark_cluster_active",
+		Help: "1 if the Cluster is the Active Cluster With Watermark Failover, 0 While it is on Standby",
+	})
+	totalWatermarkStandbyRefused = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_watermark_standby_refused",
+			Help: "Total Sign Requests Refused Because the Cluster is on Standby",
+		},
+		[]string{"chain_id"},
+	)
+	signingPaused = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "signer_signing_paused",
+		Help: "1 While Signing is Paused With the Admin API, 0 Otherwise",
+	})
+	killSwitchEngaged = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "signer_kill_switch_engaged",
+		Help: "1 While the Kill Switch is Engaged, 0 Otherwise",
+	})
+	totalKillSwitchRefusals = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_error_total_kill_switch_refusals",
+			Help: "Total Times a Sign Request Was Refused While the Kill Switch Was Engaged",
+		},
+		[]string{"chain_id"},
+	)
+	totalSigningPausedRefused = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_signing_paused_refused",
+			Help: "Total Sign Requests Refused While Signing is Paused",
+		},
+		[]string{"chain_id"},
+	)
+	totalCertificateReloads = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_tls_certificate_reloads",
+			Help: "Total Times a Changed TLS Certificate Was Reloaded, by Result",
+		},
+		[]string{"cert_file", "result"},
+	)
+	certificateNotAfter = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "signer_tls_certificate_not_after_seconds",
+			Help: "Expiry of the Served TLS Certificate as a Unix Timestamp",
+		},
+		[]string{"cert_file"},
+	)
+	totalAdminApprovalRefusals = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_error_total_admin_approval_refusals",
+			Help: "Total Admin Requests Refused for Lack of Approvals, by Operation",
+		},
+		[]string{"operation"},
+	)
+	totalAdminRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_admin_requests",
+			Help: "Total Requests to the Admin API, by Path and Status Code",
+		},
+		[]string{"path", "code"},
+	)
+	totalConfigReloads = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_config_reloads",
+			Help: "Total Reloads of the Config by Result (applied, invalid or failed)",
+		},
+		[]string{"result"},
+	)
+	configRestartRequired = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "signer_config_restart_required",
+			Help: "Whether the Reloaded Config has Changes that only Apply after a Restart (1) or not (0)",
+		},
+	)
+	totalWatermarkClaimFailed = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_error_total_watermark_claim_failed",
+			Help: "Total Times the Shared Watermark Could Not Be Claimed",
+		},
+		[]string{"chain_id"},
+	)
+
+	lastStateBackup = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "signer_state_backup_last_success_timestamp_seconds",
+		Help: "Unix Time of the Last Successful State Backup",
+	})
+	totalStateBackupFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "signer_error_total_state_backup_failures",
+		Help: "Total Times a State Backup Failed",
+	})
+
+	totalSignStateRecoveries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_sign_state_recoveries",
+			Help: "Total Times a Missing or Corrupt Sign State was Recovered from Peers",
+		},
+		[]string{"chain_id"},
+	)
+
+	totalChainTipLagRefusals = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_error_total_chain_tip_lag_refusals",
+			Help: "Total Times a Sign Request Was Refused for Being Too Far Below the Chain Tip",
+		},
+		[]string{"chain_id"},
+	)
+	totalTimestampSkewRefusals = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_error_total_timestamp_skew_refusals",
+			Help: "Total Times a Sign Request Was Refused for a Timestamp Too Far Off the Signer Clock",
+		},
+		[]string{"chain_id"},
+	)
+	totalTimestampRegressionRefusals = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_error_total_timestamp_regression_refusals",
+			Help: "Total Times a Sign Request Was Refused for a Timestamp Before the Last Signed Timestamp",
+		},
+		[]string{"chain_id"},
+	)
+	totalChainNotAllowedRefusals = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_error_total_chain_not_allowed_refusals",
+			Help: "Total Times a Sign or Public Key Request Was Refused for a Chain Not in the Allowed Chain IDs",
+		},
+		[]string{"chain_id", "request"},
+	)
+	totalSignRateLimitRefusals = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_error_total_sign_rate_limit_refusals",
+			Help: "Total Times a Sign Request Was Refused for Being Over the Rate Limit of the Chain or Connection",
+		},
+		[]string{"chain_id", "limit"},
+	)
+	totalSignRateLimitQueued = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_sign_rate_limit_queued",
+			Help: "Total Times a Sign Request Was Delayed to Be Within the Rate Limits",
+		},
+		[]string{"chain_id"},
+	)
+	signSchedulerQueued = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "signer_sign_scheduler_queued",
+			Help: "Number of Sign Requests Waiting for the Sign Scheduler to Sign Them",
+		},
+	)
+	timedSignSchedulerWait = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "signer_sign_scheduler_wait_seconds",
+			Help:    "Seconds a Sign Request Waited for the Sign Scheduler to Sign It",
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 14), // 1ms to ~8s
+		},
+		[]string{"chain_id", "type"},
+	)
+	totalMessagesSigned = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_messages_signed",
+			Help: "Total Messages Threshold Signed Through the Admin API",
+		},
+		[]string{"chain_id", "type"},
+	)
+	totalMessageSigningRefusals = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_error_total_message_signing_refusals",
+			Help: "Total Times Signing a Message Was Refused for a Type Not in the Message Signing Types",
+		},
+		[]string{"chain_id", "type"},
+	)
+	totalSignLockRefusals = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_error_total_sign_lock_refusals",
+			Help: "Total Times a Sign Request Was Refused for a Sign Lock Held by Another Cluster",
+		},
+		[]string{"chain_id"},
+	)
+	totalSignLockFailures = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_error_total_sign_lock_failures",
+			Help: "Total Times a Sign Request Was Refused for Failing to Acquire the Sign Lock",
+		},
+		[]string{"chain_id"},
+	)
+	totalSignPolicyRefusals = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_error_total_sign_policy_refusals",
+			Help: "Total Times a Sign Request Was Refused for Breaking a Rule of the Sign Policy of the Chain",
+		},
+		[]string{"chain_id", "rule"},
+	)
+	haltHeight = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "signer_halt_height",
+			Help: "Height of the Chain After Which Signing Halts for an Upgrade",
+		},
+		[]string{"chain_id"},
+	)
+	totalHaltHeightRefusals = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_error_total_halt_height_refusals",
+			Help: "Total Times a Sign Request Was Refused for a Height Above the Halt Height of the Chain",
+		},
+		[]string{"chain_id"},
+	)
+	totalChainTipQueryFailures = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_error_total_chain_tip_query_failures",
+			Help: "Total Times the Chain Tip Could Not Be Queried from the Chain RPC",
+		},
+		[]string{"chain_id"},
+	)
+
 	totalInsufficientCosigners = promauto.NewCounter(prometheus.CounterOpts{
 		Name: "signer_error_total_insufficient_cosigners",
 		Help: "Total Times Cosigners doesn't reach threshold",
@@ -305,6 +760,136 @@ var (
 		},
 		[]string{"peerid"},
 	)
+	totalMissedBlocks = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_missed_blocks",
+			Help: "Total Committed Blocks Without a Signature of the Validator, by Cause (signer or sentry)",
+		},
+		[]string{"chain_id", "cause"},
+	)
+	missedBlocksConsecutive = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "signer_missed_blocks_consecutive",
+			Help: "Number of Committed Blocks in a Row Without a Signature of the Validator",
+		},
+		[]string{"chain_id"},
+	)
+	totalMissedBlockCheckFailures = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_error_total_missed_block_check_failures",
+			Help: "Total Times the Commits Could Not Be Queried from the Chain RPC to Check for Missed Blocks",
+		},
+		[]string{"chain_id"},
+	)
+	buildInfo = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "signer_build_info",
+			Help: "Build and Effective Config of the Signer, Always 1",
+		},
+		[]string{
+			"version", "commit", "go_version", "sign_mode", "key_types",
+			"cosigners", "threshold", "config_hash", "threshold_config_hash",
+		},
+	)
+	totalProfilePushFailures = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_profile_push_failures",
+			Help: "Total Failures to Push a Profile to the Profiling Server",
+		},
+		[]string{"profile"},
+	)
+	totalDroppedAlerts = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "signer_total_dropped_alerts",
+			Help: "Total Alerts Dropped Because the Alert Queue Was Full",
+		},
+	)
+	totalAlertWebhookFailures = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_alert_webhook_failures",
+			Help: "Total Failures to Send an Alert to a Webhook",
+		},
+		[]string{"format"},
+	)
+	totalDroppedEvents = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "signer_total_dropped_events",
+			Help: "Total Events Dropped Because the Event Queue Was Full",
+		},
+	)
+	totalEventSinkFailures = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_event_sink_failures",
+			Help: "Total Failures to Send an Event to a Sink",
+		},
+		[]string{"sink"},
+	)
+	totalDroppedErrorReports = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "signer_total_dropped_error_reports",
+			Help: "Total Error Reports Dropped Because the Error Report Queue Was Full",
+		},
+	)
+	totalErrorReportFailures = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_error_report_failures",
+			Help: "Total Failures to Send an Error Report",
+		},
+		[]string{"format"},
+	)
+
+	timedCosignerRequest = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "signer_cosigner_request_seconds",
+			Help:    "Round Trip Time of Requests to Peer Cosigners",
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 14), // 1ms to ~8s
+		},
+		[]string{"peerid", "method"},
+	)
+	totalNonceStreamsOpened = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_cosigner_nonce_streams_opened",
+			Help: "Total Nonce Streams Opened to the Peer Cosigner, Once and After Each Broken Stream",
+		},
+		[]string{"peerid"},
+	)
+	totalCosignerRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_cosigner_requests",
+			Help: "Total Requests to Peer Cosigners",
+		},
+		[]string{"peerid", "method"},
+	)
+	totalCosignerRequestErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_cosigner_request_errors",
+			Help: "Total Failed Requests to Peer Cosigners, Including Timeouts",
+		},
+		[]string{"peerid", "method"},
+	)
+	totalCosignerRequestTimeouts = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_cosigner_request_timeouts",
+			Help: "Total Requests to Peer Cosigners That Timed Out",
+		},
+		[]string{"peerid", "method"},
+	)
+	timedSignPhase = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "signer_sign_phase_seconds",
+			Help:    "Seconds Taken by Each Phase of Signing a Block (Only on Raft Leader)",
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 14), // 1ms to ~8s
+		},
+		[]string{"chain_id", "phase"},
+	)
+	timedSignPhaseCosignerShare = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "signer_sign_phase_cosigner_share_seconds",
+			Help:    "Seconds Taken by Each Cosigner to Sign its Share of a Block (Only on Raft Leader)",
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 14), // 1ms to ~8s
+		},
+		[]string{"chain_id", "peerid"},
+	)
 	timedCosignerSignLag = promauto.NewSummaryVec(
 		prometheus.SummaryOpts{
 			Name:       "signer_cosigner_sign_lag_seconds",
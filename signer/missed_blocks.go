@@ -0,0 +1,328 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	cometbytes "github.com/cometbft/cometbft/libs/bytes"
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	cometservice "github.com/cometbft/cometbft/libs/service"
+)
+
+const (
+	missedBlockWatchInterval = 5 * time.Second
+
+	// missedBlockMaxHeightsPerCheck bounds the commits queried by a check when the watcher
+	// falls behind the chain, e.g. after the chain RPC was unreachable.
+	missedBlockMaxHeightsPerCheck = 100
+)
+
+// Causes of a missed block.
+const (
+	// MissedBlockCauseSigner is a block horcrux was asked to precommit but did not sign.
+	MissedBlockCauseSigner = "signer"
+
+	// MissedBlockCauseSentry is a block horcrux was never asked to precommit, or whose
+	// precommit horcrux signed but which did not reach the chain.
+	MissedBlockCauseSentry = "sentry"
+)
+
+// missedBlockChain is the state of the watcher for a chain.
+type missedBlockChain struct {
+	chainID string
+	url     string
+	tip     *chainTip
+	client  *http.Client
+
+	// address of the validator, queried from the validator on the first check.
+	address cometbytes.HexBytes
+
+	// lastChecked is the last height whose commit was checked.
+	lastChecked int64
+
+	// active is set once the validator is seen in a commit, so that a validator which
+	// is not in the active set is not reported as missing every block.
+	active bool
+
+	// consecutive is the number of blocks missed in a row.
+	consecutive int64
+
+	mu sync.Mutex
+	// precommits holds the heights horcrux was asked to precommit, and whether it signed.
+	precommits map[int64]bool
+}
+
+// MissedBlockWatcher polls the chain RPC of the chains configured with watchMissedBlocks for the
+// signatures of committed blocks, and counts the blocks the validator is absent from. A missed
+// block is attributed to the signer if horcrux was asked to precommit the block but did not sign,
+// and to the sentries otherwise. The first block missed in a row is notified to the AlertNotifier.
+type MissedBlockWatcher struct {
+	cometservice.BaseService
+
+	logger   cometlog.Logger
+	val      PrivValidator
+	notifier *AlertNotifier
+	chains   map[string]*missedBlockChain
+
+	cancel  context.CancelFunc
+	stopped sync.WaitGroup
+}
+
+// NewMissedBlockWatcher returns a MissedBlockWatcher for the chains of cfgs that watch missed blocks.
+// The address of the validator is derived from the public key of val. notifier may be nil.
+func NewMissedBlockWatcher(
+	logger cometlog.Logger,
+	val PrivValidator,
+	cfgs ChainRPCConfigs,
+	notifier *AlertNotifier,
+) (*MissedBlockWatcher, error) {
+	if err := cfgs.Validate(); err != nil {
+		return nil, err
+	}
+	chains := make(map[string]*missedBlockChain)
+	for chainID, cfg := range cfgs {
+		if !cfg.WatchMissedBlocks {
+			continue
+		}
+		timeout, _ := cfg.timeout()
+		chains[chainID] = &missedBlockChain{
+			chainID:    chainID,
+			url:        strings.TrimSuffix(cfg.URL, "/"),
+			tip:        newChainTip(cfg),
+			client:     &http.Client{Timeout: timeout},
+			precommits: make(map[int64]bool),
+		}
+	}
+	w := &MissedBlockWatcher{
+		logger:   logger,
+		val:      val,
+		notifier: notifier,
+		chains:   chains,
+	}
+	w.BaseService = *cometservice.NewBaseService(logger, "MissedBlockWatcher", w)
+	return w, nil
+}
+
+func (w *MissedBlockWatcher) OnStart() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+
+	w.stopped.Add(1)
+	go func() {
+		defer w.stopped.Done()
+		ticker := time.NewTicker(missedBlockWatchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				for _, chain := range w.chains {
+					if err := w.check(ctx, chain); err != nil && ctx.Err() == nil {
+						totalMissedBlockCheckFailures.WithLabelValues(chain.chainID).Inc()
+						w.logger.Error("Failed to check for missed blocks", "chain_id", chain.chainID, "error", err)
+					}
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+func (w *MissedBlockWatcher) OnStop() {
+	w.cancel()
+	w.stopped.Wait()
+}
+
+// recordPrecommit records that horcrux was asked to precommit the height of a chain, and whether it signed.
+func (w *MissedBlockWatcher) recordPrecommit(chainID string, height int64, signed bool) {
+	chain, ok := w.chains[chainID]
+	if !ok {
+		return
+	}
+	chain.mu.Lock()
+	defer chain.mu.Unlock()
+	if height <= chain.lastChecked {
+		return
+	}
+	// the precommit of any round counts.
+	chain.precommits[height] = chain.precommits[height] || signed
+}
+
+// check checks the commits of the chain since the last check. The latest block is not checked
+// until the next block is committed, since precommits may still be added to its commit.
+func (w *MissedBlockWatcher) check(ctx context.Context, chain *missedBlockChain) error {
+	if chain.address == nil {
+		pubKey, err := w.val.GetPubKey(ctx, chain.chainID)
+		if err != nil {
+			return fmt.Errorf("failed to get public key: %w", err)
+		}
+		chain.address = cometcryptoed25519.PubKey(pubKey).Address()
+	}
+
+	tip, err := chain.tip.get(ctx)
+	if err != nil {
+		return err
+	}
+	to := tip - 1
+	from := chain.lastChecked + 1
+	switch {
+	case chain.lastChecked == 0:
+		// blocks committed before the watcher started are not checked.
+		from = to
+	case to-from >= missedBlockMaxHeightsPerCheck:
+		from = to - missedBlockMaxHeightsPerCheck + 1
+	}
+
+	for height := from; height <= to; height++ {
+		signed, err := w.commitSigned(ctx, chain, height)
+		if err != nil {
+			return err
+		}
+		w.observe(chain, height, signed)
+	}
+	return nil
+}
+
+type chainRPCCommit struct {
+	Result struct {
+		SignedHeader struct {
+			Commit struct {
+				Signatures []struct {
+					ValidatorAddress cometbytes.HexBytes `json:"validator_address"`
+				} `json:"signatures"`
+			} `json:"commit"`
+		} `json:"signed_header"`
+	} `json:"result"`
+}
+
+// commitSigned returns true if the validator signed the commit of the height.
+// Precommits for nil count as signed, as they do for the uptime of the validator.
+func (w *MissedBlockWatcher) commitSigned(ctx context.Context, chain *missedBlockChain, height int64) (bool, error) {
+	url := fmt.Sprintf("%s/commit?height=%d", chain.url, height)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, err
+	}
+	res, err := chain.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("chain rpc commit request failed: %s", res.Status)
+	}
+
+	var commit chainRPCCommit
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<22)).Decode(&commit); err != nil {
+		return false, fmt.Errorf("failed to decode chain rpc commit: %w", err)
+	}
+	for _, sig := range commit.Result.SignedHeader.Commit.Signatures {
+		if bytes.Equal(sig.ValidatorAddress, chain.address) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// observe records whether the validator signed the commit of the height.
+func (w *MissedBlockWatcher) observe(chain *missedBlockChain, height int64, signed bool) {
+	chain.mu.Lock()
+	precommitted, requested := chain.precommits[height]
+	for h := range chain.precommits {
+		if h <= height {
+			delete(chain.precommits, h)
+		}
+	}
+	chain.lastChecked = height
+	chain.mu.Unlock()
+
+	if signed {
+		if chain.consecutive > 0 {
+			w.logger.Info(
+				"Validator is signing blocks again",
+				"chain_id", chain.chainID,
+				"height", height,
+				"missed", chain.consecutive,
+			)
+		}
+		chain.active = true
+		chain.consecutive = 0
+		missedBlocksConsecutive.WithLabelValues(chain.chainID).Set(0)
+		return
+	}
+	if !chain.active {
+		return
+	}
+
+	cause, reason := MissedBlockCauseSentry, "horcrux was not asked to precommit it, check the sentries"
+	switch {
+	case precommitted:
+		reason = "horcrux signed its precommit but the precommit did not reach the chain, check the sentries"
+	case requested:
+		cause, reason = MissedBlockCauseSigner, "horcrux was asked to precommit it but did not sign, check the signer"
+	}
+
+	chain.consecutive++
+	totalMissedBlocks.WithLabelValues(chain.chainID, cause).Inc()
+	missedBlocksConsecutive.WithLabelValues(chain.chainID).Set(float64(chain.consecutive))
+	w.logger.Error(
+		"Validator missed block",
+		"chain_id", chain.chainID,
+		"height", height,
+		"cause", cause,
+		"consecutive", chain.consecutive,
+	)
+
+	if chain.consecutive == 1 && w.notifier != nil {
+		w.notifier.Notify(Alert{
+			Event:    AlertEventMissedBlock,
+			Severity: AlertSeverityWarning,
+			Message:  fmt.Sprintf("validator missed block %d of chain %s: %s", height, chain.chainID, reason),
+			ChainID:  chain.chainID,
+			Height:   height,
+		})
+	}
+}
+
+// MissedBlockValidator is a PrivValidator that records the precommits it is asked to sign
+// in a MissedBlockWatcher.
+type MissedBlockValidator struct {
+	val     PrivValidator
+	watcher *MissedBlockWatcher
+}
+
+// NewMissedBlockValidator returns a MissedBlockValidator for the sign requests of val.
+func NewMissedBlockValidator(val PrivValidator, watcher *MissedBlockWatcher) *MissedBlockValidator {
+	return &MissedBlockValidator{
+		val:     val,
+		watcher: watcher,
+	}
+}
+
+// Sign implements PrivValidator.
+func (v *MissedBlockValidator) Sign(ctx context.Context, chainID string, block Block) ([]byte, time.Time, error) {
+	sig, timestamp, err := v.val.Sign(ctx, chainID, block)
+	if block.Step == stepPrecommit {
+		v.watcher.recordPrecommit(chainID, block.Height, err == nil)
+	}
+	return sig, timestamp, err
+}
+
+// GetPubKey implements PrivValidator.
+func (v *MissedBlockValidator) GetPubKey(ctx context.Context, chainID string) ([]byte, error) {
+	return v.val.GetPubKey(ctx, chainID)
+}
+
+// Stop implements PrivValidator.
+func (v *MissedBlockValidator) Stop() {
+	v.val.Stop()
+}
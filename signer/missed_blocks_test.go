@@ -0,0 +1,146 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type pubKeyPrivValidator struct {
+	mockPrivValidator
+	pubKey []byte
+}
+
+func (pv *pubKeyPrivValidator) GetPubKey(context.Context, string) ([]byte, error) {
+	return pv.pubKey, nil
+}
+
+// testChainRPC serves the status and the commits of a chain, signed by the validators of each height.
+type testChainRPC struct {
+	mu      sync.Mutex
+	tip     int64
+	signers map[int64][]string
+}
+
+func (c *testChainRPC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch r.URL.Path {
+	case "/status":
+		fmt.Fprintf(w, `{"result":{"sync_info":{"latest_block_height":"%d"}}}`, c.tip)
+	case "/commit":
+		height, err := strconv.ParseInt(r.URL.Query().Get("height"), 10, 64)
+		if err != nil || height > c.tip {
+			http.Error(w, "invalid height", http.StatusInternalServerError)
+			return
+		}
+		// absent validators have an empty address.
+		sigs := `{"block_id_flag":1,"validator_address":""}`
+		for _, addr := range c.signers[height] {
+			sigs += fmt.Sprintf(`,{"block_id_flag":2,"validator_address":"%s"}`, addr)
+		}
+		fmt.Fprintf(w, `{"result":{"signed_header":{"commit":{"signatures":[%s]}}}}`, sigs)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (c *testChainRPC) commit(height int64, signers ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.signers == nil {
+		c.signers = make(map[int64][]string)
+	}
+	c.signers[height] = signers
+	c.tip = height + 1
+}
+
+func TestMissedBlockWatcher(t *testing.T) {
+	const chainID = "missed-blocks-1"
+
+	rpc := &testChainRPC{}
+	srv := httptest.NewServer(rpc)
+	defer srv.Close()
+
+	privKey := cometcryptoed25519.GenPrivKey()
+	address := privKey.PubKey().Address().String()
+	other := cometcryptoed25519.GenPrivKey().PubKey().Address().String()
+
+	notifier, err := NewAlertNotifier(cometlog.NewNopLogger(), &AlertsConfig{
+		Webhooks: []AlertWebhookConfig{{URL: "http://localhost/hook"}},
+	}, nil)
+	require.NoError(t, err)
+
+	pv := &pubKeyPrivValidator{pubKey: privKey.PubKey().Bytes()}
+	watcher, err := NewMissedBlockWatcher(cometlog.NewNopLogger(), pv, ChainRPCConfigs{
+		chainID:       {URL: srv.URL, WatchMissedBlocks: true},
+		"other-chain": {URL: srv.URL},
+	}, notifier)
+	require.NoError(t, err)
+	require.Len(t, watcher.chains, 1)
+	chain := watcher.chains[chainID]
+
+	val := NewMissedBlockValidator(pv, watcher)
+	precommit := func(height int64) {
+		_, _, err := val.Sign(context.Background(), chainID, Block{Height: height, Step: stepPrecommit})
+		require.NoError(t, err)
+	}
+	check := func(tip int64) {
+		chain.tip.fetched = time.Time{}
+		require.NoError(t, watcher.check(context.Background(), chain))
+		require.Equal(t, tip-1, chain.lastChecked)
+	}
+	missed := func(cause string) float64 {
+		return testutil.ToFloat64(totalMissedBlocks.WithLabelValues(chainID, cause))
+	}
+
+	// blocks are not reported missing before the validator is seen in a commit.
+	rpc.commit(10, other)
+	check(11)
+	require.Zero(t, missed(MissedBlockCauseSentry))
+
+	precommit(11)
+	rpc.commit(11, address)
+	check(12)
+
+	// horcrux signed the precommit but it did not reach the chain.
+	precommit(12)
+	rpc.commit(12, other)
+	check(13)
+	require.Equal(t, float64(1), missed(MissedBlockCauseSentry))
+	require.Len(t, notifier.queue, 1)
+	alert := <-notifier.queue
+	require.Equal(t, AlertEventMissedBlock, alert.Event)
+	require.Equal(t, int64(12), alert.Height)
+	require.Contains(t, alert.Message, "did not reach the chain")
+
+	// horcrux was asked to precommit but failed to sign.
+	watcherVal := NewMissedBlockValidator(&failingPrivValidator{err: fmt.Errorf("cosigners unreachable")}, watcher)
+	_, _, err = watcherVal.Sign(context.Background(), chainID, Block{Height: 13, Step: stepPrecommit})
+	require.Error(t, err)
+	rpc.commit(13, other)
+	// horcrux was not asked to precommit.
+	rpc.commit(14, other)
+	check(15)
+	require.Equal(t, float64(1), missed(MissedBlockCauseSigner))
+	require.Equal(t, float64(2), missed(MissedBlockCauseSentry))
+	require.Equal(t, float64(3), testutil.ToFloat64(missedBlocksConsecutive.WithLabelValues(chainID)))
+	// only the first block missed in a row is alerted.
+	require.Empty(t, notifier.queue)
+
+	precommit(15)
+	rpc.commit(15, address)
+	check(16)
+	require.Zero(t, testutil.ToFloat64(missedBlocksConsecutive.WithLabelValues(chainID)))
+	require.Empty(t, chain.precommits)
+}
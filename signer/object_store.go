@@ -0,0 +1,291 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultS3Region = "us-east-1"
+	gcsEndpoint     = "https://storage.googleapis.com"
+
+	maxObjectSize = 64 << 20
+)
+
+// ObjectStoreURL is a parsed s3://bucket/prefix or gs://bucket/prefix URL.
+type ObjectStoreURL struct {
+	Scheme string
+	Bucket string
+	Key    string
+}
+
+// ParseObjectStoreURL parses an s3:// or gs:// URL.
+func ParseObjectStoreURL(rawURL string) (ObjectStoreURL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ObjectStoreURL{}, err
+	}
+	if u.Scheme != "s3" && u.Scheme != "gs" {
+		return ObjectStoreURL{}, fmt.Errorf("unsupported object store url scheme %q, must be s3 or gs", u.Scheme)
+	}
+	if u.Host == "" {
+		return ObjectStoreURL{}, fmt.Errorf("object store url %s is missing bucket", rawURL)
+	}
+	return ObjectStoreURL{
+		Scheme: u.Scheme,
+		Bucket: u.Host,
+		Key:    strings.Trim(u.Path, "/"),
+	}, nil
+}
+
+// objectStore is a minimal client for the S3 REST API, authenticated with AWS signature V4.
+// Google Cloud Storage is supported through its S3 compatible XML API using HMAC keys.
+// Credentials are read from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN.
+type objectStore struct {
+	endpoint  *url.URL
+	pathStyle bool
+	region    string
+	bucket    string
+
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+
+	client *http.Client
+}
+
+// newObjectStore returns an objectStore for the bucket of u.
+// If endpoint is empty, the AWS or GCS endpoint is used according to the URL scheme.
+func newObjectStore(u ObjectStoreURL, endpoint, region string) (*objectStore, error) {
+	s := &objectStore{
+		bucket:          u.Bucket,
+		region:          region,
+		accessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		client:          &http.Client{Timeout: time.Minute},
+	}
+	if s.accessKeyID == "" || s.secretAccessKey == "" {
+		return nil, fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set to access %s://%s", u.Scheme, u.Bucket)
+	}
+
+	switch {
+	case endpoint != "":
+		// custom endpoints (minio, ceph, etc.) are generally path style.
+		s.pathStyle = true
+	case u.Scheme == "gs":
+		endpoint = gcsEndpoint
+		s.pathStyle = true
+		if s.region == "" {
+			s.region = "auto"
+		}
+	default:
+		if s.region == "" {
+			s.region = defaultS3Region
+		}
+		endpoint = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", u.Bucket, s.region)
+	}
+	if s.region == "" {
+		s.region = defaultS3Region
+	}
+
+	var err error
+	s.endpoint, err = url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid object store endpoint: %w", err)
+	}
+	return s, nil
+}
+
+func (s *objectStore) objectURL(key string, query url.Values) *url.URL {
+	u := *s.endpoint
+	path := "/"
+	if s.pathStyle {
+		path += s.bucket + "/"
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + path + key
+	u.RawQuery = canonicalQueryString(query)
+	return &u
+}
+
+// PutObject uploads body to key.
+func (s *objectStore) PutObject(ctx context.Context, key string, body []byte) error {
+	res, err := s.do(ctx, http.MethodPut, key, nil, body)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	return checkObjectStoreResponse(res)
+}
+
+// GetObject downloads the object at key.
+func (s *objectStore) GetObject(ctx context.Context, key string) ([]byte, error) {
+	res, err := s.do(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if err := checkObjectStoreResponse(res); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(io.LimitReader(res.Body, maxObjectSize))
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// ListObjects returns the keys of all objects with the prefix.
+func (s *objectStore) ListObjects(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	var continuationToken string
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", prefix)
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+
+		res, err := s.do(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkObjectStoreResponse(res); err != nil {
+			res.Body.Close()
+			return nil, err
+		}
+
+		var result listBucketResult
+		err = xml.NewDecoder(io.LimitReader(res.Body, maxObjectSize)).Decode(&result)
+		res.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode object list: %w", err)
+		}
+
+		for _, c := range result.Contents {
+			keys = append(keys, c.Key)
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return keys, nil
+		}
+		continuationToken = result.NextContinuationToken
+	}
+}
+
+func (s *objectStore) do(
+	ctx context.Context,
+	method string,
+	key string,
+	query url.Values,
+	body []byte,
+) (*http.Response, error) {
+	u := s.objectURL(key, query)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, body, time.Now().UTC())
+	return s.client.Do(req)
+}
+
+// sign adds an AWS signature V4 authorization header to the request.
+func (s *objectStore) sign(req *http.Request, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if name == "x-amz-date" || name == "x-amz-content-sha256" || name == "x-amz-security-token" {
+			headers[name] = strings.TrimSpace(values[0])
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.secretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, s.region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+// canonicalQueryString encodes the query sorted by key, with spaces as %20 as required by signature V4.
+func canonicalQueryString(query url.Values) string {
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+func checkObjectStoreResponse(res *http.Response) error {
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+	return fmt.Errorf("object store request %s %s failed: %s: %s",
+		res.Request.Method, res.Request.URL.Path, res.Status, strings.TrimSpace(string(msg)))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
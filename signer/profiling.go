@@ -0,0 +1,427 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	cometservice "github.com/cometbft/cometbft/libs/service"
+)
+
+const (
+	defaultProfilingAppName  = "horcrux"
+	defaultProfilingInterval = time.Minute
+
+	profileUploadTimeout = 30 * time.Second
+
+	// profileMutexFraction samples 1 in 10 mutex contention events, and profileBlockRate
+	// one blocking event per 10µs spent blocked.
+	profileMutexFraction = 10
+	profileBlockRate     = 10000
+)
+
+// Profiling formats.
+const (
+	ProfilingFormatPyroscope = "pyroscope"
+	ProfilingFormatPprof     = "pprof"
+)
+
+// Profiles that can be pushed.
+const (
+	ProfileCPU       = "cpu"
+	ProfileHeap      = "heap"
+	ProfileGoroutine = "goroutine"
+	ProfileMutex     = "mutex"
+	ProfileBlock     = "block"
+)
+
+var profileTypes = []string{ProfileCPU, ProfileHeap, ProfileGoroutine, ProfileMutex, ProfileBlock}
+
+// Roles of the signer in the labels of the profiles.
+const (
+	ProfileRoleSingle   = "single"
+	ProfileRoleLeader   = "leader"
+	ProfileRoleFollower = "follower"
+)
+
+// ProfilingConfig configures pushing pprof profiles to a continuous profiling server.
+type ProfilingConfig struct {
+	// Format of the pushes, pyroscope (default) for the Pyroscope ingest API, or pprof to POST
+	// the raw profiles.
+	Format string `yaml:"format,omitempty"`
+
+	// URL of the Pyroscope server, e.g. http://pyroscope:4040, or the URL the pprof profiles are POSTed to.
+	URL string `yaml:"url"`
+
+	// AuthTokenFile is the file holding a bearer token sent with the pushes.
+	AuthTokenFile string `yaml:"authTokenFile,omitempty"`
+
+	// AppName is the application name of the profiles. Defaults to horcrux.
+	AppName string `yaml:"appName,omitempty"`
+
+	// Interval of the CPU profiles and between pushes. Defaults to 1m.
+	Interval string `yaml:"interval,omitempty"`
+
+	// Profiles pushed, cpu, heap, goroutine, mutex or block. Defaults to cpu and heap.
+	Profiles []string `yaml:"profiles,omitempty"`
+
+	// Tags added to the labels of every profile.
+	Tags map[string]string `yaml:"tags,omitempty"`
+}
+
+func (cfg *ProfilingConfig) Validate() error {
+	if cfg == nil {
+		return nil
+	}
+	switch cfg.format() {
+	case ProfilingFormatPyroscope, ProfilingFormatPprof:
+	default:
+		return fmt.Errorf(
+			"invalid profiling format %q, must be %s or %s",
+			cfg.Format, ProfilingFormatPyroscope, ProfilingFormatPprof,
+		)
+	}
+	if _, err := url.ParseRequestURI(cfg.URL); err != nil {
+		return fmt.Errorf("invalid profiling url: %w", err)
+	}
+	if _, err := cfg.interval(); err != nil {
+		return fmt.Errorf("invalid profiling interval: %w", err)
+	}
+	for _, p := range cfg.Profiles {
+		if !isProfileType(p) {
+			return fmt.Errorf("invalid profile %q, must be one of %v", p, profileTypes)
+		}
+	}
+	for k := range cfg.Tags {
+		if k == "" || strings.ContainsAny(k, "{}=,") {
+			return fmt.Errorf("invalid profiling tag %q", k)
+		}
+	}
+	return nil
+}
+
+func (cfg *ProfilingConfig) format() string {
+	if cfg.Format == "" {
+		return ProfilingFormatPyroscope
+	}
+	return cfg.Format
+}
+
+func (cfg *ProfilingConfig) appName() string {
+	if cfg.AppName == "" {
+		return defaultProfilingAppName
+	}
+	return cfg.AppName
+}
+
+func (cfg *ProfilingConfig) interval() (time.Duration, error) {
+	if cfg.Interval == "" {
+		return defaultProfilingInterval, nil
+	}
+	d, err := time.ParseDuration(cfg.Interval)
+	if err != nil {
+		return 0, err
+	}
+	if d < time.Second {
+		return 0, fmt.Errorf("interval must be at least 1s")
+	}
+	return d, nil
+}
+
+func (cfg *ProfilingConfig) profiles() []string {
+	if len(cfg.Profiles) == 0 {
+		return []string{ProfileCPU, ProfileHeap}
+	}
+	return cfg.Profiles
+}
+
+func (cfg *ProfilingConfig) hasProfile(profile string) bool {
+	for _, p := range cfg.profiles() {
+		if p == profile {
+			return true
+		}
+	}
+	return false
+}
+
+func isProfileType(profile string) bool {
+	for _, p := range profileTypes {
+		if p == profile {
+			return true
+		}
+	}
+	return false
+}
+
+// Profiler continuously profiles the signer and pushes the profiles to a profiling server. A CPU
+// profile covers each interval, the other profiles are snapshots taken at its end. The profiles
+// are labelled with the role of the signer at the end of the interval, so the sign path of the
+// leader can be told apart from the followers.
+type Profiler struct {
+	cometservice.BaseService
+
+	logger   cometlog.Logger
+	cfg      *ProfilingConfig
+	health   *Health
+	interval time.Duration
+	token    string
+	labels   map[string]string
+	client   *http.Client
+
+	cancel  context.CancelFunc
+	stopped sync.WaitGroup
+}
+
+// NewProfiler returns a Profiler for cfg. health tells the role of the signer, and may be nil.
+// version is added to the labels of the profiles.
+func NewProfiler(logger cometlog.Logger, cfg *ProfilingConfig, health *Health, version string) (*Profiler, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	interval, err := cfg.interval()
+	if err != nil {
+		return nil, err
+	}
+
+	var token string
+	if cfg.AuthTokenFile != "" {
+		bz, err := os.ReadFile(cfg.AuthTokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read profiling auth token: %w", err)
+		}
+		token = strings.TrimSpace(string(bz))
+	}
+
+	labels := make(map[string]string, len(cfg.Tags)+2)
+	if hostname, err := os.Hostname(); err == nil {
+		labels["hostname"] = hostname
+	}
+	if version != "" {
+		labels["version"] = version
+	}
+	for k, v := range cfg.Tags {
+		labels[k] = v
+	}
+
+	p := &Profiler{
+		logger:   logger,
+		cfg:      cfg,
+		health:   health,
+		interval: interval,
+		token:    token,
+		labels:   labels,
+		client:   &http.Client{Timeout: profileUploadTimeout},
+	}
+	p.BaseService = *cometservice.NewBaseService(logger, "Profiler", p)
+	return p, nil
+}
+
+func (p *Profiler) OnStart() error {
+	// mutex and block profiles are empty unless sampling is enabled.
+	if p.cfg.hasProfile(ProfileMutex) {
+		runtime.SetMutexProfileFraction(profileMutexFraction)
+	}
+	if p.cfg.hasProfile(ProfileBlock) {
+		runtime.SetBlockProfileRate(profileBlockRate)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	p.stopped.Add(1)
+	go func() {
+		defer p.stopped.Done()
+		for ctx.Err() == nil {
+			p.profile(ctx)
+		}
+	}()
+
+	p.logger.Info("Pushing profiles", "url", p.cfg.URL, "interval", p.interval, "profiles", p.cfg.profiles())
+	return nil
+}
+
+func (p *Profiler) OnStop() {
+	p.cancel()
+	p.stopped.Wait()
+}
+
+// role returns the role of the signer.
+func (p *Profiler) role() string {
+	if p.health == nil || p.health.threshold == nil {
+		return ProfileRoleSingle
+	}
+	if p.health.threshold.leader.IsLeader() {
+		return ProfileRoleLeader
+	}
+	return ProfileRoleFollower
+}
+
+// profile collects the profiles of an interval and pushes them.
+func (p *Profiler) profile(ctx context.Context) {
+	from := time.Now()
+
+	var cpu *bytes.Buffer
+	if p.cfg.hasProfile(ProfileCPU) {
+		cpu = new(bytes.Buffer)
+		if err := pprof.StartCPUProfile(cpu); err != nil {
+			// e.g. an operator is profiling through the debug server.
+			p.logger.Error("Failed to start CPU profile", "error", err)
+			cpu = nil
+		}
+	}
+
+	timer := time.NewTimer(p.interval)
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		if cpu != nil {
+			pprof.StopCPUProfile()
+		}
+		return
+	case <-timer.C:
+	}
+	if cpu != nil {
+		pprof.StopCPUProfile()
+	}
+	until := time.Now()
+
+	labels := make(map[string]string, len(p.labels)+1)
+	for k, v := range p.labels {
+		labels[k] = v
+	}
+	labels["role"] = p.role()
+
+	for _, profile := range p.cfg.profiles() {
+		var bz []byte
+		if profile == ProfileCPU {
+			if cpu == nil {
+				continue
+			}
+			bz = cpu.Bytes()
+		} else {
+			var buf bytes.Buffer
+			if err := pprof.Lookup(profile).WriteTo(&buf, 0); err != nil {
+				p.logger.Error("Failed to collect profile", "profile", profile, "error", err)
+				continue
+			}
+			bz = buf.Bytes()
+		}
+		if err := p.push(ctx, profile, labels, from, until, bz); err != nil && !errors.Is(err, context.Canceled) {
+			totalProfilePushFailures.WithLabelValues(profile).Inc()
+			p.logger.Error("Failed to push profile", "profile", profile, "url", p.cfg.URL, "error", err)
+		}
+	}
+}
+
+// push sends a profile in the configured format.
+func (p *Profiler) push(
+	ctx context.Context,
+	profile string,
+	labels map[string]string,
+	from, until time.Time,
+	bz []byte,
+) error {
+	var (
+		target      string
+		body        io.Reader
+		contentType string
+	)
+	switch p.cfg.format() {
+	case ProfilingFormatPyroscope:
+		q := url.Values{}
+		q.Set("name", pyroscopeName(p.cfg.appName()+"."+profile, labels))
+		q.Set("from", strconv.FormatInt(from.Unix(), 10))
+		q.Set("until", strconv.FormatInt(until.Unix(), 10))
+		q.Set("format", "pprof")
+		q.Set("spyName", "gospy")
+		if profile == ProfileCPU {
+			q.Set("sampleRate", "100")
+		}
+		target = strings.TrimSuffix(p.cfg.URL, "/") + "/ingest?" + q.Encode()
+
+		var buf bytes.Buffer
+		w := multipart.NewWriter(&buf)
+		fw, err := w.CreateFormFile("profile", "profile.pprof")
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(bz); err != nil {
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+		body, contentType = &buf, w.FormDataContentType()
+	default:
+		u, err := url.Parse(p.cfg.URL)
+		if err != nil {
+			return err
+		}
+		q := u.Query()
+		q.Set("app", p.cfg.appName())
+		q.Set("profile", profile)
+		q.Set("from", strconv.FormatInt(from.Unix(), 10))
+		q.Set("until", strconv.FormatInt(until.Unix(), 10))
+		for k, v := range labels {
+			q.Set(k, v)
+		}
+		u.RawQuery = q.Encode()
+		target = u.String()
+		body, contentType = bytes.NewReader(bz), "application/octet-stream"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+	res, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("profiling server responded with %s", res.Status)
+	}
+	return nil
+}
+
+// pyroscopeName returns the name of a profile with its labels in the Pyroscope format, app{k=v,...}.
+func pyroscopeName(name string, labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(name)
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(strings.NewReplacer("{", "_", "}", "_", ",", "_", "=", "_").Replace(labels[k]))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
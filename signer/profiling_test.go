@@ -0,0 +1,104 @@
+package signer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfilingConfigValidate(t *testing.T) {
+	var cfg *ProfilingConfig
+	require.NoError(t, cfg.Validate())
+
+	require.NoError(t, (&ProfilingConfig{URL: "http://pyroscope:4040"}).Validate())
+	require.NoError(t, (&ProfilingConfig{
+		Format:   ProfilingFormatPprof,
+		URL:      "https://profiles.example.com/upload",
+		Interval: "30s",
+		Profiles: []string{ProfileCPU, ProfileMutex},
+		Tags:     map[string]string{"cluster": "mainnet"},
+	}).Validate())
+	require.Error(t, (&ProfilingConfig{}).Validate())
+	require.Error(t, (&ProfilingConfig{URL: "http://pyroscope:4040", Format: "parca"}).Validate())
+	require.Error(t, (&ProfilingConfig{URL: "http://pyroscope:4040", Interval: "100ms"}).Validate())
+	require.Error(t, (&ProfilingConfig{URL: "http://pyroscope:4040", Profiles: []string{"threadcreate"}}).Validate())
+	require.Error(t, (&ProfilingConfig{URL: "http://pyroscope:4040", Tags: map[string]string{"a=b": "c"}}).Validate())
+}
+
+func TestPyroscopeName(t *testing.T) {
+	require.Equal(t,
+		"horcrux.cpu{hostname=cosigner-1,role=leader,version=v3_1}",
+		pyroscopeName("horcrux.cpu", map[string]string{"role": "leader", "hostname": "cosigner-1", "version": "v3,1"}),
+	)
+}
+
+func TestProfilerPush(t *testing.T) {
+	for _, format := range []string{ProfilingFormatPyroscope, ProfilingFormatPprof} {
+		format := format
+		t.Run(format, func(t *testing.T) {
+			var mu sync.Mutex
+			var requests []*http.Request
+			var bodies [][]byte
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body []byte
+				if format == ProfilingFormatPyroscope {
+					f, _, err := r.FormFile("profile")
+					require.NoError(t, err)
+					body, err = io.ReadAll(f)
+					require.NoError(t, err)
+				} else {
+					var err error
+					body, err = io.ReadAll(r.Body)
+					require.NoError(t, err)
+				}
+				mu.Lock()
+				requests = append(requests, r)
+				bodies = append(bodies, body)
+				mu.Unlock()
+			}))
+			defer srv.Close()
+
+			p, err := NewProfiler(cometlog.NewNopLogger(), &ProfilingConfig{
+				Format: format,
+				URL:    srv.URL,
+				Tags:   map[string]string{"cluster": "testnet"},
+			}, nil, "v3.3.0")
+			require.NoError(t, err)
+			p.interval = 50 * time.Millisecond
+			p.token = "token"
+
+			p.profile(context.Background())
+
+			mu.Lock()
+			defer mu.Unlock()
+			require.Len(t, requests, 2)
+			for i, profile := range []string{ProfileCPU, ProfileHeap} {
+				r := requests[i]
+				require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+				require.NotEmpty(t, bodies[i])
+				q := r.URL.Query()
+				if format == ProfilingFormatPyroscope {
+					require.Equal(t, "/ingest", r.URL.Path)
+					require.Equal(t, "pprof", q.Get("format"))
+					name := q.Get("name")
+					require.True(t, strings.HasPrefix(name, "horcrux."+profile+"{"), name)
+					require.Contains(t, name, "role=single")
+					require.Contains(t, name, "cluster=testnet")
+					require.Contains(t, name, "version=v3.3.0")
+				} else {
+					require.Equal(t, profile, q.Get("profile"))
+					require.Equal(t, "single", q.Get("role"))
+					require.Equal(t, "testnet", q.Get("cluster"))
+				}
+			}
+		})
+	}
+}
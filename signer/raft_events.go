@@ -2,13 +2,21 @@ package signer
 
 import (
 	"encoding/json"
+	"strings"
 )
 
 const (
 	raftEventLSS = "LSS"
+
+	// raftEventFeatureFlagPrefix is followed by the flag name so that the latest
+	// override for each flag is retained in snapshots.
+	raftEventFeatureFlagPrefix = "FF/"
 )
 
 func (f *fsm) getEventHandler(key string) func(string) {
+	if strings.HasPrefix(key, raftEventFeatureFlagPrefix) {
+		return f.handleFeatureFlagEvent
+	}
 	return map[string]func(string){
 		raftEventLSS: f.handleLSSEvent,
 	}[key]
@@ -40,3 +48,33 @@ func (f *fsm) handleLSSEvent(value string) {
 	_ = f.thresholdValidator.SaveLastSignedState(lss.ChainID, lss.SignStateConsensus)
 	_ = f.cosigner.SaveLastSignedState(lss.ChainID, lss.SignStateConsensus)
 }
+
+func (f *fsm) handleFeatureFlagEvent(value string) {
+	event := FeatureFlagEvent{}
+	if err := json.Unmarshal([]byte(value), &event); err != nil {
+		f.logger.Error(
+			"FeatureFlag Unmarshal Error",
+			"error", err,
+		)
+		return
+	}
+	if f.thresholdValidator == nil {
+		return
+	}
+	if err := event.Apply(f.thresholdValidator.FeatureFlags()); err != nil {
+		f.logger.Error(
+			"Error applying feature flag during raft replication",
+			"flag", event.Name,
+			"error", err,
+		)
+		return
+	}
+	f.logger.Info(
+		"Feature flag updated",
+		"flag", event.Name,
+		"enabled", event.Flag.Enabled,
+		"chain_ids", event.Flag.ChainIDs,
+		"percentage", event.Flag.Percentage,
+		"reset", event.Reset,
+	)
+}
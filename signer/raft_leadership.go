@@ -0,0 +1,98 @@
+package signer
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/raft"
+)
+
+// raftLeadershipTracker exports the raft leadership metrics from the leader observations of raft.
+type raftLeadershipTracker struct {
+	nodeID raft.ServerID
+
+	leader      raft.ServerID
+	leaderSince time.Time
+
+	// electionStart is when raft last lost its leader, zero while there is a leader.
+	electionStart time.Time
+}
+
+// newRaftLeadershipTracker returns a raftLeadershipTracker of the node, which starts without a leader.
+func newRaftLeadershipTracker(nodeID raft.ServerID, now time.Time) *raftLeadershipTracker {
+	raftLeaderID.Set(-1)
+	raftIsLeader.Set(0)
+	return &raftLeadershipTracker{
+		nodeID:        nodeID,
+		electionStart: now,
+	}
+}
+
+// observe records the leader observed at now. An empty leader means raft has no leader,
+// which raft reports before every election. It returns true if a different node became leader.
+func (t *raftLeadershipTracker) observe(leader raft.ServerID, now time.Time) bool {
+	if leader == "" {
+		if t.electionStart.IsZero() {
+			t.electionStart = now
+		}
+		raftLeaderID.Set(-1)
+		raftIsLeader.Set(0)
+		return false
+	}
+
+	if !t.electionStart.IsZero() {
+		timedRaftElection.Observe(now.Sub(t.electionStart).Seconds())
+		t.electionStart = time.Time{}
+	}
+
+	id, err := strconv.Atoi(string(leader))
+	if err != nil {
+		id = -1
+	}
+	raftLeaderID.Set(float64(id))
+	if leader == t.nodeID {
+		raftIsLeader.Set(1)
+	} else {
+		raftIsLeader.Set(0)
+	}
+
+	if leader == t.leader {
+		// re-elected.
+		return false
+	}
+	if t.leader != "" {
+		timedRaftLeadership.Observe(now.Sub(t.leaderSince).Seconds())
+		totalRaftLeaderChanges.Inc()
+	}
+	t.leader = leader
+	t.leaderSince = now
+	raftLeaderSince.Set(float64(now.Unix()))
+	return true
+}
+
+// observeLeadership registers a raft observer of leader changes and exports the raft leadership
+// metrics until the store is stopped.
+func (s *RaftStore) observeLeadership() {
+	ch := make(chan raft.Observation, 16)
+	observer := raft.NewObserver(ch, false, func(o *raft.Observation) bool {
+		_, ok := o.Data.(raft.LeaderObservation)
+		return ok
+	})
+	s.raft.RegisterObserver(observer)
+	tracker := newRaftLeadershipTracker(raft.ServerID(s.NodeID), time.Now())
+
+	go func() {
+		defer s.raft.DeregisterObserver(observer)
+		for {
+			select {
+			case <-s.Quit():
+				return
+			case o := <-ch:
+				leader := o.Data.(raft.LeaderObservation).LeaderID
+				if tracker.observe(leader, time.Now()) {
+					s.logger.Info("Raft leader changed", "leader", leader)
+				}
+			}
+		}
+	}()
+}
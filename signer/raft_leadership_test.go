@@ -0,0 +1,41 @@
+package signer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRaftLeadershipTracker(t *testing.T) {
+	start := time.Now()
+	tracker := newRaftLeadershipTracker("1", start)
+	require.Equal(t, float64(-1), testutil.ToFloat64(raftLeaderID))
+
+	changes := testutil.ToFloat64(totalRaftLeaderChanges)
+
+	// the first leader is elected.
+	require.True(t, tracker.observe("2", start.Add(time.Second)))
+	require.Equal(t, float64(2), testutil.ToFloat64(raftLeaderID))
+	require.Equal(t, float64(0), testutil.ToFloat64(raftIsLeader))
+	require.Equal(t, changes, testutil.ToFloat64(totalRaftLeaderChanges))
+
+	// the leader is re-elected.
+	require.False(t, tracker.observe("", start.Add(time.Minute)))
+	require.Equal(t, float64(-1), testutil.ToFloat64(raftLeaderID))
+	require.False(t, tracker.observe("2", start.Add(time.Minute+time.Second)))
+	require.Equal(t, changes, testutil.ToFloat64(totalRaftLeaderChanges))
+
+	// leadership moves to this node.
+	require.False(t, tracker.observe("", start.Add(time.Hour)))
+	now := start.Add(time.Hour + 2*time.Second)
+	require.True(t, tracker.observe("1", now))
+	require.Equal(t, float64(1), testutil.ToFloat64(raftLeaderID))
+	require.Equal(t, float64(1), testutil.ToFloat64(raftIsLeader))
+	require.Equal(t, float64(now.Unix()), testutil.ToFloat64(raftLeaderSince))
+	require.Equal(t, changes+1, testutil.ToFloat64(totalRaftLeaderChanges))
+
+	require.Equal(t, 1, testutil.CollectAndCount(timedRaftElection))
+	require.Equal(t, 1, testutil.CollectAndCount(timedRaftLeadership))
+}
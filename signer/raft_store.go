@@ -11,11 +11,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -32,12 +32,82 @@ import (
 	"google.golang.org/grpc/reflection"
 )
 
-var _ Leader = (*RaftStore)(nil)
+var _ ClusterLeader = (*RaftStore)(nil)
 
 const (
 	retainSnapshotCount = 2
+
+	// minRaftTimeout is the shortest timeout accepted by raft.
+	minRaftTimeout = 5 * time.Millisecond
 )
 
+// RaftElectionConfig is the on disk config format for the timing of the raft leader election,
+// e.g. to avoid spurious elections between distant cosigners or to fail over faster on a LAN.
+type RaftElectionConfig struct {
+	// HeartbeatTimeout is how long a follower goes without contact from the leader before it starts
+	// an election. The leader sends heartbeats at a tenth of it. Defaults to raftTimeout.
+	HeartbeatTimeout string `yaml:"heartbeatTimeout,omitempty"`
+
+	// ElectionTimeout is how long a candidate goes without winning an election or hearing from a
+	// leader before it starts another election. Defaults to heartbeatTimeout.
+	ElectionTimeout string `yaml:"electionTimeout,omitempty"`
+
+	// LeaderLeaseTimeout is how long the leader goes without contact from a quorum of the cosigners
+	// before it steps down. Defaults to half of heartbeatTimeout.
+	LeaderLeaseTimeout string `yaml:"leaderLeaseTimeout,omitempty"`
+}
+
+// RaftTimings are the timeouts of the raft leader election.
+type RaftTimings struct {
+	HeartbeatTimeout   time.Duration
+	ElectionTimeout    time.Duration
+	LeaderLeaseTimeout time.Duration
+}
+
+// defaultRaftTimings returns the timings derived from the heartbeat timeout.
+func defaultRaftTimings(heartbeatTimeout time.Duration) RaftTimings {
+	return RaftTimings{
+		HeartbeatTimeout:   heartbeatTimeout,
+		ElectionTimeout:    heartbeatTimeout,
+		LeaderLeaseTimeout: heartbeatTimeout / 2,
+	}
+}
+
+// timings returns the timings of the raft leader election, with the heartbeat timeout defaulting
+// to the raft timeout.
+func (cfg *RaftElectionConfig) timings(raftTimeout time.Duration) (RaftTimings, error) {
+	if cfg == nil {
+		cfg = &RaftElectionConfig{}
+	}
+	heartbeatTimeout, err := parseDurationOrDefault(cfg.HeartbeatTimeout, raftTimeout)
+	if err != nil {
+		return RaftTimings{}, fmt.Errorf("invalid raft heartbeatTimeout: %w", err)
+	}
+	t := defaultRaftTimings(heartbeatTimeout)
+	if t.ElectionTimeout, err = parseDurationOrDefault(cfg.ElectionTimeout, t.ElectionTimeout); err != nil {
+		return RaftTimings{}, fmt.Errorf("invalid raft electionTimeout: %w", err)
+	}
+	if t.LeaderLeaseTimeout, err = parseDurationOrDefault(cfg.LeaderLeaseTimeout, t.LeaderLeaseTimeout); err != nil {
+		return RaftTimings{}, fmt.Errorf("invalid raft leaderLeaseTimeout: %w", err)
+	}
+
+	if min(t.HeartbeatTimeout, t.ElectionTimeout, t.LeaderLeaseTimeout) < minRaftTimeout {
+		return RaftTimings{}, fmt.Errorf(
+			"raft heartbeatTimeout (%s), electionTimeout (%s) and leaderLeaseTimeout (%s) must be at least %s",
+			t.HeartbeatTimeout, t.ElectionTimeout, t.LeaderLeaseTimeout, minRaftTimeout,
+		)
+	}
+	if t.ElectionTimeout < t.HeartbeatTimeout {
+		return RaftTimings{}, fmt.Errorf("raft electionTimeout (%s) must not be less than heartbeatTimeout (%s)",
+			t.ElectionTimeout, t.HeartbeatTimeout)
+	}
+	if t.LeaderLeaseTimeout > t.HeartbeatTimeout {
+		return RaftTimings{}, fmt.Errorf("raft leaderLeaseTimeout (%s) must not be greater than heartbeatTimeout (%s)",
+			t.LeaderLeaseTimeout, t.HeartbeatTimeout)
+	}
+	return t, nil
+}
+
 type command struct {
 	Op    string `json:"op,omitempty"`
 	Key   string `json:"key,omitempty"`
@@ -52,7 +122,9 @@ type RaftStore struct {
 	RaftDir     string
 	RaftBind    string
 	RaftTimeout time.Duration
+	Timings     RaftTimings
 	Cosigners   []Cosigner
+	cosignersMu sync.RWMutex
 
 	mu sync.Mutex
 	m  map[string]string // The key-value store for the system.
@@ -62,6 +134,7 @@ type RaftStore struct {
 	logger             log.Logger
 	cosigner           *LocalCosigner
 	thresholdValidator *ThresholdValidator
+	transport          CosignerTransport
 }
 
 // New returns a new Store.
@@ -87,14 +160,34 @@ func (s *RaftStore) SetThresholdValidator(thresholdValidator *ThresholdValidator
 	s.thresholdValidator = thresholdValidator
 }
 
+// SetTimings sets the timeouts of the raft leader election, which default to those derived from the raft timeout.
+func (s *RaftStore) SetTimings(timings RaftTimings) {
+	s.Timings = timings
+}
+
+func (s *RaftStore) timings() RaftTimings {
+	if s.Timings == (RaftTimings{}) {
+		return defaultRaftTimings(s.RaftTimeout)
+	}
+	return s.Timings
+}
+
+// SetTransport sets the transport used for raft and cosigner gRPC connections.
+func (s *RaftStore) SetTransport(transport CosignerTransport) {
+	s.transport = transport
+}
+
+func (s *RaftStore) cosignerTransport() CosignerTransport {
+	if s.transport == nil {
+		return TCPCosignerTransport{}
+	}
+	return s.transport
+}
+
 func (s *RaftStore) init() error {
 	host := p2pURLToRaftAddress(s.RaftBind)
-	_, port, err := net.SplitHostPort(host)
-	if err != nil {
-		return fmt.Errorf("failed to parse local address: %s, %v", host, err)
-	}
-	s.logger.Info("Local Raft Listening", "port", port)
-	sock, err := net.Listen("tcp", fmt.Sprintf(":%s", port))
+	s.logger.Info("Local Raft Listening", "address", host)
+	sock, err := s.cosignerTransport().Listen(host)
 	if err != nil {
 		return err
 	}
@@ -102,7 +195,7 @@ func (s *RaftStore) init() error {
 	if err != nil {
 		return err
 	}
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(cosignerKeepaliveServerOption())
 	proto.RegisterCosignerServer(grpcServer, NewCosignerGRPCServer(s.cosigner, s.thresholdValidator, s))
 	transportManager.Register(grpcServer)
 	leaderhealth.Setup(s.raft, grpcServer, []string{"Leader"})
@@ -114,6 +207,7 @@ func (s *RaftStore) init() error {
 // OnStart starts the raft server
 func (s *RaftStore) OnStart() error {
 	go func() {
+		defer ReportPanic()
 		err := s.init()
 		if err != nil {
 			panic(err)
@@ -139,9 +233,10 @@ func (s *RaftStore) Open() (*raftgrpctransport.Manager, error) {
 	config := raft.DefaultConfig()
 	config.LocalID = raft.ServerID(s.NodeID)
 	config.LogLevel = "ERROR"
-	config.ElectionTimeout = s.RaftTimeout
-	config.HeartbeatTimeout = s.RaftTimeout
-	config.LeaderLeaseTimeout = s.RaftTimeout / 2
+	timings := s.timings()
+	config.ElectionTimeout = timings.ElectionTimeout
+	config.HeartbeatTimeout = timings.HeartbeatTimeout
+	config.LeaderLeaseTimeout = timings.LeaderLeaseTimeout
 
 	// Create the snapshot store. This allows the Raft to truncate the log.
 	snapshots, err := raft.NewFileSnapshotStore(s.RaftDir, retainSnapshotCount, os.Stderr)
@@ -167,6 +262,8 @@ func (s *RaftStore) Open() (*raftgrpctransport.Manager, error) {
 	// Setup Raft communication.
 	transportManager := raftgrpctransport.New(raftAddress, []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(s.cosignerTransport().DialContext),
+		cosignerKeepaliveDialOption(),
 	})
 
 	// Instantiate the Raft systems.
@@ -175,6 +272,7 @@ func (s *RaftStore) Open() (*raftgrpctransport.Manager, error) {
 		return nil, fmt.Errorf("new raft: %s", err)
 	}
 	s.raft = ra
+	s.observeLeadership()
 
 	configuration := raft.Configuration{
 		Servers: []raft.Server{
@@ -184,7 +282,7 @@ func (s *RaftStore) Open() (*raftgrpctransport.Manager, error) {
 			},
 		},
 	}
-	for _, c := range s.Cosigners {
+	for _, c := range s.peers() {
 		configuration.Servers = append(configuration.Servers, raft.Server{
 			ID:      raft.ServerID(fmt.Sprint(c.GetID())),
 			Address: raft.ServerAddress(p2pURLToRaftAddress(c.GetAddress())),
@@ -284,6 +382,58 @@ func (s *RaftStore) Join(nodeID, addr string) error {
 	return nil
 }
 
+func (s *RaftStore) peers() []Cosigner {
+	s.cosignersMu.RLock()
+	defer s.cosignersMu.RUnlock()
+	return s.Cosigners
+}
+
+// SetPeers changes the peer cosigners of the raft cluster. The leader adds the added peers to the
+// raft configuration, moves the peers with a new address and removes the peers that are gone,
+// and the other cosigners follow the raft configuration through the raft log.
+func (s *RaftStore) SetPeers(peers []Cosigner) error {
+	s.cosignersMu.Lock()
+	s.Cosigners = peers
+	s.cosignersMu.Unlock()
+
+	if !s.IsLeader() {
+		return nil
+	}
+
+	configFuture := s.raft.GetConfiguration()
+	if err := configFuture.Error(); err != nil {
+		return fmt.Errorf("failed to get raft configuration: %w", err)
+	}
+	servers := make(map[raft.ServerID]raft.ServerAddress)
+	for _, srv := range configFuture.Configuration().Servers {
+		servers[srv.ID] = srv.Address
+	}
+
+	ids := make(map[raft.ServerID]bool, len(peers))
+	for _, c := range peers {
+		id := raft.ServerID(fmt.Sprint(c.GetID()))
+		ids[id] = true
+		address := raft.ServerAddress(p2pURLToRaftAddress(c.GetAddress()))
+		if servers[id] == address {
+			continue
+		}
+		s.logger.Info("Adding cosigner to raft cluster", "id", id, "address", address)
+		if err := s.Join(string(id), string(address)); err != nil {
+			return fmt.Errorf("error adding cosigner %s to raft cluster: %w", id, err)
+		}
+	}
+	for id, address := range servers {
+		if id == raft.ServerID(s.NodeID) || ids[id] {
+			continue
+		}
+		s.logger.Info("Removing cosigner from raft cluster", "id", id, "address", address)
+		if err := s.raft.RemoveServer(id, 0, 0).Error(); err != nil {
+			return fmt.Errorf("error removing cosigner %s from raft cluster: %w", id, err)
+		}
+	}
+	return nil
+}
+
 func (s *RaftStore) IsLeader() bool {
 	if s == nil || s.raft == nil {
 		return false
@@ -291,6 +441,15 @@ func (s *RaftStore) IsLeader() bool {
 	return s.raft.State() == raft.Leader
 }
 
+// LeaderTerm returns the current raft term, which is greater for later elections.
+func (s *RaftStore) LeaderTerm() uint64 {
+	if s == nil || s.raft == nil {
+		return 0
+	}
+	term, _ := strconv.ParseUint(s.raft.Stats()["term"], 10, 64)
+	return term
+}
+
 func (s *RaftStore) GetLeader() int {
 	if s == nil || s.raft == nil {
 		return -1
@@ -310,6 +469,40 @@ func (s *RaftStore) ShareSigned(lss ChainSignStateConsensus) error {
 	return s.Emit(raftEventLSS, lss)
 }
 
+// TransferLeadership transfers the raft leadership to the cosigner with the shard ID, or to
+// the next candidate if the shard ID is empty.
+func (s *RaftStore) TransferLeadership(shardID string) (string, string) {
+	if s.raft.State() != raft.Leader {
+		return "", ""
+	}
+	if shardID != "" {
+		for _, c := range s.peers() {
+			if fmt.Sprint(c.GetID()) == shardID {
+				raftAddress := p2pURLToRaftAddress(c.GetAddress())
+				fmt.Printf("Transferring leadership to ID: %s - Address: %s\n", shardID, raftAddress)
+				s.raft.LeadershipTransferToServer(raft.ServerID(shardID), raft.ServerAddress(raftAddress))
+				return shardID, raftAddress
+			}
+		}
+	}
+	fmt.Printf("Transferring leadership to next candidate\n")
+	s.raft.LeadershipTransfer()
+	return "", ""
+}
+
+// SetFeatureFlag replicates a feature flag change to all cosigners.
+func (s *RaftStore) SetFeatureFlag(event FeatureFlagEvent) error {
+	if event.Name == "" {
+		return fmt.Errorf("feature flag name cannot be empty")
+	}
+	if !event.Reset {
+		if err := event.Flag.Validate(); err != nil {
+			return fmt.Errorf("invalid feature flag %s: %w", event.Name, err)
+		}
+	}
+	return s.Emit(raftEventFeatureFlagPrefix+event.Name, event)
+}
+
 type fsm RaftStore
 
 // Apply applies a Raft log entry to the key-value store.
@@ -354,6 +547,13 @@ func (f *fsm) Restore(rc io.ReadCloser) error {
 	// Set the state from the snapshot, no lock required according to
 	// Hashicorp docs.
 	f.m = o
+
+	// Retained events are not replayed from the log once compacted into a snapshot.
+	for key, value := range o {
+		if strings.HasPrefix(key, raftEventFeatureFlagPrefix) {
+			f.handleFeatureFlagEvent(value)
+		}
+	}
 	return nil
 }
 
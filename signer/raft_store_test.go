@@ -48,7 +48,7 @@ func Test_StoreInMemOpenSingleNode(t *testing.T) {
 		RaftBind:    "127.0.0.1:0",
 		RaftTimeout: 1 * time.Second,
 		m:           make(map[string]string),
-		logger:      nil,
+		logger:      log.NewNopLogger(),
 		cosigner:    cosigner,
 	}
 
@@ -87,3 +87,36 @@ func Test_StoreInMemOpenSingleNode(t *testing.T) {
 		t.Fatalf("key has wrong value: %s", value)
 	}
 }
+
+func TestRaftTimings(t *testing.T) {
+	cfg := &ThresholdModeConfig{RaftTimeout: "500ms"}
+	timings, err := cfg.RaftTimings()
+	require.NoError(t, err)
+	require.Equal(t, RaftTimings{
+		HeartbeatTimeout:   500 * time.Millisecond,
+		ElectionTimeout:    500 * time.Millisecond,
+		LeaderLeaseTimeout: 250 * time.Millisecond,
+	}, timings)
+
+	// the election and leader lease timeouts default to those derived from the heartbeat timeout.
+	cfg.LeaderElection = &LeaderElectionConfig{Raft: &RaftElectionConfig{HeartbeatTimeout: "2s", ElectionTimeout: "3s"}}
+	timings, err = cfg.RaftTimings()
+	require.NoError(t, err)
+	require.Equal(t, RaftTimings{
+		HeartbeatTimeout:   2 * time.Second,
+		ElectionTimeout:    3 * time.Second,
+		LeaderLeaseTimeout: time.Second,
+	}, timings)
+
+	for _, raftCfg := range []RaftElectionConfig{
+		{HeartbeatTimeout: "soon"},
+		{HeartbeatTimeout: "1ms"},
+		{HeartbeatTimeout: "1s", ElectionTimeout: "500ms"},
+		{HeartbeatTimeout: "1s", LeaderLeaseTimeout: "2s"},
+	} {
+		raftCfg := raftCfg
+		cfg.LeaderElection.Raft = &raftCfg
+		_, err = cfg.RaftTimings()
+		require.Error(t, err, raftCfg)
+	}
+}
@@ -4,13 +4,18 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"path"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	cometcrypto "github.com/cometbft/cometbft/crypto"
 	"github.com/google/uuid"
 	"github.com/strangelove-ventures/horcrux/signer/proto"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
 var _ Cosigner = &RemoteCosigner{}
@@ -20,12 +25,24 @@ type RemoteCosigner struct {
 	id      int
 	address string
 
+	conn   *grpc.ClientConn
 	client proto.CosignerClient
+
+	// protocolVersion is the negotiated cosigner protocol version, 0 if not yet negotiated.
+	protocolVersion atomic.Uint32
+
+	// build is the build of the remote cosigner reported during the last handshake, nil before.
+	build atomic.Pointer[CosignerBuild]
+
+	// nonceStream requests nonces from a remote cosigner on a protocol version with the StreamNonces
+	// RPC, nil until the first request or once it broke.
+	nonceStream   *nonceStream
+	nonceStreamMu sync.Mutex
 }
 
 // NewRemoteCosigner returns a newly initialized RemoteCosigner
-func NewRemoteCosigner(id int, address string) (*RemoteCosigner, error) {
-	client, err := getGRPCClient(address)
+func NewRemoteCosigner(id int, address string, transport CosignerTransport) (*RemoteCosigner, error) {
+	conn, err := getGRPCConn(id, address, transport)
 	if err != nil {
 		return nil, err
 	}
@@ -33,7 +50,8 @@ func NewRemoteCosigner(id int, address string) (*RemoteCosigner, error) {
 	cosigner := &RemoteCosigner{
 		id:      id,
 		address: address,
-		client:  client,
+		conn:    conn,
+		client:  proto.NewCosignerClient(conn),
 	}
 
 	return cosigner, nil
@@ -63,7 +81,83 @@ func (cosigner *RemoteCosigner) VerifySignature(_ string, _, _ []byte) bool {
 	return false
 }
 
-func getGRPCClient(address string) (proto.CosignerClient, error) {
+// ProtocolVersion returns the negotiated cosigner protocol version, or 0 if the
+// handshake has not completed since the last connection failure.
+func (cosigner *RemoteCosigner) ProtocolVersion() uint32 {
+	return cosigner.protocolVersion.Load()
+}
+
+// Build returns the build of the remote cosigner reported during the last handshake, and false
+// before the first handshake.
+func (cosigner *RemoteCosigner) Build() (CosignerBuild, bool) {
+	build := cosigner.build.Load()
+	if build == nil {
+		return CosignerBuild{}, false
+	}
+	return *build, true
+}
+
+// VersionSkew returns how far apart the versions of this cosigner and the remote cosigner are,
+// unknown before the first handshake.
+func (cosigner *RemoteCosigner) VersionSkew() VersionSkew {
+	build, ok := cosigner.Build()
+	if !ok {
+		return VersionSkewUnknown
+	}
+	return versionSkew(localCosignerBuild(), build)
+}
+
+// Handshake exchanges supported protocol versions and builds with the remote cosigner
+// and records the highest version supported by both.
+// Peers which predate the handshake are assumed to speak the legacy protocol.
+func (cosigner *RemoteCosigner) Handshake(ctx context.Context, localID int) (uint32, error) {
+	res, err := cosigner.client.Handshake(ctx, &proto.HandshakeRequest{
+		Id:                 int32(localID),
+		MinProtocolVersion: MinCosignerProtocolVersion,
+		MaxProtocolVersion: CosignerProtocolVersion,
+		SoftwareVersion:    SoftwareVersion,
+		Commit:             SoftwareCommit,
+	})
+	remoteMin, remoteMax := legacyCosignerProtocolVersion, legacyCosignerProtocolVersion
+	var build CosignerBuild
+	if err != nil {
+		if status.Code(err) != codes.Unimplemented {
+			return 0, err
+		}
+	} else {
+		remoteMin, remoteMax = res.MinProtocolVersion, res.MaxProtocolVersion
+		build = CosignerBuild{Version: res.SoftwareVersion, Commit: res.Commit}
+	}
+
+	cosigner.build.Store(&build)
+	cosignerVersionSkew.WithLabelValues(fmt.Sprint(cosigner.id)).Set(cosigner.VersionSkew().metric())
+
+	protocolSkew.WithLabelValues(fmt.Sprint(cosigner.id)).Set(float64(CosignerProtocolVersion) - float64(remoteMax))
+
+	version, err := negotiateProtocolVersion(MinCosignerProtocolVersion, CosignerProtocolVersion, remoteMin, remoteMax)
+	if err != nil {
+		cosigner.protocolVersion.Store(0)
+		protocolVersion.WithLabelValues(fmt.Sprint(cosigner.id)).Set(0)
+		totalProtocolIncompatible.WithLabelValues(fmt.Sprint(cosigner.id)).Inc()
+		return 0, err
+	}
+
+	cosigner.protocolVersion.Store(version)
+	protocolVersion.WithLabelValues(fmt.Sprint(cosigner.id)).Set(float64(version))
+	return version, nil
+}
+
+// resetConnectBackoff dials the remote cosigner again at once if it is not connected, instead of
+// waiting for the backoff of the previous attempts, which grows up to minutes. The host name of the
+// cosigner is resolved again, so that a cosigner that moved to a new IP is reconnected as soon as
+// its DNS record is updated.
+func (cosigner *RemoteCosigner) resetConnectBackoff() {
+	cosigner.conn.ResetConnectBackoff()
+}
+
+// getGRPCConn returns the connection to the cosigner. gRPC passes the host name of the address to
+// the dialer of the transport unresolved, so it is resolved again on every reconnect.
+func getGRPCConn(id int, address string, transport CosignerTransport) (*grpc.ClientConn, error) {
 	var grpcAddress string
 	url, err := url.Parse(address)
 	if err != nil {
@@ -71,37 +165,100 @@ func getGRPCClient(address string) (proto.CosignerClient, error) {
 	} else {
 		grpcAddress = url.Host
 	}
-	conn, err := grpc.Dial(grpcAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.Dial(grpcAddress,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(transport.DialContext),
+		grpc.WithUnaryInterceptor(cosignerMetricsInterceptor(id)),
+		cosignerKeepaliveDialOption(),
+	)
 	if err != nil {
 		return nil, err
 	}
-	return proto.NewCosignerClient(conn), nil
+	return conn, nil
 }
 
+// cosignerMetricsInterceptor records the latency, timeouts and errors of the requests
+// to the cosigner with the ID.
+func cosignerMetricsInterceptor(id int) grpc.UnaryClientInterceptor {
+	peerID := fmt.Sprint(id)
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		recordCosignerRequest(peerID, path.Base(method), start, err)
+		return err
+	}
+}
+
+// recordCosignerRequest records the latency and outcome of the request of the method, started at
+// start, to the cosigner with the peer ID.
+func recordCosignerRequest(peerID, method string, start time.Time, err error) {
+	switch status.Code(err) {
+	case codes.Canceled:
+		// canceled by the caller, e.g. on shutdown, which says nothing about the peer.
+		return
+	case codes.Unimplemented:
+		// peers running an older version, not a failure of the peer.
+	case codes.DeadlineExceeded:
+		totalCosignerRequestTimeouts.WithLabelValues(peerID, method).Inc()
+		totalCosignerRequestErrors.WithLabelValues(peerID, method).Inc()
+	case codes.OK:
+	default:
+		totalCosignerRequestErrors.WithLabelValues(peerID, method).Inc()
+	}
+	totalCosignerRequests.WithLabelValues(peerID, method).Inc()
+	timedCosignerRequest.WithLabelValues(peerID, method).Observe(time.Since(start).Seconds())
+}
+
+// GetNonces requests the nonces over the nonce stream of the cosigner if its protocol version has
+// the StreamNonces RPC, and with the unary GetNonces RPC otherwise.
 // Implements the cosigner interface
 func (cosigner *RemoteCosigner) GetNonces(
 	ctx context.Context,
 	uuids []uuid.UUID,
 ) (CosignerUUIDNoncesMultiple, error) {
-	us := make([][]byte, len(uuids))
-	for i, u := range uuids {
-		us[i] = make([]byte, 16)
-		copy(us[i], u[:])
+	if cosigner.ProtocolVersion() >= nonceStreamProtocolVersion {
+		return cosigner.streamNonces(ctx, uuids)
 	}
 	res, err := cosigner.client.GetNonces(ctx, &proto.GetNoncesRequest{
-		Uuids: us,
+		Uuids: uuidsToProto(uuids),
 	})
 	if err != nil {
 		return nil, err
 	}
-	out := make(CosignerUUIDNoncesMultiple, len(res.Nonces))
-	for i, nonces := range res.Nonces {
+	return uuidNoncesFromProto(res.Nonces), nil
+}
+
+func uuidsToProto(uuids []uuid.UUID) [][]byte {
+	us := make([][]byte, len(uuids))
+	for i, u := range uuids {
+		us[i] = make([]byte, 16)
+		copy(us[i], u[:])
+	}
+	return us
+}
+
+func uuidNoncesFromProto(nonces []*proto.UUIDNonce) CosignerUUIDNoncesMultiple {
+	out := make(CosignerUUIDNoncesMultiple, len(nonces))
+	for i, n := range nonces {
 		out[i] = &CosignerUUIDNonces{
-			UUID:   uuid.UUID(nonces.Uuid),
-			Nonces: CosignerNoncesFromProto(nonces.Nonces),
+			UUID:   uuid.UUID(n.Uuid),
+			Nonces: CosignerNoncesFromProto(n.Nonces),
 		}
 	}
-	return out, nil
+	return out
+}
+
+// PushNonces pushes the changes of the nonce cache of the leader to the remote cosigner.
+func (cosigner *RemoteCosigner) PushNonces(ctx context.Context, req *proto.PushNoncesRequest) error {
+	_, err := cosigner.client.PushNonces(ctx, req)
+	return err
 }
 
 // Implements the cosigner interface
@@ -125,6 +282,79 @@ func (cosigner *RemoteCosigner) SetNoncesAndSign(
 	}, nil
 }
 
+// GetSignState returns the greatest HRS the peer has signed for the chain,
+// or nil if it has no sign state for the chain.
+func (cosigner *RemoteCosigner) GetSignState(ctx context.Context, chainID string) (*HRSKey, error) {
+	return cosigner.GetSignStateOfKind(ctx, chainID, "")
+}
+
+// GetSignStateOfKind returns the HRS of the peer's sign state of the kind for the chain,
+// or nil if it has no such sign state for the chain.
+func (cosigner *RemoteCosigner) GetSignStateOfKind(
+	ctx context.Context,
+	chainID string,
+	kind SignStateKind,
+) (*HRSKey, error) {
+	res, err := cosigner.client.GetSignState(ctx, &proto.GetSignStateRequest{
+		ChainID: chainID,
+		Kind:    string(kind),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !res.Found {
+		return nil, nil
+	}
+	return &HRSKey{
+		Height: res.Height,
+		Round:  res.Round,
+		Step:   int8(res.Step),
+	}, nil
+}
+
+// GetStatus returns the status of the peer: its view of the leader and of its peers, its nonce cache
+// and the greatest HRS it signed of each chain.
+func (cosigner *RemoteCosigner) GetStatus(ctx context.Context) (*proto.GetStatusResponse, error) {
+	return cosigner.client.GetStatus(ctx, &proto.GetStatusRequest{})
+}
+
+// SetSigningPaused pauses signing of the cluster on the peer, or resumes signing. The peer only
+// serves the request with the auth of a peer cosigner.
+func (cosigner *RemoteCosigner) SetSigningPaused(
+	ctx context.Context,
+	paused bool,
+	auth *proto.CosignerAuth,
+) (*proto.SetSigningPausedResponse, error) {
+	return cosigner.client.SetSigningPaused(ctx, &proto.SetSigningPausedRequest{Paused: paused, Auth: auth})
+}
+
+// SetKillSwitch engages the kill switch of the peer with the reason, or re-arms signing with the
+// approvals of the re-arm of the cluster. The peer only serves the request with the auth of a peer
+// cosigner.
+func (cosigner *RemoteCosigner) SetKillSwitch(
+	ctx context.Context,
+	engaged bool,
+	reason string,
+	approvals []string,
+	auth *proto.CosignerAuth,
+) (*proto.SetKillSwitchResponse, error) {
+	return cosigner.client.SetKillSwitch(ctx, &proto.SetKillSwitchRequest{
+		Engaged:   engaged,
+		Reason:    reason,
+		Approvals: approvals,
+		Auth:      auth,
+	})
+}
+
+// TransferLeadership asks the peer, which must be the leader, to hand the leadership over to the
+// cosigner with the shard ID after the sign requests in flight, and to wait until it did.
+func (cosigner *RemoteCosigner) TransferLeadership(
+	ctx context.Context,
+	shardID string,
+) (*proto.TransferLeadershipResponse, error) {
+	return cosigner.client.TransferLeadership(ctx, &proto.TransferLeadershipRequest{LeaderID: shardID, Drain: true})
+}
+
 func (cosigner *RemoteCosigner) Sign(
 	ctx context.Context,
 	req CosignerSignBlockRequest,
@@ -0,0 +1,176 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/strangelove-ventures/horcrux/signer/proto"
+	"google.golang.org/grpc/status"
+)
+
+// nonceStreamProtocolVersion is the lowest cosigner protocol version with the StreamNonces RPC.
+const nonceStreamProtocolVersion uint32 = 3
+
+// nonceStream requests nonces from a remote cosigner over a long-lived bidirectional stream, so
+// that the leader tops up its nonce cache without the per-request overhead of the unary GetNonces
+// RPC. The requests are multiplexed on the stream and answered in any order.
+type nonceStream struct {
+	stream proto.Cosigner_StreamNoncesClient
+	cancel context.CancelFunc
+
+	// sendMu serializes the requests sent on the stream.
+	sendMu sync.Mutex
+
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[uint64]chan *proto.StreamNoncesResponse
+	// err is set once the stream broke, and fails the pending and later requests.
+	err error
+	// done is closed once the stream broke.
+	done chan struct{}
+}
+
+// openNonceStream opens a nonce stream to the remote cosigner, which lives until it is closed or
+// breaks.
+func (cosigner *RemoteCosigner) openNonceStream() (*nonceStream, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := cosigner.client.StreamNonces(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	s := &nonceStream{
+		stream:  stream,
+		cancel:  cancel,
+		pending: make(map[uint64]chan *proto.StreamNoncesResponse),
+		done:    make(chan struct{}),
+	}
+	go s.receive()
+	return s, nil
+}
+
+// receive hands the responses to the pending requests until the stream breaks.
+func (s *nonceStream) receive() {
+	for {
+		res, err := s.stream.Recv()
+		if err != nil {
+			s.close(err)
+			return
+		}
+		s.mu.Lock()
+		ch, ok := s.pending[res.Id]
+		delete(s.pending, res.Id)
+		s.mu.Unlock()
+		if ok {
+			// buffered, and the only response to the request.
+			ch <- res
+		}
+	}
+}
+
+// close breaks the stream with the error, which fails the pending and later requests.
+func (s *nonceStream) close(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return
+	}
+	s.err = err
+	s.pending = nil
+	close(s.done)
+	s.cancel()
+}
+
+// getNonces requests the nonces of the uuids over the stream.
+func (s *nonceStream) getNonces(ctx context.Context, uuids []uuid.UUID) (*proto.StreamNoncesResponse, error) {
+	ch := make(chan *proto.StreamNoncesResponse, 1)
+	s.mu.Lock()
+	if s.err != nil {
+		s.mu.Unlock()
+		return nil, s.err
+	}
+	s.nextID++
+	id := s.nextID
+	s.pending[id] = ch
+	s.mu.Unlock()
+
+	s.sendMu.Lock()
+	err := s.stream.Send(&proto.StreamNoncesRequest{Id: id, Uuids: uuidsToProto(uuids)})
+	s.sendMu.Unlock()
+	if err != nil {
+		s.close(err)
+		return nil, err
+	}
+
+	select {
+	case res := <-ch:
+		return res, nil
+	case <-s.done:
+		return nil, s.err
+	case <-ctx.Done():
+		s.mu.Lock()
+		if s.pending != nil {
+			delete(s.pending, id)
+		}
+		s.mu.Unlock()
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+// streamNonces requests the nonces of the uuids over the nonce stream of the cosigner, which is
+// opened again if it broke.
+func (cosigner *RemoteCosigner) streamNonces(
+	ctx context.Context,
+	uuids []uuid.UUID,
+) (CosignerUUIDNoncesMultiple, error) {
+	cosigner.nonceStreamMu.Lock()
+	s := cosigner.nonceStream
+	if s == nil {
+		var err error
+		if s, err = cosigner.openNonceStream(); err != nil {
+			cosigner.nonceStreamMu.Unlock()
+			return nil, err
+		}
+		cosigner.nonceStream = s
+		totalNonceStreamsOpened.WithLabelValues(fmt.Sprint(cosigner.id)).Inc()
+	}
+	cosigner.nonceStreamMu.Unlock()
+
+	start := time.Now()
+	res, err := s.getNonces(ctx, uuids)
+	if err == nil && res.Error != "" {
+		err = errors.New(res.Error)
+	}
+	recordCosignerRequest(fmt.Sprint(cosigner.id), "StreamNonces", start, err)
+	if err != nil {
+		if s.broken() {
+			cosigner.dropNonceStream(s)
+		}
+		return nil, err
+	}
+	return uuidNoncesFromProto(res.Nonces), nil
+}
+
+// broken returns true if the stream broke.
+func (s *nonceStream) broken() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// dropNonceStream forgets the nonce stream s of the cosigner, once it broke, so that the next request
+// opens a new one.
+func (cosigner *RemoteCosigner) dropNonceStream(s *nonceStream) {
+	cosigner.nonceStreamMu.Lock()
+	defer cosigner.nonceStreamMu.Unlock()
+	if cosigner.nonceStream == s {
+		cosigner.nonceStream = nil
+	}
+}
@@ -0,0 +1,168 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/strangelove-ventures/horcrux/signer/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCosignerMetricsInterceptor(t *testing.T) {
+	const (
+		peerID = "42"
+		method = "GetNonces"
+	)
+	interceptor := cosignerMetricsInterceptor(42)
+
+	call := func(err error) {
+		invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+			return err
+		}
+		require.Equal(t, err, interceptor(context.Background(), "/proto.Cosigner/"+method, nil, nil, nil, invoker))
+	}
+
+	requests := func() float64 { return testutil.ToFloat64(totalCosignerRequests.WithLabelValues(peerID, method)) }
+	errs := func() float64 { return testutil.ToFloat64(totalCosignerRequestErrors.WithLabelValues(peerID, method)) }
+	timeouts := func() float64 {
+		return testutil.ToFloat64(totalCosignerRequestTimeouts.WithLabelValues(peerID, method))
+	}
+
+	call(nil)
+	require.Equal(t, float64(1), requests())
+	require.Equal(t, float64(0), errs())
+
+	call(status.Error(codes.Unavailable, "connection refused"))
+	require.Equal(t, float64(2), requests())
+	require.Equal(t, float64(1), errs())
+	require.Equal(t, float64(0), timeouts())
+
+	call(status.Error(codes.DeadlineExceeded, "deadline exceeded"))
+	require.Equal(t, float64(3), requests())
+	require.Equal(t, float64(2), errs())
+	require.Equal(t, float64(1), timeouts())
+
+	// canceled requests are not attributed to the peer.
+	call(status.Error(codes.Canceled, "canceled"))
+	require.Equal(t, float64(3), requests())
+	require.Equal(t, float64(2), errs())
+
+	require.GreaterOrEqual(t, testutil.CollectAndCount(timedCosignerRequest), 1)
+}
+
+// dialRecorder is a CosignerTransport that records the addresses dialed and fails to connect.
+type dialRecorder struct {
+	mu     sync.Mutex
+	dialed []string
+}
+
+func (*dialRecorder) Listen(string) (net.Listener, error) { return nil, errors.New("not listening") }
+
+func (d *dialRecorder) DialContext(_ context.Context, address string) (net.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.dialed = append(d.dialed, address)
+	return nil, errors.New("no route to host")
+}
+
+func (d *dialRecorder) addresses() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.dialed...)
+}
+
+func TestRemoteCosignerRedial(t *testing.T) {
+	transport := &dialRecorder{}
+	cosigner, err := NewRemoteCosigner(2, "tcp://cosigner-2.horcrux:2222", transport)
+	require.NoError(t, err)
+
+	ping := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, err := cosigner.client.Ping(ctx, &proto.PingRequest{})
+		require.Error(t, err)
+	}
+
+	ping()
+	require.Eventually(t, func() bool { return len(transport.addresses()) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// the connection is dialed again at once, instead of after the backoff.
+	cosigner.resetConnectBackoff()
+	ping()
+	require.Eventually(t, func() bool { return len(transport.addresses()) >= 2 }, 5*time.Second, 10*time.Millisecond)
+
+	// the host name is passed to the transport unresolved, so it is resolved on every dial.
+	for _, address := range transport.addresses() {
+		require.Equal(t, "cosigner-2.horcrux:2222", address)
+	}
+}
+
+func TestRemoteCosignerStreamNonces(t *testing.T) {
+	lcs, _ := getTestLocalCosigners(t, 2, 3)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcServer := grpc.NewServer()
+	proto.RegisterCosignerServer(grpcServer, NewCosignerGRPCServer(lcs[1], nil, nil))
+	go func() { _ = grpcServer.Serve(ln) }()
+	defer grpcServer.Stop()
+
+	cosigner, err := NewRemoteCosigner(2, "tcp://"+ln.Addr().String(), TCPCosignerTransport{})
+	require.NoError(t, err)
+	version, err := cosigner.Handshake(context.Background(), 1)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, version, nonceStreamProtocolVersion)
+
+	opened := testutil.ToFloat64(totalNonceStreamsOpened.WithLabelValues("2"))
+	requests := testutil.ToFloat64(totalCosignerRequests.WithLabelValues("2", "StreamNonces"))
+
+	// the requests are multiplexed on a single stream.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			uuids := []uuid.UUID{uuid.New(), uuid.New()}
+			nonces, err := cosigner.GetNonces(ctx, uuids)
+			require.NoError(t, err)
+			require.Len(t, nonces, 2)
+			for j, n := range nonces {
+				require.Equal(t, uuids[j], n.UUID)
+				require.NotEmpty(t, n.Nonces)
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, opened+1, testutil.ToFloat64(totalNonceStreamsOpened.WithLabelValues("2")))
+	require.Equal(t, requests+10, testutil.ToFloat64(totalCosignerRequests.WithLabelValues("2", "StreamNonces")))
+
+	// a broken stream fails the request, and is opened again by the next one.
+	grpcServer.Stop()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.Eventually(t, func() bool {
+		_, err := cosigner.GetNonces(ctx, []uuid.UUID{uuid.New()})
+		return err != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	cosigner.nonceStreamMu.Lock()
+	require.Nil(t, cosigner.nonceStream)
+	cosigner.nonceStreamMu.Unlock()
+
+	// peers on an older protocol version are requested nonces with the unary RPC.
+	cosigner.protocolVersion.Store(nonceStreamProtocolVersion - 1)
+	_, err = cosigner.GetNonces(ctx, []uuid.UUID{uuid.New()})
+	require.Error(t, err)
+	cosigner.nonceStreamMu.Lock()
+	require.Nil(t, cosigner.nonceStream)
+	cosigner.nonceStreamMu.Unlock()
+}
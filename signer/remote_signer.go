@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"slices"
+	"sync"
 	"time"
 
 	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
@@ -33,28 +35,45 @@ type ReconnRemoteSigner struct {
 	cometservice.BaseService
 
 	address string
+	// validator is the validator the chain node signs with, empty for the default validator.
+	validator string
+	// chainID is the chain the chain node is restricted to sign, empty for any chain.
+	chainID string
 	privKey cometcryptoed25519.PrivKey
 	privVal PrivValidator
+	codecs  ChainSignBytesCodecs
+
+	// hold, if set, holds the connection to the chain node until it is closed.
+	hold <-chan struct{}
+
+	// limiter, if set, limits the rate of the sign requests, and connLimit the rate of this connection.
+	limiter   *SignRateLimiter
+	connLimit *tokenBucket
 
 	dialer net.Dialer
 }
 
 // NewReconnRemoteSigner return a ReconnRemoteSigner that will dial using the given
 // dialer and respond to any signature requests over the connection
-// using the given privVal.
+// using the given privVal. Sign bytes are computed with the codec selected for each chain, and
+// signed with the key of the validator of the chain node.
 //
 // If the connection is broken, the ReconnRemoteSigner will attempt to reconnect.
 func NewReconnRemoteSigner(
-	address string,
+	node ChainNode,
 	logger cometlog.Logger,
 	privVal PrivValidator,
+	codecs ChainSignBytesCodecs,
 	dialer net.Dialer,
 ) *ReconnRemoteSigner {
 	rs := &ReconnRemoteSigner{
-		address: address,
-		privVal: privVal,
-		dialer:  dialer,
-		privKey: cometcryptoed25519.GenPrivKey(),
+		address:   node.PrivValAddr,
+		validator: node.Validator,
+		chainID:   node.ChainID,
+		privVal:   privVal,
+		codecs:    codecs,
+		dialer:    dialer,
+		privKey:   cometcryptoed25519.GenPrivKey(),
 	}
 
 	rs.BaseService = *cometservice.NewBaseService(logger, "RemoteSigner", rs)
@@ -76,8 +95,14 @@ func (rs *ReconnRemoteSigner) establishConnection(ctx context.Context) (net.Conn
 	ctx, cancel := context.WithTimeout(ctx, connRetrySec*time.Second)
 	defer cancel()
 
-	proto, address := cometnet.ProtocolAndAddress(rs.address)
-	netConn, err := rs.dialer.DialContext(ctx, proto, address)
+	var netConn net.Conn
+	var err error
+	if isWebSocketURL(rs.address) {
+		netConn, err = dialWebSocket(ctx, &rs.dialer, rs.address)
+	} else {
+		proto, address := cometnet.ProtocolAndAddress(rs.address)
+		netConn, err = rs.dialer.DialContext(ctx, proto, address)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("dial error: %w", err)
 	}
@@ -93,6 +118,23 @@ func (rs *ReconnRemoteSigner) establishConnection(ctx context.Context) (net.Conn
 
 // main loop for ReconnRemoteSigner
 func (rs *ReconnRemoteSigner) loop(ctx context.Context) {
+	defer ReportPanic()
+
+	if rs.hold != nil {
+		select {
+		case <-rs.hold:
+		case <-rs.Quit():
+			return
+		default:
+			rs.Logger.Info("Holding connection to chain node until the nonce cache warmed up", "address", rs.address)
+			select {
+			case <-rs.hold:
+			case <-rs.Quit():
+				return
+			}
+		}
+	}
+
 	var conn net.Conn
 	for {
 		if !rs.IsRunning() {
@@ -120,7 +162,7 @@ func (rs *ReconnRemoteSigner) loop(ctx context.Context) {
 				"sleep (s)", connRetrySec,
 				"address", rs.address,
 				"attempt", retries,
-				"err", err,
+				"error", err,
 			)
 			select {
 			case <-ctx.Done():
@@ -141,13 +183,20 @@ func (rs *ReconnRemoteSigner) loop(ctx context.Context) {
 			rs.Logger.Error(
 				"Failed to read message from connection",
 				"address", rs.address,
-				"err", err,
+				"error", err,
 			)
 			rs.closeConn(conn)
 			conn = nil
 			continue
 		}
 
+		// the signer may have been stopped while waiting for the request, e.g. when the chain node
+		// was removed.
+		if !rs.IsRunning() {
+			rs.closeConn(conn)
+			return
+		}
+
 		// handleRequest handles request errors. We always send back a response
 		res := rs.handleRequest(req)
 
@@ -156,7 +205,7 @@ func (rs *ReconnRemoteSigner) loop(ctx context.Context) {
 			rs.Logger.Error(
 				"Failed to write message to connection",
 				"address", rs.address,
-				"err", err,
+				"error", err,
 			)
 			rs.closeConn(conn)
 			conn = nil
@@ -175,18 +224,44 @@ func (rs *ReconnRemoteSigner) handleRequest(req cometprotoprivval.Message) comet
 	case *cometprotoprivval.Message_PingRequest:
 		return rs.handlePingRequest()
 	default:
-		rs.Logger.Error("Unknown request", "err", fmt.Errorf("%v", typedReq))
+		rs.Logger.Error("Unknown request", "error", fmt.Errorf("%v", typedReq))
 		return cometprotoprivval.Message{}
 	}
 }
 
+// checkChainID returns an error if the chain node is restricted to sign a chain other than chainID.
+func (rs *ReconnRemoteSigner) checkChainID(chainID string) error {
+	if rs.chainID == "" || rs.chainID == chainID {
+		return nil
+	}
+	rs.Logger.Error("Refusing request for another chain", "address", rs.address, "chain_id", chainID)
+	return fmt.Errorf("chain node of chain %s cannot sign chain %s", rs.chainID, chainID)
+}
+
 func (rs *ReconnRemoteSigner) handleSignVoteRequest(chainID string, vote *cometproto.Vote) cometprotoprivval.Message {
 	msgSum := &cometprotoprivval.Message_SignedVoteResponse{SignedVoteResponse: &cometprotoprivval.SignedVoteResponse{
 		Vote:  cometproto.Vote{},
 		Error: nil,
 	}}
 
-	signature, timestamp, err := signAndTrack(context.TODO(), rs.Logger, rs.privVal, chainID, VoteToBlock(chainID, vote))
+	if err := rs.checkChainID(chainID); err != nil {
+		msgSum.SignedVoteResponse.Error = getRemoteSignerError(err)
+		return cometprotoprivval.Message{Sum: msgSum}
+	}
+
+	ctx := WithSentry(context.TODO(), rs.address)
+	if err := rs.limiter.Wait(ctx, ValidatorChainID(rs.validator, chainID), rs.connLimit); err != nil {
+		msgSum.SignedVoteResponse.Error = getRemoteSignerError(err)
+		return cometprotoprivval.Message{Sum: msgSum}
+	}
+
+	signature, timestamp, err := signAndTrack(
+		ctx,
+		rs.Logger,
+		rs.privVal,
+		ValidatorChainID(rs.validator, chainID),
+		VoteToBlockWithCodec(rs.codecs.For(chainID), chainID, vote),
+	)
 	if err != nil {
 		msgSum.SignedVoteResponse.Error = getRemoteSignerError(err)
 		return cometprotoprivval.Message{Sum: msgSum}
@@ -208,12 +283,23 @@ func (rs *ReconnRemoteSigner) handleSignProposalRequest(
 		},
 	}
 
+	if err := rs.checkChainID(chainID); err != nil {
+		msgSum.SignedProposalResponse.Error = getRemoteSignerError(err)
+		return cometprotoprivval.Message{Sum: msgSum}
+	}
+
+	ctx := WithSentry(context.TODO(), rs.address)
+	if err := rs.limiter.Wait(ctx, ValidatorChainID(rs.validator, chainID), rs.connLimit); err != nil {
+		msgSum.SignedProposalResponse.Error = getRemoteSignerError(err)
+		return cometprotoprivval.Message{Sum: msgSum}
+	}
+
 	signature, timestamp, err := signAndTrack(
-		context.TODO(),
+		ctx,
 		rs.Logger,
 		rs.privVal,
-		chainID,
-		ProposalToBlock(chainID, proposal),
+		ValidatorChainID(rs.validator, chainID),
+		ProposalToBlockWithCodec(rs.codecs.For(chainID), chainID, proposal),
 	)
 	if err != nil {
 		msgSum.SignedProposalResponse.Error = getRemoteSignerError(err)
@@ -226,11 +312,17 @@ func (rs *ReconnRemoteSigner) handleSignProposalRequest(
 }
 
 func (rs *ReconnRemoteSigner) handlePubKeyRequest(chainID string) cometprotoprivval.Message {
-	totalPubKeyRequests.WithLabelValues(chainID).Inc()
 	msgSum := &cometprotoprivval.Message_PubKeyResponse{PubKeyResponse: &cometprotoprivval.PubKeyResponse{
 		PubKey: cometprotocrypto.PublicKey{},
 		Error:  nil,
 	}}
+	if err := rs.checkChainID(chainID); err != nil {
+		msgSum.PubKeyResponse.Error = getRemoteSignerError(err)
+		return cometprotoprivval.Message{Sum: msgSum}
+	}
+
+	chainID = ValidatorChainID(rs.validator, chainID)
+	totalPubKeyRequests.WithLabelValues(chainID).Inc()
 
 	pubKey, err := rs.privVal.GetPubKey(context.TODO(), chainID)
 	if err != nil {
@@ -276,31 +368,156 @@ func getRemoteSignerError(err error) *cometprotoprivval.RemoteSignerError {
 	}
 }
 
+// StartRemoteSigners starts the connections to the chain nodes, which are added to services as
+// RemoteSigners. If hold is not nil, the connections are held until it is closed. If limiter is not
+// nil, it limits the rate of the sign requests of the chain nodes.
 func StartRemoteSigners(
 	services []cometservice.Service,
 	logger cometlog.Logger,
 	privVal PrivValidator,
-	nodes []string,
-) ([]cometservice.Service, error) {
-	var err error
+	codecs ChainSignBytesCodecs,
+	nodes ChainNodes,
+	hold <-chan struct{},
+	limiter *SignRateLimiter,
+) ([]cometservice.Service, *RemoteSigners, error) {
 	go StartMetrics()
+	signers := NewRemoteSigners(logger, privVal, codecs)
+	signers.SetHold(hold)
+	signers.SetRateLimiter(limiter)
+	if err := signers.Start(); err != nil {
+		return nil, nil, err
+	}
+	services = append(services, signers)
 	for _, node := range nodes {
-		// CometBFT requires a connection within 3 seconds of start or crashes
-		// A long timeout such as 30 seconds would cause the sentry to fail in loops
-		// Use a short timeout and dial often to connect within 3 second window
-		dialer := net.Dialer{Timeout: 2 * time.Second}
-		s := NewReconnRemoteSigner(node, logger, privVal, dialer)
+		if err := signers.AddNode(node); err != nil {
+			return nil, nil, err
+		}
+	}
+	return services, signers, nil
+}
 
-		err = s.Start()
-		if err != nil {
-			return nil, err
+// RemoteSigners are the connections to the chain nodes, which can be added and removed at runtime,
+// e.g. to connect to a new sentry without restarting the signer.
+type RemoteSigners struct {
+	cometservice.BaseService
+
+	logger  cometlog.Logger
+	privVal PrivValidator
+	codecs  ChainSignBytesCodecs
+
+	// hold, if set, holds the connections to the chain nodes until it is closed.
+	hold <-chan struct{}
+
+	// limiter, if set, limits the rate of the sign requests of the chain nodes.
+	limiter *SignRateLimiter
+
+	mu      sync.Mutex
+	signers map[string]*ReconnRemoteSigner
+}
+
+// NewRemoteSigners returns RemoteSigners that answer the requests of the chain nodes with privVal.
+func NewRemoteSigners(logger cometlog.Logger, privVal PrivValidator, codecs ChainSignBytesCodecs) *RemoteSigners {
+	r := &RemoteSigners{
+		logger:  logger,
+		privVal: privVal,
+		codecs:  codecs,
+		signers: make(map[string]*ReconnRemoteSigner),
+	}
+	r.BaseService = *cometservice.NewBaseService(logger, "RemoteSigners", r)
+	return r
+}
+
+// SetHold holds the connections to the chain nodes, including the ones added later, until hold is
+// closed. It must be set before the chain nodes are added.
+func (r *RemoteSigners) SetHold(hold <-chan struct{}) {
+	r.hold = hold
+}
+
+// SetRateLimiter limits the rate of the sign requests of the chain nodes, including the ones added
+// later. It must be set before the chain nodes are added.
+func (r *RemoteSigners) SetRateLimiter(limiter *SignRateLimiter) {
+	r.limiter = limiter
+}
+
+// OnStop stops the connections to the chain nodes and privVal.
+func (r *RemoteSigners) OnStop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for address, s := range r.signers {
+		if err := s.Stop(); err != nil {
+			r.logger.Error("Failed to stop remote signer", "address", address, "error", err)
 		}
+	}
+	clear(r.signers)
+	r.privVal.Stop()
+}
+
+// Add connects to the chain node at the priv validator address, which signs with the default
+// validator.
+func (r *RemoteSigners) Add(address string) error {
+	return r.AddNode(ChainNode{PrivValAddr: address})
+}
+
+// AddNode connects to the chain node.
+func (r *RemoteSigners) AddNode(node ChainNode) error {
+	address := node.PrivValAddr
+	if err := node.Validate(); err != nil {
+		return fmt.Errorf("invalid chain node %q: %w", address, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.signers[address]; ok {
+		return fmt.Errorf("chain node %s is already connected", address)
+	}
 
-		services = append(services, s)
+	// CometBFT requires a connection within 3 seconds of start or crashes
+	// A long timeout such as 30 seconds would cause the sentry to fail in loops
+	// Use a short timeout and dial often to connect within 3 second window
+	dialer := net.Dialer{Timeout: 2 * time.Second}
+	// privVal is shared by the chain nodes, and only stopped with all of them.
+	s := NewReconnRemoteSigner(node, r.logger, sharedPrivValidator{r.privVal}, r.codecs, dialer)
+	s.hold = r.hold
+	s.limiter, s.connLimit = r.limiter, r.limiter.connection()
+	if err := s.Start(); err != nil {
+		return err
 	}
-	return services, err
+	r.signers[address] = s
+	return nil
+}
+
+// Remove disconnects from the chain node at the priv validator address.
+func (r *RemoteSigners) Remove(address string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.signers[address]
+	if !ok {
+		return fmt.Errorf("chain node %s is not connected", address)
+	}
+	delete(r.signers, address)
+	return s.Stop()
 }
 
+// Addresses returns the priv validator addresses of the chain nodes, sorted.
+func (r *RemoteSigners) Addresses() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	addresses := make([]string, 0, len(r.signers))
+	for address := range r.signers {
+		addresses = append(addresses, address)
+	}
+	slices.Sort(addresses)
+	return addresses
+}
+
+// sharedPrivValidator is a PrivValidator shared by several remote signers, which is not stopped
+// with any one of them.
+type sharedPrivValidator struct {
+	PrivValidator
+}
+
+func (sharedPrivValidator) Stop() {}
+
 func (rs *ReconnRemoteSigner) closeConn(conn net.Conn) {
 	if conn == nil {
 		return
@@ -308,7 +525,7 @@ func (rs *ReconnRemoteSigner) closeConn(conn net.Conn) {
 	if err := conn.Close(); err != nil {
 		rs.Logger.Error("Failed to close connection to chain node",
 			"address", rs.address,
-			"err", err,
+			"error", err,
 		)
 	}
 }
@@ -2,6 +2,7 @@ package signer
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
 	"time"
 
@@ -10,6 +11,8 @@ import (
 
 	"github.com/strangelove-ventures/horcrux/signer/proto"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/reflection"
 )
 
@@ -21,6 +24,8 @@ type RemoteSignerGRPCServer struct {
 	validator  PrivValidator
 	logger     cometlog.Logger
 	listenAddr string
+	tlsConfig  *tls.Config
+	limiter    *SignRateLimiter
 
 	server *grpc.Server
 
@@ -41,16 +46,36 @@ func NewRemoteSignerGRPCServer(
 	return s
 }
 
+// SetTLSConfig serves the listener over TLS with the config, which must be set before the server
+// is started.
+func (s *RemoteSignerGRPCServer) SetTLSConfig(tlsConfig *tls.Config) {
+	s.tlsConfig = tlsConfig
+}
+
+// SetRateLimiter limits the rate of the sign requests of the clients, per client connection.
+func (s *RemoteSignerGRPCServer) SetRateLimiter(limiter *SignRateLimiter) {
+	s.limiter = limiter
+}
+
 func (s *RemoteSignerGRPCServer) OnStart() error {
-	s.logger.Info("Remote Signer GRPC Listening", "address", s.listenAddr)
+	s.logger.Info("Remote Signer GRPC Listening", "address", s.listenAddr, "tls", s.tlsConfig != nil)
 	sock, err := net.Listen("tcp", s.listenAddr)
 	if err != nil {
 		return err
 	}
-	s.server = grpc.NewServer()
+	var opts []grpc.ServerOption
+	if s.tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(s.tlsConfig)))
+	}
+	s.server = grpc.NewServer(opts...)
 	proto.RegisterRemoteSignerServer(s.server, s)
 	reflection.Register(s.server)
-	return s.server.Serve(sock)
+	go func() {
+		if err := s.server.Serve(sock); err != nil {
+			s.logger.Error("Remote Signer GRPC stopped", "error", err)
+		}
+	}()
+	return nil
 }
 
 func (s *RemoteSignerGRPCServer) OnStop() {
@@ -83,6 +108,15 @@ func (s *RemoteSignerGRPCServer) Sign(
 ) (*proto.SignBlockResponse, error) {
 	chainID, block := req.ChainID, BlockFromProto(req.Block)
 
+	var conn *tokenBucket
+	if p, ok := peer.FromContext(ctx); ok {
+		conn = s.limiter.peerConnection(p.Addr.String())
+	}
+	if err := s.limiter.Wait(ctx, chainID, conn); err != nil {
+		s.logger.Error("Refusing sign request", "chain_id", chainID, "error", err)
+		return nil, err
+	}
+
 	signature, timestamp, err := signAndTrack(ctx, s.logger, s.validator, chainID, block)
 	if err != nil {
 		return nil, err
@@ -101,7 +135,23 @@ func signAndTrack(
 	chainID string,
 	block Block,
 ) ([]byte, time.Time, error) {
+	sentry := SentryFromContext(ctx)
+	logger = logger.With("sentry", sentry)
+	duplicate := isDuplicateSentryRequest(sentry, chainID, block.HRSKey())
+
 	signature, timestamp, err := validator.Sign(ctx, chainID, block)
+	totalSentrySignRequests.WithLabelValues(
+		sentry, chainID, signType(block.Step), sentryRequestResult(duplicate, err),
+	).Inc()
+	if duplicate {
+		logger.Info(
+			"Sentry repeated sign request",
+			"type", signType(block.Step),
+			"chain_id", chainID,
+			"height", block.Height,
+			"round", block.Round,
+		)
+	}
 	if err != nil {
 		switch typedErr := err.(type) {
 		case *BeyondBlockError:
@@ -0,0 +1,62 @@
+package signer
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// signerStarted is when the signer process started, which tells a restarted cosigner apart.
+var signerStarted = time.Now()
+
+// SignerRestart is a request to restart the signer process, e.g. from the admin API during a
+// rolling restart of the cluster. The services are stopped as on SIGTERM, and the process is
+// replaced by a new one of the same command, so that an upgraded binary is picked up.
+type SignerRestart struct {
+	once      sync.Once
+	requested chan struct{}
+}
+
+func NewSignerRestart() *SignerRestart {
+	return &SignerRestart{requested: make(chan struct{})}
+}
+
+// Request requests the restart of the signer, once.
+func (r *SignerRestart) Request() {
+	r.once.Do(func() { close(r.requested) })
+}
+
+// Requested returns a channel that is closed once the restart is requested, or nil if r is nil.
+func (r *SignerRestart) Requested() <-chan struct{} {
+	if r == nil {
+		return nil
+	}
+	return r.requested
+}
+
+// IsRequested returns whether the restart is requested.
+func (r *SignerRestart) IsRequested() bool {
+	select {
+	case <-r.Requested():
+		return r != nil
+	default:
+		return false
+	}
+}
+
+// Exec replaces the signer process with a new one of the same command, arguments and environment,
+// keeping its PID for the service manager. It only returns on failure. The state directory must be
+// unlocked and the services stopped before.
+func (r *SignerRestart) Exec() error {
+	path, err := exec.LookPath(os.Args[0])
+	if err != nil {
+		return fmt.Errorf("failed to find the signer binary to restart: %w", err)
+	}
+	if err := syscall.Exec(path, os.Args, os.Environ()); err != nil {
+		return fmt.Errorf("failed to restart the signer: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc/peer"
+)
+
+// Results of a sign request from a sentry, in addition to the sign decisions.
+const (
+	// SentryRequestDuplicate is a request for the same height, round and step as the previous
+	// request of the same sentry for the chain.
+	SentryRequestDuplicate = "duplicate"
+
+	// SentryRequestStale is a request below the height, round and step already signed.
+	SentryRequestStale = "stale"
+)
+
+type sentryContextKey struct{}
+
+// WithSentry returns a context for the sign requests that arrived from the sentry.
+func WithSentry(ctx context.Context, sentry string) context.Context {
+	return context.WithValue(ctx, sentryContextKey{}, sentry)
+}
+
+// SentryFromContext returns the sentry the sign request of the context arrived from.
+// Requests from gRPC clients are attributed to the host of the client.
+func SentryFromContext(ctx context.Context) string {
+	if sentry, ok := ctx.Value(sentryContextKey{}).(string); ok {
+		return sentry
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		// the port of a client connection is ephemeral.
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return "grpc://" + host
+		}
+		return "grpc://" + p.Addr.String()
+	}
+	return "unknown"
+}
+
+type sentryChainKey struct {
+	sentry  string
+	chainID string
+}
+
+// sentryRequests holds the last sign request of each sentry for each chain, to detect
+// sentries that send the same request again.
+var sentryRequests = struct {
+	sync.Mutex
+	last map[sentryChainKey]HRSKey
+}{last: make(map[sentryChainKey]HRSKey)}
+
+// isDuplicateSentryRequest records the sign request of the sentry and returns true if it is
+// for the same height, round and step as the previous request of the sentry for the chain.
+func isDuplicateSentryRequest(sentry, chainID string, hrs HRSKey) bool {
+	key := sentryChainKey{sentry: sentry, chainID: chainID}
+	sentryRequests.Lock()
+	defer sentryRequests.Unlock()
+	last, ok := sentryRequests.last[key]
+	sentryRequests.last[key] = hrs
+	return ok && last == hrs
+}
+
+// sentryRequestResult returns the result of a sign request from a sentry for the metrics.
+func sentryRequestResult(duplicate bool, err error) string {
+	var (
+		beyondErr *BeyondBlockError
+		heightErr *HeightRegressionError
+		roundErr  *RoundRegressionError
+		stepErr   *StepRegressionError
+	)
+	switch {
+	case duplicate:
+		return SentryRequestDuplicate
+	case errors.As(err, &beyondErr), errors.As(err, &heightErr), errors.As(err, &roundErr), errors.As(err, &stepErr):
+		return SentryRequestStale
+	default:
+		return signDecisionOutcome(err)
+	}
+}
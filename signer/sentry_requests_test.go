@@ -0,0 +1,52 @@
+package signer
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/peer"
+)
+
+func TestSentryFromContext(t *testing.T) {
+	require.Equal(t, "unknown", SentryFromContext(context.Background()))
+	require.Equal(t, "tcp://sentry-1:1234", SentryFromContext(WithSentry(context.Background(), "tcp://sentry-1:1234")))
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 51234},
+	})
+	require.Equal(t, "grpc://10.0.0.1", SentryFromContext(ctx))
+}
+
+func TestSignAndTrackSentryRequests(t *testing.T) {
+	const sentry = "tcp://sentry-attribution:1234"
+	ctx := WithSentry(context.Background(), sentry)
+	requests := func(result string) float64 {
+		return testutil.ToFloat64(totalSentrySignRequests.WithLabelValues(sentry, testChainID, "prevote", result))
+	}
+
+	block := Block{Height: 10, Step: stepPrevote, SignBytes: []byte("sign bytes")}
+	_, _, err := signAndTrack(ctx, cometlog.NewNopLogger(), &mockPrivValidator{}, testChainID, block)
+	require.NoError(t, err)
+	require.Equal(t, float64(1), requests(SignDecisionSigned))
+
+	// the same request again from the same sentry.
+	_, _, err = signAndTrack(ctx, cometlog.NewNopLogger(), &mockPrivValidator{}, testChainID, block)
+	require.NoError(t, err)
+	require.Equal(t, float64(1), requests(SentryRequestDuplicate))
+
+	// the same request from another sentry is not a duplicate.
+	otherCtx := WithSentry(context.Background(), "tcp://sentry-attribution-2:1234")
+	_, _, err = signAndTrack(otherCtx, cometlog.NewNopLogger(), &mockPrivValidator{}, testChainID, block)
+	require.NoError(t, err)
+	require.Equal(t, float64(1), requests(SentryRequestDuplicate))
+
+	block.Height = 9
+	stale := &failingPrivValidator{err: &BeyondBlockError{msg: "already signed height 10"}}
+	_, _, err = signAndTrack(ctx, cometlog.NewNopLogger(), stale, testChainID, block)
+	require.Error(t, err)
+	require.Equal(t, float64(1), requests(SentryRequestStale))
+}
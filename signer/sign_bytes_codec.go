@@ -0,0 +1,102 @@
+package signer
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	cometproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	comet "github.com/cometbft/cometbft/types"
+)
+
+// DefaultSignBytesCodec is the name of the codec used for chains without an explicit codec selection.
+const DefaultSignBytesCodec = "comet"
+
+// SignBytesCodec computes and inspects the canonical sign bytes for votes and proposals.
+// CometBFT forks which modify the vote or proposal encoding can register their own
+// implementation with RegisterSignBytesCodec and select it per chain in the config.
+type SignBytesCodec interface {
+	// VoteSignBytes returns the canonical bytes to sign for a vote.
+	VoteSignBytes(chainID string, vote *cometproto.Vote) []byte
+
+	// ProposalSignBytes returns the canonical bytes to sign for a proposal.
+	ProposalSignBytes(chainID string, proposal *cometproto.Proposal) []byte
+
+	// UnpackHRST deserializes sign bytes and returns the height, round, step and timestamp.
+	UnpackHRST(signBytes []byte) (HRSTKey, error)
+
+	// OnlyDifferByTimestamp returns nil if the sign bytes for the given step
+	// are identical other than the timestamp.
+	OnlyDifferByTimestamp(step int8, lastSignBytes, newSignBytes []byte) error
+}
+
+var _ SignBytesCodec = CometSignBytesCodec{}
+
+// CometSignBytesCodec is the SignBytesCodec for the upstream CometBFT vote and proposal encoding.
+type CometSignBytesCodec struct{}
+
+func (CometSignBytesCodec) VoteSignBytes(chainID string, vote *cometproto.Vote) []byte {
+	return comet.VoteSignBytes(chainID, vote)
+}
+
+func (CometSignBytesCodec) ProposalSignBytes(chainID string, proposal *cometproto.Proposal) []byte {
+	return comet.ProposalSignBytes(chainID, proposal)
+}
+
+func (CometSignBytesCodec) UnpackHRST(signBytes []byte) (HRSTKey, error) {
+	return UnpackHRST(signBytes)
+}
+
+func (CometSignBytesCodec) OnlyDifferByTimestamp(step int8, lastSignBytes, newSignBytes []byte) error {
+	return onlyDifferByTimestamp(step, lastSignBytes, newSignBytes)
+}
+
+var (
+	signBytesCodecsMu sync.RWMutex
+	signBytesCodecs   = map[string]SignBytesCodec{
+		DefaultSignBytesCodec: CometSignBytesCodec{},
+	}
+)
+
+// RegisterSignBytesCodec makes a SignBytesCodec available for selection by name in the config.
+// It panics if a codec is already registered with the same name.
+func RegisterSignBytesCodec(name string, codec SignBytesCodec) {
+	signBytesCodecsMu.Lock()
+	defer signBytesCodecsMu.Unlock()
+	if _, ok := signBytesCodecs[name]; ok {
+		panic(fmt.Errorf("sign bytes codec already registered: %s", name))
+	}
+	signBytesCodecs[name] = codec
+}
+
+// GetSignBytesCodec returns the registered SignBytesCodec with the given name.
+func GetSignBytesCodec(name string) (SignBytesCodec, error) {
+	signBytesCodecsMu.RLock()
+	defer signBytesCodecsMu.RUnlock()
+	codec, ok := signBytesCodecs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown sign bytes codec %q, must be one of %v", name, signBytesCodecNamesLocked())
+	}
+	return codec, nil
+}
+
+func signBytesCodecNamesLocked() []string {
+	names := make([]string, 0, len(signBytesCodecs))
+	for name := range signBytesCodecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ChainSignBytesCodecs selects the SignBytesCodec for each chain ID.
+// Chains without an entry use the CometBFT codec.
+type ChainSignBytesCodecs map[string]SignBytesCodec
+
+// For returns the SignBytesCodec for the chain ID.
+func (c ChainSignBytesCodecs) For(chainID string) SignBytesCodec {
+	if codec, ok := c[chainID]; ok {
+		return codec
+	}
+	return CometSignBytesCodec{}
+}
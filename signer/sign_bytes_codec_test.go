@@ -0,0 +1,66 @@
+package signer
+
+import (
+	"testing"
+
+	cometproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	comet "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/require"
+)
+
+type prefixedSignBytesCodec struct {
+	CometSignBytesCodec
+}
+
+func (c prefixedSignBytesCodec) VoteSignBytes(chainID string, vote *cometproto.Vote) []byte {
+	return append([]byte("fork"), c.CometSignBytesCodec.VoteSignBytes(chainID, vote)...)
+}
+
+// registerTestSignBytesCodec registers the codec for the duration of the test, so that the test can
+// be run more than once in a process.
+func registerTestSignBytesCodec(t *testing.T, name string, codec SignBytesCodec) {
+	RegisterSignBytesCodec(name, codec)
+	t.Cleanup(func() {
+		signBytesCodecsMu.Lock()
+		defer signBytesCodecsMu.Unlock()
+		delete(signBytesCodecs, name)
+	})
+}
+
+func TestSignBytesCodecSelection(t *testing.T) {
+	registerTestSignBytesCodec(t, "prefixed-test", prefixedSignBytesCodec{})
+
+	require.Panics(t, func() {
+		RegisterSignBytesCodec("prefixed-test", prefixedSignBytesCodec{})
+	})
+
+	cfg := Config{
+		SignBytesCodecs: map[string]string{
+			"fork-1": "prefixed-test",
+		},
+	}
+	require.NoError(t, cfg.ValidateSingleSignerConfig())
+
+	codecs, err := cfg.ChainSignBytesCodecs()
+	require.NoError(t, err)
+
+	vote := cometproto.Vote{
+		Height: 1,
+		Round:  2,
+		Type:   cometproto.PrevoteType,
+	}
+
+	block := VoteToBlockWithCodec(codecs.For("fork-1"), "fork-1", &vote)
+	require.Equal(t, append([]byte("fork"), comet.VoteSignBytes("fork-1", &vote)...), block.SignBytes)
+
+	block = VoteToBlockWithCodec(codecs.For("cosmoshub-4"), "cosmoshub-4", &vote)
+	require.Equal(t, comet.VoteSignBytes("cosmoshub-4", &vote), block.SignBytes)
+
+	runtimeConfig := RuntimeConfig{Config: cfg}
+	codec, err := runtimeConfig.SignBytesCodec("fork-1")
+	require.NoError(t, err)
+	require.Equal(t, prefixedSignBytesCodec{}, codec)
+
+	cfg.SignBytesCodecs["fork-2"] = "unknown"
+	require.ErrorContains(t, cfg.ValidateSingleSignerConfig(), `unknown sign bytes codec "unknown"`)
+}
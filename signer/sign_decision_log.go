@@ -0,0 +1,400 @@
+package signer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	cometbytes "github.com/cometbft/cometbft/libs/bytes"
+	cometlog "github.com/cometbft/cometbft/libs/log"
+)
+
+const (
+	defaultSignDecisionLogFile    = "sign_decisions.wal"
+	defaultSignDecisionLogMaxSize = 64 // MB
+)
+
+// Sign decision outcomes.
+const (
+	SignDecisionSigned   = "signed"
+	SignDecisionRejected = "rejected"
+	SignDecisionError    = "error"
+)
+
+// SignDecisionLogConfig configures the write-ahead log of sign requests and their outcomes.
+type SignDecisionLogConfig struct {
+	// Path of the log file. Relative paths are relative to the state directory.
+	// Defaults to sign_decisions.wal.
+	Path string `yaml:"path,omitempty"`
+
+	// MaxSize in megabytes of the log file before it is rotated. Defaults to 64.
+	// One rotated file is kept.
+	MaxSize int `yaml:"maxSize,omitempty"`
+}
+
+func (cfg *SignDecisionLogConfig) Validate() error {
+	if cfg == nil {
+		return nil
+	}
+	if cfg.MaxSize < 0 {
+		return fmt.Errorf("sign decision log maxSize must not be negative")
+	}
+	return nil
+}
+
+func (cfg *SignDecisionLogConfig) maxSize() int64 {
+	if cfg.MaxSize == 0 {
+		return defaultSignDecisionLogMaxSize << 20
+	}
+	return int64(cfg.MaxSize) << 20
+}
+
+// SignDecisionLog opens the configured sign decision log. See OpenSignDecisionLog.
+func (c RuntimeConfig) SignDecisionLog() (*SignDecisionLog, []SignDecision, error) {
+	return OpenSignDecisionLog(c.SignDecisionLogFile(), c.Config.SignDecisionLog.maxSize())
+}
+
+// SignDecisionLogFile returns the path of the sign decision log.
+func (c RuntimeConfig) SignDecisionLogFile() string {
+	path := defaultSignDecisionLogFile
+	if cfg := c.Config.SignDecisionLog; cfg != nil && cfg.Path != "" {
+		path = cfg.Path
+	}
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(c.StateDir, path)
+}
+
+// SignDecision is an entry of the sign decision log. A request entry is appended when a sign
+// request is received, and an outcome entry with the same sequence number before it is answered.
+type SignDecision struct {
+	Seq     uint64    `json:"seq"`
+	Time    time.Time `json:"time"`
+	ChainID string    `json:"chain_id"`
+	Height  int64     `json:"height"`
+	Round   int64     `json:"round"`
+	Step    int8      `json:"step"`
+
+	// SignBytes of the request, only set on request entries.
+	SignBytes cometbytes.HexBytes `json:"signbytes,omitempty"`
+
+	// Outcome is empty on request entries, otherwise one of signed, rejected or error.
+	Outcome   string `json:"outcome,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Signature []byte `json:"signature,omitempty"`
+}
+
+// SignDecisionLog is an append-only log of sign requests and their outcomes. Every entry is fsynced
+// before the request is passed on or answered, so after a crash the log shows every request that
+// was received and which of them were answered.
+type SignDecisionLog struct {
+	path    string
+	maxSize int64
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+	seq  uint64
+	// pending are the request entries without an outcome, which are carried forward on rotation.
+	pending map[uint64]SignDecision
+}
+
+// OpenSignDecisionLog opens the sign decision log at path for appending. An entry that was only
+// partially written by a crash is truncated. It returns the requests in the log without an outcome,
+// i.e. the requests that were in progress when the signer stopped.
+func OpenSignDecisionLog(path string, maxSize int64) (*SignDecisionLog, []SignDecision, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	l := &SignDecisionLog{path: path, maxSize: maxSize, file: f}
+	pending, err := l.recover()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return l, pending, nil
+}
+
+// recover reads the log, truncates a trailing partial entry and positions the file for appending.
+func (l *SignDecisionLog) recover() ([]SignDecision, error) {
+	requests := make(map[uint64]SignDecision)
+
+	r := bufio.NewReader(l.file)
+	var valid int64
+	for {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// a line without a newline is a partial write.
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var d SignDecision
+		if err := json.Unmarshal(bytes.TrimSpace(line), &d); err != nil {
+			return nil, fmt.Errorf("sign decision log %s is corrupt at offset %d: %w", l.path, valid, err)
+		}
+		valid += int64(len(line))
+
+		if d.Seq > l.seq {
+			l.seq = d.Seq
+		}
+		if d.Outcome == "" {
+			requests[d.Seq] = d
+		} else {
+			delete(requests, d.Seq)
+		}
+	}
+
+	if err := l.file.Truncate(valid); err != nil {
+		return nil, err
+	}
+	if _, err := l.file.Seek(valid, io.SeekStart); err != nil {
+		return nil, err
+	}
+	l.size = valid
+
+	l.pending = requests
+	return l.pendingRequests(), nil
+}
+
+// pendingRequests returns the request entries without an outcome, ordered by sequence number.
+func (l *SignDecisionLog) pendingRequests() []SignDecision {
+	pending := make([]SignDecision, 0, len(l.pending))
+	for _, d := range l.pending {
+		pending = append(pending, d)
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Seq < pending[j].Seq })
+	return pending
+}
+
+// Request appends a request entry for the block and returns its sequence number.
+func (l *SignDecisionLog) Request(chainID string, block Block) (uint64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.seq++
+	d := SignDecision{
+		Seq:       l.seq,
+		Time:      time.Now(),
+		ChainID:   chainID,
+		Height:    block.Height,
+		Round:     block.Round,
+		Step:      block.Step,
+		SignBytes: block.SignBytes,
+	}
+	return d.Seq, l.append(d)
+}
+
+// Outcome appends the outcome of the request with the sequence number.
+func (l *SignDecisionLog) Outcome(seq uint64, chainID string, block Block, signature []byte, signErr error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	d := SignDecision{
+		Seq:       seq,
+		Time:      time.Now(),
+		ChainID:   chainID,
+		Height:    block.Height,
+		Round:     block.Round,
+		Step:      block.Step,
+		Outcome:   signDecisionOutcome(signErr),
+		Signature: signature,
+	}
+	if signErr != nil {
+		d.Error = signErr.Error()
+	}
+	return l.append(d)
+}
+
+// Interrupted appends an error outcome for a request that was in progress when the signer stopped,
+// so that it is only reported once. A sentry which retries the request is answered according to
+// the sign state, which was updated before any signature share for the request was produced.
+func (l *SignDecisionLog) Interrupted(request SignDecision) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	d := request
+	d.Time = time.Now()
+	d.SignBytes = nil
+	d.Outcome = SignDecisionError
+	d.Error = "interrupted, the signer stopped before answering the request"
+	return l.append(d)
+}
+
+func (l *SignDecisionLog) append(d SignDecision) error {
+	bz, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	bz = append(bz, '\n')
+
+	if l.size > 0 && l.size+int64(len(bz)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+
+	if err := l.write(bz); err != nil {
+		return err
+	}
+	if d.Outcome == "" {
+		l.pending[d.Seq] = d
+	} else {
+		delete(l.pending, d.Seq)
+	}
+	return nil
+}
+
+func (l *SignDecisionLog) write(bz []byte) error {
+	n, err := l.file.Write(bz)
+	l.size += int64(n)
+	if err != nil {
+		return err
+	}
+	return l.file.Sync()
+}
+
+// rotate moves the log to path.1, replacing the previous rotated log, and starts a new log with the
+// requests still in progress, so that the log alone shows every request without an outcome after a
+// crash, however many times it was rotated since the request was received.
+func (l *SignDecisionLog) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(l.path, l.path+".1"); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	l.file = f
+	l.size = 0
+	if err := syncDir(filepath.Dir(l.path)); err != nil {
+		return err
+	}
+
+	var bz []byte
+	for _, d := range l.pendingRequests() {
+		line, err := json.Marshal(d)
+		if err != nil {
+			return err
+		}
+		bz = append(append(bz, line...), '\n')
+	}
+	if len(bz) == 0 {
+		return nil
+	}
+	return l.write(bz)
+}
+
+// Close closes the log.
+func (l *SignDecisionLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// signDecisionOutcome classifies the result of a sign request. Requests refused by the double sign
+// protection or the chain tip guard are rejected, other failures are errors.
+func signDecisionOutcome(err error) string {
+	if err == nil {
+		return SignDecisionSigned
+	}
+	var (
+		heightErr      *HeightRegressionError
+		roundErr       *RoundRegressionError
+		stepErr        *StepRegressionError
+		conflictingErr *ConflictingDataError
+		sameHRSErr     *SameHRSError
+		alreadyErr     *AlreadySignedVoteError
+		blockIDsErr    *DiffBlockIDsError
+		beyondErr      *BeyondBlockError
+		chainTipErr    *ChainTipLagError
+		watermarkErr   *WatermarkClaimError
+	)
+	switch {
+	case errors.As(err, &heightErr),
+		errors.As(err, &roundErr),
+		errors.As(err, &stepErr),
+		errors.As(err, &conflictingErr),
+		errors.As(err, &sameHRSErr),
+		errors.As(err, &alreadyErr),
+		errors.As(err, &blockIDsErr),
+		errors.As(err, &beyondErr),
+		errors.As(err, &chainTipErr),
+		errors.As(err, &watermarkErr):
+		return SignDecisionRejected
+	default:
+		return SignDecisionError
+	}
+}
+
+// SignDecisionLogValidator is a PrivValidator that records every sign request and its outcome
+// in a SignDecisionLog. A request is not signed if it cannot be recorded.
+type SignDecisionLogValidator struct {
+	logger cometlog.Logger
+	val    PrivValidator
+	log    *SignDecisionLog
+}
+
+// NewSignDecisionLogValidator returns a SignDecisionLogValidator that records the sign requests
+// of val in log.
+func NewSignDecisionLogValidator(logger cometlog.Logger, val PrivValidator, log *SignDecisionLog) *SignDecisionLogValidator {
+	return &SignDecisionLogValidator{
+		logger: logger,
+		val:    val,
+		log:    log,
+	}
+}
+
+// Sign implements PrivValidator.
+func (v *SignDecisionLogValidator) Sign(ctx context.Context, chainID string, block Block) ([]byte, time.Time, error) {
+	seq, err := v.log.Request(chainID, block)
+	if err != nil {
+		return nil, block.Timestamp, fmt.Errorf("failed to record sign request: %w", err)
+	}
+
+	sig, timestamp, signErr := v.val.Sign(ctx, chainID, block)
+
+	if err := v.log.Outcome(seq, chainID, block, sig, signErr); err != nil {
+		// the signature is already in the sign state, withholding it does not make it safer.
+		v.logger.Error(
+			"Failed to record sign decision",
+			"chain_id", chainID,
+			"height", block.Height,
+			"round", block.Round,
+			"step", block.Step,
+			"error", err,
+		)
+	}
+
+	return sig, timestamp, signErr
+}
+
+// GetPubKey implements PrivValidator.
+func (v *SignDecisionLogValidator) GetPubKey(ctx context.Context, chainID string) ([]byte, error) {
+	return v.val.GetPubKey(ctx, chainID)
+}
+
+// Stop implements PrivValidator.
+func (v *SignDecisionLogValidator) Stop() {
+	v.val.Stop()
+	if err := v.log.Close(); err != nil {
+		v.logger.Error("Failed to close sign decision log", "error", err)
+	}
+}
@@ -0,0 +1,139 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+)
+
+type failingPrivValidator struct {
+	mockPrivValidator
+	err error
+}
+
+func (pv *failingPrivValidator) Sign(context.Context, string, Block) ([]byte, time.Time, error) {
+	return nil, time.Time{}, pv.err
+}
+
+func TestSignDecisionLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sign_decisions.wal")
+
+	l, pending, err := OpenSignDecisionLog(path, 1<<20)
+	require.NoError(t, err)
+	require.Empty(t, pending)
+
+	ctx := context.Background()
+	block := Block{Height: 10, Round: 0, Step: stepPrevote, SignBytes: []byte("sign bytes")}
+
+	_, _, err = NewSignDecisionLogValidator(log.NewNopLogger(), &mockPrivValidator{}, l).Sign(ctx, testChainID, block)
+	require.NoError(t, err)
+
+	_, _, err = NewSignDecisionLogValidator(log.NewNopLogger(), &failingPrivValidator{
+		err: &HeightRegressionError{regressed: 9, last: 10},
+	}, l).Sign(ctx, testChainID, block)
+	require.Error(t, err)
+
+	_, _, err = NewSignDecisionLogValidator(log.NewNopLogger(), &failingPrivValidator{
+		err: errors.New("insufficient cosigners"),
+	}, l).Sign(ctx, testChainID, block)
+	require.Error(t, err)
+
+	// a request which is not answered before a crash, followed by a partial entry.
+	seq, err := l.Request(testChainID, Block{Height: 11, Step: stepPropose})
+	require.NoError(t, err)
+	require.NoError(t, l.Close())
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"seq":9,"ti`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	l, pending, err = OpenSignDecisionLog(path, 1<<20)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, seq, pending[0].Seq)
+	require.Equal(t, int64(11), pending[0].Height)
+
+	require.NoError(t, l.Interrupted(pending[0]))
+	next, err := l.Request(testChainID, Block{Height: 12})
+	require.NoError(t, err)
+	require.Equal(t, seq+1, next)
+	require.NoError(t, l.Close())
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(contents)), "\n")
+	require.Len(t, lines, 9)
+	require.Contains(t, lines[1], `"outcome":"signed"`)
+	require.Contains(t, lines[3], `"outcome":"rejected"`)
+	require.Contains(t, lines[5], `"outcome":"error"`)
+	require.Contains(t, lines[7], `"outcome":"error"`)
+	require.Contains(t, lines[7], "interrupted")
+	require.NotContains(t, string(contents), `"ti`+"\n")
+
+	// only the interrupted request of height 12 remains.
+	l, pending, err = OpenSignDecisionLog(path, 1<<20)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, int64(12), pending[0].Height)
+	require.NoError(t, l.Close())
+}
+
+func TestSignDecisionLogRotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sign_decisions.wal")
+
+	l, _, err := OpenSignDecisionLog(path, 512)
+	require.NoError(t, err)
+	for h := int64(1); h <= 10; h++ {
+		block := Block{Height: h, SignBytes: make([]byte, 32)}
+		seq, err := l.Request(testChainID, block)
+		require.NoError(t, err)
+		require.NoError(t, l.Outcome(seq, testChainID, block, []byte("sig"), nil))
+	}
+	require.NoError(t, l.Close())
+
+	require.FileExists(t, path+".1")
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.LessOrEqual(t, info.Size(), int64(512))
+}
+
+func TestSignDecisionLogRotatePending(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sign_decisions.wal")
+
+	l, _, err := OpenSignDecisionLog(path, 512)
+	require.NoError(t, err)
+	pendingSeq, err := l.Request(testChainID, Block{Height: 1, SignBytes: make([]byte, 32)})
+	require.NoError(t, err)
+
+	// the log is rotated several times while the request is in progress.
+	for h := int64(2); h <= 20; h++ {
+		block := Block{Height: h, SignBytes: make([]byte, 32)}
+		seq, err := l.Request(testChainID, block)
+		require.NoError(t, err)
+		require.NoError(t, l.Outcome(seq, testChainID, block, []byte("sig"), nil))
+	}
+	require.NoError(t, l.Close())
+	require.FileExists(t, path+".1")
+
+	// the request in progress is carried forward to the log, so it is recovered.
+	l, pending, err := OpenSignDecisionLog(path, 512)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, pendingSeq, pending[0].Seq)
+	require.Equal(t, int64(1), pending[0].Height)
+	require.NoError(t, l.Interrupted(pending[0]))
+	require.NoError(t, l.Close())
+
+	_, pending, err = OpenSignDecisionLog(path, 512)
+	require.NoError(t, err)
+	require.Empty(t, pending)
+}
@@ -0,0 +1,81 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SignLock is a lock outside of the signer that a signer must hold to sign a chain, so that a second
+// signer or cluster with copies of the same key, e.g. restored from a backup by accident, cannot
+// sign the chain too.
+type SignLock interface {
+	// Acquire returns nil once this signer holds the lock of the chain, a *SignLockError if another
+	// signer holds it, or another error if the lock cannot be checked.
+	Acquire(ctx context.Context, chainID string) error
+}
+
+type SignLockError struct {
+	msg string
+}
+
+func (e *SignLockError) Error() string { return e.msg }
+
+func newSignLockError(chainID, clusterID, holder string) *SignLockError {
+	return &SignLockError{
+		msg: fmt.Sprintf(
+			"refusing to sign chain %s: its sign lock is held by cluster %s, not %s, "+
+				"check for another signer with the same key",
+			chainID, holder, clusterID,
+		),
+	}
+}
+
+func newSignLockLeaseError(chainID, clusterID string, lease int64) *SignLockError {
+	return &SignLockError{
+		msg: fmt.Sprintf(
+			"refusing to sign chain %s: its sign lock is held by cluster %s with lease %x, which is not of "+
+				"this signer or its peers, check for another signer with the same key and cluster ID",
+			chainID, clusterID, lease,
+		),
+	}
+}
+
+// SignLockValidator is a PrivValidator that only signs the chains whose sign lock it holds, and
+// refuses to sign if the lock cannot be checked.
+type SignLockValidator struct {
+	val  PrivValidator
+	lock SignLock
+}
+
+// NewSignLockValidator returns a SignLockValidator that acquires the sign lock of the chain of each
+// sign request before passing it to val.
+func NewSignLockValidator(val PrivValidator, lock SignLock) *SignLockValidator {
+	return &SignLockValidator{
+		val:  val,
+		lock: lock,
+	}
+}
+
+// Sign implements PrivValidator.
+func (v *SignLockValidator) Sign(ctx context.Context, chainID string, block Block) ([]byte, time.Time, error) {
+	if err := v.lock.Acquire(ctx, chainID); err != nil {
+		if _, ok := err.(*SignLockError); ok {
+			totalSignLockRefusals.WithLabelValues(chainID).Inc()
+			return nil, block.Timestamp, err
+		}
+		totalSignLockFailures.WithLabelValues(chainID).Inc()
+		return nil, block.Timestamp, fmt.Errorf("failed to acquire sign lock of chain %s: %w", chainID, err)
+	}
+	return v.val.Sign(ctx, chainID, block)
+}
+
+// GetPubKey implements PrivValidator.
+func (v *SignLockValidator) GetPubKey(ctx context.Context, chainID string) ([]byte, error) {
+	return v.val.GetPubKey(ctx, chainID)
+}
+
+// Stop implements PrivValidator.
+func (v *SignLockValidator) Stop() {
+	v.val.Stop()
+}
@@ -0,0 +1,289 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/libs/service"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+)
+
+const (
+	// SignLockEtcd holds the sign locks in an etcd cluster.
+	SignLockEtcd = "etcd"
+
+	defaultEtcdSignLockPrefix = "/horcrux/sign_lock"
+	defaultEtcdSignLockTTL    = 10 * time.Second
+)
+
+// SignLockConfig is the on disk config format for the sign locks, which the signer must hold to sign
+// a chain.
+type SignLockConfig struct {
+	Type string `yaml:"type"`
+
+	// ClusterID identifies the signer, or the cluster, holding the locks. It must be the same on every
+	// cosigner of a cluster and different between the signers with the same keys.
+	ClusterID string `yaml:"clusterID"`
+
+	// TTL of the etcd lease of the locks of each signer, after which the locks of a signer that
+	// stopped keeping its lease alive are released. Defaults to 10s.
+	TTL string `yaml:"ttl,omitempty"`
+
+	Endpoints   []string `yaml:"endpoints"`
+	Prefix      string   `yaml:"prefix,omitempty"`
+	DialTimeout string   `yaml:"dialTimeout,omitempty"`
+	Username    string   `yaml:"username,omitempty"`
+	Password    string   `yaml:"password,omitempty"`
+	CAFile      string   `yaml:"caFile,omitempty"`
+	CertFile    string   `yaml:"certFile,omitempty"`
+	KeyFile     string   `yaml:"keyFile,omitempty"`
+}
+
+func (cfg *SignLockConfig) Validate() error {
+	if cfg == nil {
+		return nil
+	}
+	switch cfg.Type {
+	case SignLockEtcd:
+		if len(cfg.Endpoints) == 0 {
+			return fmt.Errorf("etcd sign lock requires endpoints")
+		}
+	default:
+		return fmt.Errorf("unknown sign lock %q, must be one of %v", cfg.Type, []string{SignLockEtcd})
+	}
+	if cfg.ClusterID == "" {
+		return fmt.Errorf("sign lock requires clusterID")
+	}
+	ttl, err := parseDurationOrDefault(cfg.TTL, defaultEtcdSignLockTTL)
+	if err != nil {
+		return fmt.Errorf("invalid sign lock ttl: %w", err)
+	}
+	if ttl < time.Second || ttl%time.Second != 0 {
+		return fmt.Errorf("sign lock ttl %s must be whole seconds", ttl)
+	}
+	if _, err := parseDurationOrDefault(cfg.DialTimeout, defaultEtcdDialTimeout); err != nil {
+		return fmt.Errorf("invalid sign lock dialTimeout: %w", err)
+	}
+	return nil
+}
+
+var _ SignLock = &EtcdSignLock{}
+
+// EtcdSignLock holds the sign lock of each chain under <prefix>/<chainID>, a key whose value is the
+// cluster ID of the holder, attached to the etcd lease of the signer that acquired it. The key is
+// only created if it does not exist, so a lock is held by a single signer until it stops and its
+// lease expires. A cosigner also holds a lock attached to the lease of one of its peers, which it
+// learns from the peers rather than from etcd, so that a second cluster with the same cluster ID
+// is refused. The lock is checked at every sign request, so that a signer never signs on a lock that
+// expired while etcd was unreachable.
+type EtcdSignLock struct {
+	service.BaseService
+
+	logger    cometlog.Logger
+	client    *clientv3.Client
+	prefix    string
+	clusterID string
+	ttl       int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	session *concurrency.Session
+
+	// peerLeases returns the leases of the sign locks of the peer cosigners, nil for a single signer.
+	peerLeases func(ctx context.Context) []int64
+	// knownLeases are the leases the peers reported, and the previous leases of this signer.
+	knownLeases map[clientv3.LeaseID]bool
+}
+
+// NewEtcdSignLock connects to the etcd cluster for the config.
+func NewEtcdSignLock(logger cometlog.Logger, cfg *SignLockConfig) (*EtcdSignLock, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	ttl, _ := parseDurationOrDefault(cfg.TTL, defaultEtcdSignLockTTL)
+	dialTimeout, _ := parseDurationOrDefault(cfg.DialTimeout, defaultEtcdDialTimeout)
+
+	client, err := newEtcdClient(etcdClientConfig{
+		endpoints:   cfg.Endpoints,
+		dialTimeout: dialTimeout,
+		username:    cfg.Username,
+		password:    cfg.Password,
+		caFile:      cfg.CAFile,
+		certFile:    cfg.CertFile,
+		keyFile:     cfg.KeyFile,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := cfg.Prefix
+	if prefix == "" {
+		prefix = defaultEtcdSignLockPrefix
+	}
+
+	l := &EtcdSignLock{
+		logger:    logger,
+		client:    client,
+		prefix:    strings.TrimSuffix(prefix, "/"),
+		clusterID: cfg.ClusterID,
+		ttl:       int(ttl / time.Second),
+
+		knownLeases: make(map[clientv3.LeaseID]bool),
+	}
+	l.ctx, l.cancel = context.WithCancel(context.Background())
+	l.BaseService = *service.NewBaseService(logger, "EtcdSignLock", l)
+	return l, nil
+}
+
+// OnStop releases the locks acquired by this signer, so that they do not wait for the lease to
+// expire.
+func (l *EtcdSignLock) OnStop() {
+	l.mu.Lock()
+	if l.session != nil {
+		if err := l.session.Close(); err != nil {
+			l.logger.Error("Failed to release sign locks", "prefix", l.prefix, "error", err)
+		}
+	}
+	l.mu.Unlock()
+	l.cancel()
+	_ = l.client.Close()
+}
+
+// SetPeerLeases sets how the leases of the sign locks of the peer cosigners are requested, so that
+// the locks acquired by the peers are held by this cosigner too.
+func (l *EtcdSignLock) SetPeerLeases(peerLeases func(ctx context.Context) []int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.peerLeases = peerLeases
+}
+
+// Lease returns the lease of the locks acquired by this signer, or 0 before the first is acquired.
+func (l *EtcdSignLock) Lease() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.session == nil {
+		return 0
+	}
+	return int64(l.session.Lease())
+}
+
+// isKnownLease returns true if the lease is a previous lease of this signer, or the lease of the sign
+// locks of a peer cosigner.
+func (l *EtcdSignLock) isKnownLease(ctx context.Context, lease clientv3.LeaseID) bool {
+	l.mu.Lock()
+	known, peerLeases := l.knownLeases[lease], l.peerLeases
+	l.mu.Unlock()
+	if known || peerLeases == nil {
+		return known
+	}
+
+	leases := peerLeases(ctx)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, peerLease := range leases {
+		if peerLease != 0 {
+			l.knownLeases[clientv3.LeaseID(peerLease)] = true
+		}
+	}
+	return l.knownLeases[lease]
+}
+
+// lease returns the lease the locks are acquired with, with a new session if the previous one ended,
+// e.g. after etcd was unreachable for longer than the TTL.
+func (l *EtcdSignLock) lease(ctx context.Context) (clientv3.LeaseID, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.session != nil {
+		select {
+		case <-l.session.Done():
+			l.logger.Error("Sign lock session ended, acquiring the sign locks with a new lease", "prefix", l.prefix)
+			// the locks attached to the previous lease are held until it expires.
+			l.knownLeases[l.session.Lease()] = true
+			l.session = nil
+		default:
+			return l.session.Lease(), nil
+		}
+	}
+
+	// the lease is granted with the context of the sign request, and kept alive until the lock stops.
+	grant, err := l.client.Grant(ctx, int64(l.ttl))
+	if err != nil {
+		return 0, fmt.Errorf("failed to grant sign lock lease in etcd: %w", err)
+	}
+	session, err := concurrency.NewSession(l.client, concurrency.WithLease(grant.ID), concurrency.WithContext(l.ctx))
+	if err != nil {
+		return 0, fmt.Errorf("failed to keep sign lock lease alive in etcd: %w", err)
+	}
+	l.session = session
+	return grant.ID, nil
+}
+
+// Acquire implements SignLock.
+func (l *EtcdSignLock) Acquire(ctx context.Context, chainID string) error {
+	lease, err := l.lease(ctx)
+	if err != nil {
+		return err
+	}
+
+	key := l.prefix + "/" + chainID
+	res, err := l.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, l.clusterID, clientv3.WithLease(lease))).
+		Else(clientv3.OpGet(key)).
+		Commit()
+	if err != nil {
+		return fmt.Errorf("failed to acquire sign lock in etcd: %w", err)
+	}
+	if res.Succeeded {
+		l.logger.Info("Acquired sign lock", "chain_id", chainID, "cluster_id", l.clusterID)
+		return nil
+	}
+
+	kvs := res.Responses[0].GetResponseRange().Kvs
+	if len(kvs) == 0 {
+		return fmt.Errorf("sign lock of chain %s was released while it was acquired", chainID)
+	}
+	holder, holderLease := string(kvs[0].Value), clientv3.LeaseID(kvs[0].Lease)
+	if holder != l.clusterID {
+		return newSignLockError(chainID, l.clusterID, holder)
+	}
+	// a lock of this cluster is held if it is attached to the lease of this signer, or of a peer
+	// cosigner, and not merely because another signer is configured with the same cluster ID.
+	if holderLease == lease || l.isKnownLease(ctx, holderLease) {
+		return nil
+	}
+	return newSignLockLeaseError(chainID, l.clusterID, int64(holderLease))
+}
+
+// SetSignLock sets the sign lock of this cosigner, whose lease is reported to the other cosigners,
+// and which holds the locks acquired by the other cosigners.
+func (pv *ThresholdValidator) SetSignLock(lock *EtcdSignLock) {
+	pv.signLock.Store(lock)
+	lock.SetPeerLeases(pv.peerSignLockLeases)
+}
+
+// peerSignLockLeases returns the leases of the sign locks of the peer cosigners that answer.
+func (pv *ThresholdValidator) peerSignLockLeases(ctx context.Context) []int64 {
+	var leases []int64
+	for _, r := range eachRemotePeer(ctx, pv, func(ctx context.Context, rc *RemoteCosigner) (int64, error) {
+		res, err := rc.GetStatus(ctx)
+		if err != nil {
+			return 0, err
+		}
+		return res.GetSignLockLease(), nil
+	}) {
+		if r.err != "" {
+			pv.logger.Error("Failed to get the sign lock lease of cosigner", "cosigner", r.id, "error", r.err)
+			continue
+		}
+		leases = append(leases, r.status)
+	}
+	return leases
+}
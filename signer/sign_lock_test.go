@@ -0,0 +1,92 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+type mockSignLock struct {
+	err error
+}
+
+func (l *mockSignLock) Acquire(context.Context, string) error { return l.err }
+
+func TestSignLockConfig(t *testing.T) {
+	var cfg *SignLockConfig
+	require.NoError(t, cfg.Validate())
+
+	cfg = &SignLockConfig{Type: "dynamodb"}
+	require.EqualError(t, cfg.Validate(), `unknown sign lock "dynamodb", must be one of [etcd]`)
+
+	cfg = &SignLockConfig{Type: SignLockEtcd, ClusterID: "a"}
+	require.EqualError(t, cfg.Validate(), "etcd sign lock requires endpoints")
+
+	cfg = &SignLockConfig{Type: SignLockEtcd, Endpoints: []string{"127.0.0.1:2379"}}
+	require.EqualError(t, cfg.Validate(), "sign lock requires clusterID")
+
+	cfg.ClusterID = "a"
+	cfg.TTL = "1500ms"
+	require.ErrorContains(t, cfg.Validate(), "must be whole seconds")
+
+	cfg.TTL = "30s"
+	cfg.DialTimeout = "soon"
+	require.ErrorContains(t, cfg.Validate(), "invalid sign lock dialTimeout")
+
+	cfg.DialTimeout = "2s"
+	require.NoError(t, cfg.Validate())
+}
+
+func TestSignLockValidator(t *testing.T) {
+	const chainID = "sign-lock-1"
+
+	mock := &mockPrivValidator{}
+	lock := &mockSignLock{}
+	val := NewSignLockValidator(mock, lock)
+
+	block := Block{Height: 1, Step: stepPrevote, Timestamp: time.Now()}
+	_, _, err := val.Sign(context.Background(), chainID, block)
+	require.NoError(t, err)
+	require.Equal(t, 1, mock.signed)
+
+	lock.err = newSignLockError(chainID, "a", "b")
+	_, _, err = val.Sign(context.Background(), chainID, block)
+	require.IsType(t, &SignLockError{}, err)
+	require.ErrorContains(t, err, "held by cluster b, not a")
+	require.Equal(t, 1, mock.signed)
+	require.Equal(t, float64(1), testutil.ToFloat64(totalSignLockRefusals.WithLabelValues(chainID)))
+
+	// the signer fails closed when the lock cannot be checked.
+	lock.err = errors.New("etcd unreachable")
+	_, _, err = val.Sign(context.Background(), chainID, block)
+	require.ErrorContains(t, err, "failed to acquire sign lock of chain sign-lock-1")
+	require.Equal(t, 1, mock.signed)
+	require.Equal(t, float64(1), testutil.ToFloat64(totalSignLockFailures.WithLabelValues(chainID)))
+}
+
+func TestEtcdSignLockKnownLeases(t *testing.T) {
+	l := &EtcdSignLock{knownLeases: make(map[clientv3.LeaseID]bool)}
+	ctx := context.Background()
+
+	// a single signer only holds the locks attached to its own lease.
+	require.False(t, l.isKnownLease(ctx, 0x2a))
+
+	requests := 0
+	l.SetPeerLeases(func(context.Context) []int64 {
+		requests++
+		return []int64{0x2a, 0}
+	})
+
+	// a lock attached to the lease of a peer is held, the peers are asked again for any other lease.
+	require.True(t, l.isKnownLease(ctx, 0x2a))
+	require.True(t, l.isKnownLease(ctx, 0x2a))
+	require.Equal(t, 1, requests)
+	require.False(t, l.isKnownLease(ctx, 0x2b))
+	require.False(t, l.isKnownLease(ctx, 0))
+	require.Equal(t, 3, requests)
+}
@@ -0,0 +1,188 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// signPolicyTimeLayout is the layout of the start and end of a time window of a sign policy.
+const signPolicyTimeLayout = "15:04"
+
+type SignPolicyError struct {
+	msg string
+}
+
+func (e *SignPolicyError) Error() string { return e.msg }
+
+func newSignPolicyError(chainID string, block Block, reason string) *SignPolicyError {
+	return &SignPolicyError{
+		msg: fmt.Sprintf(
+			"refusing to sign %s of chain %s at height %d round %d: %s",
+			signType(block.Step), chainID, block.Height, block.Round, reason,
+		),
+	}
+}
+
+// SignPolicyConfig are the rules the sign requests of a chain must pass before they are signed, e.g.
+// to only vote on a chain without proposing blocks. A chain without a rule signs everything.
+type SignPolicyConfig struct {
+	// Steps are the steps that are signed: proposal, prevote and precommit. Defaults to all of them.
+	Steps []string `yaml:"steps,omitempty"`
+
+	// MinHeight is the first height that is signed.
+	MinHeight int64 `yaml:"minHeight,omitempty"`
+
+	// MaxHeight is the last height that is signed.
+	MaxHeight int64 `yaml:"maxHeight,omitempty"`
+
+	// MaxRounds is the number of rounds of a height that are signed, from round 0.
+	MaxRounds int64 `yaml:"maxRounds,omitempty"`
+
+	// TimeWindows are the times of the day in UTC in which sign requests are signed. Defaults to any
+	// time.
+	TimeWindows []SignPolicyTimeWindow `yaml:"timeWindows,omitempty"`
+}
+
+// SignPolicyTimeWindow is a time of the day in UTC, from start up to end, e.g. 08:00 to 18:00. A
+// window that ends before it starts spans midnight.
+type SignPolicyTimeWindow struct {
+	Start string `yaml:"start"`
+	End   string `yaml:"end"`
+}
+
+func (cfg *SignPolicyConfig) validate() error {
+	if cfg == nil {
+		return nil
+	}
+	for _, step := range cfg.Steps {
+		if signPolicyStep(step) == 0 {
+			return fmt.Errorf("invalid policy step %q, must be proposal, prevote or precommit", step)
+		}
+	}
+	if cfg.MinHeight < 0 || cfg.MaxHeight < 0 || cfg.MaxRounds < 0 {
+		return fmt.Errorf("policy minHeight, maxHeight and maxRounds must not be negative")
+	}
+	if cfg.MaxHeight > 0 && cfg.MaxHeight < cfg.MinHeight {
+		return fmt.Errorf("policy maxHeight %d must not be below minHeight %d", cfg.MaxHeight, cfg.MinHeight)
+	}
+	for _, w := range cfg.TimeWindows {
+		if _, _, err := w.bounds(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// signPolicyStep returns the step of the name, or 0 if it is not a step.
+func signPolicyStep(name string) int8 {
+	for _, step := range []int8{stepPropose, stepPrevote, stepPrecommit} {
+		if signType(step) == name {
+			return step
+		}
+	}
+	return 0
+}
+
+// bounds returns the start and end of the window as the time since midnight.
+func (w SignPolicyTimeWindow) bounds() (start, end time.Duration, err error) {
+	s, err := time.Parse(signPolicyTimeLayout, w.Start)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid policy time window start %q, must be HH:MM", w.Start)
+	}
+	e, err := time.Parse(signPolicyTimeLayout, w.End)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid policy time window end %q, must be HH:MM", w.End)
+	}
+	if s.Equal(e) {
+		return 0, 0, fmt.Errorf("policy time window %s-%s must not be empty", w.Start, w.End)
+	}
+	midnight := time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)
+	return s.Sub(midnight), e.Sub(midnight), nil
+}
+
+// contains returns true if the time of the day of t in UTC is in the window.
+func (w SignPolicyTimeWindow) contains(t time.Time) bool {
+	// validated with the config.
+	start, end, _ := w.bounds()
+	t = t.UTC()
+	d := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+	if start < end {
+		return d >= start && d < end
+	}
+	return d >= start || d < end
+}
+
+// evaluate returns the rule the sign request of the block breaks at now and why, or an empty rule if
+// the block is signed.
+func (cfg *SignPolicyConfig) evaluate(block Block, now time.Time) (rule, reason string) {
+	if len(cfg.Steps) > 0 && !slices.Contains(cfg.Steps, signType(block.Step)) {
+		return "step", fmt.Sprintf("only %s are signed", strings.Join(cfg.Steps, ", "))
+	}
+	if block.Height < cfg.MinHeight {
+		return "height", fmt.Sprintf("heights below %d are not signed", cfg.MinHeight)
+	}
+	if cfg.MaxHeight > 0 && block.Height > cfg.MaxHeight {
+		return "height", fmt.Sprintf("heights above %d are not signed", cfg.MaxHeight)
+	}
+	if cfg.MaxRounds > 0 && block.Round >= cfg.MaxRounds {
+		return "round", fmt.Sprintf("only the first %d rounds of a height are signed", cfg.MaxRounds)
+	}
+	if len(cfg.TimeWindows) > 0 && !slices.ContainsFunc(cfg.TimeWindows, func(w SignPolicyTimeWindow) bool {
+		return w.contains(now)
+	}) {
+		return "time_window", fmt.Sprintf("%s UTC is outside the time windows in which it is signed",
+			now.UTC().Format(signPolicyTimeLayout))
+	}
+	return "", ""
+}
+
+// HasSignPolicies returns true if any chain has a sign policy.
+func (cfgs ChainConfigs) HasSignPolicies() bool {
+	for _, cfg := range cfgs {
+		if cfg.Policy != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// SignPolicyGuard is a PrivValidator that refuses the sign requests that break the sign policy of
+// their chain.
+type SignPolicyGuard struct {
+	val    PrivValidator
+	chains ChainConfigs
+}
+
+// NewSignPolicyGuard returns a SignPolicyGuard that checks the sign requests of the chains before
+// passing them to val.
+func NewSignPolicyGuard(val PrivValidator, chains ChainConfigs) *SignPolicyGuard {
+	return &SignPolicyGuard{
+		val:    val,
+		chains: chains,
+	}
+}
+
+// Sign implements PrivValidator.
+func (g *SignPolicyGuard) Sign(ctx context.Context, chainID string, block Block) ([]byte, time.Time, error) {
+	if cfg, _ := validatorChainValue(g.chains, chainID); cfg.Policy != nil {
+		if rule, reason := cfg.Policy.evaluate(block, time.Now()); rule != "" {
+			totalSignPolicyRefusals.WithLabelValues(chainID, rule).Inc()
+			return nil, block.Timestamp, newSignPolicyError(chainID, block, reason)
+		}
+	}
+	return g.val.Sign(ctx, chainID, block)
+}
+
+// GetPubKey implements PrivValidator.
+func (g *SignPolicyGuard) GetPubKey(ctx context.Context, chainID string) ([]byte, error) {
+	return g.val.GetPubKey(ctx, chainID)
+}
+
+// Stop implements PrivValidator.
+func (g *SignPolicyGuard) Stop() {
+	g.val.Stop()
+}
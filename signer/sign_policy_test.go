@@ -0,0 +1,88 @@
+package signer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignPolicyValidate(t *testing.T) {
+	require.NoError(t, ChainConfigs{"osmosis-1": {Policy: &SignPolicyConfig{
+		Steps:       []string{"prevote", "precommit"},
+		MinHeight:   100,
+		MaxHeight:   200,
+		MaxRounds:   3,
+		TimeWindows: []SignPolicyTimeWindow{{Start: "22:00", End: "06:00"}},
+	}}}.Validate())
+
+	for _, policy := range []*SignPolicyConfig{
+		{Steps: []string{"vote"}},
+		{MinHeight: -1},
+		{MaxRounds: -1},
+		{MinHeight: 200, MaxHeight: 100},
+		{TimeWindows: []SignPolicyTimeWindow{{Start: "8am", End: "18:00"}}},
+		{TimeWindows: []SignPolicyTimeWindow{{Start: "08:00", End: "08:00"}}},
+	} {
+		require.Error(t, ChainConfigs{"osmosis-1": {Policy: policy}}.Validate(), policy)
+	}
+}
+
+func TestSignPolicyEvaluate(t *testing.T) {
+	policy := &SignPolicyConfig{
+		Steps:       []string{"prevote", "precommit"},
+		MinHeight:   100,
+		MaxHeight:   200,
+		MaxRounds:   2,
+		TimeWindows: []SignPolicyTimeWindow{{Start: "22:00", End: "06:00"}, {Start: "12:00", End: "13:00"}},
+	}
+	night := time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		block Block
+		now   time.Time
+		rule  string
+	}{
+		{Block{Height: 150, Step: stepPrevote}, night, ""},
+		{Block{Height: 150, Round: 1, Step: stepPrecommit}, night, ""},
+		{Block{Height: 150, Step: stepPropose}, night, "step"},
+		{Block{Height: 99, Step: stepPrevote}, night, "height"},
+		{Block{Height: 201, Step: stepPrevote}, night, "height"},
+		{Block{Height: 150, Round: 2, Step: stepPrevote}, night, "round"},
+		{Block{Height: 150, Step: stepPrevote}, time.Date(2024, 1, 1, 5, 59, 0, 0, time.UTC), ""},
+		{Block{Height: 150, Step: stepPrevote}, time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC), "time_window"},
+		{Block{Height: 150, Step: stepPrevote}, time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC), ""},
+		{Block{Height: 150, Step: stepPrevote}, time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC), "time_window"},
+	} {
+		rule, _ := policy.evaluate(tc.block, tc.now)
+		require.Equal(t, tc.rule, rule, tc)
+	}
+
+	// a policy without rules signs everything.
+	rule, _ := (&SignPolicyConfig{}).evaluate(Block{Height: 1, Round: 10, Step: stepPropose}, night)
+	require.Empty(t, rule)
+}
+
+func TestSignPolicyGuard(t *testing.T) {
+	pv := &mockPrivValidator{}
+	chains := ChainConfigs{
+		"osmosis-1":   {Policy: &SignPolicyConfig{Steps: []string{"prevote", "precommit"}}},
+		"cosmoshub-4": {HaltHeight: 100},
+	}
+	require.True(t, chains.HasSignPolicies())
+	g := NewSignPolicyGuard(pv, chains)
+
+	ctx := context.Background()
+	_, _, err := g.Sign(ctx, "osmosis-1", Block{Height: 1, Step: stepPrevote})
+	require.NoError(t, err)
+	_, _, err = g.Sign(ctx, "osmosis-1", Block{Height: 1, Step: stepPropose})
+	require.IsType(t, &SignPolicyError{}, err)
+
+	// the policy of a chain applies to every validator of the chain.
+	_, _, err = g.Sign(ctx, "acme@osmosis-1", Block{Height: 1, Step: stepPropose})
+	require.IsType(t, &SignPolicyError{}, err)
+	_, _, err = g.Sign(ctx, "cosmoshub-4", Block{Height: 1, Step: stepPropose})
+	require.NoError(t, err)
+	require.Equal(t, 2, pv.signed)
+}
@@ -0,0 +1,293 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// SignRateLimitReject refuses the sign requests over a rate limit.
+	SignRateLimitReject = "reject"
+
+	// SignRateLimitQueue delays the sign requests over a rate limit until the limit allows them.
+	SignRateLimitQueue = "queue"
+
+	defaultSignRateLimitQueueTimeout = time.Second
+)
+
+type SignRateLimitError struct {
+	msg string
+}
+
+func (e *SignRateLimitError) Error() string { return e.msg }
+
+func newSignRateLimitError(chainID, limit string, rate float64) *SignRateLimitError {
+	return &SignRateLimitError{
+		msg: fmt.Sprintf(
+			"refusing sign request of chain %s: over the %s rate limit of %v requests per second, "+
+				"check the chain node for a flood of sign requests",
+			chainID, limit, rate,
+		),
+	}
+}
+
+// SignRateLimitConfig limits the rate of the sign requests of the chain nodes and of the gRPC clients,
+// so that a malfunctioning chain node flooding sign requests does not overload the signer or the
+// cosigners.
+type SignRateLimitConfig struct {
+	// PerChain is the number of sign requests per second of each chain, or validator chain ID, over
+	// all the connections. Defaults to no limit.
+	PerChain float64 `yaml:"perChain,omitempty"`
+
+	// PerConnection is the number of sign requests per second of each connection to a chain node or
+	// from a gRPC client. Defaults to no limit.
+	PerConnection float64 `yaml:"perConnection,omitempty"`
+
+	// Burst is the number of sign requests over a limit that are signed at once, after a quiet
+	// period. Defaults to the limit rounded up.
+	Burst int `yaml:"burst,omitempty"`
+
+	// Policy is what happens to a sign request over a limit: reject refuses it, queue delays it until
+	// the limit allows it, or refuses it if that takes longer than queueTimeout. Defaults to reject.
+	Policy string `yaml:"policy,omitempty"`
+
+	// QueueTimeout is how long a sign request is delayed at most with the queue policy. Defaults to 1s.
+	QueueTimeout string `yaml:"queueTimeout,omitempty"`
+}
+
+// signRateLimitParams are the parameters of the rate limits of the sign requests.
+type signRateLimitParams struct {
+	perChain      float64
+	perConnection float64
+	burst         int
+	queue         bool
+	queueTimeout  time.Duration
+}
+
+func (cfg *SignRateLimitConfig) Validate() error {
+	_, err := cfg.params()
+	return err
+}
+
+// params returns the rate limits of the config, none without a config.
+func (cfg *SignRateLimitConfig) params() (signRateLimitParams, error) {
+	if cfg == nil {
+		return signRateLimitParams{}, nil
+	}
+	if cfg.PerChain < 0 || cfg.PerConnection < 0 || cfg.Burst < 0 {
+		return signRateLimitParams{}, fmt.Errorf(
+			"signRateLimit perChain (%v), perConnection (%v) and burst (%d) cannot be negative",
+			cfg.PerChain, cfg.PerConnection, cfg.Burst,
+		)
+	}
+	p := signRateLimitParams{
+		perChain:      cfg.PerChain,
+		perConnection: cfg.PerConnection,
+		burst:         cfg.Burst,
+	}
+	switch cfg.Policy {
+	case "", SignRateLimitReject:
+	case SignRateLimitQueue:
+		p.queue = true
+	default:
+		return signRateLimitParams{}, fmt.Errorf("signRateLimit policy (%s) must be %s or %s",
+			cfg.Policy, SignRateLimitReject, SignRateLimitQueue)
+	}
+	var err error
+	if p.queueTimeout, err = parseDurationOrDefault(cfg.QueueTimeout, defaultSignRateLimitQueueTimeout); err != nil {
+		return signRateLimitParams{}, fmt.Errorf("invalid signRateLimit queueTimeout: %w", err)
+	}
+	if p.queueTimeout <= 0 {
+		return signRateLimitParams{}, fmt.Errorf("signRateLimit queueTimeout (%s) must be positive", p.queueTimeout)
+	}
+	return p, nil
+}
+
+// tokenBucket allows rate requests per second, and up to burst requests at once.
+type tokenBucket struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket returns a full token bucket, with a burst of the rate rounded up without a burst.
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	b := float64(burst)
+	if burst == 0 {
+		b = math.Max(1, math.Ceil(rate))
+	}
+	return &tokenBucket{rate: rate, burst: b, tokens: b, last: time.Now()}
+}
+
+// refill adds the tokens since the last refill at now. b.mu must be held.
+func (b *tokenBucket) refill(now time.Time) {
+	if now.After(b.last) {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+	}
+}
+
+// reserve takes a token at now, and returns how long until it is available. It takes no token and
+// returns false if that is longer than maxWait.
+func (b *tokenBucket) reserve(now time.Time, maxWait time.Duration) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(now)
+	var wait time.Duration
+	if b.tokens < 1 {
+		wait = time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+	}
+	if wait > maxWait {
+		return 0, false
+	}
+	b.tokens--
+	return wait, true
+}
+
+// cancel returns a reserved token that was not used.
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = math.Min(b.burst, b.tokens+1)
+}
+
+// full returns true if the bucket refilled at now, i.e. it has not been used for a while.
+func (b *tokenBucket) full(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(now)
+	return b.tokens >= b.burst
+}
+
+// SignRateLimiter limits the rate of the sign requests of each chain and of each connection.
+type SignRateLimiter struct {
+	params signRateLimitParams
+
+	mu     sync.Mutex
+	chains map[string]*tokenBucket
+	// peers are the limits of the connections of the gRPC clients, by address.
+	peers map[string]*tokenBucket
+}
+
+// NewSignRateLimiter returns a SignRateLimiter of the config, or nil without a config.
+func NewSignRateLimiter(cfg *SignRateLimitConfig) *SignRateLimiter {
+	if cfg == nil {
+		return nil
+	}
+	// validated with the config.
+	p, _ := cfg.params()
+	return &SignRateLimiter{
+		params: p,
+		chains: make(map[string]*tokenBucket),
+		peers:  make(map[string]*tokenBucket),
+	}
+}
+
+// connection returns the limit of a new connection, or nil without a limit per connection.
+func (l *SignRateLimiter) connection() *tokenBucket {
+	if l == nil || l.params.perConnection == 0 {
+		return nil
+	}
+	return newTokenBucket(l.params.perConnection, l.params.burst)
+}
+
+// peerConnection returns the limit of the connection of the gRPC client at the address, or nil
+// without a limit per connection.
+func (l *SignRateLimiter) peerConnection(addr string) *tokenBucket {
+	if l == nil || l.params.perConnection == 0 {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b, ok := l.peers[addr]; ok {
+		return b
+	}
+	// forget the connections that have not signed for a while, so that the clients that reconnect
+	// from other ports do not pile up.
+	now := time.Now()
+	for a, b := range l.peers {
+		if b.full(now) {
+			delete(l.peers, a)
+		}
+	}
+	b := l.connection()
+	l.peers[addr] = b
+	return b
+}
+
+// chain returns the limit of the chain, or nil without a limit per chain.
+func (l *SignRateLimiter) chain(chainID string) *tokenBucket {
+	if l.params.perChain == 0 {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.chains[chainID]
+	if !ok {
+		b = newTokenBucket(l.params.perChain, l.params.burst)
+		l.chains[chainID] = b
+	}
+	return b
+}
+
+// Wait returns once the sign request of the chain over the connection conn, which may be nil, is
+// within the rate limits, or an error if it is refused. A nil SignRateLimiter limits nothing.
+func (l *SignRateLimiter) Wait(ctx context.Context, chainID string, conn *tokenBucket) error {
+	if l == nil {
+		return nil
+	}
+	var maxWait time.Duration
+	if l.params.queue {
+		maxWait = l.params.queueTimeout
+	}
+	now := time.Now()
+
+	var (
+		wait     time.Duration
+		reserved []*tokenBucket
+	)
+	for _, limit := range []struct {
+		name   string
+		rate   float64
+		bucket *tokenBucket
+	}{
+		{"connection", l.params.perConnection, conn},
+		{"chain", l.params.perChain, l.chain(chainID)},
+	} {
+		if limit.bucket == nil {
+			continue
+		}
+		w, ok := limit.bucket.reserve(now, maxWait)
+		if !ok {
+			for _, b := range reserved {
+				b.cancel()
+			}
+			totalSignRateLimitRefusals.WithLabelValues(chainID, limit.name).Inc()
+			return newSignRateLimitError(chainID, limit.name, limit.rate)
+		}
+		reserved = append(reserved, limit.bucket)
+		wait = max(wait, w)
+	}
+	if wait == 0 {
+		return nil
+	}
+
+	totalSignRateLimitQueued.WithLabelValues(chainID).Inc()
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		for _, b := range reserved {
+			b.cancel()
+		}
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,90 @@
+package signer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignRateLimitConfigValidate(t *testing.T) {
+	require.NoError(t, (*SignRateLimitConfig)(nil).Validate())
+	require.NoError(t, (&SignRateLimitConfig{PerChain: 10, Policy: SignRateLimitQueue, QueueTimeout: "2s"}).Validate())
+	require.Error(t, (&SignRateLimitConfig{PerChain: -1}).Validate())
+	require.Error(t, (&SignRateLimitConfig{Burst: -1}).Validate())
+	require.Error(t, (&SignRateLimitConfig{Policy: "drop"}).Validate())
+	require.Error(t, (&SignRateLimitConfig{QueueTimeout: "soon"}).Validate())
+	require.Error(t, (&SignRateLimitConfig{QueueTimeout: "0s"}).Validate())
+}
+
+func TestSignRateLimiterReject(t *testing.T) {
+	l := NewSignRateLimiter(&SignRateLimitConfig{PerChain: 1, PerConnection: 1, Burst: 2})
+	ctx := context.Background()
+
+	// the burst is signed at once, the next sign request of the chain is refused.
+	require.NoError(t, l.Wait(ctx, "osmosis-1", nil))
+	require.NoError(t, l.Wait(ctx, "osmosis-1", nil))
+	refusals := totalSignRateLimitRefusals.WithLabelValues("osmosis-1", "chain")
+	before := testutil.ToFloat64(refusals)
+	err := l.Wait(ctx, "osmosis-1", nil)
+	require.IsType(t, &SignRateLimitError{}, err)
+	require.Equal(t, before+1, testutil.ToFloat64(refusals))
+
+	// the chains are limited separately.
+	require.NoError(t, l.Wait(ctx, "cosmoshub-4", nil))
+
+	// a connection is limited over the chains, and a refused sign request takes no token of the chain.
+	conn := l.connection()
+	require.NoError(t, l.Wait(ctx, "osmo-testnet-5", conn))
+	require.NoError(t, l.Wait(ctx, "cosmoshub-4", conn))
+	err = l.Wait(ctx, "osmo-testnet-5", conn)
+	require.IsType(t, &SignRateLimitError{}, err)
+	require.NoError(t, l.Wait(ctx, "osmo-testnet-5", nil))
+
+	// the connections of the gRPC clients are limited by address.
+	require.Same(t, l.peerConnection("10.0.0.1:5000"), l.peerConnection("10.0.0.1:5000"))
+	require.NotSame(t, l.peerConnection("10.0.0.1:5000"), l.peerConnection("10.0.0.2:5000"))
+}
+
+func TestSignRateLimiterQueue(t *testing.T) {
+	l := NewSignRateLimiter(&SignRateLimitConfig{
+		PerChain:     20,
+		Burst:        1,
+		Policy:       SignRateLimitQueue,
+		QueueTimeout: "80ms",
+	})
+	ctx := context.Background()
+
+	require.NoError(t, l.Wait(ctx, "osmosis-1", nil))
+	start := time.Now()
+	require.NoError(t, l.Wait(ctx, "osmosis-1", nil))
+	require.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+
+	// the sign requests that would be queued longer than the queue timeout are refused.
+	require.NoError(t, l.Wait(ctx, "cosmoshub-4", nil))
+	conn := newTokenBucket(20, 1)
+	_, ok := conn.reserve(time.Now(), time.Second)
+	require.True(t, ok)
+	_, ok = conn.reserve(time.Now(), time.Second)
+	require.True(t, ok)
+	err := l.Wait(ctx, "cosmoshub-4", conn)
+	require.IsType(t, &SignRateLimitError{}, err)
+
+	// a sign request canceled while queued is not signed.
+	cctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	require.NoError(t, l.Wait(ctx, "osmo-testnet-5", nil))
+	require.ErrorIs(t, l.Wait(cctx, "osmo-testnet-5", nil), context.DeadlineExceeded)
+}
+
+func TestSignRateLimiterNil(t *testing.T) {
+	var l *SignRateLimiter
+	require.Nil(t, NewSignRateLimiter(nil))
+	require.Nil(t, l.connection())
+	require.Nil(t, l.peerConnection("10.0.0.1:5000"))
+	for i := 0; i < 100; i++ {
+		require.NoError(t, l.Wait(context.Background(), "osmosis-1", nil))
+	}
+}
@@ -0,0 +1,243 @@
+package signer
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSignSchedulerMaxConcurrent = 4
+	defaultSignSchedulerBlockTime     = 6 * time.Second
+
+	// signSchedulerBlockTimeWeight is the weight of the latest block time in the moving average of the
+	// block time of a chain.
+	signSchedulerBlockTimeWeight = 0.2
+)
+
+// SignSchedulerConfig schedules the sign requests when more of them are pending than are signed at
+// once: the precommits ahead of the prevotes ahead of the proposals, then the chains closest to
+// missing their block first, instead of in the order they arrived.
+type SignSchedulerConfig struct {
+	// MaxConcurrent is the number of sign requests signed at once. Defaults to 4.
+	MaxConcurrent int `yaml:"maxConcurrent,omitempty"`
+
+	// BlockTime is the block time of a chain until it is measured from its sign requests. Defaults to
+	// 6s.
+	BlockTime string `yaml:"blockTime,omitempty"`
+}
+
+// signSchedulerParams are the parameters of the scheduling of the sign requests.
+type signSchedulerParams struct {
+	maxConcurrent int
+	blockTime     time.Duration
+}
+
+func (cfg *SignSchedulerConfig) Validate() error {
+	if cfg == nil {
+		return nil
+	}
+	_, err := cfg.params()
+	return err
+}
+
+func (cfg *SignSchedulerConfig) params() (signSchedulerParams, error) {
+	if cfg.MaxConcurrent < 0 {
+		return signSchedulerParams{}, fmt.Errorf("signScheduler maxConcurrent (%d) cannot be negative",
+			cfg.MaxConcurrent)
+	}
+	p := signSchedulerParams{maxConcurrent: cfg.MaxConcurrent}
+	if p.maxConcurrent == 0 {
+		p.maxConcurrent = defaultSignSchedulerMaxConcurrent
+	}
+	var err error
+	if p.blockTime, err = parseDurationOrDefault(cfg.BlockTime, defaultSignSchedulerBlockTime); err != nil {
+		return signSchedulerParams{}, fmt.Errorf("invalid signScheduler blockTime: %w", err)
+	}
+	if p.blockTime <= 0 {
+		return signSchedulerParams{}, fmt.Errorf("signScheduler blockTime (%s) must be positive", p.blockTime)
+	}
+	return p, nil
+}
+
+// signSchedulerChain is the height of a chain the scheduler last saw, and its block time.
+type signSchedulerChain struct {
+	height      int64
+	heightStart time.Time
+	blockTime   time.Duration
+}
+
+// signRequest is a sign request waiting for a slot.
+type signRequest struct {
+	step     int8
+	deadline time.Time
+	arrival  time.Time
+
+	// ready is closed once the request is given a slot.
+	ready chan struct{}
+	index int
+}
+
+// before returns true if r is signed before o.
+func (r *signRequest) before(o *signRequest) bool {
+	if r.step != o.step {
+		// the steps are ordered from the proposal to the precommit.
+		return r.step > o.step
+	}
+	if !r.deadline.Equal(o.deadline) {
+		return r.deadline.Before(o.deadline)
+	}
+	return r.arrival.Before(o.arrival)
+}
+
+// signRequestQueue is a heap of the waiting sign requests, the next one to sign first.
+type signRequestQueue []*signRequest
+
+func (q signRequestQueue) Len() int           { return len(q) }
+func (q signRequestQueue) Less(i, j int) bool { return q[i].before(q[j]) }
+
+func (q signRequestQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *signRequestQueue) Push(x any) {
+	r := x.(*signRequest)
+	r.index = len(*q)
+	*q = append(*q, r)
+}
+
+func (q *signRequestQueue) Pop() any {
+	old := *q
+	r := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	r.index = -1
+	return r
+}
+
+// SignScheduler is a PrivValidator that signs a limited number of sign requests at once, and gives
+// the next free slot to the waiting sign request of the highest step, then of the chain closest to
+// missing its block: the one whose height started the longest ago relative to its block time.
+type SignScheduler struct {
+	val    PrivValidator
+	params signSchedulerParams
+
+	mu     sync.Mutex
+	active int
+	queue  signRequestQueue
+	chains map[string]*signSchedulerChain
+}
+
+// NewSignScheduler returns a SignScheduler that schedules the sign requests of val.
+func NewSignScheduler(val PrivValidator, cfg *SignSchedulerConfig) (*SignScheduler, error) {
+	p, err := cfg.params()
+	if err != nil {
+		return nil, err
+	}
+	return &SignScheduler{
+		val:    val,
+		params: p,
+		chains: make(map[string]*signSchedulerChain),
+	}, nil
+}
+
+// deadline returns the time by which the block of the chain is expected to be committed, and
+// measures the block time of the chain from the start of its heights. s.mu must be held.
+func (s *SignScheduler) deadline(chainID string, height int64, now time.Time) time.Time {
+	c, ok := s.chains[chainID]
+	if !ok {
+		c = &signSchedulerChain{height: height, heightStart: now, blockTime: s.params.blockTime}
+		s.chains[chainID] = c
+	}
+	if height > c.height {
+		if height == c.height+1 {
+			latest := float64(now.Sub(c.heightStart))
+			c.blockTime = time.Duration(signSchedulerBlockTimeWeight*latest +
+				(1-signSchedulerBlockTimeWeight)*float64(c.blockTime))
+		}
+		c.height, c.heightStart = height, now
+	}
+	return c.heightStart.Add(c.blockTime)
+}
+
+// acquire waits for a slot to sign the block of the chain.
+func (s *SignScheduler) acquire(ctx context.Context, chainID string, block Block) error {
+	now := time.Now()
+	s.mu.Lock()
+	deadline := s.deadline(chainID, block.Height, now)
+	if s.active < s.params.maxConcurrent {
+		s.active++
+		s.mu.Unlock()
+		return nil
+	}
+	r := &signRequest{
+		step:     block.Step,
+		deadline: deadline,
+		arrival:  now,
+		ready:    make(chan struct{}),
+	}
+	heap.Push(&s.queue, r)
+	signSchedulerQueued.Set(float64(len(s.queue)))
+	s.mu.Unlock()
+
+	defer func() {
+		timedSignSchedulerWait.WithLabelValues(chainID, signType(block.Step)).Observe(time.Since(now).Seconds())
+	}()
+	select {
+	case <-r.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.index < 0 {
+		// given a slot while canceled.
+		s.releaseLocked()
+		return ctx.Err()
+	}
+	heap.Remove(&s.queue, r.index)
+	signSchedulerQueued.Set(float64(len(s.queue)))
+	return ctx.Err()
+}
+
+// release frees the slot of a signed request.
+func (s *SignScheduler) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.releaseLocked()
+}
+
+// releaseLocked gives the slot to the next waiting sign request, or frees it. s.mu must be held.
+func (s *SignScheduler) releaseLocked() {
+	if len(s.queue) == 0 {
+		s.active--
+		return
+	}
+	r := heap.Pop(&s.queue).(*signRequest)
+	signSchedulerQueued.Set(float64(len(s.queue)))
+	close(r.ready)
+}
+
+// Sign implements PrivValidator.
+func (s *SignScheduler) Sign(ctx context.Context, chainID string, block Block) ([]byte, time.Time, error) {
+	if err := s.acquire(ctx, chainID, block); err != nil {
+		return nil, block.Timestamp, err
+	}
+	defer s.release()
+	return s.val.Sign(ctx, chainID, block)
+}
+
+// GetPubKey implements PrivValidator.
+func (s *SignScheduler) GetPubKey(ctx context.Context, chainID string) ([]byte, error) {
+	return s.val.GetPubKey(ctx, chainID)
+}
+
+// Stop implements PrivValidator.
+func (s *SignScheduler) Stop() {
+	s.val.Stop()
+}
@@ -0,0 +1,132 @@
+package signer
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// blockingPrivValidator records the sign requests it signs, each once unblock is sent to.
+type blockingPrivValidator struct {
+	mockPrivValidator
+	unblock chan struct{}
+
+	mu     sync.Mutex
+	chains []string
+}
+
+func (pv *blockingPrivValidator) Sign(ctx context.Context, chainID string, block Block) ([]byte, time.Time, error) {
+	<-pv.unblock
+	pv.mu.Lock()
+	defer pv.mu.Unlock()
+	pv.chains = append(pv.chains, chainID+"/"+signType(block.Step))
+	return nil, block.Timestamp, nil
+}
+
+func TestSignSchedulerConfigValidate(t *testing.T) {
+	require.NoError(t, (*SignSchedulerConfig)(nil).Validate())
+	require.NoError(t, (&SignSchedulerConfig{}).Validate())
+	require.NoError(t, (&SignSchedulerConfig{MaxConcurrent: 2, BlockTime: "1s"}).Validate())
+	require.Error(t, (&SignSchedulerConfig{MaxConcurrent: -1}).Validate())
+	require.Error(t, (&SignSchedulerConfig{BlockTime: "soon"}).Validate())
+	require.Error(t, (&SignSchedulerConfig{BlockTime: "0s"}).Validate())
+}
+
+func TestSignSchedulerPriority(t *testing.T) {
+	pv := &blockingPrivValidator{unblock: make(chan struct{})}
+	s, err := NewSignScheduler(pv, &SignSchedulerConfig{MaxConcurrent: 1, BlockTime: "6s"})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// the block of cosmoshub-4 started before the one of osmosis-1, so it is closer to being missed.
+	s.mu.Lock()
+	s.deadline("cosmoshub-4", 10, time.Now().Add(-5*time.Second))
+	s.deadline("osmosis-1", 10, time.Now())
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	sign := func(chainID string, step int8) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := s.Sign(ctx, chainID, Block{Height: 10, Step: step})
+			require.NoError(t, err)
+		}()
+		// the requests are queued in order.
+		require.Eventually(t, func() bool {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			return s.active == 1
+		}, time.Second, time.Millisecond)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	sign("osmosis-1", stepPropose)
+	sign("osmosis-1", stepPropose)
+	sign("osmosis-1", stepPrevote)
+	sign("osmosis-1", stepPrecommit)
+	sign("cosmoshub-4", stepPrevote)
+	sign("cosmoshub-4", stepPrecommit)
+
+	for i := 0; i < 6; i++ {
+		pv.unblock <- struct{}{}
+	}
+	wg.Wait()
+
+	require.Equal(t, []string{
+		"osmosis-1/proposal",
+		"cosmoshub-4/precommit",
+		"osmosis-1/precommit",
+		"cosmoshub-4/prevote",
+		"osmosis-1/prevote",
+		"osmosis-1/proposal",
+	}, pv.chains)
+	require.Zero(t, s.active)
+}
+
+func TestSignSchedulerCancel(t *testing.T) {
+	pv := &blockingPrivValidator{unblock: make(chan struct{})}
+	s, err := NewSignScheduler(pv, &SignSchedulerConfig{MaxConcurrent: 1})
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _, err := s.Sign(context.Background(), "osmosis-1", Block{Height: 1, Step: stepPrevote})
+		require.NoError(t, err)
+	}()
+	require.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.active == 1
+	}, time.Second, time.Millisecond)
+
+	// a canceled sign request leaves the queue without taking the slot.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, err = s.Sign(ctx, "osmosis-1", Block{Height: 1, Step: stepPrecommit})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Empty(t, s.queue)
+
+	pv.unblock <- struct{}{}
+	<-done
+	require.Zero(t, s.active)
+}
+
+func TestSignSchedulerBlockTime(t *testing.T) {
+	s, err := NewSignScheduler(&mockPrivValidator{}, &SignSchedulerConfig{BlockTime: "6s"})
+	require.NoError(t, err)
+	now := time.Now()
+	require.Equal(t, now.Add(6*time.Second), s.deadline("osmosis-1", 10, now))
+
+	// the block time moves towards the measured block times.
+	d := s.deadline("osmosis-1", 11, now.Add(time.Second))
+	require.Equal(t, now.Add(time.Second).Add(5*time.Second), d)
+
+	// a height skipped is not a block time.
+	d = s.deadline("osmosis-1", 13, now.Add(time.Minute))
+	require.Equal(t, now.Add(time.Minute).Add(5*time.Second), d)
+}
@@ -5,15 +5,11 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"os"
 	"sync"
 
 	cometbytes "github.com/cometbft/cometbft/libs/bytes"
-	cometjson "github.com/cometbft/cometbft/libs/json"
 	"github.com/cometbft/cometbft/libs/protoio"
-	"github.com/cometbft/cometbft/libs/tempfile"
 	cometproto "github.com/cometbft/cometbft/proto/tendermint/types"
-	comet "github.com/cometbft/cometbft/types"
 	"github.com/gogo/protobuf/proto"
 	"github.com/strangelove-ventures/horcrux/signer/cond"
 )
@@ -61,11 +57,16 @@ func VoteToStep(vote *cometproto.Vote) int8 {
 }
 
 func VoteToBlock(chainID string, vote *cometproto.Vote) Block {
+	return VoteToBlockWithCodec(CometSignBytesCodec{}, chainID, vote)
+}
+
+// VoteToBlockWithCodec converts a vote to a Block using the codec to compute the sign bytes.
+func VoteToBlockWithCodec(codec SignBytesCodec, chainID string, vote *cometproto.Vote) Block {
 	return Block{
 		Height:    vote.Height,
 		Round:     int64(vote.Round),
 		Step:      VoteToStep(vote),
-		SignBytes: comet.VoteSignBytes(chainID, vote),
+		SignBytes: codec.VoteSignBytes(chainID, vote),
 		Timestamp: vote.Timestamp,
 	}
 }
@@ -75,11 +76,16 @@ func ProposalToStep(_ *cometproto.Proposal) int8 {
 }
 
 func ProposalToBlock(chainID string, proposal *cometproto.Proposal) Block {
+	return ProposalToBlockWithCodec(CometSignBytesCodec{}, chainID, proposal)
+}
+
+// ProposalToBlockWithCodec converts a proposal to a Block using the codec to compute the sign bytes.
+func ProposalToBlockWithCodec(codec SignBytesCodec, chainID string, proposal *cometproto.Proposal) Block {
 	return Block{
 		Height:    proposal.Height,
 		Round:     int64(proposal.Round),
 		Step:      ProposalToStep(proposal),
-		SignBytes: comet.ProposalSignBytes(chainID, proposal),
+		SignBytes: codec.ProposalSignBytes(chainID, proposal),
 		Timestamp: proposal.Timestamp,
 	}
 }
@@ -106,7 +112,12 @@ type SignState struct {
 	Signature   []byte              `json:"signature,omitempty"`
 	SignBytes   cometbytes.HexBytes `json:"signbytes,omitempty"`
 
-	filePath string
+	store SignStateStore
+
+	// saveMu serializes the saves, so that each compare-and-set of the store advances from the
+	// HRS of the previous one, persisted.
+	saveMu    sync.Mutex
+	persisted HRSKey
 
 	// mu protects the cache and is used for signaling with cond.
 	mu    sync.RWMutex
@@ -114,7 +125,11 @@ type SignState struct {
 	cond  *cond.Cond
 }
 
-func (signState *SignState) existingSignatureOrErrorIfRegression(hrst HRSTKey, signBytes []byte) ([]byte, error) {
+func (signState *SignState) existingSignatureOrErrorIfRegression(
+	codec SignBytesCodec,
+	hrst HRSTKey,
+	signBytes []byte,
+) ([]byte, error) {
 	signState.mu.RLock()
 	defer signState.mu.RUnlock()
 
@@ -132,7 +147,7 @@ func (signState *SignState) existingSignatureOrErrorIfRegression(hrst HRSTKey, s
 	// It is ok to re-sign a different timestamp if that is the only difference in the sign bytes
 	if bytes.Equal(signBytes, signState.SignBytes) {
 		return signState.Signature, nil
-	} else if err := signState.OnlyDifferByTimestamp(signBytes); err != nil {
+	} else if err := codec.OnlyDifferByTimestamp(signState.Step, signState.SignBytes, signBytes); err != nil {
 		return nil, err
 	}
 
@@ -150,6 +165,19 @@ func (signState *SignState) HRSKey() HRSKey {
 	}
 }
 
+// SignStateConsensus returns the HRS, the signature and the sign bytes of the sign state.
+func (signState *SignState) SignStateConsensus() SignStateConsensus {
+	signState.mu.RLock()
+	defer signState.mu.RUnlock()
+	return SignStateConsensus{
+		Height:    signState.Height,
+		Round:     signState.Round,
+		Step:      signState.Step,
+		Signature: signState.Signature,
+		SignBytes: signState.SignBytes,
+	}
+}
+
 func (signState *SignState) hrsKeyLocked() HRSKey {
 	return HRSKey{
 		Height: signState.Height,
@@ -212,8 +240,8 @@ func (signState *SignState) GetFromCache(hrs HRSKey) (HRSKey, *SignStateConsensu
 	return latestBlock, nil
 }
 
-// cacheAndMarshal will cache a SignStateConsensus for it's HRS and return the marshalled bytes.
-func (signState *SignState) cacheAndMarshal(ssc SignStateConsensus) []byte {
+// update will cache a SignStateConsensus for it's HRS and update the high watermark.
+func (signState *SignState) update(ssc SignStateConsensus) {
 	signState.mu.Lock()
 	defer signState.mu.Unlock()
 
@@ -230,65 +258,60 @@ func (signState *SignState) cacheAndMarshal(ssc SignStateConsensus) []byte {
 	signState.Step = ssc.Step
 	signState.Signature = ssc.Signature
 	signState.SignBytes = ssc.SignBytes
-
-	jsonBytes, err := cometjson.MarshalIndent(signState, "", "  ")
-	if err != nil {
-		panic(err)
-	}
-
-	return jsonBytes
 }
 
 // Save updates the high watermark height/round/step (HRS) if it is greater
-// than the current high watermark. If pendingDiskWG is provided, the write operation
-// will be a separate goroutine (async). This allows pendingDiskWG to be used to .Wait()
-// for all pending SignState disk writes.
+// than the current high watermark. The HRS is persisted to the store before it is
+// cached, so that no signature of it is released unless the store accepted it. If
+// pendingDiskWG is provided, it tracks the save so that it can be used to .Wait()
+// for the saves in flight.
 func (signState *SignState) Save(
 	ssc SignStateConsensus,
 	pendingDiskWG *sync.WaitGroup,
 ) error {
+	if pendingDiskWG != nil {
+		pendingDiskWG.Add(1)
+		defer pendingDiskWG.Done()
+	}
+
+	signState.saveMu.Lock()
+	defer signState.saveMu.Unlock()
+
 	err := signState.GetErrorIfLessOrEqual(ssc.Height, ssc.Round, ssc.Step)
 	if err != nil {
 		return err
 	}
 
-	// HRS is greater than existing state, move forward with caching and saving.
+	// HRS is greater than existing state, move forward with saving and caching.
+
+	if signState.store != nil {
+		if err := signState.store.SetHRS(signState.persisted, ssc); err != nil {
+			return &SignStatePersistError{err: err}
+		}
+	}
+	signState.persisted = ssc.HRSKey()
 
-	jsonBytes := signState.cacheAndMarshal(ssc)
+	signState.update(ssc)
 
 	// Broadcast to waiting goroutines to notify them that an
 	// existing signature for their HRS may now be available.
 	signState.cond.Broadcast()
 
-	if pendingDiskWG != nil {
-		pendingDiskWG.Add(1)
-		go func() {
-			defer pendingDiskWG.Done()
-			signState.save(jsonBytes)
-		}()
-	} else {
-		signState.save(jsonBytes)
-	}
-
 	return nil
 }
 
-// Save persists the FilePvLastSignState to its filePath.
-func (signState *SignState) save(jsonBytes []byte) {
-	outFile := signState.filePath
-	if outFile == os.DevNull {
-		return
-	}
-	if outFile == "" {
-		panic("cannot save SignState: filePath not set")
-	}
+// SignStatePersistError is the error of a save that the store did not accept, e.g. a
+// *SignStateConflictError, which leaves the sign state as it was.
+type SignStatePersistError struct {
+	err error
+}
 
-	err := tempfile.WriteFileAtomic(outFile, jsonBytes, 0600)
-	if err != nil {
-		panic(err)
-	}
+func (e *SignStatePersistError) Error() string {
+	return fmt.Sprintf("failed to persist sign state: %v", e.err)
 }
 
+func (e *SignStatePersistError) Unwrap() error { return e.err }
+
 type HeightRegressionError struct {
 	regressed, last int64
 }
@@ -396,15 +419,17 @@ func newSameHRSError(hrs HRSKey) *SameHRSError {
 }
 
 func (signState *SignState) GetErrorIfLessOrEqual(height int64, round int64, step int8) error {
-	hrs := HRSKey{Height: height, Round: round, Step: step}
-	signStateHRS := signState.HRSKey()
+	return errorIfLessOrEqual(signState.HRSKey(), HRSKey{Height: height, Round: round, Step: step})
+}
+
+func errorIfLessOrEqual(signStateHRS, hrs HRSKey) error {
 	if signStateHRS.GreaterThan(hrs) {
 		return errors.New("regression not allowed")
 	}
 
 	if hrs == signStateHRS {
 		// same HRS as current
-		return newSameHRSError(HRSKey{Height: height, Round: round, Step: step})
+		return newSameHRSError(hrs)
 	}
 	// Step is greater, so all good
 	return nil
@@ -422,7 +447,8 @@ func (signState *SignState) FreshCache() *SignState {
 		SignBytes:   signState.SignBytes,
 		cache:       make(map[HRSKey]SignStateConsensus),
 
-		filePath: signState.filePath,
+		store:     signState.store,
+		persisted: signState.persisted,
 	}
 
 	newSignState.cond = cond.New(&newSignState.mu)
@@ -444,49 +470,45 @@ func (signState *SignState) FreshCache() *SignState {
 
 // LoadSignState loads a sign state from disk.
 func LoadSignState(filepath string) (*SignState, error) {
-	stateJSONBytes, err := os.ReadFile(filepath)
+	store := NewFileSignStateStore(filepath)
+	state, err := store.load()
 	if err != nil {
 		return nil, err
 	}
 
-	state := new(SignState)
-
-	err = cometjson.Unmarshal(stateJSONBytes, &state)
-	if err != nil {
-		return nil, err
-	}
-
-	state.filePath = filepath
-
-	return state.FreshCache(), nil
+	signState := newSignState(store, state.signStateConsensus())
+	signState.NoncePublic = state.NoncePublic
+	return signState, nil
 }
 
 // LoadOrCreateSignState loads the sign state from filepath
 // If the sign state could not be loaded, an empty sign state is initialized
 // and saved to filepath.
 func LoadOrCreateSignState(filepath string) (*SignState, error) {
-	if _, err := os.Stat(filepath); err != nil {
-		if !os.IsNotExist(err) {
-			return nil, fmt.Errorf("unexpected error checking file existence (%s): %w", filepath, err)
-		}
-		// the only scenario where we want to create a new sign state file is when the file does not exist.
-		// Make an empty sign state and save it.
-		state := &SignState{
-			filePath: filepath,
-			cache:    make(map[HRSKey]SignStateConsensus),
-		}
-		state.cond = cond.New(&state.mu)
-
-		jsonBytes, err := cometjson.MarshalIndent(state, "", "  ")
-		if err != nil {
-			panic(err)
-		}
+	return LoadOrCreateSignStateFromStore(NewFileSignStateStore(filepath))
+}
 
-		state.save(jsonBytes)
-		return state, nil
+// LoadOrCreateSignStateFromStore loads the sign state from the store.
+// If nothing has been persisted yet, an empty sign state is initialized and saved to the store.
+func LoadOrCreateSignStateFromStore(store SignStateStore) (*SignState, error) {
+	ssc, err := store.GetHRS()
+	if err != nil {
+		return nil, err
 	}
+	return newSignState(store, ssc), nil
+}
 
-	return LoadSignState(filepath)
+func newSignState(store SignStateStore, ssc SignStateConsensus) *SignState {
+	state := &SignState{
+		Height:    ssc.Height,
+		Round:     ssc.Round,
+		Step:      ssc.Step,
+		Signature: ssc.Signature,
+		SignBytes: ssc.SignBytes,
+		store:     store,
+		persisted: ssc.HRSKey(),
+	}
+	return state.FreshCache()
 }
 
 // OnlyDifferByTimestamp returns true if the sign bytes of the sign state
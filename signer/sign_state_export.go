@@ -0,0 +1,222 @@
+package signer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	cometbytes "github.com/cometbft/cometbft/libs/bytes"
+)
+
+// SignStateExport is the portable format of the sign states of a signer, used to
+// rebuild or relocate a cosigner without regressing its high watermarks.
+type SignStateExport struct {
+	Chains map[string]ChainSignStateExport `json:"chains"`
+}
+
+// ChainSignStateExport holds the sign states of a single chain.
+type ChainSignStateExport struct {
+	PrivVal  *ExportedSignState `json:"priv_validator,omitempty"`
+	Cosigner *ExportedSignState `json:"share_sign,omitempty"`
+}
+
+// ExportedSignState is the exported form of a sign state.
+type ExportedSignState struct {
+	Height    int64               `json:"height"`
+	Round     int64               `json:"round"`
+	Step      int8                `json:"step"`
+	Signature []byte              `json:"signature,omitempty"`
+	SignBytes cometbytes.HexBytes `json:"signbytes,omitempty"`
+}
+
+func (s ExportedSignState) signStateConsensus() SignStateConsensus {
+	return SignStateConsensus{
+		Height:    s.Height,
+		Round:     s.Round,
+		Step:      s.Step,
+		Signature: s.Signature,
+		SignBytes: s.SignBytes,
+	}
+}
+
+func (c RuntimeConfig) signStateKinds() map[SignStateKind]func(chainID string) string {
+	return map[SignStateKind]func(chainID string) string{
+		SignStateKindPrivVal:  c.PrivValStateFile,
+		SignStateKindCosigner: c.CosignerStateFile,
+	}
+}
+
+// SignStateChainIDs returns the chain IDs that have a sign state in the configured sign state store.
+// It fails if the store cannot list them, or if a database store has no sign state, so that an
+// export of all chains is never silently empty.
+func (c RuntimeConfig) SignStateChainIDs() ([]string, error) {
+	chainIDs, err := c.listSignStateChainIDs()
+	if err != nil {
+		return nil, err
+	}
+	if storeType := c.Config.SignState.storeType(); len(chainIDs) == 0 && storeType != SignStateStoreFile {
+		return nil, fmt.Errorf("the %s sign state store has no sign state, specify the chain IDs", storeType)
+	}
+	return chainIDs, nil
+}
+
+// listSignStateChainIDs returns the chain IDs that have a sign state in the configured sign state
+// store, which may be none.
+func (c RuntimeConfig) listSignStateChainIDs() ([]string, error) {
+	store, err := c.SignStateStore("", SignStateKindPrivVal)
+	if err != nil {
+		return nil, err
+	}
+	lister, ok := store.(SignStateChainLister)
+	if !ok {
+		return nil, fmt.Errorf("the %s sign state store cannot list its chain IDs, specify the chain IDs",
+			c.Config.SignState.storeType())
+	}
+	return lister.SignStateChainIDs()
+}
+
+// ExportSignStates exports the sign states of the chain IDs from the configured sign state store.
+func (c RuntimeConfig) ExportSignStates(chainIDs []string) (*SignStateExport, error) {
+	export := &SignStateExport{Chains: make(map[string]ChainSignStateExport, len(chainIDs))}
+	for _, chainID := range chainIDs {
+		var chain ChainSignStateExport
+		for kind, stateFile := range c.signStateKinds() {
+			if c.Config.SignState.storeType() == SignStateStoreFile {
+				// do not create sign state files that do not exist.
+				if _, err := os.Stat(stateFile(chainID)); os.IsNotExist(err) {
+					continue
+				}
+			}
+			store, err := c.SignStateStore(chainID, kind)
+			if err != nil {
+				return nil, err
+			}
+			ssc, err := store.GetHRS()
+			if err != nil {
+				return nil, fmt.Errorf("failed to load %s sign state for %s: %w", kind, chainID, err)
+			}
+			exported := &ExportedSignState{
+				Height:    ssc.Height,
+				Round:     ssc.Round,
+				Step:      ssc.Step,
+				Signature: ssc.Signature,
+				SignBytes: ssc.SignBytes,
+			}
+			switch kind {
+			case SignStateKindPrivVal:
+				chain.PrivVal = exported
+			case SignStateKindCosigner:
+				chain.Cosigner = exported
+			}
+		}
+		export.Chains[chainID] = chain
+	}
+	return export, nil
+}
+
+// LoadSignState loads the sign state of the chain and kind from the configured sign state store.
+// A sign state file that does not exist is an error.
+func (c RuntimeConfig) LoadSignState(chainID string, kind SignStateKind) (*SignState, error) {
+	if c.Config.SignState.storeType() == SignStateStoreFile {
+		stateFile, ok := c.signStateKinds()[kind]
+		if !ok {
+			return nil, fmt.Errorf("unknown sign state kind %q", kind)
+		}
+		return LoadSignState(stateFile(chainID))
+	}
+	return c.LoadOrCreateSignState(chainID, kind)
+}
+
+// LoadOrCreateSignState loads the sign state of the chain and kind from the configured sign state
+// store, initializing an empty sign state if nothing has been persisted yet.
+func (c RuntimeConfig) LoadOrCreateSignState(chainID string, kind SignStateKind) (*SignState, error) {
+	if c.Config.SignState.storeType() == SignStateStoreFile {
+		if err := os.MkdirAll(filepath.Clean(c.StateDir), 0700); err != nil {
+			return nil, err
+		}
+	}
+
+	store, err := c.SignStateStore(chainID, kind)
+	if err != nil {
+		return nil, err
+	}
+	ss, err := LoadOrCreateSignStateFromStore(store)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s sign state for %s: %w", kind, chainID, err)
+	}
+	return ss, nil
+}
+
+// SignStateImportResult describes the outcome of importing a single sign state.
+type SignStateImportResult struct {
+	ChainID  string
+	Kind     SignStateKind
+	Local    HRSKey
+	Imported HRSKey
+
+	// Updated is true if the imported sign state was ahead of the local one and was saved.
+	Updated bool
+}
+
+// ImportSignStates merges the exported sign states into the configured sign state store,
+// conservatively: for each chain and kind the greater HRS of the local and imported sign
+// state is kept, so importing can never regress a high watermark.
+func (c RuntimeConfig) ImportSignStates(export *SignStateExport) ([]SignStateImportResult, error) {
+	chainIDs := make([]string, 0, len(export.Chains))
+	for chainID := range export.Chains {
+		chainIDs = append(chainIDs, chainID)
+	}
+	sort.Strings(chainIDs)
+
+	var results []SignStateImportResult
+	for _, chainID := range chainIDs {
+		chain := export.Chains[chainID]
+		for _, imp := range []struct {
+			kind  SignStateKind
+			state *ExportedSignState
+		}{
+			{SignStateKindPrivVal, chain.PrivVal},
+			{SignStateKindCosigner, chain.Cosigner},
+		} {
+			if imp.state == nil {
+				continue
+			}
+			result, err := c.importSignState(chainID, imp.kind, imp.state.signStateConsensus())
+			if err != nil {
+				return results, err
+			}
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+func (c RuntimeConfig) importSignState(
+	chainID string,
+	kind SignStateKind,
+	imported SignStateConsensus,
+) (SignStateImportResult, error) {
+	result := SignStateImportResult{
+		ChainID:  chainID,
+		Kind:     kind,
+		Imported: imported.HRSKey(),
+	}
+
+	ss, err := c.LoadOrCreateSignState(chainID, kind)
+	if err != nil {
+		return result, err
+	}
+	result.Local = ss.HRSKey()
+
+	if !imported.HRSKey().GreaterThan(result.Local) {
+		// the local sign state is at or beyond the imported one, keep it.
+		return result, nil
+	}
+
+	if err := ss.Save(imported, nil); err != nil {
+		return result, fmt.Errorf("failed to save %s sign state for %s: %w", kind, chainID, err)
+	}
+	result.Updated = true
+	return result, nil
+}
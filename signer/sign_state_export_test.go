@@ -0,0 +1,85 @@
+package signer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignStateExportImport(t *testing.T) {
+	src := RuntimeConfig{StateDir: t.TempDir()}
+
+	pv, err := LoadOrCreateSignState(src.PrivValStateFile("chain-1"))
+	require.NoError(t, err)
+	require.NoError(t, pv.Save(SignStateConsensus{Height: 10, Round: 1, Step: stepPrecommit, Signature: []byte("sig")}, nil))
+
+	cs, err := LoadOrCreateSignState(src.CosignerStateFile("chain-1"))
+	require.NoError(t, err)
+	require.NoError(t, cs.Save(SignStateConsensus{Height: 10, Round: 1, Step: stepPrevote}, nil))
+
+	pv2, err := LoadOrCreateSignState(src.PrivValStateFile("chain-2"))
+	require.NoError(t, err)
+	require.NoError(t, pv2.Save(SignStateConsensus{Height: 5}, nil))
+
+	chainIDs, err := src.SignStateChainIDs()
+	require.NoError(t, err)
+	require.Equal(t, []string{"chain-1", "chain-2"}, chainIDs)
+
+	export, err := src.ExportSignStates(chainIDs)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), export.Chains["chain-1"].PrivVal.Height)
+	require.Equal(t, []byte("sig"), export.Chains["chain-1"].PrivVal.Signature)
+	require.Nil(t, export.Chains["chain-2"].Cosigner)
+	require.NoFileExists(t, src.CosignerStateFile("chain-2"))
+
+	// destination is ahead for chain-1 share sign state, behind for the rest.
+	dst := RuntimeConfig{StateDir: filepath.Join(t.TempDir(), "state")}
+	require.NoError(t, os.MkdirAll(dst.StateDir, 0700))
+	dcs, err := LoadOrCreateSignState(dst.CosignerStateFile("chain-1"))
+	require.NoError(t, err)
+	require.NoError(t, dcs.Save(SignStateConsensus{Height: 11}, nil))
+
+	results, err := dst.ImportSignStates(export)
+	require.NoError(t, err)
+	require.Equal(t, []SignStateImportResult{
+		{
+			ChainID:  "chain-1",
+			Kind:     SignStateKindPrivVal,
+			Local:    HRSKey{},
+			Imported: HRSKey{Height: 10, Round: 1, Step: stepPrecommit},
+			Updated:  true,
+		},
+		{
+			ChainID:  "chain-1",
+			Kind:     SignStateKindCosigner,
+			Local:    HRSKey{Height: 11},
+			Imported: HRSKey{Height: 10, Round: 1, Step: stepPrevote},
+			Updated:  false,
+		},
+		{
+			ChainID:  "chain-2",
+			Kind:     SignStateKindPrivVal,
+			Local:    HRSKey{},
+			Imported: HRSKey{Height: 5},
+			Updated:  true,
+		},
+	}, results)
+
+	ss, err := LoadSignState(dst.PrivValStateFile("chain-1"))
+	require.NoError(t, err)
+	require.Equal(t, HRSKey{Height: 10, Round: 1, Step: stepPrecommit}, ss.HRSKey())
+	require.Equal(t, []byte("sig"), ss.Signature)
+
+	ss, err = LoadSignState(dst.CosignerStateFile("chain-1"))
+	require.NoError(t, err)
+	require.Equal(t, int64(11), ss.Height)
+
+	// importing again is a no-op.
+	results, err = dst.ImportSignStates(export)
+	require.NoError(t, err)
+	for _, r := range results {
+		require.False(t, r.Updated)
+	}
+}
@@ -0,0 +1,275 @@
+package signer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// The formats of the sign state that horcrux state export writes and horcrux state import reads.
+const (
+	// SignStateFormatHorcrux is the sign state export of horcrux, SignStateExport, with the sign
+	// states of every kind.
+	SignStateFormatHorcrux = "horcrux"
+
+	// SignStateFormatInterchange is the sign state interchange format, SignStateInterchange, with
+	// the last signed height, round and step of each chain.
+	SignStateFormatInterchange = "interchange"
+
+	// SignStateFormatPrivValidator is the priv_validator_state.json of a CometBFT node, for a
+	// single chain.
+	SignStateFormatPrivValidator = "priv-validator"
+
+	// SignStateFormatTMKMS is the consensus state file of tmkms, for a single chain.
+	SignStateFormatTMKMS = "tmkms"
+
+	signStateInterchangeVersion = "1"
+)
+
+// SignStateFormats are the formats of the sign state that can be exported and imported.
+var SignStateFormats = []string{
+	SignStateFormatHorcrux,
+	SignStateFormatInterchange,
+	SignStateFormatPrivValidator,
+	SignStateFormatTMKMS,
+}
+
+// SignStateInterchange is the format to move the slashing protection of a validator between
+// signers, e.g. between horcrux, tmkms and a CometBFT node, in the manner of EIP-3076. It holds the
+// last signed height, round and step of each chain, without the sign bytes, so an imported sign
+// state never signs its height, round and step again.
+type SignStateInterchange struct {
+	Metadata SignStateInterchangeMetadata `json:"metadata"`
+	Data     []SignStateInterchangeChain  `json:"data"`
+}
+
+type SignStateInterchangeMetadata struct {
+	InterchangeFormatVersion string `json:"interchange_format_version"`
+}
+
+// SignStateInterchangeChain is the last signed height, round and step of a chain. A chain may be
+// listed more than once, e.g. when the interchanges of several signers are concatenated, in which
+// case the greatest is imported.
+type SignStateInterchangeChain struct {
+	ChainID    string                  `json:"chain_id"`
+	LastSigned SignStateInterchangeHRS `json:"last_signed"`
+}
+
+// SignStateInterchangeHRS is a height, round and step, with the height and round as strings like
+// in the JSON of CometBFT.
+type SignStateInterchangeHRS struct {
+	Height int64 `json:"height,string"`
+	Round  int64 `json:"round,string"`
+	Step   int8  `json:"step"`
+}
+
+func (hrs SignStateInterchangeHRS) hrsKey() HRSKey {
+	return HRSKey{Height: hrs.Height, Round: hrs.Round, Step: hrs.Step}
+}
+
+func (hrs SignStateInterchangeHRS) validate() error {
+	if hrs.Height < 0 || hrs.Round < 0 {
+		return fmt.Errorf("negative height %d or round %d", hrs.Height, hrs.Round)
+	}
+	if hrs.Step < 0 || hrs.Step > stepPrecommit {
+		return fmt.Errorf("invalid step %d, must be 0 to %d", hrs.Step, stepPrecommit)
+	}
+	return nil
+}
+
+// privValidatorSignState is the priv_validator_state.json of a CometBFT node.
+type privValidatorSignState struct {
+	Height int64 `json:"height,string"`
+	Round  int32 `json:"round"`
+	Step   int8  `json:"step"`
+}
+
+// tmkmsSignState is the consensus state file of tmkms, whose step is numbered from 0, unlike the
+// step of horcrux and CometBFT: see tmkmsStep.
+type tmkmsSignState struct {
+	Height  int64           `json:"height,string"`
+	Round   int64           `json:"round,string"`
+	Step    int8            `json:"step"`
+	BlockID json.RawMessage `json:"block_id"`
+}
+
+// The steps of the consensus state file of tmkms.
+const (
+	tmkmsStepPropose   int8 = 0
+	tmkmsStepPrevote   int8 = 1
+	tmkmsStepPrecommit int8 = 2
+)
+
+// tmkmsStep returns the step of tmkms of the step of horcrux. tmkms has no step for a height at
+// which nothing is signed yet, which is exported as a proposal, so that tmkms does not sign below
+// what horcrux may have signed.
+func tmkmsStep(step int8) (int8, error) {
+	switch step {
+	case 0, stepPropose:
+		return tmkmsStepPropose, nil
+	case stepPrevote:
+		return tmkmsStepPrevote, nil
+	case stepPrecommit:
+		return tmkmsStepPrecommit, nil
+	default:
+		return 0, fmt.Errorf("invalid step %d, must be 0 to %d", step, stepPrecommit)
+	}
+}
+
+// stepFromTMKMS returns the step of horcrux of the step of tmkms.
+func stepFromTMKMS(step int8) (int8, error) {
+	switch step {
+	case tmkmsStepPropose:
+		return stepPropose, nil
+	case tmkmsStepPrevote:
+		return stepPrevote, nil
+	case tmkmsStepPrecommit:
+		return stepPrecommit, nil
+	default:
+		return 0, fmt.Errorf("invalid tmkms step %d, must be %d to %d", step, tmkmsStepPropose, tmkmsStepPrecommit)
+	}
+}
+
+func (ic *SignStateInterchange) Validate() error {
+	if v := ic.Metadata.InterchangeFormatVersion; v != signStateInterchangeVersion {
+		return fmt.Errorf("unsupported sign state interchange format version %q, must be %q",
+			v, signStateInterchangeVersion)
+	}
+	for _, chain := range ic.Data {
+		if chain.ChainID == "" {
+			return fmt.Errorf("sign state interchange has a chain without chain_id")
+		}
+		if err := chain.LastSigned.validate(); err != nil {
+			return fmt.Errorf("invalid sign state of chain %s in interchange: %w", chain.ChainID, err)
+		}
+	}
+	return nil
+}
+
+// NewSignStateInterchange returns the interchange of the sign state export, with the greatest
+// height, round and step of the sign states of each chain.
+func NewSignStateInterchange(export *SignStateExport) *SignStateInterchange {
+	ic := &SignStateInterchange{
+		Metadata: SignStateInterchangeMetadata{InterchangeFormatVersion: signStateInterchangeVersion},
+		Data:     make([]SignStateInterchangeChain, 0, len(export.Chains)),
+	}
+	for chainID, chain := range export.Chains {
+		var last HRSKey
+		for _, s := range []*ExportedSignState{chain.PrivVal, chain.Cosigner} {
+			if s != nil {
+				if hrs := s.signStateConsensus().HRSKey(); hrs.GreaterThan(last) {
+					last = hrs
+				}
+			}
+		}
+		ic.Data = append(ic.Data, SignStateInterchangeChain{
+			ChainID:    chainID,
+			LastSigned: SignStateInterchangeHRS{Height: last.Height, Round: last.Round, Step: last.Step},
+		})
+	}
+	sort.Slice(ic.Data, func(i, j int) bool { return ic.Data[i].ChainID < ic.Data[j].ChainID })
+	return ic
+}
+
+// SignStateExport returns the sign state export that imports the greatest height, round and step
+// of each chain of the interchange into the sign states of every kind, so that neither the signer
+// nor its share of a threshold signer signs at or below it.
+func (ic *SignStateInterchange) SignStateExport() (*SignStateExport, error) {
+	if err := ic.Validate(); err != nil {
+		return nil, err
+	}
+	last := make(map[string]HRSKey, len(ic.Data))
+	for _, chain := range ic.Data {
+		hrs := chain.LastSigned.hrsKey()
+		if prev, ok := last[chain.ChainID]; !ok || hrs.GreaterThan(prev) {
+			last[chain.ChainID] = hrs
+		}
+	}
+	export := &SignStateExport{Chains: make(map[string]ChainSignStateExport, len(last))}
+	for chainID, hrs := range last {
+		export.Chains[chainID] = ChainSignStateExport{
+			PrivVal:  &ExportedSignState{Height: hrs.Height, Round: hrs.Round, Step: hrs.Step},
+			Cosigner: &ExportedSignState{Height: hrs.Height, Round: hrs.Round, Step: hrs.Step},
+		}
+	}
+	return export, nil
+}
+
+// MarshalSignStateInterchange returns the interchange in the format, interchange, priv-validator or
+// tmkms. The priv-validator and tmkms formats hold the sign state of a single chain.
+func MarshalSignStateInterchange(ic *SignStateInterchange, format string) ([]byte, error) {
+	if format == SignStateFormatInterchange {
+		return json.MarshalIndent(ic, "", "  ")
+	}
+	if len(ic.Data) != 1 {
+		return nil, fmt.Errorf("%s sign state format holds a single chain, not %d", format, len(ic.Data))
+	}
+	hrs := ic.Data[0].LastSigned
+	switch format {
+	case SignStateFormatPrivValidator:
+		return json.MarshalIndent(privValidatorSignState{
+			Height: hrs.Height,
+			Round:  int32(hrs.Round),
+			Step:   hrs.Step,
+		}, "", "  ")
+	case SignStateFormatTMKMS:
+		step, err := tmkmsStep(hrs.Step)
+		if err != nil {
+			return nil, err
+		}
+		return json.MarshalIndent(tmkmsSignState{
+			Height:  hrs.Height,
+			Round:   hrs.Round,
+			Step:    step,
+			BlockID: json.RawMessage("null"),
+		}, "", "  ")
+	default:
+		return nil, fmt.Errorf("unknown sign state format %q, must be one of %v", format, SignStateFormats[1:])
+	}
+}
+
+// ParseSignStateInterchange parses the sign state in the format, interchange, priv-validator or
+// tmkms. The priv-validator and tmkms formats do not name their chain, which is chainID.
+func ParseSignStateInterchange(bz []byte, format, chainID string) (*SignStateInterchange, error) {
+	var hrs SignStateInterchangeHRS
+	switch format {
+	case SignStateFormatInterchange:
+		ic := new(SignStateInterchange)
+		if err := json.Unmarshal(bz, ic); err != nil {
+			return nil, fmt.Errorf("failed to parse sign state interchange: %w", err)
+		}
+		if err := ic.Validate(); err != nil {
+			return nil, err
+		}
+		return ic, nil
+	case SignStateFormatPrivValidator:
+		var s privValidatorSignState
+		if err := json.Unmarshal(bz, &s); err != nil {
+			return nil, fmt.Errorf("failed to parse priv_validator_state.json: %w", err)
+		}
+		hrs = SignStateInterchangeHRS{Height: s.Height, Round: int64(s.Round), Step: s.Step}
+	case SignStateFormatTMKMS:
+		var s tmkmsSignState
+		if err := json.Unmarshal(bz, &s); err != nil {
+			return nil, fmt.Errorf("failed to parse tmkms consensus state: %w", err)
+		}
+		step, err := stepFromTMKMS(s.Step)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse tmkms consensus state: %w", err)
+		}
+		hrs = SignStateInterchangeHRS{Height: s.Height, Round: s.Round, Step: step}
+	default:
+		return nil, fmt.Errorf("unknown sign state format %q, must be one of %v", format, SignStateFormats[1:])
+	}
+	if chainID == "" {
+		return nil, fmt.Errorf("%s sign state format requires the chain ID", format)
+	}
+	ic := &SignStateInterchange{
+		Metadata: SignStateInterchangeMetadata{InterchangeFormatVersion: signStateInterchangeVersion},
+		Data:     []SignStateInterchangeChain{{ChainID: chainID, LastSigned: hrs}},
+	}
+	if err := ic.Validate(); err != nil {
+		return nil, err
+	}
+	return ic, nil
+}
@@ -0,0 +1,138 @@
+package signer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignStateInterchange(t *testing.T) {
+	export := &SignStateExport{Chains: map[string]ChainSignStateExport{
+		"chain-1": {
+			PrivVal:  &ExportedSignState{Height: 10, Round: 1, Step: stepPrevote, Signature: []byte("sig")},
+			Cosigner: &ExportedSignState{Height: 10, Round: 1, Step: stepPrecommit},
+		},
+		"chain-2": {PrivVal: &ExportedSignState{Height: 5}},
+	}}
+
+	ic := NewSignStateInterchange(export)
+	require.Equal(t, []SignStateInterchangeChain{
+		{ChainID: "chain-1", LastSigned: SignStateInterchangeHRS{Height: 10, Round: 1, Step: stepPrecommit}},
+		{ChainID: "chain-2", LastSigned: SignStateInterchangeHRS{Height: 5}},
+	}, ic.Data)
+
+	bz, err := MarshalSignStateInterchange(ic, SignStateFormatInterchange)
+	require.NoError(t, err)
+	require.Contains(t, string(bz), `"interchange_format_version": "1"`)
+	require.Contains(t, string(bz), `"height": "10"`)
+
+	parsed, err := ParseSignStateInterchange(bz, SignStateFormatInterchange, "")
+	require.NoError(t, err)
+	require.Equal(t, ic, parsed)
+
+	// the priv-validator and tmkms formats hold a single chain.
+	_, err = MarshalSignStateInterchange(ic, SignStateFormatTMKMS)
+	require.Error(t, err)
+
+	// a chain listed more than once imports the greatest height, round and step, into every kind,
+	// without the signature.
+	parsed.Data = append(parsed.Data, SignStateInterchangeChain{
+		ChainID:    "chain-2",
+		LastSigned: SignStateInterchangeHRS{Height: 7, Round: 2, Step: stepPropose},
+	}, SignStateInterchangeChain{
+		ChainID:    "chain-2",
+		LastSigned: SignStateInterchangeHRS{Height: 6},
+	})
+	imported, err := parsed.SignStateExport()
+	require.NoError(t, err)
+	require.Equal(t, &SignStateExport{Chains: map[string]ChainSignStateExport{
+		"chain-1": {
+			PrivVal:  &ExportedSignState{Height: 10, Round: 1, Step: stepPrecommit},
+			Cosigner: &ExportedSignState{Height: 10, Round: 1, Step: stepPrecommit},
+		},
+		"chain-2": {
+			PrivVal:  &ExportedSignState{Height: 7, Round: 2, Step: stepPropose},
+			Cosigner: &ExportedSignState{Height: 7, Round: 2, Step: stepPropose},
+		},
+	}}, imported)
+
+	const v1 = `{"metadata": {"interchange_format_version": "1"}, "data": `
+	for _, bz := range []string{
+		`{"metadata": {}, "data": []}`,
+		v1 + `[{"last_signed": {"height": "1", "round": "0", "step": 1}}]}`,
+		v1 + `[{"chain_id": "a", "last_signed": {"height": "1", "round": "0", "step": 4}}]}`,
+		v1 + `[{"chain_id": "a", "last_signed": {"height": "-1", "round": "0", "step": 1}}]}`,
+		v1 + `[{"chain_id": "a", "last_signed": {"height": 1}}]}`,
+	} {
+		_, err := ParseSignStateInterchange([]byte(bz), SignStateFormatInterchange, "")
+		require.Error(t, err, bz)
+	}
+}
+
+func TestSignStateInterchangeSingleChain(t *testing.T) {
+	want := &SignStateInterchange{
+		Metadata: SignStateInterchangeMetadata{InterchangeFormatVersion: signStateInterchangeVersion},
+		Data: []SignStateInterchangeChain{
+			{ChainID: "cosmoshub-4", LastSigned: SignStateInterchangeHRS{Height: 123, Round: 1, Step: stepPrecommit}},
+		},
+	}
+
+	// a priv_validator_state.json of a CometBFT node, and a consensus state file of tmkms.
+	for format, bz := range map[string]string{
+		SignStateFormatPrivValidator: `{"height": "123", "round": 1, "step": 3, "signature": "c2ln", "signbytes": "AB"}`,
+		SignStateFormatTMKMS:         `{"height": "123", "round": "1", "step": 2, "block_id": null}`,
+	} {
+		ic, err := ParseSignStateInterchange([]byte(bz), format, "cosmoshub-4")
+		require.NoError(t, err, format)
+		require.Equal(t, want, ic, format)
+
+		_, err = ParseSignStateInterchange([]byte(bz), format, "")
+		require.ErrorContains(t, err, "requires the chain ID", format)
+
+		out, err := MarshalSignStateInterchange(ic, format)
+		require.NoError(t, err, format)
+		ic, err = ParseSignStateInterchange(out, format, "cosmoshub-4")
+		require.NoError(t, err, format)
+		require.Equal(t, want, ic, format)
+	}
+
+	_, err := ParseSignStateInterchange([]byte(`{}`), "lighthouse", "cosmoshub-4")
+	require.ErrorContains(t, err, "unknown sign state format")
+}
+
+func TestSignStateInterchangeTMKMSSteps(t *testing.T) {
+	// tmkms numbers the steps from 0, horcrux and CometBFT from 1.
+	for step, tmkms := range map[int8]int8{stepPropose: 0, stepPrevote: 1, stepPrecommit: 2} {
+		ic := &SignStateInterchange{
+			Metadata: SignStateInterchangeMetadata{InterchangeFormatVersion: signStateInterchangeVersion},
+			Data: []SignStateInterchangeChain{
+				{ChainID: "cosmoshub-4", LastSigned: SignStateInterchangeHRS{Height: 123, Round: 1, Step: step}},
+			},
+		}
+		bz, err := MarshalSignStateInterchange(ic, SignStateFormatTMKMS)
+		require.NoError(t, err)
+		var s tmkmsSignState
+		require.NoError(t, json.Unmarshal(bz, &s))
+		require.Equal(t, tmkms, s.Step, step)
+
+		parsed, err := ParseSignStateInterchange(bz, SignStateFormatTMKMS, "cosmoshub-4")
+		require.NoError(t, err)
+		require.Equal(t, ic, parsed, step)
+	}
+
+	// a height at which nothing is signed yet is exported as a proposal.
+	bz, err := MarshalSignStateInterchange(&SignStateInterchange{
+		Data: []SignStateInterchangeChain{{ChainID: "cosmoshub-4", LastSigned: SignStateInterchangeHRS{Height: 5}}},
+	}, SignStateFormatTMKMS)
+	require.NoError(t, err)
+	require.Contains(t, string(bz), `"step": 0`)
+
+	for _, bz := range []string{
+		`{"height": "123", "round": "1", "step": 3, "block_id": null}`,
+		`{"height": "123", "round": "1", "step": -1, "block_id": null}`,
+	} {
+		_, err := ParseSignStateInterchange([]byte(bz), SignStateFormatTMKMS, "cosmoshub-4")
+		require.ErrorContains(t, err, "invalid tmkms step", bz)
+	}
+}
@@ -0,0 +1,259 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+)
+
+// signStateGetter is implemented by cosigners that can report their sign state for a chain.
+type signStateGetter interface {
+	// GetSignState returns the greatest HRS the cosigner has signed for the chain,
+	// or nil if it has no sign state for the chain.
+	GetSignState(ctx context.Context, chainID string) (*HRSKey, error)
+}
+
+type SignStateRecoveryError struct {
+	msg string
+}
+
+func (e *SignStateRecoveryError) Error() string { return e.msg }
+
+func newSignStateRecoveryError(chainID string, responses, required int) *SignStateRecoveryError {
+	return &SignStateRecoveryError{
+		msg: fmt.Sprintf("insufficient peer sign states to recover chain %s: %d responses, %d required",
+			chainID, responses, required),
+	}
+}
+
+// SignStateRecovery recovers a missing or corrupt sign state from the sign states of peer cosigners.
+//
+// Every HRS is signed by threshold cosigners, so at least threshold-1 peers of a cosigner
+// participated in anything it has signed. Any n-threshold+1 peers therefore include at least
+// one of them, and the maximum of their sign states is at or beyond the lost sign state.
+type SignStateRecovery struct {
+	logger       cometlog.Logger
+	peers        []Cosigner
+	threshold    int
+	minResponses int
+	timeout      time.Duration
+	mu           sync.RWMutex
+}
+
+// NewSignStateRecovery returns a SignStateRecovery for the peers of a cosigner in a cluster
+// with the threshold.
+func NewSignStateRecovery(
+	logger cometlog.Logger,
+	peers []Cosigner,
+	threshold int,
+	timeout time.Duration,
+) *SignStateRecovery {
+	total := len(peers) + 1
+	return &SignStateRecovery{
+		logger:       logger,
+		peers:        peers,
+		threshold:    threshold,
+		minResponses: total - threshold + 1,
+		timeout:      timeout,
+	}
+}
+
+// SetPeers changes the peers that are queried for their sign states. The required responses only
+// grow, since a removed peer may have signed HRSs that only the remaining peers that signed with it
+// can vouch for.
+func (r *SignStateRecovery) SetPeers(peers []Cosigner) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.peers = peers
+	r.minResponses = max(r.minResponses, len(peers)+1-r.threshold+1)
+}
+
+// Recover queries the peers for their sign state of the chain and returns the maximum.
+// An empty HRS is returned if none of the responding peers have a sign state for the chain.
+func (r *SignStateRecovery) Recover(chainID string) (HRSKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	r.mu.RLock()
+	peers, minResponses := r.peers, r.minResponses
+	r.mu.RUnlock()
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		responses int
+		recovered HRSKey
+	)
+	for _, peer := range peers {
+		getter, ok := peer.(signStateGetter)
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(peer Cosigner, getter signStateGetter) {
+			defer wg.Done()
+			hrs, err := getter.GetSignState(ctx, chainID)
+			if err != nil {
+				r.logger.Error(
+					"Failed to get sign state from peer",
+					"chain_id", chainID,
+					"cosigner", peer.GetID(),
+					"error", err,
+				)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			responses++
+			if hrs != nil && hrs.GreaterThan(recovered) {
+				recovered = *hrs
+			}
+		}(peer, getter)
+	}
+	wg.Wait()
+
+	if responses < minResponses {
+		return HRSKey{}, newSignStateRecoveryError(chainID, responses, minResponses)
+	}
+	return recovered, nil
+}
+
+// peekSignState returns the persisted HRS of the chain and kind without creating a sign state,
+// or nil if there is none.
+func (c RuntimeConfig) peekSignState(chainID string, kind SignStateKind) (*HRSKey, error) {
+	ssc, err := c.ReadSignState(chainID, kind)
+	if err != nil || ssc == nil {
+		return nil, err
+	}
+	hrs := ssc.HRSKey()
+	return &hrs, nil
+}
+
+// ReadSignState returns the persisted sign state of the chain and kind without creating a sign
+// state, or nil if there is none.
+func (c RuntimeConfig) ReadSignState(chainID string, kind SignStateKind) (*SignStateConsensus, error) {
+	store, err := c.SignStateStore(chainID, kind)
+	if err != nil {
+		return nil, err
+	}
+	if fileStore, ok := store.(*FileSignStateStore); ok {
+		if _, err := os.Stat(fileStore.filePath); os.IsNotExist(err) {
+			return nil, nil
+		}
+	}
+	ssc, err := store.GetHRS()
+	if err != nil {
+		return nil, err
+	}
+	return &ssc, nil
+}
+
+// loadSignState loads the sign state of the chain and kind from the configured store.
+// If recovery is set and the sign state file is missing or corrupt, the sign state is
+// initialized from the sign states of the peer cosigners. A corrupt file is kept with
+// a .corrupt suffix. A sign state that has never been signed with is initialized at the
+// latest block height if the chain RPC of the chain is configured to do so.
+func (c RuntimeConfig) loadSignState(
+	logger cometlog.Logger,
+	chainID string,
+	kind SignStateKind,
+	recovery *SignStateRecovery,
+) (*SignState, error) {
+	signState, err := c.loadOrRecoverSignState(logger, chainID, kind, recovery)
+	if err != nil {
+		return nil, err
+	}
+	if signState.HRSKey() != (HRSKey{}) {
+		return signState, nil
+	}
+
+	height, err := c.initialSignStateHeight(chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize %s sign state from chain rpc: %w", kind, err)
+	}
+	if height == 0 {
+		return signState, nil
+	}
+	if err := signState.Save(NewSignStateConsensus(height, 0, 0), nil); err != nil {
+		return nil, err
+	}
+	logger.Info("Initialized sign state at chain tip", "chain_id", chainID, "kind", kind, "height", height)
+	return signState, nil
+}
+
+func (c RuntimeConfig) loadOrRecoverSignState(
+	logger cometlog.Logger,
+	chainID string,
+	kind SignStateKind,
+	recovery *SignStateRecovery,
+) (*SignState, error) {
+	store, err := c.SignStateStore(chainID, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	fileStore, ok := store.(*FileSignStateStore)
+	if recovery == nil || !ok {
+		return LoadOrCreateSignStateFromStore(store)
+	}
+
+	_, err = os.Stat(fileStore.filePath)
+	switch {
+	case err == nil:
+		signState, err := LoadOrCreateSignStateFromStore(store)
+		if err == nil {
+			return signState, nil
+		}
+		var decryptionErr *StateFileDecryptionError
+		if errors.As(err, &decryptionErr) {
+			// a missing or wrong passphrase is not a corrupt sign state.
+			return nil, err
+		}
+		corruptPath := fileStore.filePath + ".corrupt"
+		logger.Error(
+			"Sign state is corrupt, recovering from peers",
+			"chain_id", chainID,
+			"kind", kind,
+			"moved_to", corruptPath,
+			"error", err,
+		)
+		if err := os.Rename(fileStore.filePath, corruptPath); err != nil {
+			return nil, err
+		}
+	case os.IsNotExist(err):
+		logger.Info("Sign state is missing, recovering from peers", "chain_id", chainID, "kind", kind)
+	default:
+		return nil, err
+	}
+
+	hrs, err := recovery.Recover(chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover %s sign state from peers: %w", kind, err)
+	}
+
+	signState, err := LoadOrCreateSignStateFromStore(store)
+	if err != nil {
+		return nil, err
+	}
+	if hrs.GreaterThan(signState.HRSKey()) {
+		if err := signState.Save(NewSignStateConsensus(hrs.Height, hrs.Round, hrs.Step), nil); err != nil {
+			return nil, err
+		}
+		totalSignStateRecoveries.WithLabelValues(chainID).Inc()
+	}
+
+	logger.Info(
+		"Recovered sign state from peers",
+		"chain_id", chainID,
+		"kind", kind,
+		"height", hrs.Height,
+		"round", hrs.Round,
+		"step", hrs.Step,
+	)
+	return signState, nil
+}
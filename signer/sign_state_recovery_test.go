@@ -0,0 +1,93 @@
+package signer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+)
+
+func testRecoveryPeer(t *testing.T, id int, hrs *HRSKey) *LocalCosigner {
+	dir := t.TempDir()
+	cosigner := NewLocalCosigner(
+		log.NewNopLogger(),
+		&RuntimeConfig{
+			HomeDir:  dir,
+			StateDir: dir,
+			Config: Config{
+				ThresholdModeConfig: &ThresholdModeConfig{
+					Cosigners: CosignersConfig{{ShardID: id}},
+				},
+			},
+		},
+		NewCosignerSecurityECIES(CosignerECIESKey{ID: id}),
+		"",
+	)
+	if hrs != nil {
+		ss, err := LoadOrCreateSignState(cosigner.config.CosignerStateFile(testChainID))
+		require.NoError(t, err)
+		require.NoError(t, ss.Save(NewSignStateConsensus(hrs.Height, hrs.Round, hrs.Step), nil))
+	}
+	return cosigner
+}
+
+func TestSignStateRecovery(t *testing.T) {
+	peers := []Cosigner{
+		testRecoveryPeer(t, 2, &HRSKey{Height: 10, Round: 0, Step: stepPrecommit}),
+		testRecoveryPeer(t, 3, &HRSKey{Height: 12, Round: 1, Step: stepPrevote}),
+		testRecoveryPeer(t, 4, nil),
+	}
+
+	// 3 of 4: any 2 peers include one that took part in the latest signature.
+	recovery := NewSignStateRecovery(log.NewNopLogger(), peers, 3, time.Second)
+	require.Equal(t, 2, recovery.minResponses)
+
+	hrs, err := recovery.Recover(testChainID)
+	require.NoError(t, err)
+	require.Equal(t, HRSKey{Height: 12, Round: 1, Step: stepPrevote}, hrs)
+
+	dir := t.TempDir()
+	c := RuntimeConfig{StateDir: dir}
+
+	// missing sign state is recovered.
+	ss, err := c.loadSignState(log.NewNopLogger(), testChainID, SignStateKindCosigner, recovery)
+	require.NoError(t, err)
+	require.Equal(t, hrs, ss.HRSKey())
+
+	persisted, err := c.peekSignState(testChainID, SignStateKindCosigner)
+	require.NoError(t, err)
+	require.Equal(t, hrs, *persisted)
+
+	// corrupt sign state is moved aside and recovered.
+	stateFile := c.PrivValStateFile(testChainID)
+	require.NoError(t, os.WriteFile(stateFile, []byte(`{"height":`), 0600))
+
+	ss, err = c.loadSignState(log.NewNopLogger(), testChainID, SignStateKindPrivVal, recovery)
+	require.NoError(t, err)
+	require.Equal(t, hrs, ss.HRSKey())
+	require.FileExists(t, stateFile+".corrupt")
+}
+
+func TestSignStateRecoveryInsufficientPeers(t *testing.T) {
+	unreadable := testRecoveryPeer(t, 3, nil)
+	require.NoError(t, os.WriteFile(unreadable.config.CosignerStateFile(testChainID), []byte(`{"height":`), 0600))
+
+	peers := []Cosigner{
+		testRecoveryPeer(t, 2, &HRSKey{Height: 10, Round: 0, Step: stepPrecommit}),
+		unreadable,
+	}
+
+	// 2 of 3 requires both peers to respond.
+	recovery := NewSignStateRecovery(log.NewNopLogger(), peers, 2, time.Second)
+
+	c := RuntimeConfig{StateDir: t.TempDir()}
+	_, err := c.loadSignState(log.NewNopLogger(), testChainID, SignStateKindCosigner, recovery)
+	var recoveryErr *SignStateRecoveryError
+	require.ErrorAs(t, err, &recoveryErr)
+
+	// no sign state is created if recovery fails.
+	require.NoFileExists(t, filepath.Join(c.StateDir, testChainID+"_share_sign_state.json"))
+}
@@ -0,0 +1,294 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	cometbytes "github.com/cometbft/cometbft/libs/bytes"
+	cometjson "github.com/cometbft/cometbft/libs/json"
+)
+
+const (
+	// SignStateStoreFile persists sign state as JSON files in the state directory.
+	SignStateStoreFile = "file"
+)
+
+// SignStateKind distinguishes the sign states persisted for each chain.
+type SignStateKind string
+
+const (
+	// SignStateKindPrivVal is the sign state of the validator, i.e. fully assembled signatures.
+	SignStateKindPrivVal SignStateKind = "priv_validator"
+
+	// SignStateKindCosigner is the sign state of the local cosigner's signature shares.
+	SignStateKindCosigner SignStateKind = "share_sign"
+)
+
+// SignStateStore persists the high watermark sign state for a single chain and kind.
+// Implementations must make SetHRS atomic with respect to the comparison so that
+// two signers sharing a store can never both advance from the same HRS.
+type SignStateStore interface {
+	// GetHRS returns the persisted sign state. If nothing has been persisted yet,
+	// an empty sign state is persisted and returned.
+	GetHRS() (SignStateConsensus, error)
+
+	// SetHRS persists next only if the persisted HRS is still prev (compare-and-set).
+	// It returns a *SignStateConflictError if the persisted HRS has changed.
+	SetHRS(prev HRSKey, next SignStateConsensus) error
+}
+
+// SignStateChainLister is implemented by the sign state stores that can list the chain IDs
+// that have a persisted sign state, of any kind, in the same file directory or database.
+type SignStateChainLister interface {
+	SignStateChainIDs() ([]string, error)
+}
+
+type SignStateConflictError struct {
+	msg string
+}
+
+func (e *SignStateConflictError) Error() string { return e.msg }
+
+func newSignStateConflictError(expected, actual HRSKey) *SignStateConflictError {
+	return &SignStateConflictError{
+		msg: fmt.Sprintf("sign state changed concurrently. expected %d.%d.%d, persisted %d.%d.%d",
+			expected.Height, expected.Round, expected.Step,
+			actual.Height, actual.Round, actual.Step,
+		),
+	}
+}
+
+// SignStateStoreConfig is the on disk config format for the sign state store.
+type SignStateStoreConfig struct {
+	Type string `yaml:"type"`
+
+	// DSN is the connection string for the postgres store.
+	DSN string `yaml:"dsn,omitempty"`
+
+	// Table is the table name for the postgres store. Defaults to horcrux_sign_state.
+	Table string `yaml:"table,omitempty"`
+
+	// Path is the database file for the sqlite store. Relative paths are resolved
+	// against the state directory. Defaults to sign_state.db.
+	Path string `yaml:"path,omitempty"`
+
+	// SignerID distinguishes the rows of each signer when multiple signers share a database.
+	SignerID string `yaml:"signerID,omitempty"`
+}
+
+func (cfg *SignStateStoreConfig) storeType() string {
+	if cfg == nil || cfg.Type == "" {
+		return SignStateStoreFile
+	}
+	return cfg.Type
+}
+
+func (cfg *SignStateStoreConfig) Validate() error {
+	switch cfg.storeType() {
+	case SignStateStoreFile, SignStateStoreSQLite:
+		return nil
+	case SignStateStorePostgres:
+		if cfg.DSN == "" {
+			return fmt.Errorf("postgres sign state store requires dsn")
+		}
+		if !postgresTableNameRegexp.MatchString(cfg.postgresTable()) {
+			return fmt.Errorf("invalid postgres sign state table name: %s", cfg.postgresTable())
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown sign state store %q, must be one of %v",
+			cfg.storeType(), []string{SignStateStoreFile, SignStateStorePostgres, SignStateStoreSQLite})
+	}
+}
+
+func (cfg *SignStateStoreConfig) postgresTable() string {
+	if cfg.Table == "" {
+		return defaultPostgresSignStateTable
+	}
+	return cfg.Table
+}
+
+// SignStateStore returns the configured SignStateStore for the chain ID and kind.
+func (c RuntimeConfig) SignStateStore(chainID string, kind SignStateKind) (SignStateStore, error) {
+	if err := c.Config.SignState.Validate(); err != nil {
+		return nil, err
+	}
+	switch c.Config.SignState.storeType() {
+	case SignStateStoreFile:
+		switch kind {
+		case SignStateKindPrivVal:
+			return NewFileSignStateStore(c.PrivValStateFile(chainID)), nil
+		case SignStateKindCosigner:
+			return NewFileSignStateStore(c.CosignerStateFile(chainID)), nil
+		default:
+			return nil, fmt.Errorf("unknown sign state kind %q", kind)
+		}
+	case SignStateStorePostgres:
+		cfg := c.Config.SignState
+		db, err := openPostgresSignStateDB(cfg.DSN, cfg.postgresTable())
+		if err != nil {
+			return nil, err
+		}
+		return NewPostgresSignStateStore(db, cfg.postgresTable(), cfg.SignerID, chainID, kind), nil
+	case SignStateStoreSQLite:
+		db, err := openSQLiteSignStateDB(c.sqliteSignStateFile())
+		if err != nil {
+			return nil, err
+		}
+		return NewSQLiteSignStateStore(db, chainID, kind), nil
+	default:
+		return nil, fmt.Errorf("unknown sign state store %q", c.Config.SignState.storeType())
+	}
+}
+
+func (c RuntimeConfig) sqliteSignStateFile() string {
+	path := c.Config.SignState.Path
+	if path == "" {
+		path = defaultSQLiteSignStateFile
+	}
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(c.StateDir, path)
+}
+
+var (
+	_ SignStateStore       = &FileSignStateStore{}
+	_ SignStateChainLister = &FileSignStateStore{}
+)
+
+// FileSignStateStore persists the sign state as JSON to a file.
+// The file is assumed to be owned by this process, so the compare-and-set is
+// made against the last state read from or written to the file.
+type FileSignStateStore struct {
+	filePath string
+
+	mu        sync.Mutex
+	persisted *HRSKey
+}
+
+// fileSignState is the on disk format of the sign state file.
+type fileSignState struct {
+	Height      int64               `json:"height"`
+	Round       int64               `json:"round"`
+	Step        int8                `json:"step"`
+	NoncePublic []byte              `json:"nonce_public"`
+	Signature   []byte              `json:"signature,omitempty"`
+	SignBytes   cometbytes.HexBytes `json:"signbytes,omitempty"`
+}
+
+// NewFileSignStateStore returns a FileSignStateStore for the file path.
+func NewFileSignStateStore(filePath string) *FileSignStateStore {
+	return &FileSignStateStore{filePath: filePath}
+}
+
+// SignStateChainIDs returns the chain IDs that have a sign state file in the directory of the file.
+func (s *FileSignStateStore) SignStateChainIDs() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Dir(s.filePath))
+	if err != nil {
+		return nil, err
+	}
+	chainIDs := make(map[string]struct{})
+	for _, e := range entries {
+		for _, kind := range []SignStateKind{SignStateKindPrivVal, SignStateKindCosigner} {
+			suffix := fmt.Sprintf("_%s_state.json", kind)
+			if chainID, ok := strings.CutSuffix(e.Name(), suffix); ok && chainID != "" {
+				chainIDs[chainID] = struct{}{}
+			}
+		}
+	}
+	out := make([]string, 0, len(chainIDs))
+	for chainID := range chainIDs {
+		out = append(out, chainID)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func (s *FileSignStateStore) load() (*fileSignState, error) {
+	stateJSONBytes, err := readStateFile(s.filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	state := new(fileSignState)
+	if err := cometjson.Unmarshal(stateJSONBytes, &state); err != nil {
+		return nil, err
+	}
+
+	hrs := HRSKey{Height: state.Height, Round: state.Round, Step: state.Step}
+	s.persisted = &hrs
+	return state, nil
+}
+
+func (state *fileSignState) signStateConsensus() SignStateConsensus {
+	return SignStateConsensus{
+		Height:    state.Height,
+		Round:     state.Round,
+		Step:      state.Step,
+		Signature: state.Signature,
+		SignBytes: state.SignBytes,
+	}
+}
+
+func (s *FileSignStateStore) GetHRS() (SignStateConsensus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := s.load()
+	if err == nil {
+		return state.signStateConsensus(), nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return SignStateConsensus{}, err
+	}
+
+	// the only scenario where we want to create a new sign state file is when the file does not exist.
+	if err := s.write(SignStateConsensus{}); err != nil {
+		return SignStateConsensus{}, err
+	}
+	return SignStateConsensus{}, nil
+}
+
+func (s *FileSignStateStore) SetHRS(prev HRSKey, next SignStateConsensus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.persisted != nil && *s.persisted != prev {
+		return newSignStateConflictError(prev, *s.persisted)
+	}
+	return s.write(next)
+}
+
+func (s *FileSignStateStore) write(ssc SignStateConsensus) error {
+	if s.filePath == os.DevNull {
+		return nil
+	}
+	if s.filePath == "" {
+		return fmt.Errorf("cannot save SignState: filePath not set")
+	}
+
+	jsonBytes, err := cometjson.MarshalIndent(fileSignState{
+		Height:    ssc.Height,
+		Round:     ssc.Round,
+		Step:      ssc.Step,
+		Signature: ssc.Signature,
+		SignBytes: ssc.SignBytes,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := writeStateFile(s.filePath, jsonBytes); err != nil {
+		return err
+	}
+
+	hrs := ssc.HRSKey()
+	s.persisted = &hrs
+	return nil
+}
@@ -0,0 +1,205 @@
+package signer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	// postgres driver for database/sql
+	_ "github.com/lib/pq"
+)
+
+const (
+	// SignStateStorePostgres persists sign state in a PostgreSQL table.
+	SignStateStorePostgres = "postgres"
+
+	defaultPostgresSignStateTable = "horcrux_sign_state"
+	postgresSignStateTimeout      = 5 * time.Second
+)
+
+var postgresTableNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+var (
+	postgresDBsMu sync.Mutex
+	postgresDBs   = make(map[string]*sql.DB)
+)
+
+// openPostgresSignStateDB returns a shared connection pool for the DSN and
+// creates the sign state table if it does not exist.
+func openPostgresSignStateDB(dsn, table string) (*sql.DB, error) {
+	postgresDBsMu.Lock()
+	defer postgresDBsMu.Unlock()
+
+	key := dsn + "\x00" + table
+	if db, ok := postgresDBs[key]; ok {
+		return db, nil
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres sign state database: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), postgresSignStateTimeout)
+	defer cancel()
+
+	if err := createPostgresSignStateTable(ctx, db, table); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	postgresDBs[key] = db
+	return db, nil
+}
+
+func createPostgresSignStateTable(ctx context.Context, db *sql.DB, table string) error {
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	signer_id TEXT NOT NULL,
+	chain_id TEXT NOT NULL,
+	kind TEXT NOT NULL,
+	height BIGINT NOT NULL,
+	round BIGINT NOT NULL,
+	step SMALLINT NOT NULL,
+	signature BYTEA,
+	sign_bytes BYTEA,
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	PRIMARY KEY (signer_id, chain_id, kind)
+)`, table))
+	if err != nil {
+		return fmt.Errorf("failed to create postgres sign state table %s: %w", table, err)
+	}
+	return nil
+}
+
+var (
+	_ SignStateStore       = &PostgresSignStateStore{}
+	_ SignStateChainLister = &PostgresSignStateStore{}
+)
+
+// PostgresSignStateStore persists the sign state as a row in PostgreSQL.
+// SetHRS locks the row for the duration of the compare-and-set, and only allows
+// the HRS to strictly increase. The sign state is saved before a signature is
+// returned, so if multiple signers are misconfigured to share the same row, only
+// the first to save an HRS returns its signature and the others are refused.
+type PostgresSignStateStore struct {
+	db    *sql.DB
+	table string
+
+	signerID string
+	chainID  string
+	kind     SignStateKind
+}
+
+// NewPostgresSignStateStore returns a PostgresSignStateStore for the row identified
+// by signer ID, chain ID and kind in table.
+func NewPostgresSignStateStore(
+	db *sql.DB,
+	table string,
+	signerID string,
+	chainID string,
+	kind SignStateKind,
+) *PostgresSignStateStore {
+	return &PostgresSignStateStore{
+		db:       db,
+		table:    table,
+		signerID: signerID,
+		chainID:  chainID,
+		kind:     kind,
+	}
+}
+
+func (s *PostgresSignStateStore) GetHRS() (SignStateConsensus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresSignStateTimeout)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO %s (signer_id, chain_id, kind, height, round, step)
+VALUES ($1, $2, $3, 0, 0, 0)
+ON CONFLICT (signer_id, chain_id, kind) DO NOTHING`, s.table),
+		s.signerID, s.chainID, string(s.kind),
+	)
+	if err != nil {
+		return SignStateConsensus{}, fmt.Errorf("failed to initialize postgres sign state: %w", err)
+	}
+
+	var ssc SignStateConsensus
+	var signBytes []byte
+	err = s.db.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT height, round, step, signature, sign_bytes FROM %s
+WHERE signer_id = $1 AND chain_id = $2 AND kind = $3`, s.table),
+		s.signerID, s.chainID, string(s.kind),
+	).Scan(&ssc.Height, &ssc.Round, &ssc.Step, &ssc.Signature, &signBytes)
+	if err != nil {
+		return SignStateConsensus{}, fmt.Errorf("failed to read postgres sign state: %w", err)
+	}
+	ssc.SignBytes = signBytes
+	return ssc, nil
+}
+
+// SignStateChainIDs returns the chain IDs that have a sign state of the signer ID in the table.
+func (s *PostgresSignStateStore) SignStateChainIDs() ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresSignStateTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(
+		`SELECT DISTINCT chain_id FROM %s WHERE signer_id = $1 ORDER BY chain_id`, s.table),
+		s.signerID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list postgres sign state chain IDs: %w", err)
+	}
+	return scanSignStateChainIDs(rows)
+}
+
+func (s *PostgresSignStateStore) SetHRS(prev HRSKey, next SignStateConsensus) error {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresSignStateTimeout)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin postgres sign state transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var persisted HRSKey
+	err = tx.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT height, round, step FROM %s
+WHERE signer_id = $1 AND chain_id = $2 AND kind = $3
+FOR UPDATE`, s.table),
+		s.signerID, s.chainID, string(s.kind),
+	).Scan(&persisted.Height, &persisted.Round, &persisted.Step)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("postgres sign state not initialized for chain %s", s.chainID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock postgres sign state: %w", err)
+	}
+
+	if persisted != prev {
+		return newSignStateConflictError(prev, persisted)
+	}
+	if !next.HRSKey().GreaterThan(persisted) {
+		return newSignStateConflictError(next.HRSKey(), persisted)
+	}
+
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(
+		`UPDATE %s SET height = $4, round = $5, step = $6, signature = $7, sign_bytes = $8, updated_at = now()
+WHERE signer_id = $1 AND chain_id = $2 AND kind = $3`, s.table),
+		s.signerID, s.chainID, string(s.kind),
+		next.Height, next.Round, next.Step, next.Signature, []byte(next.SignBytes),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update postgres sign state: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit postgres sign state: %w", err)
+	}
+	return nil
+}
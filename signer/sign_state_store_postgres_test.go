@@ -0,0 +1,136 @@
+package signer
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresSignStateStoreGetHRS(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := NewPostgresSignStateStore(db, "horcrux_sign_state", "cosigner-1", "chain-1", SignStateKindCosigner)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO horcrux_sign_state")).
+		WithArgs("cosigner-1", "chain-1", "share_sign").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT height, round, step, signature, sign_bytes FROM horcrux_sign_state")).
+		WithArgs("cosigner-1", "chain-1", "share_sign").
+		WillReturnRows(sqlmock.NewRows([]string{"height", "round", "step", "signature", "sign_bytes"}).
+			AddRow(10, 1, stepPrecommit, []byte("sig"), []byte("sb")))
+
+	ssc, err := store.GetHRS()
+	require.NoError(t, err)
+	require.Equal(t, HRSKey{Height: 10, Round: 1, Step: stepPrecommit}, ssc.HRSKey())
+	require.Equal(t, []byte("sig"), ssc.Signature)
+	require.Equal(t, []byte("sb"), []byte(ssc.SignBytes))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresSignStateStoreSetHRS(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := NewPostgresSignStateStore(db, "horcrux_sign_state", "cosigner-1", "chain-1", SignStateKindCosigner)
+
+	lockQuery := regexp.QuoteMeta("SELECT height, round, step FROM horcrux_sign_state") + `(?s).*FOR UPDATE`
+	hrsRows := func(hrs HRSKey) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"height", "round", "step"}).AddRow(hrs.Height, hrs.Round, hrs.Step)
+	}
+
+	prev := HRSKey{Height: 10, Round: 0, Step: stepPrevote}
+	next := SignStateConsensus{Height: 10, Round: 0, Step: stepPrecommit, Signature: []byte("sig"), SignBytes: []byte("sb")}
+
+	// persisted HRS matches, update is committed.
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockQuery).WithArgs("cosigner-1", "chain-1", "share_sign").WillReturnRows(hrsRows(prev))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE horcrux_sign_state")).
+		WithArgs("cosigner-1", "chain-1", "share_sign", next.Height, next.Round, next.Step, next.Signature, []byte("sb")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, store.SetHRS(prev, next))
+
+	// another signer advanced the row, transaction is rolled back.
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockQuery).WithArgs("cosigner-1", "chain-1", "share_sign").
+		WillReturnRows(hrsRows(HRSKey{Height: 11}))
+	mock.ExpectRollback()
+
+	var conflictErr *SignStateConflictError
+	require.ErrorAs(t, store.SetHRS(prev, next), &conflictErr)
+
+	// regression is refused even if the compare matches.
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockQuery).WithArgs("cosigner-1", "chain-1", "share_sign").WillReturnRows(hrsRows(next.HRSKey()))
+	mock.ExpectRollback()
+
+	require.ErrorAs(t, store.SetHRS(next.HRSKey(), SignStateConsensus{Height: 9}), &conflictErr)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresSignStateStoreSharedRow(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	// two signers misconfigured with the same signer ID share the row.
+	storeA := NewPostgresSignStateStore(db, "horcrux_sign_state", "cosigner-1", "chain-1", SignStateKindCosigner)
+	storeB := NewPostgresSignStateStore(db, "horcrux_sign_state", "cosigner-1", "chain-1", SignStateKindCosigner)
+
+	lockQuery := regexp.QuoteMeta("SELECT height, round, step FROM horcrux_sign_state") + `(?s).*FOR UPDATE`
+	row := HRSKey{Height: 10, Round: 0, Step: stepPrevote}
+	hrsRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"height", "round", "step"}).AddRow(row.Height, row.Round, row.Step)
+	}
+	expectGetHRS := func() {
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO horcrux_sign_state")).
+			WithArgs("cosigner-1", "chain-1", "share_sign").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT height, round, step, signature, sign_bytes FROM horcrux_sign_state")).
+			WithArgs("cosigner-1", "chain-1", "share_sign").
+			WillReturnRows(sqlmock.NewRows([]string{"height", "round", "step", "signature", "sign_bytes"}).
+				AddRow(row.Height, row.Round, row.Step, nil, nil))
+	}
+
+	expectGetHRS()
+	ssA, err := LoadOrCreateSignStateFromStore(storeA)
+	require.NoError(t, err)
+	expectGetHRS()
+	ssB, err := LoadOrCreateSignStateFromStore(storeB)
+	require.NoError(t, err)
+
+	signA := SignStateConsensus{Height: 10, Step: stepPrecommit, Signature: []byte("sig-a"), SignBytes: []byte("sb-a")}
+	signB := SignStateConsensus{Height: 10, Step: stepPrecommit, Signature: []byte("sig-b"), SignBytes: []byte("sb-b")}
+
+	// the first signer to lock the row persists its precommit, and may return its signature.
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockQuery).WithArgs("cosigner-1", "chain-1", "share_sign").WillReturnRows(hrsRows())
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE horcrux_sign_state")).
+		WithArgs("cosigner-1", "chain-1", "share_sign",
+			signA.Height, signA.Round, signA.Step, signA.Signature, []byte(signA.SignBytes)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	require.NoError(t, ssA.Save(signA, nil))
+	row = signA.HRSKey()
+
+	// the second signer finds the row advanced underneath it, and its save fails, so that
+	// the signature of the conflicting precommit is never returned.
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockQuery).WithArgs("cosigner-1", "chain-1", "share_sign").WillReturnRows(hrsRows())
+	mock.ExpectRollback()
+	var conflictErr *SignStateConflictError
+	require.ErrorAs(t, ssB.Save(signB, nil), &conflictErr)
+	require.Equal(t, HRSKey{Height: 10, Round: 0, Step: stepPrevote}, ssB.HRSKey())
+	_, cached := ssB.GetFromCache(signB.HRSKey())
+	require.Nil(t, cached)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,183 @@
+package signer
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	// sqlite driver for database/sql
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	// SignStateStoreSQLite persists sign state in a single SQLite database file with WAL journaling.
+	SignStateStoreSQLite = "sqlite"
+
+	defaultSQLiteSignStateFile = "sign_state.db"
+)
+
+var (
+	sqliteDBsMu sync.Mutex
+	sqliteDBs   = make(map[string]*sql.DB)
+)
+
+// openSQLiteSignStateDB returns a shared handle for the database file and creates
+// the sign state table if it does not exist.
+// Transactions are started with BEGIN IMMEDIATE so that the write lock is held from
+// the read of the persisted HRS until commit, and every commit is fsynced.
+func openSQLiteSignStateDB(path string) (*sql.DB, error) {
+	sqliteDBsMu.Lock()
+	defer sqliteDBsMu.Unlock()
+
+	if db, ok := sqliteDBs[path]; ok {
+		return db, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("_journal_mode", "WAL")
+	params.Set("_synchronous", "FULL")
+	params.Set("_busy_timeout", "5000")
+	params.Set("_txlock", "immediate")
+
+	db, err := sql.Open("sqlite3", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite sign state database: %w", err)
+	}
+
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS sign_state (
+	chain_id TEXT NOT NULL,
+	kind TEXT NOT NULL,
+	height INTEGER NOT NULL,
+	round INTEGER NOT NULL,
+	step INTEGER NOT NULL,
+	signature BLOB,
+	sign_bytes BLOB,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (chain_id, kind)
+)`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create sqlite sign state table: %w", err)
+	}
+
+	sqliteDBs[path] = db
+	return db, nil
+}
+
+var (
+	_ SignStateStore       = &SQLiteSignStateStore{}
+	_ SignStateChainLister = &SQLiteSignStateStore{}
+)
+
+// SQLiteSignStateStore persists the sign state as a row in a SQLite database.
+// Each update is a single transaction, so the state is never left partially
+// written if the process or host crashes mid-write.
+type SQLiteSignStateStore struct {
+	db *sql.DB
+
+	chainID string
+	kind    SignStateKind
+}
+
+// NewSQLiteSignStateStore returns a SQLiteSignStateStore for the row identified by chain ID and kind.
+func NewSQLiteSignStateStore(db *sql.DB, chainID string, kind SignStateKind) *SQLiteSignStateStore {
+	return &SQLiteSignStateStore{
+		db:      db,
+		chainID: chainID,
+		kind:    kind,
+	}
+}
+
+func (s *SQLiteSignStateStore) GetHRS() (SignStateConsensus, error) {
+	_, err := s.db.Exec(`INSERT INTO sign_state (chain_id, kind, height, round, step)
+VALUES (?, ?, 0, 0, 0)
+ON CONFLICT (chain_id, kind) DO NOTHING`,
+		s.chainID, string(s.kind),
+	)
+	if err != nil {
+		return SignStateConsensus{}, fmt.Errorf("failed to initialize sqlite sign state: %w", err)
+	}
+
+	var ssc SignStateConsensus
+	var signBytes []byte
+	err = s.db.QueryRow(`SELECT height, round, step, signature, sign_bytes FROM sign_state
+WHERE chain_id = ? AND kind = ?`,
+		s.chainID, string(s.kind),
+	).Scan(&ssc.Height, &ssc.Round, &ssc.Step, &ssc.Signature, &signBytes)
+	if err != nil {
+		return SignStateConsensus{}, fmt.Errorf("failed to read sqlite sign state: %w", err)
+	}
+	ssc.SignBytes = signBytes
+	return ssc, nil
+}
+
+// SignStateChainIDs returns the chain IDs that have a sign state in the database.
+func (s *SQLiteSignStateStore) SignStateChainIDs() ([]string, error) {
+	rows, err := s.db.Query(`SELECT DISTINCT chain_id FROM sign_state ORDER BY chain_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sqlite sign state chain IDs: %w", err)
+	}
+	return scanSignStateChainIDs(rows)
+}
+
+func (s *SQLiteSignStateStore) SetHRS(prev HRSKey, next SignStateConsensus) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin sqlite sign state transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var persisted HRSKey
+	err = tx.QueryRow(`SELECT height, round, step FROM sign_state
+WHERE chain_id = ? AND kind = ?`,
+		s.chainID, string(s.kind),
+	).Scan(&persisted.Height, &persisted.Round, &persisted.Step)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("sqlite sign state not initialized for chain %s", s.chainID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read sqlite sign state: %w", err)
+	}
+
+	if persisted != prev {
+		return newSignStateConflictError(prev, persisted)
+	}
+
+	_, err = tx.Exec(`UPDATE sign_state
+SET height = ?, round = ?, step = ?, signature = ?, sign_bytes = ?, updated_at = CURRENT_TIMESTAMP
+WHERE chain_id = ? AND kind = ?`,
+		next.Height, next.Round, next.Step, next.Signature, []byte(next.SignBytes),
+		s.chainID, string(s.kind),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update sqlite sign state: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit sqlite sign state: %w", err)
+	}
+	return nil
+}
+
+// scanSignStateChainIDs returns the chain IDs of the rows and closes them.
+func scanSignStateChainIDs(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+	var chainIDs []string
+	for rows.Next() {
+		var chainID string
+		if err := rows.Scan(&chainID); err != nil {
+			return nil, err
+		}
+		chainIDs = append(chainIDs, chainID)
+	}
+	return chainIDs, rows.Err()
+}
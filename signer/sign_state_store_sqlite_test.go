@@ -0,0 +1,69 @@
+package signer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteSignStateStore(t *testing.T) {
+	c := RuntimeConfig{
+		StateDir: t.TempDir(),
+		Config:   Config{SignState: &SignStateStoreConfig{Type: SignStateStoreSQLite}},
+	}
+
+	store, err := c.SignStateStore("chain-1", SignStateKindPrivVal)
+	require.NoError(t, err)
+
+	var journalMode string
+	require.NoError(t, store.(*SQLiteSignStateStore).db.QueryRow("PRAGMA journal_mode").Scan(&journalMode))
+	require.Equal(t, "wal", journalMode)
+	require.FileExists(t, filepath.Join(c.StateDir, "sign_state.db"))
+
+	ssc, err := store.GetHRS()
+	require.NoError(t, err)
+	require.Equal(t, HRSKey{}, ssc.HRSKey())
+
+	next := SignStateConsensus{Height: 1, Round: 0, Step: stepPrevote, Signature: []byte("sig"), SignBytes: []byte("sb")}
+	require.NoError(t, store.SetHRS(HRSKey{}, next))
+
+	// stale compare fails
+	var conflictErr *SignStateConflictError
+	require.ErrorAs(t, store.SetHRS(HRSKey{}, SignStateConsensus{Height: 2}), &conflictErr)
+
+	// cosigner state for the same chain is kept in a separate row.
+	cosignerStore, err := c.SignStateStore("chain-1", SignStateKindCosigner)
+	require.NoError(t, err)
+	ssc, err = cosignerStore.GetHRS()
+	require.NoError(t, err)
+	require.Equal(t, HRSKey{}, ssc.HRSKey())
+
+	ss, err := LoadOrCreateSignStateFromStore(store)
+	require.NoError(t, err)
+	require.Equal(t, next.HRSKey(), ss.HRSKey())
+	require.Equal(t, []byte("sig"), ss.Signature)
+	require.Equal(t, []byte("sb"), []byte(ss.SignBytes))
+}
+
+func TestSQLiteSignStateChainIDs(t *testing.T) {
+	c := RuntimeConfig{
+		StateDir: t.TempDir(),
+		Config:   Config{SignState: &SignStateStoreConfig{Type: SignStateStoreSQLite}},
+	}
+
+	// an export of all chains of an empty database fails rather than export nothing.
+	_, err := c.SignStateChainIDs()
+	require.ErrorContains(t, err, "the sqlite sign state store has no sign state, specify the chain IDs")
+
+	for _, chainID := range []string{"chain-2", "chain-1"} {
+		for _, kind := range []SignStateKind{SignStateKindPrivVal, SignStateKindCosigner} {
+			_, err := c.LoadOrCreateSignState(chainID, kind)
+			require.NoError(t, err)
+		}
+	}
+
+	chainIDs, err := c.SignStateChainIDs()
+	require.NoError(t, err)
+	require.Equal(t, []string{"chain-1", "chain-2"}, chainIDs)
+}
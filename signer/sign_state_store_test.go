@@ -0,0 +1,118 @@
+package signer
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSignStateStore(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "chain-1_priv_validator_state.json")
+
+	store := NewFileSignStateStore(stateFile)
+
+	ssc, err := store.GetHRS()
+	require.NoError(t, err)
+	require.Equal(t, SignStateConsensus{}, ssc)
+
+	next := SignStateConsensus{Height: 1, Round: 0, Step: stepPrevote, Signature: []byte("sig"), SignBytes: []byte("sb")}
+	require.NoError(t, store.SetHRS(HRSKey{}, next))
+
+	// stale compare fails
+	var conflictErr *SignStateConflictError
+	require.ErrorAs(t, store.SetHRS(HRSKey{}, SignStateConsensus{Height: 2}), &conflictErr)
+
+	ss, err := LoadSignState(stateFile)
+	require.NoError(t, err)
+	require.Equal(t, next.HRSKey(), ss.HRSKey())
+	require.Equal(t, []byte("sig"), ss.Signature)
+}
+
+func TestSignStateSaveOrdered(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "chain-1_share_sign_state.json")
+
+	ss, err := LoadOrCreateSignState(stateFile)
+	require.NoError(t, err)
+
+	// saves must reach the store in order for the compare-and-set to succeed.
+	var wg sync.WaitGroup
+	for h := int64(1); h <= 50; h++ {
+		require.NoError(t, ss.Save(NewSignStateConsensus(h, 0, stepPrecommit), &wg))
+	}
+	wg.Wait()
+
+	loaded, err := LoadSignState(stateFile)
+	require.NoError(t, err)
+	require.Equal(t, HRSKey{Height: 50, Round: 0, Step: stepPrecommit}, loaded.HRSKey())
+
+	require.Error(t, ss.Save(NewSignStateConsensus(49, 0, stepPrecommit), nil))
+}
+
+// failingSignStateStore fails to persist while err is set.
+type failingSignStateStore struct {
+	SignStateStore
+	err error
+}
+
+func (s *failingSignStateStore) SetHRS(prev HRSKey, next SignStateConsensus) error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.SignStateStore.SetHRS(prev, next)
+}
+
+func TestSignStateSavePersistFailure(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "chain-1_priv_validator_state.json")
+	store := &failingSignStateStore{SignStateStore: NewFileSignStateStore(stateFile)}
+
+	ss, err := LoadOrCreateSignStateFromStore(store)
+	require.NoError(t, err)
+	require.NoError(t, ss.Save(NewSignStateConsensus(1, 0, stepPrecommit), nil))
+
+	// a save the store does not accept fails the sign, and leaves the sign state as it was.
+	store.err = errors.New("connection refused")
+	next := SignStateConsensus{Height: 2, Step: stepPrecommit, Signature: []byte("sig"), SignBytes: []byte("sb")}
+	err = ss.Save(next, nil)
+	var persistErr *SignStatePersistError
+	require.ErrorAs(t, err, &persistErr)
+	require.ErrorContains(t, err, "connection refused")
+	require.Equal(t, HRSKey{Height: 1, Step: stepPrecommit}, ss.HRSKey())
+	_, cached := ss.GetFromCache(next.HRSKey())
+	require.Nil(t, cached)
+
+	// once the store recovers, the save advances from the HRS the store has.
+	store.err = nil
+	require.NoError(t, ss.Save(next, nil))
+	loaded, err := LoadSignState(stateFile)
+	require.NoError(t, err)
+	require.Equal(t, next.HRSKey(), loaded.HRSKey())
+
+	// a conflict is a sign error rather than a panic.
+	store.err = newSignStateConflictError(next.HRSKey(), HRSKey{Height: 3})
+	err = ss.Save(NewSignStateConsensus(3, 0, stepPrecommit), nil)
+	var conflictErr *SignStateConflictError
+	require.ErrorAs(t, err, &conflictErr)
+}
+
+func TestSignStateStoreConfig(t *testing.T) {
+	c := RuntimeConfig{StateDir: t.TempDir()}
+
+	store, err := c.SignStateStore("chain-1", SignStateKindPrivVal)
+	require.NoError(t, err)
+	require.Equal(t, c.PrivValStateFile("chain-1"), store.(*FileSignStateStore).filePath)
+
+	c.Config.SignState = &SignStateStoreConfig{Type: "unknown"}
+	_, err = c.SignStateStore("chain-1", SignStateKindPrivVal)
+	require.EqualError(t, err, `unknown sign state store "unknown", must be one of [file postgres sqlite]`)
+}
+
+func TestSignStateStoreConfigPostgres(t *testing.T) {
+	require.EqualError(t, (&SignStateStoreConfig{Type: SignStateStorePostgres}).Validate(),
+		"postgres sign state store requires dsn")
+	require.EqualError(t, (&SignStateStoreConfig{Type: SignStateStorePostgres, DSN: "x", Table: "a;drop"}).Validate(),
+		"invalid postgres sign state table name: a;drop")
+	require.NoError(t, (&SignStateStoreConfig{Type: SignStateStorePostgres, DSN: "x", Table: "horcrux.sign_state"}).Validate())
+}
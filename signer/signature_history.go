@@ -0,0 +1,246 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	cometbytes "github.com/cometbft/cometbft/libs/bytes"
+	"github.com/cometbft/cometbft/libs/protoio"
+	cometproto "github.com/cometbft/cometbft/proto/tendermint/types"
+)
+
+const defaultSignatureHistorySize = 1000
+
+// SignatureHistoryConfig configures the history of recent signatures kept for audits.
+type SignatureHistoryConfig struct {
+	// Size is the number of signatures kept per chain. Defaults to 1000.
+	Size int `yaml:"size,omitempty"`
+}
+
+func (cfg *SignatureHistoryConfig) Validate() error {
+	if cfg == nil {
+		return nil
+	}
+	if cfg.Size < 0 {
+		return fmt.Errorf("signature history size must not be negative")
+	}
+	return nil
+}
+
+// SignedPayload is a record of a signature returned to a sentry.
+type SignedPayload struct {
+	Height      int64               `json:"height"`
+	Round       int64               `json:"round"`
+	Step        int8                `json:"step"`
+	BlockIDHash cometbytes.HexBytes `json:"block_id_hash,omitempty"`
+	Timestamp   time.Time           `json:"timestamp"`
+	SignedAt    time.Time           `json:"signed_at"`
+	Signature   []byte              `json:"signature"`
+	SignBytes   cometbytes.HexBytes `json:"signbytes"`
+}
+
+// signatureRing is a fixed size ring buffer of the most recent signatures of a chain.
+type signatureRing struct {
+	entries []SignedPayload
+	next    int
+	full    bool
+}
+
+func (r *signatureRing) add(p SignedPayload) {
+	r.entries[r.next] = p
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// newestFirst returns the entries, most recent first.
+func (r *signatureRing) newestFirst() []SignedPayload {
+	n := r.next
+	if r.full {
+		n = len(r.entries)
+	}
+	out := make([]SignedPayload, 0, n)
+	for i := 1; i <= n; i++ {
+		out = append(out, r.entries[(r.next-i+len(r.entries))%len(r.entries)])
+	}
+	return out
+}
+
+// SignatureHistory keeps the most recent signatures of each chain in memory, so that
+// operators can retrieve exactly what was signed during an incident.
+type SignatureHistory struct {
+	size int
+
+	mu     sync.RWMutex
+	chains map[string]*signatureRing
+}
+
+// NewSignatureHistory returns a SignatureHistory that keeps the last size signatures per chain.
+// If size is not positive, the default size is used.
+func NewSignatureHistory(size int) *SignatureHistory {
+	if size <= 0 {
+		size = defaultSignatureHistorySize
+	}
+	return &SignatureHistory{
+		size:   size,
+		chains: make(map[string]*signatureRing),
+	}
+}
+
+// Record adds a signature to the history of the chain.
+func (h *SignatureHistory) Record(chainID string, p SignedPayload) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	r, ok := h.chains[chainID]
+	if !ok {
+		r = &signatureRing{entries: make([]SignedPayload, h.size)}
+		h.chains[chainID] = r
+	}
+	r.add(p)
+}
+
+// Get returns the recorded signatures of the chain, most recent first. If height is greater
+// than zero, only signatures at that height are returned. If limit is greater than zero,
+// at most limit signatures are returned.
+func (h *SignatureHistory) Get(chainID string, height int64, limit int) []SignedPayload {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	r, ok := h.chains[chainID]
+	if !ok {
+		return nil
+	}
+	var out []SignedPayload
+	for _, p := range r.newestFirst() {
+		if height > 0 && p.Height != height {
+			continue
+		}
+		out = append(out, p)
+		if limit > 0 && len(out) == limit {
+			break
+		}
+	}
+	return out
+}
+
+// ChainIDs returns the chain IDs with recorded signatures.
+func (h *SignatureHistory) ChainIDs() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	out := make([]string, 0, len(h.chains))
+	for chainID := range h.chains {
+		out = append(out, chainID)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// ServeHTTP serves the history as JSON. The chain_id query parameter is required unless there
+// is only one chain, and the height and limit query parameters filter the signatures.
+func (h *SignatureHistory) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	chainID := query.Get("chain_id")
+	if chainID == "" {
+		chainIDs := h.ChainIDs()
+		if len(chainIDs) != 1 {
+			http.Error(w, fmt.Sprintf("chain_id is required, one of %v", chainIDs), http.StatusBadRequest)
+			return
+		}
+		chainID = chainIDs[0]
+	}
+
+	var height int64
+	if v := query.Get("height"); v != "" {
+		var err error
+		if height, err = strconv.ParseInt(v, 10, 64); err != nil {
+			http.Error(w, fmt.Sprintf("invalid height: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	var limit int
+	if v := query.Get("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil {
+			http.Error(w, fmt.Sprintf("invalid limit: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(struct {
+		ChainID    string          `json:"chain_id"`
+		Signatures []SignedPayload `json:"signatures"`
+	}{
+		ChainID:    chainID,
+		Signatures: h.Get(chainID, height, limit),
+	})
+}
+
+// SignatureHistoryValidator is a PrivValidator that records every signature it returns.
+type SignatureHistoryValidator struct {
+	val     PrivValidator
+	history *SignatureHistory
+}
+
+// NewSignatureHistoryValidator returns a SignatureHistoryValidator that records the signatures
+// of val in history.
+func NewSignatureHistoryValidator(val PrivValidator, history *SignatureHistory) *SignatureHistoryValidator {
+	return &SignatureHistoryValidator{
+		val:     val,
+		history: history,
+	}
+}
+
+// Sign implements PrivValidator.
+func (v *SignatureHistoryValidator) Sign(ctx context.Context, chainID string, block Block) ([]byte, time.Time, error) {
+	sig, timestamp, err := v.val.Sign(ctx, chainID, block)
+	if err != nil {
+		return sig, timestamp, err
+	}
+	v.history.Record(chainID, SignedPayload{
+		Height:      block.Height,
+		Round:       block.Round,
+		Step:        block.Step,
+		BlockIDHash: signBytesBlockIDHash(block.Step, block.SignBytes),
+		Timestamp:   timestamp,
+		SignedAt:    time.Now(),
+		Signature:   sig,
+		SignBytes:   block.SignBytes,
+	})
+	return sig, timestamp, nil
+}
+
+// GetPubKey implements PrivValidator.
+func (v *SignatureHistoryValidator) GetPubKey(ctx context.Context, chainID string) ([]byte, error) {
+	return v.val.GetPubKey(ctx, chainID)
+}
+
+// Stop implements PrivValidator.
+func (v *SignatureHistoryValidator) Stop() {
+	v.val.Stop()
+}
+
+// signBytesBlockIDHash returns the block ID hash of CometBFT canonical sign bytes,
+// or nil for nil votes and sign bytes that are not in the CometBFT encoding.
+func signBytesBlockIDHash(step int8, signBytes []byte) []byte {
+	if step == stepPropose {
+		var proposal cometproto.CanonicalProposal
+		if err := protoio.UnmarshalDelimited(signBytes, &proposal); err != nil || proposal.BlockID == nil {
+			return nil
+		}
+		return proposal.BlockID.Hash
+	}
+	var vote cometproto.CanonicalVote
+	if err := protoio.UnmarshalDelimited(signBytes, &vote); err != nil || vote.BlockID == nil {
+		return nil
+	}
+	return vote.BlockID.Hash
+}
@@ -0,0 +1,73 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cometproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	comet "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignatureHistory(t *testing.T) {
+	history := NewSignatureHistory(3)
+	for h := int64(1); h <= 5; h++ {
+		history.Record(testChainID, SignedPayload{Height: h, Step: stepPrevote})
+	}
+
+	got := history.Get(testChainID, 0, 0)
+	require.Len(t, got, 3)
+	require.Equal(t, []int64{5, 4, 3}, []int64{got[0].Height, got[1].Height, got[2].Height})
+
+	require.Len(t, history.Get(testChainID, 0, 2), 2)
+	require.Len(t, history.Get(testChainID, 4, 0), 1)
+	require.Empty(t, history.Get(testChainID, 1, 0))
+	require.Empty(t, history.Get("other-chain", 0, 0))
+}
+
+func TestSignatureHistoryValidator(t *testing.T) {
+	history := NewSignatureHistory(0)
+	val := NewSignatureHistoryValidator(&mockPrivValidator{}, history)
+
+	blockHash := []byte("0123456789abcdef0123456789abcdef")
+	vote := cometproto.Vote{
+		Type:    cometproto.PrecommitType,
+		Height:  10,
+		Round:   1,
+		BlockID: cometproto.BlockID{Hash: blockHash},
+	}
+	block := Block{
+		Height:    10,
+		Round:     1,
+		Step:      stepPrecommit,
+		SignBytes: comet.VoteSignBytes(testChainID, &vote),
+		Timestamp: time.Now(),
+	}
+
+	sig, _, err := val.Sign(context.Background(), testChainID, block)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	history.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/signatures?height=10", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var res struct {
+		ChainID    string          `json:"chain_id"`
+		Signatures []SignedPayload `json:"signatures"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	require.Equal(t, testChainID, res.ChainID)
+	require.Len(t, res.Signatures, 1)
+	require.Equal(t, int64(10), res.Signatures[0].Height)
+	require.Equal(t, stepPrecommit, res.Signatures[0].Step)
+	require.Equal(t, blockHash, []byte(res.Signatures[0].BlockIDHash))
+	require.Equal(t, sig, res.Signatures[0].Signature)
+
+	rec = httptest.NewRecorder()
+	history.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/signatures?limit=x", nil))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,124 @@
+package signer
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/cometbft/cometbft/crypto"
+)
+
+// SignatureStatus is how a signature relates to the public key and the sign state of the
+// validator.
+type SignatureStatus string
+
+const (
+	// SignatureStatusInvalid is a signature that does not verify against the public key.
+	SignatureStatusInvalid SignatureStatus = "invalid"
+
+	// SignatureStatusSigned is a signature at the HRS of the sign state, of the sign bytes of the
+	// sign state, or of sign bytes that only differ from them by the timestamp.
+	SignatureStatusSigned SignatureStatus = "signed"
+
+	// SignatureStatusConflicting is a signature at the HRS of the sign state, of different sign
+	// bytes than those of the sign state: the validator signed twice at the same HRS.
+	SignatureStatusConflicting SignatureStatus = "conflicting"
+
+	// SignatureStatusBehind is a signature below the HRS of the sign state, or at it if the sign
+	// state has no sign bytes. The sign state only keeps the last sign bytes, so it cannot tell
+	// whether the signer made the signature.
+	SignatureStatusBehind SignatureStatus = "behind"
+
+	// SignatureStatusAhead is a signature above the HRS of the sign state, which the signer has
+	// not made. It was made with the key elsewhere.
+	SignatureStatusAhead SignatureStatus = "ahead"
+
+	// SignatureStatusNoSignState is a valid signature of a chain without a sign state.
+	SignatureStatusNoSignState SignatureStatus = "no_sign_state"
+)
+
+// SignatureVerification is the result of verifying a signature against the public key and the
+// sign state of the validator.
+type SignatureVerification struct {
+	Type      string
+	Height    int64
+	Round     int64
+	Step      int8
+	Timestamp time.Time
+
+	// Valid is whether the signature verifies against the public key.
+	Valid bool
+
+	// SignState is the HRS of the sign state, nil if there is none.
+	SignState *HRSKey `json:",omitempty"`
+
+	Status SignatureStatus
+}
+
+// VerifySignature verifies the signature of the sign bytes against the public key, and compares
+// the HRS of the sign bytes with the sign state, which is nil if there is none.
+func VerifySignature(
+	codec SignBytesCodec,
+	pubKey crypto.PubKey,
+	signBytes, signature []byte,
+	signState *SignStateConsensus,
+) (SignatureVerification, error) {
+	hrst, err := codec.UnpackHRST(signBytes)
+	if err != nil {
+		return SignatureVerification{}, err
+	}
+	v := SignatureVerification{
+		Type:      signType(hrst.Step),
+		Height:    hrst.Height,
+		Round:     hrst.Round,
+		Step:      hrst.Step,
+		Timestamp: time.Unix(0, hrst.Timestamp).UTC(),
+		Valid:     pubKey.VerifySignature(signBytes, signature),
+	}
+	if signState != nil {
+		hrs := signState.HRSKey()
+		v.SignState = &hrs
+	}
+
+	hrs := HRSKey{Height: hrst.Height, Round: hrst.Round, Step: hrst.Step}
+	switch {
+	case !v.Valid:
+		v.Status = SignatureStatusInvalid
+	case signState == nil:
+		v.Status = SignatureStatusNoSignState
+	case hrs.GreaterThan(*v.SignState):
+		v.Status = SignatureStatusAhead
+	case hrs.LessThan(*v.SignState):
+		v.Status = SignatureStatusBehind
+	case bytes.Equal(signBytes, signState.SignBytes):
+		v.Status = SignatureStatusSigned
+	case len(signState.SignBytes) == 0:
+		// the sign state was set or imported at this HRS without sign bytes.
+		v.Status = SignatureStatusBehind
+	case codec.OnlyDifferByTimestamp(hrst.Step, signState.SignBytes, signBytes) == nil:
+		v.Status = SignatureStatusSigned
+	default:
+		v.Status = SignatureStatusConflicting
+	}
+	return v, nil
+}
+
+// Description describes the status of the signature.
+func (s SignatureStatus) Description() string {
+	switch s {
+	case SignatureStatusInvalid:
+		return "invalid: the signature does not verify against the public key"
+	case SignatureStatusSigned:
+		return "signed: the signature is of the last sign bytes of the sign state"
+	case SignatureStatusConflicting:
+		return "conflicting: the sign state has different sign bytes at the same height, round and step"
+	case SignatureStatusBehind:
+		return "behind: the signature is at or below the sign state, which does not keep its sign bytes"
+	case SignatureStatusAhead:
+		return "ahead: the signature is above the sign state, the signer did not make it"
+	case SignatureStatusNoSignState:
+		return "no sign state: the chain has no sign state to compare with"
+	default:
+		return fmt.Sprintf("unknown status %q", string(s))
+	}
+}
@@ -0,0 +1,110 @@
+package signer
+
+import (
+	"testing"
+	"time"
+
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	cometproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	comet "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifySignature(t *testing.T) {
+	privKey := cometcryptoed25519.GenPrivKey()
+	codec := CometSignBytesCodec{}
+
+	vote := cometproto.Vote{
+		Type:      cometproto.PrecommitType,
+		Height:    10,
+		Round:     1,
+		Timestamp: time.Unix(1700000000, 0),
+	}
+	signBytes := comet.VoteSignBytes(testChainID, &vote)
+	signature, err := privKey.Sign(signBytes)
+	require.NoError(t, err)
+
+	laterVote := vote
+	laterVote.Timestamp = vote.Timestamp.Add(time.Second)
+	laterSignBytes := comet.VoteSignBytes(testChainID, &laterVote)
+
+	otherVote := vote
+	otherVote.BlockID = cometproto.BlockID{Hash: make([]byte, 32)}
+	otherSignBytes := comet.VoteSignBytes(testChainID, &otherVote)
+
+	tcs := []struct {
+		name      string
+		signature []byte
+		signState *SignStateConsensus
+		status    SignatureStatus
+	}{
+		{
+			name:      "signed",
+			signature: signature,
+			signState: &SignStateConsensus{Height: 10, Round: 1, Step: stepPrecommit, SignBytes: signBytes},
+			status:    SignatureStatusSigned,
+		},
+		{
+			name:      "signed with other timestamp",
+			signature: signature,
+			signState: &SignStateConsensus{Height: 10, Round: 1, Step: stepPrecommit, SignBytes: laterSignBytes},
+			status:    SignatureStatusSigned,
+		},
+		{
+			name:      "conflicting",
+			signature: signature,
+			signState: &SignStateConsensus{Height: 10, Round: 1, Step: stepPrecommit, SignBytes: otherSignBytes},
+			status:    SignatureStatusConflicting,
+		},
+		{
+			name:      "sign state without sign bytes",
+			signature: signature,
+			signState: &SignStateConsensus{Height: 10, Round: 1, Step: stepPrecommit},
+			status:    SignatureStatusBehind,
+		},
+		{
+			name:      "behind",
+			signature: signature,
+			signState: &SignStateConsensus{Height: 11},
+			status:    SignatureStatusBehind,
+		},
+		{
+			name:      "ahead",
+			signature: signature,
+			signState: &SignStateConsensus{Height: 10, Round: 1, Step: stepPrevote},
+			status:    SignatureStatusAhead,
+		},
+		{
+			name:      "no sign state",
+			signature: signature,
+			status:    SignatureStatusNoSignState,
+		},
+		{
+			name:      "invalid",
+			signature: make([]byte, 64),
+			signState: &SignStateConsensus{Height: 10, Round: 1, Step: stepPrecommit, SignBytes: signBytes},
+			status:    SignatureStatusInvalid,
+		},
+	}
+
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := VerifySignature(codec, privKey.PubKey(), signBytes, tc.signature, tc.signState)
+			require.NoError(t, err)
+			require.Equal(t, tc.status, v.Status)
+			require.Equal(t, tc.status != SignatureStatusInvalid, v.Valid)
+			require.Equal(t, "precommit", v.Type)
+			require.Equal(t, HRSKey{Height: 10, Round: 1, Step: stepPrecommit}, HRSKey{v.Height, v.Round, v.Step})
+			require.True(t, vote.Timestamp.Equal(v.Timestamp))
+			if tc.signState == nil {
+				require.Nil(t, v.SignState)
+			} else {
+				require.Equal(t, tc.signState.HRSKey(), *v.SignState)
+			}
+		})
+	}
+
+	_, err = VerifySignature(codec, privKey.PubKey(), []byte("not sign bytes"), signature, nil)
+	require.Error(t, err)
+}
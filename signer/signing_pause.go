@@ -0,0 +1,136 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type SigningPausedError struct {
+	msg string
+}
+
+func (e *SigningPausedError) Error() string { return e.msg }
+
+func newSigningPausedError(since time.Time, cluster bool) *SigningPausedError {
+	scope := "signing"
+	if cluster {
+		scope = "signing of the cluster"
+	}
+	return &SigningPausedError{
+		msg: fmt.Sprintf("%s is paused for maintenance since %s, retry once it is resumed",
+			scope, since.UTC().Format(time.RFC3339)),
+	}
+}
+
+// PauseValidator is a PrivValidator that refuses the sign requests while signing is paused, e.g.
+// during maintenance of the validator, without stopping the signer or disconnecting its chain nodes.
+type PauseValidator struct {
+	val PrivValidator
+
+	mu     sync.RWMutex
+	paused time.Time
+
+	// cluster is whether signing of the whole cluster is paused. The cosigner then also refuses
+	// to sign with its key shard and the sign requests proxied by the other cosigners.
+	cluster bool
+}
+
+// NewPauseValidator returns a PauseValidator for the sign requests of val, which is not paused.
+func NewPauseValidator(val PrivValidator) *PauseValidator {
+	return &PauseValidator{val: val}
+}
+
+// Pause refuses the sign requests until Resume.
+func (v *PauseValidator) Pause() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.pauseLocked()
+}
+
+// PauseCluster refuses the sign requests, the sign requests proxied by the other cosigners and
+// signing with the key shard until Resume, as part of pausing signing of the whole cluster.
+func (v *PauseValidator) PauseCluster() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.pauseLocked()
+	v.cluster = true
+}
+
+func (v *PauseValidator) pauseLocked() {
+	if v.paused.IsZero() {
+		v.paused = time.Now()
+		signingPaused.Set(1)
+	}
+}
+
+// Resume signs the sign requests again.
+func (v *PauseValidator) Resume() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.paused = time.Time{}
+	v.cluster = false
+	signingPaused.Set(0)
+}
+
+// Paused returns the time signing was paused, or false if it is not paused.
+func (v *PauseValidator) Paused() (time.Time, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.paused, !v.paused.IsZero()
+}
+
+// Status returns whether signing is paused, since when, and whether signing of the cluster is.
+func (v *PauseValidator) Status() SigningStatus {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if v.paused.IsZero() {
+		return SigningStatus{}
+	}
+	since := v.paused
+	return SigningStatus{Paused: true, Since: &since, Cluster: v.cluster}
+}
+
+// ClusterPausedError returns a *SigningPausedError if signing of the cluster is paused, or nil.
+func (v *PauseValidator) ClusterPausedError() error {
+	if v == nil {
+		return nil
+	}
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if !v.cluster {
+		return nil
+	}
+	return newSigningPausedError(v.paused, true)
+}
+
+// PausedError returns a *SigningPausedError if signing is paused, or nil.
+func (v *PauseValidator) PausedError() error {
+	if v == nil {
+		return nil
+	}
+	if status := v.Status(); status.Paused {
+		return newSigningPausedError(*status.Since, status.Cluster)
+	}
+	return nil
+}
+
+// Sign implements PrivValidator.
+func (v *PauseValidator) Sign(ctx context.Context, chainID string, block Block) ([]byte, time.Time, error) {
+	if err := v.PausedError(); err != nil {
+		totalSigningPausedRefused.WithLabelValues(chainID).Inc()
+		return nil, block.Timestamp, err
+	}
+	return v.val.Sign(ctx, chainID, block)
+}
+
+// GetPubKey implements PrivValidator.
+func (v *PauseValidator) GetPubKey(ctx context.Context, chainID string) ([]byte, error) {
+	return v.val.GetPubKey(ctx, chainID)
+}
+
+// Stop implements PrivValidator.
+func (v *PauseValidator) Stop() {
+	v.val.Stop()
+}
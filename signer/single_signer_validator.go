@@ -60,6 +60,25 @@ func (pv *SingleSignerValidator) Sign(_ context.Context, chainID string, block B
 	return chainState.filePV.Sign(block)
 }
 
+// LastSignedTimestamp returns the HRS and the timestamp of the sign state of the chain.
+// Implements SignedTimestamps.
+func (pv *SingleSignerValidator) LastSignedTimestamp(chainID string) (HRSTKey, bool, error) {
+	chainState, err := pv.loadChainStateIfNecessary(chainID)
+	if err != nil {
+		return HRSTKey{}, false, err
+	}
+	chainState.pvMutex.Lock()
+	defer chainState.pvMutex.Unlock()
+
+	lss := chainState.filePV.LastSignState
+	return lastSignedTimestamp(chainState.filePV.signBytesCodec, SignStateConsensus{
+		Height:    lss.Height,
+		Round:     int64(lss.Round),
+		Step:      lss.Step,
+		SignBytes: lss.SignBytes,
+	})
+}
+
 func (pv *SingleSignerValidator) loadChainStateIfNecessary(chainID string) (*SingleSignerChainState, error) {
 	cachedChainState, ok := pv.chainState.Load(chainID)
 	if ok {
@@ -83,6 +102,15 @@ func (pv *SingleSignerValidator) loadChainStateIfNecessary(chainID string) (*Sin
 		if err != nil {
 			return nil, err
 		}
+
+		height, err := pv.config.initialSignStateHeight(chainID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize sign state from chain rpc: %w", err)
+		}
+		if height > 0 {
+			filePV.LastSignState.Height = height
+			filePV.LastSignState.Save()
+		}
 	} else {
 		filePV, err = LoadFilePV(keyFile, stateFile, true)
 		if err != nil {
@@ -90,6 +118,12 @@ func (pv *SingleSignerValidator) loadChainStateIfNecessary(chainID string) (*Sin
 		}
 	}
 
+	codec, err := pv.config.SignBytesCodec(chainID)
+	if err != nil {
+		return nil, err
+	}
+	filePV.signBytesCodec = codec
+
 	chainState := &SingleSignerChainState{
 		filePV: filePV,
 	}
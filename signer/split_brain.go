@@ -0,0 +1,169 @@
+package signer
+
+import (
+	"sync"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/strangelove-ventures/horcrux/signer/proto"
+)
+
+// splitBrainFenceDuration is how long another cosigner is considered to claim the leadership after
+// it was last seen claiming it, so that a fence outlasts the interval of the pings between the two.
+const splitBrainFenceDuration = 5 * pingInterval
+
+// leaderTerm is implemented by the leaders that number the terms of the leadership, so that of two
+// cosigners that both claim the leadership, the one elected in the older term is fenced.
+type leaderTerm interface {
+	// LeaderTerm returns the term of the leadership of this cosigner, greater for later elections.
+	LeaderTerm() uint64
+}
+
+// splitBrainRival is another cosigner seen claiming the leadership.
+type splitBrainRival struct {
+	// fenced is true if this cosigner is fenced in favor of the rival.
+	fenced bool
+	until  time.Time
+}
+
+// SplitBrainFence detects another cosigner that claims the leadership at the same time as this
+// cosigner, e.g. when a network partition heals before the stale leader learns of the new term,
+// and fences one of the two from initiating sign rounds instead of relying on the leader election
+// alone. Both cosigners decide alike which one is fenced: the one elected in the older term, or
+// the one with the higher shard ID if the terms are equal or unknown.
+//
+// The claims are exchanged in the pings that the leader sends to every peer, so both leaders see
+// each other within a ping interval.
+type SplitBrainFence struct {
+	logger cometlog.Logger
+	id     int
+	leader Leader
+
+	mu     sync.Mutex
+	rivals map[int]splitBrainRival
+}
+
+// NewSplitBrainFence returns the SplitBrainFence of the cosigner with the shard ID.
+func NewSplitBrainFence(logger cometlog.Logger, id int, leader Leader) *SplitBrainFence {
+	return &SplitBrainFence{
+		logger: logger,
+		id:     id,
+		leader: leader,
+		rivals: make(map[int]splitBrainRival),
+	}
+}
+
+func (f *SplitBrainFence) term() uint64 {
+	if lt, ok := f.leader.(leaderTerm); ok {
+		return lt.LeaderTerm()
+	}
+	return 0
+}
+
+// pingRequest returns the ping of a peer, with the leadership claim of this cosigner.
+func (f *SplitBrainFence) pingRequest() *proto.PingRequest {
+	if f == nil {
+		return &proto.PingRequest{}
+	}
+	return &proto.PingRequest{
+		Id:         int32(f.id),
+		IsLeader:   f.leader.IsLeader(),
+		LeaderTerm: f.term(),
+	}
+}
+
+// pingResponse returns the response to the ping of a peer, with the leadership claim of this cosigner.
+func (f *SplitBrainFence) pingResponse() *proto.PingResponse {
+	if f == nil {
+		return &proto.PingResponse{}
+	}
+	return &proto.PingResponse{
+		IsLeader:   f.leader.IsLeader(),
+		LeaderTerm: f.term(),
+	}
+}
+
+// Observe records whether the peer cosigner claims the leadership in the term, as seen in a ping
+// at now. If this cosigner claims the leadership too, one of the two is fenced.
+func (f *SplitBrainFence) Observe(peerID int, peerIsLeader bool, peerTerm uint64, now time.Time) {
+	if f == nil || peerID == f.id {
+		return
+	}
+	isLeader := f.leader.IsLeader()
+	term := f.term()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	defer f.updateMetrics(now)
+
+	_, known := f.rivals[peerID]
+	if !isLeader || !peerIsLeader {
+		if known {
+			delete(f.rivals, peerID)
+			f.logger.Info("Split brain resolved, cosigner no longer claims the leadership with this cosigner",
+				"cosigner", peerID,
+				"is_leader", isLeader,
+				"cosigner_is_leader", peerIsLeader,
+			)
+		}
+		return
+	}
+
+	fenced := term < peerTerm || (term == peerTerm && f.id > peerID)
+	if !known {
+		totalSplitBrainDetected.Inc()
+		f.logger.Error("Split brain detected, another cosigner claims the leadership",
+			"cosigner", peerID,
+			"term", term,
+			"cosigner_term", peerTerm,
+			"fenced", fenced,
+		)
+	}
+	f.rivals[peerID] = splitBrainRival{fenced: fenced, until: now.Add(splitBrainFenceDuration)}
+}
+
+// Status returns the shard ID of another cosigner that claims the leadership at now, or 0 if there
+// is none, and whether this cosigner is fenced in favor of it. A rival that fences this cosigner is
+// returned first.
+func (f *SplitBrainFence) Status(now time.Time) (rival int, fenced bool) {
+	if f == nil {
+		return 0, false
+	}
+	isLeader := f.leader.IsLeader()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	defer f.updateMetrics(now)
+
+	if !isLeader {
+		// only a leader initiates sign rounds.
+		clear(f.rivals)
+		return 0, false
+	}
+	for id, r := range f.rivals {
+		if now.After(r.until) {
+			continue
+		}
+		if rival == 0 || (r.fenced && !fenced) || (r.fenced == fenced && id < rival) {
+			rival, fenced = id, r.fenced
+		}
+	}
+	return rival, fenced
+}
+
+// updateMetrics drops the expired rivals and updates the split brain metrics. f.mu must be held.
+func (f *SplitBrainFence) updateMetrics(now time.Time) {
+	detected, fenced := 0.0, 0.0
+	for id, r := range f.rivals {
+		if now.After(r.until) {
+			delete(f.rivals, id)
+			continue
+		}
+		detected = 1
+		if r.fenced {
+			fenced = 1
+		}
+	}
+	splitBrainDetected.Set(detected)
+	splitBrainFenced.Set(fenced)
+}
@@ -0,0 +1,99 @@
+package signer
+
+import (
+	"testing"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+)
+
+// termLeader is a leader elected in a term.
+type termLeader struct {
+	isLeader bool
+	term     uint64
+}
+
+func (l *termLeader) IsLeader() bool                              { return l.isLeader }
+func (l *termLeader) ShareSigned(_ ChainSignStateConsensus) error { return nil }
+func (l *termLeader) GetLeader() int                              { return -1 }
+func (l *termLeader) LeaderTerm() uint64                          { return l.term }
+
+func TestSplitBrainFence(t *testing.T) {
+	leader := &termLeader{isLeader: true, term: 5}
+	fence := NewSplitBrainFence(cometlog.NewNopLogger(), 2, leader)
+	now := time.Now()
+
+	// a peer that does not claim the leadership is no rival.
+	fence.Observe(1, false, 5, now)
+	rival, fenced := fence.Status(now)
+	require.Zero(t, rival)
+	require.False(t, fenced)
+
+	// the leader with the older term is fenced.
+	fence.Observe(3, true, 4, now)
+	rival, fenced = fence.Status(now)
+	require.Equal(t, 3, rival)
+	require.False(t, fenced)
+
+	fence.Observe(3, true, 6, now)
+	rival, fenced = fence.Status(now)
+	require.Equal(t, 3, rival)
+	require.True(t, fenced)
+
+	// the claim expires.
+	rival, fenced = fence.Status(now.Add(splitBrainFenceDuration + time.Second))
+	require.Zero(t, rival)
+	require.False(t, fenced)
+
+	// with equal terms, the leader with the higher shard ID is fenced.
+	fence.Observe(1, true, 5, now)
+	rival, fenced = fence.Status(now)
+	require.Equal(t, 1, rival)
+	require.True(t, fenced)
+
+	fence.Observe(3, true, 5, now)
+	rival, fenced = fence.Status(now)
+	require.Equal(t, 1, rival)
+	require.True(t, fenced)
+
+	// the fence is lifted once the rival steps down.
+	fence.Observe(1, false, 6, now)
+	rival, fenced = fence.Status(now)
+	require.Equal(t, 3, rival)
+	require.False(t, fenced)
+
+	// or once this cosigner steps down.
+	leader.isLeader = false
+	rival, fenced = fence.Status(now)
+	require.Zero(t, rival)
+	require.False(t, fenced)
+}
+
+func TestSplitBrainPing(t *testing.T) {
+	leader1 := &termLeader{isLeader: true, term: 3}
+	leader2 := &termLeader{isLeader: true, term: 4}
+	fence1 := NewSplitBrainFence(cometlog.NewNopLogger(), 1, leader1)
+	fence2 := NewSplitBrainFence(cometlog.NewNopLogger(), 2, leader2)
+	now := time.Now()
+
+	// cosigner 1 pings cosigner 2, and both see the other claim the leadership.
+	req := fence1.pingRequest()
+	fence2.Observe(int(req.Id), req.IsLeader, req.LeaderTerm, now)
+	res := fence2.pingResponse()
+	fence1.Observe(2, res.IsLeader, res.LeaderTerm, now)
+
+	rival, fenced := fence1.Status(now)
+	require.Equal(t, 2, rival)
+	require.True(t, fenced)
+	rival, fenced = fence2.Status(now)
+	require.Equal(t, 1, rival)
+	require.False(t, fenced)
+
+	// a nil fence, without a leader, is never fenced.
+	var fence *SplitBrainFence
+	fence.Observe(1, true, 1, now)
+	_, fenced = fence.Status(now)
+	require.False(t, fenced)
+	require.Zero(t, fence.pingRequest().Id)
+}
@@ -0,0 +1,584 @@
+package signer
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	cometjson "github.com/cometbft/cometbft/libs/json"
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	cometservice "github.com/cometbft/cometbft/libs/service"
+	"github.com/cometbft/cometbft/libs/tempfile"
+)
+
+const (
+	defaultStateBackupInterval = time.Hour
+	stateBackupTimeout         = 5 * time.Minute
+
+	// StateBackupSuffix is the file extension of encrypted state backups.
+	StateBackupSuffix = ".hbk"
+
+	stateBackupKeySize = 32
+
+	backupArchiveConfig = "config.yaml"
+	backupArchiveState  = "state"
+	backupArchiveKeys   = "keys"
+
+	// backupArchiveSignStateExport holds the sign state of a sqlite or postgres sign state store.
+	backupArchiveSignStateExport = "sign_state_export.json"
+)
+
+// stateBackupMagic prefixes encrypted state backups to identify the format version.
+var stateBackupMagic = []byte("HCBK1")
+
+var stateBackupNameRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// StateBackupConfig is the on disk config format for scheduled state backups.
+type StateBackupConfig struct {
+	// URL is the s3://bucket/prefix or gs://bucket/prefix to upload backups to.
+	URL string `yaml:"url"`
+
+	// Name distinguishes the backups of each signer under URL. Defaults to the hostname.
+	Name string `yaml:"name,omitempty"`
+
+	// Endpoint overrides the object store endpoint, e.g. for S3 compatible stores.
+	Endpoint string `yaml:"endpoint,omitempty"`
+	Region   string `yaml:"region,omitempty"`
+
+	// Interval between backups. Defaults to 1h.
+	Interval string `yaml:"interval,omitempty"`
+
+	// EncryptionKeyFile contains the base64 encoded 32 byte AES-256 key used to encrypt backups.
+	EncryptionKeyFile string `yaml:"encryptionKeyFile"`
+
+	// IncludeKeyShards adds the key shards and cosigner keys to the encrypted backup.
+	IncludeKeyShards bool `yaml:"includeKeyShards,omitempty"`
+}
+
+func (cfg *StateBackupConfig) Validate() error {
+	if cfg == nil {
+		return nil
+	}
+	if _, err := ParseObjectStoreURL(cfg.URL); err != nil {
+		return fmt.Errorf("invalid backup url: %w", err)
+	}
+	if cfg.Name != "" && !stateBackupNameRegexp.MatchString(cfg.Name) {
+		return fmt.Errorf("invalid backup name: %s", cfg.Name)
+	}
+	if cfg.EncryptionKeyFile == "" {
+		return fmt.Errorf("backup requires encryptionKeyFile")
+	}
+	if _, err := cfg.interval(); err != nil {
+		return fmt.Errorf("invalid backup interval: %w", err)
+	}
+	return nil
+}
+
+func (cfg *StateBackupConfig) interval() (time.Duration, error) {
+	if cfg.Interval == "" {
+		return defaultStateBackupInterval, nil
+	}
+	d, err := time.ParseDuration(cfg.Interval)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("interval must be positive")
+	}
+	return d, nil
+}
+
+func (cfg *StateBackupConfig) name() (string, error) {
+	if cfg.Name != "" {
+		return cfg.Name, nil
+	}
+	return os.Hostname()
+}
+
+// LoadStateBackupKey reads a base64 encoded AES-256 key from the file.
+func LoadStateBackupKey(file string) ([]byte, error) {
+	bz, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup encryption key: %w", err)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(bz)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode backup encryption key: %w", err)
+	}
+	if len(key) != stateBackupKeySize {
+		return nil, fmt.Errorf("backup encryption key must be %d bytes, got %d", stateBackupKeySize, len(key))
+	}
+	return key, nil
+}
+
+func encryptStateBackup(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newStateBackupGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append([]byte{}, stateBackupMagic...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, plaintext, stateBackupMagic), nil
+}
+
+func decryptStateBackup(key, ciphertext []byte) ([]byte, error) {
+	gcm, err := newStateBackupGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(ciphertext, stateBackupMagic) {
+		return nil, fmt.Errorf("not a horcrux state backup")
+	}
+	ciphertext = ciphertext[len(stateBackupMagic):]
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, fmt.Errorf("state backup is truncated")
+	}
+	nonce, ciphertext := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, stateBackupMagic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt state backup, wrong key?: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newStateBackupGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (c RuntimeConfig) keyDirectory() string {
+	if kd := c.cachedKeyDirectory(); kd != "" {
+		return kd
+	}
+	return c.HomeDir
+}
+
+// backupFiles returns the files to back up, keyed by their path in the archive.
+func (c RuntimeConfig) backupFiles(includeKeyShards bool) (map[string]string, error) {
+	files := map[string]string{backupArchiveConfig: c.ConfigFile}
+
+	stateFiles, err := filepath.Glob(filepath.Join(c.StateDir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range stateFiles {
+		files[path.Join(backupArchiveState, filepath.Base(f))] = f
+	}
+
+	if includeKeyShards {
+		keyFiles, err := filepath.Glob(filepath.Join(c.keyDirectory(), "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range keyFiles {
+			files[path.Join(backupArchiveKeys, filepath.Base(f))] = f
+		}
+	}
+
+	return files, nil
+}
+
+// isSignStateFile returns true if the file is a sign state file, which is read with readStateFile
+// and written with writeStateFile.
+func isSignStateFile(filePath string) bool {
+	return strings.HasSuffix(filepath.Base(filePath), "_state.json")
+}
+
+// backupSignStateExport returns the sign state export of all chains of a sqlite or postgres sign
+// state store, or nil for the file sign state store, whose sign state files are backed up as is.
+func (c RuntimeConfig) backupSignStateExport() ([]byte, error) {
+	if c.Config.SignState.storeType() == SignStateStoreFile {
+		return nil, nil
+	}
+	chainIDs, err := c.listSignStateChainIDs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the chain IDs of the sign state store: %w", err)
+	}
+	export, err := c.ExportSignStates(chainIDs)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(export)
+}
+
+// restorePath returns the local path for a file in the archive.
+func (c RuntimeConfig) restorePath(name string) (string, error) {
+	dir, base := path.Split(name)
+	if base == "" || base != filepath.Base(base) || base == "." || base == ".." {
+		return "", fmt.Errorf("invalid file in state backup: %s", name)
+	}
+	switch strings.TrimSuffix(dir, "/") {
+	case "":
+		if base == backupArchiveConfig {
+			return c.ConfigFile, nil
+		}
+	case backupArchiveState:
+		return filepath.Join(c.StateDir, base), nil
+	case backupArchiveKeys:
+		return filepath.Join(c.keyDirectory(), base), nil
+	}
+	return "", fmt.Errorf("invalid file in state backup: %s", name)
+}
+
+// CreateStateBackup returns an encrypted archive of the config and sign state, and optionally
+// the key files. The sign state of a sqlite or postgres sign state store is archived as a sign
+// state export. Nothing is written outside of the encrypted archive.
+func (c RuntimeConfig) CreateStateBackup(key []byte, includeKeyShards bool) ([]byte, error) {
+	files, err := c.backupFiles(includeKeyShards)
+	if err != nil {
+		return nil, err
+	}
+	contents := make(map[string][]byte, len(files)+1)
+	for name, f := range files {
+		var bz []byte
+		if isSignStateFile(f) {
+			// the checksum or encryption of the file is added back when it is restored.
+			bz, err = readStateFile(f)
+		} else {
+			bz, err = os.ReadFile(f)
+		}
+		if err != nil {
+			return nil, err
+		}
+		contents[name] = bz
+	}
+	export, err := c.backupSignStateExport()
+	if err != nil {
+		return nil, err
+	}
+	if export != nil {
+		contents[backupArchiveSignStateExport] = export
+	}
+
+	names := make([]string, 0, len(contents))
+	for name := range contents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for _, name := range names {
+		bz := contents[name]
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0600,
+			Size:    int64(len(bz)),
+			ModTime: time.Now(),
+		}); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(bz); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+
+	return encryptStateBackup(key, buf.Bytes())
+}
+
+// RestoreStateBackup decrypts the backup and writes its files to their locations for this config.
+// Existing files are not overwritten unless force is set. A sign state is never restored
+// over a local sign state with a greater height, round and step, since that would allow double signing,
+// nor over a local sign state that cannot be read. A sign state export of a sqlite or postgres sign
+// state store is merged into the configured sign state store like horcrux state import.
+// The restored file paths and sign states are returned.
+func (c RuntimeConfig) RestoreStateBackup(key, backup []byte, force bool) ([]string, error) {
+	plaintext, err := decryptStateBackup(key, backup)
+	if err != nil {
+		return nil, err
+	}
+	gr, err := gzip.NewReader(bytes.NewReader(plaintext))
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gr)
+
+	type restoreFile struct {
+		path string
+		data []byte
+	}
+	var files []restoreFile
+	var export *SignStateExport
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read state backup: %w", err)
+		}
+		if hdr.Name == backupArchiveSignStateExport {
+			export = new(SignStateExport)
+			if err := json.NewDecoder(io.LimitReader(tr, maxObjectSize)).Decode(export); err != nil {
+				return nil, fmt.Errorf("invalid sign state export in backup: %w", err)
+			}
+			continue
+		}
+		p, err := c.restorePath(hdr.Name)
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxObjectSize))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(p); err == nil && !force {
+			return nil, fmt.Errorf("%s already exists, use force to overwrite", p)
+		}
+		files = append(files, restoreFile{path: p, data: data})
+	}
+
+	var restored []string
+	for _, f := range files {
+		if err := os.MkdirAll(filepath.Dir(f.path), 0700); err != nil {
+			return restored, err
+		}
+		if filepath.Dir(f.path) != filepath.Clean(c.StateDir) || !isSignStateFile(f.path) {
+			if err := tempfile.WriteFileAtomic(f.path, f.data, 0600); err != nil {
+				return restored, err
+			}
+			restored = append(restored, f.path)
+			continue
+		}
+
+		// backups taken before sign state files were archived decoded hold their checksum.
+		data, err := decodeStateFile(f.path, f.data)
+		if err != nil {
+			return restored, fmt.Errorf("invalid sign state %s in backup: %w", filepath.Base(f.path), err)
+		}
+		regression, err := signStateRegression(f.path, data)
+		if err != nil {
+			return restored, err
+		}
+		if regression {
+			continue
+		}
+		if err := writeStateFile(f.path, data); err != nil {
+			return restored, err
+		}
+		restored = append(restored, f.path)
+	}
+
+	if export != nil {
+		results, err := c.ImportSignStates(export)
+		for _, r := range results {
+			if r.Updated {
+				restored = append(restored, fmt.Sprintf("%s sign state of %s", r.Kind, r.ChainID))
+			}
+		}
+		if err != nil {
+			return restored, err
+		}
+	}
+	return restored, nil
+}
+
+// signStateRegression returns true if the existing sign state file at filePath is
+// beyond the sign state in data. An existing sign state file that cannot be read is an
+// error, since the backup could regress it.
+func signStateRegression(filePath string, data []byte) (bool, error) {
+	restored := new(fileSignState)
+	if err := cometjson.Unmarshal(data, restored); err != nil {
+		return false, fmt.Errorf("invalid sign state %s in backup: %w", filepath.Base(filePath), err)
+	}
+
+	existing, err := LoadSignState(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read local sign state %s, move it aside to restore it: %w", filePath, err)
+	}
+	hrs := HRSKey{Height: restored.Height, Round: restored.Round, Step: restored.Step}
+	return existing.HRSKey().GreaterThan(hrs), nil
+}
+
+// latestStateBackupKey returns the most recent backup of the keys.
+// Backups are named by UTC timestamp, so the latest sorts last.
+func latestStateBackupKey(keys []string) (string, error) {
+	var latest string
+	for _, k := range keys {
+		if strings.HasSuffix(k, StateBackupSuffix) && k > latest {
+			latest = k
+		}
+	}
+	if latest == "" {
+		return "", fmt.Errorf("no state backups found")
+	}
+	return latest, nil
+}
+
+// DownloadStateBackup downloads the backup at the s3:// or gs:// URL. If the URL does not
+// point at a backup object, the latest backup under the URL prefix is downloaded.
+func DownloadStateBackup(ctx context.Context, rawURL, endpoint, region string) (string, []byte, error) {
+	u, err := ParseObjectStoreURL(rawURL)
+	if err != nil {
+		return "", nil, err
+	}
+	store, err := newObjectStore(u, endpoint, region)
+	if err != nil {
+		return "", nil, err
+	}
+
+	key := u.Key
+	if !strings.HasSuffix(key, StateBackupSuffix) {
+		prefix := key
+		if prefix != "" {
+			prefix += "/"
+		}
+		keys, err := store.ListObjects(ctx, prefix)
+		if err != nil {
+			return "", nil, err
+		}
+		key, err = latestStateBackupKey(keys)
+		if err != nil {
+			return "", nil, fmt.Errorf("%w under %s", err, rawURL)
+		}
+	}
+
+	backup, err := store.GetObject(ctx, key)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%s://%s/%s", u.Scheme, u.Bucket, key), backup, nil
+}
+
+// StateBackupService periodically uploads encrypted state backups to an object store.
+type StateBackupService struct {
+	cometservice.BaseService
+
+	logger  cometlog.Logger
+	config  RuntimeConfig
+	backup  *StateBackupConfig
+	store   *objectStore
+	prefix  string
+	key     []byte
+	ticker  time.Duration
+	cancel  context.CancelFunc
+	stopped chan struct{}
+}
+
+// NewStateBackupService returns a StateBackupService for the backup config of c.
+func NewStateBackupService(logger cometlog.Logger, c RuntimeConfig) (*StateBackupService, error) {
+	cfg := c.Config.Backup
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	interval, _ := cfg.interval()
+
+	key, err := LoadStateBackupKey(cfg.EncryptionKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	u, _ := ParseObjectStoreURL(cfg.URL)
+	store, err := newObjectStore(u, cfg.Endpoint, cfg.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := cfg.name()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine backup name: %w", err)
+	}
+
+	s := &StateBackupService{
+		logger:  logger,
+		config:  c,
+		backup:  cfg,
+		store:   store,
+		prefix:  path.Join(u.Key, name),
+		key:     key,
+		ticker:  interval,
+		stopped: make(chan struct{}),
+	}
+	s.BaseService = *cometservice.NewBaseService(logger, "StateBackupService", s)
+	return s, nil
+}
+
+func (s *StateBackupService) OnStart() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	go func() {
+		defer close(s.stopped)
+		ticker := time.NewTicker(s.ticker)
+		defer ticker.Stop()
+		for {
+			s.logBackupError(s.Backup(ctx))
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return nil
+}
+
+func (s *StateBackupService) OnStop() {
+	s.cancel()
+	<-s.stopped
+	// take a final backup so that the latest sign state is captured on shutdown.
+	ctx, cancel := context.WithTimeout(context.Background(), stateBackupTimeout)
+	defer cancel()
+	s.logBackupError(s.Backup(ctx))
+}
+
+func (s *StateBackupService) logBackupError(err error) {
+	if err != nil && !errors.Is(err, context.Canceled) {
+		totalStateBackupFailures.Inc()
+		s.logger.Error("Failed to back up state", "error", err)
+	}
+}
+
+// Backup uploads an encrypted backup of the current state.
+func (s *StateBackupService) Backup(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, stateBackupTimeout)
+	defer cancel()
+
+	backup, err := s.config.CreateStateBackup(s.key, s.backup.IncludeKeyShards)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	key := path.Join(s.prefix, now.Format("20060102T150405Z")+StateBackupSuffix)
+	if err := s.store.PutObject(ctx, key, backup); err != nil {
+		return err
+	}
+
+	lastStateBackup.Set(float64(now.Unix()))
+	s.logger.Debug("Backed up state", "key", key, "size", len(backup))
+	return nil
+}
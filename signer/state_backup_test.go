@@ -0,0 +1,237 @@
+package signer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+)
+
+func testStateBackupConfig(t *testing.T) RuntimeConfig {
+	home := t.TempDir()
+	c := RuntimeConfig{
+		HomeDir:    home,
+		ConfigFile: filepath.Join(home, "config.yaml"),
+		StateDir:   filepath.Join(home, "state"),
+	}
+	require.NoError(t, os.MkdirAll(c.StateDir, 0700))
+	require.NoError(t, c.WriteConfigFile())
+	return c
+}
+
+func testStateBackupKey(t *testing.T) []byte {
+	key := make([]byte, stateBackupKeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return key
+}
+
+func TestStateBackupEncryption(t *testing.T) {
+	key := testStateBackupKey(t)
+
+	ciphertext, err := encryptStateBackup(key, []byte("state"))
+	require.NoError(t, err)
+	require.NotContains(t, string(ciphertext), "state")
+
+	plaintext, err := decryptStateBackup(key, ciphertext)
+	require.NoError(t, err)
+	require.Equal(t, []byte("state"), plaintext)
+
+	_, err = decryptStateBackup(testStateBackupKey(t), ciphertext)
+	require.ErrorContains(t, err, "failed to decrypt state backup")
+}
+
+func TestStateBackupRestore(t *testing.T) {
+	key := testStateBackupKey(t)
+
+	src := testStateBackupConfig(t)
+	pv, err := LoadOrCreateSignState(src.PrivValStateFile("chain-1"))
+	require.NoError(t, err)
+	require.NoError(t, pv.Save(SignStateConsensus{Height: 10, Round: 1, Step: stepPrecommit}, nil))
+	require.NoError(t, os.WriteFile(src.KeyFilePathCosigner("chain-1"), []byte(`{"shard":"secret"}`), 0600))
+
+	backup, err := src.CreateStateBackup(key, false)
+	require.NoError(t, err)
+
+	dst := testStateBackupConfig(t)
+	require.NoError(t, os.Remove(dst.ConfigFile))
+
+	restored, err := dst.RestoreStateBackup(key, backup, false)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{dst.ConfigFile, dst.PrivValStateFile("chain-1")}, restored)
+
+	ss, err := LoadSignState(dst.PrivValStateFile("chain-1"))
+	require.NoError(t, err)
+	require.Equal(t, HRSKey{Height: 10, Round: 1, Step: stepPrecommit}, ss.HRSKey())
+	require.NoFileExists(t, dst.KeyFilePathCosigner("chain-1"))
+
+	// existing files are not overwritten without force.
+	_, err = dst.RestoreStateBackup(key, backup, false)
+	require.ErrorContains(t, err, "already exists")
+
+	// key shards are only included when requested.
+	backup, err = src.CreateStateBackup(key, true)
+	require.NoError(t, err)
+
+	// a newer local sign state is never replaced by the backup.
+	pv, err = LoadSignState(dst.PrivValStateFile("chain-1"))
+	require.NoError(t, err)
+	require.NoError(t, pv.Save(SignStateConsensus{Height: 11}, nil))
+
+	restored, err = dst.RestoreStateBackup(key, backup, true)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{dst.ConfigFile, dst.KeyFilePathCosigner("chain-1")}, restored)
+
+	ss, err = LoadSignState(dst.PrivValStateFile("chain-1"))
+	require.NoError(t, err)
+	require.Equal(t, int64(11), ss.Height)
+
+	// nor is a local sign state that cannot be read.
+	require.NoError(t, os.WriteFile(dst.PrivValStateFile("chain-1"), []byte(`{"height":"1`), 0600))
+	_, err = dst.RestoreStateBackup(key, backup, true)
+	require.ErrorContains(t, err, "failed to read local sign state")
+	bz, err := os.ReadFile(dst.PrivValStateFile("chain-1"))
+	require.NoError(t, err)
+	require.Equal(t, `{"height":"1`, string(bz))
+
+	// a restored sign state file is written with its checksum.
+	require.NoError(t, os.Remove(dst.PrivValStateFile("chain-1")))
+	_, err = dst.RestoreStateBackup(key, backup, true)
+	require.NoError(t, err)
+	bz, err = os.ReadFile(dst.PrivValStateFile("chain-1"))
+	require.NoError(t, err)
+	require.Contains(t, string(bz), stateFileChecksumPrefix)
+}
+
+func TestStateBackupRestoreSQLite(t *testing.T) {
+	key := testStateBackupKey(t)
+
+	src := testStateBackupConfig(t)
+	src.Config.SignState = &SignStateStoreConfig{Type: SignStateStoreSQLite}
+	require.NoError(t, src.WriteConfigFile())
+	pv, err := src.LoadOrCreateSignState("chain-1", SignStateKindPrivVal)
+	require.NoError(t, err)
+	require.NoError(t, pv.Save(SignStateConsensus{Height: 10, Round: 1, Step: stepPrecommit}, nil))
+
+	backup, err := src.CreateStateBackup(key, false)
+	require.NoError(t, err)
+
+	// the sign state of the database is merged into the sign state store of the destination.
+	dst := testStateBackupConfig(t)
+	dst.Config.SignState = &SignStateStoreConfig{Type: SignStateStoreSQLite}
+	cs, err := dst.LoadOrCreateSignState("chain-1", SignStateKindCosigner)
+	require.NoError(t, err)
+	require.NoError(t, cs.Save(SignStateConsensus{Height: 12}, nil))
+
+	restored, err := dst.RestoreStateBackup(key, backup, true)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{dst.ConfigFile, "priv_validator sign state of chain-1"}, restored)
+
+	ss, err := dst.LoadSignState("chain-1", SignStateKindPrivVal)
+	require.NoError(t, err)
+	require.Equal(t, HRSKey{Height: 10, Round: 1, Step: stepPrecommit}, ss.HRSKey())
+	ss, err = dst.LoadSignState("chain-1", SignStateKindCosigner)
+	require.NoError(t, err)
+	require.Equal(t, HRSKey{Height: 12}, ss.HRSKey())
+}
+
+// fakeObjectStore is an in-memory S3 compatible object store.
+type fakeObjectStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeObjectStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=test/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+	switch {
+	case r.Method == http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[key] = body
+	case r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+		var result listBucketResult
+		var keys []string
+		for k := range f.objects {
+			if strings.HasPrefix(k, r.URL.Query().Get("prefix")) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			result.Contents = append(result.Contents, struct {
+				Key string `xml:"Key"`
+			}{Key: k})
+		}
+		_ = xml.NewEncoder(w).Encode(result)
+	case r.Method == http.MethodGet:
+		body, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(body)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestStateBackupService(t *testing.T) {
+	fake := &fakeObjectStore{objects: make(map[string][]byte)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	key := testStateBackupKey(t)
+	keyFile := filepath.Join(t.TempDir(), "backup.key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key)), 0600))
+
+	c := testStateBackupConfig(t)
+	c.Config.Backup = &StateBackupConfig{
+		URL:               "s3://bucket/horcrux",
+		Name:              "cosigner-1",
+		Endpoint:          server.URL,
+		EncryptionKeyFile: keyFile,
+	}
+	_, err := LoadOrCreateSignState(c.PrivValStateFile("chain-1"))
+	require.NoError(t, err)
+
+	s, err := NewStateBackupService(cometlog.NewNopLogger(), c)
+	require.NoError(t, err)
+	require.NoError(t, s.Backup(context.Background()))
+
+	require.Len(t, fake.objects, 1)
+	for k := range fake.objects {
+		require.True(t, strings.HasPrefix(k, "horcrux/cosigner-1/"))
+		require.True(t, strings.HasSuffix(k, StateBackupSuffix))
+	}
+
+	source, backup, err := DownloadStateBackup(context.Background(), "s3://bucket/horcrux/cosigner-1", server.URL, "")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(source, "s3://bucket/horcrux/cosigner-1/"))
+
+	dst := testStateBackupConfig(t)
+	restored, err := dst.RestoreStateBackup(key, backup, true)
+	require.NoError(t, err)
+	require.Contains(t, restored, dst.PrivValStateFile("chain-1"))
+}
@@ -0,0 +1,69 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// stateDirLockFile is the file in the state directory that is locked by the process using it.
+const stateDirLockFile = "horcrux.lock"
+
+// StateDirLock is an exclusive lock of a state directory, so that two processes never sign or
+// change the sign state of the same state directory. The lock is an flock of a file in the state
+// directory, which the OS releases when the process exits, even if it crashes, so a lock file
+// left behind does not prevent a restart. The lock file holds the PID of the process holding it.
+type StateDirLock struct {
+	file *os.File
+}
+
+// StateDirLockFile returns the path of the lock file of the state directory.
+func StateDirLockFile(stateDir string) string {
+	return filepath.Join(stateDir, stateDirLockFile)
+}
+
+// LockStateDir locks the state directory, creating it if necessary, or returns an error if
+// another process, such as a running signer, holds its lock.
+func LockStateDir(stateDir string) (*StateDirLock, error) {
+	if err := os.MkdirAll(stateDir, 0700); err != nil {
+		return nil, err
+	}
+	lockFile := StateDirLockFile(stateDir)
+	file, err := os.OpenFile(lockFile, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("error opening lock file %s: %w", lockFile, err)
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		file.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			pid, _ := os.ReadFile(lockFile)
+			return nil, fmt.Errorf("horcrux is already running on PID: %s, state directory %s is locked",
+				strings.TrimSpace(string(pid)), stateDir)
+		}
+		return nil, fmt.Errorf("error locking %s: %w", lockFile, err)
+	}
+
+	if err := file.Truncate(0); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error writing to lock file %s: %w", lockFile, err)
+	}
+	if _, err := file.WriteAt([]byte(fmt.Sprintf("%d\n", os.Getpid())), 0); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error writing to lock file %s: %w", lockFile, err)
+	}
+	return &StateDirLock{file: file}, nil
+}
+
+// Unlock releases the lock of the state directory. The lock file is kept, removing it would
+// allow another process to lock a new file while a third one still holds the lock of this one.
+func (l *StateDirLock) Unlock() error {
+	if err := l.file.Truncate(0); err != nil {
+		l.file.Close()
+		return err
+	}
+	// closing the file releases the lock.
+	return l.file.Close()
+}
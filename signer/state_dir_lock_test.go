@@ -0,0 +1,47 @@
+package signer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockStateDir(t *testing.T) {
+	stateDir := filepath.Join(t.TempDir(), "state")
+
+	lock, err := LockStateDir(stateDir)
+	require.NoError(t, err)
+
+	bz, err := os.ReadFile(StateDirLockFile(stateDir))
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("%d\n", os.Getpid()), string(bz))
+
+	_, err = LockStateDir(stateDir)
+	require.EqualError(t, err, fmt.Sprintf("horcrux is already running on PID: %d, state directory %s is locked",
+		os.Getpid(), stateDir))
+
+	require.NoError(t, lock.Unlock())
+	require.FileExists(t, StateDirLockFile(stateDir))
+
+	lock, err = LockStateDir(stateDir)
+	require.NoError(t, err)
+	require.NoError(t, lock.Unlock())
+}
+
+func TestLockStateDirStaleLockFile(t *testing.T) {
+	stateDir := t.TempDir()
+
+	// a lock file left behind by a crashed process does not hold the lock.
+	require.NoError(t, os.WriteFile(StateDirLockFile(stateDir), []byte("12345\n"), 0600))
+
+	lock, err := LockStateDir(stateDir)
+	require.NoError(t, err)
+	defer lock.Unlock()
+
+	bz, err := os.ReadFile(StateDirLockFile(stateDir))
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("%d\n", os.Getpid()), string(bz))
+}
@@ -0,0 +1,204 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// StateEncryptionPassphraseEnv is the environment variable holding the state encryption
+	// passphrase if no passphrase file is configured.
+	StateEncryptionPassphraseEnv = "HORCRUX_STATE_PASSPHRASE"
+
+	// stateEncryptionMagic starts the contents of encrypted state files.
+	stateEncryptionMagic = "HCST1:"
+
+	stateEncryptionSaltSize = 16
+
+	// scrypt parameters for deriving the state encryption key from the passphrase.
+	// The key is derived once per salt, not on every write.
+	stateEncryptionScryptN = 1 << 15
+	stateEncryptionScryptR = 8
+	stateEncryptionScryptP = 1
+)
+
+// StateEncryptionConfig configures the encryption at rest of the sign state files of the file sign
+// state store. The other stores, e.g. the SQLite database, the raft directory and the logs, are not
+// encrypted.
+type StateEncryptionConfig struct {
+	// PassphraseFile is the file holding the passphrase. If empty, the passphrase is read from
+	// the HORCRUX_STATE_PASSPHRASE environment variable.
+	PassphraseFile string `yaml:"passphraseFile,omitempty"`
+}
+
+// validateSignStateFileEncryption refuses the encryption of a sign state store other than the file
+// store, which it would leave in plaintext.
+func (c *Config) validateSignStateFileEncryption() error {
+	if c.StateEncryption != nil {
+		return fmt.Errorf("stateEncryption is renamed signStateFileEncryption, as it only encrypts the sign state files")
+	}
+	if c.SignStateFileEncryption != nil && c.SignState.storeType() != SignStateStoreFile {
+		return fmt.Errorf("signStateFileEncryption requires the %s sign state store, the %s store is not encrypted",
+			SignStateStoreFile, c.SignState.storeType())
+	}
+	return nil
+}
+
+func (cfg *StateEncryptionConfig) passphrase() ([]byte, error) {
+	if cfg.PassphraseFile == "" {
+		passphrase := os.Getenv(StateEncryptionPassphraseEnv)
+		if passphrase == "" {
+			return nil, fmt.Errorf("state encryption requires passphraseFile or %s", StateEncryptionPassphraseEnv)
+		}
+		return []byte(passphrase), nil
+	}
+	bz, err := os.ReadFile(cfg.PassphraseFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state encryption passphrase: %w", err)
+	}
+	passphrase := bytes.TrimRight(bz, "\r\n")
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("state encryption passphrase file %s is empty", cfg.PassphraseFile)
+	}
+	return passphrase, nil
+}
+
+type StateFileDecryptionError struct {
+	msg string
+}
+
+func (e *StateFileDecryptionError) Error() string { return e.msg }
+
+func newStateFileDecryptionError(filePath string, reason string) *StateFileDecryptionError {
+	return &StateFileDecryptionError{
+		msg: fmt.Sprintf("failed to decrypt state file %s: %s", filePath, reason),
+	}
+}
+
+// stateCipher encrypts state files with AES-256-GCM, with keys derived from a passphrase with scrypt.
+// Each encrypted file holds the salt its key was derived with, so that files written by previous
+// processes or restored from a backup can be decrypted with the same passphrase.
+type stateCipher struct {
+	passphrase []byte
+
+	// salt of the key new files are encrypted with.
+	salt [stateEncryptionSaltSize]byte
+
+	mu    sync.Mutex
+	aeads map[[stateEncryptionSaltSize]byte]cipher.AEAD
+}
+
+// stateEncryption is the cipher of the state files, nil if state encryption is disabled.
+var stateEncryption atomic.Pointer[stateCipher]
+
+// SetStateEncryption enables the encryption of sign state files written from now on with the
+// passphrase of cfg, and the decryption of encrypted sign state files. It disables state
+// encryption if cfg is nil, in which case encrypted sign state files can no longer be read.
+func SetStateEncryption(cfg *StateEncryptionConfig) error {
+	if cfg == nil {
+		stateEncryption.Store(nil)
+		return nil
+	}
+	passphrase, err := cfg.passphrase()
+	if err != nil {
+		return err
+	}
+	c := &stateCipher{
+		passphrase: passphrase,
+		aeads:      make(map[[stateEncryptionSaltSize]byte]cipher.AEAD),
+	}
+	if _, err := rand.Read(c.salt[:]); err != nil {
+		return err
+	}
+	stateEncryption.Store(c)
+	return nil
+}
+
+func (c *stateCipher) aead(salt [stateEncryptionSaltSize]byte) (cipher.AEAD, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if aead, ok := c.aeads[salt]; ok {
+		return aead, nil
+	}
+	key, err := scrypt.Key(
+		c.passphrase, salt[:], stateEncryptionScryptN, stateEncryptionScryptR, stateEncryptionScryptP, 32,
+	)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	c.aeads[salt] = aead
+	return aead, nil
+}
+
+// encrypt returns the contents of an encrypted state file holding data.
+func (c *stateCipher) encrypt(data []byte) ([]byte, error) {
+	aead, err := c.aead(c.salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	sealed := make([]byte, 0, stateEncryptionSaltSize+len(nonce)+len(data)+aead.Overhead())
+	sealed = append(sealed, c.salt[:]...)
+	sealed = append(sealed, nonce...)
+	sealed = aead.Seal(sealed, nonce, data, []byte(stateEncryptionMagic))
+
+	return []byte(stateEncryptionMagic + base64.StdEncoding.EncodeToString(sealed) + "\n"), nil
+}
+
+// decrypt returns the data of the contents of an encrypted state file.
+func (c *stateCipher) decrypt(filePath string, contents []byte) ([]byte, error) {
+	encoded := strings.TrimSpace(string(contents[len(stateEncryptionMagic):]))
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, newStateFileDecryptionError(filePath, "invalid encoding")
+	}
+
+	var salt [stateEncryptionSaltSize]byte
+	if len(sealed) < len(salt) {
+		return nil, newStateFileDecryptionError(filePath, "truncated")
+	}
+	copy(salt[:], sealed)
+	sealed = sealed[len(salt):]
+
+	aead, err := c.aead(salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, newStateFileDecryptionError(filePath, "truncated")
+	}
+	nonce, sealed := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	data, err := aead.Open(nil, nonce, sealed, []byte(stateEncryptionMagic))
+	if err != nil {
+		return nil, newStateFileDecryptionError(filePath, "wrong passphrase or corrupt contents")
+	}
+	return data, nil
+}
+
+// isEncryptedStateFile returns true if contents are those of an encrypted state file.
+func isEncryptedStateFile(contents []byte) bool {
+	return bytes.HasPrefix(contents, []byte(stateEncryptionMagic))
+}
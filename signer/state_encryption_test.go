@@ -0,0 +1,83 @@
+package signer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateEncryption(t *testing.T) {
+	dir := t.TempDir()
+	passphraseFile := filepath.Join(dir, "passphrase")
+	require.NoError(t, os.WriteFile(passphraseFile, []byte("correct horse battery staple\n"), 0600))
+	t.Cleanup(func() { require.NoError(t, SetStateEncryption(nil)) })
+
+	c := RuntimeConfig{StateDir: dir}
+	stateFile := c.PrivValStateFile(testChainID)
+
+	// a plaintext state file written before encryption was enabled.
+	ss, err := LoadOrCreateSignState(stateFile)
+	require.NoError(t, err)
+	require.NoError(t, ss.Save(SignStateConsensus{Height: 5}, nil))
+
+	require.NoError(t, SetStateEncryption(&StateEncryptionConfig{PassphraseFile: passphraseFile}))
+
+	ss, err = LoadSignState(stateFile)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), ss.Height)
+	require.NoError(t, ss.Save(SignStateConsensus{Height: 6, SignBytes: []byte("sign bytes")}, nil))
+
+	contents, err := os.ReadFile(stateFile)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(contents), stateEncryptionMagic))
+	require.NotContains(t, string(contents), "height")
+
+	require.NoError(t, c.ValidateSignStateFiles())
+
+	// a new process with the same passphrase derives a new salt for writes,
+	// but can still read files encrypted with the previous salt.
+	require.NoError(t, SetStateEncryption(&StateEncryptionConfig{PassphraseFile: passphraseFile}))
+	ss, err = LoadSignState(stateFile)
+	require.NoError(t, err)
+	require.Equal(t, int64(6), ss.Height)
+	require.Equal(t, []byte("sign bytes"), []byte(ss.SignBytes))
+
+	// a wrong passphrase or no passphrase cannot read the file.
+	t.Setenv(StateEncryptionPassphraseEnv, "wrong")
+	require.NoError(t, SetStateEncryption(&StateEncryptionConfig{}))
+	_, err = LoadSignState(stateFile)
+	var decryptionErr *StateFileDecryptionError
+	require.ErrorAs(t, err, &decryptionErr)
+
+	require.NoError(t, SetStateEncryption(nil))
+	_, err = LoadSignState(stateFile)
+	require.ErrorAs(t, err, &decryptionErr)
+	require.ErrorAs(t, c.ValidateSignStateFiles(), &decryptionErr)
+}
+
+func TestStateEncryptionConfig(t *testing.T) {
+	t.Setenv(StateEncryptionPassphraseEnv, "")
+	require.Error(t, SetStateEncryption(&StateEncryptionConfig{}))
+
+	empty := filepath.Join(t.TempDir(), "passphrase")
+	require.NoError(t, os.WriteFile(empty, []byte("\n"), 0600))
+	require.Error(t, SetStateEncryption(&StateEncryptionConfig{PassphraseFile: empty}))
+	require.Nil(t, stateEncryption.Load())
+}
+
+func TestSignStateFileEncryptionConfig(t *testing.T) {
+	encryption := &StateEncryptionConfig{PassphraseFile: "passphrase"}
+	require.NoError(t, (&Config{SignStateFileEncryption: encryption}).validateSignStateFileEncryption())
+
+	// the other sign state stores are not encrypted.
+	for _, store := range []string{SignStateStoreSQLite, SignStateStorePostgres} {
+		c := &Config{SignStateFileEncryption: encryption, SignState: &SignStateStoreConfig{Type: store}}
+		require.ErrorContains(t, c.validateSignStateFileEncryption(), "is not encrypted", store)
+	}
+
+	c := &Config{StateEncryption: encryption}
+	require.ErrorContains(t, c.validateSignStateFileEncryption(), "stateEncryption is renamed signStateFileEncryption")
+}
@@ -0,0 +1,187 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// stateFileChecksumPrefix starts the footer line that is appended to state files.
+// The footer holds the sha256 of the preceding contents so that a torn or corrupted
+// write is detected when the file is read, rather than parsed as a stale state.
+const stateFileChecksumPrefix = "\n#sha256:"
+
+const stateFileTempPattern = ".tmp-*"
+
+type StateFileChecksumError struct {
+	msg string
+}
+
+func (e *StateFileChecksumError) Error() string { return e.msg }
+
+func newStateFileChecksumError(filePath, expected, actual string) *StateFileChecksumError {
+	return &StateFileChecksumError{
+		msg: fmt.Sprintf("state file %s is corrupt: checksum %s does not match contents %s", filePath, expected, actual),
+	}
+}
+
+// writeStateFile atomically replaces the file with data followed by a checksum footer.
+// The data is written to a temporary file in the same directory and fsynced before it is
+// renamed over the file, and the directory is fsynced so that the rename is durable.
+// A power loss at any point leaves either the previous or the new file, never a partial one.
+func writeStateFile(filePath string, data []byte) error {
+	dir, base := filepath.Split(filePath)
+	if dir == "" {
+		dir = "."
+	}
+
+	contents, err := stateFileContents(data)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, "."+base+stateFileTempPattern)
+	if err != nil {
+		return err
+	}
+	tmpPath := f.Name()
+	defer func() {
+		// no-op once renamed.
+		_ = os.Remove(tmpPath)
+	}()
+
+	if _, err := f.Write(contents); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(0600); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		return err
+	}
+
+	return syncDir(dir)
+}
+
+// stateFileContents returns the contents of a state file holding data: the encrypted data
+// if state encryption is enabled, otherwise the data followed by a checksum footer.
+// Encrypted files need no checksum since the encryption is authenticated.
+func stateFileContents(data []byte) ([]byte, error) {
+	if c := stateEncryption.Load(); c != nil {
+		return c.encrypt(data)
+	}
+
+	sum := sha256.Sum256(data)
+	contents := make([]byte, 0, len(data)+len(stateFileChecksumPrefix)+hex.EncodedLen(len(sum))+1)
+	contents = append(contents, data...)
+	contents = append(contents, stateFileChecksumPrefix...)
+	contents = append(contents, hex.EncodeToString(sum[:])...)
+	contents = append(contents, '\n')
+	return contents, nil
+}
+
+// syncDir fsyncs the directory so that a rename within it is persisted.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		// directories cannot be fsynced on windows, renames are durable once they return.
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// readStateFile reads the file and verifies and strips its checksum footer, or decrypts it.
+// Files written before checksums were added have no footer and are returned as is.
+func readStateFile(filePath string) ([]byte, error) {
+	contents, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return decodeStateFile(filePath, contents)
+}
+
+// decodeStateFile verifies and strips the checksum footer of the contents of the state file,
+// or decrypts them.
+func decodeStateFile(filePath string, contents []byte) ([]byte, error) {
+	if isEncryptedStateFile(contents) {
+		c := stateEncryption.Load()
+		if c == nil {
+			return nil, newStateFileDecryptionError(filePath, "state encryption is not configured")
+		}
+		return c.decrypt(filePath, contents)
+	}
+
+	i := bytes.LastIndex(contents, []byte(stateFileChecksumPrefix))
+	if i < 0 {
+		return contents, nil
+	}
+
+	data := contents[:i]
+	expected := strings.TrimSpace(string(contents[i+len(stateFileChecksumPrefix):]))
+	sum := sha256.Sum256(data)
+	if actual := hex.EncodeToString(sum[:]); actual != expected {
+		return nil, newStateFileChecksumError(filePath, expected, actual)
+	}
+	return data, nil
+}
+
+// removeStaleStateTempFiles removes temporary files left behind by a write to the
+// file that was interrupted before the rename. It must only be called while the signer
+// is not running, since it would otherwise remove the temporary file of a write in progress.
+func removeStaleStateTempFiles(filePath string) {
+	dir, base := filepath.Split(filePath)
+	if dir == "" {
+		dir = "."
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, "."+base+stateFileTempPattern))
+	if err != nil {
+		return
+	}
+	for _, m := range matches {
+		_ = os.Remove(m)
+	}
+}
+
+// ValidateSignStateFiles checks that every sign state file in the state directory
+// has a valid checksum and can be parsed, so that a signer refuses to start with
+// a sign state that was corrupted, e.g. by a power loss. Temporary files of interrupted
+// writes are removed.
+func (c RuntimeConfig) ValidateSignStateFiles() error {
+	stateFiles, err := filepath.Glob(filepath.Join(c.StateDir, "*_state.json"))
+	if err != nil {
+		return err
+	}
+	for _, f := range stateFiles {
+		removeStaleStateTempFiles(f)
+	}
+	for _, f := range stateFiles {
+		data, err := readStateFile(f)
+		if err != nil {
+			return err
+		}
+		if !json.Valid(data) {
+			return fmt.Errorf("state file %s is corrupt: invalid json", f)
+		}
+	}
+	return nil
+}
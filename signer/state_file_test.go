@@ -0,0 +1,59 @@
+package signer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateFileChecksum(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "chain-1_priv_validator_state.json")
+
+	require.NoError(t, writeStateFile(stateFile, []byte(`{"height":"1"}`)))
+
+	contents, err := os.ReadFile(stateFile)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(contents), `{"height":"1"}`+stateFileChecksumPrefix))
+
+	data, err := readStateFile(stateFile)
+	require.NoError(t, err)
+	require.Equal(t, `{"height":"1"}`, string(data))
+
+	// no temporary files are left behind.
+	matches, err := filepath.Glob(filepath.Join(filepath.Dir(stateFile), ".*"))
+	require.NoError(t, err)
+	require.Empty(t, matches)
+
+	// a torn write is detected.
+	require.NoError(t, os.WriteFile(stateFile, append([]byte(`{"height":"2"}`), contents[len(`{"height":"1"}`):]...), 0600))
+	_, err = readStateFile(stateFile)
+	var checksumErr *StateFileChecksumError
+	require.ErrorAs(t, err, &checksumErr)
+
+	// files without a checksum footer are still readable.
+	require.NoError(t, os.WriteFile(stateFile, []byte(`{"height":"3"}`), 0600))
+	data, err = readStateFile(stateFile)
+	require.NoError(t, err)
+	require.Equal(t, `{"height":"3"}`, string(data))
+}
+
+func TestValidateSignStateFiles(t *testing.T) {
+	c := RuntimeConfig{StateDir: t.TempDir()}
+
+	ss, err := LoadOrCreateSignState(c.PrivValStateFile("chain-1"))
+	require.NoError(t, err)
+	require.NoError(t, ss.Save(SignStateConsensus{Height: 5}, nil))
+
+	stale := filepath.Join(c.StateDir, ".chain-1_priv_validator_state.json.tmp-123")
+	require.NoError(t, os.WriteFile(stale, []byte(`{"hei`), 0600))
+
+	require.NoError(t, c.ValidateSignStateFiles())
+	require.NoFileExists(t, stale)
+
+	// truncated by a power loss.
+	require.NoError(t, os.WriteFile(c.CosignerStateFile("chain-1"), []byte(`{"height":`), 0600))
+	require.ErrorContains(t, c.ValidateSignStateFiles(), "is corrupt")
+}
@@ -0,0 +1,336 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	cometservice "github.com/cometbft/cometbft/libs/service"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+const (
+	defaultStatsdInterval = 10 * time.Second
+
+	// statsdMaxPacketSize keeps packets below the MTU of most networks.
+	statsdMaxPacketSize = 1432
+)
+
+// StatsD line formats.
+const (
+	StatsdFormatStatsd    = "statsd"
+	StatsdFormatDogStatsd = "dogstatsd"
+)
+
+// StatsdConfig configures pushing the metrics to a StatsD or DogStatsD agent.
+type StatsdConfig struct {
+	// Address is the host:port of the agent, metrics are sent over UDP.
+	Address string `yaml:"address"`
+
+	// Format of the metrics, statsd (default) or dogstatsd. Labels are appended to the metric name
+	// in the statsd format and sent as tags in the dogstatsd format.
+	Format string `yaml:"format,omitempty"`
+
+	// Prefix of the metric names, e.g. horcrux.
+	Prefix string `yaml:"prefix,omitempty"`
+
+	// Interval between pushes. Defaults to 10s.
+	Interval string `yaml:"interval,omitempty"`
+
+	// Tags added to every metric, only supported by the dogstatsd format.
+	Tags map[string]string `yaml:"tags,omitempty"`
+}
+
+func (cfg *StatsdConfig) Validate() error {
+	if cfg == nil {
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(cfg.Address); err != nil {
+		return fmt.Errorf("invalid statsd address: %w", err)
+	}
+	switch cfg.format() {
+	case StatsdFormatStatsd:
+		if len(cfg.Tags) > 0 {
+			return fmt.Errorf("statsd tags require the %s format", StatsdFormatDogStatsd)
+		}
+	case StatsdFormatDogStatsd:
+	default:
+		return fmt.Errorf("invalid statsd format %q, must be %s or %s", cfg.Format, StatsdFormatStatsd, StatsdFormatDogStatsd)
+	}
+	if _, err := cfg.interval(); err != nil {
+		return fmt.Errorf("invalid statsd interval: %w", err)
+	}
+	return nil
+}
+
+func (cfg *StatsdConfig) format() string {
+	if cfg.Format == "" {
+		return StatsdFormatStatsd
+	}
+	return cfg.Format
+}
+
+func (cfg *StatsdConfig) interval() (time.Duration, error) {
+	if cfg.Interval == "" {
+		return defaultStatsdInterval, nil
+	}
+	d, err := time.ParseDuration(cfg.Interval)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("interval must be positive")
+	}
+	return d, nil
+}
+
+// StatsdExporter periodically pushes the metrics of a prometheus.Gatherer to a StatsD agent.
+// Gauges are sent as gauges, and counters as the increase since the previous push.
+// Histograms and summaries are sent as the increase of their count and sum, and the
+// quantiles of summaries as gauges.
+type StatsdExporter struct {
+	cometservice.BaseService
+
+	logger   cometlog.Logger
+	cfg      *StatsdConfig
+	gatherer prometheus.Gatherer
+	interval time.Duration
+	tags     []string
+
+	conn net.Conn
+
+	// counters holds the last value pushed of each counter series.
+	counters map[string]float64
+
+	cancel  context.CancelFunc
+	stopped sync.WaitGroup
+}
+
+// NewStatsdExporter returns a StatsdExporter that pushes the metrics of gatherer as configured by cfg.
+func NewStatsdExporter(
+	logger cometlog.Logger,
+	cfg *StatsdConfig,
+	gatherer prometheus.Gatherer,
+) (*StatsdExporter, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	interval, err := cfg.interval()
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]string, 0, len(cfg.Tags))
+	for k, v := range cfg.Tags {
+		tags = append(tags, statsdTag(k, v))
+	}
+	sort.Strings(tags)
+
+	e := &StatsdExporter{
+		logger:   logger,
+		cfg:      cfg,
+		gatherer: gatherer,
+		interval: interval,
+		tags:     tags,
+		counters: make(map[string]float64),
+	}
+	e.BaseService = *cometservice.NewBaseService(logger, "StatsdExporter", e)
+	return e, nil
+}
+
+func (e *StatsdExporter) OnStart() error {
+	conn, err := net.Dial("udp", e.cfg.Address)
+	if err != nil {
+		return err
+	}
+	e.conn = conn
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+
+	e.stopped.Add(1)
+	go func() {
+		defer e.stopped.Done()
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := e.push(); err != nil {
+					e.logger.Error("Failed to push metrics to statsd", "address", e.cfg.Address, "error", err)
+				}
+			}
+		}
+	}()
+
+	e.logger.Info("Pushing metrics to statsd", "address", e.cfg.Address, "interval", e.interval)
+	return nil
+}
+
+func (e *StatsdExporter) OnStop() {
+	e.cancel()
+	e.stopped.Wait()
+	if err := e.conn.Close(); err != nil {
+		e.logger.Error("Failed to close statsd connection", "error", err)
+	}
+}
+
+// push gathers the metrics and sends them in as few packets as possible.
+func (e *StatsdExporter) push() error {
+	families, err := e.gatherer.Gather()
+	if err != nil {
+		return err
+	}
+
+	var packet []byte
+	for _, line := range e.lines(families) {
+		if len(packet) > 0 && len(packet)+1+len(line) > statsdMaxPacketSize {
+			if _, err := e.conn.Write(packet); err != nil {
+				return err
+			}
+			packet = packet[:0]
+		}
+		if len(packet) > 0 {
+			packet = append(packet, '\n')
+		}
+		packet = append(packet, line...)
+	}
+	if len(packet) > 0 {
+		if _, err := e.conn.Write(packet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lines returns the StatsD lines of the metric families.
+func (e *StatsdExporter) lines(families []*dto.MetricFamily) []string {
+	var lines []string
+	for _, mf := range families {
+		name := mf.GetName()
+		for _, m := range mf.GetMetric() {
+			labels := m.GetLabel()
+			switch mf.GetType() {
+			case dto.MetricType_COUNTER:
+				lines = e.appendCounter(lines, name, labels, m.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				lines = e.appendLine(lines, name, labels, nil, m.GetGauge().GetValue(), "g")
+			case dto.MetricType_UNTYPED:
+				lines = e.appendLine(lines, name, labels, nil, m.GetUntyped().GetValue(), "g")
+			case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+				h := m.GetHistogram()
+				lines = e.appendCounter(lines, name+"_count", labels, float64(h.GetSampleCount()))
+				lines = e.appendCounter(lines, name+"_sum", labels, h.GetSampleSum())
+			case dto.MetricType_SUMMARY:
+				s := m.GetSummary()
+				for _, q := range s.GetQuantile() {
+					quantileName, quantileValue := "quantile", strconv.FormatFloat(q.GetQuantile(), 'f', -1, 64)
+					quantile := &dto.LabelPair{Name: &quantileName, Value: &quantileValue}
+					lines = e.appendLine(lines, name, labels, quantile, q.GetValue(), "g")
+				}
+				lines = e.appendCounter(lines, name+"_count", labels, float64(s.GetSampleCount()))
+				lines = e.appendCounter(lines, name+"_sum", labels, s.GetSampleSum())
+			}
+		}
+	}
+	return lines
+}
+
+// appendCounter appends the increase of the counter since the previous push, if any.
+func (e *StatsdExporter) appendCounter(lines []string, name string, labels []*dto.LabelPair, value float64) []string {
+	key := statsdSeriesKey(name, labels)
+	delta := value - e.counters[key]
+	if delta < 0 {
+		// the counter was reset.
+		delta = value
+	}
+	e.counters[key] = value
+	if delta == 0 {
+		return lines
+	}
+	return e.appendLine(lines, name, labels, nil, delta, "c")
+}
+
+// appendLine appends the line of a value, extra is an additional label, e.g. a quantile.
+func (e *StatsdExporter) appendLine(
+	lines []string,
+	name string,
+	labels []*dto.LabelPair,
+	extra *dto.LabelPair,
+	value float64,
+	metricType string,
+) []string {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return lines
+	}
+	if extra != nil {
+		labels = append(labels[:len(labels):len(labels)], extra)
+	}
+
+	var b strings.Builder
+	b.WriteString(e.cfg.Prefix)
+	b.WriteString(name)
+
+	if e.cfg.format() == StatsdFormatStatsd {
+		for _, l := range labels {
+			b.WriteByte('.')
+			b.WriteString(statsdSanitize(l.GetValue()))
+		}
+	}
+
+	b.WriteByte(':')
+	b.WriteString(strconv.FormatFloat(value, 'f', -1, 64))
+	b.WriteByte('|')
+	b.WriteString(metricType)
+
+	if e.cfg.format() == StatsdFormatDogStatsd && len(e.tags)+len(labels) > 0 {
+		tags := make([]string, 0, len(e.tags)+len(labels))
+		tags = append(tags, e.tags...)
+		for _, l := range labels {
+			tags = append(tags, statsdTag(l.GetName(), l.GetValue()))
+		}
+		b.WriteString("|#")
+		b.WriteString(strings.Join(tags, ","))
+	}
+
+	return append(lines, b.String())
+}
+
+func statsdSeriesKey(name string, labels []*dto.LabelPair) string {
+	var b strings.Builder
+	b.WriteString(name)
+	for _, l := range labels {
+		b.WriteByte(0)
+		b.WriteString(l.GetName())
+		b.WriteByte(0)
+		b.WriteString(l.GetValue())
+	}
+	return b.String()
+}
+
+// statsdSanitize replaces the characters of a label value which cannot be part of a metric name.
+func statsdSanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
+
+// statsdTag returns a DogStatsD tag, replacing the characters which separate tags and fields.
+func statsdTag(name, value string) string {
+	return strings.NewReplacer(",", "_", "|", "_", "#", "_").Replace(name + ":" + value)
+}
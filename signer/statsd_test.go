@@ -0,0 +1,104 @@
+package signer
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsdConfigValidate(t *testing.T) {
+	require.NoError(t, (*StatsdConfig)(nil).Validate())
+	require.NoError(t, (&StatsdConfig{Address: "127.0.0.1:8125"}).Validate())
+	require.NoError(t, (&StatsdConfig{
+		Address: "127.0.0.1:8125",
+		Format:  StatsdFormatDogStatsd,
+		Tags:    map[string]string{"env": "mainnet"},
+	}).Validate())
+
+	require.Error(t, (&StatsdConfig{}).Validate())
+	require.Error(t, (&StatsdConfig{Address: "127.0.0.1:8125", Format: "graphite"}).Validate())
+	require.Error(t, (&StatsdConfig{Address: "127.0.0.1:8125", Interval: "0s"}).Validate())
+	require.Error(t, (&StatsdConfig{Address: "127.0.0.1:8125", Tags: map[string]string{"env": "mainnet"}}).Validate())
+}
+
+func TestStatsdExporter(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_total"}, []string{"chain_id"})
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_gauge"})
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "test_seconds"})
+	registry.MustRegister(counter, gauge, histogram)
+
+	counter.WithLabelValues("cosmoshub-4").Add(3)
+	gauge.Set(1.5)
+	histogram.Observe(0.25)
+
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	read := func() []string {
+		require.NoError(t, listener.SetReadDeadline(time.Now().Add(5*time.Second)))
+		buf := make([]byte, statsdMaxPacketSize)
+		n, _, err := listener.ReadFrom(buf)
+		require.NoError(t, err)
+		return strings.Split(string(buf[:n]), "\n")
+	}
+
+	exporter, err := NewStatsdExporter(cometlog.NewNopLogger(), &StatsdConfig{
+		Address: listener.LocalAddr().String(),
+		Format:  StatsdFormatDogStatsd,
+		Prefix:  "horcrux.",
+		Tags:    map[string]string{"env": "mainnet"},
+	}, registry)
+	require.NoError(t, err)
+	require.NoError(t, exporter.Start())
+	defer func() {
+		require.NoError(t, exporter.Stop())
+	}()
+
+	require.NoError(t, exporter.push())
+	require.ElementsMatch(t, []string{
+		"horcrux.test_total:3|c|#env:mainnet,chain_id:cosmoshub-4",
+		"horcrux.test_gauge:1.5|g|#env:mainnet",
+		"horcrux.test_seconds_count:1|c|#env:mainnet",
+		"horcrux.test_seconds_sum:0.25|c|#env:mainnet",
+	}, read())
+
+	// counters are sent as the increase since the previous push.
+	counter.WithLabelValues("cosmoshub-4").Add(2)
+	require.NoError(t, exporter.push())
+	require.ElementsMatch(t, []string{
+		"horcrux.test_total:2|c|#env:mainnet,chain_id:cosmoshub-4",
+		"horcrux.test_gauge:1.5|g|#env:mainnet",
+	}, read())
+}
+
+func TestStatsdExporterStatsdFormat(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_gauge"}, []string{"chain_id", "peerid"})
+	summary := prometheus.NewSummary(prometheus.SummaryOpts{
+		Name:       "test_lag_seconds",
+		Objectives: map[float64]float64{0.5: 0.05},
+	})
+	registry.MustRegister(gauge, summary)
+
+	gauge.WithLabelValues("cosmoshub-4", "tcp://10.0.0.1:2222").Set(2)
+	summary.Observe(0.5)
+
+	exporter, err := NewStatsdExporter(cometlog.NewNopLogger(), &StatsdConfig{Address: "127.0.0.1:8125"}, registry)
+	require.NoError(t, err)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		"test_gauge.cosmoshub-4.tcp___10_0_0_1_2222:2|g",
+		"test_lag_seconds.0_5:0.5|g",
+		"test_lag_seconds_count:1|c",
+		"test_lag_seconds_sum:0.5|c",
+	}, exporter.lines(families))
+}
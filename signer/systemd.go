@@ -0,0 +1,175 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	cometservice "github.com/cometbft/cometbft/libs/service"
+)
+
+// systemdReadyPollInterval is how often the readiness of the signer is checked until it is ready.
+const systemdReadyPollInterval = time.Second
+
+// SystemdNotifier implements the sd_notify protocol of systemd for a signer run as a service of
+// Type=notify: it notifies systemd once the signer is ready to sign, and keeps pinging the systemd
+// watchdog while the main loops of the signer run, so that systemd restarts a wedged signer.
+type SystemdNotifier struct {
+	cometservice.BaseService
+
+	logger cometlog.Logger
+	health *Health
+
+	socket   string
+	watchdog time.Duration
+	restart  *SignerRestart
+
+	cancel  context.CancelFunc
+	stopped sync.WaitGroup
+}
+
+// NewSystemdNotifier returns a SystemdNotifier for the notify socket that systemd passes in
+// NOTIFY_SOCKET, or nil if the signer is not run by systemd with Type=notify.
+func NewSystemdNotifier(logger cometlog.Logger, health *Health) (*SystemdNotifier, error) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil, nil
+	}
+	watchdog, err := systemdWatchdogInterval()
+	if err != nil {
+		return nil, err
+	}
+	n := &SystemdNotifier{
+		logger:   logger,
+		health:   health,
+		socket:   socket,
+		watchdog: watchdog,
+	}
+	n.BaseService = *cometservice.NewBaseService(logger, "SystemdNotifier", n)
+	return n, nil
+}
+
+// systemdWatchdogInterval returns the watchdog timeout that systemd passes in WATCHDOG_USEC, or 0
+// if the watchdog is disabled or meant for another process.
+func systemdWatchdogInterval() (time.Duration, error) {
+	usec := os.Getenv("WATCHDOG_USEC")
+	if usec == "" {
+		return 0, nil
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(usec, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid WATCHDOG_USEC %q", usec)
+	}
+	return time.Duration(n) * time.Microsecond, nil
+}
+
+// SetRestart sets the restart of the signer, which is notified to systemd as a reload rather than
+// as stopping, since the restarted signer keeps the PID of the service.
+func (n *SystemdNotifier) SetRestart(restart *SignerRestart) {
+	n.restart = restart
+}
+
+func (n *SystemdNotifier) OnStart() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	n.cancel = cancel
+
+	n.stopped.Add(1)
+	go func() {
+		defer n.stopped.Done()
+		n.notifyReady(ctx)
+	}()
+
+	if n.watchdog > 0 {
+		n.stopped.Add(1)
+		go func() {
+			defer n.stopped.Done()
+			n.pingWatchdog(ctx)
+		}()
+	}
+
+	n.logger.Info("Notifying systemd", "socket", n.socket, "watchdog", n.watchdog)
+	return nil
+}
+
+func (n *SystemdNotifier) OnStop() {
+	n.cancel()
+	n.stopped.Wait()
+	if n.restart.IsRequested() {
+		if err := n.notify("RELOADING=1", "STATUS=Restarting"); err != nil {
+			n.logger.Error("Failed to notify systemd of restarting", "error", err)
+		}
+		return
+	}
+	if err := n.notify("STOPPING=1"); err != nil {
+		n.logger.Error("Failed to notify systemd of stopping", "error", err)
+	}
+}
+
+// notifyReady notifies systemd once the signer is ready, and of the reasons it is not ready until then.
+func (n *SystemdNotifier) notifyReady(ctx context.Context) {
+	ticker := time.NewTicker(systemdReadyPollInterval)
+	defer ticker.Stop()
+
+	var lastStatus string
+	for {
+		ready, reasons := n.health.Ready()
+		if ready {
+			if err := n.notify("READY=1", "STATUS=Ready to sign"); err != nil {
+				n.logger.Error("Failed to notify systemd of readiness", "error", err)
+			}
+			return
+		}
+		if status := "STATUS=Not ready: " + strings.Join(reasons, ", "); status != lastStatus {
+			if err := n.notify(status); err != nil {
+				n.logger.Error("Failed to notify systemd of status", "error", err)
+			}
+			lastStatus = status
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pingWatchdog pings the watchdog at half its timeout while the main loops of the signer run.
+func (n *SystemdNotifier) pingWatchdog(ctx context.Context) {
+	ticker := time.NewTicker(n.watchdog / 2)
+	defer ticker.Stop()
+
+	for {
+		if stalled := n.health.Stalled(n.watchdog); len(stalled) > 0 {
+			n.logger.Error("Not pinging the systemd watchdog, main loops are stalled", "stalled", stalled)
+		} else if err := n.notify("WATCHDOG=1"); err != nil {
+			n.logger.Error("Failed to ping the systemd watchdog", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// notify sends the state assignments to the notify socket.
+func (n *SystemdNotifier) notify(state ...string) error {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: n.socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte(strings.Join(state, "\n")))
+	return err
+}
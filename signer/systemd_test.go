@@ -0,0 +1,84 @@
+package signer
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemdNotifier(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	n, err := NewSystemdNotifier(cometlog.NewNopLogger(), NewHealth(SignModeSingle, nil))
+	require.NoError(t, err)
+	require.Nil(t, n, "not run by systemd")
+
+	// unix socket paths are limited to about 100 bytes, so keep it short.
+	dir, err := os.MkdirTemp("", "sd")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "notify")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	t.Setenv("NOTIFY_SOCKET", socket)
+	t.Setenv("WATCHDOG_USEC", "soon")
+	_, err = NewSystemdNotifier(cometlog.NewNopLogger(), NewHealth(SignModeSingle, nil))
+	require.ErrorContains(t, err, `invalid WATCHDOG_USEC "soon"`)
+
+	// the watchdog of another process is ignored.
+	t.Setenv("WATCHDOG_USEC", "100000")
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()+1))
+	n, err = NewSystemdNotifier(cometlog.NewNopLogger(), NewHealth(SignModeSingle, nil))
+	require.NoError(t, err)
+	require.Zero(t, n.watchdog)
+
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()))
+	n, err = NewSystemdNotifier(cometlog.NewNopLogger(), NewHealth(SignModeSingle, nil))
+	require.NoError(t, err)
+	require.Equal(t, 100*time.Millisecond, n.watchdog)
+
+	require.NoError(t, n.Start())
+
+	received := func() string {
+		buf := make([]byte, 1024)
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		l, err := conn.Read(buf)
+		require.NoError(t, err)
+		return string(buf[:l])
+	}
+
+	// a single signer is ready once started, and pings the watchdog at half its timeout.
+	messages := map[string]int{}
+	for i := 0; i < 4; i++ {
+		messages[received()]++
+	}
+	require.Equal(t, 1, messages["READY=1\nSTATUS=Ready to sign"])
+	require.Equal(t, 3, messages["WATCHDOG=1"])
+
+	require.NoError(t, n.Stop())
+	for {
+		if m := received(); m != "WATCHDOG=1" {
+			require.Equal(t, "STOPPING=1", m)
+			break
+		}
+	}
+
+	// a restart is notified as a reload, the restarted signer keeps the PID.
+	t.Setenv("WATCHDOG_USEC", "")
+	n, err = NewSystemdNotifier(cometlog.NewNopLogger(), NewHealth(SignModeSingle, nil))
+	require.NoError(t, err)
+	restart := NewSignerRestart()
+	n.SetRestart(restart)
+	require.NoError(t, n.Start())
+	require.Equal(t, "READY=1\nSTATUS=Ready to sign", received())
+	restart.Request()
+	require.NoError(t, n.Stop())
+	require.Equal(t, "RELOADING=1\nSTATUS=Restarting", received())
+}
@@ -5,9 +5,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cometbft/cometbft/libs/log"
@@ -26,15 +28,18 @@ type ThresholdValidator struct {
 
 	threshold int
 
-	grpcTimeout time.Duration
+	// grpcTimeout is the timeout of the requests to the peer cosigners in nanoseconds, which may be
+	// changed at runtime with SetGRPCTimeout.
+	grpcTimeout atomic.Int64
 
 	chainState sync.Map
 
 	// our own cosigner
 	myCosigner *LocalCosigner
 
-	// peer cosigners
+	// peer cosigners, which may be changed at runtime with SetPeers
 	peerCosigners Cosigners
+	peersMu       sync.RWMutex
 
 	leader Leader
 
@@ -47,6 +52,33 @@ type ThresholdValidator struct {
 	cosignerHealth *CosignerHealth
 
 	nonceCache *CosignerNonceCache
+
+	featureFlags *FeatureFlags
+
+	// watermark is the optional high watermark store shared with other clusters.
+	watermark WatermarkStore
+
+	// signStateRecovery recovers missing or corrupt sign states from peers.
+	signStateRecovery *SignStateRecovery
+
+	// splitBrain fences this cosigner while another cosigner claims the leadership too, nil without a leader.
+	splitBrain *SplitBrainFence
+
+	// signRounds is held for reading by the sign requests in flight, and for writing while the
+	// leadership is transferred with TransferLeadership.
+	signRounds sync.RWMutex
+
+	// pause is the pause of the sign requests of the chain nodes, set once the signer is started.
+	pause atomic.Pointer[PauseValidator]
+
+	// kill is the kill switch of the sign requests of the chain nodes, set once the signer is started.
+	kill atomic.Pointer[KillSwitchValidator]
+
+	// approvals are the approvals the admin operations require, set before the kill switch.
+	approvals atomic.Pointer[AdminApprovals]
+
+	// signLock is the sign lock of the chains, set once the signer is started with one.
+	signLock atomic.Pointer[EtcdSignLock]
 }
 
 type ChainSignState struct {
@@ -76,31 +108,145 @@ func NewThresholdValidator(
 	copy(allCosigners[1:], peerCosigners)
 
 	for _, cosigner := range peerCosigners {
-		logger.Debug("Peer cosigner", "id", cosigner.GetID())
+		logger.Debug("Peer cosigner", "cosigner", cosigner.GetID())
 	}
 
+	var nonceCacheCfg *NonceCacheConfig
+	if tc := config.Config.ThresholdModeConfig; tc != nil {
+		nonceCacheCfg = tc.NonceCache
+	}
+	// validated with the config.
+	ncp, _ := nonceCacheCfg.params()
 	nc := NewCosignerNonceCache(
-		logger,
+		logger.With("module", LogModuleNonceCache),
 		allCosigners,
 		leader,
-		defaultGetNoncesInterval,
-		defaultGetNoncesTimeout,
-		defaultNonceExpiration,
+		ncp.reconcileInterval,
+		ncp.fetchTimeout,
+		ncp.expiration,
 		uint8(threshold),
 		nil,
 	)
-	return &ThresholdValidator{
+	nc.controller.multiplier = ncp.targetMultiplier
+	nc.warmup = ncp.warmup
+	nc.quorumGrace = ncp.quorumGrace
+	nc.onDemand = ncp.onDemand
+	nc.burst = ncp.burst
+	nc.expirationJitter = ncp.expirationJitter
+	if ncp.lruPruning {
+		nc.pruner = lruNonceCachePruner{cnc: nc}
+	}
+	if ncp.pushInterval > 0 {
+		nc.SetPush(ncp.pushInterval)
+	}
+	cosignerHealth := NewCosignerHealth(
+		logger.With("module", LogModuleCosignerHealth), myCosigner.GetID(), peerCosigners, leader,
+	)
+	nc.health = cosignerHealth
+	var splitBrain *SplitBrainFence
+	if _, ok := leader.(Coordinator); !ok {
+		splitBrain = NewSplitBrainFence(logger, myCosigner.GetID(), leader)
+		cosignerHealth.fence = splitBrain
+	}
+	if tc := config.Config.ThresholdModeConfig; tc != nil && tc.Quarantine != nil {
+		// validated with the config.
+		quarantine, _ := tc.Quarantine.params(grpcTimeout)
+		cosignerHealth.SetQuarantine(quarantine, threshold)
+	}
+	cosignerHealth.SetNonceBackoff(ncp.backoff, threshold)
+	if tc := config.Config.ThresholdModeConfig; tc != nil {
+		cosignerHealth.SetVersionSkewPolicy(tc.VersionSkewPolicy)
+	}
+	signStateRecovery := NewSignStateRecovery(logger, peerCosigners, threshold, grpcTimeout)
+	myCosigner.SetSignStateRecovery(signStateRecovery)
+
+	pv := &ThresholdValidator{
 		logger:                      logger,
 		config:                      config,
 		threshold:                   threshold,
-		grpcTimeout:                 grpcTimeout,
 		maxWaitForSameBlockAttempts: maxWaitForSameBlockAttempts,
 		myCosigner:                  myCosigner,
 		peerCosigners:               peerCosigners,
 		leader:                      leader,
-		cosignerHealth:              NewCosignerHealth(logger, peerCosigners, leader),
+		cosignerHealth:              cosignerHealth,
 		nonceCache:                  nc,
+		featureFlags:                NewFeatureFlags(config.Config.FeatureFlags),
+		signStateRecovery:           signStateRecovery,
+		splitBrain:                  splitBrain,
+	}
+	pv.grpcTimeout.Store(int64(grpcTimeout))
+	return pv
+}
+
+// GRPCTimeout returns the timeout of the requests to the peer cosigners.
+func (pv *ThresholdValidator) GRPCTimeout() time.Duration {
+	return time.Duration(pv.grpcTimeout.Load())
+}
+
+// chainGRPCTimeout returns the timeout of the requests to the peer cosigners while signing the chain.
+func (pv *ThresholdValidator) chainGRPCTimeout(chainID string) time.Duration {
+	if timeout := pv.config.Config.Chains.grpcTimeout(chainID); timeout > 0 {
+		return timeout
 	}
+	return pv.GRPCTimeout()
+}
+
+// SetGRPCTimeout changes the timeout of the requests to the peer cosigners, e.g. on a config reload.
+// The requests in flight keep their timeout.
+func (pv *ThresholdValidator) SetGRPCTimeout(timeout time.Duration) {
+	pv.grpcTimeout.Store(int64(timeout))
+}
+
+// peerSetter is implemented by the cluster leaders that keep the peer cosigners, so that the peers
+// can be changed at runtime.
+type peerSetter interface {
+	SetPeers(peers []Cosigner) error
+}
+
+// Peers returns the peer cosigners.
+func (pv *ThresholdValidator) Peers() Cosigners {
+	pv.peersMu.RLock()
+	defer pv.peersMu.RUnlock()
+	return pv.peerCosigners
+}
+
+// SetPeers changes the peer cosigners at runtime, e.g. to add, remove or move a cosigner without
+// restarting the cluster. The nonce cache, the health checks, the sign state recovery and the
+// cluster leader all switch to the peers. The nonces and health of a removed or replaced peer are
+// discarded. The shard IDs of the peers must be of the key shares, which are not changed.
+func (pv *ThresholdValidator) SetPeers(peers []Cosigner) error {
+	if len(peers)+1 < pv.threshold {
+		return fmt.Errorf("%d cosigners are fewer than the threshold %d", len(peers)+1, pv.threshold)
+	}
+
+	pv.peersMu.Lock()
+	pv.peerCosigners = peers
+	pv.peersMu.Unlock()
+
+	allCosigners := make([]Cosigner, len(peers)+1)
+	allCosigners[0] = pv.myCosigner
+	copy(allCosigners[1:], peers)
+
+	pv.cosignerHealth.SetCosigners(peers)
+	pv.nonceCache.SetCosigners(allCosigners)
+	pv.signStateRecovery.SetPeers(peers)
+	if ps, ok := pv.leader.(peerSetter); ok {
+		if err := ps.SetPeers(peers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FeatureFlags returns the feature flags used to gradually roll out new behaviors.
+func (pv *ThresholdValidator) FeatureFlags() *FeatureFlags {
+	return pv.featureFlags
+}
+
+// SetWatermarkStore sets the high watermark store shared with other horcrux clusters.
+// Each HRS is claimed in the store before the signing process begins.
+func (pv *ThresholdValidator) SetWatermarkStore(watermark WatermarkStore) {
+	pv.watermark = watermark
 }
 
 // Start starts the ThresholdValidator.
@@ -109,6 +255,10 @@ func (pv *ThresholdValidator) Start(ctx context.Context) error {
 
 	go pv.cosignerHealth.Start(ctx)
 
+	if err := pv.nonceCache.LoadStats(pv.config.NonceCacheStatsFile()); err != nil {
+		// the cache learns the demand again.
+		pv.logger.Error("Failed to load nonce cache stats", "error", err)
+	}
 	go pv.nonceCache.Start(ctx)
 
 	go pv.myCosigner.StartNoncePruner(ctx)
@@ -118,8 +268,8 @@ func (pv *ThresholdValidator) Start(ctx context.Context) error {
 
 // SaveLastSignedState updates the high watermark height/round/step (HRS) for a completed
 // sign process if it is greater than the current high watermark. A mutex is used to avoid concurrent
-// state updates. The disk write is scheduled in a separate goroutine which will perform an atomic write.
-// pendingDiskWG is used upon termination in pendingDiskWG to ensure all writes have completed.
+// state updates. The state is persisted before the function returns. pendingDiskWG is used upon
+// termination to ensure all writes in flight have completed.
 func (pv *ThresholdValidator) SaveLastSignedState(chainID string, signState SignStateConsensus) error {
 	css := pv.mustLoadChainState(chainID)
 
@@ -144,8 +294,8 @@ func (pv *ThresholdValidator) mustLoadChainState(chainID string) ChainSignState
 
 // SaveLastSignedStateInitiated updates the high watermark height/round/step (HRS) for an initiated
 // sign process if it is greater than the current high watermark. A mutex is used to avoid concurrent
-// state updates. The disk write is scheduled in a separate goroutine which will perform an atomic write.
-// pendingDiskWG is used upon termination in pendingDiskWG to ensure all writes have completed.
+// state updates. The state is persisted before the function returns. pendingDiskWG is used upon
+// termination to ensure all writes in flight have completed.
 func (pv *ThresholdValidator) SaveLastSignedStateInitiated(chainID string, block *Block) ([]byte, time.Time, error) {
 	css := pv.mustLoadChainState(chainID)
 
@@ -194,7 +344,7 @@ func (pv *ThresholdValidator) SaveLastSignedStateInitiated(chainID string, block
 	defer css.lastSignState.cond.L.Unlock()
 	for i := 0; i < pv.maxWaitForSameBlockAttempts; i++ {
 		// block until sign state is saved. It will notify and unblock when block is next signed.
-		css.lastSignState.cond.WaitWithTimeout(pv.grpcTimeout)
+		css.lastSignState.cond.WaitWithTimeout(pv.chainGRPCTimeout(chainID))
 
 		// check if HRS exists in cache now
 		ssc, ok := css.lastSignState.cache[block.HRSKey()]
@@ -258,6 +408,10 @@ func (pv *ThresholdValidator) notifyBlockSignError(chainID string, hrs HRSKey, s
 // Stop safely shuts down the ThresholdValidator.
 func (pv *ThresholdValidator) Stop() {
 	pv.waitForSignStatesToFlushToDisk()
+
+	if err := pv.nonceCache.SaveStats(pv.config.NonceCacheStatsFile()); err != nil {
+		pv.logger.Error("Failed to save nonce cache stats", "error", err)
+	}
 }
 
 // waitForSignStatesToFlushToDisk waits for any sign states to finish writing to disk.
@@ -372,13 +526,14 @@ func (pv *ThresholdValidator) LoadSignStateIfNecessary(chainID string) error {
 		return nil
 	}
 
-	signState, err := LoadOrCreateSignState(pv.config.PrivValStateFile(chainID))
+	signState, err := pv.config.loadSignState(pv.logger, chainID, SignStateKindPrivVal, pv.signStateRecovery)
 	if err != nil {
 		return err
 	}
 
+	// progress on the sign state is only tracked in memory.
 	lastSignStateInitiated := signState.FreshCache()
-	lastSignStateInitiated.filePath = os.DevNull
+	lastSignStateInitiated.store = nil
 
 	pv.chainState.Store(chainID, ChainSignState{
 		lastSignState:          signState,
@@ -391,6 +546,77 @@ func (pv *ThresholdValidator) LoadSignStateIfNecessary(chainID string) error {
 	return pv.myCosigner.LoadSignStateIfNecessary(chainID)
 }
 
+// GetSignState returns the HRS of the sign state of the kind for the chain, or the greatest of
+// the validator and local cosigner sign states if kind is empty. It returns nil if there is no
+// such sign state for the chain.
+func (pv *ThresholdValidator) GetSignState(ctx context.Context, chainID string, kind SignStateKind) (*HRSKey, error) {
+	switch kind {
+	case SignStateKindPrivVal:
+		return pv.getPrivValSignState(chainID)
+	case SignStateKindCosigner:
+		return pv.myCosigner.GetSignState(ctx, chainID)
+	case "":
+	default:
+		return nil, fmt.Errorf("unknown sign state kind: %s", kind)
+	}
+
+	hrs, err := pv.getPrivValSignState(chainID)
+	if err != nil {
+		return nil, err
+	}
+	cosignerHRS, err := pv.myCosigner.GetSignState(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	if hrs == nil || (cosignerHRS != nil && cosignerHRS.GreaterThan(*hrs)) {
+		hrs = cosignerHRS
+	}
+	return hrs, nil
+}
+
+// ChainIDs returns the IDs of the chains signed since the cosigner started, as the leader or as a cosigner.
+func (pv *ThresholdValidator) ChainIDs() []string {
+	var chainIDs []string
+	add := func(key, _ any) bool {
+		if chainID := key.(string); !slices.Contains(chainIDs, chainID) {
+			chainIDs = append(chainIDs, chainID)
+		}
+		return true
+	}
+	pv.chainState.Range(add)
+	pv.myCosigner.chainState.Range(add)
+	slices.Sort(chainIDs)
+	return chainIDs
+}
+
+// LastSignedTimestamp returns the HRS and the timestamp of the greatest of the validator and local
+// cosigner sign states of the chain. Implements SignedTimestamps.
+func (pv *ThresholdValidator) LastSignedTimestamp(chainID string) (HRSTKey, bool, error) {
+	if err := pv.LoadSignStateIfNecessary(chainID); err != nil {
+		return HRSTKey{}, false, err
+	}
+	ccs, err := pv.myCosigner.getChainState(chainID)
+	if err != nil {
+		return HRSTKey{}, false, err
+	}
+	codec, err := pv.config.SignBytesCodec(chainID)
+	if err != nil {
+		return HRSTKey{}, false, err
+	}
+	return lastSignedTimestamp(codec,
+		pv.mustLoadChainState(chainID).lastSignState.SignStateConsensus(),
+		ccs.lastSignState.SignStateConsensus(),
+	)
+}
+
+func (pv *ThresholdValidator) getPrivValSignState(chainID string) (*HRSKey, error) {
+	if cs, ok := pv.chainState.Load(chainID); ok {
+		hrs := cs.(ChainSignState).lastSignState.HRSKey()
+		return &hrs, nil
+	}
+	return pv.config.peekSignState(chainID, SignStateKindPrivVal)
+}
+
 // getExistingBlockSignature returns the existing block signature and no error if the signature is valid for the block.
 // It returns nil signature and nil error if there is no signature and it's okay to sign (fresh or again).
 // It returns an error if we have already signed a greater block, or if we are still waiting for in in-progress sign.
@@ -435,8 +661,13 @@ func (pv *ThresholdValidator) compareBlockSignatureAgainstSSC(
 		return existingSignature.Signature, block.Timestamp, nil
 	}
 
+	codec, err := pv.config.SignBytesCodec(chainID)
+	if err != nil {
+		return nil, stamp, err
+	}
+
 	// If there is a difference in the existing signature payload other than timestamp, return that error.
-	if err := existingSignature.OnlyDifferByTimestamp(signBytes); err != nil {
+	if err := codec.OnlyDifferByTimestamp(existingSignature.Step, existingSignature.SignBytes, signBytes); err != nil {
 		return nil, stamp, err
 	}
 
@@ -465,8 +696,16 @@ func (pv *ThresholdValidator) compareBlockSignatureAgainstHRS(
 	return newStillWaitingForBlockError(chainID, blockHRS)
 }
 
+// clearNonces clears the cached nonces of the cosigner, which failed to sign with them, and backs it
+// off from the nonce generation if its nonces are cleared repeatedly.
+func (pv *ThresholdValidator) clearNonces(cosigner Cosigner) {
+	pv.nonceCache.ClearNonces(cosigner)
+	pv.cosignerHealth.RecordNoncesCleared(cosigner, time.Now())
+}
+
 func (pv *ThresholdValidator) getNoncesFallback(
 	ctx context.Context,
+	chainID string,
 ) (*CosignerUUIDNonces, []Cosigner, error) {
 	nonces := make(map[Cosigner]CosignerNonces)
 
@@ -480,9 +719,10 @@ func (pv *ThresholdValidator) getNoncesFallback(
 
 	u := uuid.New()
 
-	allCosigners := make([]Cosigner, len(pv.peerCosigners)+1)
+	peers := slices.DeleteFunc(slices.Clone(pv.Peers()), pv.cosignerHealth.Quarantined)
+	allCosigners := make([]Cosigner, len(peers)+1)
 	allCosigners[0] = pv.myCosigner
-	copy(allCosigners[1:], pv.peerCosigners)
+	copy(allCosigners[1:], peers)
 
 	for _, c := range allCosigners {
 		go pv.waitForPeerNonces(ctx, u, c, &wg, nonces, &mu)
@@ -490,7 +730,7 @@ func (pv *ThresholdValidator) getNoncesFallback(
 
 	// Wait for threshold cosigners to be complete
 	// A Cosigner will either respond in time, or be cancelled with timeout
-	if waitUntilCompleteOrTimeout(&wg, pv.grpcTimeout) {
+	if waitUntilCompleteOrTimeout(&wg, pv.chainGRPCTimeout(chainID)) {
 		return nil, nil, errors.New("timed out waiting for ephemeral shares")
 	}
 
@@ -510,6 +750,18 @@ func (pv *ThresholdValidator) getNoncesFallback(
 	}, thresholdCosigners, nil
 }
 
+// recordShares records in the health of the peer cosigners whether their share signatures combined
+// into a valid signature.
+func (pv *ThresholdValidator) recordShares(shareSigs []PartialSignature, valid bool) {
+	ids := make([]int, 0, len(shareSigs))
+	for _, s := range shareSigs {
+		if s.ID != pv.myCosigner.GetID() {
+			ids = append(ids, s.ID)
+		}
+	}
+	pv.cosignerHealth.RecordShares(ids, valid)
+}
+
 func waitUntilCompleteOrTimeout(wg *sync.WaitGroup, timeout time.Duration) bool {
 	c := make(chan struct{})
 	go func() {
@@ -538,7 +790,7 @@ func (pv *ThresholdValidator) waitForPeerNonces(
 		missedNonces.WithLabelValues(peer.GetAddress()).Inc()
 		totalMissedNonces.WithLabelValues(peer.GetAddress()).Inc()
 
-		pv.logger.Error("Error getting nonces", "cosigner", peer.GetID(), "err", err)
+		pv.logger.Error("Error getting nonces", "cosigner", peer.GetID(), "error", err)
 		return
 	}
 
@@ -554,6 +806,64 @@ func (pv *ThresholdValidator) waitForPeerNonces(
 	mu.Unlock()
 }
 
+// InspectNonceCache returns a summary of the nonces ready in the nonce cache.
+func (pv *ThresholdValidator) InspectNonceCache() NonceCacheInspection {
+	return pv.nonceCache.Inspect()
+}
+
+// TransferLeadership hands the leadership over to the cosigner with the shard ID, or to the next
+// eligible cosigner if empty, between sign rounds. It waits for the sign requests in flight, and
+// holds the new sign requests until this cosigner is no longer the leader, so that they are proxied
+// to the new leader instead of beginning sign rounds that the transfer would interrupt.
+func (pv *ThresholdValidator) TransferLeadership(ctx context.Context, shardID string) (string, string, error) {
+	leader, ok := pv.leader.(ClusterLeader)
+	if _, isCoordinator := pv.leader.(Coordinator); !ok || isCoordinator {
+		return "", "", errors.New("cosigners without a leader have no leadership to transfer")
+	}
+	if !leader.IsLeader() {
+		return "", "", errors.New("this cosigner is not the leader")
+	}
+
+	if err := pv.drainSignRounds(ctx); err != nil {
+		return "", "", err
+	}
+	defer pv.signRounds.Unlock()
+
+	pv.logger.Info("Sign requests in flight finished, transferring leadership", "cosigner", shardID)
+	leaderID, leaderAddress := leader.TransferLeadership(shardID)
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for leader.IsLeader() {
+		select {
+		case <-ctx.Done():
+			return "", "", fmt.Errorf("timed out waiting for the leadership to be transferred: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+	return leaderID, leaderAddress, nil
+}
+
+// drainSignRounds waits for the sign requests in flight and holds new ones until signRounds is
+// unlocked by the caller, or returns an error without holding them if ctx is done first.
+func (pv *ThresholdValidator) drainSignRounds(ctx context.Context) error {
+	drained := make(chan struct{})
+	go func() {
+		pv.signRounds.Lock()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		go func() {
+			<-drained
+			pv.signRounds.Unlock()
+		}()
+		return fmt.Errorf("timed out waiting for the sign requests in flight: %w", ctx.Err())
+	}
+}
+
 func (pv *ThresholdValidator) proxyIfNecessary(
 	ctx context.Context,
 	chainID string,
@@ -561,8 +871,12 @@ func (pv *ThresholdValidator) proxyIfNecessary(
 ) (bool, []byte, time.Time, error) {
 	height, round, step, stamp := block.Height, block.Round, block.Step, block.Timestamp
 
+	if coordinator, ok := pv.leader.(Coordinator); ok {
+		return pv.proxyToCoordinator(ctx, coordinator, chainID, block)
+	}
+
 	if pv.leader.IsLeader() {
-		return false, nil, time.Time{}, nil
+		return pv.proxyIfFenced(ctx, chainID, block)
 	}
 
 	leader := pv.leader.GetLeader()
@@ -590,12 +904,59 @@ func (pv *ThresholdValidator) proxyIfNecessary(
 	)
 	totalNotRaftLeader.Inc()
 
-	cosignerLeader := pv.peerCosigners.GetByID(leader)
+	cosignerLeader := pv.Peers().GetByID(leader)
 	if cosignerLeader == nil {
 		return true, nil, stamp, fmt.Errorf("failed to find cosigner with id %d", leader)
 	}
 
-	signRes, err := cosignerLeader.(*RemoteCosigner).Sign(ctx, CosignerSignBlockRequest{
+	signature, err := pv.proxySign(ctx, cosignerLeader, chainID, block)
+	return true, signature, stamp, err
+}
+
+// proxyIfFenced proxies the sign request to the other cosigner that claims the leadership if this
+// leader is fenced by a split brain, or returns false if it is not fenced. A request proxied to the
+// fenced leader by another cosigner is refused rather than proxied again, so that a request cannot
+// bounce between two leaders that both consider themselves fenced.
+func (pv *ThresholdValidator) proxyIfFenced(
+	ctx context.Context,
+	chainID string,
+	block Block,
+) (bool, []byte, time.Time, error) {
+	rival, fenced := pv.splitBrain.Status(time.Now())
+	if !fenced {
+		return false, nil, time.Time{}, nil
+	}
+	totalSplitBrainFencedRequests.Inc()
+
+	if isProxiedSignRequest(ctx) {
+		return true, nil, block.Timestamp, fmt.Errorf(
+			"fenced from signing, cosigner %d claims the leadership too", rival,
+		)
+	}
+
+	pv.logger.Error("Fenced from signing by split brain, proxying request to the other leader",
+		"chain_id", chainID,
+		"height", block.Height,
+		"round", block.Round,
+		"step", block.Step,
+		"leader", rival,
+	)
+	peer := pv.Peers().GetByID(rival)
+	if peer == nil {
+		return true, nil, block.Timestamp, fmt.Errorf("failed to find cosigner with id %d", rival)
+	}
+	signature, err := pv.proxySign(ctx, peer, chainID, block)
+	return true, signature, block.Timestamp, err
+}
+
+// proxySign proxies the sign request to the peer cosigner.
+func (pv *ThresholdValidator) proxySign(
+	ctx context.Context,
+	peer Cosigner,
+	chainID string,
+	block Block,
+) ([]byte, error) {
+	signRes, err := peer.(*RemoteCosigner).Sign(ctx, CosignerSignBlockRequest{
 		ChainID: chainID,
 		Block:   &block,
 	})
@@ -604,12 +965,12 @@ func (pv *ThresholdValidator) proxyIfNecessary(
 			rpcErrUnwrapped := err.(*cometrpcjsontypes.RPCError).Data
 			// Need to return BeyondBlockError after proxy since the error type will be lost over RPC
 			if len(rpcErrUnwrapped) > 33 && rpcErrUnwrapped[:33] == "Progress already started on block" {
-				return true, nil, stamp, &BeyondBlockError{msg: rpcErrUnwrapped}
+				return nil, &BeyondBlockError{msg: rpcErrUnwrapped}
 			}
 		}
-		return true, nil, stamp, err
+		return nil, err
 	}
-	return true, signRes.Signature, stamp, nil
+	return signRes.Signature, nil
 }
 
 func (pv *ThresholdValidator) Sign(ctx context.Context, chainID string, block Block) ([]byte, time.Time, error) {
@@ -626,6 +987,11 @@ func (pv *ThresholdValidator) Sign(ctx context.Context, chainID string, block Bl
 		return nil, stamp, err
 	}
 
+	// a leadership transfer waits for the requests in flight, and holds the new requests until the
+	// leadership is transferred, so that they are proxied to the new leader.
+	pv.signRounds.RLock()
+	defer pv.signRounds.RUnlock()
+
 	// Only the leader can execute this function. Followers can handle the requests,
 	// but they just need to proxy the request to the raft leader
 	isProxied, proxySig, proxyStamp, err := pv.proxyIfNecessary(ctx, chainID, block)
@@ -646,6 +1012,16 @@ func (pv *ThresholdValidator) Sign(ctx context.Context, chainID string, block Bl
 		Timestamp: stamp.UnixNano(),
 	}
 
+	persistStart := time.Now()
+
+	// a cluster on standby does not begin signing, which the claim of the watermark would refuse.
+	if standby, ok := pv.watermark.(watermarkStandby); ok {
+		if err := standby.Standby(); err != nil {
+			totalWatermarkStandbyRefused.WithLabelValues(chainID).Inc()
+			return nil, stamp, err
+		}
+	}
+
 	// Keep track of the last block that we began the signing process for. Only allow one attempt per block
 	existingSignature, existingTimestamp, err := pv.SaveLastSignedStateInitiated(chainID, &block)
 	if err != nil {
@@ -656,23 +1032,34 @@ func (pv *ThresholdValidator) Sign(ctx context.Context, chainID string, block Bl
 		return existingSignature, existingTimestamp, nil
 	}
 
-	numPeers := len(pv.peerCosigners)
-	total := uint8(numPeers + 1)
+	if pv.watermark != nil {
+		if err := pv.watermark.Claim(ctx, chainID, block.HRSKey()); err != nil {
+			if _, ok := err.(*WatermarkStandbyError); ok {
+				totalWatermarkStandbyRefused.WithLabelValues(chainID).Inc()
+			}
+			totalWatermarkClaimFailed.WithLabelValues(chainID).Inc()
+			return nil, stamp, fmt.Errorf("error claiming shared watermark: %w", err)
+		}
+	}
+
+	persistDuration := time.Since(persistStart)
 
 	peerStartTime := time.Now()
 
 	cosignersOrderedByFastest := pv.cosignerHealth.GetFastest()
-	cosignersForThisBlock := make([]Cosigner, pv.threshold)
-	cosignersForThisBlock[0] = pv.myCosigner
-	copy(cosignersForThisBlock[1:], cosignersOrderedByFastest[:pv.threshold-1])
-
-	nonces, err := pv.nonceCache.GetNonces(cosignersForThisBlock)
+	nonces, cosignersForThisBlock, err := pv.nonceCache.WaitBestNonces(
+		ctx, pv.myCosigner, cosignersOrderedByFastest, pv.config.Config.Chains.nonceExpiration(chainID))
 
 	var dontIterateFastestCosigners bool
 
 	if err != nil {
+		if !pv.featureFlags.Enabled(FeatureFlagNonceFallback, chainID) {
+			pv.notifyBlockSignError(chainID, block.HRSKey(), signBytes)
+			return nil, stamp, fmt.Errorf("failed to get nonces, the %s feature flag is disabled: %w",
+				FeatureFlagNonceFallback, err)
+		}
 		var fallbackErr error
-		nonces, cosignersForThisBlock, fallbackErr = pv.getNoncesFallback(ctx)
+		nonces, cosignersForThisBlock, fallbackErr = pv.getNoncesFallback(ctx, chainID)
 		if fallbackErr != nil {
 			pv.notifyBlockSignError(chainID, block.HRSKey(), signBytes)
 			return nil, stamp, fmt.Errorf("failed to get nonces: %w", errors.Join(err, fallbackErr))
@@ -681,23 +1068,35 @@ func (pv *ThresholdValidator) Sign(ctx context.Context, chainID string, block Bl
 	} else {
 		drainedNonceCache.Set(0)
 	}
+	if !pv.featureFlags.Enabled(FeatureFlagAlternateCosigners, chainID) {
+		dontIterateFastestCosigners = true
+	}
 
-	nextFastestCosignerIndex := pv.threshold - 1
+	timedSignPhase.WithLabelValues(chainID, signPhaseNonces).Observe(time.Since(peerStartTime).Seconds())
+
+	// the peers not signing this block, in the order they are tried when a signing peer fails.
+	alternateCosigners := make([]Cosigner, 0, len(cosignersOrderedByFastest))
+	for _, c := range cosignersOrderedByFastest {
+		if !slices.ContainsFunc(cosignersForThisBlock, func(s Cosigner) bool { return s.GetID() == c.GetID() }) {
+			alternateCosigners = append(alternateCosigners, c)
+		}
+	}
+	nextFastestCosignerIndex := 0
 	var nextFastestCosignerIndexMu sync.Mutex
 	getNextFastestCosigner := func() Cosigner {
 		nextFastestCosignerIndexMu.Lock()
 		defer nextFastestCosignerIndexMu.Unlock()
-		if nextFastestCosignerIndex >= len(cosignersOrderedByFastest) {
+		if nextFastestCosignerIndex >= len(alternateCosigners) {
 			return nil
 		}
-		cosigner := cosignersOrderedByFastest[nextFastestCosignerIndex]
+		cosigner := alternateCosigners[nextFastestCosignerIndex]
 		nextFastestCosignerIndex++
 		return cosigner
 	}
 
 	timedSignBlockThresholdLag.Observe(time.Since(timeStartSignBlock).Seconds())
 
-	for _, peer := range pv.peerCosigners {
+	for _, peer := range pv.Peers() {
 		missedNonces.WithLabelValues(peer.GetAddress()).Set(0)
 		timedCosignerNonceLag.WithLabelValues(peer.GetAddress()).Observe(time.Since(peerStartTime).Seconds())
 	}
@@ -708,15 +1107,24 @@ func (pv *ThresholdValidator) Sign(ctx context.Context, chainID string, block Bl
 		cosignersForThisBlockInt[i] = cosigner.GetID()
 	}
 
-	// destination for share signatures
+	// destination for share signatures, indexed by shard ID since peers may have been removed
+	total := pv.myCosigner.GetID()
+	for _, c := range cosignersOrderedByFastest {
+		total = max(total, c.GetID())
+	}
+	for _, c := range cosignersForThisBlock {
+		total = max(total, c.GetID())
+	}
 	shareSignatures := make([][]byte, total)
 
+	shareSignStart := time.Now()
+
 	var eg errgroup.Group
 	for _, cosigner := range cosignersForThisBlock {
 		cosigner := cosigner
 		eg.Go(func() error {
 			for cosigner != nil {
-				signCtx, cancel := context.WithTimeout(ctx, pv.grpcTimeout)
+				signCtx, cancel := context.WithTimeout(ctx, pv.chainGRPCTimeout(chainID))
 				defer cancel()
 
 				peerStartTime := time.Now()
@@ -728,15 +1136,18 @@ func (pv *ThresholdValidator) Sign(ctx context.Context, chainID string, block Bl
 					HRST:      hrst,
 					SignBytes: signBytes,
 				})
+				if cosigner.GetID() != pv.myCosigner.GetID() {
+					pv.cosignerHealth.RecordResult(cosigner, time.Since(peerStartTime), err)
+				}
 				if err != nil {
 					log.Error(
 						"Cosigner failed to set nonces and sign",
 						"cosigner", cosigner.GetID(),
-						"err", err.Error(),
+						"error", err.Error(),
 					)
 
 					if strings.Contains(err.Error(), errUnexpectedState) {
-						pv.nonceCache.ClearNonces(cosigner)
+						pv.clearNonces(cosigner)
 					}
 
 					if cosigner.GetID() == pv.myCosigner.GetID() {
@@ -745,7 +1156,7 @@ func (pv *ThresholdValidator) Sign(ctx context.Context, chainID string, block Bl
 
 					if c := status.Code(err); c == codes.DeadlineExceeded || c == codes.NotFound || c == codes.Unavailable {
 						pv.cosignerHealth.MarkUnhealthy(cosigner)
-						pv.nonceCache.ClearNonces(cosigner)
+						pv.clearNonces(cosigner)
 					}
 
 					if dontIterateFastestCosigners {
@@ -759,6 +1170,8 @@ func (pv *ThresholdValidator) Sign(ctx context.Context, chainID string, block Bl
 					continue
 				}
 
+				timedSignPhaseCosignerShare.WithLabelValues(chainID, strconv.Itoa(cosigner.GetID())).
+					Observe(time.Since(peerStartTime).Seconds())
 				if cosigner != pv.myCosigner {
 					timedCosignerSignLag.WithLabelValues(cosigner.GetAddress()).Observe(time.Since(peerStartTime).Seconds())
 				}
@@ -776,6 +1189,7 @@ func (pv *ThresholdValidator) Sign(ctx context.Context, chainID string, block Bl
 	}
 
 	timedSignBlockCosignerLag.Observe(time.Since(timeStartSignBlock).Seconds())
+	timedSignPhase.WithLabelValues(chainID, signPhaseShareSign).Observe(time.Since(shareSignStart).Seconds())
 
 	// collect all valid responses into array of partial signatures
 	shareSigs := make([]PartialSignature, 0, pv.threshold)
@@ -801,6 +1215,8 @@ func (pv *ThresholdValidator) Sign(ctx context.Context, chainID string, block Bl
 		return nil, stamp, errors.New("not enough co-signers")
 	}
 
+	combineStart := time.Now()
+
 	// assemble into final signature
 	signature, err := pv.myCosigner.CombineSignatures(chainID, shareSigs)
 	if err != nil {
@@ -809,13 +1225,17 @@ func (pv *ThresholdValidator) Sign(ctx context.Context, chainID string, block Bl
 	}
 
 	// verify the combined signature before saving to watermark
-	if !pv.myCosigner.VerifySignature(chainID, signBytes, signature) {
+	valid := pv.myCosigner.VerifySignature(chainID, signBytes, signature)
+	pv.recordShares(shareSigs, valid)
+	if !valid {
 		totalInvalidSignature.Inc()
 
 		pv.notifyBlockSignError(chainID, block.HRSKey(), signBytes)
 		return nil, stamp, errors.New("combined signature is not valid")
 	}
 
+	timedSignPhase.WithLabelValues(chainID, signPhaseCombine).Observe(time.Since(combineStart).Seconds())
+
 	newLss := ChainSignStateConsensus{
 		ChainID: chainID,
 		SignStateConsensus: SignStateConsensus{
@@ -827,6 +1247,8 @@ func (pv *ThresholdValidator) Sign(ctx context.Context, chainID string, block Bl
 		},
 	}
 
+	persistStart = time.Now()
+
 	css := pv.mustLoadChainState(chainID)
 
 	// Err will be present if newLss is not above high watermark
@@ -849,6 +1271,9 @@ func (pv *ThresholdValidator) Sign(ctx context.Context, chainID string, block Bl
 		log.Error("Error emitting LSS", err.Error())
 	}
 
+	persistDuration += time.Since(persistStart)
+	timedSignPhase.WithLabelValues(chainID, signPhasePersist).Observe(persistDuration.Seconds())
+
 	timeSignBlock := time.Since(timeStartSignBlock)
 	timeSignBlockSec := timeSignBlock.Seconds()
 	timedSignBlockLag.Observe(timeSignBlockSec)
@@ -8,6 +8,7 @@ import (
 	mrand "math/rand"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"os"
@@ -22,6 +23,7 @@ import (
 	comet "github.com/cometbft/cometbft/types"
 	"github.com/ethereum/go-ethereum/crypto/ecies"
 	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	tsed25519 "gitlab.com/unit410/threshold-ed25519/pkg"
 	"golang.org/x/sync/errgroup"
@@ -110,6 +112,10 @@ func testThresholdValidator(t *testing.T, threshold, total uint8) {
 
 	require.True(t, pubKey.VerifySignature(block.SignBytes, signature))
 
+	// nonces, share_sign, combine and persist.
+	require.GreaterOrEqual(t, testutil.CollectAndCount(timedSignPhase), 4)
+	require.GreaterOrEqual(t, testutil.CollectAndCount(timedSignPhaseCosignerShare), int(threshold))
+
 	firstSignature := signature
 
 	require.Len(t, firstSignature, 64)
@@ -575,3 +581,62 @@ func testThresholdValidatorLeaderElection(t *testing.T, threshold, total uint8)
 func TestThresholdValidatorLeaderElection2of3(t *testing.T) {
 	testThresholdValidatorLeaderElection(t, 2, 3)
 }
+
+// handoverLeader is a ClusterLeader that gives up the leadership when it is transferred.
+type handoverLeader struct {
+	isLeader  atomic.Bool
+	transfers atomic.Int32
+}
+
+func (l *handoverLeader) IsLeader() bool                              { return l.isLeader.Load() }
+func (l *handoverLeader) GetLeader() int                              { return 1 }
+func (l *handoverLeader) ShareSigned(_ ChainSignStateConsensus) error { return nil }
+func (l *handoverLeader) SetFeatureFlag(_ FeatureFlagEvent) error     { return nil }
+
+func (l *handoverLeader) TransferLeadership(shardID string) (string, string) {
+	l.transfers.Add(1)
+	l.isLeader.Store(false)
+	return shardID, ""
+}
+
+func TestThresholdValidatorTransferLeadership(t *testing.T) {
+	leader := &handoverLeader{}
+	pv := &ThresholdValidator{leader: leader, logger: cometlog.NewNopLogger()}
+
+	_, _, err := pv.TransferLeadership(context.Background(), "2")
+	require.Error(t, err, "only the leader transfers the leadership")
+
+	// the leadership is transferred once the sign request in flight finished.
+	leader.isLeader.Store(true)
+	pv.signRounds.RLock()
+	transferred := make(chan error)
+	go func() {
+		_, _, err := pv.TransferLeadership(context.Background(), "2")
+		transferred <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	require.Zero(t, leader.transfers.Load())
+	pv.signRounds.RUnlock()
+	require.NoError(t, <-transferred)
+	require.EqualValues(t, 1, leader.transfers.Load())
+	require.False(t, leader.IsLeader())
+
+	// the transfer gives up if the sign request in flight does not finish in time.
+	leader.isLeader.Store(true)
+	pv.signRounds.RLock()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, _, err = pv.TransferLeadership(ctx, "2")
+	require.Error(t, err)
+	require.EqualValues(t, 1, leader.transfers.Load())
+	pv.signRounds.RUnlock()
+
+	// new sign requests are not held once the transfer gave up.
+	require.Eventually(t, func() bool {
+		if !pv.signRounds.TryRLock() {
+			return false
+		}
+		pv.signRounds.RUnlock()
+		return true
+	}, time.Second, 10*time.Millisecond)
+}
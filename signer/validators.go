@@ -0,0 +1,54 @@
+package signer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// validatorChainIDSeparator separates the validator from the chain ID in a validator chain ID.
+const validatorChainIDSeparator = "@"
+
+// validatorNamePattern restricts the validator names to names that are safe in file names and
+// metric labels, and that do not contain validatorChainIDSeparator.
+var validatorNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// ValidatorChainID returns the ID the validator signs the chain with. The key shards, sign states
+// and metrics of each validator of a chain are kept apart by it. The validator chain ID of the
+// default validator, with an empty name, is the chain ID.
+func ValidatorChainID(validator, chainID string) string {
+	if validator == "" {
+		return chainID
+	}
+	return validator + validatorChainIDSeparator + chainID
+}
+
+// SplitValidatorChainID returns the validator and the chain ID of a validator chain ID.
+func SplitValidatorChainID(id string) (validator, chainID string) {
+	validator, chainID, ok := strings.Cut(id, validatorChainIDSeparator)
+	if !ok || !validatorNamePattern.MatchString(validator) {
+		return "", id
+	}
+	return validator, chainID
+}
+
+// validatorChainValue returns the value of the validator chain ID in m, which is keyed by chain ID or
+// validator chain ID: that of the validator chain ID if it has its own, otherwise that of the chain
+// ID, which every validator of the chain shares.
+func validatorChainValue[T any](m map[string]T, id string) (T, bool) {
+	if v, ok := m[id]; ok {
+		return v, true
+	}
+	_, chainID := SplitValidatorChainID(id)
+	v, ok := m[chainID]
+	return v, ok
+}
+
+// ValidateValidatorName returns an error if the validator name is not valid. The empty name of the
+// default validator is valid.
+func ValidateValidatorName(validator string) error {
+	if validator != "" && !validatorNamePattern.MatchString(validator) {
+		return fmt.Errorf("invalid validator name %q", validator)
+	}
+	return nil
+}
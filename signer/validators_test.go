@@ -0,0 +1,50 @@
+package signer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatorChainID(t *testing.T) {
+	require.Equal(t, "cosmoshub-4", ValidatorChainID("", "cosmoshub-4"))
+	require.Equal(t, "acme@cosmoshub-4", ValidatorChainID("acme", "cosmoshub-4"))
+
+	for id, expected := range map[string][2]string{
+		"cosmoshub-4":      {"", "cosmoshub-4"},
+		"acme@cosmoshub-4": {"acme", "cosmoshub-4"},
+		"acme@chain@1":     {"acme", "chain@1"},
+		"@cosmoshub-4":     {"", "@cosmoshub-4"},
+		"ac.me@chain":      {"", "ac.me@chain"},
+	} {
+		validator, chainID := SplitValidatorChainID(id)
+		require.Equal(t, expected, [2]string{validator, chainID}, id)
+	}
+
+	require.NoError(t, ValidateValidatorName(""))
+	require.NoError(t, ValidateValidatorName("acme_validator-1"))
+	require.Error(t, ValidateValidatorName("acme@corp"))
+	require.Error(t, ValidateValidatorName("-acme"))
+}
+
+func TestValidatorChainRPC(t *testing.T) {
+	cfgs := ChainRPCConfigs{
+		"cosmoshub-4":      {URL: "http://cosmoshub:26657"},
+		"acme@cosmoshub-4": {URL: "http://acme-cosmoshub:26657"},
+	}
+	for id, url := range map[string]string{
+		"cosmoshub-4":       "http://cosmoshub:26657",
+		"acme@cosmoshub-4":  "http://acme-cosmoshub:26657",
+		"other@cosmoshub-4": "http://cosmoshub:26657",
+	} {
+		cfg, ok := validatorChainValue(cfgs, id)
+		require.True(t, ok, id)
+		require.Equal(t, url, cfg.URL, id)
+	}
+	_, ok := validatorChainValue(cfgs, "acme@osmosis-1")
+	require.False(t, ok)
+
+	c := RuntimeConfig{Config: Config{SignBytesCodecs: map[string]string{"cosmoshub-4": "unknown"}}}
+	_, err := c.SignBytesCodec("acme@cosmoshub-4")
+	require.Error(t, err, "the codec of the chain is used for every validator")
+}
@@ -0,0 +1,478 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/libs/service"
+	"go.etcd.io/etcd/client/pkg/v3/transport"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const (
+	// WatermarkStoreEtcd records the shared high watermark in an etcd cluster.
+	WatermarkStoreEtcd = "etcd"
+
+	defaultEtcdWatermarkPrefix = "/horcrux/watermark"
+	defaultEtcdDialTimeout     = 5 * time.Second
+
+	// etcdWatermarkActiveKey is the key under the prefix that holds the ID of the active cluster
+	// with failover. Chain IDs do not start with a dot.
+	etcdWatermarkActiveKey = ".active"
+
+	// etcdWatermarkRetryPeriod is the interval of the attempts to follow the watermarks again
+	// after the connection to etcd failed.
+	etcdWatermarkRetryPeriod = 5 * time.Second
+)
+
+// WatermarkStore records the high watermark HRS of each chain outside of the cluster,
+// so that multiple horcrux clusters for the same validator, e.g. during a migration
+// or disaster recovery failover, can never both sign the same height.
+type WatermarkStore interface {
+	// Claim records hrs as the high watermark for the chain on behalf of this cluster.
+	// It returns a *WatermarkClaimError if another cluster has already claimed the height,
+	// or if hrs is not beyond the recorded watermark.
+	Claim(ctx context.Context, chainID string, hrs HRSKey) error
+}
+
+type WatermarkClaimError struct {
+	msg string
+}
+
+func (e *WatermarkClaimError) Error() string { return e.msg }
+
+// WatermarkStandbyError is returned for the sign requests of a cluster on standby, which does not
+// sign until it is promoted.
+type WatermarkStandbyError struct {
+	msg string
+}
+
+func (e *WatermarkStandbyError) Error() string { return e.msg }
+
+func newWatermarkStandbyError(clusterID string, active string) *WatermarkStandbyError {
+	if active == "" {
+		return &WatermarkStandbyError{
+			msg: fmt.Sprintf("cluster %s is on standby, no cluster was promoted", clusterID),
+		}
+	}
+	return &WatermarkStandbyError{
+		msg: fmt.Sprintf("cluster %s is on standby, cluster %s is active", clusterID, active),
+	}
+}
+
+// watermarkStandby is implemented by the watermark stores with failover between clusters, so that
+// a cluster on standby refuses sign requests before it begins signing.
+type watermarkStandby interface {
+	// Standby returns a *WatermarkStandbyError if this cluster is on standby.
+	Standby() error
+}
+
+func newWatermarkClaimError(chainID string, hrs HRSKey, existing watermark) *WatermarkClaimError {
+	return &WatermarkClaimError{
+		msg: fmt.Sprintf("cannot claim %d.%d.%d for chain %s, watermark is %d.%d.%d claimed by cluster %s",
+			hrs.Height, hrs.Round, hrs.Step, chainID,
+			existing.Height, existing.Round, existing.Step, existing.ClusterID,
+		),
+	}
+}
+
+// watermark is the recorded high watermark of a chain.
+type watermark struct {
+	Height    int64  `json:"height"`
+	Round     int64  `json:"round"`
+	Step      int8   `json:"step"`
+	ClusterID string `json:"cluster_id"`
+}
+
+func (w watermark) hrsKey() HRSKey {
+	return HRSKey{Height: w.Height, Round: w.Round, Step: w.Step}
+}
+
+// claimAllowed returns whether clusterID may advance the watermark to hrs.
+// A cluster may move to a greater height, or to a greater round/step within a height
+// that it has claimed itself. Re-claiming the same HRS is allowed for the owning cluster
+// so that a new raft leader can retry a sign request.
+func (w watermark) claimAllowed(clusterID string, hrs HRSKey) bool {
+	if hrs.Height > w.Height {
+		return true
+	}
+	if hrs.Height < w.Height || clusterID != w.ClusterID {
+		return false
+	}
+	return !w.hrsKey().GreaterThan(hrs)
+}
+
+// WatermarkStoreConfig is the on disk config format for the shared watermark store.
+type WatermarkStoreConfig struct {
+	Type string `yaml:"type"`
+
+	// ClusterID identifies this horcrux cluster. It must be the same on every cosigner
+	// of a cluster and different between clusters.
+	ClusterID string `yaml:"clusterID"`
+
+	// Failover lets only one of the clusters sharing the store sign at a time, the cluster last
+	// promoted with `horcrux cluster promote`, while the others are on standby.
+	Failover bool `yaml:"failover,omitempty"`
+
+	Endpoints   []string `yaml:"endpoints"`
+	Prefix      string   `yaml:"prefix,omitempty"`
+	DialTimeout string   `yaml:"dialTimeout,omitempty"`
+	Username    string   `yaml:"username,omitempty"`
+	Password    string   `yaml:"password,omitempty"`
+	CAFile      string   `yaml:"caFile,omitempty"`
+	CertFile    string   `yaml:"certFile,omitempty"`
+	KeyFile     string   `yaml:"keyFile,omitempty"`
+}
+
+func (cfg *WatermarkStoreConfig) Validate() error {
+	if cfg == nil {
+		return nil
+	}
+	switch cfg.Type {
+	case WatermarkStoreEtcd:
+		if len(cfg.Endpoints) == 0 {
+			return fmt.Errorf("etcd watermark store requires endpoints")
+		}
+	default:
+		return fmt.Errorf("unknown watermark store %q, must be one of %v", cfg.Type, []string{WatermarkStoreEtcd})
+	}
+	if cfg.ClusterID == "" {
+		return fmt.Errorf("watermark store requires clusterID")
+	}
+	if _, err := cfg.dialTimeout(); err != nil {
+		return fmt.Errorf("invalid watermark store dialTimeout: %w", err)
+	}
+	return nil
+}
+
+func (cfg *WatermarkStoreConfig) dialTimeout() (time.Duration, error) {
+	if cfg.DialTimeout == "" {
+		return defaultEtcdDialTimeout, nil
+	}
+	return time.ParseDuration(cfg.DialTimeout)
+}
+
+// WatermarkStore returns the configured shared watermark store, or nil if none is configured.
+func (cfg *ThresholdModeConfig) WatermarkStore(logger cometlog.Logger) (WatermarkStore, error) {
+	if cfg.Watermark == nil {
+		return nil, nil
+	}
+	if err := cfg.Watermark.Validate(); err != nil {
+		return nil, err
+	}
+	return NewEtcdWatermarkStore(logger, cfg.Watermark)
+}
+
+var _ WatermarkStore = &EtcdWatermarkStore{}
+
+// EtcdWatermarkStore records the watermark of each chain under <prefix>/<chainID>,
+// and advances it using transactions conditioned on the key's mod revision.
+//
+// With failover, the ID of the active cluster is recorded under <prefix>/.active, and the claims
+// are conditioned on it too, so that a cluster that was replaced by the promotion of another
+// cluster cannot claim another height, even if it has not noticed the promotion yet. While it
+// runs, the store follows the watermarks of all clusters and the active cluster.
+type EtcdWatermarkStore struct {
+	service.BaseService
+
+	logger    cometlog.Logger
+	client    *clientv3.Client
+	prefix    string
+	clusterID string
+	failover  bool
+	cancel    context.CancelFunc
+
+	mu sync.RWMutex
+	// active is the ID of the active cluster last seen, empty if no cluster was promoted.
+	active string
+}
+
+// NewEtcdWatermarkStore connects to the etcd cluster for the config.
+func NewEtcdWatermarkStore(logger cometlog.Logger, cfg *WatermarkStoreConfig) (*EtcdWatermarkStore, error) {
+	dialTimeout, err := cfg.dialTimeout()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newEtcdClient(etcdClientConfig{
+		endpoints:   cfg.Endpoints,
+		dialTimeout: dialTimeout,
+		username:    cfg.Username,
+		password:    cfg.Password,
+		caFile:      cfg.CAFile,
+		certFile:    cfg.CertFile,
+		keyFile:     cfg.KeyFile,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := cfg.Prefix
+	if prefix == "" {
+		prefix = defaultEtcdWatermarkPrefix
+	}
+
+	s := &EtcdWatermarkStore{
+		logger:    logger,
+		client:    client,
+		prefix:    strings.TrimSuffix(prefix, "/"),
+		clusterID: cfg.ClusterID,
+		failover:  cfg.Failover,
+	}
+	s.BaseService = *service.NewBaseService(logger, "EtcdWatermarkStore", s)
+	return s, nil
+}
+
+func (s *EtcdWatermarkStore) key(chainID string) string {
+	return s.prefix + "/" + chainID
+}
+
+// OnStart follows the watermarks and the active cluster.
+func (s *EtcdWatermarkStore) OnStart() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	go s.follow(ctx)
+	return nil
+}
+
+func (s *EtcdWatermarkStore) OnStop() {
+	s.cancel()
+	_ = s.Close()
+}
+
+// follow reads the watermarks and the active cluster and watches them for changes, until the
+// context is done.
+func (s *EtcdWatermarkStore) follow(ctx context.Context) {
+	for {
+		res, err := s.client.Get(ctx, s.prefix+"/", clientv3.WithPrefix())
+		if err == nil {
+			for _, kv := range res.Kvs {
+				s.observe(string(kv.Key), kv.Value)
+			}
+			watch := s.client.Watch(ctx, s.prefix+"/", clientv3.WithPrefix(), clientv3.WithRev(res.Header.Revision+1))
+			for wres := range watch {
+				if err = wres.Err(); err != nil {
+					break
+				}
+				for _, ev := range wres.Events {
+					if ev.Type == clientv3.EventTypePut {
+						s.observe(string(ev.Kv.Key), ev.Kv.Value)
+					}
+				}
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		s.logger.Error("Failed to follow shared watermarks", "prefix", s.prefix, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(etcdWatermarkRetryPeriod):
+		}
+	}
+}
+
+// observe records the value of the key under the prefix, the active cluster or the watermark of a chain.
+func (s *EtcdWatermarkStore) observe(key string, value []byte) {
+	name := strings.TrimPrefix(key, s.prefix+"/")
+	if name == etcdWatermarkActiveKey {
+		s.setActive(string(value))
+		return
+	}
+	var w watermark
+	if err := json.Unmarshal(value, &w); err != nil {
+		s.logger.Error("Failed to unmarshal shared watermark", "chain_id", name, "error", err)
+		return
+	}
+	sharedWatermarkHeight.WithLabelValues(name).Set(float64(w.Height))
+}
+
+func (s *EtcdWatermarkStore) setActive(active string) {
+	s.mu.Lock()
+	changed := active != s.active
+	s.active = active
+	s.mu.Unlock()
+
+	if !s.failover {
+		return
+	}
+	if active == s.clusterID {
+		watermarkClusterActive.Set(1)
+	} else {
+		watermarkClusterActive.Set(0)
+	}
+	if !changed {
+		return
+	}
+	if active == s.clusterID {
+		s.logger.Info("Cluster is active", "cluster_id", s.clusterID)
+	} else {
+		s.logger.Info("Cluster is on standby", "cluster_id", s.clusterID, "active_cluster_id", active)
+	}
+}
+
+// Standby returns a *WatermarkStandbyError if this cluster is not the active cluster last seen.
+// A cluster is never on standby without failover.
+func (s *EtcdWatermarkStore) Standby() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.failover || s.active == s.clusterID {
+		return nil
+	}
+	return newWatermarkStandbyError(s.clusterID, s.active)
+}
+
+// Active returns the ID of the active cluster, or an empty string if no cluster was promoted.
+func (s *EtcdWatermarkStore) Active(ctx context.Context) (string, error) {
+	res, err := s.client.Get(ctx, s.key(etcdWatermarkActiveKey))
+	if err != nil {
+		return "", fmt.Errorf("failed to get active cluster from etcd: %w", err)
+	}
+	if len(res.Kvs) == 0 {
+		return "", nil
+	}
+	return string(res.Kvs[0].Value), nil
+}
+
+// Promote makes this cluster the active cluster, and returns the ID of the cluster that was active
+// before. The promotion is a compare-and-swap of the active cluster, so of two clusters promoted
+// at the same time, only one is promoted and the other fails.
+func (s *EtcdWatermarkStore) Promote(ctx context.Context) (string, error) {
+	if !s.failover {
+		return "", fmt.Errorf("cluster %s does not have failover enabled", s.clusterID)
+	}
+	key := s.key(etcdWatermarkActiveKey)
+	res, err := s.client.Get(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to get active cluster from etcd: %w", err)
+	}
+	cmp := clientv3.Compare(clientv3.CreateRevision(key), "=", 0)
+	var previous string
+	if len(res.Kvs) != 0 {
+		previous = string(res.Kvs[0].Value)
+		if previous == s.clusterID {
+			return previous, nil
+		}
+		cmp = clientv3.Compare(clientv3.ModRevision(key), "=", res.Kvs[0].ModRevision)
+	}
+
+	txn, err := s.client.Txn(ctx).If(cmp).Then(clientv3.OpPut(key, s.clusterID)).Commit()
+	if err != nil {
+		return "", fmt.Errorf("failed to promote cluster in etcd: %w", err)
+	}
+	if !txn.Succeeded {
+		return "", fmt.Errorf("active cluster changed concurrently from %q, promotion of cluster %s aborted",
+			previous, s.clusterID)
+	}
+	return previous, nil
+}
+
+func (s *EtcdWatermarkStore) Claim(ctx context.Context, chainID string, hrs HRSKey) error {
+	key := s.key(chainID)
+	activeKey := s.key(etcdWatermarkActiveKey)
+
+	value, err := json.Marshal(watermark{
+		Height:    hrs.Height,
+		Round:     hrs.Round,
+		Step:      hrs.Step,
+		ClusterID: s.clusterID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		res, err := s.client.Get(ctx, key)
+		if err != nil {
+			return fmt.Errorf("failed to get watermark from etcd: %w", err)
+		}
+
+		var cmp clientv3.Cmp
+		if len(res.Kvs) == 0 {
+			cmp = clientv3.Compare(clientv3.CreateRevision(key), "=", 0)
+		} else {
+			var existing watermark
+			if err := json.Unmarshal(res.Kvs[0].Value, &existing); err != nil {
+				return fmt.Errorf("failed to unmarshal watermark for chain %s: %w", chainID, err)
+			}
+			if !existing.claimAllowed(s.clusterID, hrs) {
+				return newWatermarkClaimError(chainID, hrs, existing)
+			}
+			cmp = clientv3.Compare(clientv3.ModRevision(key), "=", res.Kvs[0].ModRevision)
+		}
+		cmps := []clientv3.Cmp{cmp}
+
+		if s.failover {
+			active, err := s.Active(ctx)
+			if err != nil {
+				return err
+			}
+			s.setActive(active)
+			if active != s.clusterID {
+				return newWatermarkStandbyError(s.clusterID, active)
+			}
+			// the claim fails if another cluster is promoted in the meantime.
+			cmps = append(cmps, clientv3.Compare(clientv3.Value(activeKey), "=", s.clusterID))
+		}
+
+		txn, err := s.client.Txn(ctx).If(cmps...).Then(clientv3.OpPut(key, string(value))).Commit()
+		if err != nil {
+			return fmt.Errorf("failed to claim watermark in etcd: %w", err)
+		}
+		if txn.Succeeded {
+			return nil
+		}
+		// the watermark was changed concurrently, check it again.
+	}
+}
+
+// etcdClientConfig is the connection to an etcd cluster.
+type etcdClientConfig struct {
+	endpoints   []string
+	dialTimeout time.Duration
+	username    string
+	password    string
+	caFile      string
+	certFile    string
+	keyFile     string
+}
+
+// newEtcdClient connects to the etcd cluster, with TLS if a CA or client certificate is configured.
+func newEtcdClient(cfg etcdClientConfig) (*clientv3.Client, error) {
+	etcdConfig := clientv3.Config{
+		Endpoints:   cfg.endpoints,
+		DialTimeout: cfg.dialTimeout,
+		Username:    cfg.username,
+		Password:    cfg.password,
+	}
+	if cfg.caFile != "" || cfg.certFile != "" {
+		tlsInfo := transport.TLSInfo{
+			TrustedCAFile: cfg.caFile,
+			CertFile:      cfg.certFile,
+			KeyFile:       cfg.keyFile,
+		}
+		var err error
+		etcdConfig.TLS, err = tlsInfo.ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load etcd tls config: %w", err)
+		}
+	}
+
+	client, err := clientv3.New(etcdConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to etcd: %w", err)
+	}
+	return client, nil
+}
+
+// Close closes the etcd client.
+func (s *EtcdWatermarkStore) Close() error {
+	return s.client.Close()
+}
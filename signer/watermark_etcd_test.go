@@ -0,0 +1,85 @@
+package signer
+
+import (
+	"testing"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatermarkClaimAllowed(t *testing.T) {
+	existing := watermark{Height: 10, Round: 1, Step: stepPrevote, ClusterID: "a"}
+
+	type testCase struct {
+		name      string
+		clusterID string
+		hrs       HRSKey
+		allowed   bool
+	}
+
+	testCases := []testCase{
+		{"greater height", "b", HRSKey{Height: 11}, true},
+		{"lower height", "a", HRSKey{Height: 9, Round: 5, Step: stepPrecommit}, false},
+		{"same height other cluster", "b", HRSKey{Height: 10, Round: 2, Step: stepPropose}, false},
+		{"same height greater step", "a", HRSKey{Height: 10, Round: 1, Step: stepPrecommit}, true},
+		{"same HRS same cluster", "a", HRSKey{Height: 10, Round: 1, Step: stepPrevote}, true},
+		{"same height lower round", "a", HRSKey{Height: 10, Round: 0, Step: stepPrecommit}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.allowed, existing.claimAllowed(tc.clusterID, tc.hrs))
+		})
+	}
+}
+
+func TestWatermarkStoreConfig(t *testing.T) {
+	var cfg *WatermarkStoreConfig
+	require.NoError(t, cfg.Validate())
+
+	cfg = &WatermarkStoreConfig{Type: "consul"}
+	require.EqualError(t, cfg.Validate(), `unknown watermark store "consul", must be one of [etcd]`)
+
+	cfg = &WatermarkStoreConfig{Type: WatermarkStoreEtcd, ClusterID: "a"}
+	require.EqualError(t, cfg.Validate(), "etcd watermark store requires endpoints")
+
+	cfg = &WatermarkStoreConfig{Type: WatermarkStoreEtcd, Endpoints: []string{"127.0.0.1:2379"}}
+	require.EqualError(t, cfg.Validate(), "watermark store requires clusterID")
+
+	cfg.ClusterID = "a"
+	cfg.DialTimeout = "soon"
+	require.ErrorContains(t, cfg.Validate(), "invalid watermark store dialTimeout")
+
+	cfg.DialTimeout = "2s"
+	require.NoError(t, cfg.Validate())
+}
+
+func TestEtcdWatermarkStoreStandby(t *testing.T) {
+	s := &EtcdWatermarkStore{
+		logger:    cometlog.NewNopLogger(),
+		prefix:    defaultEtcdWatermarkPrefix,
+		clusterID: "standby",
+		failover:  true,
+	}
+
+	var standbyErr *WatermarkStandbyError
+	require.ErrorAs(t, s.Standby(), &standbyErr)
+	require.EqualError(t, s.Standby(), "cluster standby is on standby, no cluster was promoted")
+
+	s.observe(defaultEtcdWatermarkPrefix+"/.active", []byte("primary"))
+	require.EqualError(t, s.Standby(), "cluster standby is on standby, cluster primary is active")
+	require.Zero(t, testutil.ToFloat64(watermarkClusterActive))
+
+	// the standby follows the watermarks claimed by the active cluster.
+	s.observe(defaultEtcdWatermarkPrefix+"/cosmoshub-4", []byte(`{"height":100,"round":0,"step":3,"cluster_id":"primary"}`))
+	require.Equal(t, 100.0, testutil.ToFloat64(sharedWatermarkHeight.WithLabelValues("cosmoshub-4")))
+
+	s.observe(defaultEtcdWatermarkPrefix+"/.active", []byte("standby"))
+	require.NoError(t, s.Standby())
+	require.Equal(t, 1.0, testutil.ToFloat64(watermarkClusterActive))
+
+	// without failover, every cluster may claim the watermarks.
+	s = &EtcdWatermarkStore{logger: cometlog.NewNopLogger(), prefix: defaultEtcdWatermarkPrefix, clusterID: "a"}
+	require.NoError(t, s.Standby())
+}
@@ -0,0 +1,298 @@
+package signer
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// CosignerTransportWebSocket tunnels cosigner traffic over WebSocket so it can
+	// pass through HTTP(S) proxies and CDNs which only allow port 443.
+	CosignerTransportWebSocket = "websocket"
+
+	defaultWebSocketPath = "/horcrux"
+)
+
+// isWebSocketURL returns true for ws:// and wss:// addresses.
+func isWebSocketURL(address string) bool {
+	u, err := url.Parse(address)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "ws" || u.Scheme == "wss"
+}
+
+// dialWebSocket connects to a ws:// or wss:// URL and returns the tunnel as a net.Conn
+// carrying binary frames. HTTP(S)_PROXY from the environment is honored using CONNECT.
+func dialWebSocket(ctx context.Context, dialer *net.Dialer, rawURL string) (net.Conn, error) {
+	conn, _, err := dialWebSocketTLS(ctx, dialer, rawURL)
+	return conn, err
+}
+
+// dialWebSocketTLS is dialWebSocket that also returns the state of the TLS connection of a wss://
+// URL, nil for a ws:// URL.
+func dialWebSocketTLS(ctx context.Context, dialer *net.Dialer, rawURL string) (net.Conn, *tls.ConnectionState, error) {
+	location, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	origin := &url.URL{Scheme: "https", Host: location.Host}
+	if location.Scheme == "ws" {
+		origin.Scheme = "http"
+	}
+	config, err := websocket.NewConfig(location.String(), origin.String())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	host := location.Host
+	if location.Port() == "" {
+		port := "443"
+		if location.Scheme == "ws" {
+			port = "80"
+		}
+		host = net.JoinHostPort(location.Hostname(), port)
+	}
+
+	conn, err := dialThroughProxy(ctx, dialer, origin, host)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var state *tls.ConnectionState
+	if location.Scheme == "wss" {
+		tlsConn := tls.Client(conn, &tls.Config{
+			ServerName: location.Hostname(),
+			MinVersion: tls.VersionTLS12,
+		})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, nil, fmt.Errorf("websocket tls handshake: %w", err)
+		}
+		cs := tlsConn.ConnectionState()
+		state = &cs
+		conn = tlsConn
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	ws, err := websocket.NewClient(config, conn)
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("websocket handshake: %w", err)
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	ws.PayloadType = websocket.BinaryFrame
+	return ws, state, nil
+}
+
+// dialThroughProxy dials host directly, or through the HTTP proxy configured in the
+// environment for the origin using the CONNECT method.
+func dialThroughProxy(ctx context.Context, dialer *net.Dialer, origin *url.URL, host string) (net.Conn, error) {
+	proxyURL, err := http.ProxyFromEnvironment(&http.Request{URL: origin})
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy configuration: %w", err)
+	}
+	if proxyURL == nil {
+		return dialer.DialContext(ctx, "tcp", host)
+	}
+
+	proxyHost := proxyURL.Host
+	if proxyURL.Port() == "" {
+		proxyHost = net.JoinHostPort(proxyURL.Hostname(), "80")
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", proxyHost)
+	if err != nil {
+		return nil, fmt.Errorf("dial proxy %s: %w", proxyHost, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: host},
+		Host:   host,
+		Header: make(http.Header),
+	}
+	if u := proxyURL.User; u != nil {
+		password, _ := u.Password()
+		auth := base64.StdEncoding.EncodeToString([]byte(u.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+auth)
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("proxy connect: %w", err)
+	}
+	res, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("proxy connect: %w", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy connect to %s: %s", host, res.Status)
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+var _ CosignerTransport = &WebSocketCosignerTransport{}
+
+// WebSocketCosignerTransport carries cosigner gRPC and raft traffic over WebSocket.
+// Peers are dialed at ws(s)://<p2pAddr host:port><path>. The listener serves plain
+// WebSocket unless a certificate is configured, so TLS can also be terminated by a
+// reverse proxy or CDN in front of the cosigner.
+type WebSocketCosignerTransport struct {
+	path      string
+	secure    bool
+	tlsConfig *tls.Config
+}
+
+// NewWebSocketCosignerTransport returns a WebSocketCosignerTransport for the config.
+func NewWebSocketCosignerTransport(cfg *CosignerTransportConfig) (*WebSocketCosignerTransport, error) {
+	t := &WebSocketCosignerTransport{
+		path:   cfg.Path,
+		secure: cfg.TLS,
+	}
+	if t.path == "" {
+		t.path = defaultWebSocketPath
+	}
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		tlsConfig, err := newReloadingTLSConfig(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load websocket tls certificate: %w", err)
+		}
+		t.tlsConfig = tlsConfig
+	}
+	return t, nil
+}
+
+func (t *WebSocketCosignerTransport) Listen(address string) (net.Listener, error) {
+	sock, err := TCPCosignerTransport{}.Listen(address)
+	if err != nil {
+		return nil, err
+	}
+	if t.tlsConfig != nil {
+		sock = tls.NewListener(sock, t.tlsConfig)
+	}
+
+	ln := &webSocketListener{
+		addr:   sock.Addr(),
+		conns:  make(chan net.Conn),
+		closed: make(chan struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(t.path, websocket.Server{
+		// cosigners are not browsers, so there is no origin to check.
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler:   ln.handle,
+	})
+	ln.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		_ = ln.server.Serve(sock)
+	}()
+
+	return ln, nil
+}
+
+func (t *WebSocketCosignerTransport) DialContext(ctx context.Context, address string) (net.Conn, error) {
+	conn, _, err := t.dialContextTLS(ctx, address)
+	return conn, err
+}
+
+// dialContextTLS is DialContext that also returns the state of the TLS connection, nil without TLS.
+func (t *WebSocketCosignerTransport) dialContextTLS(
+	ctx context.Context,
+	address string,
+) (net.Conn, *tls.ConnectionState, error) {
+	scheme := "ws"
+	if t.secure {
+		scheme = "wss"
+	}
+	u := url.URL{Scheme: scheme, Host: address, Path: t.path}
+	return dialWebSocketTLS(ctx, &net.Dialer{}, u.String())
+}
+
+// webSocketListener adapts accepted WebSocket connections to a net.Listener.
+type webSocketListener struct {
+	addr   net.Addr
+	server *http.Server
+	conns  chan net.Conn
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// handle hands the connection to Accept and holds the websocket handler open until the
+// connection is closed, since the websocket server closes it when the handler returns.
+func (l *webSocketListener) handle(ws *websocket.Conn) {
+	ws.PayloadType = websocket.BinaryFrame
+	conn := &webSocketServerConn{Conn: ws, done: make(chan struct{})}
+	select {
+	case l.conns <- conn:
+	case <-l.closed:
+		return
+	}
+	select {
+	case <-conn.done:
+	case <-l.closed:
+	}
+}
+
+func (l *webSocketListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *webSocketListener) Close() error {
+	var err error
+	l.closeOnce.Do(func() {
+		close(l.closed)
+		err = l.server.Close()
+	})
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+func (l *webSocketListener) Addr() net.Addr {
+	return l.addr
+}
+
+type webSocketServerConn struct {
+	*websocket.Conn
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func (c *webSocketServerConn) Close() error {
+	c.closeOnce.Do(func() { close(c.done) })
+	return c.Conn.Close()
+}